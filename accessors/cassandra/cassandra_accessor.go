@@ -60,6 +60,11 @@ func NewCassandraAccessor(sourceProfile profiles.SourceProfile) (*CassandraAcces
 	return accessor, keyspaceMD, nil
 }
 
+// GetClient returns the cluster client used to query the keyspace.
+func (acc *CassandraAccessor) GetClient() cc.CassandraClusterInterface {
+	return acc.client
+}
+
 func (acc *CassandraAccessor) Close() {
 	if acc.client != nil {
 		acc.client.Close()
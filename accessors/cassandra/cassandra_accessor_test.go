@@ -62,6 +62,7 @@ func TestNewCassandraAccessor(t *testing.T) {
 		assert.NotNil(t, accessor)
 		assert.Equal(t, mockMetadata, keyspaceMD)
 		assert.Equal(t, mockClient, accessor.client)
+		assert.Equal(t, mockClient, accessor.GetClient())
 		assert.Equal(t, mockMetadata, accessor.keyspaceMetadata)
 		mockClient.AssertExpectations(t)
 	})
@@ -20,15 +20,30 @@ import (
 
 type GocqlSessionInterface interface {
 	KeyspaceMetadata(keyspace string) (*gocql.KeyspaceMetadata, error)
+	Query(stmt string, values ...interface{}) QueryInterface
 	Close()
 }
 
+// QueryInterface is the subset of gocql.Query used to read data from Cassandra.
+type QueryInterface interface {
+	Scan(dest ...interface{}) error
+	Iter() IterInterface
+}
+
+// IterInterface is the subset of gocql.Iter used to page through query results.
+type IterInterface interface {
+	Scan(dest ...interface{}) bool
+	MapScan(m map[string]interface{}) bool
+	Close() error
+}
+
 type KeyspaceMetadataInterface interface {
 	Tables() map[string]*gocql.TableMetadata
 }
 
 type CassandraClusterInterface interface {
 	KeyspaceMetadata(keyspace string) (KeyspaceMetadataInterface, error)
+	Query(stmt string, values ...interface{}) QueryInterface
 	Close() 
 }
 
@@ -51,12 +66,44 @@ func (gs *GocqlSessionImpl) KeyspaceMetadata(keyspace string) (*gocql.KeyspaceMe
 	return ks, nil
 }
 
+func (gs *GocqlSessionImpl) Query(stmt string, values ...interface{}) QueryInterface {
+	return &GocqlQueryImpl{query: gs.session.Query(stmt, values...)}
+}
+
 func (gs *GocqlSessionImpl) Close() {
 	if gs.session != nil {
 		gs.session.Close()
 	}
 }
 
+type GocqlQueryImpl struct {
+	query *gocql.Query
+}
+
+func (q *GocqlQueryImpl) Scan(dest ...interface{}) error {
+	return q.query.Scan(dest...)
+}
+
+func (q *GocqlQueryImpl) Iter() IterInterface {
+	return &GocqlIterImpl{iter: q.query.Iter()}
+}
+
+type GocqlIterImpl struct {
+	iter *gocql.Iter
+}
+
+func (it *GocqlIterImpl) Scan(dest ...interface{}) bool {
+	return it.iter.Scan(dest...)
+}
+
+func (it *GocqlIterImpl) MapScan(m map[string]interface{}) bool {
+	return it.iter.MapScan(m)
+}
+
+func (it *GocqlIterImpl) Close() error {
+	return it.iter.Close()
+}
+
 type CassandraKeyspaceMetadataImpl struct {
 	keyspaceMetadata *gocql.KeyspaceMetadata
 }
@@ -80,6 +127,10 @@ func (c *CassandraClusterImpl) KeyspaceMetadata(keyspace string) (KeyspaceMetada
 	return &CassandraKeyspaceMetadataImpl{keyspaceMetadata: ks}, nil
 }
 
+func (c *CassandraClusterImpl) Query(stmt string, values ...interface{}) QueryInterface {
+	return c.session.Query(stmt, values...)
+}
+
 func (c *CassandraClusterImpl) Close() {
 	c.session.Close()
 }
@@ -68,6 +68,16 @@ func TestCassandraClusterImpl(t *testing.T) {
 		mockSession.AssertExpectations(t)
 	})
 
+	t.Run("Query", func(t *testing.T) {
+		mockSession := new(MockGocqlSession)
+		mockQuery := new(MockQuery)
+		mockSession.On("Query", "SELECT tokens FROM system.local", []interface{}(nil)).Return(mockQuery).Once()
+		clusterImpl := &CassandraClusterImpl{session: mockSession}
+		q := clusterImpl.Query("SELECT tokens FROM system.local")
+		assert.Equal(t, mockQuery, q)
+		mockSession.AssertExpectations(t)
+	})
+
 	t.Run("Close", func(t *testing.T) {
 		mockSession := new(MockGocqlSession)
 		mockSession.On("Close").Return().Once()
@@ -30,10 +30,63 @@ func (m *MockGocqlSession) KeyspaceMetadata(keyspace string) (*gocql.KeyspaceMet
 	return nil, args.Error(1)
 }
 
+func (m *MockGocqlSession) Query(stmt string, values ...interface{}) QueryInterface {
+	args := m.Called(stmt, values)
+	if q, ok := args.Get(0).(QueryInterface); ok {
+		return q
+	}
+	return nil
+}
+
 func (m *MockGocqlSession) Close() {
 	m.Called()
 }
 
+type MockQuery struct {
+	mock.Mock
+}
+
+func (m *MockQuery) Scan(dest ...interface{}) error {
+	args := m.Called(dest)
+	return args.Error(0)
+}
+
+func (m *MockQuery) Iter() IterInterface {
+	args := m.Called()
+	if it, ok := args.Get(0).(IterInterface); ok {
+		return it
+	}
+	return nil
+}
+
+// MockIter replays Rows through MapScan. Scan is mocked as usual.
+type MockIter struct {
+	mock.Mock
+	Rows []map[string]interface{}
+	pos  int
+}
+
+func (m *MockIter) Scan(dest ...interface{}) bool {
+	args := m.Called(dest)
+	return args.Bool(0)
+}
+
+func (m *MockIter) MapScan(row map[string]interface{}) bool {
+	if m.pos >= len(m.Rows) {
+		return false
+	}
+	for k, v := range m.Rows[m.pos] {
+		row[k] = v
+	}
+	m.pos++
+	return true
+}
+
+func (m *MockIter) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
 type MockKeyspaceMetadata struct {
 	mock.Mock
 	MockTables map[string]*gocql.TableMetadata
@@ -59,6 +112,14 @@ func (m *MockCassandraCluster) KeyspaceMetadata(keyspace string) (KeyspaceMetada
 	return nil, args.Error(1)
 }
 
+func (m *MockCassandraCluster) Query(stmt string, values ...interface{}) QueryInterface {
+	args := m.Called(stmt, values)
+	if q, ok := args.Get(0).(QueryInterface); ok {
+		return q
+	}
+	return nil
+}
+
 func (m *MockCassandraCluster) Close() {
 	m.Called()
 }
@@ -140,6 +140,7 @@ func performQueryAssessment(ctx context.Context, collectors assessmentCollectors
 			ddl.Config{Comments: true, ProtectIds: false, Tables: true, ForeignKeys: true, SpDialect: conv.SpDialect, Source: "mysql"},
 			conv.SpSchema,
 			conv.SpSequences,
+			conv.SpViews,
 			conv.DatabaseOptions),
 		"\n")
 
@@ -218,6 +219,7 @@ func initializeCollectors(conv *internal.Conv, sourceProfile profiles.SourceProf
 				ddl.Config{Comments: true, ProtectIds: false, Tables: true, ForeignKeys: true, SpDialect: conv.SpDialect, Source: "mysql"},
 				conv.SpSchema,
 				conv.SpSequences,
+				conv.SpViews,
 				conv.DatabaseOptions),
 			"\n")
 
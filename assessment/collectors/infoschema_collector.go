@@ -273,13 +273,31 @@ func (c InfoSchemaCollector) ListIndexes() (map[string]utils.SrcIndexDetails, ma
 				TableId:   c.indexes[i].TableId,
 				IsUnique:  c.indexes[i].IndexDef.Unique,
 				TableName: c.conv.SpSchema[c.indexes[i].TableId].Name,
-				Ddl:       getSpannerIndex(c.indexes[i].IndexDef.Id, c.conv.SpSchema[c.indexes[i].TableId]).PrintCreateIndex(c.conv.SpSchema[c.indexes[i].TableId], ddl.Config{}),
+				Ddl:       getSpannerIndexDdl(c.indexes[i].IndexDef.Id, c.conv.SpSchema, c.indexes[i].TableId),
 			}
 		}
 	}
 	return srcIndexes, spIndexes
 }
 
+// getSpannerIndexDdl returns the DDL of the Spanner index converted from the
+// given source index. Full-text and vector source indexes are converted to
+// search indexes and vector indexes.
+func getSpannerIndexDdl(indexId string, spSchema ddl.Schema, tableId string) string {
+	spTable := spSchema[tableId]
+	for id := range spTable.SearchIndexes {
+		if spTable.SearchIndexes[id].Id == indexId {
+			return spTable.SearchIndexes[id].PrintCreateSearchIndex(spTable, ddl.Config{})
+		}
+	}
+	for id := range spTable.VectorIndexes {
+		if spTable.VectorIndexes[id].Id == indexId {
+			return spTable.VectorIndexes[id].PrintCreateVectorIndex(spTable, ddl.Config{})
+		}
+	}
+	return getSpannerIndex(indexId, spTable).PrintCreateIndex(spSchema, spTable, ddl.Config{})
+}
+
 func getSpannerIndex(indexId string, spSchema ddl.CreateTable) ddl.CreateIndex {
 	for id := range spSchema.Indexes {
 		if spSchema.Indexes[id].Id == indexId {
@@ -328,12 +346,23 @@ func (c InfoSchemaCollector) ListViews() map[string]utils.ViewAssessment {
 			SrcName:       view.Name,
 			SrcDefinition: view.Definition,
 			SrcViewType:   "NON-MATERIALIZED", // Views are always non-materialized in MySQL
-			SpName:        internal.GetSpannerValidName(c.conv, view.Name),
+			SpName:        c.spViewName(view.Name),
 		}
 	}
 	return viewAssessmentOutput
 }
 
+// spViewName returns the Spanner name of a view, which is already set if
+// the view was converted along with the schema.
+func (c InfoSchemaCollector) spViewName(srcName string) string {
+	for id, v := range c.conv.SrcViews {
+		if spView, ok := c.conv.SpViews[id]; ok && v.Name == srcName {
+			return spView.Name
+		}
+	}
+	return internal.GetSpannerValidName(c.conv, srcName)
+}
+
 func (c InfoSchemaCollector) ListColumnDefinitions() (map[string]utils.SrcColumnDetails, map[string]utils.SpColumnDetails) {
 	srcColumnDetails := make(map[string]utils.SrcColumnDetails)
 	spColumnDetails := make(map[string]utils.SpColumnDetails)
@@ -781,6 +781,31 @@ func TestGetSpannerIndex(t *testing.T) {
 	assert.Equal(t, ddl.CreateIndex{}, emptyIndex)
 }
 
+func TestGetSpannerIndexDdl(t *testing.T) {
+	spTable := ddl.CreateTable{
+		Name:   "t1",
+		ColIds: []string{"c1", "c2", "c3"},
+		ColDefs: map[string]ddl.ColumnDef{
+			"c1": {Name: "title", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+			"c2": {Name: "title_Tokens", T: ddl.Type{Name: ddl.TokenList}, Hidden: true},
+			"c3": {Name: "embedding", T: ddl.Type{Name: ddl.Float32, IsArray: true, VectorLength: 3}, NotNull: true},
+		},
+		Indexes: []ddl.CreateIndex{
+			{Id: "index1", Name: "index_a", TableId: "t1", Keys: []ddl.IndexKey{{ColId: "c1"}}},
+		},
+		SearchIndexes: []ddl.CreateSearchIndex{
+			{Id: "index2", Name: "index_b", TableId: "t1", Keys: []ddl.IndexKey{{ColId: "c2"}}},
+		},
+		VectorIndexes: []ddl.CreateVectorIndex{
+			{Id: "index3", Name: "index_c", TableId: "t1", Keys: []ddl.IndexKey{{ColId: "c3"}}, DistanceType: ddl.DistanceCosine},
+		},
+	}
+	spSchema := ddl.Schema{"t1": spTable}
+	assert.Equal(t, "CREATE INDEX index_a ON t1 (title)", getSpannerIndexDdl("index1", spSchema, "t1"))
+	assert.Equal(t, "CREATE SEARCH INDEX index_b ON t1 (title_Tokens)", getSpannerIndexDdl("index2", spSchema, "t1"))
+	assert.Equal(t, "CREATE VECTOR INDEX index_c ON t1 (embedding) OPTIONS (distance_type = 'COSINE')", getSpannerIndexDdl("index3", spSchema, "t1"))
+}
+
 func TestInfoSchemaCollector_ListTables(t *testing.T) {
 	tests := []struct {
 		name       string
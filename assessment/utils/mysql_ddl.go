@@ -109,6 +109,9 @@ func PrintCreateIndex(index schema.Index, ct schema.Table) string {
 	if index.Unique {
 		createIndex.WriteString("UNIQUE ")
 	}
+	if index.FullText {
+		createIndex.WriteString("FULLTEXT ")
+	}
 	createIndex.WriteString("INDEX ")
 	createIndex.WriteString(index.Name)
 	createIndex.WriteString(" ON ")
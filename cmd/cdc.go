@@ -0,0 +1,196 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path"
+
+	sp "cloud.google.com/go/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/conversion"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/postgres"
+	"github.com/google/subcommands"
+	"go.uber.org/zap"
+)
+
+// CdcCmd struct with flags.
+type CdcCmd struct {
+	sessionJSON      string
+	sourceProfile    string
+	targetProfile    string
+	plugin           string
+	slot             string
+	publication      string
+	input            string
+	checkpoint       string
+	DeadLetterDir    string
+	DeadLetterFormat string
+	dryRun           bool
+	logLevel         string
+}
+
+// Name returns the name of operation.
+func (cmd *CdcCmd) Name() string {
+	return "cdc"
+}
+
+// Synopsis returns summary of operation.
+func (cmd *CdcCmd) Synopsis() string {
+	return "replicate PostgreSQL changes to Spanner from logical decoding output"
+}
+
+// Usage returns usage info of the command.
+func (cmd *CdcCmd) Usage() string {
+	return fmt.Sprintf(`%v cdc -session=[session_file] -source-profile="host=...,dbName=..." -slot=[slot] -publication=[publication] -target-profile="instance=my-instance,dbName=my-db"...
+
+Replicate the changes made to a PostgreSQL database after a bulk migration,
+such as one from pg_dump output, so that the application can be cut over to
+Spanner. Changes are read from a logical replication slot, or from recorded
+logical decoding output with -input, converted as by the bulk migration
+described by the session file, and applied in commit order. The LSN of the
+last transaction applied is saved in the -checkpoint file; the command
+restarts after it, and only consumes changes from the slot once they are
+applied. With a slot, it runs until interrupted. The cdc flags are:
+`, path.Base(os.Args[0]))
+}
+
+// SetFlags sets the flags.
+func (cmd *CdcCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&cmd.sessionJSON, "session", "", "Specifies the file we restore session state from")
+	f.StringVar(&cmd.sourceProfile, "source-profile", "", "Flag for specifying connection profile for the PostgreSQL database e.g., \"host=localhost,port=5432,user=repl,dbName=db\"")
+	f.StringVar(&cmd.targetProfile, "target-profile", "", "Flag for specifying connection profile for target database e.g., \"instance=my-instance,dbName=my-db\"")
+	f.StringVar(&cmd.plugin, "plugin", postgres.PluginPgoutput, "Logical decoding plugin of the slot or of the recorded output (accepted values: `pgoutput`, `wal2json`)")
+	f.StringVar(&cmd.slot, "slot", "", "Logical replication slot to read changes from")
+	f.StringVar(&cmd.publication, "publication", "", "Publication of the tables to replicate, with pgoutput")
+	f.StringVar(&cmd.input, "input", "", "File of recorded logical decoding output to read changes from instead of a slot")
+	f.StringVar(&cmd.checkpoint, "checkpoint", "", "File to save the LSN of the last transaction applied in; defaults to <dbName>.lsn.json")
+	f.StringVar(&cmd.DeadLetterDir, "dead-letter-dir", "", "Directory to save rows that fail conversion in, one file per table")
+	f.StringVar(&cmd.DeadLetterFormat, "dead-letter-format", internal.DeadLetterFormatJSONL, "Format of the files in --dead-letter-dir (accepted values: `jsonl`, `csv`)")
+	f.BoolVar(&cmd.dryRun, "dry-run", false, "Flag for converting the changes without writing them to Spanner")
+	f.StringVar(&cmd.logLevel, "log-level", "DEBUG", "Configure the logging level for the command (INFO, DEBUG), defaults to DEBUG")
+}
+
+func (cmd *CdcCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	var err error
+	defer func() {
+		if err != nil {
+			logger.Log.Fatal("FATAL error", zap.Error(err))
+		}
+	}()
+	err = logger.InitializeLogger(cmd.logLevel)
+	if err != nil {
+		logger.Log.Info(fmt.Sprint("Error initialising logger, did you specify a valid log-level? [DEBUG, INFO, WARN, ERROR, FATAL]", err))
+		return subcommands.ExitFailure
+	}
+	defer logger.Log.Sync()
+
+	if cmd.sessionJSON == "" {
+		err = fmt.Errorf("please specify the session file with --session")
+		return subcommands.ExitUsageError
+	}
+	if (cmd.input == "") == (cmd.slot == "") {
+		err = fmt.Errorf("please specify either a replication slot with --slot or recorded changes with --input")
+		return subcommands.ExitUsageError
+	}
+	targetProfile, err := profiles.NewTargetProfile(cmd.targetProfile, cmd.dryRun)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
+	if targetProfile.Conn.Sp.Dbname == "" {
+		err = fmt.Errorf("please specify the database to write to with dbName in --target-profile")
+		return subcommands.ExitUsageError
+	}
+	conv := internal.MakeConv()
+	err = conversion.ReadSessionFile(conv, cmd.sessionJSON)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
+	conv.Audit.SkipMetricsPopulation = os.Getenv("SKIP_METRICS_POPULATION") == "true"
+	if conv.Source != constants.POSTGRES && conv.Source != constants.PGDUMP {
+		err = fmt.Errorf("the session file is for a %s migration, but cdc only supports PostgreSQL", conv.Source)
+		return subcommands.ExitUsageError
+	}
+	conv.SetDataMode()
+
+	src := postgres.LogicalSource{Plugin: cmd.plugin, Slot: cmd.slot, Publication: cmd.publication, File: cmd.input}
+	if cmd.slot != "" {
+		n := profiles.NewSourceProfileImpl{}
+		var sourceProfile profiles.SourceProfile
+		sourceProfile, err = profiles.NewSourceProfile(cmd.sourceProfile, constants.POSTGRES, &n)
+		if err != nil {
+			return subcommands.ExitUsageError
+		}
+		sourceProfile.Driver = constants.POSTGRES
+		gi := conversion.GetInfoImpl{}
+		is, e := gi.GetInfoSchema("", sourceProfile, targetProfile)
+		if e != nil {
+			err = fmt.Errorf("can't connect to the source database: %v", e)
+			return subcommands.ExitFailure
+		}
+		src.Db = is.(postgres.InfoSchemaImpl).Db
+		defer src.Db.Close()
+	}
+	closeDeadLetters, err := openDeadLetters(conv, cmd.DeadLetterDir, cmd.DeadLetterFormat, os.Stdout)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
+	defer closeDeadLetters()
+	if cmd.checkpoint == "" {
+		cmd.checkpoint = targetProfile.Conn.Sp.Dbname + ".lsn.json"
+	}
+	store := &internal.FileCheckpointStore{Path: cmd.checkpoint}
+
+	ioHelper := utils.IOStreams{In: os.Stdin, Out: os.Stdout}
+	var (
+		client *sp.Client
+		dbURI  = targetProfile.Conn.Sp.Dbname
+	)
+	if cmd.dryRun {
+		conv.Audit.DryRun = true
+	} else {
+		adminClient, c, uri, e := CreateDatabaseClient(ctx, targetProfile, conv.Source, targetProfile.Conn.Sp.Dbname, ioHelper)
+		if e != nil {
+			err = fmt.Errorf("can't create database client: %v", e)
+			return subcommands.ExitFailure
+		}
+		defer adminClient.Close()
+		defer c.Close()
+		client, dbURI = c, uri
+	}
+
+	// Replication from a slot runs until interrupted.
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	err = postgres.ReplicateLogical(ctx, conv, src, store, client)
+	if err != nil && ctx.Err() == nil {
+		return subcommands.ExitFailure
+	}
+	err = nil
+	lsn, e := postgres.LoadLSN(store)
+	if e != nil {
+		logger.Log.Warn(e.Error())
+	}
+	fmt.Fprintf(ioHelper.Out, "Replicated %d row changes to %s: %d bad rows. Last transaction applied: %s.\n", conv.Rows(), dbURI, conv.BadRows(), lsn)
+	return subcommands.ExitSuccess
+}
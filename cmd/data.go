@@ -48,6 +48,17 @@ type DataCmd struct {
 	filePrefix       string // TODO: move filePrefix to global flags
 	project          string
 	WriteLimit       int64
+	ParallelTables   int
+	ChunksPerTable   int
+	Checkpoint       string
+	Resume           bool
+	DeadLetterDir    string
+	DeadLetterFormat string
+	SamplePercent    float64
+	SampleRows       int64
+	TableSelection   string
+	Tables           string
+	ExcludeTables    string
 	dryRun           bool
 	logLevel         string
 	SkipForeignKeys  bool
@@ -86,6 +97,17 @@ func (cmd *DataCmd) SetFlags(f *flag.FlagSet) {
 	f.StringVar(&cmd.filePrefix, "prefix", "", "File prefix for generated files")
 	f.StringVar(&cmd.project, "project", "", "Flag spcifying default project id for all the generated resources for the migration")
 	f.Int64Var(&cmd.WriteLimit, "write-limit", DefaultWritersLimit, "Write limit for writes to spanner")
+	f.IntVar(&cmd.ParallelTables, "parallel-tables", 1, "Number of tables to read from the source database concurrently (MySQL, PostgreSQL, SQL Server and Oracle only)")
+	f.IntVar(&cmd.ChunksPerTable, "chunks-per-table", 1, "Number of primary key ranges to read each table in concurrently, for tables with an integer leading primary key column (MySQL, PostgreSQL, SQL Server and Oracle only)")
+	f.StringVar(&cmd.Checkpoint, "checkpoint", "", "Saves data migration progress so that it can be resumed with --resume: a local file path, or `metadata-db` to save it in the internal metadata database (defaults to <prefix>.checkpoint.json when --resume is set)")
+	f.BoolVar(&cmd.Resume, "resume", false, "Resumes an interrupted data migration from the progress saved with --checkpoint, skipping data already written")
+	f.StringVar(&cmd.DeadLetterDir, "dead-letter-dir", "", "Directory to save rows that fail conversion or writing to Spanner in, one file per table. The rows can be migrated again with the replay command")
+	f.StringVar(&cmd.DeadLetterFormat, "dead-letter-format", internal.DeadLetterFormatJSONL, "Format of the files in --dead-letter-dir (accepted values: `jsonl`, `csv`)")
+	f.StringVar(&cmd.TableSelection, "table-selection", "", "JSON file selecting the tables to migrate, with `include` and `exclude` lists of table name globs, and the rows to migrate, with a `where` map from table name to a condition in the source database's SQL")
+	f.StringVar(&cmd.Tables, "tables", "", "Comma-separated globs of the names of the source tables to migrate (defaults to all tables)")
+	f.StringVar(&cmd.ExcludeTables, "exclude-tables", "", "Comma-separated globs of the names of source tables not to migrate")
+	f.Float64Var(&cmd.SamplePercent, "sample-percent", 0, "Migrates only about this percentage of the rows of tables that don't reference other tables, and the rows of other tables that reference them, keeping foreign keys and interleaving intact")
+	f.Int64Var(&cmd.SampleRows, "sample-rows", 0, "Migrates at most this many rows per table, keeping foreign keys and interleaving intact")
 	f.BoolVar(&cmd.dryRun, "dry-run", false, "Flag for generating DDL and schema conversion report without creating a spanner database")
 	f.StringVar(&cmd.logLevel, "log-level", "DEBUG", "Configure the logging level for the command (INFO, DEBUG), defaults to DEBUG")
 	f.BoolVar(&cmd.SkipForeignKeys, "skip-foreign-keys", false, "Skip creating foreign keys after data migration is complete (ddl statements for foreign keys can still be found in the downloaded schema.ddl.txt file and the same can be applied separately)")
@@ -117,6 +139,10 @@ func (cmd *DataCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface
 		err = fmt.Errorf("error while preparing prerequisites for migration: %v", err)
 		return subcommands.ExitUsageError
 	}
+	if err = conversion.CheckDataSource(sourceProfile); err != nil {
+		logger.Log.Error("Can't migrate data", zap.Error(err))
+		return subcommands.ExitUsageError
+	}
 	if cmd.project == "" {
 		getInfo := &utils.GetUtilInfoImpl{}
 		cmd.project, err = getInfo.GetProject()
@@ -154,6 +180,21 @@ func (cmd *DataCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface
 			return subcommands.ExitUsageError
 		}
 	}
+	conv.BulkRead = internal.BulkReadOptions{ParallelTables: cmd.ParallelTables, ChunksPerTable: cmd.ChunksPerTable}
+	conv.TableSelection, err = loadTableSelection(cmd.TableSelection, cmd.Tables, cmd.ExcludeTables, sourceProfile.Driver)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
+	closeDeadLetters, err := openDeadLetters(conv, cmd.DeadLetterDir, cmd.DeadLetterFormat, ioHelper.Out)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
+	defer closeDeadLetters()
+	closeSampler, err := openSampler(conv, cmd.SamplePercent, cmd.SampleRows, cmd.Resume, ioHelper.Out)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
+	defer closeSampler()
 
 	var (
 		dbURI string
@@ -220,7 +261,7 @@ func validateExistingDb(ctx context.Context, spDialect, dbURI string, adminClien
 	}
 	spannerConv := internal.MakeConv()
 	spannerConv.SpDialect = spDialect
-	err = utils.ReadSpannerSchema(ctx, spannerConv, client)
+	err = conversion.ReadSpannerSchema(ctx, spannerConv, client)
 	if err != nil {
 		err = fmt.Errorf("can't read spanner schema: %v", err)
 		return err
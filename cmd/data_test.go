@@ -37,6 +37,9 @@ func TestDataSetFlags(t *testing.T) {
                                 targetProfile:    "",
                                 filePrefix:       "",
                                 WriteLimit:       DefaultWritersLimit,
+                                ParallelTables:   1,
+                                ChunksPerTable:   1,
+                                DeadLetterFormat: "jsonl",
                                 dryRun:           false,
                                 logLevel:         "DEBUG",
                                 SkipForeignKeys:  false,
@@ -53,6 +56,9 @@ func TestDataSetFlags(t *testing.T) {
                                 targetProfile:    "",
                                 filePrefix:       "",
                                 WriteLimit:       DefaultWritersLimit,
+                                ParallelTables:   1,
+                                ChunksPerTable:   1,
+                                DeadLetterFormat: "jsonl",
                                 dryRun:           false,
                                 logLevel:         "DEBUG",
                                 SkipForeignKeys:  false,
@@ -69,6 +75,9 @@ func TestDataSetFlags(t *testing.T) {
                                 targetProfile:    "target.json",
                                 filePrefix:       "",
                                 WriteLimit:       DefaultWritersLimit,
+                                ParallelTables:   1,
+                                ChunksPerTable:   1,
+                                DeadLetterFormat: "jsonl",
                                 dryRun:           false,
                                 logLevel:         "DEBUG",
                                 SkipForeignKeys:  false,
@@ -76,8 +85,8 @@ func TestDataSetFlags(t *testing.T) {
                         },
                 },
                 {
-                        testName: "File Prefix and Write Limit",
-                        flagArgs: []string{"--prefix=test", "--write-limit=100"},
+                        testName: "File Prefix, Write Limit and Parallel Reads",
+                        flagArgs: []string{"--prefix=test", "--write-limit=100", "--parallel-tables=4", "--chunks-per-table=8"},
                         expectedValues: DataCmd{
                                 source:           "",
                                 sourceProfile:    "",
@@ -85,6 +94,9 @@ func TestDataSetFlags(t *testing.T) {
                                 targetProfile:    "",
                                 filePrefix:       "test",
                                 WriteLimit:       100,
+                                ParallelTables:   4,
+                                ChunksPerTable:   8,
+                                DeadLetterFormat: "jsonl",
                                 dryRun:           false,
                                 logLevel:         "DEBUG",
                                 SkipForeignKeys:  false,
@@ -101,6 +113,9 @@ func TestDataSetFlags(t *testing.T) {
                                 targetProfile:    "",
                                 filePrefix:       "",
                                 WriteLimit:       DefaultWritersLimit,
+                                ParallelTables:   1,
+                                ChunksPerTable:   1,
+                                DeadLetterFormat: "jsonl",
                                 dryRun:           true,
                                 logLevel:         "INFO",
                                 SkipForeignKeys:  false,
@@ -117,6 +132,9 @@ func TestDataSetFlags(t *testing.T) {
                                 targetProfile:    "",
                                 filePrefix:       "",
                                 WriteLimit:       DefaultWritersLimit,
+                                ParallelTables:   1,
+                                ChunksPerTable:   1,
+                                DeadLetterFormat: "jsonl",
                                 dryRun:           false,
                                 logLevel:         "DEBUG",
                                 SkipForeignKeys:  true,
@@ -144,6 +162,9 @@ func TestDataSetFlags(t *testing.T) {
                                 targetProfile:    "spanner.json",
                                 filePrefix:       "output",
                                 WriteLimit:       50,
+                                ParallelTables:   1,
+                                ChunksPerTable:   1,
+                                DeadLetterFormat: "jsonl",
                                 dryRun:           true,
                                 logLevel:         "WARN",
                                 SkipForeignKeys:  true,
@@ -16,8 +16,11 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
 	"net/url"
 	"os"
 	"path"
@@ -31,6 +34,7 @@ import (
 	spanneraccessor "github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/spanner"
 
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/utils"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/import_file"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
@@ -49,6 +53,9 @@ type ImportDataCmd struct {
 	schemaUri         string
 	csvLineDelimiter  string
 	csvFieldDelimiter string
+	csvSampleRows     int
+	manifest          string
+	fileConcurrency   int
 	project           string
 	databaseDialect   string
 	logLevel          string
@@ -58,11 +65,14 @@ func (cmd *ImportDataCmd) SetFlags(set *flag.FlagSet) {
 	set.StringVar(&cmd.instance, "instance", "", "Spanner instance Id")
 	set.StringVar(&cmd.database, "database", "", "Spanner database name. If one with the specified name does not exist, a new one will be created with the same")
 	set.StringVar(&cmd.tableName, "table-name", "", "Spanner table name. Optional. If not specified, source-uri name will be used")
-	set.StringVar(&cmd.sourceUri, "source-uri", "", "URI of the file to import")
-	set.StringVar(&cmd.sourceFormat, "source-format", "", fmt.Sprintf("Format of the file to import. Valid values {%s, %s, %s}", constants.MYSQLDUMP, constants.PGDUMP, constants.CSV))
-	set.StringVar(&cmd.schemaUri, "schema-uri", "", "URI of the file with schema for the csv to import. Only non-optional for csv format.")
+	set.StringVar(&cmd.sourceUri, "source-uri", "", "URI of the file to import: a local path, or a gs://, s3://, http:// or https:// URI. Files compressed with gzip, zstd or bzip2 are decompressed as they are read. A glob, e.g. gs://bucket/export/part-*.csv, or a directory or prefix ending with '/' imports all the files it matches into --table-name. Globs and prefixes are supported for local paths, gs:// and s3:// URIs")
+	set.StringVar(&cmd.sourceFormat, "source-format", "", fmt.Sprintf("Format of the file to import. Valid values {%s, %s, %s, %s, %s, %s}", constants.MYSQLDUMP, constants.PGDUMP, constants.CSV, constants.PARQUET, constants.AVRO, constants.JSONL))
+	set.StringVar(&cmd.schemaUri, "schema-uri", "", "URI of the file with schema for the csv to import. Optional. If not specified, the schema of a csv file is inferred from its rows, and the table of a parquet, avro or jsonl file is created from the schema of the file.")
 	set.StringVar(&cmd.csvLineDelimiter, "csv-line-delimiter", "\n", "Token to be used as line delimiter for csv format. Optional. Defaults to '\\n'. Only used for csv format.")
 	set.StringVar(&cmd.csvFieldDelimiter, "csv-field-delimiter", ",", "Token to be used as field delimiter for csv format. Optional. Defaults to ','. Only used for csv format.")
+	set.IntVar(&cmd.csvSampleRows, "csv-sample-rows", import_file.DefaultCsvSampleRows, fmt.Sprintf("Number of rows read to infer the schema of a csv file when --schema-uri is not specified. Optional. Defaults to %d. Only used for csv format.", import_file.DefaultCsvSampleRows))
+	set.StringVar(&cmd.manifest, "manifest", "", "URI of a json manifest of the files to import, instead of --source-uri. The manifest is a list of tables, each with a table_name, the file_patterns of its files, and optionally the schema_uri of its schema file. Only used for csv, parquet, avro and jsonl formats.")
+	set.IntVar(&cmd.fileConcurrency, "file-concurrency", import_file.DefaultFileConcurrency, fmt.Sprintf("Number of files imported at once when --source-uri is a glob or prefix, or a manifest is passed. Optional. Defaults to %d.", import_file.DefaultFileConcurrency))
 	set.StringVar(&cmd.project, "project", "", "Project id for all resources related to this import. Optional")
 	set.StringVar(&cmd.databaseDialect, "database-dialect", constants.DIALECT_GOOGLESQL, fmt.Sprintf("Spanner database dialect. Defaults to %s. Valid values {%s, %s}", constants.DIALECT_GOOGLESQL, constants.DIALECT_GOOGLESQL, constants.DIALECT_POSTGRESQL))
 	set.StringVar(&cmd.logLevel, "log-level", "INFO", "Configure the logging level for the command (INFO, DEBUG), defaults to DEBUG")
@@ -97,6 +107,15 @@ func (cmd *ImportDataCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...
 		return subcommands.ExitFailure
 	}
 
+	if cmd.isMultiFileImport() {
+		err := cmd.handleFiles(ctx, dbURI, dialect, spannerAccessor)
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("Unable to import files %v", err))
+			return subcommands.ExitFailure
+		}
+		return subcommands.ExitSuccess
+	}
+
 	sourceReader, schemaReader, err := validateUriRemote(ctx, cmd)
 	if err != nil {
 		logger.Log.Error(fmt.Sprintf("Input validation failed. Reason %v", err))
@@ -107,14 +126,27 @@ func (cmd *ImportDataCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...
 
 	switch cmd.sourceFormat {
 	case constants.CSV:
-		// schemaReader will only be valid if sourceFormat is CSV
-		defer schemaReader.Close()
+		// schemaReader is only valid if a schema URI was passed.
+		if schemaReader != nil {
+			defer schemaReader.Close()
+		}
 		err := cmd.handleCsv(ctx, dbURI, dialect, spannerAccessor, sourceReader, schemaReader)
 		if err != nil {
 			logger.Log.Error(fmt.Sprintf("Unable to handle Csv %v", err))
 			return subcommands.ExitFailure
 		}
 		return subcommands.ExitSuccess
+	case constants.PARQUET, constants.AVRO, constants.JSONL:
+		// schemaReader is only valid if a schema URI was passed.
+		if schemaReader != nil {
+			defer schemaReader.Close()
+		}
+		err := cmd.handleRecordFile(ctx, dbURI, dialect, spannerAccessor, sourceReader, schemaReader)
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("Unable to handle %s file %v", cmd.sourceFormat, err))
+			return subcommands.ExitFailure
+		}
+		return subcommands.ExitSuccess
 	case constants.MYSQLDUMP, constants.PGDUMP:
 		err := cmd.handleDatabaseDumpFile(ctx, dbURI, cmd.sourceFormat, dialect, spannerAccessor, sourceReader)
 		if err != nil {
@@ -164,7 +196,8 @@ func validateSpannerAccessor(ctx context.Context, dbURI string) (spanneraccessor
 }
 
 // validateUriRemote validate if source URI and schema URI are accessible. Return sourceReader, schemaReader, error.
-// If sourceFormat is not CSV, schemaReader will be nil.
+// schemaReader is nil unless schema URI is passed for a CSV, Parquet, Avro or
+// JSONL file.
 func validateUriRemote(ctx context.Context, input *ImportDataCmd) (file_reader.FileReader, file_reader.FileReader, error) {
 	sourceReader, err := file_reader.NewFileReader(ctx, input.sourceUri)
 	if err != nil {
@@ -172,7 +205,7 @@ func validateUriRemote(ctx context.Context, input *ImportDataCmd) (file_reader.F
 	}
 
 	var schemaReader file_reader.FileReader
-	if input.sourceFormat == constants.CSV {
+	if (input.sourceFormat == constants.CSV || isRecordFormat(input.sourceFormat)) && len(input.schemaUri) != 0 {
 		schemaReader, err = file_reader.NewFileReader(ctx, input.schemaUri)
 		if err != nil {
 			sourceReader.Close()
@@ -182,6 +215,16 @@ func validateUriRemote(ctx context.Context, input *ImportDataCmd) (file_reader.F
 	return sourceReader, schemaReader, nil
 }
 
+// isRecordFormat returns true for the formats of files of records with an
+// embedded or inferred schema.
+func isRecordFormat(sourceFormat string) bool {
+	switch sourceFormat {
+	case constants.PARQUET, constants.AVRO, constants.JSONL:
+		return true
+	}
+	return false
+}
+
 func getDialectWithDefaults(dialect string) string {
 	dialect = strings.ToLower(dialect)
 	switch dialect {
@@ -198,9 +241,9 @@ func getDialectWithDefaults(dialect string) string {
 /*
 1. instance Id is mandatory and accessible
 2. database name is mandatory and accessible
-3. source uri is mandatory and accessible
+3. source uri or manifest is mandatory and accessible
 4. source format is valid
-5. If CSV, schema URI is mandatory and accessible
+5. several files are only imported for csv and record formats, into a named table
 */
 func validateInputLocal(input *ImportDataCmd) error {
 
@@ -213,21 +256,36 @@ func validateInputLocal(input *ImportDataCmd) error {
 		return fmt.Errorf("Please specify databaseName using the --database parameter. Received  databaseName: %v", input.database)
 	}
 
-	if len(input.sourceUri) == 0 {
+	if len(input.sourceUri) == 0 && len(input.manifest) == 0 {
 		return fmt.Errorf("Please specify sourceUri using the --source-uri parameter. Received  sourceUri: %v", input.sourceUri)
 	}
 
+	if len(input.sourceUri) != 0 && len(input.manifest) != 0 {
+		return fmt.Errorf("Please specify either --source-uri or --manifest, not both")
+	}
+
 	if len(input.sourceFormat) == 0 {
 		return fmt.Errorf("Please specify sourceFormat using the --source-format parameter. Received  sourceFormat: %v", input.sourceFormat)
 	}
 
-	if input.sourceFormat == constants.CSV && len(input.schemaUri) == 0 {
-		return fmt.Errorf("Please specify schemaUri using the --schema-uri parameter. Received  schemaUri: %v", input.sourceFormat)
+	if input.isMultiFileImport() {
+		if input.sourceFormat != constants.CSV && !isRecordFormat(input.sourceFormat) {
+			return fmt.Errorf("Several files can only be imported for csv, parquet, avro and jsonl formats. Received sourceFormat: %v", input.sourceFormat)
+		}
+		if len(input.manifest) == 0 && len(input.tableName) == 0 {
+			return fmt.Errorf("Please specify tableName using the --table-name parameter when --source-uri matches several files")
+		}
 	}
 
 	return err
 }
 
+// isMultiFileImport returns true if several files are imported: a manifest
+// is passed, or the source uri is a glob or prefix.
+func (cmd *ImportDataCmd) isMultiFileImport() bool {
+	return len(cmd.manifest) != 0 || file_reader.IsFilePattern(cmd.sourceUri)
+}
+
 func (cmd *ImportDataCmd) handleCsv(ctx context.Context, dbURI, dialect string,
 	sp spanneraccessor.SpannerAccessor, sourceReader file_reader.FileReader, schemaReader file_reader.FileReader) error {
 
@@ -240,6 +298,13 @@ func (cmd *ImportDataCmd) handleCsv(ctx context.Context, dbURI, dialect string,
 	}
 
 	startTime := time.Now()
+	if schemaReader == nil {
+		sourceReader, schemaReader, err = cmd.inferCsvSchema(ctx, dialect, sourceReader)
+		if err != nil {
+			return err
+		}
+		defer schemaReader.Close()
+	}
 	csvSchema := import_file.NewCsvSchema(cmd.project, cmd.instance,
 		cmd.database, cmd.tableName, cmd.schemaUri, schemaReader)
 	err = csvSchema.CreateSchema(ctx, dialect, sp)
@@ -262,6 +327,240 @@ func (cmd *ImportDataCmd) handleCsv(ctx context.Context, dbURI, dialect string,
 
 }
 
+// inferCsvSchema infers the schema of the csv file and writes it to
+// <table-name>.schema.json in the working directory, for review and reuse
+// with --schema-uri. If that file exists, a numbered name such as
+// <table-name>.2.schema.json is used instead. It returns the reader of the
+// source file to import, whose header row names the inferred columns, and
+// the reader of the schema file.
+func (cmd *ImportDataCmd) inferCsvSchema(ctx context.Context, dialect string, sourceReader file_reader.FileReader) (file_reader.FileReader, file_reader.FileReader, error) {
+	colDefs, sourceReader, err := import_file.InferCsvSchema(ctx, sourceReader, rune(cmd.csvFieldDelimiter[0]), cmd.csvSampleRows, dialect)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't infer schema of %s: %w", cmd.sourceUri, err)
+	}
+	schema, err := json.MarshalIndent(colDefs, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+	cmd.schemaUri, err = writeNewFile(cmd.tableName, ".schema.json", schema)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't write inferred schema: %w", err)
+	}
+	logger.Log.Info(fmt.Sprintf("Wrote the inferred schema of %s to %s. Review it and pass it with --schema-uri to reuse it.", cmd.sourceUri, cmd.schemaUri))
+	schemaReader, err := file_reader.NewFileReader(ctx, cmd.schemaUri)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sourceReader, schemaReader, nil
+}
+
+// writeNewFile writes data to a new file <prefix><suffix> in the working
+// directory, or <prefix>.<n><suffix> for the first n such that the file
+// doesn't exist yet, and returns its name. Existing files are never
+// overwritten.
+func writeNewFile(prefix, suffix string, data []byte) (string, error) {
+	for n := 1; ; n++ {
+		name := prefix + suffix
+		if n > 1 {
+			name = fmt.Sprintf("%s.%d%s", prefix, n, suffix)
+		}
+		f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if _, err := f.Write(data); err != nil {
+			f.Close()
+			return "", err
+		}
+		return name, f.Close()
+	}
+}
+
+// handleRecordFile imports a Parquet, Avro or JSONL file. The table is
+// created from the schema file if one is passed, and from the schema of the
+// source file otherwise.
+func (cmd *ImportDataCmd) handleRecordFile(ctx context.Context, dbURI, dialect string,
+	sp spanneraccessor.SpannerAccessor, sourceReader file_reader.FileReader, schemaReader file_reader.FileReader) error {
+
+	cmd.tableName = handleTableNameDefaults(cmd.tableName, cmd.sourceUri)
+
+	infoSchema, err := spanner.NewInfoSchemaImplWithSpannerClient(ctx, dbURI, dialect)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("Unable to instantiate spanner client %v", err))
+		return err
+	}
+
+	startTime := time.Now()
+	if schemaReader != nil {
+		err = import_file.NewCsvSchema(cmd.project, cmd.instance,
+			cmd.database, cmd.tableName, cmd.schemaUri, schemaReader).CreateSchema(ctx, dialect, sp)
+	} else {
+		err = import_file.NewRecordSchema(cmd.project, cmd.instance,
+			cmd.database, cmd.tableName, cmd.sourceFormat, sourceReader).CreateSchema(ctx, dialect, sp)
+	}
+
+	endTime1 := time.Now()
+	elapsedTime := endTime1.Sub(startTime)
+	logger.Log.Info(fmt.Sprintf("Schema creation took %f secs", elapsedTime.Seconds()))
+	if err != nil {
+		return err
+	}
+
+	recordData := import_file.NewRecordData(cmd.project, cmd.instance,
+		cmd.database, cmd.tableName, cmd.sourceFormat, sourceReader)
+	err = recordData.ImportData(ctx, infoSchema, dialect, internal.MakeConv(), &common.InfoSchemaImpl{})
+
+	endTime2 := time.Now()
+	elapsedTime = endTime2.Sub(endTime1)
+	logger.Log.Info(fmt.Sprintf("Data import took %f secs", elapsedTime.Seconds()))
+	return err
+}
+
+// handleFiles imports the files matched by the source uri, or by the file
+// patterns of the tables of the manifest. Each table is created from its
+// schema file, or from the schema of its first file, and then the files of
+// all the tables are imported concurrently.
+func (cmd *ImportDataCmd) handleFiles(ctx context.Context, dbURI, dialect string, sp spanneraccessor.SpannerAccessor) error {
+	tables, err := cmd.getManifestTables(ctx)
+	if err != nil {
+		return err
+	}
+
+	infoSchema, err := spanner.NewInfoSchemaImplWithSpannerClient(ctx, dbURI, dialect)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("Unable to instantiate spanner client %v", err))
+		return err
+	}
+
+	startTime := time.Now()
+	var files []import_file.FileImport
+	for _, table := range tables {
+		uris, err := import_file.ExpandFilePatterns(ctx, table.File_patterns)
+		if err != nil {
+			return err
+		}
+		logger.Log.Info(fmt.Sprintf("Found %d files for table %s", len(uris), table.Table_name))
+		inferred, err := cmd.createFileTable(ctx, dialect, sp, table.Table_name, table.Schema_uri, uris[0])
+		if err != nil {
+			return fmt.Errorf("can't create table %s: %w", table.Table_name, err)
+		}
+		for _, uri := range uris {
+			files = append(files, import_file.FileImport{TableName: table.Table_name, SourceUri: uri, RenameCsvHeader: inferred})
+		}
+	}
+
+	endTime1 := time.Now()
+	elapsedTime := endTime1.Sub(startTime)
+	logger.Log.Info(fmt.Sprintf("Schema creation took %f secs", elapsedTime.Seconds()))
+
+	filesData := import_file.NewFilesData(cmd.project, cmd.instance, cmd.database,
+		cmd.sourceFormat, cmd.csvFieldDelimiter, files, cmd.fileConcurrency)
+	statuses, err := filesData.ImportData(ctx, infoSchema, dialect, &common.InfoSchemaImpl{})
+
+	endTime2 := time.Now()
+	elapsedTime = endTime2.Sub(endTime1)
+	logger.Log.Info(fmt.Sprintf("Data import took %f secs", elapsedTime.Seconds()))
+	if statuses == nil {
+		return err
+	}
+	// The summary names the files that failed, also when the import fails.
+	if summaryErr := logFileImportSummary(statuses); err == nil {
+		err = summaryErr
+	}
+	return err
+}
+
+// getManifestTables returns the tables of the manifest, or the table of the
+// source uri if no manifest is passed.
+func (cmd *ImportDataCmd) getManifestTables(ctx context.Context) ([]import_file.ManifestTable, error) {
+	if len(cmd.manifest) == 0 {
+		return []import_file.ManifestTable{{
+			ManifestTable: utils.ManifestTable{Table_name: handleTableNameDefaults(cmd.tableName, cmd.sourceUri), File_patterns: []string{cmd.sourceUri}},
+			Schema_uri:    cmd.schemaUri,
+		}}, nil
+	}
+	manifestReader, err := file_reader.NewFileReader(ctx, cmd.manifest)
+	if err != nil {
+		return nil, fmt.Errorf("manifest:%v not accessible. Please check the input and access permissions and try again", cmd.manifest)
+	}
+	defer manifestReader.Close()
+	manifest, err := manifestReader.ReadAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can't read manifest file due to: %v", err)
+	}
+	tables, err := import_file.ParseManifest(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", cmd.manifest, err)
+	}
+	for i := range tables {
+		tables[i].Table_name = handleTableNameDefaults(tables[i].Table_name, "")
+	}
+	return tables, nil
+}
+
+// createFileTable creates a table from its schema file, if one is passed,
+// and from the schema of the file at sourceUri otherwise. It returns
+// whether the schema was inferred from a csv file, whose column names are
+// then those of csv headers made valid Spanner identifiers.
+func (cmd *ImportDataCmd) createFileTable(ctx context.Context, dialect string, sp spanneraccessor.SpannerAccessor,
+	tableName, schemaUri, sourceUri string) (bool, error) {
+	tableCmd := *cmd
+	tableCmd.tableName = tableName
+	tableCmd.schemaUri = schemaUri
+	tableCmd.sourceUri = sourceUri
+	sourceReader, schemaReader, err := validateUriRemote(ctx, &tableCmd)
+	if err != nil {
+		return false, err
+	}
+	defer sourceReader.Close()
+	inferred := false
+	if schemaReader == nil && cmd.sourceFormat == constants.CSV {
+		// The files of the table are read with their header rows renamed
+		// to the inferred column names.
+		_, schemaReader, err = tableCmd.inferCsvSchema(ctx, dialect, sourceReader)
+		if err != nil {
+			return false, err
+		}
+		inferred = true
+	}
+	if schemaReader == nil {
+		return false, import_file.NewRecordSchema(cmd.project, cmd.instance,
+			cmd.database, tableName, cmd.sourceFormat, sourceReader).CreateSchema(ctx, dialect, sp)
+	}
+	defer schemaReader.Close()
+	return inferred, import_file.NewCsvSchema(cmd.project, cmd.instance,
+		cmd.database, tableName, tableCmd.schemaUri, schemaReader).CreateSchema(ctx, dialect, sp)
+}
+
+// logFileImportSummary logs the status of each imported file, and returns an
+// error if any file failed.
+func logFileImportSummary(statuses []import_file.FileImportStatus) error {
+	failed := 0
+	rows, badRows, droppedRows := int64(0), int64(0), int64(0)
+	var summary strings.Builder
+	for _, status := range statuses {
+		result := "OK"
+		if status.Err != nil {
+			failed++
+			result = fmt.Sprintf("FAILED: %v", status.Err)
+		}
+		rows += status.Rows
+		badRows += status.BadRows
+		droppedRows += status.DroppedRows
+		fmt.Fprintf(&summary, "\n  %s -> %s: %d rows, %d bad rows, %d dropped rows, %.1f secs, %s",
+			status.SourceUri, status.TableName, status.Rows, status.BadRows, status.DroppedRows, status.Duration.Seconds(), result)
+	}
+	logger.Log.Info(fmt.Sprintf("Imported %d of %d files, %d rows, %d bad rows, %d dropped rows:%s",
+		len(statuses)-failed, len(statuses), rows, badRows, droppedRows, summary.String()))
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed to import", failed, len(statuses))
+	}
+	return nil
+}
+
 func getDBUri(projectId, instanceId, databaseName string) string {
 	return fmt.Sprintf("projects/%s/instances/%s/databases/%s", projectId, instanceId, databaseName)
 }
@@ -317,9 +616,10 @@ func (cmd *ImportDataCmd) Synopsis() string {
 // Usage returns usage info of the command.
 func (cmd *ImportDataCmd) Usage() string {
 	return fmt.Sprintf(`%v import --instance-id=i1 --database-name=db1 --source-format=csv --source-uri=uri1 --schema-uri=uri2 ...
+%v import --instance-id=i1 --database-name=db1 --source-format=csv --manifest=uri1 ...
 
 Import data from supported source files to spanner
-`, path.Base(os.Args[0]))
+`, path.Base(os.Args[0]), path.Base(os.Args[0]))
 
 }
 
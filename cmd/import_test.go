@@ -16,8 +16,10 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -102,11 +104,11 @@ func TestValidateInputLocal_MissingSourceFormat(t *testing.T) {
 	assert.Contains(t, err.Error(), "Please specify sourceFormat")
 }
 
-func TestValidateInputLocal_CSVMissingSchemaURI(t *testing.T) {
+func TestValidateInputLocal_CSVWithoutSchemaURI(t *testing.T) {
+	// The schema of the csv is inferred if no schema URI is passed.
 	input := &ImportDataCmd{instance: "test-instance", database: "test-db", sourceUri: "file:///tmp/data.csv", sourceFormat: constants.CSV}
 	err := validateInputLocal(input)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "Please specify schemaUri")
+	assert.NoError(t, err)
 }
 
 func TestValidateInputLocal_SuccessCSV(t *testing.T) {
@@ -839,12 +841,319 @@ func TestHandleCsv(t *testing.T) {
 	}
 }
 
+func TestHandleCsv_InferSchema(t *testing.T) {
+	t.Chdir(t.TempDir())
+	ctx := context.Background()
+	cmd := &ImportDataCmd{
+		project:           "test-project",
+		instance:          "test-instance",
+		database:          "test-db",
+		sourceUri:         "gs://test-bucket/orders.csv",
+		csvFieldDelimiter: ";",
+		csvSampleRows:     10,
+	}
+	sourceReader := &file_reader.MockFileReader{}
+	inferredReader := &file_reader.MockFileReader{}
+	colDefs := []import_file.ColumnDefinition{{Name: "id", Type: ddl.Int64, NotNull: true, PkOrder: 1}}
+
+	originalNewInfoSchemaFunc := sourcesspanner.NewInfoSchemaImplWithSpannerClient
+	originalInferCsvSchema := import_file.InferCsvSchema
+	originalNewCsvSchema := import_file.NewCsvSchema
+	originalNewCsvData := import_file.NewCsvData
+	defer func() {
+		sourcesspanner.NewInfoSchemaImplWithSpannerClient = originalNewInfoSchemaFunc
+		import_file.InferCsvSchema = originalInferCsvSchema
+		import_file.NewCsvSchema = originalNewCsvSchema
+		import_file.NewCsvData = originalNewCsvData
+	}()
+
+	sourcesspanner.NewInfoSchemaImplWithSpannerClient = func(ctx context.Context, dbURI string, spDialect string) (*sourcesspanner.InfoSchemaImpl, error) {
+		return &sourcesspanner.InfoSchemaImpl{}, nil
+	}
+	import_file.InferCsvSchema = func(ctx context.Context, sourceFileReader file_reader.FileReader, delimiter rune, sampleRows int, dialect string) ([]import_file.ColumnDefinition, file_reader.FileReader, error) {
+		assert.Same(t, sourceReader, sourceFileReader)
+		assert.Equal(t, ';', delimiter)
+		assert.Equal(t, 10, sampleRows)
+		assert.Equal(t, constants.DIALECT_GOOGLESQL, dialect)
+		return colDefs, inferredReader, nil
+	}
+	wantSchemaUri := "orders.schema.json"
+	import_file.NewCsvSchema = func(projectId, instanceId, dbName, tableName, schemaUri string, schemaFileReader file_reader.FileReader) import_file.CsvSchema {
+		assert.Equal(t, wantSchemaUri, schemaUri)
+		schema, err := schemaFileReader.ReadAll(ctx)
+		assert.NoError(t, err)
+		var got []import_file.ColumnDefinition
+		assert.NoError(t, json.Unmarshal(schema, &got))
+		assert.Equal(t, colDefs, got)
+		return &import_file.MockCsvSchema{}
+	}
+	import_file.NewCsvData = func(projectId, instanceId, dbName, tableName, sourceUri, csvFieldDelimiter string, sourceFileReader file_reader.FileReader) import_file.CsvData {
+		assert.Same(t, inferredReader, sourceFileReader)
+		return &import_file.MockCsvData{}
+	}
+
+	err := cmd.handleCsv(ctx, "projects/test-project/instances/test-instance/databases/test-db", constants.DIALECT_GOOGLESQL, &spanneraccessor.SpannerAccessorMock{}, sourceReader, nil)
+	assert.NoError(t, err)
+	assert.FileExists(t, "orders.schema.json")
+
+	// An existing schema file isn't overwritten.
+	assert.NoError(t, os.WriteFile("orders.schema.json", []byte("[]"), 0644))
+	wantSchemaUri = "orders.2.schema.json"
+	err = cmd.handleCsv(ctx, "projects/test-project/instances/test-instance/databases/test-db", constants.DIALECT_GOOGLESQL, &spanneraccessor.SpannerAccessorMock{}, sourceReader, nil)
+	assert.NoError(t, err)
+	b, err := os.ReadFile("orders.schema.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "[]", string(b))
+	assert.FileExists(t, "orders.2.schema.json")
+
+	import_file.InferCsvSchema = func(ctx context.Context, sourceFileReader file_reader.FileReader, delimiter rune, sampleRows int, dialect string) ([]import_file.ColumnDefinition, file_reader.FileReader, error) {
+		return nil, nil, fmt.Errorf("empty file")
+	}
+	err = cmd.handleCsv(ctx, "projects/test-project/instances/test-instance/databases/test-db", constants.DIALECT_GOOGLESQL, &spanneraccessor.SpannerAccessorMock{}, sourceReader, nil)
+	assert.Error(t, err)
+}
+
+func TestHandleRecordFile(t *testing.T) {
+	expectedDbUri := "projects/test-project/instances/test-instance/databases/test-db"
+
+	testCases := []struct {
+		desc             string
+		schemaReader     file_reader.FileReader
+		expectedErr      error
+		csvSchemaFunc    func(projectId, instanceId, dbName, tableName, schemaUri string, schemaFileReader file_reader.FileReader) import_file.CsvSchema
+		recordSchemaFunc func(projectId, instanceId, dbName, tableName, sourceFormat string, sourceFileReader file_reader.FileReader) import_file.RecordSchema
+		recordDataFunc   func(projectId, instanceId, dbName, tableName, sourceFormat string, sourceFileReader file_reader.FileReader) import_file.RecordData
+	}{
+		{
+			desc: "Schema from source file",
+			csvSchemaFunc: func(projectId, instanceId, dbName, tableName, schemaUri string, schemaFileReader file_reader.FileReader) import_file.CsvSchema {
+				t.Errorf("schema file used without schema URI")
+				return &import_file.MockCsvSchema{}
+			},
+			recordSchemaFunc: func(projectId, instanceId, dbName, tableName, sourceFormat string, sourceFileReader file_reader.FileReader) import_file.RecordSchema {
+				assert.Equal(t, "testtable", tableName)
+				assert.Equal(t, constants.PARQUET, sourceFormat)
+				return &import_file.MockRecordSchema{}
+			},
+			recordDataFunc: func(projectId, instanceId, dbName, tableName, sourceFormat string, sourceFileReader file_reader.FileReader) import_file.RecordData {
+				assert.Equal(t, "testtable", tableName)
+				assert.Equal(t, constants.PARQUET, sourceFormat)
+				return &import_file.MockRecordData{}
+			},
+		},
+		{
+			desc:         "Schema from schema file",
+			schemaReader: &file_reader.LocalFileReaderImpl{},
+			csvSchemaFunc: func(projectId, instanceId, dbName, tableName, schemaUri string, schemaFileReader file_reader.FileReader) import_file.CsvSchema {
+				assert.Equal(t, "gs://test-bucket/test_schema.json", schemaUri)
+				return &import_file.MockCsvSchema{}
+			},
+			recordSchemaFunc: func(projectId, instanceId, dbName, tableName, sourceFormat string, sourceFileReader file_reader.FileReader) import_file.RecordSchema {
+				t.Errorf("source file schema used with schema URI")
+				return &import_file.MockRecordSchema{}
+			},
+			recordDataFunc: func(projectId, instanceId, dbName, tableName, sourceFormat string, sourceFileReader file_reader.FileReader) import_file.RecordData {
+				return &import_file.MockRecordData{}
+			},
+		},
+		{
+			desc: "Schema creation fails",
+			recordSchemaFunc: func(projectId, instanceId, dbName, tableName, sourceFormat string, sourceFileReader file_reader.FileReader) import_file.RecordSchema {
+				return &import_file.MockRecordSchema{
+					CreateSchemaFn: func(ctx context.Context, dialect string, sp spanneraccessor.SpannerAccessor) error {
+						return fmt.Errorf("schema creation error")
+					},
+				}
+			},
+			expectedErr: fmt.Errorf("schema creation error"),
+		},
+		{
+			desc: "Data import fails",
+			recordSchemaFunc: func(projectId, instanceId, dbName, tableName, sourceFormat string, sourceFileReader file_reader.FileReader) import_file.RecordSchema {
+				return &import_file.MockRecordSchema{}
+			},
+			recordDataFunc: func(projectId, instanceId, dbName, tableName, sourceFormat string, sourceFileReader file_reader.FileReader) import_file.RecordData {
+				return &import_file.MockRecordData{
+					ImportDataFn: func(ctx context.Context, spannerInfoSchema *sourcesspanner.InfoSchemaImpl, dialect string, conv *internal.Conv, commonInfoSchema common.InfoSchemaInterface) error {
+						return fmt.Errorf("data import error")
+					},
+				}
+			},
+			expectedErr: fmt.Errorf("data import error"),
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctx := context.Background()
+			cmd := &ImportDataCmd{
+				project:      "test-project",
+				instance:     "test-instance",
+				database:     "test-db",
+				tableName:    "test-table",
+				sourceUri:    "gs://test-bucket/test.parquet",
+				sourceFormat: constants.PARQUET,
+			}
+			if tC.schemaReader != nil {
+				cmd.schemaUri = "gs://test-bucket/test_schema.json"
+			}
+			originalNewInfoSchemaFunc := sourcesspanner.NewInfoSchemaImplWithSpannerClient
+			originalNewCsvSchema := import_file.NewCsvSchema
+			originalNewRecordSchema := import_file.NewRecordSchema
+			originalNewRecordData := import_file.NewRecordData
+
+			defer func() {
+				sourcesspanner.NewInfoSchemaImplWithSpannerClient = originalNewInfoSchemaFunc
+				import_file.NewCsvSchema = originalNewCsvSchema
+				import_file.NewRecordSchema = originalNewRecordSchema
+				import_file.NewRecordData = originalNewRecordData
+			}()
+
+			sourcesspanner.NewInfoSchemaImplWithSpannerClient = func(ctx context.Context, dbURI string, spDialect string) (*sourcesspanner.InfoSchemaImpl, error) {
+				assert.Equal(t, expectedDbUri, dbURI)
+				return &sourcesspanner.InfoSchemaImpl{}, nil
+			}
+			import_file.NewCsvSchema = tC.csvSchemaFunc
+			import_file.NewRecordSchema = tC.recordSchemaFunc
+			import_file.NewRecordData = tC.recordDataFunc
+
+			err := cmd.handleRecordFile(ctx, expectedDbUri, constants.DIALECT_GOOGLESQL, &spanneraccessor.SpannerAccessorMock{}, &file_reader.GcsFileReaderImpl{}, tC.schemaReader)
+
+			if tC.expectedErr != nil {
+				assert.EqualError(t, err, tC.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateInputLocal_MultiFile(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		input       ImportDataCmd
+		expectedErr string
+	}{
+		{
+			desc:  "Glob",
+			input: ImportDataCmd{sourceUri: "gs://bucket/export/part-*.csv", sourceFormat: constants.CSV, tableName: "singers"},
+		},
+		{
+			desc:  "Manifest",
+			input: ImportDataCmd{manifest: "manifest.json", sourceFormat: constants.PARQUET},
+		},
+		{
+			desc:        "Glob without table name",
+			input:       ImportDataCmd{sourceUri: "s3://bucket/export/", sourceFormat: constants.CSV},
+			expectedErr: "Please specify tableName",
+		},
+		{
+			desc:        "Source uri and manifest",
+			input:       ImportDataCmd{sourceUri: "singers.csv", manifest: "manifest.json", sourceFormat: constants.CSV},
+			expectedErr: "either --source-uri or --manifest",
+		},
+		{
+			desc:        "Dump format",
+			input:       ImportDataCmd{manifest: "manifest.json", sourceFormat: constants.MYSQLDUMP},
+			expectedErr: "Several files can only be imported",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			tC.input.instance = "test-instance"
+			tC.input.database = "test-db"
+			err := validateInputLocal(&tC.input)
+			if tC.expectedErr != "" {
+				assert.ErrorContains(t, err, tC.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHandleFiles(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	for _, name := range []string{"singers-0000.csv", "singers-0001.csv", "albums-0000.csv"} {
+		assert.NoError(t, os.WriteFile(name, []byte("id,name\n1,a\n2,b\n"), 0644))
+	}
+	assert.NoError(t, os.WriteFile("albums.schema.json", []byte(`[{"Name": "id", "Type": "INT64", "NotNull": true, "PkOrder": 1}]`), 0644))
+	assert.NoError(t, os.WriteFile("manifest.json", []byte(`[
+		{"table_name": "Singers", "file_patterns": ["singers-*.csv"]},
+		{"table_name": "Albums", "file_patterns": ["albums-0000.csv"], "schema_uri": "albums.schema.json"}
+	]`), 0644))
+	expectedDbUri := "projects/test-project/instances/test-instance/databases/test-db"
+
+	originalNewInfoSchemaFunc := sourcesspanner.NewInfoSchemaImplWithSpannerClient
+	originalNewCsvSchema := import_file.NewCsvSchema
+	originalNewFilesData := import_file.NewFilesData
+	defer func() {
+		sourcesspanner.NewInfoSchemaImplWithSpannerClient = originalNewInfoSchemaFunc
+		import_file.NewCsvSchema = originalNewCsvSchema
+		import_file.NewFilesData = originalNewFilesData
+	}()
+	sourcesspanner.NewInfoSchemaImplWithSpannerClient = func(ctx context.Context, dbURI string, spDialect string) (*sourcesspanner.InfoSchemaImpl, error) {
+		return &sourcesspanner.InfoSchemaImpl{}, nil
+	}
+	var schemaUris []string
+	import_file.NewCsvSchema = func(projectId, instanceId, dbName, tableName, schemaUri string, schemaFileReader file_reader.FileReader) import_file.CsvSchema {
+		schemaUris = append(schemaUris, tableName+":"+schemaUri)
+		return &import_file.MockCsvSchema{}
+	}
+	var fileErr error
+	import_file.NewFilesData = func(projectId, instanceId, dbName, sourceFormat, csvFieldDelimiter string, files []import_file.FileImport, concurrency int) import_file.FilesData {
+		assert.Equal(t, constants.CSV, sourceFormat)
+		assert.Equal(t, 3, concurrency)
+		assert.Equal(t, []import_file.FileImport{
+			{TableName: "singers", SourceUri: "singers-0000.csv", RenameCsvHeader: true},
+			{TableName: "singers", SourceUri: "singers-0001.csv", RenameCsvHeader: true},
+			{TableName: "albums", SourceUri: "albums-0000.csv"},
+		}, files)
+		return &import_file.MockFilesData{
+			ImportDataFn: func(ctx context.Context, spannerInfoSchema *sourcesspanner.InfoSchemaImpl, dialect string, commonInfoSchema common.InfoSchemaInterface) ([]import_file.FileImportStatus, error) {
+				var statuses []import_file.FileImportStatus
+				for _, file := range files {
+					statuses = append(statuses, import_file.FileImportStatus{FileImport: file, Rows: 2, Err: fileErr})
+				}
+				return statuses, nil
+			},
+		}
+	}
+
+	cmd := &ImportDataCmd{
+		project:           "test-project",
+		instance:          "test-instance",
+		database:          "test-db",
+		manifest:          "manifest.json",
+		sourceFormat:      constants.CSV,
+		csvFieldDelimiter: ",",
+		csvSampleRows:     10,
+		fileConcurrency:   3,
+	}
+	err := cmd.handleFiles(context.Background(), expectedDbUri, constants.DIALECT_GOOGLESQL, &spanneraccessor.SpannerAccessorMock{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"singers:singers.schema.json", "albums:albums.schema.json"}, schemaUris)
+	assert.FileExists(t, "singers.schema.json")
+
+	fileErr = fmt.Errorf("can't read source file")
+	err = cmd.handleFiles(context.Background(), expectedDbUri, constants.DIALECT_GOOGLESQL, &spanneraccessor.SpannerAccessorMock{})
+	assert.EqualError(t, err, "3 of 3 files failed to import")
+
+	cmd.manifest = ""
+	cmd.sourceUri = "missing-*.csv"
+	cmd.tableName = "missing"
+	err = cmd.handleFiles(context.Background(), expectedDbUri, constants.DIALECT_GOOGLESQL, &spanneraccessor.SpannerAccessorMock{})
+	assert.ErrorContains(t, err, "no files match")
+}
+
 func fetchDDLString(conv *internal.Conv) string {
 	return strings.Replace(strings.Join(
 		ddl.GetDDL(
 			ddl.Config{Comments: false, ProtectIds: false, Tables: true, ForeignKeys: true, SpDialect: conv.SpDialect, Source: "mysql"},
 			conv.SpSchema,
 			conv.SpSequences,
+			conv.SpViews,
 			conv.DatabaseOptions), ";"), "\n", " ", -1)
 }
 
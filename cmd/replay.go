@@ -0,0 +1,202 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/conversion"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/writer"
+	"github.com/google/subcommands"
+	"go.uber.org/zap"
+)
+
+// ReplayCmd struct with flags.
+type ReplayCmd struct {
+	sessionJSON      string
+	targetProfile    string
+	input            string
+	filePrefix       string
+	WriteLimit       int64
+	DeadLetterDir    string
+	DeadLetterFormat string
+	dryRun           bool
+	logLevel         string
+}
+
+// Name returns the name of operation.
+func (cmd *ReplayCmd) Name() string {
+	return "replay"
+}
+
+// Synopsis returns summary of operation.
+func (cmd *ReplayCmd) Synopsis() string {
+	return "migrate rows saved in dead-letter files again"
+}
+
+// Usage returns usage info of the command.
+func (cmd *ReplayCmd) Usage() string {
+	return fmt.Sprintf(`%v replay -session=[session_file] -input=[dead_letter_dir] -target-profile="instance=my-instance,dbName=my-db"...
+
+Migrate rows that were rejected by a data or schema-and-data run with
+-dead-letter-dir again, typically after fixing the session file. Rows that
+failed conversion are converted again from their source values; rows that
+Spanner rejected are written again with the values originally sent. The
+replay flags are:
+`, path.Base(os.Args[0]))
+}
+
+// SetFlags sets the flags.
+func (cmd *ReplayCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&cmd.sessionJSON, "session", "", "Specifies the file we restore session state from")
+	f.StringVar(&cmd.targetProfile, "target-profile", "", "Flag for specifying connection profile for target database e.g., \"instance=my-instance,dbName=my-db\"")
+	f.StringVar(&cmd.input, "input", "", "Dead-letter file, or directory of dead-letter files, to replay")
+	f.StringVar(&cmd.filePrefix, "prefix", "", "File prefix for generated files")
+	f.Int64Var(&cmd.WriteLimit, "write-limit", DefaultWritersLimit, "Write limit for writes to spanner")
+	f.StringVar(&cmd.DeadLetterDir, "dead-letter-dir", "", "Directory to save rows that are rejected again in, one file per table")
+	f.StringVar(&cmd.DeadLetterFormat, "dead-letter-format", internal.DeadLetterFormatJSONL, "Format of the files in --dead-letter-dir (accepted values: `jsonl`, `csv`)")
+	f.BoolVar(&cmd.dryRun, "dry-run", false, "Flag for converting the rows without writing them to Spanner")
+	f.StringVar(&cmd.logLevel, "log-level", "DEBUG", "Configure the logging level for the command (INFO, DEBUG), defaults to DEBUG")
+}
+
+func (cmd *ReplayCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	var err error
+	defer func() {
+		if err != nil {
+			logger.Log.Fatal("FATAL error", zap.Error(err))
+		}
+	}()
+	err = logger.InitializeLogger(cmd.logLevel)
+	if err != nil {
+		logger.Log.Info(fmt.Sprint("Error initialising logger, did you specify a valid log-level? [DEBUG, INFO, WARN, ERROR, FATAL]", err))
+		return subcommands.ExitFailure
+	}
+	defer logger.Log.Sync()
+
+	if cmd.sessionJSON == "" || cmd.input == "" {
+		err = fmt.Errorf("please specify the session file with --session and the dead-letter files with --input")
+		return subcommands.ExitUsageError
+	}
+	if cmd.DeadLetterDir != "" && filepath.Clean(cmd.DeadLetterDir) == filepath.Clean(cmd.input) {
+		err = fmt.Errorf("--dead-letter-dir must be different from --input, so that the rows being replayed are kept")
+		return subcommands.ExitUsageError
+	}
+	targetProfile, err := profiles.NewTargetProfile(cmd.targetProfile, cmd.dryRun)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
+	if targetProfile.Conn.Sp.Dbname == "" {
+		err = fmt.Errorf("please specify the database to write to with dbName in --target-profile")
+		return subcommands.ExitUsageError
+	}
+	conv := internal.MakeConv()
+	err = conversion.ReadSessionFile(conv, cmd.sessionJSON)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
+	conv.Audit.SkipMetricsPopulation = os.Getenv("SKIP_METRICS_POPULATION") == "true"
+	var rows []internal.DeadLetter
+	rows, err = readDeadLetterInput(cmd.input)
+	if err != nil {
+		return subcommands.ExitFailure
+	}
+	closeDeadLetters, err := openDeadLetters(conv, cmd.DeadLetterDir, cmd.DeadLetterFormat, os.Stdout)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
+	defer closeDeadLetters()
+	if cmd.filePrefix == "" {
+		cmd.filePrefix = targetProfile.Conn.Sp.Dbname
+	}
+
+	now := time.Now()
+	config := writer.BatchWriterConfig{
+		BytesLimit: 100 * 1000 * 1000,
+		WriteLimit: cmd.WriteLimit,
+		RetryLimit: 1000,
+		Verbose:    internal.Verbose(),
+		// Rows that failed in a batch may have been written by a retry.
+		Upsert: true,
+	}
+	ioHelper := utils.IOStreams{In: os.Stdin, Out: os.Stdout}
+	var (
+		bw    *writer.BatchWriter
+		dbURI string
+	)
+	if cmd.dryRun {
+		conv.Audit.DryRun = true
+		dbURI = targetProfile.Conn.Sp.Dbname
+		bw = conversion.ReplayDeadLetters(conv, rows, config, nil)
+	} else {
+		adminClient, client, uri, e := CreateDatabaseClient(ctx, targetProfile, conv.Source, targetProfile.Conn.Sp.Dbname, ioHelper)
+		if e != nil {
+			err = fmt.Errorf("can't create database client: %v", e)
+			return subcommands.ExitFailure
+		}
+		defer adminClient.Close()
+		defer client.Close()
+		dbURI = uri
+		bw = conversion.ReplayDeadLetters(conv, rows, config, client)
+	}
+
+	rejected := conv.BadRows()
+	for _, n := range bw.DroppedRowsByTable() {
+		rejected += n
+	}
+	fmt.Fprintf(ioHelper.Out, "Replayed %d rows to %s: %d rejected again.\n", len(rows), dbURI, rejected)
+	conversion.WriteBadData(bw, conv, utils.GetBanner(now, dbURI), cmd.filePrefix+badDataFile, ioHelper.Out)
+	return subcommands.ExitSuccess
+}
+
+// readDeadLetterInput reads the rows in dead-letter file input, or in all
+// the dead-letter files in directory input.
+func readDeadLetterInput(input string) ([]internal.DeadLetter, error) {
+	fi, err := os.Stat(input)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{input}
+	if fi.IsDir() {
+		files = nil
+		for _, format := range []string{internal.DeadLetterFormatJSONL, internal.DeadLetterFormatCSV} {
+			matches, err := filepath.Glob(filepath.Join(input, "*."+format))
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, matches...)
+		}
+		sort.Strings(files)
+	}
+	var rows []internal.DeadLetter
+	for _, file := range files {
+		l, err := internal.ReadDeadLetters(file)
+		if err != nil {
+			return nil, fmt.Errorf("can't read dead-letter file %s: %v", file, err)
+		}
+		rows = append(rows, l...)
+	}
+	return rows, nil
+}
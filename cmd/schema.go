@@ -49,6 +49,9 @@ type SchemaCmd struct {
 	validate        bool
 	sessionJSON     string
 	sessionFileName string
+	TableSelection  string
+	Tables          string
+	ExcludeTables   string
 }
 
 // Name returns the name of operation.
@@ -85,6 +88,9 @@ func (cmd *SchemaCmd) SetFlags(f *flag.FlagSet) {
 	f.BoolVar(&cmd.validate, "validate", false, "Flag for validating if all the required input parameters are present")
 	f.StringVar(&cmd.sessionJSON, "session", "", "Optional. Specifies the file we restore session state from.")
 	f.StringVar(&cmd.sessionFileName, "session-file-name", "", "Optional. Specifies the name of the file we store session state in.")
+	f.StringVar(&cmd.TableSelection, "table-selection", "", "JSON file selecting the tables to migrate, with `include` and `exclude` lists of table name globs (a `where` map of row conditions is only used by data migrations)")
+	f.StringVar(&cmd.Tables, "tables", "", "Comma-separated globs of the names of the source tables to migrate (defaults to all tables)")
+	f.StringVar(&cmd.ExcludeTables, "exclude-tables", "", "Comma-separated globs of the names of source tables not to migrate")
 }
 
 func (cmd *SchemaCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
@@ -163,8 +169,14 @@ func (cmd *SchemaCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interfa
 			logger.Log.Error(fmt.Sprintf("error trying create ddl verifier: %v", err))
 			return subcommands.ExitFailure
 		}
+		var tableSelection *internal.TableSelection
+		tableSelection, err = loadTableSelection(cmd.TableSelection, cmd.Tables, cmd.ExcludeTables, sourceProfile.Driver)
+		if err != nil {
+			return subcommands.ExitUsageError
+		}
 		sfs := &conversion.SchemaFromSourceImpl{
-			DdlVerifier: ddlVerifier,
+			DdlVerifier:    ddlVerifier,
+			TableSelection: tableSelection,
 		}
 		conv, err = convImpl.SchemaConv(cmd.project, sourceProfile, targetProfile, &ioHelper, sfs)
 		if err != nil {
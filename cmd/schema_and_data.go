@@ -46,6 +46,17 @@ type SchemaAndDataCmd struct {
 	filePrefix       string // TODO: move filePrefix to global flags
 	project          string
 	WriteLimit       int64
+	ParallelTables   int
+	ChunksPerTable   int
+	Checkpoint       string
+	Resume           bool
+	DeadLetterDir    string
+	DeadLetterFormat string
+	SamplePercent    float64
+	SampleRows       int64
+	TableSelection   string
+	Tables           string
+	ExcludeTables    string
 	dryRun           bool
 	logLevel         string
 	validate         bool
@@ -84,6 +95,17 @@ func (cmd *SchemaAndDataCmd) SetFlags(f *flag.FlagSet) {
 	f.StringVar(&cmd.filePrefix, "prefix", "", "File prefix for generated files")
 	f.StringVar(&cmd.project, "project", "", "Flag spcifying default project id for all the generated resources for the migration")
 	f.Int64Var(&cmd.WriteLimit, "write-limit", DefaultWritersLimit, "Write limit for writes to spanner")
+	f.IntVar(&cmd.ParallelTables, "parallel-tables", 1, "Number of tables to read from the source database concurrently (MySQL, PostgreSQL, SQL Server and Oracle only)")
+	f.IntVar(&cmd.ChunksPerTable, "chunks-per-table", 1, "Number of primary key ranges to read each table in concurrently, for tables with an integer leading primary key column (MySQL, PostgreSQL, SQL Server and Oracle only)")
+	f.StringVar(&cmd.Checkpoint, "checkpoint", "", "Saves data migration progress so that it can be resumed with --resume: a local file path, or `metadata-db` to save it in the internal metadata database (defaults to <prefix>.checkpoint.json when --resume is set)")
+	f.BoolVar(&cmd.Resume, "resume", false, "Resumes an interrupted data migration from the progress saved with --checkpoint, skipping data already written")
+	f.StringVar(&cmd.DeadLetterDir, "dead-letter-dir", "", "Directory to save rows that fail conversion or writing to Spanner in, one file per table. The rows can be migrated again with the replay command")
+	f.StringVar(&cmd.DeadLetterFormat, "dead-letter-format", internal.DeadLetterFormatJSONL, "Format of the files in --dead-letter-dir (accepted values: `jsonl`, `csv`)")
+	f.StringVar(&cmd.TableSelection, "table-selection", "", "JSON file selecting the tables to migrate, with `include` and `exclude` lists of table name globs, and the rows to migrate, with a `where` map from table name to a condition in the source database's SQL")
+	f.StringVar(&cmd.Tables, "tables", "", "Comma-separated globs of the names of the source tables to migrate (defaults to all tables)")
+	f.StringVar(&cmd.ExcludeTables, "exclude-tables", "", "Comma-separated globs of the names of source tables not to migrate")
+	f.Float64Var(&cmd.SamplePercent, "sample-percent", 0, "Migrates only about this percentage of the rows of tables that don't reference other tables, and the rows of other tables that reference them, keeping foreign keys and interleaving intact")
+	f.Int64Var(&cmd.SampleRows, "sample-rows", 0, "Migrates at most this many rows per table, keeping foreign keys and interleaving intact")
 	f.BoolVar(&cmd.dryRun, "dry-run", false, "Flag for generating DDL and schema conversion report without creating a spanner database")
 	f.StringVar(&cmd.logLevel, "log-level", "DEBUG", "Configure the logging level for the command (INFO, DEBUG), defaults to DEBUG")
 	f.BoolVar(&cmd.validate, "validate", false, "Flag for validating if all the required input parameters are present")
@@ -113,6 +135,10 @@ func (cmd *SchemaAndDataCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...
 		err = fmt.Errorf("error while preparing prerequisites for migration: %v", err)
 		return subcommands.ExitUsageError
 	}
+	if err = conversion.CheckDataSource(sourceProfile); err != nil {
+		logger.Log.Error("Can't migrate data", zap.Error(err))
+		return subcommands.ExitUsageError
+	}
 	if cmd.project == "" {
 		getInfo := &utils.GetUtilInfoImpl{}
 		cmd.project, err = getInfo.GetProject()
@@ -144,8 +170,13 @@ func (cmd *SchemaAndDataCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...
 		logger.Log.Error(fmt.Sprintf("error trying create ddl verifier: %v", err))
 		return subcommands.ExitFailure
 	}
+	tableSelection, err := loadTableSelection(cmd.TableSelection, cmd.Tables, cmd.ExcludeTables, sourceProfile.Driver)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
 	sfs := &conversion.SchemaFromSourceImpl{
-		DdlVerifier: ddlVerifier,
+		DdlVerifier:    ddlVerifier,
+		TableSelection: tableSelection,
 	}
 	if !cmd.dryRun {
 		_, _, _, err = targetProfile.GetResourceIds(ctx, time.Now(), sourceProfile.Driver, ioHelper.Out, &utils.GetUtilInfoImpl{})
@@ -160,6 +191,17 @@ func (cmd *SchemaAndDataCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...
 	}
 	schemaCoversionEndTime := time.Now()
 	conv.Audit.SchemaConversionDuration = schemaCoversionEndTime.Sub(schemaConversionStartTime)
+	conv.BulkRead = internal.BulkReadOptions{ParallelTables: cmd.ParallelTables, ChunksPerTable: cmd.ChunksPerTable}
+	closeDeadLetters, err := openDeadLetters(conv, cmd.DeadLetterDir, cmd.DeadLetterFormat, ioHelper.Out)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
+	defer closeDeadLetters()
+	closeSampler, err := openSampler(conv, cmd.SamplePercent, cmd.SampleRows, cmd.Resume, ioHelper.Out)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
+	defer closeSampler()
 
 	// Populate migration request id and migration type in conv object.
 	conv.Audit.MigrationRequestId, _ = utils.GenerateName("smt-job")
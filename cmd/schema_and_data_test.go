@@ -37,6 +37,9 @@ func TestSchemaAndDataSetFlags(t *testing.T) {
 				targetProfile:    "",
 				filePrefix:       "",
 				WriteLimit:       DefaultWritersLimit,
+				ParallelTables:   1,
+				ChunksPerTable:   1,
+				DeadLetterFormat: "jsonl",
 				dryRun:           false,
 				logLevel:         "DEBUG",
 				SkipForeignKeys:  false,
@@ -54,6 +57,9 @@ func TestSchemaAndDataSetFlags(t *testing.T) {
 				targetProfile:    "",
 				filePrefix:       "",
 				WriteLimit:       DefaultWritersLimit,
+				ParallelTables:   1,
+				ChunksPerTable:   1,
+				DeadLetterFormat: "jsonl",
 				dryRun:           false,
 				logLevel:         "DEBUG",
 				SkipForeignKeys:  false,
@@ -71,6 +77,9 @@ func TestSchemaAndDataSetFlags(t *testing.T) {
 				targetProfile:    "target.json",
 				filePrefix:       "",
 				WriteLimit:       DefaultWritersLimit,
+				ParallelTables:   1,
+				ChunksPerTable:   1,
+				DeadLetterFormat: "jsonl",
 				dryRun:           false,
 				logLevel:         "DEBUG",
 				SkipForeignKeys:  false,
@@ -88,6 +97,9 @@ func TestSchemaAndDataSetFlags(t *testing.T) {
 				targetProfile:    "",
 				filePrefix:       "test",
 				WriteLimit:       100,
+				ParallelTables:   1,
+				ChunksPerTable:   1,
+				DeadLetterFormat: "jsonl",
 				dryRun:           false,
 				logLevel:         "DEBUG",
 				SkipForeignKeys:  false,
@@ -105,6 +117,9 @@ func TestSchemaAndDataSetFlags(t *testing.T) {
 				targetProfile:    "",
 				filePrefix:       "",
 				WriteLimit:       DefaultWritersLimit,
+				ParallelTables:   1,
+				ChunksPerTable:   1,
+				DeadLetterFormat: "jsonl",
 				dryRun:           true,
 				logLevel:         "INFO",
 				SkipForeignKeys:  false,
@@ -122,6 +137,9 @@ func TestSchemaAndDataSetFlags(t *testing.T) {
 				targetProfile:    "",
 				filePrefix:       "",
 				WriteLimit:       DefaultWritersLimit,
+				ParallelTables:   1,
+				ChunksPerTable:   1,
+				DeadLetterFormat: "jsonl",
 				dryRun:           false,
 				logLevel:         "DEBUG",
 				SkipForeignKeys:  true,
@@ -139,6 +157,9 @@ func TestSchemaAndDataSetFlags(t *testing.T) {
 				targetProfile:    "",
 				filePrefix:       "",
 				WriteLimit:       DefaultWritersLimit,
+				ParallelTables:   1,
+				ChunksPerTable:   1,
+				DeadLetterFormat: "jsonl",
 				dryRun:           false,
 				logLevel:         "DEBUG",
 				SkipForeignKeys:  false,
@@ -168,6 +189,9 @@ func TestSchemaAndDataSetFlags(t *testing.T) {
 				targetProfile:    "spanner.json",
 				filePrefix:       "output",
 				WriteLimit:       50,
+				ParallelTables:   1,
+				ChunksPerTable:   1,
+				DeadLetterFormat: "jsonl",
 				dryRun:           true,
 				logLevel:         "WARN",
 				SkipForeignKeys:  true,
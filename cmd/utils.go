@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"io"
 	"path/filepath"
 	"strings"
 	"time"
@@ -41,15 +42,20 @@ import (
 )
 
 var (
-	badDataFile   = ".dropped.txt"
-	schemaFile    = ".schema.txt"
-	sessionFile   = ".session.json"
-	overridesFile = ".overrides.json"
+	badDataFile    = ".dropped.txt"
+	schemaFile     = ".schema.txt"
+	sessionFile    = ".session.json"
+	overridesFile  = ".overrides.json"
+	checkpointFile = ".checkpoint.json"
+	validationFile = ".validation.json"
 )
 
 const (
 	DefaultWritersLimit  = 40
 	completionPercentage = 100
+	// checkpointMetadataDb is the value of the -checkpoint flag that saves
+	// migration progress in the internal metadata database.
+	checkpointMetadataDb = "metadata-db"
 )
 
 func metricsPopulation(ctx context.Context, driver string, conv *internal.Conv) {
@@ -206,6 +212,11 @@ func migrateData(ctx context.Context, migrationProjectId string, targetProfile p
 		bw  *writer.BatchWriter
 		err error
 	)
+	closeCheckpoint, err := openCheckpoint(ctx, conv, sourceProfile, targetProfile, dbURI, cmd.Checkpoint, cmd.filePrefix, cmd.Resume)
+	if err != nil {
+		return nil, err
+	}
+	defer closeCheckpoint()
 	if !sourceProfile.UseTargetSchema() {
 		err = validateExistingDb(ctx, conv.SpDialect, dbURI, adminClient, client, conv)
 		if err != nil {
@@ -241,14 +252,26 @@ func migrateSchemaAndData(ctx context.Context, migrationProjectId string, target
 	if err != nil {
 		return nil, err
 	}
-	tablesExistingOnSpanner, err := spA.GetTableNamesFromSpanner(ctx, conv.SpDialect, dbURI, client)
+	closeCheckpoint, err := openCheckpoint(ctx, conv, sourceProfile, targetProfile, dbURI, cmd.Checkpoint, cmd.filePrefix, cmd.Resume)
 	if err != nil {
 		return nil, err
 	}
-	err = spA.CreateOrUpdateDatabase(ctx, dbURI, sourceProfile.Driver, conv, sourceProfile.Config.ConfigType, tablesExistingOnSpanner)
-	if err != nil {
-		err = fmt.Errorf("can't create/update database: %v", err)
-		return nil, err
+	defer closeCheckpoint()
+	if conv.Checkpoint != nil && conv.Checkpoint.SchemaDone {
+		logger.Log.Info(fmt.Sprintf("Skipping schema migration: the schema of db %s was created by a previous run\n", dbURI))
+	} else {
+		tablesExistingOnSpanner, err := spA.GetTableNamesFromSpanner(ctx, conv.SpDialect, dbURI, client)
+		if err != nil {
+			return nil, err
+		}
+		err = spA.CreateOrUpdateDatabase(ctx, dbURI, sourceProfile.Driver, conv, sourceProfile.Config.ConfigType, tablesExistingOnSpanner)
+		if err != nil {
+			err = fmt.Errorf("can't create/update database: %v", err)
+			return nil, err
+		}
+		if conv.Checkpoint != nil {
+			conv.Checkpoint.SetSchemaDone()
+		}
 	}
 	metricsPopulation(ctx, sourceProfile.Driver, conv)
 	conv.Audit.Progress.UpdateProgress("Schema migration complete.", completionPercentage, internal.SchemaMigrationComplete)
@@ -270,4 +293,151 @@ func migrateSchemaAndData(ctx context.Context, migrationProjectId string, target
 	return bw, nil
 }
 
+// openCheckpoint sets conv.Checkpoint if the -checkpoint or -resume flags
+// ask for the progress of the data migration to dbURI to be saved. The
+// checkpoint is kept in a local file (by default <prefix>.checkpoint.json)
+// or, if location is "metadata-db", in the internal metadata database.
+// The returned function releases the checkpoint store.
+func openCheckpoint(ctx context.Context, conv *internal.Conv, sourceProfile profiles.SourceProfile, targetProfile profiles.TargetProfile, dbURI, location, filePrefix string, resume bool) (func(), error) {
+	if location == "" && !resume {
+		return func() {}, nil
+	}
+	if sourceProfile.Ty != profiles.SourceProfileTypeConnection && sourceProfile.Ty != profiles.SourceProfileTypeCloudSQL {
+		return nil, fmt.Errorf("checkpoints are only supported for migrations that connect directly to a source database")
+	}
+	var store internal.CheckpointStore
+	closeStore := func() {}
+	switch location {
+	case checkpointMetadataDb:
+		ms, err := helpers.NewMetadataCheckpointStore(ctx, targetProfile.Conn.Sp.Project, targetProfile.Conn.Sp.Instance, dbURI)
+		if err != nil {
+			return nil, fmt.Errorf("can't connect to the metadata database to save checkpoints: %v", err)
+		}
+		store, closeStore = ms, ms.Close
+	case "":
+		store = &internal.FileCheckpointStore{Path: filePrefix + checkpointFile}
+	default:
+		store = &internal.FileCheckpointStore{Path: location}
+	}
+	cp, err := internal.OpenCheckpoint(store, dbURI, resume)
+	if err != nil {
+		closeStore()
+		return nil, err
+	}
+	if cp.Resumed {
+		logger.Log.Info(fmt.Sprintf("Resuming data migration from the checkpoint in %s\n", store))
+	} else {
+		logger.Log.Info(fmt.Sprintf("Saving data migration progress to %s\n", store))
+	}
+	conv.Checkpoint = cp
+	return closeStore, nil
+}
 
+// openDeadLetters sets conv.DeadLetters if the -dead-letter-dir flag is
+// set, so that rows that fail conversion or writing are saved to dir. It
+// returns a function that closes the dead-letter files and lists them.
+func openDeadLetters(conv *internal.Conv, dir, format string, out io.Writer) (func(), error) {
+	if dir == "" {
+		return func() {}, nil
+	}
+	dw, err := internal.NewDeadLetterWriter(dir, format)
+	if err != nil {
+		return nil, err
+	}
+	conv.DeadLetters = dw
+	return func() {
+		files := dw.Files()
+		if err := dw.Close(); err != nil {
+			fmt.Fprintf(out, "Can't write dead-letter files in %s: %v\n", dir, err)
+		}
+		if len(files) == 0 {
+			return
+		}
+		fmt.Fprintf(out, "Wrote rejected rows to:\n")
+		for _, f := range files {
+			fmt.Fprintf(out, "  %s\n", f)
+		}
+	}, nil
+}
+
+// openSampler sets conv.Sampler if the -sample-percent or -sample-rows
+// flags ask for only a sample of the data to be migrated. It returns a
+// function that reports how many rows were left out of the sample.
+func openSampler(conv *internal.Conv, percent float64, rows int64, resume bool, out io.Writer) (func(), error) {
+	if percent == 0 && rows == 0 {
+		return func() {}, nil
+	}
+	if percent < 0 || percent > 100 {
+		return nil, fmt.Errorf("--sample-percent must be between 0 and 100, got %v", percent)
+	}
+	if rows < 0 {
+		return nil, fmt.Errorf("--sample-rows can't be negative, got %d", rows)
+	}
+	if resume {
+		// The rows kept by the interrupted run aren't known, so the rows
+		// referencing them couldn't be kept.
+		return nil, fmt.Errorf("a sample of the data can't be migrated with --resume")
+	}
+	if len(conv.SpSchema) == 0 {
+		return nil, fmt.Errorf("a sample of the data can only be migrated with a session file or source schema")
+	}
+	// Tables left out of the migration aren't sampled, so that they don't
+	// hold back the rows that reference them.
+	spSchema := ddl.Schema{}
+	for id, t := range conv.SpSchema {
+		if conv.TableSelection.Selected(conv.SrcSchema[id].Name) {
+			spSchema[id] = t
+		}
+	}
+	conv.Sampler = internal.NewSampler(spSchema, internal.SampleOptions{Percent: percent, MaxRows: rows})
+	return func() {
+		n := int64(0)
+		for _, c := range conv.Stats.SampledOut {
+			n += c
+		}
+		fmt.Fprintf(out, "Migrated a sample of the data: %d rows were left out.\n", n)
+	}, nil
+}
+
+// loadTableSelection returns the table selection of the -table-selection,
+// -tables and -exclude-tables flags, or nil if none of them is set. The
+// globs of -tables and -exclude-tables are comma separated and added to
+// those of the selection file. Tables and rows are only selected when
+// reading from a database, so the flags are ignored, with a warning, for
+// dump and CSV files.
+func loadTableSelection(file, include, exclude, driver string) (*internal.TableSelection, error) {
+	if file == "" && include == "" && exclude == "" {
+		return nil, nil
+	}
+	switch driver {
+	case constants.MYSQL, constants.POSTGRES, constants.SQLSERVER, constants.ORACLE, constants.CASSANDRA, constants.SPANNER, constants.SQLITE:
+	default:
+		logger.Log.Warn(fmt.Sprintf("Ignoring the table selection: tables can only be selected when migrating from a database, not from %s files", driver))
+		return nil, nil
+	}
+	ts := &internal.TableSelection{}
+	if file != "" {
+		var err error
+		if ts, err = internal.LoadTableSelection(file); err != nil {
+			return nil, err
+		}
+	}
+	splitGlobs := func(s string) []string {
+		var globs []string
+		for _, g := range strings.Split(s, ",") {
+			if g = strings.TrimSpace(g); g != "" {
+				globs = append(globs, g)
+			}
+		}
+		return globs
+	}
+	ts.Include = append(ts.Include, splitGlobs(include)...)
+	ts.Exclude = append(ts.Exclude, splitGlobs(exclude)...)
+	if err := ts.Validate(); err != nil {
+		return nil, err
+	}
+	if ts.HasPredicates() && (driver == constants.CASSANDRA || driver == constants.SPANNER) {
+		return nil, fmt.Errorf("row predicates in the table selection aren't supported for %s", driver)
+	}
+	return ts, nil
+}
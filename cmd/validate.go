@@ -0,0 +1,168 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/conversion"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/google/subcommands"
+	"go.uber.org/zap"
+)
+
+// ValidateCmd struct with flags.
+type ValidateCmd struct {
+	source        string
+	sourceProfile string
+	targetProfile string
+	sessionJSON   string
+	filePrefix    string
+	project       string
+	ranges        int
+	logLevel      string
+}
+
+// Name returns the name of operation.
+func (cmd *ValidateCmd) Name() string {
+	return "validate"
+}
+
+// Synopsis returns summary of operation.
+func (cmd *ValidateCmd) Synopsis() string {
+	return "compare the data of the source db with the data migrated to Spanner"
+}
+
+// Usage returns usage info of the command.
+func (cmd *ValidateCmd) Usage() string {
+	return fmt.Sprintf(`%v validate -session=[session_file] -source=[source] -source-profile="..." -target-profile="instance=my-instance,dbName=my-db"...
+
+Compare the rows of every table of the session in the source db with the
+rows migrated to Spanner. Source rows are converted as the data subcommand
+converts them, then row counts and hashes of ranges of primary keys are
+compared. The results are written to <prefix>.validation.json, and the
+command fails if a table doesn't match. The validate flags are:
+`, path.Base(os.Args[0]))
+}
+
+// SetFlags sets the flags.
+func (cmd *ValidateCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&cmd.source, "source", "", "Flag for specifying source DB, (e.g., `PostgreSQL`, `MySQL`)")
+	f.StringVar(&cmd.sourceProfile, "source-profile", "", "Flag for specifying connection profile for source database e.g., \"file=<path>,format=dump\"")
+	f.StringVar(&cmd.targetProfile, "target-profile", "", "Flag for specifying connection profile for target database e.g., \"instance=my-instance,dbName=my-db\"")
+	f.StringVar(&cmd.sessionJSON, "session", "", "Specifies the file we restore session state from")
+	f.StringVar(&cmd.filePrefix, "prefix", "", "File prefix for generated files")
+	f.StringVar(&cmd.project, "project", "", "Flag spcifying default project id for all the generated resources for the migration")
+	f.IntVar(&cmd.ranges, "ranges", conversion.DefaultValidationRanges, "Number of primary key ranges to compare the rows of each table in")
+	f.StringVar(&cmd.logLevel, "log-level", "DEBUG", "Configure the logging level for the command (INFO, DEBUG), defaults to DEBUG")
+}
+
+func (cmd *ValidateCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	var err error
+	defer func() {
+		if err != nil {
+			logger.Log.Fatal("FATAL error", zap.Error(err))
+		}
+	}()
+	err = logger.InitializeLogger(cmd.logLevel)
+	if err != nil {
+		logger.Log.Info(fmt.Sprint("Error initialising logger, did you specify a valid log-level? [DEBUG, INFO, WARN, ERROR, FATAL]", err))
+		return subcommands.ExitFailure
+	}
+	defer logger.Log.Sync()
+
+	if cmd.sessionJSON == "" {
+		err = fmt.Errorf("please specify the session file with --session")
+		return subcommands.ExitUsageError
+	}
+	sourceProfile, targetProfile, ioHelper, _, err := PrepareMigrationPrerequisites(cmd.sourceProfile, cmd.targetProfile, cmd.source, false)
+	if err != nil {
+		err = fmt.Errorf("error while preparing prerequisites for validation: %v", err)
+		return subcommands.ExitUsageError
+	}
+	if targetProfile.Conn.Sp.Dbname == "" {
+		err = fmt.Errorf("please specify the database to validate with dbName in --target-profile")
+		return subcommands.ExitUsageError
+	}
+	if cmd.project == "" {
+		getInfo := &utils.GetUtilInfoImpl{}
+		cmd.project, err = getInfo.GetProject()
+		if err != nil {
+			logger.Log.Error("Could not get project id from gcloud environment or --project flag. Either pass the projectId in the --project flag or configure in gcloud CLI using gcloud config set", zap.Error(err))
+			return subcommands.ExitUsageError
+		}
+	}
+	conv := internal.MakeConv()
+	err = conversion.ReadSessionFile(conv, cmd.sessionJSON)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
+	conv.Audit.SkipMetricsPopulation = true
+	adminClient, client, dbURI, err := CreateDatabaseClient(ctx, targetProfile, sourceProfile.Driver, targetProfile.Conn.Sp.Dbname, ioHelper)
+	if err != nil {
+		err = fmt.Errorf("can't create database client: %v", err)
+		return subcommands.ExitFailure
+	}
+	defer adminClient.Close()
+	defer client.Close()
+
+	report, err := conversion.ValidateData(ctx, cmd.project, sourceProfile, targetProfile, &ioHelper, conv, client, cmd.ranges)
+	if err != nil {
+		err = fmt.Errorf("can't validate database %s: %v", dbURI, err)
+		return subcommands.ExitFailure
+	}
+	if cmd.filePrefix == "" {
+		cmd.filePrefix = targetProfile.Conn.Sp.Dbname
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return subcommands.ExitFailure
+	}
+	if err = os.WriteFile(cmd.filePrefix+validationFile, data, 0644); err != nil {
+		err = fmt.Errorf("can't write validation report: %v", err)
+		return subcommands.ExitFailure
+	}
+	writeValidationSummary(ioHelper.Out, report)
+	fmt.Fprintf(ioHelper.Out, "Wrote validation report to file '%s'.\n", cmd.filePrefix+validationFile)
+	if n := report.Mismatches(); n > 0 {
+		fmt.Fprintf(ioHelper.Out, "%d of %d tables don't match.\n", n, len(report.Tables))
+		return subcommands.ExitFailure
+	}
+	return subcommands.ExitSuccess
+}
+
+// writeValidationSummary writes a line per table of report.
+func writeValidationSummary(out io.Writer, report *conversion.ValidationReport) {
+	for _, t := range report.Tables {
+		status := "OK"
+		switch {
+		case t.Error != "":
+			status = "ERROR: " + t.Error
+		case len(t.MismatchedRanges) > 0:
+			status = fmt.Sprintf("MISMATCH in %d of %d key ranges", len(t.MismatchedRanges), t.Ranges)
+		case !t.Match:
+			status = "MISMATCH"
+		}
+		fmt.Fprintf(out, "%s -> %s: %d source rows (%d bad), %d Spanner rows: %s\n", t.SrcTable, t.SpTable, t.SourceRows, t.BadRows, t.SpannerRows, status)
+	}
+}
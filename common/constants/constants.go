@@ -43,6 +43,29 @@ const (
 	// CASSANDRA is the driver name for Cassandra.
 	CASSANDRA string = "cassandra"
 
+	// SPANNER is the driver name for a Spanner database used as a source.
+	SPANNER string = "spanner"
+
+	// SQLITE is the driver name for a SQLite database file.
+	SQLITE string = "sqlite"
+
+	// MYDUMPER is the source-profile format of a directory written by
+	// mydumper. It is read by the mysqldump driver.
+	MYDUMPER string = "mydumper"
+
+	// BACPAC is the source-profile format of a BACPAC file exported from
+	// SQL Server.
+	BACPAC string = "bacpac"
+
+	// PARQUET is the import format of Parquet files.
+	PARQUET string = "parquet"
+
+	// AVRO is the import format of Avro object container files.
+	AVRO string = "avro"
+
+	// JSONL is the import format of newline-delimited JSON files.
+	JSONL string = "jsonl"
+
 	// Target db for which schema is being generated.
 	// This can be removed once the support for global flags is removed.
 	TargetSpanner              string = "spanner"
@@ -66,6 +89,11 @@ const (
 	GCS_SCHEME      string = "gs"
 	GCS_FILE_PREFIX string = "gs://"
 
+	// Schemes used for S3 and http(s) paths
+	S3_SCHEME    string = "s3"
+	HTTP_SCHEME  string = "http"
+	HTTPS_SCHEME string = "https"
+
 	// File upload prefix for dump and session load.
 	UPLOAD_FILE_DIR string = "upload-file"
 	// Rule types
@@ -73,6 +101,7 @@ const (
 	AddIndex             = "add_index"
 	EditColumnMaxLength  = "edit_column_max_length"
 	AddShardIdPrimaryKey = "add_shard_id_primary_key"
+	AddRowDeletionPolicy = "add_row_deletion_policy"
 	// bulk migration type
 	BULK_MIGRATION = "bulk"
 	// DMS migration type
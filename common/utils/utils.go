@@ -20,6 +20,8 @@ import (
 	"bufio"
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -27,6 +29,7 @@ import (
 	"net/url"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"reflect"
 	"sort"
@@ -42,10 +45,8 @@ import (
 	accessorclients "github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/clients"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/parse"
-	"github.com/GoogleCloudPlatform/spanner-migration-tool/expressions_api"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/file_reader"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
-	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
-	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/spanner"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
 	"golang.org/x/crypto/ssh/terminal"
 	"google.golang.org/api/iterator"
@@ -97,6 +98,8 @@ func NewIOStreams(driver string, dumpFile string) IOStreams {
 			bucketName := u.Host
 			filePath := u.Path[1:] // removes "/" from beginning of path
 			f, err = DownloadFromGCS(bucketName, filePath, "spanner-migration-tool.gcs.data")
+		} else if file_reader.IsObjectUri(dumpFile) {
+			f, err = DownloadFromUri(dumpFile, "spanner-migration-tool.remote.data")
 		} else {
 			f, err = os.Open(dumpFile)
 		}
@@ -164,8 +167,29 @@ func DownloadFromGCS(bucketName, filePath, tmpFile string) (*os.File, error) {
 	return tmpfile, nil
 }
 
-// PreloadGCSFiles downloads gcs files to tmp and updates the file paths in manifest with the local path.
-func PreloadGCSFiles(tables []ManifestTable) ([]ManifestTable, error) {
+// DownloadFromUri returns the s3 or http(s) file at uri, downloaded to
+// tmpFile in the tmp dir. Compressed files are downloaded as they are.
+func DownloadFromUri(uri, tmpFile string) (*os.File, error) {
+	tmpDir := filepath.Join(os.TempDir(), constants.SMT_TMP_DIR)
+	os.MkdirAll(tmpDir, os.ModePerm)
+	f, err := os.Create(filepath.Join(tmpDir, tmpFile))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create temporary file to save %s: %w", uri, err)
+	}
+	logger.Log.Info(fmt.Sprintf("\nDownloading file %s\n", uri))
+	if err := file_reader.DownloadObject(context.Background(), uri, f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("unable to download %s: %w", uri, err)
+	}
+	if _, err := f.Seek(0, 0); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// PreloadRemoteFiles downloads gcs, s3 and http(s) files to tmp and updates the file paths in manifest with the local path.
+func PreloadRemoteFiles(tables []ManifestTable) ([]ManifestTable, error) {
 	for i, table := range tables {
 		for j, filePath := range table.File_patterns {
 			u, err := url.Parse(filePath)
@@ -175,7 +199,7 @@ func PreloadGCSFiles(tables []ManifestTable) ([]ManifestTable, error) {
 			if u.Scheme == constants.GCS_SCHEME {
 				bucketName := u.Host
 				filePath := u.Path[1:] // removes "/" from beginning of path
-				tmpFile := strings.ReplaceAll(filePath, "/", ".")
+				tmpFile := remoteFileName(u)
 				// Files get downloaded to tmp dir.
 				fileLoc := filepath.Join(os.TempDir(), constants.SMT_TMP_DIR, tmpFile)
 				_, err = DownloadFromGCS(bucketName, filePath, tmpFile)
@@ -184,12 +208,33 @@ func PreloadGCSFiles(tables []ManifestTable) ([]ManifestTable, error) {
 				}
 				tables[i].File_patterns[j] = fileLoc
 				logger.Log.Info(fmt.Sprintf("Downloaded file: %s\n", fileLoc))
+			} else if file_reader.IsObjectUri(filePath) {
+				f, err := DownloadFromUri(filePath, remoteFileName(u))
+				if err != nil {
+					return nil, fmt.Errorf("cannot download file: %s for table %s: %v", filePath, table.Table_name, err)
+				}
+				f.Close()
+				tables[i].File_patterns[j] = f.Name()
+				logger.Log.Info(fmt.Sprintf("Downloaded file: %s\n", f.Name()))
 			}
 		}
 	}
 	return tables, nil
 }
 
+// remoteFileName returns the name of the tmp file the remote file at u is
+// downloaded to: its base name, which keeps its extension, prefixed with a
+// hash of u, so that files of different buckets or hosts, or whose paths
+// only differ in their separators, don't overwrite each other.
+func remoteFileName(u *url.URL) string {
+	hash := sha256.Sum256([]byte(u.String()))
+	name := path.Base(u.Path)
+	if name == "/" || name == "." {
+		return hex.EncodeToString(hash[:8])
+	}
+	return hex.EncodeToString(hash[:8]) + "-" + name
+}
+
 // GetProject returns the cloud project we should use by default to create resources.
 // Use environment variable GCLOUD_PROJECT if it is set.
 // Otherwise, use the default project returned from gcloud.
@@ -393,42 +438,6 @@ func GetBanner(now time.Time, db string) string {
 	return fmt.Sprintf("Generated at %s for db %s\n\n", now.Format("2006-01-02 15:04:05"), db)
 }
 
-// ReadSpannerSchema fills conv by querying Spanner infoschema treating Spanner as both the source and dest.
-func ReadSpannerSchema(ctx context.Context, conv *internal.Conv, client *sp.Client) error {
-	infoSchema := spanner.InfoSchemaImpl{Client: client, Ctx: ctx, SpDialect: conv.SpDialect}
-	processSchema := common.ProcessSchemaImpl{}
-	expressionVerificationAccessor, _ := expressions_api.NewExpressionVerificationAccessorImpl(ctx, conv.SpProjectId, conv.SpInstanceId)
-	ddlVerifier, err := expressions_api.NewDDLVerifierImpl(ctx, conv.SpProjectId, conv.SpInstanceId)
-	if err != nil {
-		return fmt.Errorf("error trying create ddl verifier: %v", err)
-	}
-	schemaToSpanner := common.SchemaToSpannerImpl{
-		DdlV:                           ddlVerifier,
-		ExpressionVerificationAccessor: expressionVerificationAccessor,
-	}
-	err = processSchema.ProcessSchema(conv, infoSchema, common.DefaultWorkers, internal.AdditionalSchemaAttributes{IsSharded: false}, &schemaToSpanner, &common.UtilsOrderImpl{}, &common.InfoSchemaImpl{})
-	if err != nil {
-		return fmt.Errorf("error trying to read and convert spanner schema: %v", err)
-	}
-	parentTables, err := infoSchema.GetInterleaveTables(conv.SpSchema)
-	if err != nil {
-		// We should ideally throw an error here as it could potentially cause a lot of failed writes.
-		// We raise an unexpected error for now to make it compatible with the integration tests.
-		// In Spanner Omni, the interleave_type column in not supported hence the query fails.
-		conv.Unexpected(fmt.Sprintf("error trying to fetch interleave table info from schema: %v", err))
-	}
-	// Assign parents if any.
-	for tableName, parentTable := range parentTables {
-		tableId, _ := internal.GetTableIdFromSpName(conv.SpSchema, tableName)
-		spTable := conv.SpSchema[tableId]
-		spTable.ParentTable.Id = parentTable.Id
-		spTable.ParentTable.OnDelete = parentTable.OnDelete
-		spTable.ParentTable.InterleaveType = parentTable.InterleaveType
-		conv.SpSchema[tableId] = spTable
-	}
-	return nil
-}
-
 // CompareSchema compares the spanner schema of two conv objects and returns specific error if they don't match
 func CompareSchema(sessionFileConv, actualSpannerConv *internal.Conv) error {
 	if sessionFileConv.SpDialect != actualSpannerConv.SpDialect {
@@ -2,10 +2,18 @@ package utils
 
 import (
 	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	sp "cloud.google.com/go/spanner"
 	database "cloud.google.com/go/spanner/admin/database/apiv1"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/api/option"
 )
@@ -152,3 +160,40 @@ func TestNewDatabaseAdminClient(t *testing.T) {
 		})
 	}
 }
+
+func TestPreloadRemoteFiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "SingerId\n1\n")
+	}))
+	defer server.Close()
+
+	tables, err := PreloadRemoteFiles([]ManifestTable{
+		{Table_name: "singers", File_patterns: []string{server.URL + "/exports/singers.csv", "local.csv"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "local.csv", tables[0].File_patterns[1])
+	assert.Equal(t, filepath.Join(os.TempDir(), constants.SMT_TMP_DIR), filepath.Dir(tables[0].File_patterns[0]))
+	assert.True(t, strings.HasSuffix(tables[0].File_patterns[0], "-singers.csv"), tables[0].File_patterns[0])
+	b, err := os.ReadFile(tables[0].File_patterns[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "SingerId\n1\n", string(b))
+	os.Remove(tables[0].File_patterns[0])
+
+	_, err = PreloadRemoteFiles([]ManifestTable{{Table_name: "singers", File_patterns: []string{"s3://"}}})
+	assert.Error(t, err)
+}
+
+func TestRemoteFileName(t *testing.T) {
+	name := func(uri string) string {
+		u, err := url.Parse(uri)
+		assert.NoError(t, err)
+		return remoteFileName(u)
+	}
+	assert.True(t, strings.HasSuffix(name("s3://exports/a/singers.csv.gz"), "-singers.csv.gz"))
+	// Files with the same path in different buckets, or whose paths only
+	// differ in their separators, are downloaded to different files.
+	assert.NotEqual(t, name("s3://exports/singers.csv"), name("s3://backup/singers.csv"))
+	assert.NotEqual(t, name("https://example.com/a/b.csv"), name("https://example.com/a.b.csv"))
+	assert.NotEqual(t, name("https://example.com/a.b/c.csv"), name("https://example.com/a/b.c.csv"))
+	assert.Equal(t, name("s3://exports/singers.csv"), name("s3://exports/singers.csv"))
+}
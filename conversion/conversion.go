@@ -57,14 +57,22 @@ func (ci *ConvImpl) SchemaConv(migrationProjectId string, sourceProfile profiles
 	var conv *internal.Conv
 	var err error
 	switch sourceProfile.Driver {
-	case constants.POSTGRES, constants.MYSQL, constants.SQLSERVER, constants.ORACLE, constants.CASSANDRA:
+	case constants.POSTGRES, constants.MYSQL, constants.SQLSERVER, constants.ORACLE, constants.CASSANDRA, constants.SPANNER, constants.SQLITE:
 		conv, err = schemaFromSource.schemaFromDatabase(migrationProjectId, sourceProfile, targetProfile, &GetInfoImpl{}, &common.ProcessSchemaImpl{})
 	case constants.PGDUMP, constants.MYSQLDUMP:
-		ddlVerifier, err := expressions_api.NewDDLVerifierImpl(context.Background(), targetProfile.Conn.Sp.Project, targetProfile.Conn.Sp.Instance)
+		ddlVerifier, verr := expressions_api.NewDDLVerifierImpl(context.Background(), targetProfile.Conn.Sp.Project, targetProfile.Conn.Sp.Instance)
+		if verr != nil {
+			fmt.Printf("Warning: failed to initialize expression verifier: %v\n", verr)
+		}
+		processDump := &ProcessDumpByDialectImpl{ExpressionVerificationAccessor: ddlVerifier.Expressions, DdlVerifier: ddlVerifier, PgArchivePath: pgArchivePath(sourceProfile)}
+		if sourceProfile.File.Format == constants.MYDUMPER {
+			conv, err = schemaFromSource.SchemaFromMydumper(targetProfile.Conn.Sp.Project, targetProfile.Conn.Sp.Instance, sourceProfile.File.Path, targetProfile.Conn.Sp.Dialect, ioHelper, processDump, targetProfile.DefaultIdentityOptions)
+		} else {
+			conv, err = schemaFromSource.SchemaFromDump(targetProfile.Conn.Sp.Project, targetProfile.Conn.Sp.Instance, sourceProfile.Driver, targetProfile.Conn.Sp.Dialect, ioHelper, processDump, targetProfile.DefaultIdentityOptions)
+		}
 		if err != nil {
-			fmt.Printf("Warning: failed to initialize expression verifier: %v\n", err)
+			return nil, err
 		}
-		conv, err = schemaFromSource.SchemaFromDump(targetProfile.Conn.Sp.Project, targetProfile.Conn.Sp.Instance, sourceProfile.Driver, targetProfile.Conn.Sp.Dialect, ioHelper, &ProcessDumpByDialectImpl{ExpressionVerificationAccessor: ddlVerifier.Expressions, DdlVerifier: ddlVerifier}, targetProfile.DefaultIdentityOptions)
 	default:
 		return nil, fmt.Errorf("schema conversion for driver %s not supported", sourceProfile.Driver)
 	}
@@ -77,20 +85,31 @@ func (ci *ConvImpl) SchemaConv(migrationProjectId string, sourceProfile profiles
 // DataConv performs the data conversion
 // The SourceProfile param provides the connection details to use the go SQL library.
 func (ci *ConvImpl) DataConv(ctx context.Context, migrationProjectId string, sourceProfile profiles.SourceProfile, targetProfile profiles.TargetProfile, ioHelper *utils.IOStreams, client *sp.Client, conv *internal.Conv, dataOnly bool, writeLimit int64, dataFromSource DataFromSourceInterface) (*writer.BatchWriter, error) {
+	if err := CheckDataSource(sourceProfile); err != nil {
+		return nil, err
+	}
 	config := writer.BatchWriterConfig{
 		BytesLimit: 100 * 1000 * 1000,
 		WriteLimit: writeLimit,
 		RetryLimit: 1000,
 		Verbose:    internal.Verbose(),
+		// Rows written by the run being resumed may be written again.
+		Upsert: conv.Checkpoint != nil && conv.Checkpoint.Resumed,
+	}
+	if conv.DeadLetters != nil {
+		config.DroppedRow = deadLetterSink(conv)
 	}
 	switch sourceProfile.Driver {
-	case constants.POSTGRES, constants.MYSQL, constants.SQLSERVER, constants.ORACLE:
+	case constants.POSTGRES, constants.MYSQL, constants.SQLSERVER, constants.ORACLE, constants.CASSANDRA, constants.SPANNER, constants.SQLITE:
 		return dataFromSource.dataFromDatabase(ctx, migrationProjectId, sourceProfile, targetProfile, config, conv, client, &GetInfoImpl{}, &DataFromDatabaseImpl{}, &SnapshotMigrationImpl{})
 	case constants.PGDUMP, constants.MYSQLDUMP:
 		if conv.SpSchema.CheckInterleaved() {
 			return nil, fmt.Errorf("spanner migration tool does not currently support data conversion from dump files\nif the schema contains interleaved tables. Suggest using direct access to source database\ni.e. using drivers postgres and mysql")
 		}
-		return dataFromSource.dataFromDump(sourceProfile.Driver, config, ioHelper, client, conv, dataOnly, &ProcessDumpByDialectImpl{}, &PopulateDataConvImpl{})
+		if sourceProfile.File.Format == constants.MYDUMPER {
+			return dataFromSource.dataFromMydumper(sourceProfile.File.Path, config, ioHelper, client, conv, &PopulateDataConvImpl{})
+		}
+		return dataFromSource.dataFromDump(sourceProfile.Driver, config, ioHelper, client, conv, dataOnly, &ProcessDumpByDialectImpl{PgArchivePath: pgArchivePath(sourceProfile)}, &PopulateDataConvImpl{})
 	case constants.CSV:
 		return dataFromSource.dataFromCSV(ctx, sourceProfile, targetProfile, config, conv, client, &PopulateDataConvImpl{}, &csv.CsvImpl{})
 	default:
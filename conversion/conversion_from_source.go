@@ -16,6 +16,7 @@ package conversion
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
 
@@ -28,6 +29,7 @@ import (
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/csv"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/mysql"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/writer"
 
@@ -36,15 +38,20 @@ import (
 type SchemaFromSourceInterface interface {
 	schemaFromDatabase(migrationProjectId string, sourceProfile profiles.SourceProfile, targetProfile profiles.TargetProfile, getInfo GetInfoInterface, processSchema common.ProcessSchemaInterface) (*internal.Conv, error)
 	SchemaFromDump(SpProjectId string, SpInstanceId string, driver string, spDialect string, ioHelper *utils.IOStreams, processDump ProcessDumpByDialectInterface, defaultIdentityOptions profiles.DefaultIdentityOptions) (*internal.Conv, error)
+	SchemaFromMydumper(SpProjectId string, SpInstanceId string, dir string, spDialect string, ioHelper *utils.IOStreams, processDump ProcessDumpByDialectInterface, defaultIdentityOptions profiles.DefaultIdentityOptions) (*internal.Conv, error)
 }
 
 type SchemaFromSourceImpl struct {
 	DdlVerifier expressions_api.DDLVerifier
+	// TableSelection restricts the tables whose schema is read from a
+	// source database, if set. It is kept in the conv returned.
+	TableSelection *internal.TableSelection
 }
 
 type DataFromSourceInterface interface {
 	dataFromDatabase(ctx context.Context, migrationProjectId string, sourceProfile profiles.SourceProfile, targetProfile profiles.TargetProfile, config writer.BatchWriterConfig, conv *internal.Conv, client *sp.Client, getInfo GetInfoInterface, dataFromDb DataFromDatabaseInterface, snapshotMigration SnapshotMigrationInterface) (*writer.BatchWriter, error)
 	dataFromDump(driver string, config writer.BatchWriterConfig, ioHelper *utils.IOStreams, client *sp.Client, conv *internal.Conv, dataOnly bool, processDump ProcessDumpByDialectInterface, populateDataConv PopulateDataConvInterface) (*writer.BatchWriter, error)
+	dataFromMydumper(dir string, config writer.BatchWriterConfig, ioHelper *utils.IOStreams, client *sp.Client, conv *internal.Conv, populateDataConv PopulateDataConvInterface) (*writer.BatchWriter, error)
 	dataFromCSV(ctx context.Context, sourceProfile profiles.SourceProfile, targetProfile profiles.TargetProfile, config writer.BatchWriterConfig, conv *internal.Conv, client *sp.Client, populateDataConv PopulateDataConvInterface, csv csv.CsvInterface) (*writer.BatchWriter, error)
 }
 
@@ -52,6 +59,7 @@ type DataFromSourceImpl struct{}
 
 func (sads *SchemaFromSourceImpl) schemaFromDatabase(migrationProjectId string, sourceProfile profiles.SourceProfile, targetProfile profiles.TargetProfile, getInfo GetInfoInterface, processSchema common.ProcessSchemaInterface) (*internal.Conv, error) {
 	conv := internal.MakeConv()
+	conv.TableSelection = sads.TableSelection
 	conv.SpDialect = targetProfile.Conn.Sp.Dialect
 	conv.SpProjectId = targetProfile.Conn.Sp.Project
 	conv.SpInstanceId = targetProfile.Conn.Sp.Instance
@@ -77,8 +85,11 @@ func (sads *SchemaFromSourceImpl) schemaFromDatabase(migrationProjectId string,
 				return conv, err
 			}
 		} else if sourceProfile.Config.ConfigType == constants.DMS_MIGRATION {
-			// TODO: Define the schema processing logic for DMS migrations here.
-			return conv, fmt.Errorf("dms based migrations are not implemented yet")
+			schemaSource := sourceProfile.Config.ShardConfigurationDMS.SchemaSource
+			infoSchema, err = getInfo.getInfoSchemaForShard(migrationProjectId, schemaSource, sourceProfile.Driver, targetProfile, &profiles.SourceProfileDialectImpl{}, &GetInfoImpl{})
+			if err != nil {
+				return conv, err
+			}
 		} else {
 			return conv, fmt.Errorf("unknown type of migration, please select one of bulk or dms")
 		}
@@ -94,6 +105,7 @@ func (sads *SchemaFromSourceImpl) schemaFromDatabase(migrationProjectId string,
 			return conv, err
 		}
 	}
+	defer closeInfoSchema(infoSchema)
 	additionalSchemaAttributes := internal.AdditionalSchemaAttributes{
 		IsSharded: isSharded,
 	}
@@ -125,8 +137,14 @@ func (sads *SchemaFromSourceImpl) SchemaFromDump(SpProjectId string, SpInstanceI
 		SkipRangeMax: defaultIdentityOptions.SkipRangeMax,
 		StartCounterWith: defaultIdentityOptions.StartCounterWith,
 	}
+	// n is the size of the file, which is compressed for a compressed dump,
+	// and the reader reports progress against compressed bytes.
 	p := internal.NewProgress(n, "Generating schema", internal.Verbose(), false, int(internal.SchemaCreationInProgress))
-	r := internal.NewReader(bufio.NewReader(f), p)
+	r, err := internal.NewCompressedReader(f, f.Name(), p)
+	if err != nil {
+		fmt.Fprintf(ioHelper.Out, "Failed to read the data file: %v", err)
+		return nil, fmt.Errorf("failed to read the data file: %w", err)
+	}
 	conv.SetSchemaMode() // Build schema and ignore data in dump.
 	conv.SetDataSink(nil)
 	err = processDump.ProcessDump(driver, conv, r)
@@ -138,6 +156,45 @@ func (sads *SchemaFromSourceImpl) SchemaFromDump(SpProjectId string, SpInstanceI
 	return conv, nil
 }
 
+// SchemaFromMydumper builds the schema from the schema files of the
+// mydumper directory dir. Rows are taken from the metadata file when it
+// has them, and counted in the data files otherwise.
+func (sads *SchemaFromSourceImpl) SchemaFromMydumper(SpProjectId string, SpInstanceId string, dir string, spDialect string, ioHelper *utils.IOStreams, processDump ProcessDumpByDialectInterface, defaultIdentityOptions profiles.DefaultIdentityOptions) (*internal.Conv, error) {
+	d, err := mysql.ReadMydumperDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	schemaDump, err := d.SchemaDump()
+	if err != nil {
+		return nil, err
+	}
+	ioHelper.BytesRead = d.Bytes
+	conv := internal.MakeConv()
+	conv.SpDialect = spDialect
+	conv.Source = constants.MYSQLDUMP
+	conv.SpProjectId = SpProjectId
+	conv.SpInstanceId = SpInstanceId
+	conv.DefaultIdentityOptions = ddl.IdentityOptions{
+		SkipRangeMin:     defaultIdentityOptions.SkipRangeMin,
+		SkipRangeMax:     defaultIdentityOptions.SkipRangeMax,
+		StartCounterWith: defaultIdentityOptions.StartCounterWith,
+	}
+	p := internal.NewProgress(int64(len(schemaDump)), "Generating schema", internal.Verbose(), false, int(internal.SchemaCreationInProgress))
+	r := internal.NewReader(bufio.NewReader(bytes.NewReader(schemaDump)), p)
+	conv.SetSchemaMode()
+	conv.SetDataSink(nil)
+	err = processDump.ProcessDump(constants.MYSQLDUMP, conv, r)
+	if err != nil {
+		fmt.Fprintf(ioHelper.Out, "Failed to parse the schema files: %v", err)
+		return nil, fmt.Errorf("failed to parse the schema files: %w", err)
+	}
+	p.Done()
+	if err := d.SetRowStats(conv, common.DefaultWorkers); err != nil {
+		return nil, fmt.Errorf("failed to count rows: %w", err)
+	}
+	return conv, nil
+}
+
 func (sads *DataFromSourceImpl) dataFromDump(driver string, config writer.BatchWriterConfig, ioHelper *utils.IOStreams, client *sp.Client, conv *internal.Conv, dataOnly bool, processDump ProcessDumpByDialectInterface, populateDataConv PopulateDataConvInterface) (*writer.BatchWriter, error) {
 	// TODO: refactor of the way we handle getSeekable
 	// to avoid the code duplication here
@@ -161,7 +218,10 @@ func (sads *DataFromSourceImpl) dataFromDump(driver string, config writer.BatchW
 	totalRows := conv.Rows()
 
 	conv.Audit.Progress = *internal.NewProgress(totalRows, "Writing data to Spanner", internal.Verbose(), false, int(internal.DataWriteInProgress))
-	r := internal.NewReader(bufio.NewReader(ioHelper.SeekableIn), nil)
+	r, err := internal.NewCompressedReader(ioHelper.SeekableIn, ioHelper.SeekableIn.Name(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the data file: %w", err)
+	}
 	batchWriter := populateDataConv.populateDataConv(conv, config, client)
 	processDump.ProcessDump(driver, conv, r)
 	batchWriter.Flush()
@@ -170,6 +230,27 @@ func (sads *DataFromSourceImpl) dataFromDump(driver string, config writer.BatchW
 	return batchWriter, nil
 }
 
+// dataFromMydumper migrates the rows of the data files of the mydumper
+// directory dir, processing the files in parallel.
+func (sads *DataFromSourceImpl) dataFromMydumper(dir string, config writer.BatchWriterConfig, ioHelper *utils.IOStreams, client *sp.Client, conv *internal.Conv, populateDataConv PopulateDataConvInterface) (*writer.BatchWriter, error) {
+	d, err := mysql.ReadMydumperDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	ioHelper.BytesRead = d.Bytes
+	totalRows := conv.Rows()
+
+	conv.Audit.Progress = *internal.NewProgress(totalRows, "Writing data to Spanner", internal.Verbose(), false, int(internal.DataWriteInProgress))
+	batchWriter := populateDataConv.populateDataConv(conv, config, client)
+	err = d.ProcessData(conv, common.DefaultWorkers)
+	batchWriter.Flush()
+	conv.Audit.Progress.Done()
+	if err != nil {
+		return nil, err
+	}
+	return batchWriter, nil
+}
+
 func (sads *DataFromSourceImpl) dataFromCSV(ctx context.Context, sourceProfile profiles.SourceProfile, targetProfile profiles.TargetProfile, config writer.BatchWriterConfig, conv *internal.Conv, client *sp.Client, populateDataConv PopulateDataConvInterface, csv csv.CsvInterface) (*writer.BatchWriter, error) {
 	if targetProfile.Conn.Sp.Dbname == "" {
 		return nil, fmt.Errorf("dbName is mandatory in target-profile for csv source")
@@ -185,7 +266,7 @@ func (sads *DataFromSourceImpl) dataFromCSV(ctx context.Context, sourceProfile p
 
 	delimiter := rune(delimiterStr[0])
 
-	err := utils.ReadSpannerSchema(ctx, conv, client)
+	err := ReadSpannerSchema(ctx, conv, client)
 	if err != nil {
 		return nil, fmt.Errorf("error trying to read and convert spanner schema: %v", err)
 	}
@@ -225,7 +306,7 @@ func (sads *DataFromSourceImpl) dataFromDatabase(ctx context.Context, migrationP
 		if sourceProfile.Config.ConfigType == constants.BULK_MIGRATION {
 			return dataFromDb.dataFromDatabaseForBulkMigration(migrationProjectId, sourceProfile, targetProfile, config, conv, client, getInfo, snapshotMigration)
 		} else if sourceProfile.Config.ConfigType == constants.DMS_MIGRATION {
-			return dataFromDb.dataFromDatabaseForDMSMigration()
+			return dataFromDb.dataFromDatabaseForDMSMigration(ctx, migrationProjectId, sourceProfile, targetProfile, config, conv, client, getInfo, snapshotMigration)
 		} else {
 			return nil, fmt.Errorf("configType should be one of 'bulk' or 'dms'")
 		}
@@ -244,7 +325,8 @@ func (sads *DataFromSourceImpl) dataFromDatabase(ctx context.Context, migrationP
 			}
 		}
 
+		defer closeInfoSchema(infoSchema)
 		//bulk migration for a single shard
-		return snapshotMigration.performSnapshotMigration(config, conv, client, infoSchema, internal.AdditionalDataAttributes{ShardId: ""}, &common.InfoSchemaImpl{}, &PopulateDataConvImpl{}), nil
+		return snapshotMigration.performSnapshotMigration(config, conv, client, infoSchema, internal.AdditionalDataAttributes{ShardId: ""}, &common.InfoSchemaImpl{}, &PopulateDataConvImpl{})
 	}
 }
@@ -87,10 +87,17 @@ func TestSchemaFromDatabase(t *testing.T) {
 			errorExpected:      true,
 		},
 		{
-			name:               "source profile config for dms migration",
+			name:               "successful source profile config for dms migration",
 			sourceProfile:      sourceProfileConfigDms,
 			getInfoError:       nil,
 			processSchemaError: nil,
+			errorExpected:      false,
+		},
+		{
+			name:               "source profile config for dms migration: get info error",
+			sourceProfile:      sourceProfileConfigDms,
+			getInfoError:       fmt.Errorf("error"),
+			processSchemaError: nil,
 			errorExpected:      true,
 		},
 		{
@@ -21,6 +21,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync/atomic"
 	"syscall"
@@ -36,6 +37,7 @@ import (
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/mysql"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/postgres"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/spanner"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/writer"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/proto"
@@ -48,6 +50,39 @@ type ProcessDumpByDialectInterface interface {
 type ProcessDumpByDialectImpl struct {
 	ExpressionVerificationAccessor expressions_api.ExpressionVerificationAccessor
 	DdlVerifier                    expressions_api.DDLVerifier
+	// PgArchivePath is the path of a local pg_dump archive in the custom or
+	// directory format, which is then read in parallel.
+	PgArchivePath string
+}
+
+// pgArchivePath returns the path of the dump file when it is a local
+// pg_dump archive in the custom or directory format, and "" otherwise.
+func pgArchivePath(sourceProfile profiles.SourceProfile) string {
+	if sourceProfile.Driver != constants.PGDUMP || !postgres.IsArchive(sourceProfile.File.Path) {
+		return ""
+	}
+	return sourceProfile.File.Path
+}
+
+// isBacpac returns true if the source is a BACPAC file exported from SQL
+// Server rather than a live database.
+func isBacpac(sourceProfile profiles.SourceProfile) bool {
+	return sourceProfile.Driver == constants.SQLSERVER && sourceProfile.Ty == profiles.SourceProfileTypeFile && sourceProfile.File.Format == constants.BACPAC
+}
+
+// isCqlSchemaFile returns true if the source is a file of CQL statements
+// describing a Cassandra keyspace rather than a live cluster.
+func isCqlSchemaFile(sourceProfile profiles.SourceProfile) bool {
+	return sourceProfile.Driver == constants.CASSANDRA && sourceProfile.Ty == profiles.SourceProfileTypeFile
+}
+
+// CheckDataSource returns an error if rows can't be read from the source,
+// such as a CQL schema file, which only describes the tables.
+func CheckDataSource(sourceProfile profiles.SourceProfile) error {
+	if isCqlSchemaFile(sourceProfile) {
+		return fmt.Errorf("a CQL schema file has no rows to migrate: migrate the data from the Cassandra cluster with a connection source profile")
+	}
+	return nil
 }
 
 type PopulateDataConvInterface interface {
@@ -94,7 +129,7 @@ func (pdd *ProcessDumpByDialectImpl) ProcessDump(driver string, conv *internal.C
 	case constants.MYSQLDUMP:
 		return common.ProcessDbDump(conv, r, mysql.DbDumpImpl{}, pdd.DdlVerifier, pdd.ExpressionVerificationAccessor)
 	case constants.PGDUMP:
-		return common.ProcessDbDump(conv, r, postgres.DbDumpImpl{}, pdd.DdlVerifier, pdd.ExpressionVerificationAccessor)
+		return common.ProcessDbDump(conv, r, postgres.DbDumpImpl{ArchivePath: pdd.PgArchivePath}, pdd.DdlVerifier, pdd.ExpressionVerificationAccessor)
 	default:
 		return fmt.Errorf("process dump for driver %s not supported", driver)
 	}
@@ -124,9 +159,18 @@ func (pdc *PopulateDataConvImpl) populateDataConv(conv *internal.Conv, config wr
 		conv.SetDataSink(
 			func(table string, cols []string, vals []interface{}) {
 				batchWriter.AddRow(table, cols, vals)
+				// Periodically flush so that the checkpoint can move forward
+				// within a table.
+				if conv.Checkpoint != nil && conv.Checkpoint.RowAdded(table, cols, vals) {
+					batchWriter.Flush()
+					conv.Checkpoint.Flushed()
+				}
 			})
 		conv.DataFlush = func() {
 			batchWriter.Flush()
+			if conv.Checkpoint != nil {
+				conv.Checkpoint.Flushed()
+			}
 		}
 	}
 
@@ -159,9 +203,14 @@ func ConnectionConfig(sourceProfile profiles.SourceProfile) (interface{}, error)
 		return profiles.GetSQLConnectionStr(sourceProfile), nil
 	case constants.ORACLE:
 		return profiles.GetSQLConnectionStr(sourceProfile), nil
+	case constants.SQLITE:
+		return sqliteConnectionStr(sourceProfile.File.Path)
 	// Returns an empty string as Cassandra connections are managed directly by the gocql session.	
 	case constants.CASSANDRA:
 		return "", nil
+	// Returns an empty string as the source Spanner client is created from the source profile.
+	case constants.SPANNER:
+		return "", nil
 	default:
 		return "", fmt.Errorf("driver %s not supported", sourceProfile.Driver)
 	}
@@ -186,4 +235,58 @@ func getDbNameFromSQLConnectionStr(driver, sqlConnectionStr string) string {
 	return ""
 }
 
+// sqliteConnectionStr returns the data source name that opens a SQLite
+// database file read-only, so that a mistyped path isn't silently
+// created as an empty database.
+func sqliteConnectionStr(path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("please specify the SQLite database file using -source-profile=\"file=<path>\"")
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("can't open SQLite database file: %w", err)
+	}
+	return "file:" + path + "?mode=ro", nil
+}
+
+// sqliteDbName returns the name of a SQLite database: its file name
+// without extension.
+func sqliteDbName(path string) string {
+	name := filepath.Base(path)
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
 
+// ReadSpannerSchema fills conv by querying Spanner infoschema treating Spanner as both the source and dest.
+func ReadSpannerSchema(ctx context.Context, conv *internal.Conv, client *sp.Client) error {
+	infoSchema := spanner.InfoSchemaImpl{Client: client, Ctx: ctx, SpDialect: conv.SpDialect}
+	processSchema := common.ProcessSchemaImpl{}
+	expressionVerificationAccessor, _ := expressions_api.NewExpressionVerificationAccessorImpl(ctx, conv.SpProjectId, conv.SpInstanceId)
+	ddlVerifier, err := expressions_api.NewDDLVerifierImpl(ctx, conv.SpProjectId, conv.SpInstanceId)
+	if err != nil {
+		return fmt.Errorf("error trying create ddl verifier: %v", err)
+	}
+	schemaToSpanner := common.SchemaToSpannerImpl{
+		DdlV:                           ddlVerifier,
+		ExpressionVerificationAccessor: expressionVerificationAccessor,
+	}
+	err = processSchema.ProcessSchema(conv, infoSchema, common.DefaultWorkers, internal.AdditionalSchemaAttributes{IsSharded: false}, &schemaToSpanner, &common.UtilsOrderImpl{}, &common.InfoSchemaImpl{})
+	if err != nil {
+		return fmt.Errorf("error trying to read and convert spanner schema: %v", err)
+	}
+	parentTables, err := infoSchema.GetInterleaveTables(conv.SpSchema)
+	if err != nil {
+		// We should ideally throw an error here as it could potentially cause a lot of failed writes.
+		// We raise an unexpected error for now to make it compatible with the integration tests.
+		// In Spanner Omni, the interleave_type column in not supported hence the query fails.
+		conv.Unexpected(fmt.Sprintf("error trying to fetch interleave table info from schema: %v", err))
+	}
+	// Assign parents if any.
+	for tableName, parentTable := range parentTables {
+		tableId, _ := internal.GetTableIdFromSpName(conv.SpSchema, tableName)
+		spTable := conv.SpSchema[tableId]
+		spTable.ParentTable.Id = parentTable.Id
+		spTable.ParentTable.OnDelete = parentTable.OnDelete
+		spTable.ParentTable.InterleaveType = parentTable.InterleaveType
+		conv.SpSchema[tableId] = spTable
+	}
+	return nil
+}
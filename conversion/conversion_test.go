@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	sp "cloud.google.com/go/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/utils"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
@@ -35,6 +36,7 @@ func TestSchemaConv(t *testing.T) {
 		output						interface{}
 		function  					string
 		errorExpected 				bool
+		format						string
 	}{
 		{
 			name: 					"postgres driver",
@@ -78,6 +80,14 @@ func TestSchemaConv(t *testing.T) {
 			function: 				"SchemaFromDump",
 			errorExpected: 			false,
 		},
+		{
+			name: 					"mydumper directory",
+			sourceProfileDriver: 	"mysqldump",
+			output: 				&internal.Conv{},
+			function: 				"SchemaFromMydumper",
+			errorExpected: 			false,
+			format: 				"mydumper",
+		},
 		{
 			name: 					"invalid driver",
 			sourceProfileDriver: 	"invalid",
@@ -91,7 +101,7 @@ func TestSchemaConv(t *testing.T) {
 		m := MockSchemaFromSource{}
 		m.On(tc.function, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tc.output, nil)
 		c := ConvImpl{}
-		_, err := c.SchemaConv("migration-project-id", profiles.SourceProfile{Driver: tc.sourceProfileDriver}, profiles.TargetProfile{}, &utils.IOStreams{}, &m)
+		_, err := c.SchemaConv("migration-project-id", profiles.SourceProfile{Driver: tc.sourceProfileDriver, File: profiles.SourceProfileFile{Format: tc.format}}, profiles.TargetProfile{}, &utils.IOStreams{}, &m)
 		assert.Equal(t, tc.errorExpected, err != nil, tc.name)
 		if err == nil {
 			m.AssertExpectations(t) 
@@ -107,6 +117,7 @@ func TestDataConv(t *testing.T) {
 		output						interface{}
 		function  					string
 		errorExpected 				bool
+		format						string
 	}{
 		{
 			name: 					"postgres driver",
@@ -136,6 +147,20 @@ func TestDataConv(t *testing.T) {
 			function: 				"dataFromDatabase",
 			errorExpected: 			false,
 		},
+		{
+			name: 					"cassandra driver",
+			sourceProfileDriver: 	"cassandra",
+			output: 				&writer.BatchWriter{},
+			function: 				"dataFromDatabase",
+			errorExpected: 			false,
+		},
+		{
+			name: 					"spanner driver",
+			sourceProfileDriver: 	"spanner",
+			output: 				&writer.BatchWriter{},
+			function: 				"dataFromDatabase",
+			errorExpected: 			false,
+		},
 		{
 			name: 					"pg dump driver",
 			sourceProfileDriver: 	"pg_dump",
@@ -150,6 +175,14 @@ func TestDataConv(t *testing.T) {
 			function: 				"dataFromDump",
 			errorExpected: 			false,
 		},
+		{
+			name: 					"mydumper directory",
+			sourceProfileDriver: 	"mysqldump",
+			output: 				&writer.BatchWriter{},
+			function: 				"dataFromMydumper",
+			errorExpected: 			false,
+			format: 				"mydumper",
+		},
 		{
 			name: 					"crv driver",
 			sourceProfileDriver: 	"csv",
@@ -171,10 +204,20 @@ func TestDataConv(t *testing.T) {
 		m := MockDataFromSource{}
 		m.On(tc.function, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tc.output, nil)
 		c := ConvImpl{}
-		_, err := c.DataConv(ctx, "migration-project-id", profiles.SourceProfile{Driver: tc.sourceProfileDriver}, profiles.TargetProfile{}, &utils.IOStreams{}, &sp.Client{}, &internal.Conv{}, true, int64(5), &m)
+		_, err := c.DataConv(ctx, "migration-project-id", profiles.SourceProfile{Driver: tc.sourceProfileDriver, File: profiles.SourceProfileFile{Format: tc.format}}, profiles.TargetProfile{}, &utils.IOStreams{}, &sp.Client{}, &internal.Conv{}, true, int64(5), &m)
 		assert.Equal(t, tc.errorExpected, err != nil, tc.name)
 		if err == nil {
 			m.AssertExpectations(t) 
 		}
 	}
+}
+
+func TestDataConv_CqlSchemaFile(t *testing.T) {
+	// A CQL schema file has no rows, so the data is never read.
+	m := MockDataFromSource{}
+	c := ConvImpl{}
+	sourceProfile := profiles.SourceProfile{Driver: constants.CASSANDRA, Ty: profiles.SourceProfileTypeFile, File: profiles.SourceProfileFile{Path: "schema.cql"}}
+	_, err := c.DataConv(context.Background(), "migration-project-id", sourceProfile, profiles.TargetProfile{}, &utils.IOStreams{}, &sp.Client{}, &internal.Conv{}, true, int64(5), &m)
+	assert.ErrorContains(t, err, "CQL schema file")
+	m.AssertNotCalled(t, "dataFromDatabase", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
\ No newline at end of file
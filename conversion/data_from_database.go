@@ -15,26 +15,99 @@
 package conversion
 
 import (
+	"context"
 	"fmt"
 
 	sp "cloud.google.com/go/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/mysql"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/writer"
 )
 
 type DataFromDatabaseInterface interface {
-	dataFromDatabaseForDMSMigration() (*writer.BatchWriter, error)
+	dataFromDatabaseForDMSMigration(ctx context.Context, migrationProjectId string, sourceProfile profiles.SourceProfile, targetProfile profiles.TargetProfile, config writer.BatchWriterConfig, conv *internal.Conv, client *sp.Client, gi GetInfoInterface, sm SnapshotMigrationInterface) (*writer.BatchWriter, error)
 	dataFromDatabaseForBulkMigration(migrationProjectId string, sourceProfile profiles.SourceProfile, targetProfile profiles.TargetProfile, config writer.BatchWriterConfig, conv *internal.Conv, client *sp.Client, gi GetInfoInterface, sm SnapshotMigrationInterface) (*writer.BatchWriter, error)
 }
 
 type DataFromDatabaseImpl struct{}
 
-// TODO: Define the data processing logic for DMS migrations here.
-func (dd *DataFromDatabaseImpl) dataFromDatabaseForDMSMigration() (*writer.BatchWriter, error) {
-	return nil, fmt.Errorf("dms configType is not implemented yet, please use 'bulk'")
+// 1. Load the binlog position saved by a previous run, if any.
+// 2. Otherwise, take a snapshot of the schema source (unless skipped) and
+//    save the binlog position it is consistent with (or the configured one).
+// 3. Replicate the changes in the binlog from that position, from the
+//    source or from binlog files, until ctx is done or the files are read.
+func (dd *DataFromDatabaseImpl) dataFromDatabaseForDMSMigration(ctx context.Context, migrationProjectId string, sourceProfile profiles.SourceProfile, targetProfile profiles.TargetProfile, config writer.BatchWriterConfig, conv *internal.Conv, client *sp.Client, gi GetInfoInterface, sm SnapshotMigrationInterface) (*writer.BatchWriter, error) {
+	if sourceProfile.Driver != constants.MYSQL {
+		return nil, fmt.Errorf("dms configType is only supported for MySQL databases")
+	}
+	dmsConfig := sourceProfile.Config.ShardConfigurationDMS
+	schemaSource := dmsConfig.SchemaSource
+	positionFile := dmsConfig.PositionFile
+	if positionFile == "" {
+		positionFile = schemaSource.DbName + ".binlog-position.json"
+	}
+	store := &internal.FileCheckpointStore{Path: positionFile}
+	pos, err := mysql.LoadBinlogPosition(store)
+	if err != nil {
+		return nil, err
+	}
+	additionalDataAttributes := internal.AdditionalDataAttributes{
+		ShardId: schemaSource.DataShardId,
+	}
+	var bw *writer.BatchWriter
+	if pos == nil && dmsConfig.BinlogDir == "" {
+		infoSchema, err := gi.getInfoSchemaForShard(migrationProjectId, schemaSource, sourceProfile.Driver, targetProfile, &profiles.SourceProfileDialectImpl{}, &GetInfoImpl{})
+		if err != nil {
+			return nil, err
+		}
+		start := mysql.BinlogPosition{File: dmsConfig.BinlogFile, Pos: dmsConfig.BinlogPos}
+		if start.File == "" {
+			// Changes made during the snapshot are replicated again, which
+			// is harmless since they are applied as upserts and deletes.
+			isi, ok := infoSchema.(mysql.InfoSchemaImpl)
+			if !ok {
+				return nil, fmt.Errorf("can't get the binlog position of %s, please set binlogFile and binlogPos", schemaSource.DbName)
+			}
+			start, err = mysql.GetBinlogPosition(isi.Db)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !dmsConfig.SkipSnapshot {
+			logger.Log.Info(fmt.Sprintf("Taking a snapshot of %s before replicating from binlog position %s\n", schemaSource.DbName, start))
+			bw, err = sm.performSnapshotMigration(config, conv, client, infoSchema, additionalDataAttributes, &common.InfoSchemaImpl{}, &PopulateDataConvImpl{})
+			if err != nil {
+				return nil, err
+			}
+		}
+		if err := mysql.SaveBinlogPosition(store, start); err != nil {
+			return nil, fmt.Errorf("can't save binlog position to %s: %v", store, err)
+		}
+		pos = &start
+	} else if pos == nil {
+		pos = &mysql.BinlogPosition{File: dmsConfig.BinlogFile, Pos: dmsConfig.BinlogPos}
+	}
+	if bw == nil {
+		bw = (&PopulateDataConvImpl{}).populateDataConv(conv, config, client)
+	}
+	src := mysql.BinlogSource{
+		Host:     schemaSource.Host,
+		Port:     schemaSource.Port,
+		User:     schemaSource.User,
+		Password: schemaSource.Password,
+		ServerId: dmsConfig.ServerId,
+		Dir:      dmsConfig.BinlogDir,
+	}
+	logger.Log.Info(fmt.Sprintf("Replicating changes of %s from binlog position %s\n", schemaSource.DbName, pos))
+	err = mysql.ReplicateBinlog(ctx, conv, schemaSource.DbName, src, *pos, store, client, additionalDataAttributes)
+	if err != nil && err != context.Canceled {
+		return nil, err
+	}
+	return bw, nil
 }
 
 
@@ -55,7 +128,10 @@ func (dd *DataFromDatabaseImpl) dataFromDatabaseForBulkMigration(migrationProjec
 		additionalDataAttributes := internal.AdditionalDataAttributes{
 			ShardId: dataShard.DataShardId,
 		}
-		bw = sm.performSnapshotMigration(config, conv, client, infoSchema, additionalDataAttributes, &common.InfoSchemaImpl{}, &PopulateDataConvImpl{})
+		bw, err = sm.performSnapshotMigration(config, conv, client, infoSchema, additionalDataAttributes, &common.InfoSchemaImpl{}, &PopulateDataConvImpl{})
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return bw, nil
@@ -18,6 +18,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"io"
 	"net"
 	"strings"
 
@@ -31,6 +32,8 @@ import (
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/mysql"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/oracle"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/postgres"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/sqlite"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/sqlserver"
 	mysqldriver "github.com/go-sql-driver/mysql"
 	"github.com/jackc/pgx/v5"
@@ -160,6 +163,27 @@ func (gi *GetInfoImpl) GetInfoSchemaFromCloudSQL(migrationProjectId string, sour
 }
 
 func (gi *GetInfoImpl) GetInfoSchema(migrationProjectId string, sourceProfile profiles.SourceProfile, targetProfile profiles.TargetProfile) (common.InfoSchema, error) {
+	if isBacpac(sourceProfile) {
+		bacpac, err := sqlserver.OpenBacpac(sourceProfile.File.Path)
+		if err != nil {
+			return nil, err
+		}
+		return sqlserver.BacpacInfoSchemaImpl{Bacpac: bacpac}, nil
+	}
+	if isCqlSchemaFile(sourceProfile) {
+		// Only the schema is read from the file: there is no client to read
+		// rows with.
+		ksMetadata, err := cassandra.ReadCqlSchema(context.Background(), sourceProfile.File.Path)
+		if err != nil {
+			return nil, err
+		}
+		sourceProfile.Conn.Cassandra.Keyspace = ksMetadata.Keyspace
+		return cassandra.InfoSchemaImpl{
+			KeyspaceMetadata: ksMetadata,
+			SourceProfile:    sourceProfile,
+			TargetProfile:    targetProfile,
+		}, nil
+	}
 	connectionConfig, err := ConnectionConfig(sourceProfile)
 	if err != nil {
 		return nil, err
@@ -218,17 +242,45 @@ func (gi *GetInfoImpl) GetInfoSchema(migrationProjectId string, sourceProfile pr
 			return nil, fmt.Errorf("failed to connect to source database: %w", err)
 		}
 		return oracle.InfoSchemaImpl{DbName: strings.ToUpper(dbName), Db: db, MigrationProjectId: migrationProjectId, SourceProfile: sourceProfile, TargetProfile: targetProfile}, nil
+	case constants.SQLITE:
+		db, err := sql.Open(driver, connectionConfig.(string))
+		if err != nil {
+			return nil, err
+		}
+		if err = db.Ping(); err != nil {
+			return nil, fmt.Errorf("failed to open source database file: %w", err)
+		}
+		return sqlite.InfoSchemaImpl{DbName: sqliteDbName(sourceProfile.File.Path), Db: db}, nil
 	case constants.CASSANDRA:
-		_, ksMetadata, err := ca.NewCassandraAccessor(sourceProfile)
+		accessor, ksMetadata, err := ca.NewCassandraAccessor(sourceProfile)
 		if err != nil {
 			return nil, err
 		}
 		return cassandra.InfoSchemaImpl{
 			KeyspaceMetadata: ksMetadata,
+			Client:           accessor.GetClient(),
 			SourceProfile:    sourceProfile,
 			TargetProfile:    targetProfile,
 		}, nil
+	case constants.SPANNER:
+		spConn := sourceProfile.Conn.Spanner
+		dbURI := fmt.Sprintf("projects/%s/instances/%s/databases/%s", spConn.Project, spConn.Instance, spConn.Db)
+		infoSchema, err := spanner.NewInfoSchemaImplForSource(context.Background(), dbURI)
+		if err != nil {
+			return nil, err
+		}
+		infoSchema.SourceProfile = sourceProfile
+		infoSchema.TargetProfile = targetProfile
+		return *infoSchema, nil
 	default:
 		return nil, fmt.Errorf("driver %s not supported", driver)
 	}
 }
+
+// closeInfoSchema releases the connection of an InfoSchema that holds one
+// open until it is closed, such as a Spanner source database.
+func closeInfoSchema(infoSchema common.InfoSchema) {
+	if c, ok := infoSchema.(io.Closer); ok {
+		c.Close()
+	}
+}
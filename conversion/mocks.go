@@ -70,6 +70,11 @@ func (msads *MockSchemaFromSource) SchemaFromDump(SpProjectId string, SpInstance
 	return args.Get(0).(*internal.Conv), args.Error(1)
 }
 
+func (msads *MockSchemaFromSource) SchemaFromMydumper(SpProjectId string, SpInstanceId string, dir string, spDialect string, ioHelper *utils.IOStreams, processDump ProcessDumpByDialectInterface, defaultIdentityOptions profiles.DefaultIdentityOptions) (*internal.Conv, error) {
+	args := msads.Called(dir, spDialect, ioHelper, processDump)
+	return args.Get(0).(*internal.Conv), args.Error(1)
+}
+
 type MockDataFromSource struct {
 	mock.Mock
 }
@@ -82,6 +87,10 @@ func (msads *MockDataFromSource) dataFromDump(driver string, config writer.Batch
 	args := msads.Called(driver, config, ioHelper, client, conv, dataOnly, processDump, populateDataConv)
 	return args.Get(0).(*writer.BatchWriter), args.Error(1)
 }
+func (msads *MockDataFromSource) dataFromMydumper(dir string, config writer.BatchWriterConfig, ioHelper *utils.IOStreams, client *sp.Client, conv *internal.Conv, populateDataConv PopulateDataConvInterface) (*writer.BatchWriter, error) {
+	args := msads.Called(dir, config, ioHelper, client, conv, populateDataConv)
+	return args.Get(0).(*writer.BatchWriter), args.Error(1)
+}
 func (msads *MockDataFromSource) dataFromCSV(ctx context.Context, sourceProfile profiles.SourceProfile, targetProfile profiles.TargetProfile, config writer.BatchWriterConfig, conv *internal.Conv, client *sp.Client, pdc PopulateDataConvInterface, csv csv.CsvInterface) (*writer.BatchWriter, error) {
 	args := msads.Called(ctx, sourceProfile, targetProfile, config, conv, client, pdc, csv)
 	return args.Get(0).(*writer.BatchWriter), args.Error(1)
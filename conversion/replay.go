@@ -0,0 +1,195 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	sp "cloud.google.com/go/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/csv"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/mysql"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/oracle"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/postgres"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/sqlite"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/sqlserver"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/writer"
+)
+
+// deadLetterSink returns a BatchWriter DroppedRow callback that saves
+// dropped rows to conv.DeadLetters.
+func deadLetterSink(conv *internal.Conv) func(table string, cols []string, vals []interface{}, err error) {
+	return func(table string, cols []string, vals []interface{}, err error) {
+		if dlErr := conv.DeadLetters.WriteFailed(conv, table, cols, vals, err); dlErr != nil {
+			logger.Log.Warn(fmt.Sprintf("Couldn't save rejected row of table %s: %v", table, dlErr))
+		}
+	}
+}
+
+// ReplayDeadLetters converts and writes rows saved by a
+// internal.DeadLetterWriter again, using the schema mapping in conv (which
+// is typically read from a session file that has been fixed since the rows
+// were rejected). Rows that fail again are saved to conv.DeadLetters, if
+// set.
+//
+// Rows rejected at the convert stage are converted from their source
+// values, as for the source database conv was created for (MySQL,
+// PostgreSQL, SQL Server or Oracle). Rows rejected at the write stage are
+// converted from the values originally sent to Spanner, so they can be
+// replayed whatever the source database.
+func ReplayDeadLetters(conv *internal.Conv, rows []internal.DeadLetter, config writer.BatchWriterConfig, client *sp.Client) *writer.BatchWriter {
+	if conv.DeadLetters != nil {
+		config.DroppedRow = deadLetterSink(conv)
+	}
+	pdc := &PopulateDataConvImpl{}
+	bw := pdc.populateDataConv(conv, config, client)
+	for _, dl := range rows {
+		conv.StatsAddRow(dl.SrcTable, conv.DataMode())
+		var err error
+		switch dl.Stage {
+		case internal.DeadLetterStageConvert:
+			err = replayConvert(conv, dl)
+		case internal.DeadLetterStageWrite:
+			err = replayWrite(conv, dl)
+		default:
+			err = fmt.Errorf("unknown stage %q", dl.Stage)
+		}
+		if err != nil {
+			conv.Unexpected(fmt.Sprintf("Can't replay row of table %s: %s", dl.SrcTable, err))
+			conv.StatsAddBadRow(dl.SrcTable, conv.DataMode())
+			if conv.DeadLetters != nil {
+				dl.Error = err.Error()
+				if dlErr := conv.DeadLetters.Write(dl); dlErr != nil {
+					logger.Log.Warn(fmt.Sprintf("Couldn't save rejected row of table %s: %v", dl.SrcTable, dlErr))
+				}
+			}
+		}
+	}
+	bw.Flush()
+	return bw
+}
+
+// replayConvert converts a row rejected at the convert stage from its
+// source values. Conversion errors are handled by ProcessDataRow.
+func replayConvert(conv *internal.Conv, dl internal.DeadLetter) error {
+	tableId, err := internal.GetTableIdFromSrcName(conv.SrcSchema, dl.SrcTable)
+	if err != nil {
+		return err
+	}
+	srcSchema, spSchema := conv.SrcSchema[tableId], conv.SpSchema[tableId]
+	colNameIdMap := internal.GetSrcColNameIdMap(srcSchema)
+	var srcColIds []string
+	for _, c := range dl.SrcCols {
+		colId, ok := colNameIdMap[c]
+		if !ok {
+			return fmt.Errorf("column id not found for source-db column %s", c)
+		}
+		srcColIds = append(srcColIds, colId)
+	}
+	colIds := common.IntersectionOfTwoStringSlices(spSchema.ColIds, srcColIds)
+	vals, err := common.PrepareValues(conv, tableId, colNameIdMap, colIds, dl.SrcCols, dl.Values)
+	if err != nil {
+		return err
+	}
+	switch conv.Source {
+	case constants.MYSQL, constants.MYSQLDUMP:
+		mysql.ProcessDataRow(conv, tableId, colIds, srcSchema, spSchema, vals, internal.AdditionalDataAttributes{})
+	case constants.POSTGRES, constants.PGDUMP:
+		postgres.ProcessDataRow(conv, tableId, colIds, vals)
+	case constants.SQLSERVER:
+		sqlserver.ProcessDataRow(conv, tableId, colIds, srcSchema, spSchema, vals)
+	case constants.ORACLE:
+		oracle.ProcessDataRow(conv, tableId, colIds, srcSchema, spSchema, vals)
+	case constants.SQLITE:
+		sqlite.ProcessDataRow(conv, tableId, colIds, srcSchema, spSchema, vals)
+	default:
+		return fmt.Errorf("replaying rows that failed conversion is not supported for source %q", conv.Source)
+	}
+	return nil
+}
+
+// replayWrite converts a row rejected at the write stage from the values
+// that were sent to Spanner, using the Spanner column types in conv.
+func replayWrite(conv *internal.Conv, dl internal.DeadLetter) error {
+	var tableId string
+	var err error
+	if dl.SrcTable != "" {
+		tableId, err = internal.GetTableIdFromSrcName(conv.SrcSchema, dl.SrcTable)
+	} else {
+		tableId, err = internal.GetTableIdFromSpName(conv.SpSchema, dl.SpTable)
+	}
+	if err != nil {
+		return err
+	}
+	srcSchema, spSchema := conv.SrcSchema[tableId], conv.SpSchema[tableId]
+	var cols []string
+	var vals []interface{}
+	for i, v := range dl.Values {
+		var colId string
+		if i < len(dl.SrcCols) && dl.SrcCols[i] != "" {
+			colId, err = internal.GetColIdFromSrcName(srcSchema.ColDefs, dl.SrcCols[i])
+		} else if i < len(dl.SpCols) {
+			colId, err = internal.GetColIdFromSpName(spSchema.ColDefs, dl.SpCols[i])
+		} else {
+			err = fmt.Errorf("no column for value %d", i)
+		}
+		if err != nil {
+			return err
+		}
+		colDef, ok := spSchema.ColDefs[colId]
+		if !ok {
+			// The column has been dropped from the Spanner schema.
+			continue
+		}
+		x, err := csv.ConvertValue(conv.SpDialect, colDef.T, v)
+		if err == nil && colDef.T.Name == ddl.Bytes {
+			x, err = decodeBytes(x)
+		}
+		if err != nil {
+			return fmt.Errorf("can't convert value of column %s: %v", colDef.Name, err)
+		}
+		cols = append(cols, colDef.Name)
+		vals = append(vals, x)
+	}
+	conv.WriteRow(srcSchema.Name, spSchema.Name, cols, vals)
+	return nil
+}
+
+// decodeBytes decodes the base64-encoded BYTES values saved by
+// internal.DeadLetterWriter, once converted by csv.ConvertValue.
+func decodeBytes(x interface{}) (interface{}, error) {
+	switch b := x.(type) {
+	case []byte:
+		return base64.StdEncoding.DecodeString(string(b))
+	case [][]byte:
+		r := make([][]byte, len(b))
+		for i := range b {
+			if b[i] == nil {
+				continue
+			}
+			var err error
+			if r[i], err = base64.StdEncoding.DecodeString(string(b[i])); err != nil {
+				return nil, err
+			}
+		}
+		return r, nil
+	}
+	return x, nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"path/filepath"
+	"testing"
+
+	sp "cloud.google.com/go/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/writer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayDeadLetters(t *testing.T) {
+	conv := internal.MakeConv()
+	conv.Source = constants.MYSQL
+	conv.Audit.DryRun = true
+	conv.SrcSchema["t1"] = schema.Table{
+		Id:     "t1",
+		Name:   "src_t",
+		ColIds: []string{"c1", "c2"},
+		ColDefs: map[string]schema.Column{
+			"c1": {Id: "c1", Name: "id", Type: schema.Type{Name: "bigint"}},
+			"c2": {Id: "c2", Name: "note", Type: schema.Type{Name: "varchar"}},
+		},
+	}
+	conv.SpSchema["t1"] = ddl.CreateTable{
+		Id:     "t1",
+		Name:   "sp_t",
+		ColIds: []string{"c1", "c2"},
+		ColDefs: map[string]ddl.ColumnDef{
+			"c1": {Id: "c1", Name: "Id", T: ddl.Type{Name: ddl.Int64}},
+			"c2": {Id: "c2", Name: "Note", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+		},
+	}
+	dir := t.TempDir()
+	dw, err := internal.NewDeadLetterWriter(dir, internal.DeadLetterFormatJSONL)
+	assert.Nil(t, err)
+	conv.DeadLetters = dw
+
+	convertRow := func(id string) internal.DeadLetter {
+		return internal.DeadLetter{Stage: internal.DeadLetterStageConvert, SrcTable: "src_t", SrcCols: []string{"id", "note"}, Values: []string{id, "n"}}
+	}
+	writeRow := func(id string) internal.DeadLetter {
+		return internal.DeadLetter{Stage: internal.DeadLetterStageWrite, SrcTable: "src_t", SrcCols: []string{"id", ""}, SpTable: "sp_t", SpCols: []string{"Id", "Note"}, Values: []string{id, "n"}}
+	}
+	ReplayDeadLetters(conv, []internal.DeadLetter{convertRow("1"), convertRow("x"), writeRow("2"), writeRow("y")}, writer.BatchWriterConfig{WriteLimit: 1}, nil)
+	assert.Nil(t, dw.Close())
+
+	assert.Equal(t, int64(4), conv.Stats.Rows["src_t"])
+	assert.Equal(t, int64(2), conv.Stats.GoodRows["src_t"])
+	assert.Equal(t, int64(2), conv.Stats.BadRows["src_t"])
+	rows, err := internal.ReadDeadLetters(filepath.Join(dir, "sp_t.jsonl"))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, internal.DeadLetterStageConvert, rows[0].Stage)
+	assert.Equal(t, []string{"x", "n"}, rows[0].Values)
+	assert.Equal(t, internal.DeadLetterStageWrite, rows[1].Stage)
+	assert.Equal(t, []string{"y", "n"}, rows[1].Values)
+	assert.Contains(t, rows[1].Error, "can't convert value of column Id")
+}
+
+func TestDecodeBytes(t *testing.T) {
+	b, err := decodeBytes([]byte("eHl6"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("xyz"), b)
+	b, err = decodeBytes([][]byte{[]byte("YQ=="), nil})
+	assert.Nil(t, err)
+	assert.Equal(t, [][]byte{[]byte("a"), nil}, b)
+	_, err = decodeBytes([]byte("not base64!"))
+	assert.NotNil(t, err)
+	b, err = decodeBytes([]sp.NullString{})
+	assert.Nil(t, err)
+	assert.Equal(t, []sp.NullString{}, b)
+}
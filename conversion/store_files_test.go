@@ -20,6 +20,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
@@ -136,6 +137,103 @@ func TestReadSessionFile(t *testing.T) {
 	}
 }
 
+func TestReadSessionFile_RowDeletionPolicy(t *testing.T) {
+	makeConv := func(colType string) *internal.Conv {
+		conv := internal.MakeConv()
+		conv.SpSchema = map[string]ddl.CreateTable{
+			"t1": {
+				Name:   "events",
+				ColIds: []string{"c1", "c2"},
+				ColDefs: map[string]ddl.ColumnDef{
+					"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}, NotNull: true},
+					"c2": {Name: "created_at", Id: "c2", T: ddl.Type{Name: colType}},
+				},
+				PrimaryKeys:       []ddl.IndexKey{{ColId: "c1", Order: 1}},
+				RowDeletionPolicy: ddl.RowDeletionPolicy{ColId: "c2", NumDays: 30},
+				Id:                "t1",
+			},
+		}
+		return conv
+	}
+	testCases := []struct {
+		name        string
+		colType     string
+		expectError bool
+	}{
+		{name: "row deletion policy on timestamp column", colType: ddl.Timestamp, expectError: false},
+		{name: "row deletion policy on string column", colType: ddl.String, expectError: true},
+	}
+	for _, tc := range testCases {
+		sessionJSON := filepath.Join(t.TempDir(), "session.json")
+		b, err := json.Marshal(makeConv(tc.colType))
+		assert.NoError(t, err, tc.name)
+		assert.NoError(t, os.WriteFile(sessionJSON, b, 0644), tc.name)
+		conv := internal.MakeConv()
+		err = ReadSessionFile(conv, sessionJSON)
+		assert.Equal(t, tc.expectError, err != nil, tc.name)
+		if !tc.expectError {
+			assert.Equal(t, ddl.RowDeletionPolicy{ColId: "c2", NumDays: 30}, conv.SpSchema["t1"].RowDeletionPolicy, tc.name)
+		}
+	}
+}
+
+func TestReadSessionFile_InterleavedIndex(t *testing.T) {
+	makeConv := func(interleaveTableId string) *internal.Conv {
+		conv := internal.MakeConv()
+		conv.SpSchema = map[string]ddl.CreateTable{
+			"t1": {
+				Name:        "singers",
+				ColIds:      []string{"c1"},
+				ColDefs:     map[string]ddl.ColumnDef{"c1": {Name: "singer_id", Id: "c1", T: ddl.Type{Name: ddl.Int64}, NotNull: true}},
+				PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}},
+				Id:          "t1",
+			},
+			"t2": {
+				Name:   "albums",
+				ColIds: []string{"c2", "c3", "c4"},
+				ColDefs: map[string]ddl.ColumnDef{
+					"c2": {Name: "singer_id", Id: "c2", T: ddl.Type{Name: ddl.Int64}, NotNull: true},
+					"c3": {Name: "album_id", Id: "c3", T: ddl.Type{Name: ddl.Int64}, NotNull: true},
+					"c4": {Name: "title", Id: "c4", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+				},
+				PrimaryKeys: []ddl.IndexKey{{ColId: "c2", Order: 1}, {ColId: "c3", Order: 2}},
+				ParentTable: ddl.InterleavedParent{Id: "t1", OnDelete: constants.FK_CASCADE, InterleaveType: "IN PARENT"},
+				Indexes: []ddl.CreateIndex{{
+					Name:              "albums_by_title",
+					TableId:           "t2",
+					Keys:              []ddl.IndexKey{{ColId: "c2", Order: 1}, {ColId: "c4", Order: 2}},
+					Id:                "i1",
+					NullFiltered:      true,
+					InterleaveTableId: interleaveTableId,
+				}},
+				Id: "t2",
+			},
+		}
+		return conv
+	}
+	testCases := []struct {
+		name              string
+		interleaveTableId string
+		expectError       bool
+	}{
+		{name: "index interleaved in parent table", interleaveTableId: "t1", expectError: false},
+		{name: "index interleaved in its own table", interleaveTableId: "t2", expectError: true},
+	}
+	for _, tc := range testCases {
+		sessionJSON := filepath.Join(t.TempDir(), "session.json")
+		b, err := json.Marshal(makeConv(tc.interleaveTableId))
+		assert.NoError(t, err, tc.name)
+		assert.NoError(t, os.WriteFile(sessionJSON, b, 0644), tc.name)
+		conv := internal.MakeConv()
+		err = ReadSessionFile(conv, sessionJSON)
+		assert.Equal(t, tc.expectError, err != nil, tc.name)
+		if !tc.expectError {
+			assert.True(t, conv.SpSchema["t2"].Indexes[0].NullFiltered, tc.name)
+			assert.Equal(t, "t1", conv.SpSchema["t2"].Indexes[0].InterleaveTableId, tc.name)
+		}
+	}
+}
+
 func TestWriteOverridesFile(t *testing.T) {
 	// Create a temporary directory for test files
 	tempDir := t.TempDir()
@@ -0,0 +1,699 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	sp "cloud.google.com/go/spanner"
+	sppb "cloud.google.com/go/spanner/apiv1/spannerpb"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/csv"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/writer"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// DefaultValidationRanges is the default number of key ranges that the rows
+// of a table are split into for validation.
+const DefaultValidationRanges = 64
+
+// validationPageSize is the number of rows of a Spanner table read at a
+// time for validation.
+const validationPageSize = 10000
+
+// ValidationReport is the result of comparing the rows of a source database
+// with the rows migrated to Spanner.
+type ValidationReport struct {
+	Tables []TableValidation `json:"tables"`
+}
+
+// Mismatches returns the number of tables that don't match.
+func (r *ValidationReport) Mismatches() int {
+	n := 0
+	for _, t := range r.Tables {
+		if !t.Match {
+			n++
+		}
+	}
+	return n
+}
+
+// TableValidation compares the rows of a source table with the rows of the
+// Spanner table it is migrated to. SourceRows counts the source rows that
+// were converted, and BadRows the ones that failed conversion. Rows are
+// split into ranges of their Spanner primary key, and MismatchedRanges
+// lists the ranges whose row counts or hashes differ.
+type TableValidation struct {
+	SrcTable         string               `json:"srcTable"`
+	SpTable          string               `json:"spTable"`
+	SourceRows       int64                `json:"sourceRows"`
+	BadRows          int64                `json:"badRows"`
+	SpannerRows      int64                `json:"spannerRows"`
+	Ranges           int                  `json:"ranges"`
+	Match            bool                 `json:"match"`
+	MismatchedRanges []KeyRangeValidation `json:"mismatchedRanges,omitempty"`
+	Error            string               `json:"error,omitempty"`
+}
+
+// KeyRangeValidation compares a range of primary keys, from Start
+// (inclusive) to End (exclusive). Keys are written as JSON arrays of their
+// column values; an empty Start or End means the range is unbounded.
+type KeyRangeValidation struct {
+	Start       string `json:"start,omitempty"`
+	End         string `json:"end,omitempty"`
+	SourceRows  int64  `json:"sourceRows"`
+	SpannerRows int64  `json:"spannerRows"`
+	SourceHash  string `json:"sourceHash"`
+	SpannerHash string `json:"spannerHash"`
+}
+
+// ValidateData compares the rows of every table of conv in the source
+// database with the rows of the Spanner database client is connected to.
+// Source rows are read and converted as a data migration would convert
+// them, with the ConvertData function of the source, so that type
+// conversions don't show up as mismatches. For each table, the row counts
+// are compared, and so are the hashes of the converted values of the rows
+// in up to maxRanges ranges of primary keys, which locate the differences.
+// Only the columns migrated from the source are compared, and tables with a
+// synthetic primary key are compared as a single range.
+func ValidateData(ctx context.Context, migrationProjectId string, sourceProfile profiles.SourceProfile, targetProfile profiles.TargetProfile, ioHelper *utils.IOStreams, conv *internal.Conv, client *sp.Client, maxRanges int) (*ValidationReport, error) {
+	v := newValidator(conv, maxRanges)
+	for _, t := range v.tables {
+		if err := v.readSpannerTable(ctx, client, t); err != nil {
+			t.err = fmt.Errorf("can't read Spanner table %s: %v", t.spTable, err)
+		}
+	}
+	if err := v.readSource(ctx, migrationProjectId, sourceProfile, targetProfile, ioHelper, client); err != nil {
+		return nil, err
+	}
+	return v.report(), nil
+}
+
+// validator accumulates the row counts and hashes of the source and
+// Spanner tables.
+type validator struct {
+	conv      *internal.Conv
+	maxRanges int
+	tables    []*tableDigest
+	bySpName  map[string]*tableDigest
+	mu        sync.Mutex
+}
+
+// tableDigest holds the key ranges of a table. Spanner rows are read in key
+// order and split into ranges of equal size; the first key of each range
+// but the first is its lower bound. Source rows are then assigned to the
+// range of their key.
+type tableDigest struct {
+	tableId  string
+	srcTable string
+	spTable  string
+	// cols are the columns compared, and keyCols the positions of the
+	// primary key columns among them (none with a synthetic primary key).
+	// pkCols are the primary key columns of the Spanner table, which pages
+	// of rows are read by.
+	cols    []string
+	keyCols []int
+	desc    []bool
+	pkCols  []string
+	dialect string
+	bounds  [][]keyPart
+	ranges  []rangeDigest
+	spRows  int64
+	srcRows int64
+	err     error
+}
+
+type rangeDigest struct {
+	srcRows, spRows int64
+	srcHash, spHash uint64
+}
+
+// keyPart is a primary key value in the canonical form of canonicalValue.
+type keyPart struct {
+	code  sppb.TypeCode
+	null  bool
+	value string
+}
+
+func newValidator(conv *internal.Conv, maxRanges int) *validator {
+	if maxRanges <= 0 {
+		maxRanges = DefaultValidationRanges
+	}
+	v := &validator{conv: conv, maxRanges: maxRanges, bySpName: make(map[string]*tableDigest)}
+	var tableIds []string
+	for tableId := range conv.SpSchema {
+		if _, ok := conv.SrcSchema[tableId]; ok {
+			tableIds = append(tableIds, tableId)
+		}
+	}
+	sort.Slice(tableIds, func(i, j int) bool {
+		return conv.SpSchema[tableIds[i]].Name < conv.SpSchema[tableIds[j]].Name
+	})
+	for _, tableId := range tableIds {
+		srcSchema, spSchema := conv.SrcSchema[tableId], conv.SpSchema[tableId]
+		t := &tableDigest{tableId: tableId, srcTable: srcSchema.Name, spTable: spSchema.Name, dialect: conv.SpDialect}
+		synthetic, hasSynthetic := conv.SyntheticPKeys[tableId]
+		pos := make(map[string]int)
+		for _, colId := range spSchema.ColIds {
+			if _, ok := srcSchema.ColDefs[colId]; !ok || (hasSynthetic && colId == synthetic.ColId) {
+				continue
+			}
+			pos[colId] = len(t.cols)
+			t.cols = append(t.cols, spSchema.ColDefs[colId].Name)
+		}
+		pks := append([]ddl.IndexKey{}, spSchema.PrimaryKeys...)
+		sort.SliceStable(pks, func(i, j int) bool { return pks[i].Order < pks[j].Order })
+		for _, pk := range pks {
+			t.pkCols = append(t.pkCols, spSchema.ColDefs[pk.ColId].Name)
+		}
+		if !hasSynthetic {
+			for _, pk := range pks {
+				i, ok := pos[pk.ColId]
+				if !ok {
+					// The key isn't migrated from the source.
+					t.keyCols, t.desc = nil, nil
+					break
+				}
+				t.keyCols = append(t.keyCols, i)
+				t.desc = append(t.desc, pk.Desc)
+			}
+		}
+		t.ranges = []rangeDigest{{}}
+		v.tables = append(v.tables, t)
+		v.bySpName[t.spTable] = t
+	}
+	return v
+}
+
+// readSpannerTable reads the rows of table t from Spanner, in key order.
+// Rows are read in pages, each with its own read, so that no transaction is
+// held open while a large table is scanned.
+func (v *validator) readSpannerTable(ctx context.Context, client *sp.Client, t *tableDigest) error {
+	quote := func(name string) string {
+		if v.conv.SpDialect == constants.DIALECT_POSTGRESQL {
+			return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+		}
+		return "`" + name + "`"
+	}
+	var count int64
+	err := client.Single().Query(ctx, sp.Statement{SQL: fmt.Sprintf("SELECT COUNT(*) FROM %s", quote(t.spTable))}).Do(func(r *sp.Row) error {
+		return r.Column(0, &count)
+	})
+	if err != nil {
+		return err
+	}
+	rangeSize := (count + int64(v.maxRanges) - 1) / int64(v.maxRanges)
+	// Reads return rows in primary key order, so each page starts after the
+	// key of the last row of the previous one. The key columns are read
+	// after the compared ones.
+	cols := append(append([]string{}, t.cols...), t.pkCols...)
+	var keys sp.KeySet = sp.AllKeys()
+	for {
+		n := 0
+		var last *sp.Row
+		err := client.Single().ReadWithOptions(ctx, t.spTable, keys, cols, &sp.ReadOptions{Limit: validationPageSize}).Do(func(r *sp.Row) error {
+			n++
+			last = r
+			return t.addSpannerRow(r, rangeSize)
+		})
+		if err != nil {
+			return err
+		}
+		if n < validationPageSize {
+			return nil
+		}
+		start, err := spannerKey(last, len(t.cols))
+		if err != nil {
+			return err
+		}
+		keys = sp.KeyRange{Start: start, End: sp.Key{}, Kind: sp.OpenClosed}
+	}
+}
+
+// spannerKey returns the key made of the columns of row r from the one at
+// position from, in the form the Spanner client takes keys in.
+func spannerKey(r *sp.Row, from int) (sp.Key, error) {
+	var key sp.Key
+	for i := from; i < r.Size(); i++ {
+		var g sp.GenericColumnValue
+		if err := r.Column(i, &g); err != nil {
+			return nil, err
+		}
+		part, err := spannerKeyPart(g)
+		if err != nil {
+			return nil, fmt.Errorf("key column %s: %v", r.ColumnName(i), err)
+		}
+		key = append(key, part)
+	}
+	return key, nil
+}
+
+func spannerKeyPart(g sp.GenericColumnValue) (interface{}, error) {
+	var err error
+	switch g.Type.GetCode() {
+	case sppb.TypeCode_INT64:
+		var x sp.NullInt64
+		err = g.Decode(&x)
+		return x, err
+	case sppb.TypeCode_FLOAT64:
+		var x sp.NullFloat64
+		err = g.Decode(&x)
+		return x, err
+	case sppb.TypeCode_FLOAT32:
+		var x sp.NullFloat32
+		err = g.Decode(&x)
+		return x, err
+	case sppb.TypeCode_BOOL:
+		var x sp.NullBool
+		err = g.Decode(&x)
+		return x, err
+	case sppb.TypeCode_STRING:
+		var x sp.NullString
+		err = g.Decode(&x)
+		return x, err
+	case sppb.TypeCode_BYTES:
+		var x []byte
+		err = g.Decode(&x)
+		return x, err
+	case sppb.TypeCode_TIMESTAMP:
+		var x sp.NullTime
+		err = g.Decode(&x)
+		return x, err
+	case sppb.TypeCode_DATE:
+		var x sp.NullDate
+		err = g.Decode(&x)
+		return x, err
+	case sppb.TypeCode_NUMERIC:
+		var x sp.NullNumeric
+		err = g.Decode(&x)
+		return x, err
+	}
+	return nil, fmt.Errorf("unsupported key type %v", g.Type.GetCode())
+}
+
+// addSpannerRow adds a row read from Spanner, starting a new range every
+// rangeSize rows.
+func (t *tableDigest) addSpannerRow(r *sp.Row, rangeSize int64) error {
+	parts := make([]keyPart, len(t.cols))
+	for i := range t.cols {
+		var g sp.GenericColumnValue
+		if err := r.Column(i, &g); err != nil {
+			return err
+		}
+		p, err := canonicalValue(g.Type, g.Value)
+		if err != nil {
+			return fmt.Errorf("column %s: %v", t.cols[i], err)
+		}
+		parts[i] = p
+	}
+	key, h := t.hashRow(parts)
+	if len(t.keyCols) > 0 && rangeSize > 0 && t.spRows > 0 && t.spRows%rangeSize == 0 {
+		t.bounds = append(t.bounds, key)
+		t.ranges = append(t.ranges, rangeDigest{})
+	}
+	rd := &t.ranges[len(t.ranges)-1]
+	rd.spRows++
+	rd.spHash += h
+	t.spRows++
+	return nil
+}
+
+// addSourceRow adds a converted source row. Values are encoded as the
+// Spanner client encodes them, so that they compare with the values read
+// from Spanner.
+func (t *tableDigest) addSourceRow(cols []string, vals []interface{}) error {
+	index := make(map[string]int, len(t.cols))
+	for i, c := range t.cols {
+		index[c] = i
+	}
+	// Converted rows leave out NULL values.
+	parts := make([]keyPart, len(t.cols))
+	for i := range parts {
+		parts[i].null = true
+	}
+	var present []int
+	var encCols []string
+	var encVals []interface{}
+	for i, c := range cols {
+		if j, ok := index[c]; ok && vals[i] != nil {
+			present = append(present, j)
+			encCols = append(encCols, c)
+			encVals = append(encVals, vals[i])
+		}
+	}
+	r, err := sp.NewRow(encCols, encVals)
+	if err != nil {
+		return err
+	}
+	for k, j := range present {
+		var g sp.GenericColumnValue
+		if err := r.Column(k, &g); err != nil {
+			return err
+		}
+		if parts[j], err = canonicalValue(g.Type, g.Value); err != nil {
+			return fmt.Errorf("column %s: %v", t.cols[j], err)
+		}
+	}
+	key, h := t.hashRow(parts)
+	// The range of key is the last one whose lower bound is at most key.
+	i := sort.Search(len(t.bounds), func(i int) bool {
+		return compareKeys(t.bounds[i], key, t.desc, t.dialect) > 0
+	})
+	rd := &t.ranges[i]
+	rd.srcRows++
+	rd.srcHash += h
+	t.srcRows++
+	return nil
+}
+
+// hashRow returns the key of a row, given by the canonical values of its
+// columns, and its hash. The hash of a range is the sum of the hashes of
+// its rows, so that it doesn't depend on the order rows are read in.
+func (t *tableDigest) hashRow(parts []keyPart) ([]keyPart, uint64) {
+	hash := sha256.New()
+	for i, p := range parts {
+		hash.Write([]byte(t.cols[i]))
+		if p.null {
+			hash.Write([]byte{0})
+		} else {
+			hash.Write([]byte{1})
+			hash.Write([]byte(p.value))
+		}
+		hash.Write([]byte{0})
+	}
+	var key []keyPart
+	for _, k := range t.keyCols {
+		key = append(key, parts[k])
+	}
+	return key, binary.BigEndian.Uint64(hash.Sum(nil))
+}
+
+// canonicalValue returns the canonical form of a Spanner value, which is
+// the same for values that Spanner considers equal: it doesn't depend on
+// whether the value was encoded by the client library or read from
+// Spanner.
+func canonicalValue(t *sppb.Type, v *structpb.Value) (keyPart, error) {
+	p := keyPart{code: t.GetCode()}
+	if _, ok := v.GetKind().(*structpb.Value_NullValue); ok || v == nil {
+		p.null = true
+		return p, nil
+	}
+	switch p.code {
+	case sppb.TypeCode_ARRAY:
+		var elems []string
+		for _, e := range v.GetListValue().GetValues() {
+			ep, err := canonicalValue(t.GetArrayElementType(), e)
+			if err != nil {
+				return p, err
+			}
+			if ep.null {
+				elems = append(elems, "NULL")
+			} else {
+				elems = append(elems, strconv.Quote(ep.value))
+			}
+		}
+		p.value = "[" + strings.Join(elems, ",") + "]"
+	case sppb.TypeCode_FLOAT64, sppb.TypeCode_FLOAT32:
+		var f float64
+		switch k := v.GetKind().(type) {
+		case *structpb.Value_NumberValue:
+			f = k.NumberValue
+		case *structpb.Value_StringValue:
+			var err error
+			if f, err = strconv.ParseFloat(k.StringValue, 64); err != nil {
+				return p, err
+			}
+		}
+		bits := 64
+		if p.code == sppb.TypeCode_FLOAT32 {
+			bits = 32
+		}
+		p.value = strconv.FormatFloat(f, 'g', -1, bits)
+	case sppb.TypeCode_BOOL:
+		p.value = strconv.FormatBool(v.GetBoolValue())
+	case sppb.TypeCode_TIMESTAMP:
+		ts, err := time.Parse(time.RFC3339Nano, v.GetStringValue())
+		if err != nil {
+			return p, err
+		}
+		p.value = ts.UTC().Format(time.RFC3339Nano)
+	case sppb.TypeCode_NUMERIC:
+		r, ok := new(big.Rat).SetString(v.GetStringValue())
+		if !ok {
+			// PostgreSQL NaN.
+			p.value = v.GetStringValue()
+		} else {
+			p.value = r.RatString()
+		}
+	case sppb.TypeCode_JSON:
+		dec := json.NewDecoder(strings.NewReader(v.GetStringValue()))
+		dec.UseNumber()
+		var j interface{}
+		if err := dec.Decode(&j); err != nil {
+			return p, err
+		}
+		b, err := json.Marshal(j)
+		if err != nil {
+			return p, err
+		}
+		p.value = string(b)
+	default:
+		p.value = v.GetStringValue()
+	}
+	return p, nil
+}
+
+// compareKeys compares keys in the order of Spanner databases of dialect:
+// values in ascending order, or reversed for descending key columns. NULLs
+// and NaNs come before other values in GoogleSQL databases, and after them
+// in PostgreSQL ones.
+func compareKeys(a, b []keyPart, desc []bool, dialect string) int {
+	for i := range a {
+		c := compareKeyPart(a[i], b[i], dialect == constants.DIALECT_POSTGRESQL)
+		if desc[i] {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func compareKeyPart(a, b keyPart, lowLast bool) int {
+	low := -1
+	if lowLast {
+		low = 1
+	}
+	switch {
+	case a.null && b.null:
+		return 0
+	case a.null:
+		return low
+	case b.null:
+		return -low
+	}
+	switch a.code {
+	case sppb.TypeCode_INT64:
+		x, _ := strconv.ParseInt(a.value, 10, 64)
+		y, _ := strconv.ParseInt(b.value, 10, 64)
+		return cmpOrdered(x, y)
+	case sppb.TypeCode_FLOAT64, sppb.TypeCode_FLOAT32:
+		x, _ := strconv.ParseFloat(a.value, 64)
+		y, _ := strconv.ParseFloat(b.value, 64)
+		switch {
+		case math.IsNaN(x) && math.IsNaN(y):
+			return 0
+		case math.IsNaN(x):
+			return low
+		case math.IsNaN(y):
+			return -low
+		}
+		return cmpOrdered(x, y)
+	case sppb.TypeCode_NUMERIC:
+		x, ok1 := new(big.Rat).SetString(a.value)
+		y, ok2 := new(big.Rat).SetString(b.value)
+		if ok1 && ok2 {
+			return x.Cmp(y)
+		}
+	case sppb.TypeCode_TIMESTAMP:
+		x, _ := time.Parse(time.RFC3339Nano, a.value)
+		y, _ := time.Parse(time.RFC3339Nano, b.value)
+		return x.Compare(y)
+	case sppb.TypeCode_BYTES:
+		x, _ := base64.StdEncoding.DecodeString(a.value)
+		y, _ := base64.StdEncoding.DecodeString(b.value)
+		return bytes.Compare(x, y)
+	case sppb.TypeCode_BOOL:
+		return cmpOrdered(boolInt(a.value == "true"), boolInt(b.value == "true"))
+	}
+	return strings.Compare(a.value, b.value)
+}
+
+func cmpOrdered[T int | int64 | float64](x, y T) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func keyString(key []keyPart) string {
+	if key == nil {
+		return ""
+	}
+	var parts []string
+	for _, p := range key {
+		if p.null {
+			parts = append(parts, "null")
+		} else {
+			parts = append(parts, strconv.Quote(p.value))
+		}
+	}
+	return "[" + strings.Join(parts, ",") + "]"
+}
+
+// sink is the data sink of conv while the source is read.
+func (v *validator) sink(table string, cols []string, vals []interface{}) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	t, ok := v.bySpName[table]
+	if !ok || t.err != nil {
+		return
+	}
+	if err := t.addSourceRow(cols, vals); err != nil {
+		t.err = fmt.Errorf("can't hash source row: %v", err)
+	}
+}
+
+// readSource reads the rows of the source database into v, through the
+// data migration path of the source, with v.sink as the data sink.
+func (v *validator) readSource(ctx context.Context, migrationProjectId string, sourceProfile profiles.SourceProfile, targetProfile profiles.TargetProfile, ioHelper *utils.IOStreams, client *sp.Client) error {
+	conv := v.conv
+	conv.Audit.DryRun = false
+	config := writer.BatchWriterConfig{BytesLimit: 100 * 1000 * 1000, WriteLimit: 1, RetryLimit: 1, Verbose: internal.Verbose()}
+	pdc := &validationDataConv{v: v}
+	sads := &DataFromSourceImpl{}
+	var err error
+	switch sourceProfile.Driver {
+	case constants.POSTGRES, constants.MYSQL, constants.SQLSERVER, constants.ORACLE, constants.CASSANDRA, constants.SPANNER, constants.SQLITE:
+		if sourceProfile.Ty == profiles.SourceProfileTypeConfig && sourceProfile.Config.ConfigType == constants.DMS_MIGRATION {
+			return fmt.Errorf("validation of dms migrations is not supported, please validate each shard with its connection profile")
+		}
+		_, err = sads.dataFromDatabase(ctx, migrationProjectId, sourceProfile, targetProfile, config, conv, nil, &GetInfoImpl{}, &DataFromDatabaseImpl{}, &validationSnapshot{pdc: pdc})
+	case constants.PGDUMP, constants.MYSQLDUMP:
+		if sourceProfile.File.Format == constants.MYDUMPER {
+			_, err = sads.dataFromMydumper(sourceProfile.File.Path, config, ioHelper, nil, conv, pdc)
+			break
+		}
+		_, err = sads.dataFromDump(sourceProfile.Driver, config, ioHelper, nil, conv, true, &ProcessDumpByDialectImpl{PgArchivePath: pgArchivePath(sourceProfile)}, pdc)
+	case constants.CSV:
+		_, err = sads.dataFromCSV(ctx, sourceProfile, targetProfile, config, conv, client, pdc, &csv.CsvImpl{})
+	default:
+		err = fmt.Errorf("validation for driver %s not supported", sourceProfile.Driver)
+	}
+	return err
+}
+
+// validationDataConv sets up conv to send converted rows to the validator
+// instead of Spanner.
+type validationDataConv struct {
+	v *validator
+}
+
+func (pdc *validationDataConv) populateDataConv(conv *internal.Conv, config writer.BatchWriterConfig, client *sp.Client) *writer.BatchWriter {
+	conv.SetDataMode()
+	conv.SetDataSink(pdc.v.sink)
+	conv.DataFlush = nil
+	return writer.NewBatchWriter(config)
+}
+
+// validationSnapshot reads the rows of database sources with a
+// validationDataConv.
+type validationSnapshot struct {
+	pdc *validationDataConv
+}
+
+func (vs *validationSnapshot) performSnapshotMigration(config writer.BatchWriterConfig, conv *internal.Conv, client *sp.Client, infoSchema common.InfoSchema, additionalAttributes internal.AdditionalDataAttributes, infoSchemaI common.InfoSchemaInterface, _ PopulateDataConvInterface) (*writer.BatchWriter, error) {
+	sm := &SnapshotMigrationImpl{}
+	return sm.performSnapshotMigration(config, conv, client, infoSchema, additionalAttributes, infoSchemaI, vs.pdc)
+}
+
+func (v *validator) report() *ValidationReport {
+	r := &ValidationReport{Tables: []TableValidation{}}
+	for _, t := range v.tables {
+		tv := TableValidation{
+			SrcTable:    t.srcTable,
+			SpTable:     t.spTable,
+			SourceRows:  t.srcRows,
+			BadRows:     v.conv.Stats.BadRows[t.srcTable],
+			SpannerRows: t.spRows,
+			Ranges:      len(t.ranges),
+		}
+		if t.err != nil {
+			tv.Error = t.err.Error()
+			r.Tables = append(r.Tables, tv)
+			continue
+		}
+		for i, rd := range t.ranges {
+			if rd.srcRows == rd.spRows && rd.srcHash == rd.spHash {
+				continue
+			}
+			kr := KeyRangeValidation{
+				SourceRows:  rd.srcRows,
+				SpannerRows: rd.spRows,
+				SourceHash:  fmt.Sprintf("%016x", rd.srcHash),
+				SpannerHash: fmt.Sprintf("%016x", rd.spHash),
+			}
+			if i > 0 {
+				kr.Start = keyString(t.bounds[i-1])
+			}
+			if i < len(t.bounds) {
+				kr.End = keyString(t.bounds[i])
+			}
+			tv.MismatchedRanges = append(tv.MismatchedRanges, kr)
+		}
+		tv.Match = tv.BadRows == 0 && tv.SourceRows == tv.SpannerRows && len(tv.MismatchedRanges) == 0
+		r.Tables = append(r.Tables, tv)
+	}
+	return r
+}
@@ -0,0 +1,203 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	sp "cloud.google.com/go/spanner"
+	sppb "cloud.google.com/go/spanner/apiv1/spannerpb"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// spannerValue returns a value as Spanner returns it in query results.
+func spannerValue(code sppb.TypeCode, s *string) sp.GenericColumnValue {
+	v := structpb.NewNullValue()
+	if s != nil {
+		v = structpb.NewStringValue(*s)
+	}
+	return sp.GenericColumnValue{Type: &sppb.Type{Code: code}, Value: v}
+}
+
+func TestValidator(t *testing.T) {
+	conv := internal.MakeConv()
+	conv.SrcSchema["t1"] = schema.Table{
+		Id:     "t1",
+		Name:   "src_items",
+		ColIds: []string{"c1", "c2", "c3", "c4"},
+		ColDefs: map[string]schema.Column{
+			"c1": {Id: "c1", Name: "id"},
+			"c2": {Id: "c2", Name: "price"},
+			"c3": {Id: "c3", Name: "ts"},
+			"c4": {Id: "c4", Name: "note"},
+		},
+	}
+	conv.SpSchema["t1"] = ddl.CreateTable{
+		Id:          "t1",
+		Name:        "items",
+		ColIds:      []string{"c1", "c2", "c3", "c4", "c5"},
+		PrimaryKeys: []ddl.IndexKey{{ColId: "c1"}},
+		ColDefs: map[string]ddl.ColumnDef{
+			"c1": {Id: "c1", Name: "id", T: ddl.Type{Name: ddl.Int64}},
+			"c2": {Id: "c2", Name: "price", T: ddl.Type{Name: ddl.Numeric}},
+			"c3": {Id: "c3", Name: "ts", T: ddl.Type{Name: ddl.Timestamp}},
+			"c4": {Id: "c4", Name: "note", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+			// Added in Spanner: not compared.
+			"c5": {Id: "c5", Name: "added", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+		},
+	}
+	conv.SrcSchema["t2"] = schema.Table{
+		Id:      "t2",
+		Name:    "src_logs",
+		ColIds:  []string{"c6"},
+		ColDefs: map[string]schema.Column{"c6": {Id: "c6", Name: "msg"}},
+	}
+	conv.SpSchema["t2"] = ddl.CreateTable{
+		Id:          "t2",
+		Name:        "logs",
+		ColIds:      []string{"c6", "c7"},
+		PrimaryKeys: []ddl.IndexKey{{ColId: "c7"}},
+		ColDefs: map[string]ddl.ColumnDef{
+			"c6": {Id: "c6", Name: "msg", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+			"c7": {Id: "c7", Name: "synth_id", T: ddl.Type{Name: ddl.String, Len: 50}},
+		},
+	}
+	conv.SyntheticPKeys["t2"] = internal.SyntheticPKey{ColId: "c7"}
+	conv.Stats.BadRows["src_logs"] = 1
+
+	v := newValidator(conv, 2)
+	assert.Equal(t, 2, len(v.tables))
+	items, logs := v.bySpName["items"], v.bySpName["logs"]
+	assert.Equal(t, []string{"id", "price", "ts", "note"}, items.cols)
+	assert.Equal(t, []int{0}, items.keyCols)
+	assert.Equal(t, []string{"id"}, items.pkCols)
+	assert.Equal(t, []string{"msg"}, logs.cols)
+	assert.Nil(t, logs.keyCols)
+	// Pages of rows are read by the synthetic key.
+	assert.Equal(t, []string{"synth_id"}, logs.pkCols)
+
+	str := func(s string) *string { return &s }
+	for _, id := range []string{"1", "2", "3", "4"} {
+		r, err := sp.NewRow(items.cols, []interface{}{
+			spannerValue(sppb.TypeCode_INT64, str(id)),
+			spannerValue(sppb.TypeCode_NUMERIC, str("1.5")),
+			spannerValue(sppb.TypeCode_TIMESTAMP, str("2024-01-02T03:04:05.100Z")),
+			spannerValue(sppb.TypeCode_STRING, nil),
+		})
+		assert.Nil(t, err)
+		assert.Nil(t, items.addSpannerRow(r, 2))
+	}
+	r, err := sp.NewRow(logs.cols, []interface{}{"hi"})
+	assert.Nil(t, err)
+	assert.Nil(t, logs.addSpannerRow(r, 1))
+
+	// Source rows, in any order, as converted by ConvertData: NULL values
+	// are left out.
+	price := big.NewRat(3, 2)
+	ts := time.Date(2024, 1, 2, 4, 4, 5, 100000000, time.FixedZone("", 3600))
+	cols := []string{"id", "price", "ts"}
+	v.sink("items", cols, []interface{}{int64(4), price, ts})
+	v.sink("items", cols, []interface{}{int64(2), price, ts})
+	v.sink("items", cols, []interface{}{int64(1), price, ts})
+	v.sink("items", append(cols, "note"), []interface{}{int64(3), price, ts, "changed"})
+	v.sink("logs", []string{"msg", "synth_id"}, []interface{}{"hi", "0"})
+	v.sink("other", []string{"x"}, []interface{}{int64(1)})
+
+	report := v.report()
+	assert.Equal(t, &ValidationReport{Tables: []TableValidation{
+		{
+			SrcTable:    "src_items",
+			SpTable:     "items",
+			SourceRows:  4,
+			SpannerRows: 4,
+			Ranges:      2,
+			MismatchedRanges: []KeyRangeValidation{{
+				Start:       `["3"]`,
+				SourceRows:  2,
+				SpannerRows: 2,
+				SourceHash:  report.Tables[0].MismatchedRanges[0].SourceHash,
+				SpannerHash: report.Tables[0].MismatchedRanges[0].SpannerHash,
+			}},
+		},
+		{SrcTable: "src_logs", SpTable: "logs", SourceRows: 1, BadRows: 1, SpannerRows: 1, Ranges: 1},
+	}}, report)
+	assert.NotEqual(t, report.Tables[0].MismatchedRanges[0].SourceHash, report.Tables[0].MismatchedRanges[0].SpannerHash)
+	assert.Equal(t, 2, report.Mismatches())
+}
+
+func TestCompareKeys(t *testing.T) {
+	part := func(code sppb.TypeCode, s string) keyPart { return keyPart{code: code, value: s} }
+	null := keyPart{null: true}
+	pg := constants.DIALECT_POSTGRESQL
+	testCases := []struct {
+		a, b     keyPart
+		desc     bool
+		dialect  string
+		expected int
+	}{
+		{part(sppb.TypeCode_INT64, "9"), part(sppb.TypeCode_INT64, "10"), false, "", -1},
+		{part(sppb.TypeCode_INT64, "9"), part(sppb.TypeCode_INT64, "10"), true, "", 1},
+		{null, part(sppb.TypeCode_INT64, "-10"), false, "", -1},
+		{null, part(sppb.TypeCode_INT64, "-10"), true, "", 1},
+		{null, null, false, "", 0},
+		{part(sppb.TypeCode_NUMERIC, "3/2"), part(sppb.TypeCode_NUMERIC, "2"), false, "", -1},
+		{part(sppb.TypeCode_FLOAT64, "NaN"), part(sppb.TypeCode_FLOAT64, "-1"), false, "", -1},
+		{part(sppb.TypeCode_STRING, "b"), part(sppb.TypeCode_STRING, "a"), false, "", 1},
+		{part(sppb.TypeCode_BYTES, "AQ=="), part(sppb.TypeCode_BYTES, "/w=="), false, "", -1},
+		{part(sppb.TypeCode_TIMESTAMP, "2024-01-02T03:04:05.1Z"), part(sppb.TypeCode_TIMESTAMP, "2024-01-02T03:04:05Z"), false, "", 1},
+		// PostgreSQL databases sort NULLs and NaNs last.
+		{null, part(sppb.TypeCode_INT64, "-10"), false, pg, 1},
+		{null, part(sppb.TypeCode_INT64, "-10"), true, pg, -1},
+		{part(sppb.TypeCode_INT64, "9"), part(sppb.TypeCode_INT64, "10"), false, pg, -1},
+		{part(sppb.TypeCode_FLOAT64, "NaN"), part(sppb.TypeCode_FLOAT64, "-1"), false, pg, 1},
+		{part(sppb.TypeCode_FLOAT64, "NaN"), part(sppb.TypeCode_FLOAT64, "NaN"), false, pg, 0},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, compareKeys([]keyPart{tc.a}, []keyPart{tc.b}, []bool{tc.desc}, tc.dialect), "%v %v %s", tc.a, tc.b, tc.dialect)
+	}
+}
+
+func TestSpannerKey(t *testing.T) {
+	str := func(s string) *string { return &s }
+	r, err := sp.NewRow([]string{"msg", "id", "name", "day", "data"}, []interface{}{
+		"not a key column",
+		spannerValue(sppb.TypeCode_INT64, str("7")),
+		spannerValue(sppb.TypeCode_STRING, nil),
+		spannerValue(sppb.TypeCode_DATE, str("2024-01-02")),
+		spannerValue(sppb.TypeCode_BYTES, str("AQ==")),
+	})
+	assert.Nil(t, err)
+	key, err := spannerKey(r, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, sp.Key{
+		sp.NullInt64{Int64: 7, Valid: true},
+		sp.NullString{},
+		sp.NullDate{Date: civil.Date{Year: 2024, Month: 1, Day: 2}, Valid: true},
+		[]byte{1},
+	}, key)
+
+	r, err = sp.NewRow([]string{"j"}, []interface{}{spannerValue(sppb.TypeCode_JSON, str("{}"))})
+	assert.Nil(t, err)
+	_, err = spannerKey(r, 0)
+	assert.Error(t, err)
+}
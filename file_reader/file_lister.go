@@ -0,0 +1,199 @@
+package file_reader
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/clients"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"google.golang.org/api/iterator"
+)
+
+var ListFiles = listFiles
+
+// IsFilePattern returns true if uri is a pattern matching several files: a
+// glob, e.g. gs://bucket/export/part-*.csv, or a directory or prefix ending
+// with "/", e.g. s3://bucket/export/, which matches all the files under it.
+// http(s) uris are never patterns, and only the path of gs:// and s3://
+// uris is matched, so that their queries, e.g. of presigned urls, aren't
+// taken for globs.
+func IsFilePattern(uri string) bool {
+	if strings.Contains(uri, "://") {
+		u, err := url.Parse(uri)
+		if err != nil {
+			return false
+		}
+		switch u.Scheme {
+		case constants.HTTP_SCHEME, constants.HTTPS_SCHEME:
+			return false
+		}
+		uri = u.Path
+	}
+	return strings.ContainsAny(uri, "*?[") || strings.HasSuffix(uri, "/")
+}
+
+// listFiles returns the sorted uris of the files matched by pattern, a
+// local path, or a gs:// or s3:// uri. Globs are matched as by path.Match,
+// so '*' doesn't match '/'. Files of http(s) uris can't be listed.
+func listFiles(ctx context.Context, pattern string) ([]string, error) {
+	scheme, rest, found := strings.Cut(pattern, "://")
+	if !found {
+		return listLocalFiles(pattern)
+	}
+	// As in IsFilePattern, the query isn't part of the pattern.
+	rest, _, _ = strings.Cut(rest, "?")
+	bucket, keyPattern, _ := strings.Cut(rest, "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("invalid uri %s: no bucket", pattern)
+	}
+	// Only the objects starting with the part of the pattern before the
+	// first glob character are listed.
+	prefix := keyPattern
+	if i := strings.IndexAny(keyPattern, "*?["); i >= 0 {
+		prefix = keyPattern[:i]
+	}
+	if _, err := path.Match(keyPattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %s: %v", pattern, err)
+	}
+
+	var keys []string
+	var err error
+	switch scheme {
+	case constants.GCS_SCHEME:
+		keys, err = listGcsObjects(ctx, bucket, prefix)
+	case constants.S3_SCHEME:
+		keys, err = listS3Objects(ctx, bucket, prefix)
+	default:
+		return nil, fmt.Errorf("can't list the files of %s: listing is only supported for local paths, gs:// and s3:// uris", pattern)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't list the files of %s: %v", pattern, err)
+	}
+
+	var uris []string
+	for _, key := range keys {
+		// Objects ending with "/" are placeholders of directories.
+		if strings.HasSuffix(key, "/") {
+			continue
+		}
+		if !strings.HasSuffix(keyPattern, "/") {
+			if matched, _ := path.Match(keyPattern, key); !matched {
+				continue
+			}
+		}
+		uris = append(uris, scheme+"://"+bucket+"/"+key)
+	}
+	sort.Strings(uris)
+	return uris, nil
+}
+
+// listLocalFiles returns the files matched by a glob, or all the files under
+// a directory ending with "/".
+func listLocalFiles(pattern string) ([]string, error) {
+	var files []string
+	if strings.HasSuffix(pattern, "/") {
+		err := filepath.WalkDir(pattern, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.Type().IsRegular() {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("can't list the files of %s: %v", pattern, err)
+		}
+		return files, nil
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %s: %v", pattern, err)
+	}
+	for _, match := range matches {
+		if info, err := os.Stat(match); err == nil && info.Mode().IsRegular() {
+			files = append(files, match)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func listGcsObjects(ctx context.Context, bucket, prefix string) ([]string, error) {
+	storageClient, err := GoogleStorageNewClient(ctx, clients.FetchStorageClientOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	defer storageClient.Close()
+	query := &storage.Query{Prefix: prefix}
+	if err := query.SetAttrSelection([]string{"Name"}); err != nil {
+		return nil, err
+	}
+	var keys []string
+	it := storageClient.Bucket(bucket).Objects(ctx, query)
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			return keys, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, attrs.Name)
+	}
+}
+
+// listS3Objects lists the keys of the objects starting with prefix, with
+// ListObjectsV2 requests.
+func listS3Objects(ctx context.Context, bucket, prefix string) ([]string, error) {
+	s3Config, err := GetS3Config(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bucketUrl, err := s3BucketUrl(s3Config, bucket)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		object := &httpObject{url: bucketUrl + "/?" + s3EncodeQuery(query), sign: s3Signer(s3Config)}
+		resp, err := object.do(ctx, http.MethodGet, 0, "")
+		if err != nil {
+			return nil, err
+		}
+		var result struct {
+			Contents []struct {
+				Key string
+			}
+			IsTruncated           bool
+			NextContinuationToken string
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("can't parse the list of objects of bucket %s: %v", bucket, err)
+		}
+		for _, content := range result.Contents {
+			keys = append(keys, content.Key)
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return keys, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
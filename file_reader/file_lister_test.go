@@ -0,0 +1,132 @@
+package file_reader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/storage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/option"
+)
+
+func TestIsFilePattern(t *testing.T) {
+	assert.True(t, IsFilePattern("gs://bucket/export/part-*.csv"))
+	assert.True(t, IsFilePattern("/tmp/part-000?.csv"))
+	assert.True(t, IsFilePattern("s3://bucket/export/"))
+	assert.False(t, IsFilePattern("s3://bucket/export/part-0000.csv"))
+	assert.False(t, IsFilePattern("/tmp/singers.csv"))
+	assert.False(t, IsFilePattern("https://host/singers.csv?token=abc"))
+	assert.False(t, IsFilePattern("https://host/export/"))
+	assert.False(t, IsFilePattern("s3://bucket/singers.csv?X-Amz-Signature=abc"))
+	assert.True(t, IsFilePattern("gs://bucket/export/part-*.csv?generation=1"))
+}
+
+func TestListFiles_Local(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"part-0001.csv", "part-0000.csv", "part-0002.json", "sub/part-0003.csv"} {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("a\n1\n"), 0644))
+	}
+	ctx := context.Background()
+
+	files, err := ListFiles(ctx, filepath.Join(dir, "part-*.csv"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "part-0000.csv"), filepath.Join(dir, "part-0001.csv")}, files)
+
+	files, err = ListFiles(ctx, filepath.Join(dir, "*"))
+	assert.NoError(t, err)
+	assert.Len(t, files, 3, "directories are not matched")
+
+	files, err = ListFiles(ctx, dir+"/")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "part-0000.csv"), filepath.Join(dir, "part-0001.csv"),
+		filepath.Join(dir, "part-0002.json"), filepath.Join(dir, "sub", "part-0003.csv")}, files)
+
+	files, err = ListFiles(ctx, filepath.Join(dir, "missing-*.csv"))
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+
+	_, err = ListFiles(ctx, filepath.Join(dir, "[.csv"))
+	assert.Error(t, err)
+
+	_, err = ListFiles(ctx, filepath.Join(dir, "missing")+"/")
+	assert.Error(t, err)
+}
+
+func TestListFiles_S3(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/exports/", r.URL.Path)
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 "))
+		queries = append(queries, r.URL.RawQuery)
+		if r.URL.Query().Get("continuation-token") == "" {
+			fmt.Fprint(w, `<ListBucketResult><Contents><Key>singers/part-0000.csv</Key></Contents>`+
+				`<Contents><Key>singers/</Key></Contents><IsTruncated>true</IsTruncated>`+
+				`<NextContinuationToken>next/1</NextContinuationToken></ListBucketResult>`)
+			return
+		}
+		fmt.Fprint(w, `<ListBucketResult><Contents><Key>singers/part-0001.csv</Key></Contents>`+
+			`<Contents><Key>singers/sub/part-0002.csv</Key></Contents><IsTruncated>false</IsTruncated></ListBucketResult>`)
+	}))
+	defer server.Close()
+
+	originalGetS3Config := GetS3Config
+	defer func() { GetS3Config = originalGetS3Config }()
+	GetS3Config = func(ctx context.Context) (S3Config, error) {
+		return S3Config{Endpoint: server.URL, Region: "us-east-1", Credentials: staticCredentials("minio", "secret")}, nil
+	}
+	ctx := context.Background()
+
+	files, err := ListFiles(ctx, "s3://exports/singers/part-*.csv")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"s3://exports/singers/part-0000.csv", "s3://exports/singers/part-0001.csv"}, files)
+	assert.Equal(t, []string{"list-type=2&prefix=singers%2Fpart-",
+		"continuation-token=next%2F1&list-type=2&prefix=singers%2Fpart-"}, queries)
+
+	files, err = ListFiles(ctx, "s3://exports/singers/")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"s3://exports/singers/part-0000.csv", "s3://exports/singers/part-0001.csv",
+		"s3://exports/singers/sub/part-0002.csv"}, files)
+
+	files, err = ListFiles(ctx, "s3://exports/singers/part-*.csv?X-Amz-Signature=abc")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"s3://exports/singers/part-0000.csv", "s3://exports/singers/part-0001.csv"}, files)
+}
+
+func TestListFiles_Gcs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/b/exports/o") {
+			http.NotFound(w, r)
+			return
+		}
+		assert.Equal(t, "singers/part-", r.URL.Query().Get("prefix"))
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"items": []map[string]string{{"name": "singers/part-0001.csv"}, {"name": "singers/part-0000.csv"}, {"name": "singers/part-0000.json"}},
+		})
+	}))
+	defer server.Close()
+
+	originalGoogleStorageNewClient := GoogleStorageNewClient
+	defer func() { GoogleStorageNewClient = originalGoogleStorageNewClient }()
+	GoogleStorageNewClient = func(ctx context.Context, opts ...option.ClientOption) (*storage.Client, error) {
+		return storage.NewClient(ctx, option.WithEndpoint(server.URL+"/storage/v1/"), option.WithoutAuthentication())
+	}
+
+	files, err := ListFiles(context.Background(), "gs://exports/singers/part-*.csv")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"gs://exports/singers/part-0000.csv", "gs://exports/singers/part-0001.csv"}, files)
+}
+
+func TestListFiles_Unsupported(t *testing.T) {
+	_, err := ListFiles(context.Background(), "https://example.com/part-*.csv")
+	assert.Error(t, err)
+	_, err = ListFiles(context.Background(), "gs:///part-*.csv")
+	assert.Error(t, err)
+}
@@ -2,6 +2,7 @@ package file_reader
 
 import (
 	"context"
+	"fmt"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"io"
 	"net/url"
@@ -26,13 +27,58 @@ func newFileReader(ctx context.Context, uri string) (FileReader, error) {
 		return nil, err
 	}
 
-	if u.Scheme == constants.GCS_SCHEME {
+	switch u.Scheme {
+	case constants.GCS_SCHEME:
 		return NewGcsFileReader(ctx, uri, u.Host, u.Path)
-	} else {
+	case constants.S3_SCHEME:
+		return NewS3FileReader(ctx, uri, u.Host, u.Path)
+	case constants.HTTP_SCHEME, constants.HTTPS_SCHEME:
+		return NewHttpFileReader(ctx, uri)
+	default:
 		return NewLocalFileReader(uri)
 	}
 }
 
+// IsObjectUri returns true if uri is an s3 or http(s) uri, whose files are
+// read with http requests.
+func IsObjectUri(uri string) bool {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+	switch u.Scheme {
+	case constants.S3_SCHEME, constants.HTTP_SCHEME, constants.HTTPS_SCHEME:
+		return true
+	}
+	return false
+}
+
+// DownloadObject writes the s3 or http(s) file at uri to w, as it is
+// stored: compressed files are not decompressed.
+func DownloadObject(ctx context.Context, uri string, w io.Writer) error {
+	reader, err := newFileReader(ctx, uri)
+	if err != nil {
+		return err
+	}
+	var object *httpObject
+	switch r := reader.(type) {
+	case *HttpFileReaderImpl:
+		object = r.object
+	case *S3FileReaderImpl:
+		object = r.object
+	default:
+		reader.Close()
+		return fmt.Errorf("%s is not an s3 or http(s) uri", uri)
+	}
+	body, err := object.open(ctx)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	_, err = io.Copy(w, body)
+	return err
+}
+
 type MockFileReader struct {
 	ReadAllFn      func(ctx context.Context) ([]byte, error)
 	CloseFn        func()
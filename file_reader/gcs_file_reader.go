@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/clients"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
 	"google.golang.org/api/option"
 	"io"
@@ -20,6 +21,7 @@ type GcsFileReaderImpl struct {
 	gcsFilePath   string
 	storageClient *storage.Client
 	storageReader *storage.Reader
+	reader        io.Reader // The contents of storageReader, decompressed if the object is compressed.
 }
 
 func NewGcsFileReader(ctx context.Context, uri, host, path string) (*GcsFileReaderImpl, error) {
@@ -57,7 +59,19 @@ func (reader *GcsFileReaderImpl) CreateReader(ctx context.Context) (io.Reader, e
 		return nil, err
 	}
 	reader.storageReader = rc
-	return rc, nil
+	// Compressed objects are decompressed as they are read. Objects stored
+	// with gzip content encoding are already decompressed by the client, and
+	// their extension doesn't tell their format.
+	name := reader.uri
+	if rc.Attrs.Decompressed {
+		name = ""
+	}
+	r, err := internal.Decompress(rc, name)
+	if err != nil {
+		return nil, err
+	}
+	reader.reader = r
+	return r, nil
 }
 
 func (reader *GcsFileReaderImpl) Close() {
@@ -77,7 +91,7 @@ func (reader *GcsFileReaderImpl) ReadAll(ctx context.Context) ([]byte, error) {
 			return nil, err
 		}
 	}
-	return io.ReadAll(reader.storageReader)
+	return io.ReadAll(reader.reader)
 }
 
 func validateObjectExists(ctx context.Context, client *storage.Client, bucket, object string) error {
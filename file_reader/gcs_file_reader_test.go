@@ -193,7 +193,7 @@ func TestGCSFileReaderImpl_CreateReader(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.NotNil(t, r)
-				assert.IsType(t, &storage.Reader{}, r)
+				assert.IsType(t, &storage.Reader{}, reader.storageReader)
 			}
 		})
 	}
@@ -257,7 +257,7 @@ func TestGCSFileReaderImpl_ResetReader(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.NotNil(t, r)
-				assert.IsType(t, &storage.Reader{}, r)
+				assert.IsType(t, &storage.Reader{}, reader.(*GcsFileReaderImpl).storageReader)
 			}
 		})
 	}
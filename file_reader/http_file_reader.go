@@ -0,0 +1,210 @@
+package file_reader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+)
+
+// HttpClient is the client used to read http(s) and s3 files.
+var HttpClient = http.DefaultClient
+
+// maxResumes is the number of times the read of a file is resumed after a
+// failure.
+const maxResumes = 5
+
+// HttpFileReaderImpl reads a file served over http or https.
+type HttpFileReaderImpl struct {
+	uri    string
+	object *httpObject
+	body   *rangeReader
+	reader io.Reader // The contents of body, decompressed if the file is compressed.
+}
+
+func NewHttpFileReader(ctx context.Context, uri string) (*HttpFileReaderImpl, error) {
+	reader := &HttpFileReaderImpl{uri: uri, object: &httpObject{url: uri}}
+	if err := reader.object.stat(ctx); err != nil {
+		return nil, err
+	}
+	return reader, nil
+}
+
+// ResetReader reads the file again from its start, since the reader of a
+// response can't seek.
+func (reader *HttpFileReaderImpl) ResetReader(ctx context.Context) (io.Reader, error) {
+	if reader.body != nil {
+		reader.body.Close()
+	}
+	return reader.CreateReader(ctx)
+}
+
+func (reader *HttpFileReaderImpl) CreateReader(ctx context.Context) (io.Reader, error) {
+	body, err := reader.object.open(ctx)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("readFile: unable to open file %s: %v", reader.uri, err))
+		return nil, err
+	}
+	reader.body = body
+	// Compressed files are decompressed as they are read.
+	r, err := internal.Decompress(body, reader.uri)
+	if err != nil {
+		return nil, err
+	}
+	reader.reader = r
+	return r, nil
+}
+
+func (reader *HttpFileReaderImpl) Close() {
+	if reader.body != nil {
+		reader.body.Close()
+	}
+}
+
+func (reader *HttpFileReaderImpl) ReadAll(ctx context.Context) ([]byte, error) {
+	if reader.body == nil {
+		_, err := reader.CreateReader(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return io.ReadAll(reader.reader)
+}
+
+// httpObject is a file read with http GET requests. sign, if set, adds the
+// credentials of the requests.
+type httpObject struct {
+	url  string
+	sign func(req *http.Request) error
+}
+
+// do sends a request for the file from offset. If etag is set, the file
+// is only returned if its ETag is still etag.
+func (o *httpObject) do(ctx context.Context, method string, offset int64, etag string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, o.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	// The body is read as stored, so that offsets of range requests match,
+	// and compressed files are decompressed by the reader.
+	req.Header.Set("Accept-Encoding", "identity")
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
+	if o.sign != nil {
+		if err := o.sign(req); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := HttpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, &httpStatusError{method: method, url: o.url, status: resp.Status, code: resp.StatusCode}
+	}
+	return resp, nil
+}
+
+// stat checks that the file exists and can be read. Servers that don't
+// support HEAD requests are sent a GET request, whose body isn't read.
+func (o *httpObject) stat(ctx context.Context) error {
+	resp, err := o.do(ctx, http.MethodHead, 0, "")
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) && (statusErr.code == http.StatusMethodNotAllowed || statusErr.code == http.StatusNotImplemented) {
+		resp, err = o.do(ctx, http.MethodGet, 0, "")
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+type httpStatusError struct {
+	method string
+	url    string
+	status string
+	code   int
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.method, e.url, e.status)
+}
+
+// open returns a reader of the file from its start.
+func (o *httpObject) open(ctx context.Context) (*rangeReader, error) {
+	r := &rangeReader{ctx: ctx, object: o}
+	if err := r.resume(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// rangeReader reads the body of a GET request. When reading the body
+// fails, e.g. because the connection was reset, the read is resumed where
+// it stopped with a range request, so that a large file isn't read again
+// from its start. The read is only resumed if the ETag of the file hasn't
+// changed, so that the parts of two versions of a file aren't mixed.
+type rangeReader struct {
+	ctx     context.Context
+	object  *httpObject
+	body    io.ReadCloser
+	offset  int64
+	etag    string
+	resumes int
+}
+
+func (r *rangeReader) resume() error {
+	resp, err := r.object.do(r.ctx, http.MethodGet, r.offset, r.etag)
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) && statusErr.code == http.StatusPreconditionFailed {
+		return fmt.Errorf("can't resume read of %s at offset %d: the file has changed", r.object.url, r.offset)
+	}
+	if err != nil {
+		return err
+	}
+	if r.offset == 0 {
+		// Weak ETags don't identify the bytes of the file.
+		if etag := resp.Header.Get("ETag"); !strings.HasPrefix(etag, "W/") {
+			r.etag = etag
+		}
+	} else if resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return fmt.Errorf("can't resume read of %s at offset %d: range requests are not supported", r.object.url, r.offset)
+	} else if r.etag != "" && resp.Header.Get("ETag") != r.etag {
+		// The server ignored If-Match.
+		resp.Body.Close()
+		return fmt.Errorf("can't resume read of %s at offset %d: the file has changed", r.object.url, r.offset)
+	}
+	r.body = resp.Body
+	return nil
+}
+
+func (r *rangeReader) Read(p []byte) (int, error) {
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	if err == nil || err == io.EOF || r.ctx.Err() != nil || r.resumes == maxResumes {
+		return n, err
+	}
+	logger.Log.Debug(fmt.Sprintf("Resuming read of %s at offset %d after error: %v", r.object.url, r.offset, err))
+	r.body.Close()
+	r.resumes++
+	if err := r.resume(); err != nil {
+		return n, err
+	}
+	return n, nil
+}
+
+func (r *rangeReader) Close() error {
+	return r.body.Close()
+}
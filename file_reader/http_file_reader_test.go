@@ -0,0 +1,153 @@
+package file_reader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const httpTestData = "SingerId,FirstName\n1,fn1\n2,fn2\n"
+
+// newTestServer serves data at /data.csv. The first GET request is aborted
+// after half of the data when abort is set, so that the read is resumed.
+func newTestServer(t *testing.T, data []byte, abort bool) (*httptest.Server, *[]string) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.Header.Get("Range"))
+		if r.URL.Path != "/data.csv" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("ETag", `"1"`)
+		if abort && r.Method == http.MethodGet && r.Header.Get("Range") == "" {
+			abort = false
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+			w.Write(data[:len(data)/2])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		http.ServeContent(w, r, "data.csv", time.Time{}, bytes.NewReader(data))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestHttpFileReaderImpl(t *testing.T) {
+	server, requests := newTestServer(t, []byte(httpTestData), false)
+	ctx := context.Background()
+
+	reader, err := NewFileReader(ctx, server.URL+"/data.csv")
+	assert.NoError(t, err)
+	assert.IsType(t, &HttpFileReaderImpl{}, reader)
+	defer reader.Close()
+
+	r, err := reader.CreateReader(ctx)
+	assert.NoError(t, err)
+	b, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, httpTestData, string(b))
+
+	r, err = reader.ResetReader(ctx)
+	assert.NoError(t, err)
+	b, err = io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, httpTestData, string(b))
+	assert.Equal(t, []string{"HEAD ", "GET ", "GET "}, *requests)
+
+	_, err = NewFileReader(ctx, server.URL+"/missing.csv")
+	assert.Error(t, err)
+}
+
+func TestHttpFileReaderImpl_ResumeRead(t *testing.T) {
+	data := []byte(strings.Repeat(httpTestData, 100))
+	server, requests := newTestServer(t, data, true)
+	ctx := context.Background()
+
+	reader, err := NewHttpFileReader(ctx, server.URL+"/data.csv")
+	assert.NoError(t, err)
+	defer reader.Close()
+	b, err := reader.ReadAll(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, string(data), string(b))
+	assert.Equal(t, []string{"HEAD ", "GET ", "GET bytes=1550-"}, *requests)
+}
+
+func TestHttpFileReaderImpl_ResumeChangedFile(t *testing.T) {
+	data := []byte(strings.Repeat(httpTestData, 100))
+	etag := `"1"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", etag)
+		if r.Method == http.MethodGet && r.Header.Get("Range") == "" {
+			// The file is replaced while it is read.
+			etag = `"2"`
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+			w.Write(data[:len(data)/2])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		http.ServeContent(w, r, "data.csv", time.Time{}, bytes.NewReader(data))
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	reader, err := NewHttpFileReader(ctx, server.URL+"/data.csv")
+	assert.NoError(t, err)
+	defer reader.Close()
+	_, err = reader.ReadAll(ctx)
+	assert.ErrorContains(t, err, "the file has changed")
+}
+
+func TestHttpFileReaderImpl_Compressed(t *testing.T) {
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write([]byte(httpTestData))
+	assert.NoError(t, w.Close())
+	server, _ := newTestServer(t, gz.Bytes(), false)
+	ctx := context.Background()
+
+	reader, err := NewHttpFileReader(ctx, server.URL+"/data.csv")
+	assert.NoError(t, err)
+	defer reader.Close()
+	b, err := reader.ReadAll(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, httpTestData, string(b))
+
+	// Downloads keep the file compressed.
+	var downloaded bytes.Buffer
+	assert.NoError(t, DownloadObject(ctx, server.URL+"/data.csv", &downloaded))
+	assert.Equal(t, gz.Bytes(), downloaded.Bytes())
+}
+
+func TestHttpFileReaderImpl_HeadNotAllowed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		io.WriteString(w, httpTestData)
+	}))
+	defer server.Close()
+
+	reader, err := NewHttpFileReader(context.Background(), server.URL+"/data.csv")
+	assert.NoError(t, err)
+	b, err := reader.ReadAll(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, httpTestData, string(b))
+}
+
+func TestIsObjectUri(t *testing.T) {
+	assert.True(t, IsObjectUri("s3://bucket/data.csv"))
+	assert.True(t, IsObjectUri("https://example.com/data.csv"))
+	assert.True(t, IsObjectUri("http://example.com/data.csv"))
+	assert.False(t, IsObjectUri("gs://bucket/data.csv"))
+	assert.False(t, IsObjectUri("/tmp/data.csv"))
+}
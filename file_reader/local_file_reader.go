@@ -3,6 +3,7 @@ package file_reader
 import (
 	"context"
 	"fmt"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
 	"io"
 	"os"
@@ -11,6 +12,7 @@ import (
 type LocalFileReaderImpl struct {
 	uri        string
 	fileHandle *os.File
+	reader     io.Reader // The contents of fileHandle, decompressed if the file is compressed.
 }
 
 func NewLocalFileReader(uri string) (*LocalFileReaderImpl, error) {
@@ -25,7 +27,7 @@ func (reader *LocalFileReaderImpl) ResetReader(ctx context.Context) (io.Reader,
 	if reader.fileHandle != nil {
 		_, err := reader.fileHandle.Seek(0, 0)
 		if err == nil {
-			return reader.fileHandle, nil
+			return reader.contents()
 		}
 		reader.fileHandle.Close()
 	}
@@ -40,7 +42,25 @@ func (reader *LocalFileReaderImpl) CreateReader(_ context.Context) (io.Reader, e
 		return nil, err
 	}
 	reader.fileHandle = f
-	return f, nil
+	return reader.contents()
+}
+
+// contents returns the reader of the contents of the file. Compressed files
+// are decompressed as they are read. Other files are read directly, so that
+// they can also be read at random positions.
+func (reader *LocalFileReaderImpl) contents() (io.Reader, error) {
+	header := make([]byte, 5)
+	n, _ := reader.fileHandle.ReadAt(header, 0)
+	if internal.DetectCompression(reader.uri, header[:n]) == internal.CompressionNone {
+		reader.reader = reader.fileHandle
+		return reader.reader, nil
+	}
+	r, err := internal.Decompress(reader.fileHandle, reader.uri)
+	if err != nil {
+		return nil, err
+	}
+	reader.reader = r
+	return reader.reader, nil
 }
 
 func (reader *LocalFileReaderImpl) Close() {
@@ -56,5 +76,5 @@ func (reader *LocalFileReaderImpl) ReadAll(_ context.Context) ([]byte, error) {
 			return nil, err
 		}
 	}
-	return io.ReadAll(reader.fileHandle)
+	return io.ReadAll(reader.reader)
 }
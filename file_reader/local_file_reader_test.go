@@ -1,9 +1,13 @@
 package file_reader
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"github.com/stretchr/testify/assert"
+	"io"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -260,3 +264,35 @@ func TestLocalFileReaderImpl_ReadAll(t *testing.T) {
 		})
 	}
 }
+
+func TestLocalFileReaderImpl_Compressed(t *testing.T) {
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	w.Write([]byte("a,b\n1,2\n"))
+	assert.NoError(t, w.Close())
+	path := filepath.Join(t.TempDir(), "data.csv.gz")
+	assert.NoError(t, os.WriteFile(path, b.Bytes(), 0644))
+
+	reader, err := NewLocalFileReader(path)
+	assert.NoError(t, err)
+	defer reader.Close()
+
+	r, err := reader.CreateReader(context.Background())
+	assert.NoError(t, err)
+	data, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "a,b\n1,2\n", string(data))
+
+	// The file is decompressed again from its start.
+	r, err = reader.ResetReader(context.Background())
+	assert.NoError(t, err)
+	data, err = io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "a,b\n1,2\n", string(data))
+
+	_, err = reader.ResetReader(context.Background())
+	assert.NoError(t, err)
+	data, err = reader.ReadAll(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "a,b\n1,2\n", string(data))
+}
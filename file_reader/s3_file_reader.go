@@ -0,0 +1,176 @@
+package file_reader
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// emptyPayloadHash is the SHA-256 hash of an empty request body.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3Config configures the access to S3 or S3-compatible object storage,
+// such as MinIO.
+type S3Config struct {
+	// Endpoint is the URL of the storage service, e.g.
+	// "https://minio.example.com:9000". Empty for AWS.
+	Endpoint string
+	Region   string
+	// Credentials sign the requests, or are nil for unsigned requests to
+	// public buckets.
+	Credentials aws.CredentialsProvider
+}
+
+// GetS3Config returns the configuration of S3 from the standard AWS
+// configuration, as the AWS SDK loads it: environment variables, shared
+// config and credentials files, SSO, and the roles of ECS tasks and EC2
+// instances. Requests are not signed if no credentials are found, for
+// public buckets.
+var GetS3Config = func(ctx context.Context) (S3Config, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return S3Config{}, fmt.Errorf("can't load AWS configuration: %w", err)
+	}
+	s3Config := S3Config{Endpoint: os.Getenv("AWS_ENDPOINT_URL_S3"), Region: cfg.Region, Credentials: cfg.Credentials}
+	if s3Config.Endpoint == "" && cfg.BaseEndpoint != nil {
+		s3Config.Endpoint = *cfg.BaseEndpoint
+	}
+	if s3Config.Region == "" {
+		s3Config.Region = "us-east-1"
+	}
+	if _, err := cfg.Credentials.Retrieve(ctx); err != nil {
+		logger.Log.Debug(fmt.Sprintf("No AWS credentials found, s3 requests are not signed: %v", err))
+		s3Config.Credentials = nil
+	}
+	return s3Config, nil
+}
+
+// S3FileReaderImpl reads an object of S3 or of S3-compatible object
+// storage, with the http reader.
+type S3FileReaderImpl struct {
+	HttpFileReaderImpl
+	bucket string
+	key    string
+}
+
+func NewS3FileReader(ctx context.Context, uri, host, path string) (*S3FileReaderImpl, error) {
+	s3Config, err := GetS3Config(ctx)
+	if err != nil {
+		return nil, err
+	}
+	objectUrl, err := s3ObjectUrl(s3Config, host, strings.TrimPrefix(path, "/"))
+	if err != nil {
+		return nil, err
+	}
+	object := &httpObject{url: objectUrl, sign: s3Signer(s3Config)}
+	reader := &S3FileReaderImpl{
+		HttpFileReaderImpl: HttpFileReaderImpl{uri: uri, object: object},
+		bucket:             host,
+		key:                strings.TrimPrefix(path, "/"), // removes "/" from beginning of path
+	}
+	if err := object.stat(ctx); err != nil {
+		return nil, err
+	}
+	return reader, nil
+}
+
+// s3Signer returns the function signing the requests of config, or nil
+// if it has no credentials.
+func s3Signer(config S3Config) func(req *http.Request) error {
+	if config.Credentials == nil {
+		return nil
+	}
+	return func(req *http.Request) error {
+		creds, err := config.Credentials.Retrieve(req.Context())
+		if err != nil {
+			return fmt.Errorf("can't get AWS credentials: %w", err)
+		}
+		return signS3Request(req, config.Region, creds, time.Now())
+	}
+}
+
+// s3ObjectUrl returns the URL of an object.
+func s3ObjectUrl(config S3Config, bucket, key string) (string, error) {
+	if bucket == "" || key == "" {
+		return "", fmt.Errorf("s3 uri must be of the form s3://bucket/key")
+	}
+	bucketUrl, err := s3BucketUrl(config, bucket)
+	if err != nil {
+		return "", err
+	}
+	return bucketUrl + "/" + s3EscapePath(key), nil
+}
+
+// s3BucketUrl returns the URL of a bucket, without a trailing "/". Buckets
+// of a custom endpoint are addressed in the path, as MinIO expects, and
+// AWS buckets in the host name, unless their name has dots, which don't
+// match the certificate.
+func s3BucketUrl(config S3Config, bucket string) (string, error) {
+	if config.Endpoint != "" {
+		endpoint, err := url.Parse(config.Endpoint)
+		if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+			return "", fmt.Errorf("invalid s3 endpoint %q", config.Endpoint)
+		}
+		return strings.TrimSuffix(config.Endpoint, "/") + "/" + s3EscapePath(bucket), nil
+	}
+	if strings.Contains(bucket, ".") {
+		return fmt.Sprintf("https://s3.%s.amazonaws.com/%s", config.Region, s3EscapePath(bucket)), nil
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucket, config.Region), nil
+}
+
+// s3EscapePath escapes the characters of an object key other than the
+// unreserved characters and '/', as S3 signatures expect.
+func s3EscapePath(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("-_.~/", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// signS3Request signs req with the AWS Signature Version 4, at time t.
+func signS3Request(req *http.Request, region string, creds aws.Credentials, t time.Time) error {
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+	// S3 expects the path as it is escaped, not escaped twice as other
+	// services do.
+	signer := v4.NewSigner(func(o *v4.SignerOptions) { o.DisableURIPathEscaping = true })
+	return signer.SignHTTP(req.Context(), creds, req, emptyPayloadHash, "s3", region, t)
+}
+
+// s3EncodeQuery encodes query in the canonical form of S3 signatures:
+// sorted by key, with all the characters other than the unreserved
+// characters escaped.
+func s3EncodeQuery(query url.Values) string {
+	var keys []string
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var params []string
+	for _, key := range keys {
+		for _, value := range query[key] {
+			params = append(params, s3EscapeQuery(key)+"="+s3EscapeQuery(value))
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+func s3EscapeQuery(s string) string {
+	return strings.ReplaceAll(s3EscapePath(s), "/", "%2F")
+}
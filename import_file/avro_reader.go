@@ -0,0 +1,368 @@
+package import_file
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/linkedin/goavro/v2"
+)
+
+// avroSchema is the part of an Avro schema used to import its values.
+type avroSchema struct {
+	Type        string
+	Name        string // Full name of records, enums and fixed.
+	LogicalType string
+	Precision   int
+	Scale       int
+	Fields      []avroField   // Fields of a record.
+	Items       *avroSchema   // Items of an array.
+	Values      *avroSchema   // Values of a map.
+	Branches    []*avroSchema // Branches of a union.
+}
+
+type avroField struct {
+	Name   string
+	Schema *avroSchema
+}
+
+// avroReader reads the records of an Avro object container file.
+type avroReader struct {
+	ocf    *goavro.OCFReader
+	schema *avroSchema
+}
+
+func newAvroReader(r io.Reader) (*avroReader, error) {
+	ocf, err := goavro.NewOCFReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("can't read avro file: %w", err)
+	}
+	var s interface{}
+	if err := json.Unmarshal(ocf.MetaData()["avro.schema"], &s); err != nil {
+		return nil, fmt.Errorf("can't parse avro schema: %w", err)
+	}
+	schema, err := parseAvroSchema(s, "", map[string]*avroSchema{})
+	if err != nil {
+		return nil, fmt.Errorf("can't parse avro schema: %w", err)
+	}
+	if schema.Type != "record" {
+		return nil, fmt.Errorf("avro schema is a %s, expected a record", schema.Type)
+	}
+	return &avroReader{ocf: ocf, schema: schema}, nil
+}
+
+func (ar *avroReader) Close() error {
+	return nil
+}
+
+func (ar *avroReader) Columns() ([]recordColumn, error) {
+	var cols []recordColumn
+	for _, f := range ar.schema.Fields {
+		cols = append(cols, recordColumn{Name: f.Name, T: avroType(f.Schema), NotNull: !avroNullable(f.Schema)})
+	}
+	return cols, nil
+}
+
+func (ar *avroReader) Next() (map[string]interface{}, error) {
+	if !ar.ocf.Scan() {
+		if err := ar.ocf.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	datum, err := ar.ocf.Read()
+	if err != nil {
+		return nil, err
+	}
+	m, ok := datum.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("avro datum is a %T, expected a record", datum)
+	}
+	record := make(map[string]interface{}, len(ar.schema.Fields))
+	for _, f := range ar.schema.Fields {
+		v, err := avroValue(f.Schema, m[f.Name])
+		if err != nil {
+			return nil, &recordValueError{fmt.Errorf("can't read value of column %s: %w", f.Name, err)}
+		}
+		record[f.Name] = v
+	}
+	return record, nil
+}
+
+// parseAvroSchema parses the JSON form of an Avro schema. names holds the
+// named types defined so far, by full name.
+func parseAvroSchema(s interface{}, namespace string, names map[string]*avroSchema) (*avroSchema, error) {
+	switch x := s.(type) {
+	case string:
+		if n, ok := names[x]; ok {
+			return n, nil
+		}
+		if n, ok := names[namespace+"."+x]; ok {
+			return n, nil
+		}
+		switch x {
+		case "null", "boolean", "int", "long", "float", "double", "bytes", "string":
+			return &avroSchema{Type: x}, nil
+		}
+		return nil, fmt.Errorf("unknown type %q", x)
+	case []interface{}:
+		u := &avroSchema{Type: "union"}
+		for _, b := range x {
+			branch, err := parseAvroSchema(b, namespace, names)
+			if err != nil {
+				return nil, err
+			}
+			u.Branches = append(u.Branches, branch)
+		}
+		return u, nil
+	case map[string]interface{}:
+		t, _ := x["type"].(string)
+		if t == "" {
+			// The type is itself a schema, e.g. {"type": {"type": "array", ...}}.
+			return parseAvroSchema(x["type"], namespace, names)
+		}
+		lt, _ := x["logicalType"].(string)
+		precision, _ := x["precision"].(float64)
+		scale, _ := x["scale"].(float64)
+		switch t {
+		case "record", "error", "enum", "fixed":
+			s := &avroSchema{Type: t, LogicalType: lt, Precision: int(precision), Scale: int(scale)}
+			s.Name, namespace = avroFullName(x, namespace)
+			names[s.Name] = s
+			if t == "enum" || t == "fixed" {
+				return s, nil
+			}
+			s.Type = "record"
+			fields, _ := x["fields"].([]interface{})
+			for _, f := range fields {
+				fm, _ := f.(map[string]interface{})
+				name, _ := fm["name"].(string)
+				fs, err := parseAvroSchema(fm["type"], namespace, names)
+				if err != nil {
+					return nil, fmt.Errorf("field %s: %w", name, err)
+				}
+				s.Fields = append(s.Fields, avroField{Name: name, Schema: fs})
+			}
+			return s, nil
+		case "array":
+			items, err := parseAvroSchema(x["items"], namespace, names)
+			if err != nil {
+				return nil, err
+			}
+			return &avroSchema{Type: t, Items: items}, nil
+		case "map":
+			values, err := parseAvroSchema(x["values"], namespace, names)
+			if err != nil {
+				return nil, err
+			}
+			return &avroSchema{Type: t, Values: values}, nil
+		}
+		s, err := parseAvroSchema(t, namespace, names)
+		if err != nil {
+			return nil, err
+		}
+		if lt == "" {
+			return s, nil
+		}
+		return &avroSchema{Type: s.Type, LogicalType: lt, Precision: int(precision), Scale: int(scale)}, nil
+	}
+	return nil, fmt.Errorf("invalid schema %v", s)
+}
+
+// avroFullName returns the full name of a named type, and the namespace
+// of the names it defines.
+func avroFullName(x map[string]interface{}, namespace string) (string, string) {
+	name, _ := x["name"].(string)
+	if ns, ok := x["namespace"].(string); ok && !strings.Contains(name, ".") {
+		namespace = ns
+	}
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name, name[:i]
+	}
+	if namespace == "" {
+		return name, namespace
+	}
+	return namespace + "." + name, namespace
+}
+
+// avroNullable returns true if s is null or a union with null.
+func avroNullable(s *avroSchema) bool {
+	if s.Type == "null" {
+		return true
+	}
+	for _, b := range s.Branches {
+		if b.Type == "null" {
+			return true
+		}
+	}
+	return false
+}
+
+// avroNonNull returns the branches of a union other than null.
+func avroNonNull(s *avroSchema) []*avroSchema {
+	var branches []*avroSchema
+	for _, b := range s.Branches {
+		if b.Type != "null" {
+			branches = append(branches, b)
+		}
+	}
+	return branches
+}
+
+// avroType returns the Spanner type of the values of s.
+func avroType(s *avroSchema) ddl.Type {
+	switch s.Type {
+	case "union":
+		branches := avroNonNull(s)
+		switch len(branches) {
+		case 0:
+			return ddl.Type{Name: ddl.String, Len: ddl.MaxLength}
+		case 1:
+			return avroType(branches[0])
+		}
+		return ddl.Type{Name: ddl.JSON}
+	case "boolean":
+		return ddl.Type{Name: ddl.Bool}
+	case "int", "long":
+		switch s.LogicalType {
+		case "date":
+			return ddl.Type{Name: ddl.Date}
+		case "timestamp-millis", "timestamp-micros", "timestamp-nanos", "local-timestamp-millis", "local-timestamp-micros", "local-timestamp-nanos":
+			return ddl.Type{Name: ddl.Timestamp}
+		case "time-millis", "time-micros":
+			return ddl.Type{Name: ddl.String, Len: ddl.MaxLength}
+		}
+		return ddl.Type{Name: ddl.Int64}
+	case "float":
+		return ddl.Type{Name: ddl.Float32}
+	case "double":
+		return ddl.Type{Name: ddl.Float64}
+	case "string", "enum", "null":
+		if s.LogicalType == "uuid" {
+			return ddl.Type{Name: ddl.String, Len: 36}
+		}
+		return ddl.Type{Name: ddl.String, Len: ddl.MaxLength}
+	case "bytes", "fixed":
+		if s.LogicalType == "decimal" {
+			return numericType(s.Precision, s.Scale)
+		}
+		return ddl.Type{Name: ddl.Bytes, Len: ddl.MaxLength}
+	case "array":
+		return arrayType(avroType(s.Items))
+	}
+	return ddl.Type{Name: ddl.JSON}
+}
+
+// avroValue converts a value decoded by goavro to a record value.
+func avroValue(s *avroSchema, v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	switch s.Type {
+	case "union":
+		// goavro decodes a non-null union value as a map from the name of
+		// its branch to the value.
+		m, ok := v.(map[string]interface{})
+		if !ok || len(m) != 1 {
+			return nil, fmt.Errorf("unexpected union value %v", v)
+		}
+		for name, x := range m {
+			if b := avroBranch(s, name); b != nil {
+				return avroValue(b, x)
+			}
+			return x, nil
+		}
+	case "int", "long":
+		return avroIntValue(s, v)
+	case "array":
+		l, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected array value %v", v)
+		}
+		elems := make([]interface{}, len(l))
+		for i, e := range l {
+			x, err := avroValue(s.Items, e)
+			if err != nil {
+				return nil, err
+			}
+			elems[i] = x
+		}
+		return elems, nil
+	case "map", "record":
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected %s value %v", s.Type, v)
+		}
+		r := make(map[string]interface{}, len(m))
+		for k, e := range m {
+			fs := s.Values
+			for _, f := range s.Fields {
+				if f.Name == k {
+					fs = f.Schema
+				}
+			}
+			x, err := avroValue(fs, e)
+			if err != nil {
+				return nil, err
+			}
+			r[k] = x
+		}
+		return r, nil
+	}
+	return v, nil
+}
+
+// avroBranch returns the branch of union s that goavro names name, or nil
+// if there is none. Logical types are named after their underlying type.
+func avroBranch(s *avroSchema, name string) *avroSchema {
+	for _, b := range s.Branches {
+		if b.Name == name || (b.Name == "" && (b.Type == name || b.Type+"."+b.LogicalType == name)) {
+			return b
+		}
+	}
+	if branches := avroNonNull(s); len(branches) == 1 {
+		return branches[0]
+	}
+	return nil
+}
+
+// avroIntValue converts an int or long value, as decoded by goavro for its
+// logical type, to a record value.
+func avroIntValue(s *avroSchema, v interface{}) (interface{}, error) {
+	var i int64
+	switch x := v.(type) {
+	case int32:
+		i = int64(x)
+	case int64:
+		i = x
+	case time.Time:
+		if s.LogicalType == "date" {
+			return civil.DateOf(x), nil
+		}
+		return x.UTC(), nil
+	case time.Duration:
+		return time.Time{}.Add(x).Format("15:04:05.999999"), nil
+	default:
+		return nil, fmt.Errorf("unexpected %s value %v", s.Type, v)
+	}
+	// Logical types goavro doesn't decode.
+	switch s.LogicalType {
+	case "date":
+		return civil.DateOf(time.Unix(i*24*60*60, 0).UTC()), nil
+	case "timestamp-millis", "local-timestamp-millis":
+		return time.UnixMilli(i).UTC(), nil
+	case "timestamp-micros", "local-timestamp-micros":
+		return time.UnixMicro(i).UTC(), nil
+	case "timestamp-nanos", "local-timestamp-nanos":
+		return time.Unix(0, i).UTC(), nil
+	case "time-millis":
+		return time.Time{}.Add(time.Duration(i) * time.Millisecond).Format("15:04:05.999999"), nil
+	case "time-micros":
+		return time.Time{}.Add(time.Duration(i) * time.Microsecond).Format("15:04:05.999999"), nil
+	}
+	return i, nil
+}
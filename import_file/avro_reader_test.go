@@ -0,0 +1,170 @@
+package import_file
+
+import (
+	"bytes"
+	"io"
+	"math/big"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+const avroTestSchema = `{
+	"type": "record",
+	"name": "Event",
+	"namespace": "com.example",
+	"fields": [
+		{"name": "id", "type": "long"},
+		{"name": "name", "type": ["null", "string"]},
+		{"name": "price", "type": {"type": "bytes", "logicalType": "decimal", "precision": 10, "scale": 2}},
+		{"name": "day", "type": {"type": "int", "logicalType": "date"}},
+		{"name": "created", "type": ["null", {"type": "long", "logicalType": "timestamp-micros"}]},
+		{"name": "tags", "type": {"type": "array", "items": ["null", "string"]}},
+		{"name": "matrix", "type": {"type": "array", "items": {"type": "array", "items": "int"}}},
+		{"name": "attrs", "type": {"type": "map", "values": "long"}},
+		{"name": "kind", "type": {"type": "enum", "name": "Kind", "symbols": ["A", "B"]}},
+		{"name": "key", "type": {"type": "string", "logicalType": "uuid"}},
+		{"name": "score", "type": "float"},
+		{"name": "nested", "type": {"type": "record", "name": "Nested", "fields": [{"name": "a", "type": "int"}]}},
+		{"name": "other", "type": ["null", "Nested"]},
+		{"name": "either", "type": ["int", "string"]}
+	]
+}`
+
+func writeAvroTestFile(t *testing.T, schema string, records []map[string]interface{}) *bytes.Reader {
+	var buf bytes.Buffer
+	w, err := goavro.NewOCFWriter(goavro.OCFConfig{W: &buf, Schema: schema})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data []interface{}
+	for _, r := range records {
+		data = append(data, r)
+	}
+	if err := w.Append(data); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestAvroReader(t *testing.T) {
+	created := time.Date(2024, 3, 4, 5, 6, 7, 123456000, time.UTC)
+	records := []map[string]interface{}{
+		{
+			"id":      int64(1),
+			"name":    goavro.Union("string", "widget"),
+			"price":   big.NewRat(12345, 100),
+			"day":     time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+			"created": goavro.Union("long.timestamp-micros", created),
+			"tags":    []interface{}{goavro.Union("string", "a"), nil},
+			"matrix":  []interface{}{[]interface{}{int32(1), int32(2)}, []interface{}{int32(3)}},
+			"attrs":   map[string]interface{}{"x": int64(1)},
+			"kind":    "B",
+			"key":     "12345678-9abc-def0-1234-56789abcdef0",
+			"score":   float32(1.5),
+			"nested":  map[string]interface{}{"a": int32(7)},
+			"other":   goavro.Union("com.example.Nested", map[string]interface{}{"a": int32(8)}),
+			"either":  goavro.Union("int", int32(9)),
+		},
+		{
+			"id":      int64(2),
+			"name":    nil,
+			"price":   big.NewRat(-1, 100),
+			"day":     time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+			"created": nil,
+			"tags":    []interface{}{},
+			"matrix":  []interface{}{},
+			"attrs":   map[string]interface{}{},
+			"kind":    "A",
+			"key":     "",
+			"score":   float32(0),
+			"nested":  map[string]interface{}{"a": int32(0)},
+			"other":   nil,
+			"either":  goavro.Union("string", "s"),
+		},
+	}
+	r, err := newAvroReader(writeAvroTestFile(t, avroTestSchema, records))
+	assert.NoError(t, err)
+
+	cols, err := r.Columns()
+	assert.NoError(t, err)
+	assert.Equal(t, []recordColumn{
+		{Name: "id", T: ddl.Type{Name: ddl.Int64}, NotNull: true},
+		{Name: "name", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+		{Name: "price", T: ddl.Type{Name: ddl.Numeric}, NotNull: true},
+		{Name: "day", T: ddl.Type{Name: ddl.Date}, NotNull: true},
+		{Name: "created", T: ddl.Type{Name: ddl.Timestamp}},
+		{Name: "tags", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: true}, NotNull: true},
+		{Name: "matrix", T: ddl.Type{Name: ddl.JSON}, NotNull: true},
+		{Name: "attrs", T: ddl.Type{Name: ddl.JSON}, NotNull: true},
+		{Name: "kind", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, NotNull: true},
+		{Name: "key", T: ddl.Type{Name: ddl.String, Len: 36}, NotNull: true},
+		{Name: "score", T: ddl.Type{Name: ddl.Float32}, NotNull: true},
+		{Name: "nested", T: ddl.Type{Name: ddl.JSON}, NotNull: true},
+		{Name: "other", T: ddl.Type{Name: ddl.JSON}},
+		{Name: "either", T: ddl.Type{Name: ddl.JSON}, NotNull: true},
+	}, cols)
+
+	record, err := r.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"id":      int64(1),
+		"name":    "widget",
+		"price":   big.NewRat(12345, 100),
+		"day":     civil.Date{Year: 2024, Month: 3, Day: 4},
+		"created": created,
+		"tags":    []interface{}{"a", nil},
+		"matrix":  []interface{}{[]interface{}{int64(1), int64(2)}, []interface{}{int64(3)}},
+		"attrs":   map[string]interface{}{"x": int64(1)},
+		"kind":    "B",
+		"key":     "12345678-9abc-def0-1234-56789abcdef0",
+		"score":   float32(1.5),
+		"nested":  map[string]interface{}{"a": int64(7)},
+		"other":   map[string]interface{}{"a": int64(8)},
+		"either":  int64(9),
+	}, record)
+
+	record, err = r.Next()
+	assert.NoError(t, err)
+	assert.Nil(t, record["name"])
+	assert.Nil(t, record["created"])
+	assert.Nil(t, record["other"])
+	assert.Equal(t, civil.Date{Year: 1970, Month: 1, Day: 1}, record["day"])
+	assert.Equal(t, "s", record["either"])
+
+	_, err = r.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestAvroReader_NotRecord(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := goavro.NewOCFWriter(goavro.OCFConfig{W: &buf, Schema: `"long"`})
+	assert.NoError(t, err)
+	assert.NoError(t, w.Append([]interface{}{int64(1)}))
+	_, err = newAvroReader(bytes.NewReader(buf.Bytes()))
+	assert.Error(t, err)
+}
+
+func TestAvroIntValue(t *testing.T) {
+	tests := []struct {
+		logicalType string
+		v           interface{}
+		want        interface{}
+	}{
+		{"", int32(5), int64(5)},
+		{"date", int32(1), civil.Date{Year: 1970, Month: 1, Day: 2}},
+		{"timestamp-millis", int64(1500), time.Unix(1, 500000000).UTC()},
+		{"local-timestamp-micros", int64(1500000), time.Unix(1, 500000000).UTC()},
+		{"time-millis", int32(3723004), "01:02:03.004"},
+		{"time-micros", 3723*time.Second + 5*time.Microsecond, "01:02:03.000005"},
+	}
+	for _, tc := range tests {
+		got, err := avroIntValue(&avroSchema{Type: "long", LogicalType: tc.logicalType}, tc.v)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.want, got, tc.logicalType)
+	}
+}
@@ -176,7 +176,8 @@ func (m MockCsvInterface) ProcessSingleCSV(conv *internal.Conv, tableName string
 	return m.MockProcessSingleCSV(conv, tableName, columnNames, colDefs, sourceIoReader, nullStr, delimiter)
 }
 
-func (m *MockInfoSchemaInterface) ProcessData(conv *internal.Conv, infoSchema common.InfoSchema, additionalAttributes internal.AdditionalDataAttributes) {
+func (m *MockInfoSchemaInterface) ProcessData(conv *internal.Conv, infoSchema common.InfoSchema, additionalAttributes internal.AdditionalDataAttributes) error {
+	return nil
 }
 
 func (m *MockInfoSchemaInterface) SetRowStats(conv *internal.Conv, infoSchema common.InfoSchema) {
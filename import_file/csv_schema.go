@@ -48,7 +48,8 @@ type ColumnDefinition struct {
 	Name    string `json:"name"`
 	Type    string `json:"type"` // e.g., "INT64", "STRING(MAX)", "TIMESTAMP", "DATE"
 	NotNull bool   `json:"notNull"`
-	PkOrder int    `json:"primaryKeyOrder"` // defines the order in the PK for the table, 0 means absence.
+	PkOrder int    `json:"primaryKeyOrder"`   // defines the order in the PK for the table, 0 means absence.
+	Default string `json:"default,omitempty"` // e.g., "GENERATE_UUID()", the expression of the default value.
 }
 
 type PrimaryKey struct {
@@ -116,7 +117,7 @@ func parseSchema(schemaFile []byte) ([]ColumnDefinition, error) {
 	var colDefs []ColumnDefinition
 	for _, column := range schema {
 
-		colDef := ColumnDefinition{column.Name, column.Type, column.NotNull, column.PkOrder}
+		colDef := ColumnDefinition{column.Name, column.Type, column.NotNull, column.PkOrder, column.Default}
 		colDefs = append(colDefs, colDef)
 	}
 	return colDefs, nil
@@ -163,6 +164,9 @@ func printColumnDef(c ColumnDefinition) string {
 	if c.NotNull {
 		s += " NOT NULL "
 	}
+	if c.Default != "" {
+		s += fmt.Sprintf(" DEFAULT (%s)", c.Default)
+	}
 	return s
 }
 
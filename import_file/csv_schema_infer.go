@@ -0,0 +1,326 @@
+package import_file
+
+import (
+	"bytes"
+	"context"
+	csvReader "encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/file_reader"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/csv"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// DefaultCsvSampleRows is the number of rows read after the header row to
+// infer the schema of a CSV file.
+const DefaultCsvSampleRows = 1000
+
+// maxInferredStringLength is the longest STRING(n) type inferred. Longer
+// columns are STRING(MAX).
+const maxInferredStringLength = 2621440
+
+var decimalRegex = regexp.MustCompile(`^[+-]?([0-9]*)\.?([0-9]*)$`)
+
+var InferCsvSchema = inferCsvSchema
+
+// inferCsvSchema infers the columns of a CSV file from its header row and
+// the sampleRows rows after it. It returns the columns, and a reader of the
+// file whose header row names them.
+//
+// Column names are the header names, made valid Spanner identifiers. The
+// primary key is a column named id, or a first column whose name ends with
+// id, if its sampled values are unique. Otherwise, a synth_id column filled
+// with UUIDs by Spanner is added as the primary key. Types and defaults are
+// written in the syntax of dialect.
+func inferCsvSchema(ctx context.Context, sourceFileReader file_reader.FileReader, delimiter rune, sampleRows int, dialect string) ([]ColumnDefinition, file_reader.FileReader, error) {
+	sourceIoReader, err := sourceFileReader.ResetReader(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't read source file: %w", err)
+	}
+	r := csvReader.NewReader(sourceIoReader)
+	r.Comma = delimiter
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil, fmt.Errorf("can't infer schema of an empty csv file")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't read csv header: %w", err)
+	}
+	headerLength := r.InputOffset()
+
+	cols := make([]csvColumnSample, len(header))
+	for i := range cols {
+		cols[i] = newCsvColumnSample()
+	}
+	for n := 0; n < sampleRows; n++ {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("can't read csv row: %w", err)
+		}
+		for i, v := range row {
+			if i < len(cols) {
+				cols[i].add(v)
+			}
+		}
+	}
+
+	names := csvColumnNames(header)
+	var colDefs []ColumnDefinition
+	for i, name := range names {
+		colDefs = append(colDefs, ColumnDefinition{Name: name, Type: columnType(cols[i].ddlType(), dialect)})
+	}
+	if pk := csvPrimaryKey(names, cols); pk >= 0 {
+		colDefs[pk].NotNull = true
+		colDefs[pk].PkOrder = 1
+	} else {
+		uuid := "GENERATE_UUID()"
+		if dialect == constants.DIALECT_POSTGRESQL {
+			uuid = "spanner.generate_uuid()"
+		}
+		colDefs = append(colDefs, ColumnDefinition{
+			Name:    internal.SyntheticPrimaryKey,
+			Type:    columnType(ddl.Type{Name: ddl.String, Len: 36}, dialect),
+			NotNull: true,
+			PkOrder: 1,
+			Default: uuid,
+		})
+	}
+
+	sourceFileReader, err = newCsvHeaderFileReader(sourceFileReader, header, headerLength, delimiter)
+	if err != nil {
+		return nil, nil, err
+	}
+	return colDefs, sourceFileReader, nil
+}
+
+// renameCsvHeader returns a reader of the CSV file whose header row names
+// the columns inferred from it, as inferCsvSchema does. Files of a table
+// whose schema was inferred from another file are read with it, since
+// their header rows can differ.
+func renameCsvHeader(ctx context.Context, sourceFileReader file_reader.FileReader, delimiter rune) (file_reader.FileReader, error) {
+	sourceIoReader, err := sourceFileReader.ResetReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can't read source file: %w", err)
+	}
+	r := csvReader.NewReader(sourceIoReader)
+	r.Comma = delimiter
+	header, err := r.Read()
+	if err == io.EOF {
+		return sourceFileReader, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't read csv header: %w", err)
+	}
+	return newCsvHeaderFileReader(sourceFileReader, header, r.InputOffset(), delimiter)
+}
+
+// newCsvHeaderFileReader returns a reader of the CSV file whose header row
+// of headerLength bytes names the columns of csvColumnNames. The file is
+// read as it is if the names are those of its header.
+func newCsvHeaderFileReader(sourceFileReader file_reader.FileReader, header []string, headerLength int64, delimiter rune) (file_reader.FileReader, error) {
+	names := csvColumnNames(header)
+	if strings.Join(names, "\x00") == strings.Join(header, "\x00") {
+		return sourceFileReader, nil
+	}
+	var buf bytes.Buffer
+	w := csvReader.NewWriter(&buf)
+	w.Comma = delimiter
+	if err := w.Write(names); err != nil {
+		return nil, err
+	}
+	w.Flush()
+	return &csvHeaderFileReader{FileReader: sourceFileReader, header: buf.Bytes(), headerLength: headerLength}, nil
+}
+
+// csvColumnSample holds the types the sampled values of a column can all
+// be converted to.
+type csvColumnSample struct {
+	types     map[string]bool
+	values    map[string]bool // The distinct non-empty values.
+	count     int             // The number of non-empty values.
+	hasEmpty  bool
+	maxLength int
+}
+
+// csvInferredTypes are the types a column can be inferred as, in order of
+// preference.
+var csvInferredTypes = []string{ddl.Int64, ddl.Numeric, ddl.Float64, ddl.Bool, ddl.Date, ddl.Timestamp, ddl.JSON}
+
+func newCsvColumnSample() csvColumnSample {
+	s := csvColumnSample{types: map[string]bool{}, values: map[string]bool{}}
+	for _, t := range csvInferredTypes {
+		s.types[t] = true
+	}
+	return s
+}
+
+func (s *csvColumnSample) add(v string) {
+	// Empty values are imported as NULL.
+	if v == "" {
+		s.hasEmpty = true
+		return
+	}
+	s.values[v] = true
+	s.count++
+	if n := utf8.RuneCountInString(v); n > s.maxLength {
+		s.maxLength = n
+	}
+	for t, ok := range s.types {
+		if ok && !csvValueHasType(t, v) {
+			s.types[t] = false
+		}
+	}
+}
+
+// csvValueHasType returns true if v can be imported into a column of type t.
+func csvValueHasType(t, v string) bool {
+	switch t {
+	case ddl.Numeric:
+		// Only decimals that fit in a NUMERIC, which has 29 digits before
+		// the decimal point and 9 after it.
+		m := decimalRegex.FindStringSubmatch(v)
+		return m != nil && m[1]+m[2] != "" && len(strings.TrimLeft(m[1], "0")) <= 29 && len(m[2]) <= 9
+	case ddl.Float64:
+		// Not words like inf or nan, which are more likely strings.
+		if !strings.ContainsAny(v, "0123456789") {
+			return false
+		}
+	case ddl.JSON:
+		return (strings.HasPrefix(v, "{") || strings.HasPrefix(v, "[")) && json.Valid([]byte(v))
+	}
+	_, err := csv.ConvertValue(constants.DIALECT_GOOGLESQL, ddl.Type{Name: t}, v)
+	return err == nil
+}
+
+// spannerType returns the type of the column, in the syntax of the schema
+// file. Strings get twice the length of the longest sampled value, for
+// values longer than the sampled ones.
+func (s *csvColumnSample) spannerType() string {
+	return s.ddlType().PrintColumnDefType(false)
+}
+
+func (s *csvColumnSample) ddlType() ddl.Type {
+	if len(s.values) == 0 {
+		return ddl.Type{Name: ddl.String, Len: ddl.MaxLength}
+	}
+	for _, t := range csvInferredTypes {
+		if s.types[t] {
+			return ddl.Type{Name: t}
+		}
+	}
+	n := int64(16)
+	for n < 2*int64(s.maxLength) {
+		n *= 2
+	}
+	if n > maxInferredStringLength {
+		return ddl.Type{Name: ddl.String, Len: ddl.MaxLength}
+	}
+	return ddl.Type{Name: ddl.String, Len: n}
+}
+
+// columnType returns the type of a column of the schema file for dialect.
+func columnType(t ddl.Type, dialect string) string {
+	if dialect == constants.DIALECT_POSTGRESQL {
+		return t.PGPrintColumnDefType(false)
+	}
+	return t.PrintColumnDefType(false)
+}
+
+// csvPrimaryKey returns the index of the primary key column, or -1 if no
+// column can be the key. A key column has unique, non-empty sampled values
+// of type INT64 or STRING(n).
+func csvPrimaryKey(names []string, cols []csvColumnSample) int {
+	isKey := func(i int) bool {
+		t := cols[i].spannerType()
+		return !cols[i].hasEmpty && len(cols[i].values) > 0 && len(cols[i].values) == cols[i].count &&
+			(t == ddl.Int64 || strings.HasPrefix(t, "STRING(") && t != "STRING(MAX)")
+	}
+	for i, name := range names {
+		if strings.EqualFold(name, "id") && isKey(i) {
+			return i
+		}
+	}
+	if len(names) > 0 && strings.HasSuffix(strings.ToLower(names[0]), "id") && isKey(0) {
+		return 0
+	}
+	return -1
+}
+
+// csvColumnNames returns the names of the columns of a CSV header, made
+// valid and unique Spanner identifiers. synth_id is left for the synthetic
+// primary key.
+func csvColumnNames(header []string) []string {
+	names := make([]string, len(header))
+	used := map[string]bool{internal.SyntheticPrimaryKey: true}
+	for i, h := range header {
+		name := strings.Map(func(r rune) rune {
+			if r < utf8.RuneSelf && (r == '_' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9') {
+				return r
+			}
+			return '_'
+		}, strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		if name == "" {
+			name = fmt.Sprintf("column_%d", i+1)
+		} else if c := name[0]; !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z') {
+			name = "c_" + name
+		}
+		if len(name) > 120 {
+			name = name[:120]
+		}
+		base := name
+		for k := 2; used[strings.ToLower(name)]; k++ {
+			name = fmt.Sprintf("%s_%d", base, k)
+		}
+		used[strings.ToLower(name)] = true
+		names[i] = name
+	}
+	return names
+}
+
+// csvHeaderFileReader reads a CSV file with its header row replaced.
+type csvHeaderFileReader struct {
+	file_reader.FileReader
+	header       []byte // The new header row.
+	headerLength int64  // The length of the header row of the file.
+}
+
+func (r *csvHeaderFileReader) CreateReader(ctx context.Context) (io.Reader, error) {
+	sourceIoReader, err := r.FileReader.CreateReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.replaceHeader(sourceIoReader)
+}
+
+func (r *csvHeaderFileReader) ResetReader(ctx context.Context) (io.Reader, error) {
+	sourceIoReader, err := r.FileReader.ResetReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.replaceHeader(sourceIoReader)
+}
+
+func (r *csvHeaderFileReader) ReadAll(ctx context.Context) ([]byte, error) {
+	sourceIoReader, err := r.ResetReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(sourceIoReader)
+}
+
+func (r *csvHeaderFileReader) replaceHeader(sourceIoReader io.Reader) (io.Reader, error) {
+	if _, err := io.CopyN(io.Discard, sourceIoReader, r.headerLength); err != nil {
+		return nil, fmt.Errorf("can't skip csv header: %w", err)
+	}
+	return io.MultiReader(bytes.NewReader(r.header), sourceIoReader), nil
+}
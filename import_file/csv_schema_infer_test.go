@@ -0,0 +1,151 @@
+package import_file
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/file_reader"
+	"github.com/stretchr/testify/assert"
+)
+
+func csvFileReader(data string) *file_reader.MockFileReader {
+	return &file_reader.MockFileReader{
+		CreateReaderFn: func(ctx context.Context) (io.Reader, error) {
+			return strings.NewReader(data), nil
+		},
+		ResetReaderFn: func(ctx context.Context) (io.Reader, error) {
+			return strings.NewReader(data), nil
+		},
+	}
+}
+
+func TestInferCsvSchema(t *testing.T) {
+	data := "id,count,price,ratio,active,day,created,extra,name,empty\n" +
+		"1,10,1.25,1e3,true,2024-03-04,2024-03-04 05:06:07,\"{\"\"a\"\":1}\",alice,\n" +
+		"2,-3,0.5,2.5,false,2024-03-05,2024-03-05T05:06:07Z,[1],bob,\n"
+	sourceReader := csvFileReader(data)
+	colDefs, reader, err := inferCsvSchema(context.Background(), sourceReader, ',', DefaultCsvSampleRows, constants.DIALECT_GOOGLESQL)
+	assert.NoError(t, err)
+	assert.Equal(t, []ColumnDefinition{
+		{Name: "id", Type: "INT64", NotNull: true, PkOrder: 1},
+		{Name: "count", Type: "INT64"},
+		{Name: "price", Type: "NUMERIC"},
+		{Name: "ratio", Type: "FLOAT64"},
+		{Name: "active", Type: "BOOL"},
+		{Name: "day", Type: "DATE"},
+		{Name: "created", Type: "TIMESTAMP"},
+		{Name: "extra", Type: "JSON"},
+		{Name: "name", Type: "STRING(16)"},
+		{Name: "empty", Type: "STRING(MAX)"},
+	}, colDefs)
+	// The header is unchanged, so the file is read as is.
+	assert.Same(t, sourceReader, reader)
+}
+
+func TestInferCsvSchema_SyntheticKey(t *testing.T) {
+	data := "Order Id;1st;order id\n" +
+		"a;x;1\n" +
+		"a;y;2\n"
+	colDefs, reader, err := inferCsvSchema(context.Background(), csvFileReader(data), ';', 2, constants.DIALECT_GOOGLESQL)
+	assert.NoError(t, err)
+	assert.Equal(t, []ColumnDefinition{
+		{Name: "Order_Id", Type: "STRING(16)"},
+		{Name: "c_1st", Type: "STRING(16)"},
+		{Name: "order_id_2", Type: "INT64"},
+		{Name: "synth_id", Type: "STRING(36)", NotNull: true, PkOrder: 1, Default: "GENERATE_UUID()"},
+	}, colDefs)
+
+	// The header row is replaced by the column names.
+	want := "Order_Id;c_1st;order_id_2\na;x;1\na;y;2\n"
+	b, err := reader.ReadAll(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, want, string(b))
+	r, err := reader.CreateReader(context.Background())
+	assert.NoError(t, err)
+	b, err = io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, want, string(b))
+}
+
+func TestInferCsvSchema_PostgreSQL(t *testing.T) {
+	data := "name,count,created\n" +
+		"a,1,2024-03-04 05:06:07\n" +
+		"a,2,2024-03-05 05:06:07\n"
+	colDefs, _, err := inferCsvSchema(context.Background(), csvFileReader(data), ',', DefaultCsvSampleRows, constants.DIALECT_POSTGRESQL)
+	assert.NoError(t, err)
+	assert.Equal(t, []ColumnDefinition{
+		{Name: "name", Type: "VARCHAR(16)"},
+		{Name: "count", Type: "INT8"},
+		{Name: "created", Type: "TIMESTAMPTZ"},
+		{Name: "synth_id", Type: "VARCHAR(36)", NotNull: true, PkOrder: 1, Default: "spanner.generate_uuid()"},
+	}, colDefs)
+}
+
+func TestInferCsvSchema_EmptyFile(t *testing.T) {
+	_, _, err := inferCsvSchema(context.Background(), csvFileReader(""), ',', DefaultCsvSampleRows, constants.DIALECT_GOOGLESQL)
+	assert.Error(t, err)
+}
+
+func TestCsvColumnSample_spannerType(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   string
+	}{
+		{name: "no values", values: []string{"", ""}, want: "STRING(MAX)"},
+		{name: "int64 with nulls", values: []string{"1", "", "-2"}, want: "INT64"},
+		{name: "int64 overflow", values: []string{"99999999999999999999"}, want: "NUMERIC"},
+		{name: "numeric scale too large", values: []string{"0.1234567890"}, want: "FLOAT64"},
+		{name: "float64 words", values: []string{"nan", "inf"}, want: "STRING(16)"},
+		{name: "bool", values: []string{"TRUE", "false"}, want: "BOOL"},
+		{name: "json scalar", values: []string{"\"a\""}, want: "STRING(16)"},
+		{name: "mixed", values: []string{"1", "a"}, want: "STRING(16)"},
+		{name: "long string", values: []string{strings.Repeat("é", 20)}, want: "STRING(64)"},
+		{name: "very long string", values: []string{strings.Repeat("a", 2000000)}, want: "STRING(MAX)"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newCsvColumnSample()
+			for _, v := range tc.values {
+				s.add(v)
+			}
+			assert.Equal(t, tc.want, s.spannerType())
+		})
+	}
+}
+
+func TestCsvPrimaryKey(t *testing.T) {
+	sample := func(values ...string) csvColumnSample {
+		s := newCsvColumnSample()
+		for _, v := range values {
+			s.add(v)
+		}
+		return s
+	}
+	tests := []struct {
+		name  string
+		names []string
+		cols  []csvColumnSample
+		want  int
+	}{
+		{name: "id column", names: []string{"name", "ID"}, cols: []csvColumnSample{sample("a", "b"), sample("1", "2")}, want: 1},
+		{name: "first column ending with id", names: []string{"user_id", "name"}, cols: []csvColumnSample{sample("u1", "u2"), sample("a", "a")}, want: 0},
+		{name: "duplicate values", names: []string{"id"}, cols: []csvColumnSample{sample("1", "1")}, want: -1},
+		{name: "empty values", names: []string{"id"}, cols: []csvColumnSample{sample("1", "")}, want: -1},
+		{name: "float values", names: []string{"id"}, cols: []csvColumnSample{sample("1.5", "2.5")}, want: -1},
+		{name: "no key name", names: []string{"name"}, cols: []csvColumnSample{sample("a", "b")}, want: -1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, csvPrimaryKey(tc.names, tc.cols))
+		})
+	}
+}
+
+func TestCsvColumnNames(t *testing.T) {
+	got := csvColumnNames([]string{"\ufeffid", " first name ", "", "2nd", "Synth_Id", "ID", strings.Repeat("a", 130)})
+	assert.Equal(t, []string{"id", "first_name", "column_3", "c_2nd", "Synth_Id_2", "ID_2", strings.Repeat("a", 120)}, got)
+}
@@ -183,8 +183,8 @@ func Test_getCreateTableStmt(t *testing.T) {
 			name:      "standard create table",
 			tableName: "test_table",
 			colDef: []ColumnDefinition{
-				{"col1", "INT64", true, 1},
-				{"col2", "STRING(MAX)", false, 2},
+				{"col1", "INT64", true, 1, ""},
+				{"col2", "STRING(MAX)", false, 2, ""},
 			},
 			dialect: constants.DIALECT_GOOGLESQL,
 			want:    "CREATE TABLE `test_table` (`col1` INT64 NOT NULL ,`col2` STRING(MAX)) PRIMARY KEY (`col1`,`col2`)",
@@ -193,8 +193,8 @@ func Test_getCreateTableStmt(t *testing.T) {
 			name:      "Postgres Dialect",
 			tableName: "test_table",
 			colDef: []ColumnDefinition{
-				{"col1", "INT64", true, 1},
-				{"col2", "STRING(MAX)", false, 2},
+				{"col1", "INT64", true, 1, ""},
+				{"col2", "STRING(MAX)", false, 2, ""},
 			},
 			dialect: constants.DIALECT_POSTGRESQL,
 			want:    "CREATE TABLE `test_table` (`col1` INT64 NOT NULL ,`col2` STRING(MAX)) PRIMARY KEY (`col1`,`col2`)",
@@ -227,6 +227,11 @@ func Test_printColumnDef(t *testing.T) {
 			c:    ColumnDefinition{Name: "col2", Type: "STRING(MAX)", NotNull: false},
 			want: "`col2` STRING(MAX)",
 		},
+		{
+			name: "default",
+			c:    ColumnDefinition{Name: "synth_id", Type: "STRING(36)", NotNull: true, Default: "GENERATE_UUID()"},
+			want: "`synth_id` STRING(36) NOT NULL  DEFAULT (GENERATE_UUID())",
+		},
 	}
 
 	for _, tt := range tests {
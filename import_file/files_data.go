@@ -0,0 +1,252 @@
+package import_file
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/task"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/file_reader"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/csv"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/writer"
+)
+
+// DefaultFileConcurrency is the number of files imported at once.
+const DefaultFileConcurrency = 4
+
+// ManifestTable is a table of the manifest of an import: the files matched
+// by its file patterns are imported into it. The manifest has the format of
+// the manifest of the csv source, with an optional schema file per table.
+type ManifestTable struct {
+	utils.ManifestTable
+	Schema_uri string `json:"schema_uri,omitempty"`
+}
+
+// ParseManifest parses a json manifest, a list of tables.
+func ParseManifest(data []byte) ([]ManifestTable, error) {
+	var tables []ManifestTable
+	if err := json.Unmarshal(data, &tables); err != nil {
+		return nil, fmt.Errorf("unable to unmarshall json due to: %v", err)
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("no tables found")
+	}
+	for i, table := range tables {
+		if table.Table_name == "" {
+			return nil, fmt.Errorf("table number %d (0-indexed) does not have a name", i)
+		}
+		if len(table.File_patterns) == 0 {
+			return nil, fmt.Errorf("no file path provided for table %s", table.Table_name)
+		}
+	}
+	return tables, nil
+}
+
+// ExpandFilePatterns returns the files matched by patterns, in order and
+// without duplicates. Patterns that are not globs or prefixes are files.
+// Each pattern must match at least one file.
+func ExpandFilePatterns(ctx context.Context, patterns []string) ([]string, error) {
+	var files []string
+	seen := map[string]bool{}
+	for _, pattern := range patterns {
+		matches := []string{pattern}
+		if file_reader.IsFilePattern(pattern) {
+			var err error
+			matches, err = file_reader.ListFiles(ctx, pattern)
+			if err != nil {
+				return nil, err
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no files match %s", pattern)
+			}
+		}
+		for _, file := range matches {
+			if !seen[file] {
+				seen[file] = true
+				files = append(files, file)
+			}
+		}
+	}
+	return files, nil
+}
+
+// FileImport is a file to import into a table.
+type FileImport struct {
+	TableName string
+	SourceUri string
+	// Whether the header row of the csv file is renamed to the column
+	// names inferred from it, for tables whose schema was inferred.
+	RenameCsvHeader bool
+}
+
+// FileImportStatus is the outcome of the import of a file.
+type FileImportStatus struct {
+	FileImport
+	Rows        int64 // Rows written to Spanner.
+	BadRows     int64 // Rows that couldn't be converted to the types of the table.
+	DroppedRows int64 // Rows that were converted, but couldn't be written to Spanner.
+	Duration    time.Duration
+	Err         error
+}
+
+var NewFilesData = newFilesData
+
+// FilesData imports several files into tables that already exist.
+type FilesData interface {
+	ImportData(ctx context.Context, spannerInfoSchema *spanner.InfoSchemaImpl, dialect string, commonInfoSchema common.InfoSchemaInterface) ([]FileImportStatus, error)
+}
+
+type FilesDataImpl struct {
+	ProjectId         string
+	InstanceId        string
+	DbName            string
+	SourceFormat      string
+	CsvFieldDelimiter string
+	Files             []FileImport
+	Concurrency       int
+}
+
+func newFilesData(projectId, instanceId, dbName, sourceFormat, csvFieldDelimiter string, files []FileImport, concurrency int) FilesData {
+	return &FilesDataImpl{
+		ProjectId:         projectId,
+		InstanceId:        instanceId,
+		DbName:            dbName,
+		SourceFormat:      sourceFormat,
+		CsvFieldDelimiter: csvFieldDelimiter,
+		Files:             files,
+		Concurrency:       concurrency,
+	}
+}
+
+// ImportData imports the files, Concurrency at a time. The rows of all the
+// files are written by one batch writer, so that its limits on buffered
+// bytes and in-progress writes hold for the whole import. Returns the
+// status of each file, in the order of Files, and an error if rows
+// couldn't be written to Spanner.
+func (source *FilesDataImpl) ImportData(ctx context.Context, spannerInfoSchema *spanner.InfoSchemaImpl, dialect string, commonInfoSchema common.InfoSchemaInterface) ([]FileImportStatus, error) {
+	conv := getConvObject(source.ProjectId, source.InstanceId, dialect, internal.MakeConv())
+	err := spannerInfoSchema.PopulateSpannerSchema(ctx, conv, commonInfoSchema)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("Unable to read Spanner schema %v", err))
+		return nil, err
+	}
+	batchWriter := writer.GetSyncBatchWriterWithConfig(ctx, spannerInfoSchema.SpannerClient)
+	statuses := source.importFiles(ctx, conv, batchWriter)
+	dropped := int64(0)
+	for table, n := range batchWriter.DroppedRowsByTable() {
+		logger.Log.Warn(fmt.Sprintf("%d rows of table %s could not be written to Spanner", n, table))
+		dropped += n
+	}
+	if dropped > 0 {
+		return statuses, fmt.Errorf("%d rows could not be written to Spanner", dropped)
+	}
+	return statuses, nil
+}
+
+// importFiles imports the files concurrently, and flushes batchWriter. The
+// status of a file is only final once batchWriter is flushed, since its
+// rows may be dropped by writes that are still in progress.
+func (source *FilesDataImpl) importFiles(ctx context.Context, conv *internal.Conv, batchWriter *writer.SyncBatchWriter) []FileImportStatus {
+	concurrency := source.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	indexes := make([]int, len(source.Files))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	statuses := make([]FileImportStatus, len(source.Files))
+	dropped := make([]int64, len(source.Files))
+	runner := &task.RunParallelTasksImpl[int, FileImportStatus]{}
+	// Tasks don't fail fast, so that a file that fails doesn't stop the
+	// import of the others.
+	runner.RunParallelTasks(indexes, concurrency, func(i int, mutex *sync.Mutex) task.TaskResult[FileImportStatus] {
+		// Each task sets the status of its own file.
+		statuses[i] = source.importFile(ctx, conv, batchWriter, source.Files[i], &dropped[i])
+		return task.TaskResult[FileImportStatus]{Result: statuses[i], Err: statuses[i].Err}
+	}, false)
+	batchWriter.Flush()
+	for i := range statuses {
+		if dropped[i] == 0 {
+			continue
+		}
+		status := &statuses[i]
+		status.Rows -= dropped[i]
+		status.DroppedRows = dropped[i]
+		if status.Err == nil {
+			status.Err = fmt.Errorf("%d rows could not be written to Spanner", dropped[i])
+		}
+		logger.Log.Error(fmt.Sprintf("Unable to write %d rows of file %s to table %s", dropped[i], status.SourceUri, status.TableName))
+	}
+	return statuses
+}
+
+// importFile imports a file with its own conv, whose rows are added to
+// batchWriter. The Spanner schema of conv is shared, and only read. The
+// rows of the file that batchWriter drops are counted in dropped.
+func (source *FilesDataImpl) importFile(ctx context.Context, conv *internal.Conv, batchWriter *writer.SyncBatchWriter, file FileImport, dropped *int64) FileImportStatus {
+	startTime := time.Now()
+	status := FileImportStatus{FileImport: file}
+	fileConv := getConvObject(source.ProjectId, source.InstanceId, conv.SpDialect, internal.MakeConv())
+	fileConv.SpSchema = conv.SpSchema
+	batchWriter.SetDataSink(fileConv, dropped)
+
+	status.Err = source.processFile(ctx, fileConv, file)
+	status.Rows = fileConv.Stats.GoodRows[file.TableName]
+	status.BadRows = fileConv.BadRows()
+	status.Duration = time.Since(startTime)
+	if status.Err != nil {
+		logger.Log.Error(fmt.Sprintf("Unable to import file %s into table %s: %v", file.SourceUri, file.TableName, status.Err))
+	} else {
+		logger.Log.Info(fmt.Sprintf("Converted file %s for table %s: %d rows, %d bad rows", file.SourceUri, file.TableName, status.Rows, status.BadRows))
+	}
+	return status
+}
+
+func (source *FilesDataImpl) processFile(ctx context.Context, conv *internal.Conv, file FileImport) error {
+	tableId, err := internal.GetTableIdFromSpName(conv.SpSchema, file.TableName)
+	if err != nil {
+		return fmt.Errorf("table %s not found in Spanner", file.TableName)
+	}
+	sourceFileReader, err := file_reader.NewFileReader(ctx, file.SourceUri)
+	if err != nil {
+		return fmt.Errorf("can't open source file: %w", err)
+	}
+	defer sourceFileReader.Close()
+	if source.SourceFormat == constants.CSV && file.RenameCsvHeader {
+		sourceFileReader, err = renameCsvHeader(ctx, sourceFileReader, rune(source.CsvFieldDelimiter[0]))
+		if err != nil {
+			return err
+		}
+	}
+	sourceIoReader, err := sourceFileReader.ResetReader(ctx)
+	if err != nil {
+		return fmt.Errorf("can't read source file: %w", err)
+	}
+
+	spTable := conv.SpSchema[tableId]
+	switch source.SourceFormat {
+	case constants.CSV:
+		columnNames := []string{}
+		for _, v := range spTable.ColIds {
+			columnNames = append(columnNames, spTable.ColDefs[v].Name)
+		}
+		return (&csv.CsvImpl{}).ProcessSingleCSV(conv, file.TableName, columnNames,
+			spTable.ColDefs, sourceIoReader, "", rune(source.CsvFieldDelimiter[0]))
+	default:
+		reader, err := newRecordReader(source.SourceFormat, sourceIoReader)
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+		return processRecords(conv, file.TableName, spTable, reader)
+	}
+}
@@ -0,0 +1,267 @@
+package import_file
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	sp "cloud.google.com/go/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/file_reader"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/writer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseManifest(t *testing.T) {
+	tables, err := ParseManifest([]byte(`[
+		{"table_name": "Singers", "file_patterns": ["gs://bucket/singers/part-*.csv"], "schema_uri": "singers.json"},
+		{"table_name": "Albums", "file_patterns": ["albums-0.csv", "albums-1.csv"]}
+	]`))
+	assert.NoError(t, err)
+	assert.Equal(t, []ManifestTable{
+		{ManifestTable: utils.ManifestTable{Table_name: "Singers", File_patterns: []string{"gs://bucket/singers/part-*.csv"}}, Schema_uri: "singers.json"},
+		{ManifestTable: utils.ManifestTable{Table_name: "Albums", File_patterns: []string{"albums-0.csv", "albums-1.csv"}}},
+	}, tables)
+
+	for _, manifest := range []string{
+		`{"table_name": "Singers"}`,
+		`[]`,
+		`[{"file_patterns": ["singers.csv"]}]`,
+		`[{"table_name": "Singers", "file_patterns": []}]`,
+	} {
+		_, err := ParseManifest([]byte(manifest))
+		assert.Error(t, err, manifest)
+	}
+}
+
+func TestExpandFilePatterns(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"part-0000.csv", "part-0001.csv"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("a\n1\n"), 0644))
+	}
+	ctx := context.Background()
+
+	files, err := ExpandFilePatterns(ctx, []string{filepath.Join(dir, "part-0001.csv"), filepath.Join(dir, "part-*.csv"), "other.csv"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "part-0001.csv"), filepath.Join(dir, "part-0000.csv"), "other.csv"}, files)
+
+	_, err = ExpandFilePatterns(ctx, []string{filepath.Join(dir, "missing-*.csv")})
+	assert.Error(t, err)
+}
+
+func TestFilesDataImpl_importFiles(t *testing.T) {
+	dir := t.TempDir()
+	for i, name := range []string{"part-0000.csv", "part-0001.csv", "part-0002.csv"} {
+		data := "SingerId,Name\n"
+		for j := 0; j < 10; j++ {
+			data += fmt.Sprintf("%d,n\n", i*10+j)
+		}
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0644))
+	}
+	var lock sync.Mutex
+	written := 0
+	batchWriter := writer.NewSyncBatchWriter(writer.NewBatchWriter(writer.BatchWriterConfig{
+		BytesLimit: 100,
+		WriteLimit: 2,
+		RetryLimit: 1,
+		Write: func(m []*sp.Mutation) error {
+			lock.Lock()
+			defer lock.Unlock()
+			written += len(m)
+			return nil
+		},
+	}))
+	source := &FilesDataImpl{
+		SourceFormat:      constants.CSV,
+		CsvFieldDelimiter: ",",
+		Files: []FileImport{
+			{TableName: "Singers", SourceUri: filepath.Join(dir, "part-0000.csv")},
+			{TableName: "Singers", SourceUri: filepath.Join(dir, "part-0001.csv")},
+			{TableName: "Albums", SourceUri: filepath.Join(dir, "part-0002.csv")},
+			{TableName: "Singers", SourceUri: filepath.Join(dir, "part-0002.csv")},
+		},
+		Concurrency: 2,
+	}
+
+	statuses := source.importFiles(context.Background(), getSingersConv(), batchWriter)
+	assert.Len(t, statuses, 4)
+	for i, status := range statuses {
+		assert.Equal(t, source.Files[i], status.FileImport)
+		if status.TableName == "Albums" {
+			assert.ErrorContains(t, status.Err, "not found in Spanner")
+			continue
+		}
+		assert.NoError(t, status.Err)
+		assert.Equal(t, int64(10), status.Rows)
+	}
+	assert.Equal(t, 30, written)
+}
+
+func TestFilesDataImpl_importFiles_DroppedRows(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"part-0000.csv": "SingerId,Name\n1,a\n2,b\n",
+		"part-0001.csv": "SingerId,Name\n3,c\n4,d\n",
+	} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0644))
+	}
+	bad := sp.Insert("Singers", []string{"SingerId", "Name"}, []interface{}{int64(4), "d"})
+	batchWriter := writer.NewSyncBatchWriter(writer.NewBatchWriter(writer.BatchWriterConfig{
+		BytesLimit: 1000,
+		WriteLimit: 1,
+		RetryLimit: 10,
+		Write: func(m []*sp.Mutation) error {
+			for _, x := range m {
+				if reflect.DeepEqual(x, bad) {
+					return fmt.Errorf("already exists")
+				}
+			}
+			return nil
+		},
+	}))
+	source := &FilesDataImpl{
+		SourceFormat:      constants.CSV,
+		CsvFieldDelimiter: ",",
+		Files: []FileImport{
+			{TableName: "Singers", SourceUri: filepath.Join(dir, "part-0000.csv")},
+			{TableName: "Singers", SourceUri: filepath.Join(dir, "part-0001.csv")},
+		},
+		Concurrency: 2,
+	}
+
+	statuses := source.importFiles(context.Background(), getSingersConv(), batchWriter)
+	assert.NoError(t, statuses[0].Err)
+	assert.Equal(t, int64(2), statuses[0].Rows)
+	assert.EqualError(t, statuses[1].Err, "1 rows could not be written to Spanner")
+	assert.Equal(t, int64(1), statuses[1].Rows)
+	assert.Equal(t, int64(1), statuses[1].DroppedRows)
+}
+
+func getSingersConv() *internal.Conv {
+	conv := internal.MakeConv()
+	conv.SpSchema["t1"] = ddl.CreateTable{
+		Name:   "Singers",
+		Id:     "t1",
+		ColIds: []string{"c1", "c2"},
+		ColDefs: map[string]ddl.ColumnDef{
+			"c1": {Name: "SingerId", Id: "c1", T: ddl.Type{Name: ddl.Int64}},
+			"c2": {Name: "Name", Id: "c2", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+		},
+		PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}},
+	}
+	return conv
+}
+
+func TestFilesDataImpl_importFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"part-0000.csv":   "SingerId,Name\n1,a\n2,b\n",
+		"part-0001.csv":   "3,c\nx,d\n",
+		"part-0000.jsonl": `{"SingerId": 4, "Name": "e"}` + "\n" + `{"SingerId": "y"}` + "\n",
+	}
+	for name, data := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0644))
+	}
+	conv := getSingersConv()
+	var lock sync.Mutex
+	var written []*sp.Mutation
+	batchWriter := writer.NewSyncBatchWriter(writer.NewBatchWriter(writer.BatchWriterConfig{
+		BytesLimit: 1000,
+		WriteLimit: 1,
+		RetryLimit: 1,
+		Write: func(m []*sp.Mutation) error {
+			lock.Lock()
+			defer lock.Unlock()
+			written = append(written, m...)
+			return nil
+		},
+	}))
+	ctx := context.Background()
+
+	source := &FilesDataImpl{SourceFormat: constants.CSV, CsvFieldDelimiter: ","}
+	status := source.importFile(ctx, conv, batchWriter, FileImport{TableName: "Singers", SourceUri: filepath.Join(dir, "part-0000.csv")}, nil)
+	assert.NoError(t, status.Err)
+	assert.Equal(t, int64(2), status.Rows)
+	status = source.importFile(ctx, conv, batchWriter, FileImport{TableName: "Singers", SourceUri: filepath.Join(dir, "part-0001.csv")}, nil)
+	assert.NoError(t, status.Err)
+	assert.Equal(t, int64(1), status.Rows, "rows without a header are imported, and rows that can't be converted are skipped")
+
+	source = &FilesDataImpl{SourceFormat: constants.JSONL}
+	status = source.importFile(ctx, conv, batchWriter, FileImport{TableName: "Singers", SourceUri: filepath.Join(dir, "part-0000.jsonl")}, nil)
+	assert.NoError(t, status.Err)
+	assert.Equal(t, int64(1), status.Rows)
+	assert.Equal(t, int64(1), status.BadRows)
+
+	status = source.importFile(ctx, conv, batchWriter, FileImport{TableName: "Singers", SourceUri: filepath.Join(dir, "missing.jsonl")}, nil)
+	assert.Error(t, status.Err)
+
+	batchWriter.Flush()
+	assert.Len(t, written, 4)
+}
+
+func TestFilesDataImpl_importFiles_InferredSchema(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"part-0000.csv": "\ufeffSinger Id,First Name,1st\n1,a,x\n",
+		"part-0001.csv": "Singer Id,1st,First Name\n2,y,b\n",
+	} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0644))
+	}
+	ctx := context.Background()
+	uris, err := ExpandFilePatterns(ctx, []string{filepath.Join(dir, "part-*.csv")})
+	assert.NoError(t, err)
+	reader, err := file_reader.NewFileReader(ctx, uris[0])
+	assert.NoError(t, err)
+	defer reader.Close()
+	colDefs, _, err := InferCsvSchema(ctx, reader, ',', 10, constants.DIALECT_GOOGLESQL)
+	assert.NoError(t, err)
+
+	conv := internal.MakeConv()
+	table := ddl.CreateTable{Name: "Singers", Id: "t1", ColDefs: map[string]ddl.ColumnDef{}}
+	for i, colDef := range colDefs {
+		id := fmt.Sprintf("c%d", i+1)
+		colType := ddl.Type{Name: ddl.String, Len: ddl.MaxLength}
+		if colDef.PkOrder == 1 {
+			colType = ddl.Type{Name: ddl.Int64}
+			table.PrimaryKeys = []ddl.IndexKey{{ColId: id, Order: 1}}
+		}
+		table.ColIds = append(table.ColIds, id)
+		table.ColDefs[id] = ddl.ColumnDef{Name: colDef.Name, Id: id, T: colType}
+	}
+	conv.SpSchema["t1"] = table
+	var lock sync.Mutex
+	var written []*sp.Mutation
+	batchWriter := writer.NewSyncBatchWriter(writer.NewBatchWriter(writer.BatchWriterConfig{
+		BytesLimit: 1000,
+		WriteLimit: 1,
+		RetryLimit: 1,
+		Write: func(m []*sp.Mutation) error {
+			lock.Lock()
+			defer lock.Unlock()
+			written = append(written, m...)
+			return nil
+		},
+	}))
+	source := &FilesDataImpl{SourceFormat: constants.CSV, CsvFieldDelimiter: ",", Concurrency: 1}
+	for _, uri := range uris {
+		source.Files = append(source.Files, FileImport{TableName: "Singers", SourceUri: uri, RenameCsvHeader: true})
+	}
+
+	statuses := source.importFiles(ctx, conv, batchWriter)
+	for _, status := range statuses {
+		assert.NoError(t, status.Err)
+		assert.Equal(t, int64(1), status.Rows)
+		assert.Equal(t, int64(0), status.BadRows)
+	}
+	assert.ElementsMatch(t, []*sp.Mutation{
+		sp.Insert("Singers", []string{"Singer_Id", "First_Name", "c_1st"}, []interface{}{int64(1), "a", "x"}),
+		sp.Insert("Singers", []string{"Singer_Id", "c_1st", "First_Name"}, []interface{}{int64(2), "y", "b"}),
+	}, written)
+}
@@ -47,7 +47,7 @@ func NewImportFromDump(
 	dbURI string,
 	sp spanneraccessor.SpannerAccessor,
 	sourceReader file_reader.FileReader) (ImportFromDump, error) {
-	dbDump, err := getDbDump(sourceFormat)
+	dbDump, err := getDbDump(sourceFormat, dumpUri)
 	if err != nil {
 		return nil, err
 	}
@@ -121,11 +121,17 @@ func (source *ImportFromDumpImpl) ImportData(ctx context.Context, conv *internal
 	return nil
 }
 
-func getDbDump(sourceFormat string) (common.DbDump, error) {
+// getDbDump returns the DbDump for sourceFormat. Local pg_dump archives in
+// the custom or directory format are read directly from dumpUri, so that
+// their tables can be processed in parallel.
+func getDbDump(sourceFormat, dumpUri string) (common.DbDump, error) {
 	switch sourceFormat {
 	case constants.MYSQLDUMP:
 		return mysql.DbDumpImpl{}, nil
 	case constants.PGDUMP:
+		if postgres.IsArchive(dumpUri) {
+			return postgres.DbDumpImpl{ArchivePath: dumpUri}, nil
+		}
 		return postgres.DbDumpImpl{}, nil
 	default:
 		return nil, fmt.Errorf("process dump for sourceFormat %s not supported", sourceFormat)
@@ -338,7 +338,7 @@ func TestGetDbDump(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			dbDump, err := getDbDump(tc.sourceFormat)
+			dbDump, err := getDbDump(tc.sourceFormat, "dump.sql")
 			if tc.expectedError != nil {
 				assert.EqualError(t, err, tc.expectedError.Error())
 				assert.Nil(t, dbDump)
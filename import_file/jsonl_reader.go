@@ -0,0 +1,202 @@
+package import_file
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// maxJsonlLine is the maximum length of a line of a JSONL file.
+const maxJsonlLine = 64 * 1024 * 1024
+
+// jsonlReader reads the objects of a newline-delimited JSON file. Numbers
+// are read as json.Number, so that integers keep their precision.
+type jsonlReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newJsonlReader(r io.Reader) *jsonlReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJsonlLine)
+	return &jsonlReader{scanner: scanner}
+}
+
+func (jr *jsonlReader) Close() error {
+	return nil
+}
+
+// Columns reads all records of the file, and returns their keys in order
+// of first appearance. The type of a column is the type of its values, or
+// JSON if their types differ. Lines that aren't JSON objects are skipped.
+func (jr *jsonlReader) Columns() ([]recordColumn, error) {
+	var cols []recordColumn
+	kinds := map[string]jsonKind{}
+	for {
+		record, order, err := jr.next()
+		if err == io.EOF {
+			break
+		}
+		var valueErr *recordValueError
+		if errors.As(err, &valueErr) {
+			// The line is counted as a bad row when the data is imported.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range order {
+			if _, ok := kinds[k]; !ok {
+				cols = append(cols, recordColumn{Name: k})
+			}
+			kinds[k] = mergeJsonKind(kinds[k], jsonKindOf(record[k]))
+		}
+	}
+	for i := range cols {
+		cols[i].T = kinds[cols[i].Name].ddlType()
+	}
+	return cols, nil
+}
+
+func (jr *jsonlReader) Next() (map[string]interface{}, error) {
+	record, _, err := jr.next()
+	return record, err
+}
+
+// next returns the next record, with its keys in the order of the file.
+func (jr *jsonlReader) next() (map[string]interface{}, []string, error) {
+	for jr.scanner.Scan() {
+		jr.line++
+		line := bytes.TrimSpace(jr.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		record, order, err := decodeJsonObject(line)
+		if err != nil {
+			return nil, nil, &recordValueError{fmt.Errorf("can't read line %d: %w", jr.line, err)}
+		}
+		return record, order, nil
+	}
+	if err := jr.scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return nil, nil, io.EOF
+}
+
+// decodeJsonObject decodes a JSON object, and returns its keys in order.
+func decodeJsonObject(b []byte) (map[string]interface{}, []string, error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	t, err := d.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+	if t != json.Delim('{') {
+		return nil, nil, fmt.Errorf("expected a JSON object")
+	}
+	record := map[string]interface{}{}
+	var order []string
+	for d.More() {
+		t, err := d.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		k := t.(string)
+		var v interface{}
+		if err := d.Decode(&v); err != nil {
+			return nil, nil, err
+		}
+		if _, ok := record[k]; !ok {
+			order = append(order, k)
+		}
+		record[k] = v
+	}
+	if _, err := d.Token(); err != nil {
+		return nil, nil, err
+	}
+	if _, err := d.Token(); err != io.EOF {
+		return nil, nil, fmt.Errorf("unexpected data after JSON object")
+	}
+	return record, order, nil
+}
+
+// jsonKind is the kind of the JSON values of a column.
+type jsonKind struct {
+	name    string // "", "bool", "int", "float", "string" or "json".
+	isArray bool   // If true, the values are arrays of values of kind name.
+}
+
+func jsonKindOf(v interface{}) jsonKind {
+	switch x := v.(type) {
+	case nil:
+		return jsonKind{}
+	case bool:
+		return jsonKind{name: "bool"}
+	case json.Number:
+		if _, err := x.Int64(); err == nil {
+			return jsonKind{name: "int"}
+		}
+		return jsonKind{name: "float"}
+	case string:
+		return jsonKind{name: "string"}
+	case []interface{}:
+		var elem jsonKind
+		for _, e := range x {
+			elem = mergeJsonKind(elem, jsonKindOf(e))
+		}
+		if elem.isArray || elem.name == "json" {
+			return jsonKind{name: "json"}
+		}
+		return jsonKind{name: elem.name, isArray: true}
+	}
+	return jsonKind{name: "json"}
+}
+
+// mergeJsonKind returns the kind of a column holding values of kinds a
+// and b.
+func mergeJsonKind(a, b jsonKind) jsonKind {
+	switch {
+	case a.name == "" && !a.isArray:
+		return b
+	case b.name == "" && !b.isArray:
+		return a
+	case a.isArray != b.isArray:
+		return jsonKind{name: "json"}
+	}
+	// Empty arrays have no element kind.
+	if a.name == "" {
+		return b
+	}
+	if b.name == "" || a.name == b.name {
+		return a
+	}
+	if (a.name == "int" && b.name == "float") || (a.name == "float" && b.name == "int") {
+		return jsonKind{name: "float", isArray: a.isArray}
+	}
+	return jsonKind{name: "json"}
+}
+
+func (k jsonKind) ddlType() ddl.Type {
+	var t ddl.Type
+	switch k.name {
+	case "bool":
+		t = ddl.Type{Name: ddl.Bool}
+	case "int":
+		t = ddl.Type{Name: ddl.Int64}
+	case "float":
+		t = ddl.Type{Name: ddl.Float64}
+	case "json":
+		t = ddl.Type{Name: ddl.JSON}
+	default:
+		t = ddl.Type{Name: ddl.String, Len: ddl.MaxLength}
+	}
+	if k.isArray {
+		return arrayType(t)
+	}
+	return t
+}
@@ -0,0 +1,65 @@
+package import_file
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJsonlReader_Columns(t *testing.T) {
+	data := `{"id": 1, "name": "a", "score": 1, "tags": [], "ok": true}
+{"id": 2, "name": null, "score": 1.5, "tags": ["x", null], "extra": {"k": 1}, "ok": false}
+
+{"id": 3, "score": 2, "mixed": 1, "nums": [1, 2.5], "nested": [[1]]}
+{"mixed": "x", "nothing": null}
+`
+	cols, err := newJsonlReader(strings.NewReader(data)).Columns()
+	assert.NoError(t, err)
+	assert.Equal(t, []recordColumn{
+		{Name: "id", T: ddl.Type{Name: ddl.Int64}},
+		{Name: "name", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+		{Name: "score", T: ddl.Type{Name: ddl.Float64}},
+		{Name: "tags", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: true}},
+		{Name: "ok", T: ddl.Type{Name: ddl.Bool}},
+		{Name: "extra", T: ddl.Type{Name: ddl.JSON}},
+		{Name: "mixed", T: ddl.Type{Name: ddl.JSON}},
+		{Name: "nums", T: ddl.Type{Name: ddl.Float64, IsArray: true}},
+		{Name: "nested", T: ddl.Type{Name: ddl.JSON}},
+		{Name: "nothing", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+	}, cols)
+}
+
+func TestJsonlReader_Next(t *testing.T) {
+	data := `{"id": 12345678901234567890, "tags": ["x", null], "extra": {"k": 1}}
+not json
+[1]
+{"id": 2}
+`
+	r := newJsonlReader(strings.NewReader(data))
+
+	record, err := r.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"id":    json.Number("12345678901234567890"),
+		"tags":  []interface{}{"x", nil},
+		"extra": map[string]interface{}{"k": json.Number("1")},
+	}, record)
+
+	var valueErr *recordValueError
+	for i := 0; i < 2; i++ {
+		_, err = r.Next()
+		assert.True(t, errors.As(err, &valueErr), "%v", err)
+	}
+
+	record, err = r.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"id": json.Number("2")}, record)
+
+	_, err = r.Next()
+	assert.Equal(t, io.EOF, err)
+}
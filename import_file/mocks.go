@@ -32,3 +32,39 @@ func (m *MockCsvData) ImportData(ctx context.Context, spannerInfoSchema *spanner
 	}
 	return nil
 }
+
+// MockRecordSchema for testing.
+type MockRecordSchema struct {
+	CreateSchemaFn func(ctx context.Context, dialect string, sp spanneraccessor.SpannerAccessor) error
+}
+
+func (m *MockRecordSchema) CreateSchema(ctx context.Context, dialect string, sp spanneraccessor.SpannerAccessor) error {
+	if m.CreateSchemaFn != nil {
+		return m.CreateSchemaFn(ctx, dialect, sp)
+	}
+	return nil
+}
+
+// MockRecordData for testing.
+type MockRecordData struct {
+	ImportDataFn func(ctx context.Context, spannerInfoSchema *spanner.InfoSchemaImpl, dialect string, conv *internal.Conv, commonInfoSchema common.InfoSchemaInterface) error
+}
+
+func (m *MockRecordData) ImportData(ctx context.Context, spannerInfoSchema *spanner.InfoSchemaImpl, dialect string, conv *internal.Conv, commonInfoSchema common.InfoSchemaInterface) error {
+	if m.ImportDataFn != nil {
+		return m.ImportDataFn(ctx, spannerInfoSchema, dialect, conv, commonInfoSchema)
+	}
+	return nil
+}
+
+// MockFilesData for testing.
+type MockFilesData struct {
+	ImportDataFn func(ctx context.Context, spannerInfoSchema *spanner.InfoSchemaImpl, dialect string, commonInfoSchema common.InfoSchemaInterface) ([]FileImportStatus, error)
+}
+
+func (m *MockFilesData) ImportData(ctx context.Context, spannerInfoSchema *spanner.InfoSchemaImpl, dialect string, commonInfoSchema common.InfoSchemaInterface) ([]FileImportStatus, error) {
+	if m.ImportDataFn != nil {
+		return m.ImportDataFn(ctx, spannerInfoSchema, dialect, commonInfoSchema)
+	}
+	return nil, nil
+}
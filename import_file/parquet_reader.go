@@ -0,0 +1,425 @@
+package import_file
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/deprecated"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+// julianUnixEpoch is the Julian day of 1970-01-01, used by INT96 timestamps.
+const julianUnixEpoch = 2440588
+
+// parquetReader reads the rows of a Parquet file. Records are assembled
+// from the leaf values of the rows, using their repetition and definition
+// levels.
+type parquetReader struct {
+	fields []parquet.Field
+	// groups holds the logical types of the groups of the schema, which
+	// parquet-go only keeps for MAP groups.
+	groups  map[parquet.Node]*format.LogicalType
+	reader  *parquet.Reader
+	leaves  int
+	rows    []parquet.Row
+	lastErr error
+	release func() error
+}
+
+func newParquetReader(r io.Reader) (*parquetReader, error) {
+	ra, size, release, err := readerAt(r)
+	if err != nil {
+		return nil, err
+	}
+	f, err := parquet.OpenFile(ra, size)
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("can't read parquet file: %w", err)
+	}
+	return &parquetReader{
+		fields:  f.Schema().Fields(),
+		groups:  parquetGroupTypes(f),
+		reader:  parquet.NewReader(f),
+		leaves:  parquetLeafCount(f.Schema()),
+		rows:    make([]parquet.Row, 1),
+		release: release,
+	}, nil
+}
+
+func (pr *parquetReader) Close() error {
+	pr.reader.Close()
+	return pr.release()
+}
+
+func (pr *parquetReader) Columns() ([]recordColumn, error) {
+	var cols []recordColumn
+	for _, f := range pr.fields {
+		cols = append(cols, recordColumn{Name: f.Name(), T: pr.columnType(f), NotNull: f.Required()})
+	}
+	return cols, nil
+}
+
+func (pr *parquetReader) Next() (map[string]interface{}, error) {
+	if pr.lastErr != nil {
+		return nil, pr.lastErr
+	}
+	n, err := pr.reader.ReadRows(pr.rows)
+	if n == 0 {
+		if err == nil {
+			err = io.EOF
+		}
+		return nil, err
+	}
+	// The error is returned by the next call, after this row.
+	pr.lastErr = err
+	cols := make([][]parquet.Value, pr.leaves)
+	pr.rows[0].Range(func(i int, values []parquet.Value) bool {
+		cols[i] = values
+		return true
+	})
+	record := make(map[string]interface{}, len(pr.fields))
+	off := 0
+	for _, f := range pr.fields {
+		n := parquetLeafCount(f)
+		v, err := pr.value(f, 0, 0, cols[off:off+n])
+		if err != nil {
+			return nil, &recordValueError{fmt.Errorf("can't read value of column %s: %w", f.Name(), err)}
+		}
+		record[f.Name()] = v
+		off += n
+	}
+	return record, nil
+}
+
+// parquetLeafCount returns the number of leaf columns of node.
+func parquetLeafCount(node parquet.Node) int {
+	if node.Leaf() {
+		return 1
+	}
+	n := 0
+	for _, f := range node.Fields() {
+		n += parquetLeafCount(f)
+	}
+	return n
+}
+
+// parquetListElement returns the repeated field of a LIST, and whether its
+// elements are the values of the only field of that repeated group rather
+// than the group itself, following the backward-compatibility rules of the
+// Parquet format.
+func parquetListElement(list parquet.Field) (parquet.Field, bool, bool) {
+	fields := list.Fields()
+	if len(fields) != 1 || !fields[0].Repeated() {
+		return nil, false, false
+	}
+	rep := fields[0]
+	wrapped := !rep.Leaf() && len(rep.Fields()) == 1 && rep.Name() != "array" && rep.Name() != list.Name()+"_tuple"
+	return rep, wrapped, true
+}
+
+// parquetMapKeyValue returns the repeated key-value group of a MAP.
+func parquetMapKeyValue(node parquet.Node) (parquet.Field, bool) {
+	fields := node.Fields()
+	if len(fields) != 1 || !fields[0].Repeated() || fields[0].Leaf() || len(fields[0].Fields()) != 2 {
+		return nil, false
+	}
+	return fields[0], true
+}
+
+// parquetGroupTypes returns the logical types of the groups of the schema
+// of f, from the schema elements of its metadata. The elements are the
+// nodes of the schema in depth-first order, starting with the root.
+func parquetGroupTypes(f *parquet.File) map[parquet.Node]*format.LogicalType {
+	elements := f.Metadata().Schema
+	groups := map[parquet.Node]*format.LogicalType{}
+	i := 1
+	var walk func(fields []parquet.Field)
+	walk = func(fields []parquet.Field) {
+		for _, field := range fields {
+			if i >= len(elements) {
+				return
+			}
+			e := elements[i]
+			i++
+			if field.Leaf() {
+				continue
+			}
+			lt := e.LogicalType
+			if lt == nil && e.ConvertedType != nil {
+				switch *e.ConvertedType {
+				case deprecated.List:
+					lt = &format.LogicalType{List: &format.ListType{}}
+				case deprecated.Map, deprecated.MapKeyValue:
+					lt = &format.LogicalType{Map: &format.MapType{}}
+				}
+			}
+			if lt != nil {
+				groups[field] = lt
+			}
+			walk(field.Fields())
+		}
+	}
+	walk(f.Schema().Fields())
+	return groups
+}
+
+func (pr *parquetReader) logicalType(node parquet.Node) *format.LogicalType {
+	if lt, ok := pr.groups[node]; ok {
+		return lt
+	}
+	if lt := node.Type().LogicalType(); lt != nil {
+		return lt
+	}
+	return &format.LogicalType{}
+}
+
+// columnType returns the Spanner type of the values of node.
+func (pr *parquetReader) columnType(node parquet.Field) ddl.Type {
+	if node.Repeated() {
+		return arrayType(pr.elemType(node))
+	}
+	return pr.elemType(node)
+}
+
+// elemType returns the Spanner type of node, ignoring its repetition.
+func (pr *parquetReader) elemType(node parquet.Field) ddl.Type {
+	lt := pr.logicalType(node)
+	if !node.Leaf() {
+		if lt.List != nil {
+			if rep, wrapped, ok := parquetListElement(node); ok {
+				if wrapped {
+					return arrayType(pr.columnType(rep.Fields()[0]))
+				}
+				return arrayType(pr.elemType(rep))
+			}
+		}
+		return ddl.Type{Name: ddl.JSON}
+	}
+	switch {
+	case lt.Decimal != nil:
+		return numericType(int(lt.Decimal.Precision), int(lt.Decimal.Scale))
+	case lt.Date != nil:
+		return ddl.Type{Name: ddl.Date}
+	case lt.Timestamp != nil:
+		return ddl.Type{Name: ddl.Timestamp}
+	case lt.Time != nil, lt.UTF8 != nil, lt.Enum != nil:
+		return ddl.Type{Name: ddl.String, Len: ddl.MaxLength}
+	case lt.UUID != nil:
+		return ddl.Type{Name: ddl.String, Len: 36}
+	case lt.Json != nil:
+		return ddl.Type{Name: ddl.JSON}
+	case lt.Integer != nil && !lt.Integer.IsSigned && lt.Integer.BitWidth == 64:
+		return ddl.Type{Name: ddl.Numeric}
+	}
+	switch node.Type().Kind() {
+	case parquet.Boolean:
+		return ddl.Type{Name: ddl.Bool}
+	case parquet.Int32, parquet.Int64:
+		return ddl.Type{Name: ddl.Int64}
+	case parquet.Int96:
+		return ddl.Type{Name: ddl.Timestamp}
+	case parquet.Float:
+		return ddl.Type{Name: ddl.Float32}
+	case parquet.Double:
+		return ddl.Type{Name: ddl.Float64}
+	}
+	return ddl.Type{Name: ddl.Bytes, Len: ddl.MaxLength}
+}
+
+// value assembles the value of node in a row from cols, the values
+// of the leaf columns of node. def and rep are the definition level and
+// the repetition depth of the parent of node.
+func (pr *parquetReader) value(node parquet.Field, def, rep int, cols [][]parquet.Value) (interface{}, error) {
+	for _, col := range cols {
+		if len(col) == 0 {
+			return nil, fmt.Errorf("missing values in row")
+		}
+	}
+	switch {
+	case node.Optional():
+		def++
+		if cols[0][0].DefinitionLevel() < def {
+			return nil, nil
+		}
+	case node.Repeated():
+		def++
+		rep++
+		if cols[0][0].DefinitionLevel() < def {
+			return []interface{}{}, nil
+		}
+		var l []interface{}
+		for _, elem := range parquetSplit(cols, rep) {
+			v, err := pr.requiredValue(node, def, rep, elem)
+			if err != nil {
+				return nil, err
+			}
+			l = append(l, v)
+		}
+		return l, nil
+	}
+	return pr.requiredValue(node, def, rep, cols)
+}
+
+// parquetSplit splits the values of the leaf columns of a repeated node
+// into the values of each of its elements.
+func parquetSplit(cols [][]parquet.Value, rep int) [][][]parquet.Value {
+	var elems [][][]parquet.Value
+	for i, col := range cols {
+		start, k := 0, 0
+		for j := 1; j <= len(col); j++ {
+			if j < len(col) && col[j].RepetitionLevel() > rep {
+				continue
+			}
+			if k == len(elems) {
+				elems = append(elems, make([][]parquet.Value, len(cols)))
+			}
+			elems[k][i] = col[start:j]
+			start = j
+			k++
+		}
+	}
+	return elems
+}
+
+func (pr *parquetReader) requiredValue(node parquet.Field, def, rep int, cols [][]parquet.Value) (interface{}, error) {
+	if node.Leaf() {
+		return pr.leafValue(node, cols[0][0])
+	}
+	lt := pr.logicalType(node)
+	if lt.List != nil {
+		if r, wrapped, ok := parquetListElement(node); ok {
+			v, err := pr.value(r, def, rep, cols)
+			if err != nil || !wrapped {
+				return v, err
+			}
+			l := v.([]interface{})
+			name := r.Fields()[0].Name()
+			for i, e := range l {
+				l[i] = e.(map[string]interface{})[name]
+			}
+			return l, nil
+		}
+	}
+	if lt.Map != nil {
+		if kv, ok := parquetMapKeyValue(node); ok {
+			v, err := pr.value(kv, def, rep, cols)
+			if err != nil {
+				return nil, err
+			}
+			keyName, valueName := kv.Fields()[0].Name(), kv.Fields()[1].Name()
+			m := map[string]interface{}{}
+			for _, e := range v.([]interface{}) {
+				entry := e.(map[string]interface{})
+				m[fmt.Sprint(jsonValue(entry[keyName]))] = entry[valueName]
+			}
+			return m, nil
+		}
+	}
+	m := map[string]interface{}{}
+	off := 0
+	for _, f := range node.Fields() {
+		n := parquetLeafCount(f)
+		v, err := pr.value(f, def, rep, cols[off:off+n])
+		if err != nil {
+			return nil, err
+		}
+		m[f.Name()] = v
+		off += n
+	}
+	return m, nil
+}
+
+// leafValue converts a leaf value to a record value, using the
+// logical type of node.
+func (pr *parquetReader) leafValue(node parquet.Node, v parquet.Value) (interface{}, error) {
+	if v.IsNull() {
+		return nil, nil
+	}
+	lt := pr.logicalType(node)
+	kind := node.Type().Kind()
+	switch {
+	case lt.Decimal != nil:
+		var i *big.Int
+		switch kind {
+		case parquet.Int32:
+			i = big.NewInt(int64(v.Int32()))
+		case parquet.Int64:
+			i = big.NewInt(v.Int64())
+		default:
+			i = twosComplement(v.ByteArray())
+		}
+		return new(big.Rat).SetFrac(i, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(lt.Decimal.Scale)), nil)), nil
+	case lt.Date != nil:
+		return civil.DateOf(time.Unix(int64(v.Int32())*24*60*60, 0).UTC()), nil
+	case lt.Timestamp != nil:
+		return time.Unix(0, v.Int64()*timeUnit(lt.Timestamp.Unit)).UTC(), nil
+	case lt.Time != nil:
+		var units int64
+		if kind == parquet.Int32 {
+			units = int64(v.Int32())
+		} else {
+			units = v.Int64()
+		}
+		d := time.Duration(units * timeUnit(lt.Time.Unit))
+		return time.Time{}.Add(d).Format("15:04:05.999999999"), nil
+	case lt.UUID != nil:
+		u, err := uuid.FromBytes(v.ByteArray())
+		if err != nil {
+			return nil, err
+		}
+		return u.String(), nil
+	case lt.Json != nil:
+		return json.RawMessage(string(v.ByteArray())), nil
+	case lt.UTF8 != nil, lt.Enum != nil:
+		return string(v.ByteArray()), nil
+	case lt.Integer != nil && !lt.Integer.IsSigned:
+		if kind == parquet.Int32 {
+			return int64(v.Uint32()), nil
+		}
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(v.Uint64())), nil
+	}
+	switch kind {
+	case parquet.Boolean:
+		return v.Boolean(), nil
+	case parquet.Int32:
+		return int64(v.Int32()), nil
+	case parquet.Int64:
+		return v.Int64(), nil
+	case parquet.Int96:
+		i := v.Int96()
+		nanos := int64(i[1])<<32 | int64(i[0])
+		return time.Unix((int64(i[2])-julianUnixEpoch)*24*60*60, nanos).UTC(), nil
+	case parquet.Float:
+		return v.Float(), nil
+	case parquet.Double:
+		return v.Double(), nil
+	}
+	return append([]byte(nil), v.ByteArray()...), nil
+}
+
+// timeUnit returns the number of nanoseconds of a unit of u.
+func timeUnit(u format.TimeUnit) int64 {
+	switch {
+	case u.Millis != nil:
+		return int64(time.Millisecond)
+	case u.Micros != nil:
+		return int64(time.Microsecond)
+	}
+	return 1
+}
+
+// twosComplement decodes a big-endian two's complement integer.
+func twosComplement(b []byte) *big.Int {
+	i := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		i.Sub(i, new(big.Int).Lsh(big.NewInt(1), uint(len(b)*8)))
+	}
+	return i
+}
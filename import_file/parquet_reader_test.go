@@ -0,0 +1,168 @@
+package import_file
+
+import (
+	"bytes"
+	"io"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+)
+
+type parquetTestRow struct {
+	Id       int64             `parquet:"id"`
+	Name     *string           `parquet:"name,optional"`
+	Price    int64             `parquet:"price,decimal(2:10)"`
+	Day      int32             `parquet:"day,date"`
+	Created  int64             `parquet:"created,timestamp(microsecond)"`
+	Tags     []string          `parquet:"tags,list"`
+	Matrix   [][]int32         `parquet:"matrix,list"`
+	Attrs    map[string]int64  `parquet:"attrs"`
+	Score    float32           `parquet:"score"`
+	Nested   parquetTestNested `parquet:"nested"`
+	Unsigned uint64            `parquet:"unsigned"`
+}
+
+type parquetTestNested struct {
+	A int32  `parquet:"a"`
+	B string `parquet:"b"`
+}
+
+func writeParquetTestFile(t *testing.T, rows []parquetTestRow) *bytes.Reader {
+	var buf bytes.Buffer
+	if err := parquet.Write(&buf, rows); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestParquetReader(t *testing.T) {
+	name := "widget"
+	created := time.Date(2024, 3, 4, 5, 6, 7, 123456000, time.UTC)
+	rows := []parquetTestRow{
+		{
+			Id:       1,
+			Name:     &name,
+			Price:    12345,
+			Day:      int32(civil.Date{Year: 2024, Month: 3, Day: 4}.DaysSince(civil.Date{Year: 1970, Month: 1, Day: 1})),
+			Created:  created.UnixMicro(),
+			Tags:     []string{"a", "b"},
+			Matrix:   [][]int32{{1, 2}, {3}},
+			Attrs:    map[string]int64{"x": 1},
+			Score:    1.5,
+			Nested:   parquetTestNested{A: 7, B: "b"},
+			Unsigned: 1 << 63,
+		},
+		{Id: 2, Price: -1},
+	}
+	r, err := newParquetReader(writeParquetTestFile(t, rows))
+	assert.NoError(t, err)
+
+	cols, err := r.Columns()
+	assert.NoError(t, err)
+	assert.Equal(t, []recordColumn{
+		{Name: "id", T: ddl.Type{Name: ddl.Int64}, NotNull: true},
+		{Name: "name", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+		{Name: "price", T: ddl.Type{Name: ddl.Numeric}, NotNull: true},
+		{Name: "day", T: ddl.Type{Name: ddl.Date}, NotNull: true},
+		{Name: "created", T: ddl.Type{Name: ddl.Timestamp}, NotNull: true},
+		{Name: "tags", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: true}, NotNull: true},
+		{Name: "matrix", T: ddl.Type{Name: ddl.JSON}, NotNull: true},
+		{Name: "attrs", T: ddl.Type{Name: ddl.JSON}, NotNull: true},
+		{Name: "score", T: ddl.Type{Name: ddl.Float32}, NotNull: true},
+		{Name: "nested", T: ddl.Type{Name: ddl.JSON}, NotNull: true},
+		{Name: "unsigned", T: ddl.Type{Name: ddl.Numeric}, NotNull: true},
+	}, cols)
+
+	record, err := r.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), record["id"])
+	assert.Equal(t, "widget", record["name"])
+	assert.Equal(t, big.NewRat(12345, 100), record["price"])
+	assert.Equal(t, civil.Date{Year: 2024, Month: 3, Day: 4}, record["day"])
+	assert.Equal(t, created, record["created"])
+	assert.Equal(t, []interface{}{"a", "b"}, record["tags"])
+	assert.Equal(t, []interface{}{[]interface{}{int64(1), int64(2)}, []interface{}{int64(3)}}, record["matrix"])
+	assert.Equal(t, map[string]interface{}{"x": int64(1)}, record["attrs"])
+	assert.Equal(t, float32(1.5), record["score"])
+	assert.Equal(t, map[string]interface{}{"a": int64(7), "b": "b"}, record["nested"])
+	assert.Equal(t, new(big.Rat).SetInt(new(big.Int).Lsh(big.NewInt(1), 63)), record["unsigned"])
+
+	record, err = r.Next()
+	assert.NoError(t, err)
+	assert.Nil(t, record["name"])
+	assert.Equal(t, big.NewRat(-1, 100), record["price"])
+	assert.Equal(t, []interface{}{}, record["tags"])
+	assert.Equal(t, []interface{}{}, record["matrix"])
+	assert.Equal(t, map[string]interface{}{}, record["attrs"])
+
+	_, err = r.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestParquetReader_InvalidFile(t *testing.T) {
+	_, err := newParquetReader(bytes.NewReader([]byte("not parquet")))
+	assert.Error(t, err)
+}
+
+func TestTwosComplement(t *testing.T) {
+	assert.Equal(t, big.NewInt(-1), twosComplement([]byte{0xff}))
+	assert.Equal(t, big.NewInt(255), twosComplement([]byte{0x00, 0xff}))
+	assert.Equal(t, big.NewInt(-256), twosComplement([]byte{0xff, 0x00}))
+	assert.Equal(t, big.NewInt(0), twosComplement(nil))
+}
+
+func TestParquetReader_OptionalList(t *testing.T) {
+	// Rows are written with explicit levels: the max definition level of
+	// the element is 3 and its max repetition level is 1.
+	schema := parquet.NewSchema("t", parquet.Group{
+		"tags": parquet.Optional(parquet.List(parquet.Optional(parquet.String()))),
+	})
+	var buf bytes.Buffer
+	w := parquet.NewWriter(&buf, schema)
+	_, err := w.WriteRows([]parquet.Row{
+		{parquet.ValueOf("a").Level(0, 3, 0), parquet.NullValue().Level(1, 2, 0), parquet.ValueOf("c").Level(1, 3, 0)},
+		{parquet.NullValue().Level(0, 1, 0)},
+		{parquet.NullValue().Level(0, 0, 0)},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	r, err := newParquetReader(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	cols, err := r.Columns()
+	assert.NoError(t, err)
+	assert.Equal(t, []recordColumn{{Name: "tags", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: true}}}, cols)
+
+	for _, want := range []interface{}{[]interface{}{"a", nil, "c"}, []interface{}{}, nil} {
+		record, err := r.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, want, record["tags"])
+	}
+	_, err = r.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestParquetReader_Spooled(t *testing.T) {
+	// Readers without random access, such as Cloud Storage objects, are
+	// spooled to a temporary file that is removed on Close.
+	dir := t.TempDir()
+	t.Setenv("TMPDIR", dir)
+	r, err := newParquetReader(io.MultiReader(writeParquetTestFile(t, []parquetTestRow{{Id: 1}, {Id: 2}})))
+	assert.NoError(t, err)
+	spooled, _ := os.ReadDir(dir)
+	assert.Len(t, spooled, 1)
+	for _, id := range []int64{1, 2} {
+		record, err := r.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, id, record["id"])
+	}
+	assert.NoError(t, r.Close())
+	spooled, _ = os.ReadDir(dir)
+	assert.Empty(t, spooled)
+}
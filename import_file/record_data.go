@@ -0,0 +1,368 @@
+package import_file
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"time"
+
+	"cloud.google.com/go/civil"
+	sp "cloud.google.com/go/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/file_reader"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/writer"
+	"github.com/google/uuid"
+)
+
+var NewRecordData = newRecordData
+
+// RecordData imports the records of a Parquet, Avro or JSONL file.
+type RecordData interface {
+	ImportData(ctx context.Context, spannerInfoSchema *spanner.InfoSchemaImpl, dialect string, conv *internal.Conv, commonInfoSchema common.InfoSchemaInterface) error
+}
+
+type RecordDataImpl struct {
+	ProjectId        string
+	InstanceId       string
+	DbName           string
+	TableName        string
+	SourceFormat     string
+	SourceFileReader file_reader.FileReader
+}
+
+func newRecordData(projectId, instanceId, dbName, tableName, sourceFormat string, sourceFileReader file_reader.FileReader) RecordData {
+	return &RecordDataImpl{
+		ProjectId:        projectId,
+		InstanceId:       instanceId,
+		DbName:           dbName,
+		TableName:        tableName,
+		SourceFormat:     sourceFormat,
+		SourceFileReader: sourceFileReader,
+	}
+}
+
+func (source *RecordDataImpl) ImportData(ctx context.Context, spannerInfoSchema *spanner.InfoSchemaImpl, dialect string, conv *internal.Conv, commonInfoSchema common.InfoSchemaInterface) error {
+	conv = getConvObject(source.ProjectId, source.InstanceId, dialect, conv)
+	batchWriter := writer.GetBatchWriterWithConfig(ctx, spannerInfoSchema.SpannerClient, conv)
+
+	err := spannerInfoSchema.PopulateSpannerSchema(ctx, conv, commonInfoSchema)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("Unable to read Spanner schema %v", err))
+		return err
+	}
+
+	tableId, err := internal.GetTableIdFromSpName(conv.SpSchema, source.TableName)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("Table %s not found in Spanner", source.TableName))
+		return err
+	}
+
+	sourceIoReader, err := source.SourceFileReader.ResetReader(ctx)
+	if err != nil {
+		return fmt.Errorf("can't read source file: %w", err)
+	}
+	reader, err := newRecordReader(source.SourceFormat, sourceIoReader)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	err = processRecords(conv, source.TableName, conv.SpSchema[tableId], reader)
+	batchWriter.Flush()
+	if err != nil {
+		return err
+	}
+	logger.Log.Info(fmt.Sprintf("Imported %d rows into table %s, %d bad rows", conv.Stats.GoodRows[source.TableName], source.TableName, conv.BadRows()))
+	return nil
+}
+
+// processRecords writes the records read by reader to spTable. Records
+// whose values can't be converted to the types of the table are counted
+// as bad rows and skipped.
+func processRecords(conv *internal.Conv, tableName string, spTable ddl.CreateTable, reader recordReader) error {
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		var valueErr *recordValueError
+		if errors.As(err, &valueErr) {
+			logger.Log.Error(fmt.Sprintf("Error while reading record: %s\n", err))
+			conv.Unexpected(err.Error())
+			conv.StatsAddBadRow(tableName, conv.DataMode())
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("can't read record: %w", err)
+		}
+		conv.StatsAddRow(tableName, conv.DataMode())
+		cols, vals, err := convertRecord(conv.SpDialect, spTable, record)
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("Error while converting data: %s\n", err))
+			conv.Unexpected(err.Error())
+			conv.StatsAddBadRow(tableName, conv.DataMode())
+			continue
+		}
+		conv.WriteRow(tableName, tableName, cols, vals)
+	}
+}
+
+// convertRecord converts the values of a record to the types of the
+// columns of spTable. Null and missing values are left out, except for
+// the synthetic primary key, which is generated.
+func convertRecord(dialect string, spTable ddl.CreateTable, record map[string]interface{}) ([]string, []interface{}, error) {
+	var cols []string
+	var vals []interface{}
+	for _, colId := range spTable.ColIds {
+		col := spTable.ColDefs[colId]
+		v, ok := record[col.Name]
+		if !ok && col.Name == internal.SyntheticPrimaryKey {
+			v = uuid.New().String()
+		}
+		if v == nil {
+			continue
+		}
+		x, err := convertRecordValue(dialect, col.T, v)
+		if err != nil {
+			return nil, nil, fmt.Errorf("can't convert value of column %s: %w", col.Name, err)
+		}
+		cols = append(cols, col.Name)
+		vals = append(vals, x)
+	}
+	return cols, vals, nil
+}
+
+// convertRecordValue converts a record value to a value of Spanner type t.
+func convertRecordValue(dialect string, t ddl.Type, v interface{}) (interface{}, error) {
+	if !t.IsArray {
+		return convertRecordScalar(dialect, t, v)
+	}
+	elems, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("can't convert value of type %T to ARRAY<%s>", v, t.Name)
+	}
+	elemType := ddl.Type{Name: t.Name, Len: t.Len}
+	cvt := make([]interface{}, len(elems))
+	for i, e := range elems {
+		if e == nil {
+			continue
+		}
+		x, err := convertRecordScalar(dialect, elemType, e)
+		if err != nil {
+			return nil, err
+		}
+		cvt[i] = x
+	}
+	// The Spanner client doesn't accept []interface{} for arrays, so the
+	// elements are copied to a slice of the type of the array.
+	switch t.Name {
+	case ddl.Bool:
+		r := make([]sp.NullBool, len(cvt))
+		for i, x := range cvt {
+			if x != nil {
+				r[i] = sp.NullBool{Bool: x.(bool), Valid: true}
+			}
+		}
+		return r, nil
+	case ddl.Int64:
+		r := make([]sp.NullInt64, len(cvt))
+		for i, x := range cvt {
+			if x != nil {
+				r[i] = sp.NullInt64{Int64: x.(int64), Valid: true}
+			}
+		}
+		return r, nil
+	case ddl.Float32:
+		r := make([]sp.NullFloat32, len(cvt))
+		for i, x := range cvt {
+			if x != nil {
+				r[i] = sp.NullFloat32{Float32: x.(float32), Valid: true}
+			}
+		}
+		return r, nil
+	case ddl.Float64:
+		r := make([]sp.NullFloat64, len(cvt))
+		for i, x := range cvt {
+			if x != nil {
+				r[i] = sp.NullFloat64{Float64: x.(float64), Valid: true}
+			}
+		}
+		return r, nil
+	case ddl.String, ddl.JSON:
+		r := make([]sp.NullString, len(cvt))
+		for i, x := range cvt {
+			if x != nil {
+				r[i] = sp.NullString{StringVal: x.(string), Valid: true}
+			}
+		}
+		return r, nil
+	case ddl.Bytes:
+		r := make([][]byte, len(cvt))
+		for i, x := range cvt {
+			if x != nil {
+				r[i] = x.([]byte)
+			}
+		}
+		return r, nil
+	case ddl.Date:
+		r := make([]sp.NullDate, len(cvt))
+		for i, x := range cvt {
+			if x != nil {
+				r[i] = sp.NullDate{Date: x.(civil.Date), Valid: true}
+			}
+		}
+		return r, nil
+	case ddl.Timestamp:
+		r := make([]sp.NullTime, len(cvt))
+		for i, x := range cvt {
+			if x != nil {
+				r[i] = sp.NullTime{Time: x.(time.Time), Valid: true}
+			}
+		}
+		return r, nil
+	case ddl.Numeric:
+		if dialect == constants.DIALECT_POSTGRESQL {
+			r := make([]sp.PGNumeric, len(cvt))
+			for i, x := range cvt {
+				if x != nil {
+					r[i] = x.(sp.PGNumeric)
+				}
+			}
+			return r, nil
+		}
+		r := make([]sp.NullNumeric, len(cvt))
+		for i, x := range cvt {
+			if x != nil {
+				r[i] = sp.NullNumeric{Numeric: *x.(*big.Rat), Valid: true}
+			}
+		}
+		return r, nil
+	}
+	return nil, fmt.Errorf("array type %s not supported", t.Name)
+}
+
+func convertRecordScalar(dialect string, t ddl.Type, v interface{}) (interface{}, error) {
+	switch t.Name {
+	case ddl.Bool:
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+	case ddl.Int64:
+		switch x := v.(type) {
+		case int64:
+			return x, nil
+		case json.Number:
+			return x.Int64()
+		}
+	case ddl.Float32:
+		switch x := v.(type) {
+		case float32:
+			return x, nil
+		case float64:
+			if math.Abs(x) <= math.MaxFloat32 || math.IsInf(x, 0) || math.IsNaN(x) {
+				return float32(x), nil
+			}
+		case int64:
+			return float32(x), nil
+		case json.Number:
+			f, err := x.Float64()
+			return float32(f), err
+		}
+	case ddl.Float64:
+		switch x := v.(type) {
+		case float32:
+			return float64(x), nil
+		case float64:
+			return x, nil
+		case int64:
+			return float64(x), nil
+		case json.Number:
+			return x.Float64()
+		}
+	case ddl.Numeric:
+		var r *big.Rat
+		switch x := v.(type) {
+		case *big.Rat:
+			r = x
+		case int64:
+			r = new(big.Rat).SetInt64(x)
+		case json.Number, string:
+			var ok bool
+			if r, ok = new(big.Rat).SetString(fmt.Sprint(x)); !ok {
+				return nil, fmt.Errorf("can't convert %q to %s", x, t.Name)
+			}
+		}
+		if r != nil {
+			if dialect == constants.DIALECT_POSTGRESQL {
+				return sp.PGNumeric{Numeric: sp.NumericString(r), Valid: true}, nil
+			}
+			return r, nil
+		}
+	case ddl.String:
+		return recordString(v)
+	case ddl.JSON:
+		if raw, ok := v.(json.RawMessage); ok {
+			return string(raw), nil
+		}
+		b, err := json.Marshal(jsonValue(v))
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case ddl.Bytes:
+		switch x := v.(type) {
+		case []byte:
+			return x, nil
+		case string:
+			// Binary data is base64 encoded in JSON.
+			return base64.StdEncoding.DecodeString(x)
+		}
+	case ddl.Date:
+		switch x := v.(type) {
+		case civil.Date:
+			return x, nil
+		case string:
+			return civil.ParseDate(x)
+		}
+	case ddl.Timestamp:
+		switch x := v.(type) {
+		case time.Time:
+			return x, nil
+		case string:
+			return time.Parse(time.RFC3339Nano, x)
+		}
+	default:
+		return nil, fmt.Errorf("data conversion not implemented for type %v", t.Name)
+	}
+	return nil, fmt.Errorf("can't convert value of type %T to %s", v, t.Name)
+}
+
+// recordString returns the text of a record value. Lists and nested
+// records are written as JSON.
+func recordString(v interface{}) (string, error) {
+	switch x := v.(type) {
+	case string:
+		return x, nil
+	case json.RawMessage:
+		return string(x), nil
+	case []interface{}, map[string]interface{}:
+		b, err := json.Marshal(jsonValue(x))
+		return string(b), err
+	}
+	switch x := jsonValue(v).(type) {
+	case string:
+		return x, nil
+	case json.Number:
+		return string(x), nil
+	}
+	return fmt.Sprint(v), nil
+}
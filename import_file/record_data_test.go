@@ -0,0 +1,137 @@
+package import_file
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	sp "cloud.google.com/go/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/file_reader"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordDataImpl_ImportData_TableNotFound(t *testing.T) {
+	source := RecordDataImpl{
+		ProjectId:    "test-project",
+		InstanceId:   "test-instance",
+		DbName:       "test-db",
+		TableName:    "nonexistent_table",
+		SourceFormat: constants.JSONL,
+		SourceFileReader: &file_reader.MockFileReader{
+			ResetReaderFn: func(ctx context.Context) (io.Reader, error) {
+				t.Errorf("source file read for nonexistent table")
+				return strings.NewReader(""), nil
+			},
+		},
+	}
+	infoSchema := &spanner.InfoSchemaImpl{SpannerClient: getSpannerClientMock(getDefaultRowIteratoMock())}
+	err := source.ImportData(context.Background(), infoSchema, constants.DIALECT_GOOGLESQL, internal.MakeConv(), getCommonInfoSchemaMock(0))
+	assert.Error(t, err)
+}
+
+func TestProcessRecords(t *testing.T) {
+	spTable := ddl.CreateTable{
+		Name:   "events",
+		Id:     "t1",
+		ColIds: []string{"c1", "c2", "c3", "c4"},
+		ColDefs: map[string]ddl.ColumnDef{
+			"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}},
+			"c2": {Name: "tags", Id: "c2", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: true}},
+			"c3": {Name: "extra", Id: "c3", T: ddl.Type{Name: ddl.JSON}},
+			"c4": {Name: "synth_id", Id: "c4", T: ddl.Type{Name: ddl.String, Len: 36}},
+		},
+	}
+	data := `{"id": 1, "tags": ["a", null], "extra": {"k": [1, 2]}}
+not json
+{"id": "x"}
+{"id": 2, "synth_id": "s2"}
+`
+	conv := internal.MakeConv()
+	conv.SetDataMode()
+	type row struct {
+		cols []string
+		vals []interface{}
+	}
+	var rows []row
+	conv.SetDataSink(func(table string, cols []string, vals []interface{}) {
+		assert.Equal(t, "events", table)
+		rows = append(rows, row{cols, vals})
+	})
+
+	err := processRecords(conv, "events", spTable, newJsonlReader(strings.NewReader(data)))
+	assert.NoError(t, err)
+
+	assert.Len(t, rows, 2)
+	assert.Equal(t, []string{"id", "tags", "extra", "synth_id"}, rows[0].cols)
+	assert.Equal(t, int64(1), rows[0].vals[0])
+	assert.Equal(t, []sp.NullString{{StringVal: "a", Valid: true}, {}}, rows[0].vals[1])
+	assert.Equal(t, `{"k":[1,2]}`, rows[0].vals[2])
+	assert.Len(t, rows[0].vals[3], 36)
+	assert.Equal(t, row{[]string{"id", "synth_id"}, []interface{}{int64(2), "s2"}}, rows[1])
+	assert.Equal(t, int64(2), conv.BadRows())
+	assert.Equal(t, int64(2), conv.Stats.GoodRows["events"])
+}
+
+func TestConvertRecordValue(t *testing.T) {
+	created := time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC)
+	tests := []struct {
+		name    string
+		dialect string
+		t       ddl.Type
+		v       interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "bool", t: ddl.Type{Name: ddl.Bool}, v: true, want: true},
+		{name: "int64 from json number", t: ddl.Type{Name: ddl.Int64}, v: json.Number("12"), want: int64(12)},
+		{name: "int64 from float", t: ddl.Type{Name: ddl.Int64}, v: float64(1.5), wantErr: true},
+		{name: "float32 from float64", t: ddl.Type{Name: ddl.Float32}, v: float64(1.5), want: float32(1.5)},
+		{name: "float32 overflow", t: ddl.Type{Name: ddl.Float32}, v: float64(1e300), wantErr: true},
+		{name: "float64 from float32", t: ddl.Type{Name: ddl.Float64}, v: float32(1.5), want: float64(1.5)},
+		{name: "float64 from json number", t: ddl.Type{Name: ddl.Float64}, v: json.Number("2.5"), want: float64(2.5)},
+		{name: "numeric", t: ddl.Type{Name: ddl.Numeric}, v: big.NewRat(12345, 100), want: big.NewRat(12345, 100)},
+		{name: "numeric from json number", t: ddl.Type{Name: ddl.Numeric}, v: json.Number("1.25"), want: big.NewRat(5, 4)},
+		{name: "pg numeric", dialect: constants.DIALECT_POSTGRESQL, t: ddl.Type{Name: ddl.Numeric}, v: big.NewRat(12345, 100), want: sp.PGNumeric{Numeric: "123.450000000", Valid: true}},
+		{name: "string", t: ddl.Type{Name: ddl.String}, v: "s", want: "s"},
+		{name: "string from decimal", t: ddl.Type{Name: ddl.String}, v: big.NewRat(1, 2), want: "0.5"},
+		{name: "string from wide decimal", t: ddl.Type{Name: ddl.String}, v: new(big.Rat).SetFrac64(1, 1e12), want: "0.000000000001"},
+		{name: "string from date", t: ddl.Type{Name: ddl.String}, v: civil.Date{Year: 2024, Month: 3, Day: 4}, want: "2024-03-04"},
+		{name: "string from list", t: ddl.Type{Name: ddl.String}, v: []interface{}{int64(1), "a"}, want: `[1,"a"]`},
+		{name: "json from raw message", t: ddl.Type{Name: ddl.JSON}, v: json.RawMessage(`{"a":1}`), want: `{"a":1}`},
+		{name: "json from map", t: ddl.Type{Name: ddl.JSON}, v: map[string]interface{}{"b": []byte("hi")}, want: `{"b":"aGk="}`},
+		{name: "json from string", t: ddl.Type{Name: ddl.JSON}, v: "s", want: `"s"`},
+		{name: "bytes", t: ddl.Type{Name: ddl.Bytes}, v: []byte("hi"), want: []byte("hi")},
+		{name: "bytes from base64", t: ddl.Type{Name: ddl.Bytes}, v: "aGk=", want: []byte("hi")},
+		{name: "date", t: ddl.Type{Name: ddl.Date}, v: civil.Date{Year: 2024, Month: 3, Day: 4}, want: civil.Date{Year: 2024, Month: 3, Day: 4}},
+		{name: "date from string", t: ddl.Type{Name: ddl.Date}, v: "2024-03-04", want: civil.Date{Year: 2024, Month: 3, Day: 4}},
+		{name: "timestamp", t: ddl.Type{Name: ddl.Timestamp}, v: created, want: created},
+		{name: "timestamp from string", t: ddl.Type{Name: ddl.Timestamp}, v: "2024-03-04T05:06:07Z", want: created},
+		{name: "bool from string", t: ddl.Type{Name: ddl.Bool}, v: "true", wantErr: true},
+		{name: "int64 array", t: ddl.Type{Name: ddl.Int64, IsArray: true}, v: []interface{}{int64(1), nil}, want: []sp.NullInt64{{Int64: 1, Valid: true}, {}}},
+		{name: "date array", t: ddl.Type{Name: ddl.Date, IsArray: true}, v: []interface{}{civil.Date{Year: 2024, Month: 3, Day: 4}}, want: []sp.NullDate{{Date: civil.Date{Year: 2024, Month: 3, Day: 4}, Valid: true}}},
+		{name: "numeric array", t: ddl.Type{Name: ddl.Numeric, IsArray: true}, v: []interface{}{big.NewRat(1, 2)}, want: []sp.NullNumeric{{Numeric: *big.NewRat(1, 2), Valid: true}}},
+		{name: "bytes array", t: ddl.Type{Name: ddl.Bytes, IsArray: true}, v: []interface{}{[]byte("a"), nil}, want: [][]byte{[]byte("a"), nil}},
+		{name: "array from scalar", t: ddl.Type{Name: ddl.Int64, IsArray: true}, v: int64(1), wantErr: true},
+		{name: "array with bad element", t: ddl.Type{Name: ddl.Int64, IsArray: true}, v: []interface{}{"a"}, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := convertRecordValue(tc.dialect, tc.t, tc.v)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package import_file
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// recordColumn is a column of a Parquet, Avro or JSONL file, with the
+// Spanner type its values are imported as.
+type recordColumn struct {
+	Name    string
+	T       ddl.Type
+	NotNull bool
+}
+
+// recordReader reads the records of a Parquet, Avro or JSONL file. The
+// values of a record are nil, bool, int64, float32, float64, string,
+// []byte, *big.Rat, civil.Date, time.Time, json.Number for JSON numbers,
+// json.RawMessage for JSON text, []interface{} for lists and
+// map[string]interface{} for nested records and maps.
+type recordReader interface {
+	// Columns returns the columns of the file. The columns of a JSONL file
+	// are inferred from its records, which are all read.
+	Columns() ([]recordColumn, error)
+	// Next returns the values of the next record, keyed by column name. It
+	// returns io.EOF after the last record.
+	Next() (map[string]interface{}, error)
+	// Close releases the resources of the reader, such as temporary files.
+	Close() error
+}
+
+// recordValueError is returned for a record holding a value that can't be
+// read. The next records can still be read.
+type recordValueError struct {
+	err error
+}
+
+func (e *recordValueError) Error() string {
+	return e.err.Error()
+}
+
+func newRecordReader(format string, r io.Reader) (recordReader, error) {
+	switch format {
+	case constants.PARQUET:
+		return newParquetReader(r)
+	case constants.AVRO:
+		return newAvroReader(r)
+	case constants.JSONL:
+		return newJsonlReader(r), nil
+	}
+	return nil, fmt.Errorf("format %s not supported", format)
+}
+
+// readerAt returns r as an io.ReaderAt, with its size, and a function
+// that releases it. Readers that don't support random access, such as
+// objects read from Cloud Storage or decompressed files, are spooled to a
+// temporary file, so that large files aren't held in memory.
+func readerAt(r io.Reader) (io.ReaderAt, int64, func() error, error) {
+	noop := func() error { return nil }
+	switch r := r.(type) {
+	case *os.File:
+		fi, err := r.Stat()
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		return r, fi.Size(), noop, nil
+	case interface {
+		io.ReaderAt
+		Size() int64
+	}:
+		return r, r.Size(), noop, nil
+	}
+	f, err := os.CreateTemp("", "spanner-migration-tool-import-*")
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("can't create temporary file: %w", err)
+	}
+	release := func() error {
+		f.Close()
+		return os.Remove(f.Name())
+	}
+	size, err := io.Copy(f, r)
+	if err != nil {
+		release()
+		return nil, 0, nil, fmt.Errorf("can't copy source file to temporary file %s: %w", f.Name(), err)
+	}
+	return f, size, release, nil
+}
+
+// arrayType returns the type of an array of t. Spanner arrays can't be
+// nested, so lists of lists are imported as JSON.
+func arrayType(t ddl.Type) ddl.Type {
+	if t.IsArray {
+		return ddl.Type{Name: ddl.JSON}
+	}
+	t.IsArray = true
+	return t
+}
+
+// numericType returns the type of decimals of the given precision and
+// scale: NUMERIC if it can hold them, and STRING otherwise.
+func numericType(precision, scale int) ddl.Type {
+	if scale <= 9 && precision-scale <= 29 {
+		return ddl.Type{Name: ddl.Numeric}
+	}
+	return ddl.Type{Name: ddl.String, Len: ddl.MaxLength}
+}
+
+// jsonValue converts a record value to a value encoding/json encodes as
+// the JSON representation of the value.
+func jsonValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case []byte:
+		return base64.StdEncoding.EncodeToString(x)
+	case *big.Rat:
+		return json.Number(decimalString(x))
+	case civil.Date:
+		return x.String()
+	case time.Time:
+		return x.UTC().Format(time.RFC3339Nano)
+	case []interface{}:
+		l := make([]interface{}, len(x))
+		for i, e := range x {
+			l[i] = jsonValue(e)
+		}
+		return l
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, e := range x {
+			m[k] = jsonValue(e)
+		}
+		return m
+	}
+	return v
+}
+
+// decimalString returns the text of r with as many digits as decimals of
+// the scale of r need, and with the scale of NUMERIC otherwise.
+func decimalString(r *big.Rat) string {
+	p := big.NewInt(1)
+	for scale := 0; scale <= 38; scale++ {
+		if new(big.Int).Mod(p, r.Denom()).Sign() == 0 {
+			return r.FloatString(scale)
+		}
+		p.Mul(p, big.NewInt(10))
+	}
+	return spanner.NumericString(r)
+}
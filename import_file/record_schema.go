@@ -0,0 +1,118 @@
+package import_file
+
+import (
+	"context"
+	"fmt"
+
+	spanneraccessor "github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/parse"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/file_reader"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	adminpb "google.golang.org/genproto/googleapis/spanner/admin/database/v1"
+)
+
+var NewRecordSchema = newRecordSchema
+
+// RecordSchema creates the table for a Parquet, Avro or JSONL file from the
+// schema of the file.
+type RecordSchema interface {
+	CreateSchema(ctx context.Context, dialect string, sp spanneraccessor.SpannerAccessor) error
+}
+
+type RecordSchemaImpl struct {
+	ProjectId        string
+	InstanceId       string
+	DbName           string
+	TableName        string
+	SourceFormat     string
+	SourceFileReader file_reader.FileReader
+}
+
+func newRecordSchema(projectId, instanceId, dbName, tableName, sourceFormat string, sourceFileReader file_reader.FileReader) RecordSchema {
+	return &RecordSchemaImpl{
+		ProjectId:        projectId,
+		InstanceId:       instanceId,
+		DbName:           dbName,
+		TableName:        tableName,
+		SourceFormat:     sourceFormat,
+		SourceFileReader: sourceFileReader,
+	}
+}
+
+func (source *RecordSchemaImpl) CreateSchema(ctx context.Context, dialect string, sp spanneraccessor.SpannerAccessor) error {
+	dbURI := fmt.Sprintf("projects/%s/instances/%s/databases/%s", source.ProjectId, source.InstanceId, source.DbName)
+
+	tableExists, err := sp.TableExists(ctx, source.TableName)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("Unable to check existing schema %v", err))
+		return err
+	}
+	if tableExists {
+		logger.Log.Info(fmt.Sprintf("table %s exists ", source.TableName))
+		return nil
+	}
+
+	sourceIoReader, err := source.SourceFileReader.ResetReader(ctx)
+	if err != nil {
+		return fmt.Errorf("can't read source file: %w", err)
+	}
+	reader, err := newRecordReader(source.SourceFormat, sourceIoReader)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	cols, err := reader.Columns()
+	if err != nil {
+		return fmt.Errorf("can't read schema of source file: %w", err)
+	}
+	if len(cols) == 0 {
+		return fmt.Errorf("source file has no columns")
+	}
+
+	stmt := getRecordCreateTableStmt(source.TableName, cols, dialect)
+	logger.Log.Debug(fmt.Sprintf("create table cmd %s ==", stmt))
+	req := &adminpb.UpdateDatabaseDdlRequest{
+		Database:   dbURI,
+		Statements: []string{stmt},
+	}
+	op, err := sp.GetSpannerAdminClient().UpdateDatabaseDdl(ctx, req)
+	if err != nil {
+		return fmt.Errorf("can't build UpdateDatabaseDdlRequest: %w", parse.AnalyzeError(err, dbURI))
+	}
+	if err := op.Wait(ctx); err != nil {
+		return fmt.Errorf("UpdateDatabaseDdl call failed: %w", parse.AnalyzeError(err, dbURI))
+	}
+
+	logger.Log.Info(fmt.Sprintf("Created table %v successfully\n", source.TableName))
+	return nil
+}
+
+// getRecordCreateTableStmt returns the CREATE TABLE statement for a table
+// with the given columns. Files have no primary key, so the table is keyed
+// by a synthetic UUID column, filled in when the data is imported.
+func getRecordCreateTableStmt(tableName string, cols []recordColumn, dialect string) string {
+	ct := ddl.CreateTable{
+		Name:    tableName,
+		Id:      tableName,
+		ColDefs: map[string]ddl.ColumnDef{},
+	}
+	hasSynthId := false
+	for _, c := range cols {
+		ct.ColIds = append(ct.ColIds, c.Name)
+		ct.ColDefs[c.Name] = ddl.ColumnDef{Name: c.Name, Id: c.Name, T: c.T, NotNull: c.NotNull}
+		hasSynthId = hasSynthId || c.Name == internal.SyntheticPrimaryKey
+	}
+	if !hasSynthId {
+		ct.ColIds = append(ct.ColIds, internal.SyntheticPrimaryKey)
+		ct.ColDefs[internal.SyntheticPrimaryKey] = ddl.ColumnDef{
+			Name:    internal.SyntheticPrimaryKey,
+			Id:      internal.SyntheticPrimaryKey,
+			T:       ddl.Type{Name: ddl.String, Len: 36},
+			NotNull: true,
+		}
+	}
+	ct.PrimaryKeys = []ddl.IndexKey{{ColId: internal.SyntheticPrimaryKey, Order: 1}}
+	return ct.PrintCreateTable(ddl.NewSchema(), ddl.Config{ProtectIds: true, SpDialect: dialect})
+}
@@ -0,0 +1,108 @@
+package import_file
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/spanner"
+	spanneradmin "github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/clients/spanner/admin"
+	spannerclient "github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/clients/spanner/client"
+	spanneraccessor "github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/file_reader"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordSchemaImpl_CreateSchema(t *testing.T) {
+	ctx := context.Background()
+	jsonl := `{"id": 1, "name": "a"}`
+
+	tests := []struct {
+		name              string
+		data              string
+		spannerClientMock spannerclient.SpannerClientMock
+		adminClientMock   *spanneradmin.AdminClientMock
+		wantErr           bool
+	}{
+		{
+			name:              "successful schema creation",
+			data:              jsonl,
+			spannerClientMock: getSpannerClientMock(getDefaultRowIteratoMock()),
+			adminClientMock:   getSpannerAdminClientMock(nil),
+		},
+		{
+			name: "table exists",
+			spannerClientMock: getSpannerClientMock(&spannerclient.RowIteratorMock{
+				NextMock: func() (*spanner.Row, error) {
+					return &spanner.Row{}, nil
+				},
+				StopMock: func() {},
+			}),
+			adminClientMock: getSpannerAdminClientMock(errors.New("unexpected ddl")),
+		},
+		{
+			name:              "no columns",
+			data:              "",
+			spannerClientMock: getSpannerClientMock(getDefaultRowIteratoMock()),
+			adminClientMock:   getSpannerAdminClientMock(nil),
+			wantErr:           true,
+		},
+		{
+			name:              "update database ddl error",
+			data:              jsonl,
+			spannerClientMock: getSpannerClientMock(getDefaultRowIteratoMock()),
+			adminClientMock:   getSpannerAdminClientMock(errors.New("update error")),
+			wantErr:           true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := RecordSchemaImpl{
+				ProjectId:    "test-project",
+				InstanceId:   "test-instance",
+				DbName:       "test-db",
+				TableName:    "test_table",
+				SourceFormat: constants.JSONL,
+				SourceFileReader: &file_reader.MockFileReader{
+					ResetReaderFn: func(ctx context.Context) (io.Reader, error) {
+						return strings.NewReader(tt.data), nil
+					},
+				},
+			}
+			spannerAccessor := &spanneraccessor.SpannerAccessorImpl{SpannerClient: tt.spannerClientMock, AdminClient: tt.adminClientMock}
+			if err := source.CreateSchema(ctx, constants.DIALECT_GOOGLESQL, spannerAccessor); (err != nil) != tt.wantErr {
+				t.Errorf("RecordSchemaImpl.CreateSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_getRecordCreateTableStmt(t *testing.T) {
+	cols := []recordColumn{
+		{Name: "id", T: ddl.Type{Name: ddl.Int64}, NotNull: true},
+		{Name: "tags", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: true}},
+	}
+	stmt := getRecordCreateTableStmt("events", cols, constants.DIALECT_GOOGLESQL)
+	assert.Equal(t, "CREATE TABLE `events` (\n"+
+		"\t`id` INT64 NOT NULL ,\n"+
+		"\t`tags` ARRAY<STRING(MAX)>,\n"+
+		"\t`synth_id` STRING(36) NOT NULL ,\n"+
+		") PRIMARY KEY (`synth_id`)", stmt)
+
+	stmt = getRecordCreateTableStmt("events", cols, constants.DIALECT_POSTGRESQL)
+	assert.Equal(t, "CREATE TABLE \"events\" (\n"+
+		"\t\"id\" INT8 NOT NULL ,\n"+
+		"\t\"tags\" VARCHAR(2621440),\n"+
+		"\t\"synth_id\" VARCHAR(36) NOT NULL ,\n"+
+		"\tPRIMARY KEY (\"synth_id\")\n"+
+		")", stmt)
+
+	// A synth_id column of the file is used as the key.
+	stmt = getRecordCreateTableStmt("events", []recordColumn{{Name: "synth_id", T: ddl.Type{Name: ddl.String, Len: 36}, NotNull: true}}, constants.DIALECT_GOOGLESQL)
+	assert.Equal(t, 1, strings.Count(stmt, "`synth_id` STRING(36)"))
+}
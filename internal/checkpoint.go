@@ -0,0 +1,309 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+)
+
+// DefaultCheckpointInterval is the number of rows after which buffered
+// rows are flushed to Spanner and the checkpoint is saved.
+const DefaultCheckpointInterval = 100 * 1000
+
+// Checkpoint records the progress of a bulk data migration so that an
+// interrupted migration can be resumed. Progress is kept per Spanner
+// table and, for tables read in key ranges, per range. It only moves
+// forward once the rows it covers have been flushed to Spanner, so a
+// resumed migration re-reads at most the rows written since the last
+// flush (which it overwrites, see Resumed).
+//
+// Checkpoint is safe for concurrent use.
+type Checkpoint struct {
+	Database   string                      // URI of the target Spanner database.
+	SchemaDone bool                        // Whether the Spanner schema has been created.
+	Tables     map[string]*TableCheckpoint // Maps Spanner table name to its progress.
+	UpdatedAt  time.Time
+
+	// Interval is the number of rows after which a flush is requested.
+	Interval int64 `json:"-"`
+	// Resumed is true if the checkpoint was loaded from a previous run.
+	Resumed bool `json:"-"`
+
+	store    CheckpointStore
+	keyCols  map[string]string // Maps Spanner table name to the name of its range column.
+	unsaved  int64             // Rows added since the last flush.
+	lock     sync.Mutex
+	saveLock sync.Mutex
+}
+
+// TableCheckpoint is the progress of a table.
+type TableCheckpoint struct {
+	Done   bool
+	Rows   int64              // Rows flushed to Spanner.
+	Ranges []*RangeCheckpoint // Key ranges the table is read in, if any.
+
+	pendingRows int64
+}
+
+// RangeCheckpoint is the progress of a key range of a table. Rows of the
+// range are read in key order, so all rows with keys up to LastKey have
+// been flushed.
+type RangeCheckpoint struct {
+	Start   *int64
+	End     *int64
+	LastKey *int64
+	Done    bool
+	Rows    int64
+
+	pendingKey  *int64
+	pendingRows int64
+	finished    bool
+}
+
+// CheckpointStore persists a Checkpoint between runs.
+type CheckpointStore interface {
+	// Load returns the saved checkpoint, or nil if there is none.
+	Load() ([]byte, error)
+	Save(data []byte) error
+	// String describes where the checkpoint is stored.
+	String() string
+}
+
+// FileCheckpointStore stores a checkpoint in a local file.
+type FileCheckpointStore struct {
+	Path string
+}
+
+func (fs *FileCheckpointStore) Load() ([]byte, error) {
+	data, err := os.ReadFile(fs.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// Save writes the checkpoint to a temporary file first, so that a crash
+// while saving doesn't leave a truncated checkpoint behind.
+func (fs *FileCheckpointStore) Save(data []byte) error {
+	tmp := fs.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, fs.Path)
+}
+
+func (fs *FileCheckpointStore) String() string {
+	return fmt.Sprintf("file %s", fs.Path)
+}
+
+// OpenCheckpoint returns the checkpoint for a migration to database dbURI
+// kept in store. If resume is true, the progress saved by a previous run
+// is loaded; it is an error if that progress is for another database.
+// Otherwise a new checkpoint is started, replacing any saved one.
+func OpenCheckpoint(store CheckpointStore, dbURI string, resume bool) (*Checkpoint, error) {
+	cp := &Checkpoint{
+		Database: dbURI,
+		Tables:   make(map[string]*TableCheckpoint),
+		Interval: DefaultCheckpointInterval,
+		store:    store,
+		keyCols:  make(map[string]string),
+	}
+	if !resume {
+		return cp, nil
+	}
+	data, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("can't read checkpoint from %s: %v", store, err)
+	}
+	if data == nil {
+		logger.Log.Info(fmt.Sprintf("No checkpoint found in %s, starting from the beginning", store))
+		return cp, nil
+	}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("can't parse checkpoint from %s: %v", store, err)
+	}
+	if cp.Database != dbURI {
+		return nil, fmt.Errorf("checkpoint in %s is for database %s, not %s", store, cp.Database, dbURI)
+	}
+	if cp.Tables == nil {
+		cp.Tables = make(map[string]*TableCheckpoint)
+	}
+	cp.Resumed = true
+	return cp, nil
+}
+
+// Table returns the progress of Spanner table spTable.
+func (cp *Checkpoint) Table(spTable string) TableCheckpoint {
+	cp.lock.Lock()
+	defer cp.lock.Unlock()
+	tcp := cp.table(spTable)
+	t := TableCheckpoint{Done: tcp.Done, Rows: tcp.Rows}
+	for _, r := range tcp.Ranges {
+		rc := *r
+		t.Ranges = append(t.Ranges, &rc)
+	}
+	return t
+}
+
+// StartTable records that spTable is about to be read, in ranges of
+// column keyCol (a Spanner column name) if ranges isn't empty. Ranges
+// are only recorded the first time a table is read: a resumed migration
+// reuses them. A table read with a single query is read again from the
+// start, so its progress is reset.
+func (cp *Checkpoint) StartTable(spTable, keyCol string, ranges []*KeyRange) {
+	cp.lock.Lock()
+	defer cp.lock.Unlock()
+	tcp := cp.table(spTable)
+	if len(tcp.Ranges) == 0 {
+		tcp.Rows = 0
+		for _, kr := range ranges {
+			tcp.Ranges = append(tcp.Ranges, &RangeCheckpoint{Start: kr.Start, End: kr.End})
+		}
+	}
+	if len(tcp.Ranges) > 0 {
+		cp.keyCols[spTable] = keyCol
+	}
+}
+
+// RowAdded records that a row of spTable has been handed to the writer.
+// It returns true when Interval rows have been added since the last
+// flush, in which case the caller should flush and call Flushed.
+func (cp *Checkpoint) RowAdded(spTable string, cols []string, vals []interface{}) bool {
+	cp.lock.Lock()
+	defer cp.lock.Unlock()
+	tcp := cp.table(spTable)
+	tcp.pendingRows++
+	if keyCol, ok := cp.keyCols[spTable]; ok {
+		if key, ok := rowKey(keyCol, cols, vals); ok {
+			if r := tcp.rangeFor(key); r != nil {
+				r.pendingKey = &key
+				r.pendingRows++
+			}
+		}
+	}
+	cp.unsaved++
+	return cp.Interval > 0 && cp.unsaved >= cp.Interval
+}
+
+// RangeFinished records that all rows of the range of spTable ending at
+// end have been handed to the writer. The range is done once they have
+// been flushed. Ranges are identified by their end since, unlike their
+// start, it doesn't change when a range is resumed.
+func (cp *Checkpoint) RangeFinished(spTable string, end *int64) {
+	cp.lock.Lock()
+	defer cp.lock.Unlock()
+	for _, r := range cp.table(spTable).Ranges {
+		if equalBound(r.End, end) {
+			r.finished = true
+		}
+	}
+}
+
+// TableDone records that all rows of spTable have been flushed, and saves
+// the checkpoint.
+func (cp *Checkpoint) TableDone(spTable string) {
+	cp.lock.Lock()
+	tcp := cp.table(spTable)
+	tcp.Done = true
+	for _, r := range tcp.Ranges {
+		r.finished = true
+	}
+	cp.lock.Unlock()
+	cp.Flushed()
+}
+
+// SetSchemaDone records that the Spanner schema has been created, and
+// saves the checkpoint.
+func (cp *Checkpoint) SetSchemaDone() {
+	cp.lock.Lock()
+	cp.SchemaDone = true
+	cp.lock.Unlock()
+	cp.Flushed()
+}
+
+// Flushed records that all rows added so far have been flushed to
+// Spanner, and saves the checkpoint. Failing to save is logged but isn't
+// fatal: the migration just has more to redo if it is resumed.
+func (cp *Checkpoint) Flushed() {
+	cp.saveLock.Lock()
+	defer cp.saveLock.Unlock()
+	cp.lock.Lock()
+	for _, tcp := range cp.Tables {
+		tcp.Rows += tcp.pendingRows
+		tcp.pendingRows = 0
+		for _, r := range tcp.Ranges {
+			if r.pendingKey != nil {
+				r.LastKey = r.pendingKey
+			}
+			r.Rows += r.pendingRows
+			r.pendingKey, r.pendingRows = nil, 0
+			if r.finished {
+				r.Done = true
+			}
+		}
+	}
+	cp.unsaved = 0
+	cp.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(cp, "", "  ")
+	cp.lock.Unlock()
+	if err == nil {
+		err = cp.store.Save(data)
+	}
+	if err != nil {
+		logger.Log.Warn(fmt.Sprintf("Couldn't save checkpoint to %s: %v", cp.store, err))
+	}
+}
+
+func (cp *Checkpoint) table(spTable string) *TableCheckpoint {
+	tcp, ok := cp.Tables[spTable]
+	if !ok {
+		tcp = &TableCheckpoint{}
+		cp.Tables[spTable] = tcp
+	}
+	return tcp
+}
+
+func (tcp *TableCheckpoint) rangeFor(key int64) *RangeCheckpoint {
+	for _, r := range tcp.Ranges {
+		if (r.Start == nil || key >= *r.Start) && (r.End == nil || key < *r.End) {
+			return r
+		}
+	}
+	return nil
+}
+
+func rowKey(keyCol string, cols []string, vals []interface{}) (int64, bool) {
+	for i, c := range cols {
+		if c == keyCol && i < len(vals) {
+			key, ok := vals[i].(int64)
+			return key, ok
+		}
+	}
+	return 0, false
+}
+
+func equalBound(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckpoint(t *testing.T) {
+	i64 := func(i int64) *int64 { return &i }
+	store := &FileCheckpointStore{Path: filepath.Join(t.TempDir(), "checkpoint.json")}
+	const db = "projects/p/instances/i/databases/d"
+
+	cp, err := OpenCheckpoint(store, db, true)
+	assert.Nil(t, err)
+	assert.False(t, cp.Resumed)
+	cp.Interval = 3
+
+	cp.StartTable("t", "id", []*KeyRange{{ColId: "c1", End: i64(10)}, {ColId: "c1", Start: i64(10)}})
+	assert.False(t, cp.RowAdded("t", []string{"id", "v"}, []interface{}{int64(1), "a"}))
+	assert.False(t, cp.RowAdded("t", []string{"id", "v"}, []interface{}{int64(10), "b"}))
+	// Progress only moves forward once rows have been flushed.
+	assert.Nil(t, cp.Table("t").Ranges[0].LastKey)
+	assert.True(t, cp.RowAdded("t", []string{"id", "v"}, []interface{}{int64(2), "c"}))
+	cp.RangeFinished("t", i64(10))
+	cp.Flushed()
+	cp.StartTable("u", "", nil)
+	cp.RowAdded("u", []string{"x"}, []interface{}{"y"})
+	cp.TableDone("u")
+
+	// A new run that resumes picks up the flushed progress.
+	cp, err = OpenCheckpoint(store, db, true)
+	assert.Nil(t, err)
+	assert.True(t, cp.Resumed)
+	tcp := cp.Table("t")
+	assert.False(t, tcp.Done)
+	assert.Equal(t, int64(3), tcp.Rows)
+	assert.Equal(t, 2, len(tcp.Ranges))
+	assert.True(t, tcp.Ranges[0].Done)
+	assert.Equal(t, int64(2), *tcp.Ranges[0].LastKey)
+	assert.False(t, tcp.Ranges[1].Done)
+	assert.Equal(t, int64(10), *tcp.Ranges[1].LastKey)
+	assert.Equal(t, TableCheckpoint{Done: true, Rows: 1}, cp.Table("u"))
+
+	// Ranges recorded by a previous run are kept.
+	cp.StartTable("t", "id", []*KeyRange{{ColId: "c1"}})
+	assert.Equal(t, 2, len(cp.Table("t").Ranges))
+
+	_, err = OpenCheckpoint(store, "projects/p/instances/i/databases/other", true)
+	assert.NotNil(t, err)
+
+	// Not resuming starts from scratch.
+	cp, err = OpenCheckpoint(store, db, false)
+	assert.Nil(t, err)
+	assert.False(t, cp.Resumed)
+	assert.Equal(t, TableCheckpoint{}, cp.Table("t"))
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression formats of input files.
+const (
+	CompressionNone  = ""
+	CompressionGzip  = "gzip"
+	CompressionZstd  = "zstd"
+	CompressionBzip2 = "bzip2"
+)
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	bzip2Magic = []byte("BZh")
+)
+
+// DetectCompression returns the compression format of a file from header,
+// the first bytes of its contents, or from the extension of name when they
+// don't start with a known magic number. A damaged file is then reported as
+// such, instead of being read as uncompressed.
+func DetectCompression(name string, header []byte) string {
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		return CompressionGzip
+	case bytes.HasPrefix(header, zstdMagic):
+		return CompressionZstd
+	case len(header) >= 5 && bytes.HasPrefix(header, bzip2Magic) && '1' <= header[3] && header[3] <= '9' && (header[4] == 0x31 || header[4] == 0x17):
+		// The block size is followed by the magic number of a block, or of
+		// the end of the stream.
+		return CompressionBzip2
+	}
+	return CompressionFromName(name)
+}
+
+// CompressionFromName returns the compression format of a file from the
+// extension of its name.
+func CompressionFromName(name string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".gz", ".gzip":
+		return CompressionGzip
+	case ".zst", ".zstd":
+		return CompressionZstd
+	case ".bz2":
+		return CompressionBzip2
+	}
+	return CompressionNone
+}
+
+// TrimCompressionExt removes the extension of a compressed file from name,
+// e.g. "dump.sql.gz" becomes "dump.sql".
+func TrimCompressionExt(name string) string {
+	if CompressionFromName(name) == CompressionNone {
+		return name
+	}
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
+// Decompress returns a reader of the decompressed contents of r, which is
+// read as a stream. Contents that aren't compressed are returned as is.
+// name is the name of the file, used for the error messages.
+func Decompress(r io.Reader, name string) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(5)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("can't read %s: %w", name, err)
+	}
+	switch DetectCompression(name, header) {
+	case CompressionGzip:
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("can't read gzip file %s: %w", name, err)
+		}
+		return gr, nil
+	case CompressionZstd:
+		// With a concurrency of 1, the stream is decoded as it is read,
+		// without goroutines.
+		zr, err := zstd.NewReader(br, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, fmt.Errorf("can't read zstd file %s: %w", name, err)
+		}
+		return zr.IOReadCloser(), nil
+	case CompressionBzip2:
+		return io.NopCloser(bzip2.NewReader(br)), nil
+	}
+	return io.NopCloser(br), nil
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
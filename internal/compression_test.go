@@ -0,0 +1,107 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const csvData = "a,b\n1,2\n"
+
+// bzip2Data is csvData compressed with bzip2, which the standard library
+// can't write.
+const bzip2Data = "425a6839314159265359bf87407f00000359000010000430003000200030c00869b28823278bb9229c28485fc3a03f80"
+
+func compressed(t *testing.T, compression string) []byte {
+	switch compression {
+	case CompressionGzip:
+		var b bytes.Buffer
+		w := gzip.NewWriter(&b)
+		w.Write([]byte(csvData))
+		require.NoError(t, w.Close())
+		return b.Bytes()
+	case CompressionZstd:
+		w, err := zstd.NewWriter(nil)
+		require.NoError(t, err)
+		return w.EncodeAll([]byte(csvData), nil)
+	case CompressionBzip2:
+		b, err := hex.DecodeString(bzip2Data)
+		require.NoError(t, err)
+		return b
+	}
+	return []byte(csvData)
+}
+
+func TestDetectCompression(t *testing.T) {
+	for _, c := range []string{CompressionNone, CompressionGzip, CompressionZstd, CompressionBzip2} {
+		assert.Equal(t, c, DetectCompression("data", compressed(t, c)), c)
+	}
+	// The extension is only used when the contents don't tell.
+	assert.Equal(t, CompressionGzip, DetectCompression("data.csv.gz", []byte(csvData)))
+	assert.Equal(t, CompressionZstd, DetectCompression("gs://bucket/dump.SQL.ZST", nil))
+	assert.Equal(t, CompressionBzip2, DetectCompression("dump.sql.bz2", nil))
+	assert.Equal(t, CompressionGzip, DetectCompression("data.csv", compressed(t, CompressionGzip)))
+	assert.Equal(t, CompressionNone, DetectCompression("BZh", []byte("BZh is not bzip2")))
+	assert.Equal(t, CompressionNone, DetectCompression("gz", nil))
+}
+
+func TestTrimCompressionExt(t *testing.T) {
+	assert.Equal(t, "shop.t.sql", TrimCompressionExt("shop.t.sql.gz"))
+	assert.Equal(t, "shop.t.sql", TrimCompressionExt("shop.t.sql.zst"))
+	assert.Equal(t, "shop.t.sql", TrimCompressionExt("shop.t.sql"))
+}
+
+func TestDecompress(t *testing.T) {
+	for _, c := range []string{CompressionNone, CompressionGzip, CompressionZstd, CompressionBzip2} {
+		r, err := Decompress(bytes.NewReader(compressed(t, c)), "data")
+		require.NoError(t, err, c)
+		b, err := io.ReadAll(r)
+		assert.NoError(t, err, c)
+		assert.Equal(t, csvData, string(b), c)
+		assert.NoError(t, r.Close(), c)
+	}
+	r, err := Decompress(strings.NewReader(""), "empty.csv")
+	require.NoError(t, err)
+	b, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Empty(t, b)
+
+	_, err = Decompress(strings.NewReader(csvData), "data.csv.gz")
+	assert.Error(t, err)
+}
+
+func TestNewCompressedReader(t *testing.T) {
+	in := compressed(t, CompressionGzip)
+	p := NewProgress(int64(len(in)), "Progress", false, false, int(DefaultStatus))
+	r, err := NewCompressedReader(bytes.NewReader(in), "data.csv.gz", p)
+	require.NoError(t, err)
+	assert.Equal(t, "a,b\n", string(r.ReadLine()))
+	assert.Equal(t, "1,2\n", string(r.ReadLine()))
+	assert.Equal(t, "", string(r.ReadLine()))
+	assert.True(t, r.EOF)
+	// Offsets are in decompressed bytes, and progress in compressed bytes.
+	assert.Equal(t, len(csvData)+1, r.Offset)
+	assert.Equal(t, int64(len(in)), p.progress)
+	assert.Equal(t, 100, p.pct)
+}
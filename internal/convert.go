@@ -39,27 +39,36 @@ type Conv struct {
 	ToSource               map[string]NameAndCols       `json:"-"` // Maps from Spanner table name to source-DB table name and column mapping.
 	UsedNames              map[string]bool              `json:"-"` // Map storing the names that are already assigned to tables, indices or foreign key contraints.
 	dataSink               func(table string, cols []string, values []interface{})
-	DataFlush              func()                  `json:"-"` // Data flush is used to flush out remaining writes and wait for them to complete.
-	Location               *time.Location          // Timezone (for timestamp conversion).
-	sampleBadRows          rowSamples              // Rows that generated errors during conversion.
-	Stats                  stats                   `json:"-"`
-	TimezoneOffset         string                  // Timezone offset for timestamp conversion.
-	SpDialect              string                  // The dialect of the spanner database to which Spanner migration tool is writing.
-	UniquePKey             map[string][]string     // Maps Spanner table name to unique column name being used as primary key (if needed).
-	Audit                  Audit                   `json:"-"` // Stores the audit information for the database conversion
-	Rules                  []Rule                  // Stores applied rules during schema conversion
-	IsSharded              bool                    // Flag denoting if the migration is sharded or not
-	ConvLock               sync.RWMutex            `json:"-"` // ConvLock prevents concurrent map read/write operations. This lock will be used in all the APIs that either read or write elements to the conv object.
-	SpRegion               string                  // Leader Region for Spanner Instance
-	ResourceValidation     bool                    // Flag denoting if validation for resources to generated is complete
-	UI                     bool                    // Flag if UI interface was used for migration. ToDo: Remove flag after resource generation is introduced to UI
-	SpSequences            map[string]ddl.Sequence // Maps Spanner Sequences to Sequence Schema
-	SrcSequences           map[string]ddl.Sequence // Maps source-DB Sequences to Sequence schema information
-	SpProjectId            string                  // Spanner Project Id
-	SpInstanceId           string                  // Spanner Instance Id
-	Source                 string                  // Source Database type being migrated
+	sinkLock               sync.Mutex                // Serializes calls to dataSink made by WriteRowConcurrently.
+	convertLock            sync.Mutex                // Guards Stats.Unexpected and SyntheticPKeys, which rows converted concurrently update without holding ConvLock.
+	DataFlush              func()                    `json:"-"` // Data flush is used to flush out remaining writes and wait for them to complete.
+	Location               *time.Location            // Timezone (for timestamp conversion).
+	sampleBadRows          rowSamples                // Rows that generated errors during conversion.
+	Stats                  stats                     `json:"-"`
+	TimezoneOffset         string                    // Timezone offset for timestamp conversion.
+	SpDialect              string                    // The dialect of the spanner database to which Spanner migration tool is writing.
+	UniquePKey             map[string][]string       // Maps Spanner table name to unique column name being used as primary key (if needed).
+	Audit                  Audit                     `json:"-"` // Stores the audit information for the database conversion
+	Rules                  []Rule                    // Stores applied rules during schema conversion
+	IsSharded              bool                      // Flag denoting if the migration is sharded or not
+	ConvLock               sync.RWMutex              `json:"-"` // ConvLock prevents concurrent map read/write operations. This lock will be used in all the APIs that either read or write elements to the conv object.
+	SpRegion               string                    // Leader Region for Spanner Instance
+	ResourceValidation     bool                      // Flag denoting if validation for resources to generated is complete
+	UI                     bool                      // Flag if UI interface was used for migration. ToDo: Remove flag after resource generation is introduced to UI
+	SpSequences            map[string]ddl.Sequence   // Maps Spanner Sequences to Sequence Schema
+	SrcSequences           map[string]ddl.Sequence   // Maps source-DB Sequences to Sequence schema information
+	SpViews                map[string]ddl.CreateView // Maps Spanner view id to Spanner view
+	SrcViews               map[string]schema.View    // Maps source-DB view id to source view
+	SpProjectId            string                    // Spanner Project Id
+	SpInstanceId           string                    // Spanner Instance Id
+	Source                 string                    // Source Database type being migrated
 	DatabaseOptions        ddl.DatabaseOptions
 	DefaultIdentityOptions ddl.IdentityOptions // Default values to use for IDENTITY columns
+	BulkRead               BulkReadOptions     `json:"-"` // Controls how tables are read from the source during bulk data migration.
+	Checkpoint             *Checkpoint         `json:"-"` // Progress of the bulk data migration, if it is being checkpointed.
+	DeadLetters            *DeadLetterWriter   `json:"-"` // Saves rejected rows, if set.
+	Sampler                *Sampler            `json:"-"` // Selects the rows migrated, if only a sample of the data is.
+	TableSelection         *TableSelection     `json:"-"` // Restricts the tables and rows read from a source database, if set.
 }
 
 type InvalidCheckExp struct {
@@ -78,6 +87,28 @@ type AdditionalSchemaAttributes struct {
 
 type AdditionalDataAttributes struct {
 	ShardId string
+	// KeyRange restricts the rows read from the source table to a range of
+	// its primary key. Nil means the whole table is read.
+	KeyRange *KeyRange
+}
+
+// BulkReadOptions controls the parallelism of bulk data reads from
+// sources that read through an InfoSchema. The zero value reads one
+// table at a time with a single query per table.
+type BulkReadOptions struct {
+	ParallelTables int // Number of tables read concurrently.
+	ChunksPerTable int // Number of primary key ranges each table is split into and read concurrently.
+}
+
+// KeyRange is the range [Start, End) of values of the leading primary
+// key column ColId of a table. A nil Start or End leaves the range
+// unbounded on that side. If Ordered is set, rows are read in order of
+// ColId, so that a Checkpoint can record how far the range has got.
+type KeyRange struct {
+	ColId   string
+	Start   *int64
+	End     *int64
+	Ordered bool
 }
 
 type mode int
@@ -159,6 +190,12 @@ const (
 	PossibleOverflow
 	IdentitySkipRange
 	GeneratedColumnValueError
+	ViewUnsupportedSql
+	ViewMissingTable
+	FullTextSearchIndex
+	VectorIndex
+	VectorIndexNotSupported
+	PartialIndex
 )
 
 const (
@@ -198,10 +235,12 @@ type row struct {
 // b) successfully converted and successfully written to Spanner.
 // c) successfully converted, but an error occurs when writing the row to Spanner.
 // d) unsuccessfully converted (we won't try to write such rows to Spanner).
+// e) successfully converted, but left out of the sample (see Sampler).
 type stats struct {
-	Rows       map[string]int64          // Count of rows encountered during processing (a + b + c + d), broken down by source table.
+	Rows       map[string]int64          // Count of rows encountered during processing (a + b + c + d + e), broken down by source table.
 	GoodRows   map[string]int64          // Count of rows successfully converted (b + c), broken down by source table.
 	BadRows    map[string]int64          // Count of rows where conversion failed (d), broken down by source table.
+	SampledOut map[string]int64          // Count of rows left out of the sample (e), broken down by source table.
 	Statement  map[string]*statementStat // Count of processed statements, broken down by statement type.
 	Unexpected map[string]int64          // Count of unexpected conditions, broken down by condition description.
 	Reparsed   int64                     // Count of times we re-parse dump data looking for end-of-statement.
@@ -301,6 +340,7 @@ func MakeConv() *Conv {
 			Rows:       make(map[string]int64),
 			GoodRows:   make(map[string]int64),
 			BadRows:    make(map[string]int64),
+			SampledOut: make(map[string]int64),
 			Statement:  make(map[string]*statementStat),
 			Unexpected: make(map[string]int64),
 		},
@@ -312,6 +352,8 @@ func MakeConv() *Conv {
 		Rules:           []Rule{},
 		SpSequences:     make(map[string]ddl.Sequence),
 		SrcSequences:    make(map[string]ddl.Sequence),
+		SpViews:         make(map[string]ddl.CreateView),
+		SrcViews:        make(map[string]schema.View),
 		DatabaseOptions: ddl.DatabaseOptions{},
 	}
 }
@@ -321,6 +363,7 @@ func (conv *Conv) ResetStats() {
 		Rows:       make(map[string]int64),
 		GoodRows:   make(map[string]int64),
 		BadRows:    make(map[string]int64),
+		SampledOut: make(map[string]int64),
 		Statement:  make(map[string]*statementStat),
 		Unexpected: make(map[string]int64),
 	}
@@ -355,6 +398,12 @@ func (conv *Conv) SetDataMode() {
 
 // WriteRow calls dataSink and updates row stats.
 func (conv *Conv) WriteRow(srcTable, spTable string, spCols []string, spVals []interface{}) {
+	if conv.Sampler != nil && !conv.Sampler.Keep(spTable, spCols, spVals) {
+		if conv.DataMode() {
+			conv.Stats.SampledOut[srcTable]++
+		}
+		return
+	}
 	if conv.Audit.DryRun {
 		conv.statsAddGoodRow(srcTable, conv.DataMode())
 	} else if conv.dataSink == nil {
@@ -370,6 +419,56 @@ func (conv *Conv) WriteRow(srcTable, spTable string, spCols []string, spVals []i
 	}
 }
 
+// WriteRowConcurrently is WriteRow for callers that write rows from
+// several goroutines. conv is only updated while holding ConvLock, and
+// dataSink, which needn't be thread-safe, is called while holding a lock
+// of its own so that other goroutines can update conv in the meantime.
+func (conv *Conv) WriteRowConcurrently(srcTable, spTable string, spCols []string, spVals []interface{}) {
+	if conv.Audit.DryRun || conv.dataSink == nil {
+		conv.ConvLock.Lock()
+		defer conv.ConvLock.Unlock()
+		conv.WriteRow(srcTable, spTable, spCols, spVals)
+		return
+	}
+	keep := conv.Sampler == nil || conv.Sampler.Keep(spTable, spCols, spVals)
+	if keep {
+		conv.sinkLock.Lock()
+		conv.dataSink(spTable, spCols, spVals)
+		conv.sinkLock.Unlock()
+	}
+	conv.ConvLock.Lock()
+	defer conv.ConvLock.Unlock()
+	if keep {
+		conv.statsAddGoodRow(srcTable, conv.DataMode())
+	} else if conv.DataMode() {
+		conv.Stats.SampledOut[srcTable]++
+	}
+}
+
+// BadRowConcurrently records a row of srcTable that couldn't be
+// converted, for callers that convert rows from several goroutines: conv
+// is only updated while holding ConvLock.
+func (conv *Conv) BadRowConcurrently(srcTable string, srcCols, vals []string, err error) {
+	conv.ConvLock.Lock()
+	defer conv.ConvLock.Unlock()
+	conv.Unexpected(fmt.Sprintf("Error while converting data: %s\n", err))
+	conv.StatsAddBadRow(srcTable, conv.DataMode())
+	conv.CollectBadRow(srcTable, srcCols, vals, err)
+}
+
+// NextSyntheticPKey returns the synthetic primary key of table tableId,
+// with the sequence number of its next row, or false if the table has
+// none. It may be called concurrently.
+func (conv *Conv) NextSyntheticPKey(tableId string) (SyntheticPKey, bool) {
+	conv.convertLock.Lock()
+	defer conv.convertLock.Unlock()
+	aux, ok := conv.SyntheticPKeys[tableId]
+	if ok {
+		conv.SyntheticPKeys[tableId] = SyntheticPKey{ColId: aux.ColId, Sequence: aux.Sequence + 1}
+	}
+	return aux, ok
+}
+
 // Rows returns the total count of data rows processed.
 func (conv *Conv) Rows() int64 {
 	n := int64(0)
@@ -414,8 +513,14 @@ func (conv *Conv) Unexpecteds() int64 {
 }
 
 // CollectBadRow updates the list of bad rows, while respecting
-// the byte limit for bad rows.
-func (conv *Conv) CollectBadRow(srcTable string, srcCols, vals []string) {
+// the byte limit for bad rows. If conv.DeadLetters is set, the row
+// and err (the reason it couldn't be converted) are also saved there.
+func (conv *Conv) CollectBadRow(srcTable string, srcCols, vals []string, err error) {
+	if conv.DeadLetters != nil {
+		if dlErr := conv.DeadLetters.ConvertFailed(conv, srcTable, srcCols, vals, err); dlErr != nil {
+			logger.Log.Warn(fmt.Sprintf("Couldn't save rejected row of table %s: %v", srcTable, dlErr))
+		}
+	}
 	r := &row{table: srcTable, cols: srcCols, vals: vals}
 	bytes := byteSize(r)
 	// Cap storage used by badRows. Keep at least one bad row.
@@ -543,6 +648,8 @@ func (conv *Conv) Unexpected(u string) {
 	VerbosePrintf("Unexpected condition: %s\n", u)
 	logger.Log.Debug("Unexpected condition", zap.String("condition", u))
 
+	conv.convertLock.Lock()
+	defer conv.convertLock.Unlock()
 	// Limit size of unexpected map. If over limit, then only
 	// update existing entries.
 	if _, ok := conv.Stats.Unexpected[u]; ok || len(conv.Stats.Unexpected) < 1000 {
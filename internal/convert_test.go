@@ -15,6 +15,8 @@
 package internal
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -87,6 +89,63 @@ func TestGetBadRows(t *testing.T) {
 	assert.Equal(t, 2, len(conv.SampleBadRows(100)))
 }
 
+func TestWriteRowConcurrently(t *testing.T) {
+	conv := MakeConv()
+	conv.SetDataMode()
+	// The sink isn't thread-safe: the race detector reports it if calls
+	// aren't serialized.
+	var rows int
+	conv.SetDataSink(func(table string, cols []string, vals []interface{}) { rows++ })
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				conv.WriteRowConcurrently("t", "t", []string{"a"}, []interface{}{int64(j)})
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 400, rows)
+	assert.Equal(t, int64(400), conv.Stats.GoodRows["t"])
+}
+
+func TestConvertRowsConcurrently(t *testing.T) {
+	conv := MakeConv()
+	conv.SetDataMode()
+	conv.SyntheticPKeys["t1"] = SyntheticPKey{ColId: "c9", Sequence: 0}
+	var wg sync.WaitGroup
+	seqs := make([][]int64, 4)
+	for i := range seqs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				aux, ok := conv.NextSyntheticPKey("t1")
+				assert.True(t, ok)
+				seqs[i] = append(seqs[i], aux.Sequence)
+				conv.Unexpected("converted")
+				conv.BadRowConcurrently("t", []string{"a"}, []string{"x"}, fmt.Errorf("bad"))
+			}
+		}(i)
+	}
+	wg.Wait()
+	// Each row has a sequence number of its own.
+	seen := make(map[int64]bool)
+	for _, l := range seqs {
+		for _, seq := range l {
+			seen[seq] = true
+		}
+	}
+	assert.Len(t, seen, 400)
+	assert.Equal(t, SyntheticPKey{ColId: "c9", Sequence: 400}, conv.SyntheticPKeys["t1"])
+	assert.Equal(t, int64(400), conv.Stats.Unexpected["converted"])
+	assert.Equal(t, int64(400), conv.Stats.BadRows["t"])
+	_, ok := conv.NextSyntheticPKey("t2")
+	assert.False(t, ok)
+}
+
 func TestAddPrimaryKeys(t *testing.T) {
 	addPrimaryKeyTests := []struct {
 		name           string
@@ -0,0 +1,395 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+)
+
+// Stages at which a row can be rejected.
+const (
+	DeadLetterStageConvert = "convert" // The row couldn't be converted to Spanner types.
+	DeadLetterStageWrite   = "write"   // Spanner rejected the converted row.
+)
+
+// Formats of dead-letter files.
+const (
+	DeadLetterFormatJSONL = "jsonl"
+	DeadLetterFormatCSV   = "csv"
+)
+
+// deadLetterCSVHeader is the header row of CSV dead-letter files. Lists of
+// columns and values are stored as JSON arrays.
+var deadLetterCSVHeader = []string{"stage", "src_table", "src_cols", "sp_table", "sp_cols", "values", "error"}
+
+// DeadLetter is a row that was rejected during data migration.
+//
+// For rows rejected at the convert stage, Values are the source values
+// of SrcCols. For rows rejected at the write stage, Values are the values
+// sent to Spanner for SpCols, in the text form used for CSV data, except
+// that BYTES values are base64-encoded; NULL values are left out. SrcCols and SpCols are matched up through the
+// session, and a column that only exists on one side has an empty name on
+// the other.
+type DeadLetter struct {
+	Stage    string   `json:"stage"`
+	SrcTable string   `json:"src_table"`
+	SrcCols  []string `json:"src_cols"`
+	SpTable  string   `json:"sp_table"`
+	SpCols   []string `json:"sp_cols"`
+	Values   []string `json:"values"`
+	Error    string   `json:"error"`
+}
+
+// DeadLetterWriter saves rejected rows to one file per Spanner table in a
+// directory, named after the table with the extension of the format. The
+// characters of table names other than letters, digits, '_', '-' and '.'
+// are escaped as %XX, so that each table has a file of its own.
+//
+// DeadLetterWriter is safe for concurrent use.
+type DeadLetterWriter struct {
+	Dir    string
+	Format string
+
+	files map[string]*deadLetterFile
+	lock  sync.Mutex
+}
+
+type deadLetterFile struct {
+	f   *os.File
+	buf *bufio.Writer
+	csv *csv.Writer
+}
+
+// NewDeadLetterWriter returns a DeadLetterWriter that saves rows to dir in
+// format, which is DeadLetterFormatJSONL or DeadLetterFormatCSV. Files are
+// only created once a row of their table is rejected.
+func NewDeadLetterWriter(dir, format string) (*DeadLetterWriter, error) {
+	switch format {
+	case DeadLetterFormatJSONL, DeadLetterFormatCSV:
+	default:
+		return nil, fmt.Errorf("unsupported dead-letter format %q: expected %s or %s", format, DeadLetterFormatJSONL, DeadLetterFormatCSV)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("can't create dead-letter directory %s: %v", dir, err)
+	}
+	return &DeadLetterWriter{Dir: dir, Format: format, files: make(map[string]*deadLetterFile)}, nil
+}
+
+// ConvertFailed saves a row of source table srcTable that couldn't be
+// converted.
+func (dw *DeadLetterWriter) ConvertFailed(conv *Conv, srcTable string, srcCols, vals []string, err error) error {
+	dl := DeadLetter{Stage: DeadLetterStageConvert, SrcTable: srcTable, SrcCols: srcCols, Values: vals, Error: errorString(err)}
+	if tableId, e := GetTableIdFromSrcName(conv.SrcSchema, srcTable); e == nil {
+		srcTbl, spTbl := conv.SrcSchema[tableId], conv.SpSchema[tableId]
+		dl.SpTable = spTbl.Name
+		colNameIdMap := GetSrcColNameIdMap(srcTbl)
+		for _, c := range srcCols {
+			dl.SpCols = append(dl.SpCols, spTbl.ColDefs[colNameIdMap[c]].Name)
+		}
+	}
+	return dw.Write(dl)
+}
+
+// WriteFailed saves a row of Spanner table spTable that Spanner rejected.
+func (dw *DeadLetterWriter) WriteFailed(conv *Conv, spTable string, spCols []string, vals []interface{}, err error) error {
+	dl := DeadLetter{Stage: DeadLetterStageWrite, SpTable: spTable, Error: errorString(err)}
+	tableId, e := GetTableIdFromSpName(conv.SpSchema, spTable)
+	var srcTbl schema.Table
+	if e == nil {
+		srcTbl = conv.SrcSchema[tableId]
+		dl.SrcTable = srcTbl.Name
+	}
+	for i, c := range spCols {
+		if i >= len(vals) {
+			break
+		}
+		v, ok := DeadLetterValue(vals[i])
+		if !ok {
+			continue
+		}
+		dl.SpCols = append(dl.SpCols, c)
+		dl.Values = append(dl.Values, v)
+		srcCol := ""
+		if e == nil {
+			if colId, err := GetColIdFromSpName(conv.SpSchema[tableId].ColDefs, c); err == nil {
+				srcCol = srcTbl.ColDefs[colId].Name
+			}
+		}
+		dl.SrcCols = append(dl.SrcCols, srcCol)
+	}
+	return dw.Write(dl)
+}
+
+// Write appends dl to the file of its Spanner table, or of its source
+// table if it has no Spanner table. The file is flushed, so that the row
+// isn't lost if the migration is stopped before Close, e.g. after its
+// checkpoint or CDC position has moved past the row.
+func (dw *DeadLetterWriter) Write(dl DeadLetter) error {
+	table := dl.SpTable
+	if table == "" {
+		table = dl.SrcTable
+	}
+	dw.lock.Lock()
+	defer dw.lock.Unlock()
+	df, err := dw.file(table)
+	if err != nil {
+		return err
+	}
+	if df.csv != nil {
+		df.csv.Write([]string{dl.Stage, dl.SrcTable, jsonList(dl.SrcCols), dl.SpTable, jsonList(dl.SpCols), jsonList(dl.Values), dl.Error})
+		df.csv.Flush()
+		if err := df.csv.Error(); err != nil {
+			return err
+		}
+		return df.buf.Flush()
+	}
+	b, err := json.Marshal(dl)
+	if err != nil {
+		return err
+	}
+	if _, err := df.buf.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	return df.buf.Flush()
+}
+
+// Close flushes and closes all dead-letter files.
+func (dw *DeadLetterWriter) Close() error {
+	dw.lock.Lock()
+	defer dw.lock.Unlock()
+	var firstErr error
+	for _, df := range dw.files {
+		err := df.buf.Flush()
+		if cerr := df.f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	dw.files = make(map[string]*deadLetterFile)
+	return firstErr
+}
+
+// Files returns the paths of the dead-letter files written so far.
+func (dw *DeadLetterWriter) Files() []string {
+	dw.lock.Lock()
+	defer dw.lock.Unlock()
+	var l []string
+	for _, df := range dw.files {
+		l = append(l, df.f.Name())
+	}
+	sort.Strings(l)
+	return l
+}
+
+func (dw *DeadLetterWriter) file(table string) (*deadLetterFile, error) {
+	if df, ok := dw.files[table]; ok {
+		return df, nil
+	}
+	f, err := os.Create(filepath.Join(dw.Dir, deadLetterFileName(table)+"."+dw.Format))
+	if err != nil {
+		return nil, fmt.Errorf("can't create dead-letter file for table %s: %v", table, err)
+	}
+	df := &deadLetterFile{f: f, buf: bufio.NewWriter(f)}
+	if dw.Format == DeadLetterFormatCSV {
+		df.csv = csv.NewWriter(df.buf)
+		df.csv.Write(deadLetterCSVHeader)
+	}
+	dw.files[table] = df
+	return df, nil
+}
+
+// deadLetterFileName escapes table for use as a file name.
+func deadLetterFileName(table string) string {
+	var sb strings.Builder
+	for i := 0; i < len(table); i++ {
+		c := table[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '_' || c == '-' || c == '.' {
+			sb.WriteByte(c)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
+
+// ReadDeadLetters reads the rows saved in dead-letter file path, whose
+// format is given by its extension.
+func ReadDeadLetters(path string) ([]DeadLetter, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	switch strings.TrimPrefix(filepath.Ext(path), ".") {
+	case DeadLetterFormatJSONL:
+		return readDeadLettersJSONL(f)
+	case DeadLetterFormatCSV:
+		return readDeadLettersCSV(f)
+	default:
+		return nil, fmt.Errorf("unsupported dead-letter file %s: expected a .%s or .%s file", path, DeadLetterFormatJSONL, DeadLetterFormatCSV)
+	}
+}
+
+func readDeadLettersJSONL(r io.Reader) ([]DeadLetter, error) {
+	var l []DeadLetter
+	dec := json.NewDecoder(r)
+	for {
+		var dl DeadLetter
+		if err := dec.Decode(&dl); err == io.EOF {
+			return l, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("can't parse dead-letter row %d: %v", len(l)+1, err)
+		}
+		l = append(l, dl)
+	}
+}
+
+func readDeadLettersCSV(r io.Reader) ([]DeadLetter, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 || !reflect.DeepEqual(records[0], deadLetterCSVHeader) {
+		return nil, fmt.Errorf("missing dead-letter header %v", deadLetterCSVHeader)
+	}
+	var l []DeadLetter
+	for i, rec := range records[1:] {
+		dl := DeadLetter{Stage: rec[0], SrcTable: rec[1], SpTable: rec[3], Error: rec[6]}
+		for _, x := range []struct {
+			s string
+			l *[]string
+		}{{rec[2], &dl.SrcCols}, {rec[4], &dl.SpCols}, {rec[5], &dl.Values}} {
+			if err := json.Unmarshal([]byte(x.s), x.l); err != nil {
+				return nil, fmt.Errorf("can't parse dead-letter row %d: %v", i+1, err)
+			}
+		}
+		l = append(l, dl)
+	}
+	return l, nil
+}
+
+// DeadLetterValue returns the text form of a value sent to Spanner, as
+// accepted for CSV data of the column's type, except that raw bytes are
+// base64-encoded. It returns false for NULL.
+func DeadLetterValue(v interface{}) (string, bool) {
+	switch x := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		return x, true
+	case []byte:
+		if x == nil {
+			return "", false
+		}
+		return base64.StdEncoding.EncodeToString(x), true
+	case time.Time:
+		return x.UTC().Format("2006-01-02 15:04:05.999999999"), true
+	case civil.Date:
+		return x.String(), true
+	case big.Rat:
+		return spanner.NumericString(&x), true
+	case *big.Rat:
+		if x == nil {
+			return "", false
+		}
+		return spanner.NumericString(x), true
+	case spanner.NullString:
+		return nullable(x.Valid, x.StringVal)
+	case spanner.NullInt64:
+		return nullable(x.Valid, x.Int64)
+	case spanner.NullFloat64:
+		return nullable(x.Valid, x.Float64)
+	case spanner.NullFloat32:
+		return nullable(x.Valid, x.Float32)
+	case spanner.NullBool:
+		return nullable(x.Valid, x.Bool)
+	case spanner.NullTime:
+		return nullable(x.Valid, x.Time)
+	case spanner.NullDate:
+		return nullable(x.Valid, x.Date)
+	case spanner.NullNumeric:
+		return nullable(x.Valid, x.Numeric)
+	case spanner.PGNumeric:
+		return nullable(x.Valid, x.Numeric)
+	case spanner.NullJSON:
+		return nullable(x.Valid, x.String())
+	case float64:
+		return strconv.FormatFloat(x, 'g', -1, 64), true
+	case float32:
+		return strconv.FormatFloat(float64(x), 'g', -1, 32), true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice {
+		if rv.IsNil() {
+			return "", false
+		}
+		var elems []string
+		for i := 0; i < rv.Len(); i++ {
+			s, ok := DeadLetterValue(rv.Index(i).Interface())
+			switch {
+			case !ok:
+				s = "NULL"
+			case s == "NULL" || strings.ContainsAny(s, ",\"[]"):
+				s = strconv.Quote(s)
+			}
+			elems = append(elems, s)
+		}
+		return "[" + strings.Join(elems, ",") + "]", true
+	}
+	return fmt.Sprint(v), true
+}
+
+func nullable(valid bool, v interface{}) (string, bool) {
+	if !valid {
+		return "", false
+	}
+	return DeadLetterValue(v)
+}
+
+func jsonList(l []string) string {
+	if l == nil {
+		l = []string{}
+	}
+	b, _ := json.Marshal(l)
+	return string(b)
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+func TestDeadLetterWriter(t *testing.T) {
+	conv := MakeConv()
+	conv.SrcSchema["t1"] = schema.Table{
+		Id:   "t1",
+		Name: "src_t",
+		ColDefs: map[string]schema.Column{
+			"c1": {Id: "c1", Name: "id"},
+			"c2": {Id: "c2", Name: "name"},
+			"c3": {Id: "c3", Name: "dropped"},
+		},
+	}
+	conv.SpSchema["t1"] = ddl.CreateTable{
+		Id:   "t1",
+		Name: "sp_t",
+		ColDefs: map[string]ddl.ColumnDef{
+			"c1": {Id: "c1", Name: "Id"},
+			"c2": {Id: "c2", Name: "Name"},
+		},
+	}
+	for _, format := range []string{DeadLetterFormatJSONL, DeadLetterFormatCSV} {
+		dir := t.TempDir()
+		dw, err := NewDeadLetterWriter(dir, format)
+		assert.Nil(t, err)
+		conv.DeadLetters = dw
+		conv.CollectBadRow("src_t", []string{"id", "name", "dropped"}, []string{"1", "a,\"b\"", "x"}, fmt.Errorf("can't convert"))
+		assert.Nil(t, dw.WriteFailed(conv, "sp_t", []string{"Id", "Name"}, []interface{}{int64(2), spanner.NullString{}}, fmt.Errorf("already exists")))
+		assert.Nil(t, dw.Write(DeadLetter{Stage: DeadLetterStageConvert, SrcTable: "other", Error: "no table"}))
+		// Tables whose names only differ by escaped characters have files
+		// of their own.
+		assert.Nil(t, dw.Write(DeadLetter{Stage: DeadLetterStageConvert, SrcTable: "a/b", Error: "no table"}))
+		assert.Nil(t, dw.Write(DeadLetter{Stage: DeadLetterStageConvert, SrcTable: "a_b", Error: "no table"}))
+		path := filepath.Join(dir, "sp_t."+format)
+		assert.Equal(t, []string{filepath.Join(dir, "a%2Fb."+format), filepath.Join(dir, "a_b."+format), filepath.Join(dir, "other."+format), path}, dw.Files())
+		// Rows are in the files before they are closed.
+		rows, err := ReadDeadLetters(path)
+		assert.Nil(t, err, format)
+		assert.Equal(t, 2, len(rows), format)
+		assert.Nil(t, dw.Close())
+
+		rows, err = ReadDeadLetters(path)
+		assert.Nil(t, err, format)
+		assert.Equal(t, []DeadLetter{
+			{
+				Stage:    DeadLetterStageConvert,
+				SrcTable: "src_t",
+				SrcCols:  []string{"id", "name", "dropped"},
+				SpTable:  "sp_t",
+				SpCols:   []string{"Id", "Name", ""},
+				Values:   []string{"1", "a,\"b\"", "x"},
+				Error:    "can't convert",
+			},
+			{
+				Stage:    DeadLetterStageWrite,
+				SrcTable: "src_t",
+				SrcCols:  []string{"id"},
+				SpTable:  "sp_t",
+				SpCols:   []string{"Id"},
+				Values:   []string{"2"},
+				Error:    "already exists",
+			},
+		}, rows, format)
+	}
+	assert.Equal(t, 2, len(conv.SampleBadRows(10)))
+	_, err := NewDeadLetterWriter(t.TempDir(), "xml")
+	assert.NotNil(t, err)
+}
+
+func TestDeadLetterValue(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 600000000, time.UTC)
+	testCases := []struct {
+		v        interface{}
+		expected string
+		ok       bool
+	}{
+		{nil, "", false},
+		{"abc", "abc", true},
+		{int64(-7), "-7", true},
+		{float64(1.5), "1.5", true},
+		{true, "true", true},
+		{[]byte("xyz"), "eHl6", true},
+		{[][]byte{[]byte("a"), nil}, "[YQ==,NULL]", true},
+		{ts, "2024-01-02 03:04:05.6", true},
+		{civil.Date{Year: 2024, Month: 1, Day: 2}, "2024-01-02", true},
+		{*big.NewRat(5, 4), "1.250000000", true},
+		{spanner.NullInt64{Int64: 3, Valid: true}, "3", true},
+		{spanner.NullInt64{}, "", false},
+		{spanner.PGNumeric{Numeric: "1.23", Valid: true}, "1.23", true},
+		{[]int64{1, 2}, "[1,2]", true},
+		{[]spanner.NullString{{StringVal: "a,b", Valid: true}, {}}, `["a,b",NULL]`, true},
+		{[]int64(nil), "", false},
+	}
+	for _, tc := range testCases {
+		s, ok := DeadLetterValue(tc.v)
+		assert.Equal(t, tc.ok, ok, "%#v", tc.v)
+		assert.Equal(t, tc.expected, s, "%#v", tc.v)
+	}
+}
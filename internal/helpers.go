@@ -163,6 +163,12 @@ func ComputeUsedNames(conv *Conv) map[string]bool {
 		for _, index := range table.Indexes {
 			usedNames[strings.ToLower(index.Name)] = true
 		}
+		for _, index := range table.SearchIndexes {
+			usedNames[strings.ToLower(index.Name)] = true
+		}
+		for _, index := range table.VectorIndexes {
+			usedNames[strings.ToLower(index.Name)] = true
+		}
 		for _, fk := range table.ForeignKeys {
 			usedNames[strings.ToLower(fk.Name)] = true
 		}
@@ -182,14 +188,20 @@ func GetSrcTableByName(srcSchema map[string]schema.Table, name string) (*schema.
 func ResolveForeignKeyIds(schema map[string]schema.Table) {
 	for key, tbl := range schema {
 		for idx, fk := range tbl.ForeignKeys {
-			colIds := []string{}
-			for _, cn := range fk.ColumnNames {
-				colIds = append(colIds, tbl.ColNameIdMap[cn])
+			// Keys resolved by an earlier call on the same schema are left as they are.
+			if len(fk.ColumnNames) > 0 {
+				colIds := []string{}
+				for _, cn := range fk.ColumnNames {
+					colIds = append(colIds, tbl.ColNameIdMap[cn])
+				}
+				schema[key].ForeignKeys[idx].ColIds = colIds
+				// ColumnNames is used only to fetch the Ids. The collection is not maintained in the application
+				schema[key].ForeignKeys[idx].ColumnNames = nil
 			}
-			schema[key].ForeignKeys[idx].ColIds = colIds
-			// ColumnNames is used only to fetch the Ids. The collection is not maintained in the application
-			schema[key].ForeignKeys[idx].ColumnNames = nil
 
+			if fk.ReferTableName == "" {
+				continue
+			}
 			if refTbl, ok := GetSrcTableByName(schema, fk.ReferTableName); ok {
 				refColIds := []string{}
 				for _, cn := range fk.ReferColumnNames {
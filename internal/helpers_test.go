@@ -19,6 +19,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
 )
 
 func TestGenerateIdSuffix(t *testing.T) {
@@ -48,4 +50,20 @@ func TestGenerateIdSuffix(t *testing.T) {
 		// Assert that the counter is actually incremented to n.
 		assert.Equal(t, tc.expected, counter.ObjectId)
 	}
-}
\ No newline at end of file
+}
+func TestResolveForeignKeyIdsTwice(t *testing.T) {
+	srcSchema := map[string]schema.Table{
+		"t1": {Id: "t1", Name: "parent", ColNameIdMap: map[string]string{"id": "c1"}},
+		"t2": {
+			Id:           "t2",
+			Name:         "child",
+			ColNameIdMap: map[string]string{"parent_id": "c2"},
+			ForeignKeys:  []schema.ForeignKey{{Name: "fk", ColumnNames: []string{"parent_id"}, ReferTableName: "parent", ReferColumnNames: []string{"id"}}},
+		},
+	}
+	expected := schema.ForeignKey{Name: "fk", ColIds: []string{"c2"}, ReferTableId: "t1", ReferColumnIds: []string{"c1"}}
+	ResolveForeignKeyIds(srcSchema)
+	assert.Equal(t, expected, srcSchema["t2"].ForeignKeys[0])
+	ResolveForeignKeyIds(srcSchema)
+	assert.Equal(t, expected, srcSchema["t2"].ForeignKeys[0])
+}
@@ -30,6 +30,7 @@ type Reader struct {
 	EOF        bool
 	r          *bufio.Reader
 	progress   *Progress
+	compressed *countingReader // The compressed input, if any.
 }
 
 // NewReader builds and returns an instance of Reader.
@@ -37,6 +38,21 @@ func NewReader(r *bufio.Reader, progress *Progress) *Reader {
 	return &Reader{LineNumber: 1, Offset: 1, EOF: false, r: r, progress: progress}
 }
 
+// NewCompressedReader builds a Reader of the contents of in, which are
+// decompressed as they are read if in is gzip, zstd or bzip2 compressed.
+// Progress is reported against the bytes read from in, so that it matches
+// the size of the compressed file.
+func NewCompressedReader(in io.Reader, name string, progress *Progress) (*Reader, error) {
+	c := &countingReader{r: in}
+	d, err := Decompress(c, name)
+	if err != nil {
+		return nil, err
+	}
+	r := NewReader(bufio.NewReader(d), progress)
+	r.compressed = c
+	return r, nil
+}
+
 // ReadLine returns a line of input.
 func (r *Reader) ReadLine() []byte {
 	if r.EOF {
@@ -55,7 +71,39 @@ func (r *Reader) ReadLine() []byte {
 		r.LineNumber++
 	}
 	if r.progress != nil {
-		r.progress.MaybeReport(int64(r.Offset - 1))
+		r.progress.MaybeReport(r.bytesRead())
 	}
 	return b
 }
+
+// Peek returns the next n bytes of input without consuming them.
+func (r *Reader) Peek(n int) ([]byte, error) {
+	return r.r.Peek(n)
+}
+
+// Read implements io.Reader, for inputs that aren't line oriented such as
+// pg_dump archives. It keeps the offset and progress of r up to date, but
+// not its line number.
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.EOF {
+		return 0, io.EOF
+	}
+	n, err := r.r.Read(p)
+	if err == io.EOF {
+		r.EOF = true
+	}
+	r.Offset += n
+	if r.progress != nil {
+		r.progress.MaybeReport(r.bytesRead())
+	}
+	return n, err
+}
+
+// bytesRead returns the number of bytes of input read, which are compressed
+// for a compressed input.
+func (r *Reader) bytesRead() int64 {
+	if r.compressed != nil {
+		return r.compressed.n
+	}
+	return int64(r.Offset - 1)
+}
@@ -16,6 +16,7 @@ package internal
 
 import (
 	"bufio"
+	"io"
 	"strings"
 	"testing"
 
@@ -59,3 +60,19 @@ func TestReadLine(t *testing.T) {
 		}
 	}
 }
+
+func TestReaderRead(t *testing.T) {
+	r := NewReader(bufio.NewReader(strings.NewReader("PGDMP\x01\x0e")), nil)
+	b, err := r.Peek(5)
+	assert.NoError(t, err)
+	assert.Equal(t, "PGDMP", string(b))
+	assert.Equal(t, 1, r.Offset)
+	p := make([]byte, 8)
+	n, err := r.Read(p)
+	assert.NoError(t, err)
+	assert.Equal(t, "PGDMP\x01\x0e", string(p[:n]))
+	assert.Equal(t, 8, r.Offset)
+	_, err = r.Read(p)
+	assert.Equal(t, io.EOF, err)
+	assert.True(t, r.EOF)
+}
@@ -503,6 +503,30 @@ func buildTableReportBody(conv *internal.Conv, tableId string, issues map[string
 					}
 					l = append(l, toAppend)
 
+				case internal.FullTextSearchIndex:
+					toAppend := Issue{
+						Category:    IssueDB[i].Category,
+						Description: fmt.Sprintf("Full-text index on column '%s' of table '%s' is converted to a Spanner search index on a TOKENLIST column. %s", spColName, conv.SpSchema[tableId].Name, IssueDB[i].Brief),
+					}
+					l = append(l, toAppend)
+				case internal.VectorIndex:
+					toAppend := Issue{
+						Category:    IssueDB[i].Category,
+						Description: fmt.Sprintf("Vector index on column '%s' of table '%s' is converted to a Spanner vector index. %s", spColName, conv.SpSchema[tableId].Name, IssueDB[i].Brief),
+					}
+					l = append(l, toAppend)
+				case internal.VectorIndexNotSupported:
+					toAppend := Issue{
+						Category:    IssueDB[i].Category,
+						Description: fmt.Sprintf("Vector index on column '%s' of table '%s' is not converted. %s", spColName, conv.SpSchema[tableId].Name, IssueDB[i].Brief),
+					}
+					l = append(l, toAppend)
+				case internal.PartialIndex:
+					toAppend := Issue{
+						Category:    IssueDB[i].Category,
+						Description: fmt.Sprintf("Partial index on column '%s' of table '%s' is converted to an index on all rows. %s", spColName, conv.SpSchema[tableId].Name, IssueDB[i].Brief),
+					}
+					l = append(l, toAppend)
 				case internal.DefaultValueError:
 					toAppend := Issue{
 						Category:    IssueDB[i].Category,
@@ -592,13 +616,15 @@ func fillRowStats(conv *internal.Conv, srcTable string, badWrites map[string]int
 	rows := conv.Stats.Rows[srcTable]
 	goodConvRows := conv.Stats.GoodRows[srcTable]
 	badConvRows := conv.Stats.BadRows[srcTable]
+	sampledOutRows := conv.Stats.SampledOut[srcTable]
 	badRowWrites := badWrites[srcTable]
 	// Note on rows:
 	// rows: all rows we encountered during processing.
 	// goodConvRows: rows we successfully converted.
 	// badConvRows: rows we failed to convert.
+	// sampledOutRows: rows we converted, but left out of the sample.
 	// badRowWrites: rows we converted, but could not write to Spanner.
-	if rows != goodConvRows+badConvRows || badRowWrites > goodConvRows {
+	if rows != goodConvRows+badConvRows+sampledOutRows || badRowWrites > goodConvRows {
 		conv.Unexpected(fmt.Sprintf("Inconsistent row counts for table %s: %d %d %d %d\n", srcTable, rows, goodConvRows, badConvRows, badRowWrites))
 	}
 	tr.rows = rows
@@ -686,6 +712,12 @@ var IssueDB = map[internal.SchemaIssue]struct {
 	internal.CassandraTIMEUUID:            {Brief: "Cassandra TimeUUIDs map to Spanner's BYTES(16). This generic type doesn't validate embedded timestamps.", Severity: warning, Category: "CASSANDRA_TIMEUUID_USES"},
 	internal.CassandraMAP:                 {Brief: "Cassandra MAP type maps to Spanner's JSON. Spanner does not validate internal JSON structure or types, unlike Cassandra's MAP.", Severity: warning, Category: "CASSANDRA_MAP_USES"},
 	internal.PossibleOverflow:             {Brief: "Possible overflow in Spanner. Source type does not entirely fit inside Spanner's type. Please check if the data fits within the target type's limits.", Severity: warning, Category: "POSSIBLE_OVERFLOW"},
+	internal.ViewUnsupportedSql:           {Brief: "View uses SQL that Spanner migration tool can't translate to Spanner. Please create the view manually", Severity: warning, Category: "VIEW_UNSUPPORTED_SQL"},
+	internal.ViewMissingTable:             {Brief: "View reads from a table, column or view that is not migrated to Spanner. Please create the view manually", Severity: warning, Category: "VIEW_MISSING_TABLE"},
+	internal.FullTextSearchIndex:          {Brief: "Full-text queries such as MATCH() AGAINST() or @@ to_tsquery() must be rewritten to use the Spanner SEARCH() function, and Spanner tokenizes text differently from the source database", Severity: warning, Category: "FULLTEXT_SEARCH_INDEX"},
+	internal.VectorIndex:                  {Brief: "Queries using pgvector distance operators such as <-> or <=> must be rewritten to use the Spanner APPROX_*_DISTANCE functions, and the index build options are not preserved", Severity: warning, Category: "VECTOR_INDEX"},
+	internal.VectorIndexNotSupported:      {Brief: "Spanner vector indexes require a vector column with a fixed dimension and a cosine, euclidean or dot product distance. Please create the index manually if possible", Severity: warning, Category: "VECTOR_INDEX_NOT_SUPPORTED"},
+	internal.PartialIndex:                 {Brief: "Spanner indexes can only filter out rows with NULL key columns, so the predicate of the index is dropped and a unique index no longer enforces uniqueness", Severity: warning, Category: "PARTIAL_INDEX"},
 }
 
 type Severity int
@@ -0,0 +1,381 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+const (
+	// maxSampleKeys is the maximum number of keys of a table recorded for
+	// the tables that reference it. Once it is reached, no more rows of
+	// the table are kept, so that the memory used by a Sampler is bounded.
+	maxSampleKeys = 1 << 20
+	// maxPushedKeys is the maximum number of keys of a referenced table
+	// that Pushdown lists for sources to filter rows with.
+	maxPushedKeys = 1000
+)
+
+// SampleOptions controls which rows a Sampler keeps.
+type SampleOptions struct {
+	Percent float64 // Percentage of the rows of root tables to keep; 0 keeps all of them.
+	MaxRows int64   // Maximum number of rows to keep per table; 0 means no limit.
+}
+
+// Sampler selects a referentially consistent sample of the data being
+// migrated, so that a proof of concept can run on a fraction of a
+// database.
+//
+// Root tables, which are neither interleaved nor have foreign keys to
+// other tables, are sampled by a hash of each row against Percent, so the
+// same rows are picked on every run. Rows of the other tables are kept
+// only if the rows they reference through their interleave parent and
+// foreign keys have been kept: references whose columns are NULL are
+// always satisfied. A table's kept rows are capped at MaxRows, and, if
+// other tables reference it, at the number of its keys that can be
+// recorded.
+//
+// Sources can avoid reading rows that won't be kept by restricting their
+// reads with Pushdown; Keep still decides which rows are kept.
+//
+// The sample is only representative if referenced tables are migrated
+// before the tables that reference them (see SortTableIds): a row read
+// before the rows it references is left out.
+//
+// Sampler is safe for concurrent use.
+type Sampler struct {
+	Options SampleOptions
+
+	tables  map[string]*sampleTable // Keyed by Spanner table name.
+	names   map[string]string       // Maps table id to Spanner table name.
+	maxKeys int
+	lock    sync.Mutex
+}
+
+// sampleTable is the sampling state of a Spanner table.
+type sampleTable struct {
+	id   string
+	root bool
+	refs []sampleRef
+	// keys maps each set of columns referenced by other tables to the
+	// keys (see sampleKey) of those columns in the rows kept, and for a
+	// single column to its value.
+	keys map[string]map[string]interface{}
+	kept int64
+}
+
+// sampleRef is a reference from a table to the rows of another table.
+type sampleRef struct {
+	table    string   // Spanner name of the table referenced.
+	cols     []string // Columns of the referencing table.
+	colIds   []string // Ids of cols.
+	referCol string   // Key of the referenced columns in the keys of table.
+}
+
+// SampleKeyFilter restricts the rows of a table to those whose column
+// ColId is NULL or has one of Values, as converted to Spanner.
+type SampleKeyFilter struct {
+	ColId  string
+	Values []interface{}
+}
+
+// NewSampler returns a Sampler for the tables of spSchema.
+func NewSampler(spSchema ddl.Schema, opts SampleOptions) *Sampler {
+	s := &Sampler{Options: opts, tables: make(map[string]*sampleTable), names: make(map[string]string), maxKeys: maxSampleKeys}
+	for id, t := range spSchema {
+		s.tables[t.Name] = &sampleTable{id: id, keys: make(map[string]map[string]interface{})}
+		s.names[id] = t.Name
+	}
+	colNames := func(t ddl.CreateTable, colIds []string) ([]string, bool) {
+		var names []string
+		for _, id := range colIds {
+			cd, ok := t.ColDefs[id]
+			if !ok {
+				return nil, false
+			}
+			names = append(names, cd.Name)
+		}
+		return names, len(names) > 0
+	}
+	addRef := func(t *sampleTable, refer ddl.CreateTable, cols, colIds, referCols []string) {
+		key := strings.Join(referCols, "\x00")
+		t.refs = append(t.refs, sampleRef{table: refer.Name, cols: cols, colIds: colIds, referCol: key})
+		if _, ok := s.tables[refer.Name].keys[key]; !ok {
+			s.tables[refer.Name].keys[key] = make(map[string]interface{})
+		}
+	}
+	for _, t := range spSchema {
+		st := s.tables[t.Name]
+		if parent, ok := spSchema[t.ParentTable.Id]; ok && t.ParentTable.Id != "" {
+			// The primary key of an interleaved table starts with the
+			// primary key columns of its parent, under the same names.
+			var pkIds []string
+			for _, k := range parent.PrimaryKeys {
+				pkIds = append(pkIds, k.ColId)
+			}
+			if names, ok := colNames(parent, pkIds); ok {
+				colIds := make([]string, len(names))
+				for i, name := range names {
+					for id, cd := range t.ColDefs {
+						if cd.Name == name {
+							colIds[i] = id
+						}
+					}
+				}
+				addRef(st, parent, names, colIds, names)
+			}
+		}
+		for _, fk := range t.ForeignKeys {
+			refer, ok := spSchema[fk.ReferTableId]
+			if !ok || fk.ReferTableId == t.Id {
+				continue
+			}
+			cols, ok1 := colNames(t, fk.ColIds)
+			referCols, ok2 := colNames(refer, fk.ReferColumnIds)
+			if ok1 && ok2 && len(cols) == len(referCols) {
+				addRef(st, refer, cols, fk.ColIds, referCols)
+			}
+		}
+		st.root = len(st.refs) == 0
+	}
+	return s
+}
+
+// Keep reports whether a row of Spanner table spTable belongs to the
+// sample, and if so records the values other tables may reference. Rows
+// of tables that aren't in the schema are kept.
+func (s *Sampler) Keep(spTable string, cols []string, vals []interface{}) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	t, ok := s.tables[spTable]
+	if !ok {
+		return true
+	}
+	if s.Options.MaxRows > 0 && t.kept >= s.Options.MaxRows {
+		return false
+	}
+	row := make(map[string]interface{}, len(cols))
+	for i, c := range cols {
+		if i < len(vals) {
+			row[c] = vals[i]
+		}
+	}
+	if t.root {
+		if !s.pick(spTable, vals) {
+			return false
+		}
+	} else {
+		for _, r := range t.refs {
+			key, ok := sampleKey(row, r.cols)
+			if !ok {
+				continue
+			}
+			if _, found := s.tables[r.table].keys[r.referCol][key]; !found {
+				return false
+			}
+		}
+	}
+	// The keys of the row must be recorded for the rows that reference it
+	// to be kept.
+	newKeys := make(map[string]string)
+	for referCol, keys := range t.keys {
+		key, ok := sampleKey(row, strings.Split(referCol, "\x00"))
+		if !ok {
+			continue
+		}
+		if _, found := keys[key]; !found {
+			if len(keys) >= s.maxKeys {
+				return false
+			}
+			newKeys[referCol] = key
+		}
+	}
+	for referCol, key := range newKeys {
+		var v interface{}
+		if !strings.Contains(referCol, "\x00") {
+			v = row[referCol]
+		}
+		t.keys[referCol][key] = v
+	}
+	t.kept++
+	return true
+}
+
+// Pushdown returns filters that the rows of table tableId kept by Keep
+// satisfy, so that sources can leave out the others when reading the
+// table, for the references of the table to a single column whose kept
+// keys are few enough to list. limit is the maximum number of rows that
+// can be kept if all the rows satisfying the filters are, and 0
+// otherwise. The filters only hold once the tables the table depends on
+// (see DependsOn) have been migrated.
+func (s *Sampler) Pushdown(tableId string) (filters []SampleKeyFilter, limit int64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	t, ok := s.tables[s.names[tableId]]
+	if !ok {
+		return nil, 0
+	}
+	exact := !t.root || s.Options.Percent <= 0 || s.Options.Percent >= 100
+	for _, r := range t.refs {
+		keys := s.tables[r.table].keys[r.referCol]
+		if len(r.colIds) != 1 || r.colIds[0] == "" || len(keys) > maxPushedKeys {
+			exact = false
+			continue
+		}
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+		f := SampleKeyFilter{ColId: r.colIds[0], Values: make([]interface{}, 0, len(keys))}
+		for _, key := range sorted {
+			f.Values = append(f.Values, keys[key])
+		}
+		filters = append(filters, f)
+	}
+	if exact {
+		limit = s.Options.MaxRows
+	}
+	return filters, limit
+}
+
+// pick reports whether a row of a root table falls in the sampled
+// percentage.
+func (s *Sampler) pick(spTable string, vals []interface{}) bool {
+	if s.Options.Percent <= 0 || s.Options.Percent >= 100 {
+		return true
+	}
+	h := fnv.New64a()
+	h.Write([]byte(spTable))
+	for _, v := range vals {
+		fmt.Fprintf(h, "\x00%v", v)
+	}
+	return float64(h.Sum64()%10000) < s.Options.Percent*100
+}
+
+// sampleKey returns the values of cols in row encoded as a string, such
+// that the keys of two rows are equal if their values are. ok is false if
+// any of them is NULL or missing, since such a reference needs no row.
+func sampleKey(row map[string]interface{}, cols []string) (key string, ok bool) {
+	var b []byte
+	for _, c := range cols {
+		v, found := row[c]
+		if !found || v == nil {
+			return "", false
+		}
+		b = appendSampleValue(b, v)
+	}
+	return string(b), true
+}
+
+// appendSampleValue appends v to b as its type and a canonical string
+// form, prefixed with its length so that keys of several values can't
+// collide.
+func appendSampleValue(b []byte, v interface{}) []byte {
+	var typ byte
+	var s string
+	switch x := v.(type) {
+	case int64:
+		typ, s = 'i', strconv.FormatInt(x, 10)
+	case float64:
+		typ, s = 'f', strconv.FormatFloat(x, 'g', -1, 64)
+	case float32:
+		typ, s = 'f', strconv.FormatFloat(float64(x), 'g', -1, 32)
+	case bool:
+		typ, s = 'b', strconv.FormatBool(x)
+	case string:
+		typ, s = 's', x
+	case []byte:
+		typ, s = 'y', string(x)
+	case time.Time:
+		typ, s = 't', x.UTC().Format(time.RFC3339Nano)
+	case civil.Date:
+		typ, s = 'd', x.String()
+	case *big.Rat:
+		typ, s = 'n', x.RatString()
+	case spanner.PGNumeric:
+		// The same number can be written in several ways, e.g. 1.50 and 1.5.
+		typ, s = 'n', x.Numeric
+		if r, ok := new(big.Rat).SetString(x.Numeric); ok {
+			s = r.RatString()
+		}
+	default:
+		typ, s = 'v', fmt.Sprintf("%T:%v", v, v)
+	}
+	b = append(b, typ)
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// DependsOn returns the ids of the tables whose rows must be migrated
+// before those of table tableId for its rows to be kept.
+func (s *Sampler) DependsOn(tableId string) []string {
+	t, ok := s.tables[s.names[tableId]]
+	if !ok {
+		return nil
+	}
+	var ids []string
+	for _, r := range t.refs {
+		ids = append(ids, s.tables[r.table].id)
+	}
+	return ids
+}
+
+// SortTableIds reorders tableIds so that tables come after the tables
+// they depend on, keeping the given order otherwise. Tables in a cycle
+// of foreign keys are left in the given order.
+func (s *Sampler) SortTableIds(tableIds []string) []string {
+	pending := make(map[string]bool)
+	for _, id := range tableIds {
+		pending[id] = true
+	}
+	ready := func(id string) bool {
+		for _, dep := range s.DependsOn(id) {
+			if pending[dep] {
+				return false
+			}
+		}
+		return true
+	}
+	var sorted []string
+	remaining := append([]string(nil), tableIds...)
+	for len(remaining) > 0 {
+		next := 0
+		for i, id := range remaining {
+			if ready(id) {
+				next = i
+				break
+			}
+		}
+		id := remaining[next]
+		sorted = append(sorted, id)
+		delete(pending, id)
+		remaining = append(remaining[:next], remaining[next+1:]...)
+	}
+	return sorted
+}
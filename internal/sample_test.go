@@ -0,0 +1,217 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// sampleSchema has customers, orders interleaved in customers, and
+// items with foreign keys to orders and to products.
+func sampleSchema() ddl.Schema {
+	col := func(id, name string) ddl.ColumnDef {
+		return ddl.ColumnDef{Id: id, Name: name, T: ddl.Type{Name: ddl.Int64}}
+	}
+	return ddl.Schema{
+		"t1": {
+			Id:          "t1",
+			Name:        "customers",
+			ColIds:      []string{"c1"},
+			ColDefs:     map[string]ddl.ColumnDef{"c1": col("c1", "customer_id")},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c1"}},
+		},
+		"t2": {
+			Id:          "t2",
+			Name:        "orders",
+			ColIds:      []string{"c2", "c3"},
+			ColDefs:     map[string]ddl.ColumnDef{"c2": col("c2", "customer_id"), "c3": col("c3", "order_id")},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c2"}, {ColId: "c3"}},
+			ParentTable: ddl.InterleavedParent{Id: "t1"},
+		},
+		"t3": {
+			Id:     "t3",
+			Name:   "items",
+			ColIds: []string{"c4", "c5", "c6", "c7"},
+			ColDefs: map[string]ddl.ColumnDef{
+				"c4": col("c4", "item_id"), "c5": col("c5", "cust"), "c6": col("c6", "ord"), "c7": col("c7", "product"),
+			},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c4"}},
+			ForeignKeys: []ddl.Foreignkey{
+				{Id: "f1", ColIds: []string{"c5", "c6"}, ReferTableId: "t2", ReferColumnIds: []string{"c2", "c3"}},
+				{Id: "f2", ColIds: []string{"c7"}, ReferTableId: "t4", ReferColumnIds: []string{"c8"}},
+				{Id: "f3", ColIds: []string{"c4"}, ReferTableId: "t3", ReferColumnIds: []string{"c4"}},
+			},
+		},
+		"t4": {
+			Id:          "t4",
+			Name:        "products",
+			ColIds:      []string{"c8"},
+			ColDefs:     map[string]ddl.ColumnDef{"c8": col("c8", "product_id")},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c8"}},
+		},
+	}
+}
+
+func TestSampler(t *testing.T) {
+	s := NewSampler(sampleSchema(), SampleOptions{Percent: 50})
+	// keepRoot returns a row of a root table that is kept and one that
+	// isn't.
+	keepRoot := func(table, col string) (kept, dropped int64) {
+		kept, dropped = -1, -1
+		for i := int64(0); kept < 0 || dropped < 0; i++ {
+			if s.Keep(table, []string{col}, []interface{}{i}) {
+				kept = i
+			} else {
+				dropped = i
+			}
+		}
+		return kept, dropped
+	}
+	customer, otherCustomer := keepRoot("customers", "customer_id")
+	product, otherProduct := keepRoot("products", "product_id")
+
+	// The rows picked are the same on every run.
+	again := NewSampler(sampleSchema(), SampleOptions{Percent: 50})
+	assert.True(t, again.Keep("customers", []string{"customer_id"}, []interface{}{customer}))
+	assert.False(t, again.Keep("customers", []string{"customer_id"}, []interface{}{otherCustomer}))
+
+	orderCols := []string{"customer_id", "order_id"}
+	assert.True(t, s.Keep("orders", orderCols, []interface{}{customer, int64(1)}))
+	assert.False(t, s.Keep("orders", orderCols, []interface{}{otherCustomer, int64(2)}))
+
+	itemCols := []string{"item_id", "cust", "ord", "product"}
+	testCases := []struct {
+		name     string
+		cols     []string
+		vals     []interface{}
+		expected bool
+	}{
+		{"order and product kept", itemCols, []interface{}{int64(1), customer, int64(1), product}, true},
+		{"order not kept", itemCols, []interface{}{int64(2), otherCustomer, int64(2), product}, false},
+		{"product not kept", itemCols, []interface{}{int64(3), customer, int64(1), otherProduct}, false},
+		{"NULL references", itemCols, []interface{}{int64(4), customer, nil, nil}, true},
+		{"missing references", []string{"item_id"}, []interface{}{int64(5)}, true},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, s.Keep("items", tc.cols, tc.vals), tc.name)
+	}
+	assert.True(t, s.Keep("unknown", []string{"a"}, []interface{}{int64(1)}))
+}
+
+func TestSamplerMaxRows(t *testing.T) {
+	s := NewSampler(sampleSchema(), SampleOptions{MaxRows: 2})
+	for i := int64(0); i < 3; i++ {
+		assert.Equal(t, i < 2, s.Keep("customers", []string{"customer_id"}, []interface{}{i}))
+	}
+	// Rows of the customers left out aren't kept either.
+	orderCols := []string{"customer_id", "order_id"}
+	assert.True(t, s.Keep("orders", orderCols, []interface{}{int64(0), int64(1)}))
+	assert.False(t, s.Keep("orders", orderCols, []interface{}{int64(2), int64(2)}))
+}
+
+func TestSamplerKeys(t *testing.T) {
+	s := NewSampler(sampleSchema(), SampleOptions{})
+	orderCols := []string{"customer_id", "order_id"}
+	itemCols := []string{"item_id", "cust", "ord"}
+	assert.True(t, s.Keep("customers", []string{"customer_id"}, []interface{}{"a\x00b"}))
+	assert.True(t, s.Keep("orders", orderCols, []interface{}{"a\x00b", "c"}))
+	// Keys are compared by type and value, and values can't run into each
+	// other.
+	assert.False(t, s.Keep("items", itemCols, []interface{}{int64(1), "a", "b\x00c"}))
+	assert.True(t, s.Keep("items", itemCols, []interface{}{int64(2), "a\x00b", "c"}))
+	assert.True(t, s.Keep("customers", []string{"customer_id"}, []interface{}{int64(1)}))
+	assert.False(t, s.Keep("orders", orderCols, []interface{}{"1", int64(1)}))
+	// Times are compared as instants.
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.True(t, s.Keep("customers", []string{"customer_id"}, []interface{}{at}))
+	assert.True(t, s.Keep("orders", orderCols, []interface{}{at.In(time.FixedZone("", 3600)), int64(1)}))
+}
+
+func TestSamplerMaxKeys(t *testing.T) {
+	s := NewSampler(sampleSchema(), SampleOptions{})
+	s.maxKeys = 2
+	for i := int64(0); i < 3; i++ {
+		assert.Equal(t, i < 2, s.Keep("customers", []string{"customer_id"}, []interface{}{i}))
+	}
+	// Rows whose keys are already recorded are kept, and tables nothing
+	// references aren't limited.
+	assert.True(t, s.Keep("customers", []string{"customer_id"}, []interface{}{int64(1)}))
+	for i := int64(0); i < 3; i++ {
+		assert.True(t, s.Keep("items", []string{"item_id"}, []interface{}{i}))
+	}
+}
+
+func TestSamplerPushdown(t *testing.T) {
+	s := NewSampler(sampleSchema(), SampleOptions{MaxRows: 10})
+	filters, limit := s.Pushdown("t1")
+	assert.Empty(t, filters)
+	assert.Equal(t, int64(10), limit)
+	filters, limit = s.Pushdown("t2")
+	assert.Equal(t, []SampleKeyFilter{{ColId: "c2", Values: []interface{}{}}}, filters)
+	assert.Equal(t, int64(10), limit)
+
+	s.Keep("customers", []string{"customer_id"}, []interface{}{int64(2)})
+	s.Keep("customers", []string{"customer_id"}, []interface{}{int64(1)})
+	s.Keep("products", []string{"product_id"}, []interface{}{int64(7)})
+	filters, limit = s.Pushdown("t2")
+	assert.Equal(t, []SampleKeyFilter{{ColId: "c2", Values: []interface{}{int64(1), int64(2)}}}, filters)
+	assert.Equal(t, int64(10), limit)
+	// The reference of items to orders is on two columns, so it is only
+	// checked by Keep.
+	filters, limit = s.Pushdown("t3")
+	assert.Equal(t, []SampleKeyFilter{{ColId: "c7", Values: []interface{}{int64(7)}}}, filters)
+	assert.Equal(t, int64(0), limit)
+
+	s = NewSampler(sampleSchema(), SampleOptions{Percent: 10, MaxRows: 10})
+	_, limit = s.Pushdown("t1")
+	assert.Equal(t, int64(0), limit)
+	filters, limit = s.Pushdown("unknown")
+	assert.Nil(t, filters)
+	assert.Equal(t, int64(0), limit)
+}
+
+func TestSamplerPercent(t *testing.T) {
+	s := NewSampler(sampleSchema(), SampleOptions{Percent: 10})
+	kept := 0
+	for i := int64(0); i < 10000; i++ {
+		if s.Keep("products", []string{"product_id"}, []interface{}{i}) {
+			kept++
+		}
+	}
+	assert.InDelta(t, 1000, kept, 150)
+}
+
+func TestSamplerSortTableIds(t *testing.T) {
+	s := NewSampler(sampleSchema(), SampleOptions{Percent: 10})
+	assert.Equal(t, []string{"t1", "t2", "t4", "t3"}, s.SortTableIds([]string{"t1", "t2", "t3", "t4"}))
+	assert.ElementsMatch(t, []string{"t2", "t4"}, s.DependsOn("t3"))
+	assert.Equal(t, []string{"t1"}, s.DependsOn("t2"))
+	assert.Nil(t, s.DependsOn("t1"))
+
+	// Tables in a cycle keep their order.
+	schema := sampleSchema()
+	products := schema["t4"]
+	products.ColIds = append(products.ColIds, "c9")
+	products.ColDefs["c9"] = ddl.ColumnDef{Id: "c9", Name: "first_item", T: ddl.Type{Name: ddl.Int64}}
+	products.ForeignKeys = []ddl.Foreignkey{{Id: "f4", ColIds: []string{"c9"}, ReferTableId: "t3", ReferColumnIds: []string{"c4"}}}
+	schema["t4"] = products
+	s = NewSampler(schema, SampleOptions{Percent: 10})
+	assert.Equal(t, []string{"t1", "t2", "t3", "t4"}, s.SortTableIds([]string{"t1", "t2", "t3", "t4"}))
+}
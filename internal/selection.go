@@ -0,0 +1,101 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+)
+
+// TableSelection restricts a migration from a source database to some of
+// its tables, and the rows read from some tables to those satisfying a
+// predicate. Tables are named as in the source schema of the session,
+// e.g. "schema.table" for PostgreSQL tables outside the public schema.
+//
+// The methods of TableSelection can be called on a nil selection, which
+// selects every row of every table.
+type TableSelection struct {
+	// Include lists glob patterns (as matched by path.Match) of the tables
+	// to migrate. All tables are included if it is empty.
+	Include []string `json:"include,omitempty"`
+	// Exclude lists glob patterns of tables not to migrate, even if they
+	// are included.
+	Exclude []string `json:"exclude,omitempty"`
+	// Where maps a table to the condition, in the SQL of the source
+	// database, that the rows migrated must satisfy.
+	Where map[string]string `json:"where,omitempty"`
+}
+
+// LoadTableSelection reads a TableSelection from a JSON file.
+func LoadTableSelection(file string) (*TableSelection, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("can't read table selection file %s: %v", file, err)
+	}
+	ts := &TableSelection{}
+	if err := json.Unmarshal(data, ts); err != nil {
+		return nil, fmt.Errorf("can't parse table selection file %s: %v", file, err)
+	}
+	return ts, nil
+}
+
+// Validate checks that the patterns of ts are well formed.
+func (ts *TableSelection) Validate() error {
+	if ts == nil {
+		return nil
+	}
+	for _, p := range append(append([]string{}, ts.Include...), ts.Exclude...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid table pattern %q: %v", p, err)
+		}
+	}
+	return nil
+}
+
+// Selected reports whether table srcTable is migrated.
+func (ts *TableSelection) Selected(srcTable string) bool {
+	if ts == nil {
+		return true
+	}
+	if len(ts.Include) > 0 && !matchAny(ts.Include, srcTable) {
+		return false
+	}
+	return !matchAny(ts.Exclude, srcTable)
+}
+
+// Predicate returns the condition that the rows of table srcTable must
+// satisfy, or "" if all its rows are migrated.
+func (ts *TableSelection) Predicate(srcTable string) string {
+	if ts == nil {
+		return ""
+	}
+	return ts.Where[srcTable]
+}
+
+// HasPredicates reports whether ts restricts the rows of any table.
+func (ts *TableSelection) HasPredicates() bool {
+	return ts != nil && len(ts.Where) > 0
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
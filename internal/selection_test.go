@@ -0,0 +1,74 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableSelection(t *testing.T) {
+	var none *TableSelection
+	assert.True(t, none.Selected("orders"))
+	assert.Equal(t, "", none.Predicate("orders"))
+	assert.False(t, none.HasPredicates())
+
+	ts := &TableSelection{
+		Include: []string{"sales.*", "users"},
+		Exclude: []string{"*_archive"},
+		Where:   map[string]string{"sales.orders": "created_at >= '2024-01-01'"},
+	}
+	testCases := []struct {
+		table    string
+		expected bool
+	}{
+		{"sales.orders", true},
+		{"sales.orders_archive", false},
+		{"users", true},
+		{"users_archive", false},
+		{"logs", false},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, ts.Selected(tc.table), tc.table)
+	}
+	assert.Equal(t, "created_at >= '2024-01-01'", ts.Predicate("sales.orders"))
+	assert.Equal(t, "", ts.Predicate("users"))
+	assert.True(t, ts.HasPredicates())
+
+	// Without Include, every table that isn't excluded is selected.
+	ts = &TableSelection{Exclude: []string{"tmp_*"}}
+	assert.True(t, ts.Selected("logs"))
+	assert.False(t, ts.Selected("tmp_1"))
+
+	assert.Nil(t, ts.Validate())
+	assert.NotNil(t, (&TableSelection{Include: []string{"[a"}}).Validate())
+}
+
+func TestLoadTableSelection(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "selection.json")
+	assert.Nil(t, os.WriteFile(file, []byte(`{"include": ["orders*"], "where": {"orders": "id > 10"}}`), 0644))
+	ts, err := LoadTableSelection(file)
+	assert.Nil(t, err)
+	assert.Equal(t, &TableSelection{Include: []string{"orders*"}, Where: map[string]string{"orders": "id > 10"}}, ts)
+
+	assert.Nil(t, os.WriteFile(file, []byte(`{"include": "orders"}`), 0644))
+	_, err = LoadTableSelection(file)
+	assert.NotNil(t, err)
+	_, err = LoadTableSelection(filepath.Join(t.TempDir(), "missing.json"))
+	assert.NotNil(t, err)
+}
@@ -55,6 +55,9 @@ func main() {
 	subcommands.Register(&cmd.AssessmentCmd{}, "")
 	subcommands.Register(&webv2.WebCmd{DistDir: distDir}, "")
 	subcommands.Register(&cmd.ImportDataCmd{}, "")
+	subcommands.Register(&cmd.ReplayCmd{}, "")
+	subcommands.Register(&cmd.CdcCmd{}, "")
+	subcommands.Register(&cmd.ValidateCmd{}, "")
 	flag.Parse()
 	os.Exit(int(subcommands.Execute(ctx)))
 }
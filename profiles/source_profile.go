@@ -51,6 +51,7 @@ type SourceProfileDialectInterface interface {
 	NewSourceProfileConnectionSqlServer(params map[string]string, g utils.GetUtilInfoInterface) (SourceProfileConnectionSqlServer, error)
 	NewSourceProfileConnectionOracle(params map[string]string, g utils.GetUtilInfoInterface) (SourceProfileConnectionOracle, error)
 	NewSourceProfileConnectionCassandra(params map[string]string, g utils.GetUtilInfoInterface) (SourceProfileConnectionCassandra, error)
+	NewSourceProfileConnectionSpanner(params map[string]string, g utils.GetUtilInfoInterface) (SourceProfileConnectionSpanner, error)
 }
 
 type SourceProfileDialectImpl struct{}
@@ -89,6 +90,7 @@ const (
 	SourceProfileConnectionTypeSqlServer
 	SourceProfileConnectionTypeOracle
 	SourceProfileConnectionTypeCassandra
+	SourceProfileConnectionTypeSpanner
 )
 
 type SourceProfileConnectionTypeCloudSQL int
@@ -455,6 +457,33 @@ func (spd *SourceProfileDialectImpl) NewSourceProfileConnectionCassandra(params
 	return cs, nil
 }
 
+// SourceProfileConnectionSpanner identifies a Spanner database that is
+// read as the source of a migration, e.g. to copy data between instances.
+type SourceProfileConnectionSpanner struct {
+	Project  string
+	Instance string
+	Db       string
+}
+
+func (spd *SourceProfileDialectImpl) NewSourceProfileConnectionSpanner(params map[string]string, g utils.GetUtilInfoInterface) (SourceProfileConnectionSpanner, error) {
+	sp := SourceProfileConnectionSpanner{}
+	project, projectOk := params["project"]
+	instance, instanceOk := params["instance"]
+	db, dbOk := params["dbName"]
+	if !instanceOk || !dbOk || instance == "" || db == "" {
+		return sp, fmt.Errorf("please specify instance and dbName in the source-profile")
+	}
+	if !projectOk || project == "" {
+		var err error
+		project, err = g.GetProject()
+		if err != nil {
+			return sp, fmt.Errorf("project for source spanner instance not specified in source-profile, and unable to fetch from gcloud. Please specify project in the source-profile or configure in gcloud")
+		}
+	}
+	sp.Project, sp.Instance, sp.Db = project, instance, db
+	return sp, nil
+}
+
 type SourceProfileConnection struct {
 	Ty        SourceProfileConnectionType
 	Mysql     SourceProfileConnectionMySQL
@@ -462,6 +491,7 @@ type SourceProfileConnection struct {
 	SqlServer SourceProfileConnectionSqlServer
 	Oracle    SourceProfileConnectionOracle
 	Cassandra SourceProfileConnectionCassandra
+	Spanner   SourceProfileConnectionSpanner
 }
 
 type SourceProfileConnectionCloudSQL struct {
@@ -517,6 +547,14 @@ func (nsp *NewSourceProfileImpl) NewSourceProfileConnection(source string, param
 				return conn, err
 			}
 		}
+	case "spanner":
+		{
+			conn.Ty = SourceProfileConnectionTypeSpanner
+			conn.Spanner, err = s.NewSourceProfileConnectionSpanner(params, &utils.GetUtilInfoImpl{})
+			if err != nil {
+				return conn, err
+			}
+		}
 	default:
 		return conn, fmt.Errorf("please specify a valid source database using -source flag, received source = %v", source)
 	}
@@ -562,8 +600,29 @@ type ShardConfigurationBulk struct {
 	DataShards   []DirectConnectionConfig `json:"dataShards"`
 }
 
-// TODO: Define the sharding structure for DMS migrations here.
+// ShardConfigurationDMS configures a migration that replicates the changes
+// of a MySQL database from its binlog, after an optional snapshot.
 type ShardConfigurationDMS struct {
+	// SchemaSource is the database to read the schema and snapshot from, and
+	// to replicate from unless BinlogDir is set.
+	SchemaSource DirectConnectionConfig `json:"schemaSource"`
+	// ServerId identifies the migration as a replica of the source, and must
+	// differ from the server ids of its other replicas.
+	ServerId uint32 `json:"serverId"`
+	// BinlogFile and BinlogPos are the position to start replicating from,
+	// if there is no saved position. They default to the current position
+	// of the source, after taking a snapshot.
+	BinlogFile string `json:"binlogFile"`
+	BinlogPos  uint32 `json:"binlogPos"`
+	// BinlogDir is a directory of binlog files to read instead of
+	// connecting to the source, e.g. for offline testing.
+	BinlogDir string `json:"binlogDir"`
+	// PositionFile is the file the binlog position is saved in, so that
+	// replication can restart from it.
+	PositionFile string `json:"positionFile"`
+	// SkipSnapshot disables the snapshot of the source taken before
+	// replication starts, when there is no saved position.
+	SkipSnapshot bool `json:"skipSnapshot"`
 }
 
 type SourceProfileConfig struct {
@@ -626,6 +685,14 @@ func (src SourceProfile) UseTargetSchema() bool {
 	return (src.Driver == constants.CSV)
 }
 
+func isSQLServerSource(source string) bool {
+	switch strings.ToLower(source) {
+	case "sqlserver", "mssql":
+		return true
+	}
+	return false
+}
+
 // ToLegacyDriver converts source-profile to equivalent legacy global flags
 // e.g., -driver, -dump-file etc since the rest of the codebase still uses the
 // same. TODO: Deprecate this function and pass around SourceProfile across the
@@ -634,13 +701,30 @@ func (src SourceProfile) ToLegacyDriver(source string) (string, error) {
 	switch src.Ty {
 	case SourceProfileTypeFile:
 		{
+			if src.File.Format == constants.MYDUMPER && strings.ToLower(source) != "mysql" {
+				return "", fmt.Errorf("format %s is only supported with MySQL, received source = %v", constants.MYDUMPER, source)
+			}
+			if src.File.Format == constants.BACPAC && !isSQLServerSource(source) {
+				return "", fmt.Errorf("format %s is only supported with SQL Server, received source = %v", constants.BACPAC, source)
+			}
 			switch strings.ToLower(source) {
 			case "mysql":
 				return constants.MYSQLDUMP, nil
 			case "postgresql", "postgres", "pg":
 				return constants.PGDUMP, nil
+			case "sqlite":
+				return constants.SQLITE, nil
+			case "sqlserver", "mssql":
+				if src.File.Format != constants.BACPAC {
+					return "", fmt.Errorf("only BACPAC files are supported with SQL Server, please specify format=%s", constants.BACPAC)
+				}
+				return constants.SQLSERVER, nil
 			case "cassandra":
-				return "", fmt.Errorf("dump files are not supported with Cassandra")	
+				// The file is a CQL schema, e.g. the output of DESCRIBE KEYSPACE.
+				if src.File.Format != "" && src.File.Format != "dump" {
+					return "", fmt.Errorf("only CQL schema files are supported with Cassandra, received format = %v", src.File.Format)
+				}
+				return constants.CASSANDRA, nil
 			default:
 				return "", fmt.Errorf("please specify a valid source database using -source flag, received source = %v", source)
 			}
@@ -659,6 +743,8 @@ func (src SourceProfile) ToLegacyDriver(source string) (string, error) {
 				return constants.ORACLE, nil
 			case "cassandra":
 				return constants.CASSANDRA, nil
+			case "spanner":
+				return constants.SPANNER, nil
 			default:
 				return "", fmt.Errorf("please specify a valid source database using -source flag, received source = %v", source)
 			}
@@ -727,7 +813,8 @@ func NewSourceProfile(s string, source string, n NewSourceProfileInterface) (Sou
 	} else if file, ok := params["config"]; ok {
 		config, err := n.NewSourceProfileConfig(strings.ToLower(source), file)
 		return SourceProfile{Ty: SourceProfileTypeConfig, Config: config}, err
-	} else if _, ok := params["instance"]; ok {
+	} else if _, ok := params["instance"]; ok && strings.ToLower(source) != constants.SPANNER {
+		// A Spanner source also has an instance, but it is not a Cloud SQL one.
 		conn, err := n.NewSourceProfileConnectionCloudSQL(source, params, &SourceProfileDialectImpl{})
 		return SourceProfile{Ty: SourceProfileTypeCloudSQL, ConnCloudSQL: conn}, err
 	} else {
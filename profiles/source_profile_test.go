@@ -72,6 +72,11 @@ func (m *MockSourceProfileDialect) NewSourceProfileConnectionCassandra(params ma
 	return args.Get(0).(SourceProfileConnectionCassandra), args.Error(1)
 }
 
+func (m *MockSourceProfileDialect) NewSourceProfileConnectionSpanner(params map[string]string, g utils.GetUtilInfoInterface) (SourceProfileConnectionSpanner, error) {
+	args := m.Called(params, g)
+	return args.Get(0).(SourceProfileConnectionSpanner), args.Error(1)
+}
+
 func setEnvVariables() {
 	// My Sql variables
 	os.Setenv("MYSQLHOST", "0.0.0.0")
@@ -580,6 +585,61 @@ func TestNewSourceProfileConnectionCassandra(t *testing.T) {
 	}
 }
 
+// code for testing spanner source connection
+func TestNewSourceProfileConnectionSpanner(t *testing.T) {
+	testCases := []struct {
+		name            string
+		params          map[string]string
+		getProjectFails bool
+		expected        SourceProfileConnectionSpanner
+		errorExpected   bool
+	}{
+		{
+			name:          "all params provided",
+			params:        map[string]string{"project": "p", "instance": "i", "dbName": "d"},
+			expected:      SourceProfileConnectionSpanner{Project: "p", Instance: "i", Db: "d"},
+			errorExpected: false,
+		},
+		{
+			name:          "project is fetched from gcloud",
+			params:        map[string]string{"instance": "i", "dbName": "d"},
+			expected:      SourceProfileConnectionSpanner{Project: "project-id", Instance: "i", Db: "d"},
+			errorExpected: false,
+		},
+		{
+			name:            "project is not specified and util getProject() fails",
+			params:          map[string]string{"instance": "i", "dbName": "d"},
+			getProjectFails: true,
+			errorExpected:   true,
+		},
+		{
+			name:          "instance is not specified",
+			params:        map[string]string{"project": "p", "dbName": "d"},
+			errorExpected: true,
+		},
+		{
+			name:          "dbName is blank",
+			params:        map[string]string{"project": "p", "instance": "i", "dbName": ""},
+			errorExpected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		sourceProfileDialect := SourceProfileDialectImpl{}
+		g := GetUtilInfoMock{}
+		if tc.getProjectFails {
+			g.On("GetProject").Return("", fmt.Errorf("error"))
+		} else {
+			g.On("GetProject").Return("project-id", nil)
+		}
+		sp, err := sourceProfileDialect.NewSourceProfileConnectionSpanner(tc.params, &g)
+		assert.Equal(t, tc.errorExpected, err != nil, tc.name)
+		if !tc.errorExpected {
+			assert.Equal(t, tc.expected, sp, tc.name)
+		}
+	}
+}
+
 // code for testing cloud sql mysql connection
 func TestNewSourceProfileConnectionCloudSQLMySQL(t *testing.T) {
 	// Avoid getting/setting env variables in the unit tests.
@@ -773,6 +833,14 @@ func TestNewSourceProfileConnection(t *testing.T) {
 			returnConnProfile: SourceProfileConnectionCassandra{},
 			errorExpected:     true,
 		},
+		{
+			name:              "source spanner",
+			source:            "spanner",
+			params:            map[string]string{},
+			function:          "NewSourceProfileConnectionSpanner",
+			returnConnProfile: SourceProfileConnectionSpanner{},
+			errorExpected:     false,
+		},
 		{
 			name:              "invalid source",
 			source:            "invalid",
@@ -944,6 +1012,20 @@ func TestToLegacyDriver(t *testing.T) {
 			returnConstant: constants.MYSQLDUMP,
 			errorExpected:  false,
 		},
+		{
+			name:           "source profile type FILE with format mydumper and source mysql",
+			srcDriver:      SourceProfile{Ty: SourceProfileTypeFile, File: SourceProfileFile{Format: constants.MYDUMPER}},
+			source:         "mysql",
+			returnConstant: constants.MYSQLDUMP,
+			errorExpected:  false,
+		},
+		{
+			name:           "source profile type FILE with format mydumper and source postgresql",
+			srcDriver:      SourceProfile{Ty: SourceProfileTypeFile, File: SourceProfileFile{Format: constants.MYDUMPER}},
+			source:         "postgresql",
+			returnConstant: "",
+			errorExpected:  true,
+		},
 		{
 			name:           "source profile type FILE and source postgresql",
 			srcDriver:      SourceProfile{Ty: SourceProfileTypeFile},
@@ -952,8 +1034,43 @@ func TestToLegacyDriver(t *testing.T) {
 			errorExpected:  false,
 		},
 		{
-			name:           "source profile type FILE and source cassandra",
+			name:           "source profile type FILE and source sqlite",
+			srcDriver:      SourceProfile{Ty: SourceProfileTypeFile},
+			source:         "sqlite",
+			returnConstant: constants.SQLITE,
+			errorExpected:  false,
+		},
+		{
+			name:           "source profile type FILE with format bacpac and source sqlserver",
+			srcDriver:      SourceProfile{Ty: SourceProfileTypeFile, File: SourceProfileFile{Format: constants.BACPAC}},
+			source:         "sqlserver",
+			returnConstant: constants.SQLSERVER,
+			errorExpected:  false,
+		},
+		{
+			name:           "source profile type FILE with format bacpac and source mysql",
+			srcDriver:      SourceProfile{Ty: SourceProfileTypeFile, File: SourceProfileFile{Format: constants.BACPAC}},
+			source:         "mysql",
+			returnConstant: "",
+			errorExpected:  true,
+		},
+		{
+			name:           "source profile type FILE and source sqlserver",
 			srcDriver:      SourceProfile{Ty: SourceProfileTypeFile},
+			source:         "sqlserver",
+			returnConstant: "",
+			errorExpected:  true,
+		},
+		{
+			name:           "source profile type FILE and source cassandra",
+			srcDriver:      SourceProfile{Ty: SourceProfileTypeFile, File: SourceProfileFile{Format: "dump"}},
+			source:         "cassandra",
+			returnConstant: constants.CASSANDRA,
+			errorExpected:  false,
+		},
+		{
+			name:           "source profile type FILE with format csv and source cassandra",
+			srcDriver:      SourceProfile{Ty: SourceProfileTypeFile, File: SourceProfileFile{Format: "csv"}},
 			source:         "cassandra",
 			returnConstant: "",
 			errorExpected:  true,
@@ -1000,6 +1117,13 @@ func TestToLegacyDriver(t *testing.T) {
 			returnConstant: constants.CASSANDRA,
 			errorExpected:  false,
 		},
+		{
+			name:           "source profile type CONNECTION and source spanner",
+			srcDriver:      SourceProfile{Ty: SourceProfileTypeConnection},
+			source:         "spanner",
+			returnConstant: constants.SPANNER,
+			errorExpected:  false,
+		},
 		{
 			name:           "source profile type CONNECTION and source invalid",
 			srcDriver:      SourceProfile{Ty: SourceProfileTypeConnection},
@@ -1121,6 +1245,15 @@ func TestNewSourceProfile(t *testing.T) {
 			returnTy:      SourceProfileTypeCloudSQL,
 			errorExpected: false,
 		},
+		{
+			name:          "source profile for spanner instance",
+			params:        "instance='instance',dbName='db'",
+			source:        "spanner",
+			function:      "NewSourceProfileConnection",
+			mockReturn:    SourceProfileConnection{},
+			returnTy:      SourceProfileTypeConnection,
+			errorExpected: false,
+		},
 		{
 			name:          "source profile for csv",
 			params:        "",
@@ -111,6 +111,29 @@ type Index struct {
 	Keys            []Key
 	Id              string
 	StoredColumnIds []string
+	// FullText is set for full-text indexes, such as MySQL FULLTEXT indexes
+	// and PostgreSQL GIN indexes on tsvector values.
+	FullText bool
+	// Vector is set for vector indexes, such as pgvector HNSW and IVFFlat
+	// indexes. DistanceType is the Spanner distance type matching the
+	// distance function of the index, or "" if Spanner has none.
+	Vector       bool
+	DistanceType string
+	// Partial is set for indexes on the rows matching a predicate, such as
+	// PostgreSQL indexes with a WHERE clause. NotNullColIds is set for
+	// those whose predicate only requires columns to be non-NULL, e.g.
+	// WHERE a IS NOT NULL, and lists those columns.
+	Partial       bool
+	NotNullColIds []string
+}
+
+// View represents a database view. Definition is the query of the view,
+// in the SQL of the source database.
+type View struct {
+	Name       string
+	Schema     string
+	Definition string
+	Id         string
 }
 
 // Type represents the type of a column.
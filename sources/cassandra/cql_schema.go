@@ -0,0 +1,735 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/file_reader"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/gocql/gocql"
+)
+
+// CqlKeyspaceMetadata is the metadata of a keyspace read from CQL
+// statements, such as the output of DESCRIBE KEYSPACE in cqlsh, rather
+// than from a cluster. Tables are described as gocql describes them, so
+// that InfoSchemaImpl reads them as it reads the tables of a cluster.
+type CqlKeyspaceMetadata struct {
+	Keyspace string
+	tables   map[string]*gocql.TableMetadata
+}
+
+// Tables returns the tables of the keyspace by name.
+func (m *CqlKeyspaceMetadata) Tables() map[string]*gocql.TableMetadata {
+	return m.tables
+}
+
+// cqlNativeTypes are the types of CQL that aren't collections, tuples or
+// user-defined types.
+var cqlNativeTypes = map[string]gocql.Type{
+	"ascii":     gocql.TypeAscii,
+	"bigint":    gocql.TypeBigInt,
+	"blob":      gocql.TypeBlob,
+	"boolean":   gocql.TypeBoolean,
+	"counter":   gocql.TypeCounter,
+	"date":      gocql.TypeDate,
+	"decimal":   gocql.TypeDecimal,
+	"double":    gocql.TypeDouble,
+	"duration":  gocql.TypeDuration,
+	"float":     gocql.TypeFloat,
+	"inet":      gocql.TypeInet,
+	"int":       gocql.TypeInt,
+	"smallint":  gocql.TypeSmallInt,
+	"text":      gocql.TypeText,
+	"time":      gocql.TypeTime,
+	"timestamp": gocql.TypeTimestamp,
+	"timeuuid":  gocql.TypeTimeUUID,
+	"tinyint":   gocql.TypeTinyInt,
+	"uuid":      gocql.TypeUUID,
+	"varchar":   gocql.TypeVarchar,
+	"varint":    gocql.TypeVarint,
+}
+
+// ReadCqlSchema reads the CQL schema file at uri, a local path or a gs://,
+// s3:// or http(s):// uri.
+func ReadCqlSchema(ctx context.Context, uri string) (*CqlKeyspaceMetadata, error) {
+	if uri == "" {
+		return nil, fmt.Errorf("please specify the CQL schema file using -source-profile=\"file=<path>\"")
+	}
+	reader, err := file_reader.NewFileReader(ctx, uri)
+	if err != nil {
+		return nil, fmt.Errorf("can't open CQL schema file: %w", err)
+	}
+	defer reader.Close()
+	data, err := reader.ReadAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can't read CQL schema file: %w", err)
+	}
+	return ParseCqlSchema(string(data))
+}
+
+// ParseCqlSchema parses the CREATE KEYSPACE, CREATE TYPE, CREATE TABLE and
+// CREATE INDEX statements of a keyspace. Other statements, e.g. of
+// materialized views or functions, are skipped. Columns of user-defined
+// types have the custom type, as gocql reports them.
+func ParseCqlSchema(cql string) (*CqlKeyspaceMetadata, error) {
+	tokens, err := lexCql(cql)
+	if err != nil {
+		return nil, err
+	}
+	// Types are parsed before the tables that use them, and tables before
+	// their indexes, whatever the order of the statements.
+	var types, tables, indexes []*cqlParser
+	keyspaces := map[string]bool{}
+	for _, stmt := range splitCqlStatements(tokens) {
+		p := &cqlParser{tokens: stmt}
+		if !p.keyword("create") {
+			logger.Log.Warn(fmt.Sprintf("Skipping CQL statement: %s", p.describe()))
+			continue
+		}
+		switch {
+		case p.keyword("keyspace"):
+			p.keyword("if", "not", "exists")
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			keyspaces[name] = true
+		case p.keyword("type"):
+			types = append(types, p)
+		case p.keyword("table"), p.keyword("columnfamily"):
+			tables = append(tables, p)
+		case p.keyword("index"), p.keyword("custom", "index"):
+			indexes = append(indexes, p)
+		default:
+			logger.Log.Warn(fmt.Sprintf("Skipping CQL statement: %s", p.describe()))
+		}
+	}
+
+	udts := map[string]bool{}
+	for _, p := range types {
+		keyspace, name, err := p.parseCreateType(udts)
+		if err != nil {
+			return nil, err
+		}
+		if keyspace != "" {
+			keyspaces[keyspace] = true
+		}
+		udts[name] = true
+	}
+	m := &CqlKeyspaceMetadata{tables: map[string]*gocql.TableMetadata{}}
+	for _, p := range tables {
+		table, err := p.parseCreateTable(udts)
+		if err != nil {
+			return nil, err
+		}
+		if table.Keyspace != "" {
+			keyspaces[table.Keyspace] = true
+		}
+		if _, found := m.tables[table.Name]; found {
+			return nil, fmt.Errorf("table %s is created more than once", table.Name)
+		}
+		m.tables[table.Name] = table
+	}
+	for _, p := range indexes {
+		if err := p.parseCreateIndex(m.tables); err != nil {
+			return nil, err
+		}
+	}
+	if len(m.tables) == 0 {
+		return nil, fmt.Errorf("no CREATE TABLE statements found")
+	}
+	if len(keyspaces) > 1 {
+		var names []string
+		for name := range keyspaces {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("the CQL schema has the statements of several keyspaces (%s): only one keyspace can be converted at a time", strings.Join(names, ", "))
+	}
+	for name := range keyspaces {
+		m.Keyspace = name
+	}
+	for _, table := range m.tables {
+		table.Keyspace = m.Keyspace
+		for _, col := range table.Columns {
+			col.Keyspace = m.Keyspace
+		}
+	}
+	return m, nil
+}
+
+type cqlTokenKind int
+
+const (
+	cqlIdent       cqlTokenKind = iota // An unquoted identifier or keyword, in lower case.
+	cqlQuotedIdent                     // A double quoted identifier, whose case is preserved.
+	cqlString                          // A string constant, in single quotes or $$.
+	cqlNumber
+	cqlSymbol
+)
+
+type cqlToken struct {
+	kind cqlTokenKind
+	text string
+}
+
+// lexCql splits CQL statements into tokens, skipping comments.
+func lexCql(cql string) ([]cqlToken, error) {
+	var tokens []cqlToken
+	for i := 0; i < len(cql); {
+		c := cql[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(cql[i:], "--") || strings.HasPrefix(cql[i:], "//"):
+			end := strings.IndexByte(cql[i:], '\n')
+			if end < 0 {
+				return tokens, nil
+			}
+			i += end + 1
+		case strings.HasPrefix(cql[i:], "/*"):
+			end := strings.Index(cql[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment in CQL schema")
+			}
+			i += end + 4
+		case strings.HasPrefix(cql[i:], "$$"):
+			end := strings.Index(cql[i+2:], "$$")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string in CQL schema")
+			}
+			tokens = append(tokens, cqlToken{kind: cqlString, text: cql[i+2 : i+2+end]})
+			i += end + 4
+		case c == '\'' || c == '"':
+			text, n, ok := unquoteCql(cql[i:])
+			if !ok {
+				return nil, fmt.Errorf("unterminated quote in CQL schema")
+			}
+			kind := cqlString
+			if c == '"' {
+				kind = cqlQuotedIdent
+			}
+			tokens = append(tokens, cqlToken{kind: kind, text: text})
+			i += n
+		case isCqlIdentChar(c):
+			j := i
+			for j < len(cql) && (isCqlIdentChar(cql[j]) || (cql[i] >= '0' && cql[i] <= '9' && cql[j] == '.')) {
+				j++
+			}
+			if c >= '0' && c <= '9' {
+				tokens = append(tokens, cqlToken{kind: cqlNumber, text: cql[i:j]})
+			} else {
+				tokens = append(tokens, cqlToken{kind: cqlIdent, text: strings.ToLower(cql[i:j])})
+			}
+			i = j
+		default:
+			tokens = append(tokens, cqlToken{kind: cqlSymbol, text: string(c)})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// unquoteCql returns the text quoted at the start of s, in which the quote
+// is escaped by doubling it, and the length of the quoted text.
+func unquoteCql(s string) (string, int, bool) {
+	quote := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		if s[i] != quote {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == quote {
+			b.WriteByte(quote)
+			i++
+			continue
+		}
+		return b.String(), i + 1, true
+	}
+	return "", 0, false
+}
+
+func isCqlIdentChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// splitCqlStatements splits tokens on semicolons.
+func splitCqlStatements(tokens []cqlToken) [][]cqlToken {
+	var stmts [][]cqlToken
+	start := 0
+	for i, t := range tokens {
+		if t.kind == cqlSymbol && t.text == ";" {
+			if i > start {
+				stmts = append(stmts, tokens[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		stmts = append(stmts, tokens[start:])
+	}
+	return stmts
+}
+
+// cqlParser parses the tokens of a statement.
+type cqlParser struct {
+	tokens []cqlToken
+	pos    int
+}
+
+func (p *cqlParser) peek() cqlToken {
+	if p.pos >= len(p.tokens) {
+		return cqlToken{kind: cqlSymbol}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *cqlParser) next() cqlToken {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+// keyword consumes the keywords kws if they are the next tokens.
+func (p *cqlParser) keyword(kws ...string) bool {
+	for i, kw := range kws {
+		if p.pos+i >= len(p.tokens) {
+			return false
+		}
+		t := p.tokens[p.pos+i]
+		if t.kind != cqlIdent || t.text != kw {
+			return false
+		}
+	}
+	p.pos += len(kws)
+	return true
+}
+
+// symbol consumes the symbol s if it is the next token.
+func (p *cqlParser) symbol(s string) bool {
+	if t := p.peek(); t.kind == cqlSymbol && t.text == s {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *cqlParser) expect(s string) error {
+	if !p.symbol(s) {
+		return p.errorf("expected %q", s)
+	}
+	return nil
+}
+
+func (p *cqlParser) name() (string, error) {
+	t := p.next()
+	if t.kind != cqlIdent && t.kind != cqlQuotedIdent {
+		return "", p.errorf("expected a name")
+	}
+	return t.text, nil
+}
+
+// qualifiedName parses a name, optionally prefixed by its keyspace.
+func (p *cqlParser) qualifiedName() (string, string, error) {
+	name, err := p.name()
+	if err != nil {
+		return "", "", err
+	}
+	if !p.symbol(".") {
+		return "", name, nil
+	}
+	keyspace := name
+	name, err = p.name()
+	return keyspace, name, err
+}
+
+// describe returns the first words of the statement, for messages.
+func (p *cqlParser) describe() string {
+	var words []string
+	for _, t := range p.tokens {
+		if len(words) == 4 {
+			words = append(words, "...")
+			break
+		}
+		words = append(words, t.text)
+	}
+	return strings.Join(words, " ")
+}
+
+func (p *cqlParser) errorf(format string, args ...interface{}) error {
+	near := "end of statement"
+	if p.pos < len(p.tokens) {
+		near = fmt.Sprintf("%q", p.tokens[p.pos].text)
+	}
+	return fmt.Errorf("can't parse CQL statement '%s': %s near %s", p.describe(), fmt.Sprintf(format, args...), near)
+}
+
+// parseCreateType parses the rest of a CREATE TYPE statement.
+func (p *cqlParser) parseCreateType(udts map[string]bool) (string, string, error) {
+	p.keyword("if", "not", "exists")
+	keyspace, name, err := p.qualifiedName()
+	if err != nil {
+		return "", "", err
+	}
+	if err := p.expect("("); err != nil {
+		return "", "", err
+	}
+	for {
+		if _, err := p.name(); err != nil {
+			return "", "", err
+		}
+		if _, err := p.typeInfo(udts); err != nil {
+			return "", "", err
+		}
+		if !p.symbol(",") {
+			break
+		}
+	}
+	return keyspace, name, p.expect(")")
+}
+
+// typeInfo parses a type, as gocql reports it in the metadata of a table.
+// udts are the names of the user-defined types.
+func (p *cqlParser) typeInfo(udts map[string]bool) (gocql.TypeInfo, error) {
+	if t := p.peek(); t.kind == cqlString {
+		// A custom type, named by its Java class.
+		p.next()
+		return gocql.NewNativeType(0, gocql.TypeCustom, t.text), nil
+	}
+	quoted := p.peek().kind == cqlQuotedIdent
+	_, name, err := p.qualifiedName()
+	if err != nil {
+		return nil, err
+	}
+	if !quoted {
+		switch name {
+		case "frozen":
+			return p.typeArgs(udts, 1, func(args []gocql.TypeInfo) gocql.TypeInfo { return args[0] })
+		case "list", "set":
+			typ := gocql.TypeList
+			if name == "set" {
+				typ = gocql.TypeSet
+			}
+			return p.typeArgs(udts, 1, func(args []gocql.TypeInfo) gocql.TypeInfo {
+				return gocql.CollectionType{NativeType: gocql.NewNativeType(0, typ, ""), Elem: args[0]}
+			})
+		case "map":
+			return p.typeArgs(udts, 2, func(args []gocql.TypeInfo) gocql.TypeInfo {
+				return gocql.CollectionType{NativeType: gocql.NewNativeType(0, gocql.TypeMap, ""), Key: args[0], Elem: args[1]}
+			})
+		case "tuple":
+			return p.typeArgs(udts, -1, func(args []gocql.TypeInfo) gocql.TypeInfo {
+				return gocql.TupleTypeInfo{NativeType: gocql.NewNativeType(0, gocql.TypeTuple, ""), Elems: args}
+			})
+		case "vector":
+			// vector<float, 3>: the dimension isn't a type.
+			if err := p.expect("<"); err != nil {
+				return nil, err
+			}
+			if _, err := p.typeInfo(udts); err != nil {
+				return nil, err
+			}
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+			if t := p.next(); t.kind != cqlNumber {
+				return nil, p.errorf("expected the dimension of a vector")
+			}
+			return gocql.NewNativeType(0, gocql.TypeCustom, "vector"), p.expect(">")
+		}
+		if typ, ok := cqlNativeTypes[name]; ok {
+			return gocql.NewNativeType(0, typ, ""), nil
+		}
+	}
+	if udts[name] {
+		return gocql.NewNativeType(0, gocql.TypeCustom, name), nil
+	}
+	return nil, p.errorf("unknown type %s", name)
+}
+
+// typeArgs parses the n type arguments of a type, or any number of them
+// if n is -1, and returns the type that build makes of them.
+func (p *cqlParser) typeArgs(udts map[string]bool, n int, build func([]gocql.TypeInfo) gocql.TypeInfo) (gocql.TypeInfo, error) {
+	if err := p.expect("<"); err != nil {
+		return nil, err
+	}
+	var args []gocql.TypeInfo
+	for {
+		arg, err := p.typeInfo(udts)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if !p.symbol(",") {
+			break
+		}
+	}
+	if n >= 0 && len(args) != n {
+		return nil, p.errorf("expected %d type arguments, found %d", n, len(args))
+	}
+	return build(args), p.expect(">")
+}
+
+// parseCreateTable parses the rest of a CREATE TABLE statement.
+func (p *cqlParser) parseCreateTable(udts map[string]bool) (*gocql.TableMetadata, error) {
+	p.keyword("if", "not", "exists")
+	keyspace, name, err := p.qualifiedName()
+	if err != nil {
+		return nil, err
+	}
+	table := &gocql.TableMetadata{Keyspace: keyspace, Name: name, Columns: map[string]*gocql.ColumnMetadata{}}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var regular, partitionKey, clusteringColumns []string
+	for {
+		if p.keyword("primary", "key") {
+			if len(partitionKey) > 0 {
+				return nil, p.errorf("more than one primary key")
+			}
+			if partitionKey, clusteringColumns, err = p.primaryKey(); err != nil {
+				return nil, err
+			}
+		} else {
+			colName, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			typ, err := p.typeInfo(udts)
+			if err != nil {
+				return nil, err
+			}
+			if _, found := table.Columns[colName]; found {
+				return nil, p.errorf("column %s is defined more than once", colName)
+			}
+			col := &gocql.ColumnMetadata{Table: name, Name: colName, Type: typ, Kind: gocql.ColumnRegular}
+			table.Columns[colName] = col
+			regular = append(regular, colName)
+			if p.keyword("static") {
+				col.Kind = gocql.ColumnStatic
+			}
+			if p.keyword("primary", "key") {
+				if len(partitionKey) > 0 {
+					return nil, p.errorf("more than one primary key")
+				}
+				partitionKey = []string{colName}
+			}
+		}
+		if !p.symbol(",") {
+			break
+		}
+		// cqlsh writes no trailing comma, but it is accepted.
+		if t := p.peek(); t.kind == cqlSymbol && t.text == ")" {
+			break
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if len(partitionKey) == 0 {
+		return nil, fmt.Errorf("table %s has no primary key", name)
+	}
+
+	keyColumns := map[string]bool{}
+	for i, colName := range partitionKey {
+		col, ok := table.Columns[colName]
+		if !ok {
+			return nil, fmt.Errorf("primary key column %s of table %s is not defined", colName, name)
+		}
+		col.Kind, col.ComponentIndex = gocql.ColumnPartitionKey, i
+		table.PartitionKey = append(table.PartitionKey, col)
+		keyColumns[colName] = true
+	}
+	for i, colName := range clusteringColumns {
+		col, ok := table.Columns[colName]
+		if !ok {
+			return nil, fmt.Errorf("clustering column %s of table %s is not defined", colName, name)
+		}
+		col.Kind, col.ComponentIndex = gocql.ColumnClusteringKey, i
+		col.ClusteringOrder = "asc"
+		table.ClusteringColumns = append(table.ClusteringColumns, col)
+		keyColumns[colName] = true
+	}
+	table.OrderedColumns = append(append([]string{}, partitionKey...), clusteringColumns...)
+	for _, colName := range regular {
+		if !keyColumns[colName] {
+			table.OrderedColumns = append(table.OrderedColumns, colName)
+		}
+	}
+	return table, p.clusteringOrder(table)
+}
+
+// primaryKey parses the columns of a PRIMARY KEY clause: the partition key
+// is the first column, or the columns in parentheses, and the other
+// columns are the clustering columns.
+func (p *cqlParser) primaryKey() ([]string, []string, error) {
+	if err := p.expect("("); err != nil {
+		return nil, nil, err
+	}
+	var partitionKey []string
+	if p.symbol("(") {
+		names, err := p.names()
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, nil, err
+		}
+		partitionKey = names
+	} else {
+		colName, err := p.name()
+		if err != nil {
+			return nil, nil, err
+		}
+		partitionKey = []string{colName}
+	}
+	var clusteringColumns []string
+	if p.symbol(",") {
+		names, err := p.names()
+		if err != nil {
+			return nil, nil, err
+		}
+		clusteringColumns = names
+	}
+	return partitionKey, clusteringColumns, p.expect(")")
+}
+
+// names parses a list of names separated by commas.
+func (p *cqlParser) names() ([]string, error) {
+	var names []string
+	for {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !p.symbol(",") {
+			return names, nil
+		}
+	}
+}
+
+// clusteringOrder sets the order of the clustering columns from the
+// CLUSTERING ORDER BY option of the table, if any. Other options are
+// skipped.
+func (p *cqlParser) clusteringOrder(table *gocql.TableMetadata) error {
+	for p.pos < len(p.tokens) {
+		if !p.keyword("clustering", "order", "by") {
+			p.next()
+			continue
+		}
+		if err := p.expect("("); err != nil {
+			return err
+		}
+		for {
+			colName, err := p.name()
+			if err != nil {
+				return err
+			}
+			col, ok := table.Columns[colName]
+			if !ok || col.Kind != gocql.ColumnClusteringKey {
+				return p.errorf("%s is not a clustering column", colName)
+			}
+			if p.keyword("desc") {
+				col.ClusteringOrder, col.Order = "desc", gocql.DESC
+			} else {
+				p.keyword("asc")
+			}
+			if !p.symbol(",") {
+				break
+			}
+		}
+		return p.expect(")")
+	}
+	return nil
+}
+
+// parseCreateIndex parses the rest of a CREATE [CUSTOM] INDEX statement,
+// and sets the index of the indexed column of its table. The column of
+// an index on the keys, values or entries of a collection is indexed.
+func (p *cqlParser) parseCreateIndex(tables map[string]*gocql.TableMetadata) error {
+	custom := p.tokens[1].text == "custom"
+	p.keyword("if", "not", "exists")
+	name := ""
+	if !p.keyword("on") {
+		var err error
+		if name, err = p.name(); err != nil {
+			return err
+		}
+		if !p.keyword("on") {
+			return p.errorf("expected ON")
+		}
+	}
+	_, tableName, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	table, ok := tables[tableName]
+	if !ok {
+		return p.errorf("table %s is not defined", tableName)
+	}
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	target := ""
+	if p.keyword("keys") || p.keyword("values") || p.keyword("entries") || p.keyword("full") {
+		target = p.tokens[p.pos-1].text
+		if err := p.expect("("); err != nil {
+			return err
+		}
+	}
+	colName, err := p.name()
+	if err != nil {
+		return err
+	}
+	if target != "" {
+		if err := p.expect(")"); err != nil {
+			return err
+		}
+		target = fmt.Sprintf("%s(%s)", target, colName)
+	} else {
+		target = colName
+	}
+	if err := p.expect(")"); err != nil {
+		return err
+	}
+	col, ok := table.Columns[colName]
+	if !ok {
+		return p.errorf("column %s of table %s is not defined", colName, tableName)
+	}
+	if name == "" {
+		// The name Cassandra gives to an index without a name.
+		name = fmt.Sprintf("%s_%s_idx", tableName, colName)
+	}
+	if col.Index.Name != "" {
+		logger.Log.Warn(fmt.Sprintf("Skipping index %s: column %s of table %s already has index %s", name, colName, tableName, col.Index.Name))
+		return nil
+	}
+	col.Index = gocql.ColumnIndexMetadata{Name: name, Type: "COMPOSITES", Options: map[string]interface{}{"target": target}}
+	if custom {
+		col.Index.Type = "CUSTOM"
+		if p.keyword("using") {
+			if t := p.next(); t.kind == cqlString {
+				col.Index.Options["class_name"] = t.text
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cassandra
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/expressions_api"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/mocks"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testDescribeKeyspace is the output of DESCRIBE KEYSPACE in cqlsh.
+const testDescribeKeyspace = `
+CREATE KEYSPACE shop WITH replication = {'class': 'SimpleStrategy', 'replication_factor': '1'}  AND durable_writes = true;
+
+CREATE TYPE shop.address (
+    street text,
+    zip int
+);
+
+CREATE TABLE shop.users (
+    id uuid PRIMARY KEY,
+    "Name" text,
+    emails set<text>,
+    home frozen<address>,
+    scores map<text, int>
+) WITH additional_write_policy = '99p'
+    AND bloom_filter_fp_chance = 0.01
+    AND caching = {'keys': 'ALL', 'rows_per_partition': 'NONE'}
+    AND comment = 'it''s the users; all of them'
+    AND default_time_to_live = 0;
+
+CREATE INDEX users_name_idx ON shop.users ("Name");
+CREATE CUSTOM INDEX ON shop.users (values(emails)) USING 'StorageAttachedIndex';
+
+/*
+Warning: Table shop.legacy omitted because it has constructs not compatible with CQL (was created via legacy API).
+*/
+
+CREATE TABLE shop.events (
+    user_id uuid,
+    day date,
+    ts timeuuid,
+    kind text static,
+    payload list<frozen<tuple<int, text>>>,
+    PRIMARY KEY ((user_id, day), ts)
+) WITH CLUSTERING ORDER BY (ts DESC)
+    AND compaction = {'class': 'org.apache.cassandra.db.compaction.SizeTieredCompactionStrategy'};
+
+-- Materialized views aren't converted.
+CREATE MATERIALIZED VIEW shop.events_by_kind AS
+    SELECT * FROM shop.events
+    WHERE kind IS NOT NULL AND user_id IS NOT NULL AND day IS NOT NULL AND ts IS NOT NULL
+    PRIMARY KEY (kind, user_id, day, ts);
+`
+
+func TestParseCqlSchema(t *testing.T) {
+	m, err := ParseCqlSchema(testDescribeKeyspace)
+	require.NoError(t, err)
+	assert.Equal(t, "shop", m.Keyspace)
+	assert.Len(t, m.Tables(), 2)
+
+	users := m.Tables()["users"]
+	require.NotNil(t, users)
+	assert.Equal(t, "shop", users.Keyspace)
+	assert.Equal(t, []string{"id", "Name", "emails", "home", "scores"}, users.OrderedColumns)
+	assert.Equal(t, []*gocql.ColumnMetadata{users.Columns["id"]}, users.PartitionKey)
+	assert.Empty(t, users.ClusteringColumns)
+	assert.Equal(t, gocql.ColumnIndexMetadata{Name: "users_name_idx", Type: "COMPOSITES", Options: map[string]interface{}{"target": "Name"}}, users.Columns["Name"].Index)
+	assert.Equal(t, gocql.ColumnIndexMetadata{Name: "users_emails_idx", Type: "CUSTOM",
+		Options: map[string]interface{}{"target": "values(emails)", "class_name": "StorageAttachedIndex"}}, users.Columns["emails"].Index)
+	for name, want := range map[string]string{"id": "uuid", "Name": "text", "emails": "set<text>", "home": "custom", "scores": "map<text,int>"} {
+		typ, err := getTypeString(users.Columns[name].Type)
+		assert.NoError(t, err)
+		assert.Equal(t, want, typ, name)
+	}
+
+	events := m.Tables()["events"]
+	require.NotNil(t, events)
+	assert.Equal(t, []string{"user_id", "day", "ts", "kind", "payload"}, events.OrderedColumns)
+	assert.Equal(t, []*gocql.ColumnMetadata{events.Columns["user_id"], events.Columns["day"]}, events.PartitionKey)
+	assert.Equal(t, []*gocql.ColumnMetadata{events.Columns["ts"]}, events.ClusteringColumns)
+	assert.Equal(t, gocql.DESC, events.Columns["ts"].Order)
+	assert.Equal(t, gocql.ColumnStatic, events.Columns["kind"].Kind)
+	typ, err := getTypeString(events.Columns["payload"].Type)
+	assert.NoError(t, err)
+	assert.Equal(t, "list<tuple>", typ)
+}
+
+func TestParseCqlSchema_Errors(t *testing.T) {
+	testCases := []struct {
+		name string
+		cql  string
+	}{
+		{"no tables", `CREATE KEYSPACE shop WITH replication = {'class': 'SimpleStrategy'};`},
+		{"unknown type", `CREATE TABLE t (id int PRIMARY KEY, home frozen<address>);`},
+		{"no primary key", `CREATE TABLE t (id int, name text);`},
+		{"undefined key column", `CREATE TABLE t (id int, name text, PRIMARY KEY (id, day));`},
+		{"two keyspaces", `CREATE TABLE a.t (id int PRIMARY KEY); CREATE TABLE b.t2 (id int PRIMARY KEY);`},
+		{"index of undefined table", `CREATE TABLE t (id int PRIMARY KEY); CREATE INDEX ON u (id);`},
+		{"unterminated string", `CREATE TABLE t (id int PRIMARY KEY) WITH comment = 'users;`},
+		{"bad map", `CREATE TABLE t (id int PRIMARY KEY, m map<text>);`},
+	}
+	for _, tc := range testCases {
+		_, err := ParseCqlSchema(tc.cql)
+		assert.Error(t, err, tc.name)
+	}
+}
+
+func TestReadCqlSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shop.cql")
+	require.NoError(t, os.WriteFile(path, []byte(testDescribeKeyspace), 0644))
+	m, err := ReadCqlSchema(context.Background(), path)
+	require.NoError(t, err)
+	assert.Len(t, m.Tables(), 2)
+
+	_, err = ReadCqlSchema(context.Background(), filepath.Join(t.TempDir(), "missing.cql"))
+	assert.Error(t, err)
+	_, err = ReadCqlSchema(context.Background(), "")
+	assert.Error(t, err)
+}
+
+func TestCqlSchemaProcessSchema(t *testing.T) {
+	m, err := ParseCqlSchema(testDescribeKeyspace)
+	require.NoError(t, err)
+	sourceProfile := profiles.SourceProfile{}
+	sourceProfile.Conn.Cassandra.Keyspace = m.Keyspace
+	isi := InfoSchemaImpl{KeyspaceMetadata: m, SourceProfile: sourceProfile}
+
+	conv := internal.MakeConv()
+	conv.Source = "cassandra"
+	mockAccessor := new(mocks.MockExpressionVerificationAccessor)
+	mockAccessor.On("VerifyExpressions", context.Background(), mock.Anything).Return(internal.VerifyExpressionsOutput{})
+	schemaToSpanner := common.SchemaToSpannerImpl{
+		ExpressionVerificationAccessor: mockAccessor,
+		DdlV:                           &expressions_api.MockDDLVerifier{},
+	}
+	processSchema := common.ProcessSchemaImpl{}
+	err = processSchema.ProcessSchema(conv, isi, 1, internal.AdditionalSchemaAttributes{}, &schemaToSpanner, &common.UtilsOrderImpl{}, &common.InfoSchemaImpl{})
+	require.NoError(t, err)
+
+	tableId, err := internal.GetTableIdFromSpName(conv.SpSchema, "users")
+	require.NoError(t, err)
+	users := conv.SpSchema[tableId]
+	types := map[string]ddl.Type{}
+	for _, col := range users.ColDefs {
+		types[col.Name] = col.T
+	}
+	assert.Equal(t, map[string]ddl.Type{
+		"id":     {Name: ddl.String, Len: ddl.MaxLength},
+		"Name":   {Name: ddl.String, Len: ddl.MaxLength},
+		"emails": {Name: ddl.String, Len: ddl.MaxLength, IsArray: true},
+		"home":   {Name: ddl.String, Len: ddl.MaxLength},
+		"scores": {Name: ddl.JSON},
+	}, types)
+	assert.Len(t, users.Indexes, 2)
+
+	tableId, err = internal.GetTableIdFromSpName(conv.SpSchema, "events")
+	require.NoError(t, err)
+	events := conv.SpSchema[tableId]
+	var keys []string
+	for _, k := range events.PrimaryKeys {
+		keys = append(keys, events.ColDefs[k.ColId].Name)
+	}
+	assert.Equal(t, []string{"user_id", "day", "ts"}, keys)
+
+	_, err = isi.GetRowCount(common.SchemaAndName{Schema: "shop", Name: "users"})
+	assert.Error(t, err, "rows can't be read from a CQL schema")
+}
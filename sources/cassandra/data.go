@@ -0,0 +1,463 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassandra
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/gocql/gocql"
+	"gopkg.in/inf.v0"
+)
+
+// ProcessDataRow converts a row of data and writes it out to Spanner.
+// srcSchema and spSchema are the source and Spanner tables, and vals
+// contains the values returned by gocql for the columns in colIds.
+// ProcessDataRow is only called in DataMode.
+func ProcessDataRow(conv *internal.Conv, tableId string, colIds []string, srcSchema schema.Table, spSchema ddl.CreateTable, vals []interface{}) {
+	spTableName, cvtCols, cvtVals, err := ConvertData(conv, tableId, colIds, srcSchema, spSchema, vals)
+	srcTableName := srcSchema.Name
+	if err != nil {
+		srcCols := []string{}
+		for _, colId := range colIds {
+			srcCols = append(srcCols, srcSchema.ColDefs[colId].Name)
+		}
+		conv.Unexpected(fmt.Sprintf("Error while converting data: %s\n", err))
+		conv.StatsAddBadRow(srcTableName, conv.DataMode())
+		conv.CollectBadRow(srcTableName, srcCols, valsToStrings(vals), err)
+	} else {
+		conv.WriteRow(srcTableName, spTableName, cvtCols, cvtVals)
+	}
+}
+
+// ConvertData maps the Cassandra values in vals into Spanner data,
+// based on the Spanner and source DB schemas. Unlike the SQL sources,
+// values arrive already typed by gocql (e.g. int16, gocql.UUID,
+// []string, map[string]interface{} for UDTs), so conversion works on
+// the Go types rather than on strings. Null values are dropped, so
+// we also return the list of columns that were written.
+func ConvertData(conv *internal.Conv, tableId string, colIds []string, srcSchema schema.Table, spSchema ddl.CreateTable, vals []interface{}) (string, []string, []interface{}, error) {
+	var c []string
+	var v []interface{}
+	if len(colIds) != len(vals) {
+		return "", []string{}, []interface{}{}, fmt.Errorf("ConvertData: colIds and vals don't all have the same lengths: len(colIds)=%d, len(vals)=%d", len(colIds), len(vals))
+	}
+	for i, colId := range colIds {
+		if isNull(vals[i]) {
+			continue
+		}
+		spColDef, ok1 := spSchema.ColDefs[colId]
+		srcColDef, ok2 := srcSchema.ColDefs[colId]
+		if !ok1 || !ok2 {
+			return "", []string{}, []interface{}{}, fmt.Errorf("can't find Spanner and source-db schema for colId %s", colId)
+		}
+		var x interface{}
+		var err error
+		if spColDef.T.IsArray {
+			x, err = convArray(conv, spColDef.T, srcColDef.Type.Name, vals[i])
+		} else {
+			x, err = convScalar(conv, spColDef.T, srcColDef.Type.Name, vals[i])
+		}
+		if err != nil {
+			return "", []string{}, []interface{}{}, fmt.Errorf("column %s: %w", srcColDef.Name, err)
+		}
+		v = append(v, x)
+		c = append(c, spColDef.Name)
+	}
+	if aux, ok := conv.NextSyntheticPKey(tableId); ok {
+		c = append(c, conv.SpSchema[tableId].ColDefs[aux.ColId].Name)
+		v = append(v, fmt.Sprintf("%d", int64(bits.Reverse64(uint64(aux.Sequence)))))
+	}
+	return spSchema.Name, c, v, nil
+}
+
+// isNull reports whether val represents a Cassandra null. Cassandra
+// does not distinguish between null and empty collections, so empty
+// lists, sets and maps are treated as null as well.
+func isNull(val interface{}) bool {
+	if val == nil {
+		return true
+	}
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return rv.IsNil()
+	case reflect.Slice:
+		if _, ok := val.([]byte); ok {
+			return rv.IsNil()
+		}
+		return rv.Len() == 0
+	case reflect.Map:
+		return rv.Len() == 0
+	}
+	return false
+}
+
+// convScalar converts a gocql value to the Go value expected by the
+// Spanner client for spannerType. srcTypeName is the Cassandra type
+// of the column (e.g. "timeuuid" or "map<text,int>").
+func convScalar(conv *internal.Conv, spannerType ddl.Type, srcTypeName string, val interface{}) (interface{}, error) {
+	switch spannerType.Name {
+	case ddl.Bool:
+		return convBool(val)
+	case ddl.Bytes:
+		return convBytes(val)
+	case ddl.Date:
+		return convDate(val)
+	case ddl.Float32:
+		return convFloat32(val)
+	case ddl.Float64:
+		return convFloat64(val)
+	case ddl.Int64:
+		return convInt64(val)
+	case ddl.Numeric:
+		return convNumeric(conv, val)
+	case ddl.String:
+		return convString(srcTypeName, val)
+	case ddl.Timestamp:
+		return convTimestamp(val)
+	case ddl.JSON:
+		return convJSON(val)
+	default:
+		return val, fmt.Errorf("data conversion not implemented for type %v", spannerType.Name)
+	}
+}
+
+func convBool(val interface{}) (bool, error) {
+	switch v := val.(type) {
+	case bool:
+		return v, nil
+	}
+	return false, fmt.Errorf("can't convert %T to bool", val)
+}
+
+func convBytes(val interface{}) ([]byte, error) {
+	switch v := val.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	case gocql.UUID:
+		return v.Bytes(), nil
+	case *big.Int:
+		return varintBytes(v), nil
+	}
+	return nil, fmt.Errorf("can't convert %T to bytes", val)
+}
+
+func convDate(val interface{}) (civil.Date, error) {
+	switch v := val.(type) {
+	case time.Time:
+		return civil.DateOf(v.UTC()), nil
+	}
+	return civil.Date{}, fmt.Errorf("can't convert %T to date", val)
+}
+
+func convFloat32(val interface{}) (float32, error) {
+	switch v := val.(type) {
+	case float32:
+		return v, nil
+	case float64:
+		return float32(v), nil
+	}
+	return 0, fmt.Errorf("can't convert %T to float32", val)
+}
+
+func convFloat64(val interface{}) (float64, error) {
+	switch v := val.(type) {
+	case float32:
+		// Go through the shortest decimal representation so that e.g.
+		// 0.1 doesn't become 0.10000000149011612.
+		return strconv.ParseFloat(strconv.FormatFloat(float64(v), 'g', -1, 32), 64)
+	case float64:
+		return v, nil
+	}
+	return 0, fmt.Errorf("can't convert %T to float64", val)
+}
+
+// convInt64 handles the Cassandra integer types, counters, and the
+// TIME type, which is stored as nanoseconds since midnight.
+func convInt64(val interface{}) (int64, error) {
+	switch v := val.(type) {
+	case int:
+		return int64(v), nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case time.Duration:
+		return int64(v), nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case *big.Int:
+		if !v.IsInt64() {
+			return 0, fmt.Errorf("varint %s out of range for int64", v.String())
+		}
+		return v.Int64(), nil
+	}
+	return 0, fmt.Errorf("can't convert %T to int64", val)
+}
+
+// convNumeric maps DECIMAL and VARINT values to a Spanner numeric.
+func convNumeric(conv *internal.Conv, val interface{}) (interface{}, error) {
+	var s string
+	switch v := val.(type) {
+	case *inf.Dec:
+		s = v.String()
+	case *big.Int:
+		s = v.String()
+	default:
+		return nil, fmt.Errorf("can't convert %T to numeric", val)
+	}
+	if conv.SpDialect == constants.DIALECT_POSTGRESQL {
+		return spanner.PGNumeric{Numeric: s, Valid: true}, nil
+	}
+	r := new(big.Rat)
+	if _, ok := r.SetString(s); !ok {
+		return nil, fmt.Errorf("can't convert %q to big.Rat", s)
+	}
+	return r, nil
+}
+
+func convTimestamp(val interface{}) (time.Time, error) {
+	switch v := val.(type) {
+	case time.Time:
+		return v, nil
+	}
+	return time.Time{}, fmt.Errorf("can't convert %T to timestamp", val)
+}
+
+// convString renders val as text. Collections, tuples and UDTs are
+// rendered as JSON; everything else uses the CQL literal format.
+func convString(srcTypeName string, val interface{}) (string, error) {
+	switch reflect.ValueOf(val).Kind() {
+	case reflect.Map, reflect.Slice:
+		if _, ok := val.([]byte); !ok {
+			return convJSON(val)
+		}
+	}
+	return scalarToString(srcTypeName, val), nil
+}
+
+// convJSON serializes maps, sets, lists, tuples and UDTs into a JSON
+// document. Non-string map keys are converted to strings since JSON
+// objects only allow string keys.
+func convJSON(val interface{}) (string, error) {
+	b, err := json.Marshal(toJSONValue(val))
+	if err != nil {
+		return "", fmt.Errorf("can't convert %T to json: %w", val, err)
+	}
+	return string(b), nil
+}
+
+func toJSONValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return v
+	case gocql.UUID, time.Time, time.Duration, gocql.Duration:
+		return scalarToString("", v)
+	case *inf.Dec:
+		return json.Number(v.String())
+	case *big.Int:
+		return json.Number(v.String())
+	}
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Map:
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			m[scalarToString("", iter.Key().Interface())] = toJSONValue(iter.Value().Interface())
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		a := make([]interface{}, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			a[i] = toJSONValue(rv.Index(i).Interface())
+		}
+		return a
+	}
+	return val
+}
+
+func scalarToString(srcTypeName string, val interface{}) string {
+	switch v := val.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case gocql.UUID:
+		return v.String()
+	case time.Time:
+		if strings.EqualFold(srcTypeName, "date") {
+			return v.UTC().Format("2006-01-02")
+		}
+		return v.UTC().Format(time.RFC3339Nano)
+	case time.Duration:
+		// CQL TIME literal: hh:mm:ss.nnnnnnnnn
+		return fmt.Sprintf("%02d:%02d:%02d.%09d", v/time.Hour, v%time.Hour/time.Minute, v%time.Minute/time.Second, v%time.Second)
+	case gocql.Duration:
+		return fmt.Sprintf("%dmo%dd%dns", v.Months, v.Days, v.Nanoseconds)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case *inf.Dec:
+		return v.String()
+	case *big.Int:
+		return v.String()
+	}
+	return fmt.Sprint(val)
+}
+
+// varintBytes encodes n the way Cassandra's varintAsBlob does: as a
+// minimal big-endian two's complement integer.
+func varintBytes(n *big.Int) []byte {
+	m := n
+	if n.Sign() < 0 {
+		m = new(big.Int).Not(n)
+	}
+	l := m.BitLen()/8 + 1
+	t := n
+	if n.Sign() < 0 {
+		t = new(big.Int).Add(n, new(big.Int).Lsh(big.NewInt(1), uint(8*l)))
+	}
+	return t.FillBytes(make([]byte, l))
+}
+
+// convArray converts a Cassandra list or set to a Spanner array. The
+// Spanner client for go does not accept []interface{} for arrays, so
+// we build a slice of the specific type for spannerType.
+func convArray(conv *internal.Conv, spannerType ddl.Type, srcTypeName string, val interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(val)
+	if rv.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("can't convert %T to array", val)
+	}
+	elemTypeName := srcTypeName
+	if m := listSetRegex.FindStringSubmatch(strings.ToUpper(strings.ReplaceAll(srcTypeName, " ", ""))); len(m) > 0 {
+		elemTypeName = strings.ToLower(m[2])
+	}
+	elemType := ddl.Type{Name: spannerType.Name, Len: spannerType.Len}
+	elems := make([]interface{}, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		x, err := convScalar(conv, elemType, elemTypeName, rv.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		elems[i] = x
+	}
+	switch spannerType.Name {
+	case ddl.Bool:
+		r := []spanner.NullBool{}
+		for _, e := range elems {
+			r = append(r, spanner.NullBool{Bool: e.(bool), Valid: true})
+		}
+		return r, nil
+	case ddl.Bytes:
+		r := [][]byte{}
+		for _, e := range elems {
+			r = append(r, e.([]byte))
+		}
+		return r, nil
+	case ddl.Date:
+		r := []spanner.NullDate{}
+		for _, e := range elems {
+			r = append(r, spanner.NullDate{Date: e.(civil.Date), Valid: true})
+		}
+		return r, nil
+	case ddl.Float32:
+		r := []spanner.NullFloat32{}
+		for _, e := range elems {
+			r = append(r, spanner.NullFloat32{Float32: e.(float32), Valid: true})
+		}
+		return r, nil
+	case ddl.Float64:
+		r := []spanner.NullFloat64{}
+		for _, e := range elems {
+			r = append(r, spanner.NullFloat64{Float64: e.(float64), Valid: true})
+		}
+		return r, nil
+	case ddl.Int64:
+		r := []spanner.NullInt64{}
+		for _, e := range elems {
+			r = append(r, spanner.NullInt64{Int64: e.(int64), Valid: true})
+		}
+		return r, nil
+	case ddl.Numeric:
+		if conv.SpDialect == constants.DIALECT_POSTGRESQL {
+			r := []spanner.PGNumeric{}
+			for _, e := range elems {
+				r = append(r, e.(spanner.PGNumeric))
+			}
+			return r, nil
+		}
+		r := []spanner.NullNumeric{}
+		for _, e := range elems {
+			r = append(r, spanner.NullNumeric{Numeric: *e.(*big.Rat), Valid: true})
+		}
+		return r, nil
+	case ddl.String:
+		r := []spanner.NullString{}
+		for _, e := range elems {
+			r = append(r, spanner.NullString{StringVal: e.(string), Valid: true})
+		}
+		return r, nil
+	case ddl.Timestamp:
+		r := []spanner.NullTime{}
+		for _, e := range elems {
+			r = append(r, spanner.NullTime{Time: e.(time.Time), Valid: true})
+		}
+		return r, nil
+	}
+	return nil, fmt.Errorf("array type conversion not implemented for type %v", spannerType.Name)
+}
+
+// valsToStrings renders gocql values for bad row reporting.
+func valsToStrings(vals []interface{}) []string {
+	s := make([]string, len(vals))
+	for i, v := range vals {
+		if isNull(v) {
+			s[i] = "NULL"
+			continue
+		}
+		if str, err := convString("", v); err == nil {
+			s[i] = str
+		} else {
+			s[i] = fmt.Sprint(v)
+		}
+	}
+	return s
+}
@@ -0,0 +1,188 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassandra
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/inf.v0"
+)
+
+type spannerData struct {
+	table string
+	cols  []string
+	vals  []interface{}
+}
+
+func buildConv(spTable ddl.CreateTable, srcTable schema.Table) *internal.Conv {
+	conv := internal.MakeConv()
+	conv.SpSchema[spTable.Id] = spTable
+	conv.SrcSchema[srcTable.Id] = srcTable
+	return conv
+}
+
+func TestProcessDataRow(t *testing.T) {
+	colIds := []string{"c1", "c2", "c3"}
+	conv := buildConv(
+		ddl.CreateTable{
+			Name:   "events",
+			Id:     "t1",
+			ColIds: colIds,
+			ColDefs: map[string]ddl.ColumnDef{
+				"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+				"c2": {Name: "hits", Id: "c2", T: ddl.Type{Name: ddl.Int64}},
+				"c3": {Name: "tags", Id: "c3", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: true}},
+			}},
+		schema.Table{
+			Name:   "events",
+			Id:     "t1",
+			ColIds: colIds,
+			ColDefs: map[string]schema.Column{
+				"c1": {Name: "id", Id: "c1", Type: schema.Type{Name: "uuid"}},
+				"c2": {Name: "hits", Id: "c2", Type: schema.Type{Name: "counter"}},
+				"c3": {Name: "tags", Id: "c3", Type: schema.Type{Name: "set<text>"}},
+			}})
+	conv.SetDataMode()
+	var rows []spannerData
+	conv.SetDataSink(func(table string, cols []string, vals []interface{}) {
+		rows = append(rows, spannerData{table: table, cols: cols, vals: vals})
+	})
+	id := gocql.MustRandomUUID()
+	ProcessDataRow(conv, "t1", colIds, conv.SrcSchema["t1"], conv.SpSchema["t1"], []interface{}{id, int64(7), []string{}})
+	assert.Equal(t, []spannerData{{table: "events", cols: []string{"id", "hits"}, vals: []interface{}{id.String(), int64(7)}}}, rows)
+
+	// A value of the wrong type is reported as a bad row.
+	ProcessDataRow(conv, "t1", colIds, conv.SrcSchema["t1"], conv.SpSchema["t1"], []interface{}{id, "seven", nil})
+	assert.Equal(t, 1, len(rows))
+	assert.Equal(t, int64(1), conv.BadRows())
+}
+
+func TestConvertData(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 123000000, time.UTC)
+	id, _ := gocql.ParseUUID("8e2c5b8a-1b4c-11ef-9262-0242ac120002")
+	dec := inf.NewDec(12345, 2)
+	numVal, _ := new(big.Rat).SetString("123.45")
+	tests := []struct {
+		name    string
+		srcType string
+		spType  ddl.Type
+		in      interface{}
+		e       interface{}
+	}{
+		{"tinyint", "tinyint", ddl.Type{Name: ddl.Int64}, int8(-3), int64(-3)},
+		{"smallint", "smallint", ddl.Type{Name: ddl.Int64}, int16(300), int64(300)},
+		{"int", "int", ddl.Type{Name: ddl.Int64}, 70000, int64(70000)},
+		{"counter", "counter", ddl.Type{Name: ddl.Int64}, int64(42), int64(42)},
+		{"varint", "varint", ddl.Type{Name: ddl.Int64}, big.NewInt(99), int64(99)},
+		{"time as int64", "time", ddl.Type{Name: ddl.Int64}, 90 * time.Second, int64(90 * time.Second)},
+		{"time as text", "time", ddl.Type{Name: ddl.String}, time.Hour + 2*time.Minute + 3*time.Second + 4, "01:02:03.000000004"},
+		{"float", "float", ddl.Type{Name: ddl.Float32}, float32(1.5), float32(1.5)},
+		{"float widened", "float", ddl.Type{Name: ddl.Float64}, float32(0.1), float64(0.1)},
+		{"double", "double", ddl.Type{Name: ddl.Float64}, 2.25, 2.25},
+		{"decimal", "decimal", ddl.Type{Name: ddl.Numeric}, dec, numVal},
+		{"varint numeric", "varint", ddl.Type{Name: ddl.Numeric}, big.NewInt(-5), big.NewRat(-5, 1)},
+		{"varint blob", "varint", ddl.Type{Name: ddl.Bytes}, big.NewInt(-129), []byte{0xff, 0x7f}},
+		{"varint text", "varint", ddl.Type{Name: ddl.String}, big.NewInt(123), "123"},
+		{"boolean", "boolean", ddl.Type{Name: ddl.Bool}, true, true},
+		{"boolean as int", "boolean", ddl.Type{Name: ddl.Int64}, true, int64(1)},
+		{"text", "text", ddl.Type{Name: ddl.String}, "hello", "hello"},
+		{"text as blob", "text", ddl.Type{Name: ddl.Bytes}, "hi", []byte("hi")},
+		{"blob", "blob", ddl.Type{Name: ddl.Bytes}, []byte{1, 2}, []byte{1, 2}},
+		{"uuid", "uuid", ddl.Type{Name: ddl.String}, id, "8e2c5b8a-1b4c-11ef-9262-0242ac120002"},
+		{"timeuuid as bytes", "timeuuid", ddl.Type{Name: ddl.Bytes, Len: 16}, id, id.Bytes()},
+		{"inet", "inet", ddl.Type{Name: ddl.String}, "10.0.0.1", "10.0.0.1"},
+		{"date", "date", ddl.Type{Name: ddl.Date}, ts, civil.Date{Year: 2024, Month: 5, Day: 6}},
+		{"date as text", "date", ddl.Type{Name: ddl.String}, ts, "2024-05-06"},
+		{"timestamp", "timestamp", ddl.Type{Name: ddl.Timestamp}, ts, ts},
+		{"timestamp as text", "timestamp", ddl.Type{Name: ddl.String}, ts, "2024-05-06T07:08:09.123Z"},
+		{"duration", "duration", ddl.Type{Name: ddl.String}, gocql.Duration{Months: 1, Days: 2, Nanoseconds: 3}, "1mo2d3ns"},
+		{"list", "list<int>", ddl.Type{Name: ddl.Int64, IsArray: true}, []int{1, 2}, []spanner.NullInt64{{Int64: 1, Valid: true}, {Int64: 2, Valid: true}}},
+		{"set", "set<text>", ddl.Type{Name: ddl.String, IsArray: true}, []string{"a", "b"}, []spanner.NullString{{StringVal: "a", Valid: true}, {StringVal: "b", Valid: true}}},
+		{"set of uuid", "set<uuid>", ddl.Type{Name: ddl.String, IsArray: true}, []gocql.UUID{id}, []spanner.NullString{{StringVal: id.String(), Valid: true}}},
+		{"list of timestamp", "list<timestamp>", ddl.Type{Name: ddl.Timestamp, IsArray: true}, []time.Time{ts}, []spanner.NullTime{{Time: ts, Valid: true}}},
+		{"map", "map<text,int>", ddl.Type{Name: ddl.JSON}, map[string]int{"a": 1}, `{"a":1}`},
+		{"map with uuid keys", "map<uuid,decimal>", ddl.Type{Name: ddl.JSON}, map[gocql.UUID]*inf.Dec{id: dec}, `{"8e2c5b8a-1b4c-11ef-9262-0242ac120002":123.45}`},
+		{"udt", "udt", ddl.Type{Name: ddl.String}, map[string]interface{}{"street": "Main", "zip": 12345}, `{"street":"Main","zip":12345}`},
+		{"tuple", "tuple", ddl.Type{Name: ddl.String}, []interface{}{1, "x"}, `[1,"x"]`},
+	}
+	for _, tc := range tests {
+		conv := buildConv(
+			ddl.CreateTable{Name: "t", Id: "t1", ColIds: []string{"c1"}, ColDefs: map[string]ddl.ColumnDef{"c1": {Name: "col", Id: "c1", T: tc.spType}}},
+			schema.Table{Name: "t", Id: "t1", ColIds: []string{"c1"}, ColDefs: map[string]schema.Column{"c1": {Name: "col", Id: "c1", Type: schema.Type{Name: tc.srcType}}}})
+		table, cols, vals, err := ConvertData(conv, "t1", []string{"c1"}, conv.SrcSchema["t1"], conv.SpSchema["t1"], []interface{}{tc.in})
+		assert.Nil(t, err, tc.name)
+		assert.Equal(t, "t", table, tc.name)
+		assert.Equal(t, []string{"col"}, cols, tc.name)
+		assert.Equal(t, []interface{}{tc.e}, vals, tc.name)
+	}
+}
+
+func TestConvertData_PGNumeric(t *testing.T) {
+	conv := buildConv(
+		ddl.CreateTable{Name: "t", Id: "t1", ColIds: []string{"c1"}, ColDefs: map[string]ddl.ColumnDef{"c1": {Name: "col", Id: "c1", T: ddl.Type{Name: ddl.Numeric}}}},
+		schema.Table{Name: "t", Id: "t1", ColIds: []string{"c1"}, ColDefs: map[string]schema.Column{"c1": {Name: "col", Id: "c1", Type: schema.Type{Name: "decimal"}}}})
+	conv.SpDialect = constants.DIALECT_POSTGRESQL
+	_, _, vals, err := ConvertData(conv, "t1", []string{"c1"}, conv.SrcSchema["t1"], conv.SpSchema["t1"], []interface{}{inf.NewDec(5, 1)})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{spanner.PGNumeric{Numeric: "0.5", Valid: true}}, vals)
+}
+
+func TestConvertData_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		srcType string
+		spType  ddl.Type
+		in      interface{}
+	}{
+		{"varint overflow", "varint", ddl.Type{Name: ddl.Int64}, new(big.Int).Lsh(big.NewInt(1), 70)},
+		{"string to int", "int", ddl.Type{Name: ddl.Int64}, "12"},
+		{"bool from int", "int", ddl.Type{Name: ddl.Bool}, 1},
+		{"list to scalar", "list<int>", ddl.Type{Name: ddl.Int64}, []int{1}},
+	}
+	for _, tc := range tests {
+		conv := buildConv(
+			ddl.CreateTable{Name: "t", Id: "t1", ColIds: []string{"c1"}, ColDefs: map[string]ddl.ColumnDef{"c1": {Name: "col", Id: "c1", T: tc.spType}}},
+			schema.Table{Name: "t", Id: "t1", ColIds: []string{"c1"}, ColDefs: map[string]schema.Column{"c1": {Name: "col", Id: "c1", Type: schema.Type{Name: tc.srcType}}}})
+		_, _, _, err := ConvertData(conv, "t1", []string{"c1"}, conv.SrcSchema["t1"], conv.SpSchema["t1"], []interface{}{tc.in})
+		assert.NotNil(t, err, tc.name)
+	}
+}
+
+func TestVarintBytes(t *testing.T) {
+	tests := []struct {
+		in int64
+		e  []byte
+	}{
+		{0, []byte{0x00}},
+		{127, []byte{0x7f}},
+		{128, []byte{0x00, 0x80}},
+		{-1, []byte{0xff}},
+		{-128, []byte{0x80}},
+		{-129, []byte{0xff, 0x7f}},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.e, varintBytes(big.NewInt(tc.in)), tc.in)
+	}
+}
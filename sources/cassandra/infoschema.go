@@ -15,7 +15,10 @@
 package cassandra
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 
 	cc "github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/clients/cassandra"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
@@ -29,6 +32,7 @@ import (
 // InfoSchemaImpl is Cassandra specific implementation for InfoSchema
 type InfoSchemaImpl struct {
 	KeyspaceMetadata cc.KeyspaceMetadataInterface
+	Client           cc.CassandraClusterInterface
 	SourceProfile    profiles.SourceProfile
 	TargetProfile    profiles.TargetProfile
 }
@@ -231,19 +235,137 @@ func (isi InfoSchemaImpl) GetIndexes(conv *internal.Conv, table common.SchemaAnd
 	return indexes, nil
 }
 
-// Data Migration Related Methods (Stubs for Schema Migration)
-
-var errNotSupported = fmt.Errorf("operation not supported")
-
+// GetRowsFromTable starts reading all rows of a table. The table is read
+// in parallel over the token ranges of the cluster, and the rows are
+// delivered on the returned *tableRows as they arrive.
 func (isi InfoSchemaImpl) GetRowsFromTable(conv *internal.Conv, tableId string) (interface{}, error) {
-	return nil, errNotSupported
+	if isi.Client == nil {
+		return nil, fmt.Errorf("cassandra client not initialized")
+	}
+	tbl := conv.SrcSchema[tableId]
+	partitionKeys, err := isi.getPartitionKeys(tbl.Name)
+	if err != nil {
+		return nil, err
+	}
+	ranges, err := getTokenRanges(isi.Client)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get token ranges: %w", err)
+	}
+	var cols []string
+	for _, colId := range tbl.ColIds {
+		cols = append(cols, quoteIdent(tbl.ColDefs[colId].Name))
+	}
+	q := fmt.Sprintf("SELECT %s FROM %s.%s", strings.Join(cols, ", "), quoteIdent(isi.SourceProfile.Conn.Cassandra.Keyspace), quoteIdent(tbl.Name))
+
+	rows := &tableRows{
+		rows: make(chan map[string]interface{}, common.DefaultWorkers),
+		errs: make(chan error, len(ranges)),
+	}
+	go func() {
+		errs := forEachTokenRange(ranges, common.DefaultWorkers, func(tr tokenRange) error {
+			stmt, args := tr.restrict(q, partitionKeys)
+			iter := isi.Client.Query(stmt, args...).Iter()
+			for {
+				row := make(map[string]interface{})
+				if !iter.MapScan(row) {
+					break
+				}
+				rows.rows <- row
+			}
+			return iter.Close()
+		})
+		close(rows.rows)
+		for _, err := range errs {
+			rows.errs <- err
+		}
+		close(rows.errs)
+	}()
+	return rows, nil
 }
 
+// GetRowCount returns the number of rows in a table, counted in parallel
+// over the token ranges of the cluster.
 func (isi InfoSchemaImpl) GetRowCount(table common.SchemaAndName) (int64, error) {
-	return 0, errNotSupported
+	if isi.Client == nil {
+		return 0, fmt.Errorf("cassandra client not initialized")
+	}
+	partitionKeys, err := isi.getPartitionKeys(table.Name)
+	if err != nil {
+		return 0, err
+	}
+	ranges, err := getTokenRanges(isi.Client)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't get token ranges: %w", err)
+	}
+	q := fmt.Sprintf("SELECT COUNT(*) FROM %s.%s", quoteIdent(isi.SourceProfile.Conn.Cassandra.Keyspace), quoteIdent(table.Name))
+	var mu sync.Mutex
+	var total int64
+	errs := forEachTokenRange(ranges, common.DefaultWorkers, func(tr tokenRange) error {
+		stmt, args := tr.restrict(q, partitionKeys)
+		var count int64
+		if err := isi.Client.Query(stmt, args...).Scan(&count); err != nil {
+			return err
+		}
+		mu.Lock()
+		total += count
+		mu.Unlock()
+		return nil
+	})
+	if len(errs) > 0 {
+		return 0, errs[0]
+	}
+	return total, nil
 }
 
-func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSchema schema.Table, spCols []string, spSchema ddl.CreateTable, additionalAttributes internal.AdditionalDataAttributes) error {
-	return errNotSupported
+// ProcessData performs data conversion for a Cassandra table. Rows are
+// read concurrently over token ranges, but conversion and writes happen
+// on this goroutine since conv is not safe for concurrent use. The rows
+// of the token ranges that were read are written even if others fail, but
+// the failures are returned so that the table isn't reported as migrated.
+func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSchema schema.Table, commonColIds []string, spSchema ddl.CreateTable, additionalAttributes internal.AdditionalDataAttributes) error {
+	srcTableName := conv.SrcSchema[tableId].Name
+	rowsInterface, err := isi.GetRowsFromTable(conv, tableId)
+	if err != nil {
+		conv.Unexpected(fmt.Sprintf("Couldn't get data for table %s : err = %s", srcTableName, err))
+		return err
+	}
+	rows := rowsInterface.(*tableRows)
+	for row := range rows.rows {
+		vals := make([]interface{}, len(commonColIds))
+		for i, colId := range commonColIds {
+			vals[i] = row[srcSchema.ColDefs[colId].Name]
+		}
+		ProcessDataRow(conv, tableId, commonColIds, srcSchema, spSchema, vals)
+	}
+	var errs []error
+	for err := range rows.errs {
+		conv.Unexpected(fmt.Sprintf("Couldn't read token range of table %s : err = %s", srcTableName, err))
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("couldn't read %d token ranges of table %s: %w", len(errs), srcTableName, errors.Join(errs...))
+	}
+	return nil
 }
 
+// getPartitionKeys returns the partition key columns of a table in
+// the order used by the TOKEN function.
+func (isi InfoSchemaImpl) getPartitionKeys(tableName string) ([]string, error) {
+	tableMetadata, ok := isi.getTableMetadata(tableName)
+	if !ok {
+		return nil, fmt.Errorf("table '%s' not found in keyspace metadata", tableName)
+	}
+	var partitionKeys []string
+	for _, col := range tableMetadata.PartitionKey {
+		partitionKeys = append(partitionKeys, col.Name)
+	}
+	if len(partitionKeys) == 0 {
+		return nil, fmt.Errorf("no partition keys found for table %s", tableName)
+	}
+	return partitionKeys, nil
+}
+
+// quoteIdent quotes a Cassandra identifier so that case is preserved.
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -14,6 +14,7 @@
 package cassandra
 
 import (
+	"errors"
 	"sort"
 	"testing"
 
@@ -25,6 +26,7 @@ import (
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
 	"github.com/gocql/gocql"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestGetToDdl(t *testing.T) {
@@ -393,18 +395,119 @@ func TestGetIndexes(t *testing.T) {
 	mockKeyspace.AssertExpectations(t)
 }
 
-func TestDataMigrationStubs(t *testing.T) {
-	isi := InfoSchemaImpl{}
-	conv := internal.MakeConv()
+// mockTokenRing sets up a single node ring that owns token 0, which
+// splits the ring into (-inf, 0) and [0, +inf).
+func mockTokenRing(client *cc.MockCassandraCluster) {
+	local := new(cc.MockQuery)
+	local.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).([]interface{})[0].(*[]string) = []string{"0"}
+	}).Return(nil)
+	peersIter := new(cc.MockIter)
+	peersIter.On("Scan", mock.Anything).Return(false)
+	peersIter.On("Close").Return(nil)
+	peers := new(cc.MockQuery)
+	peers.On("Iter").Return(peersIter)
+	client.On("Query", "SELECT tokens FROM system.local", []interface{}(nil)).Return(local)
+	client.On("Query", "SELECT tokens FROM system.peers_v2", []interface{}(nil)).Return(peers)
+}
 
-	_, err := isi.GetRowsFromTable(conv, "table1")
-	assert.ErrorIs(t, err, errNotSupported)
+func TestGetRowCount(t *testing.T) {
+	mockKeyspace := &cc.MockKeyspaceMetadata{}
+	mockKeyspace.On("Tables").Return(map[string]*gocql.TableMetadata{
+		"users": {Name: "users", PartitionKey: []*gocql.ColumnMetadata{{Name: "id"}}},
+	})
+	client := new(cc.MockCassandraCluster)
+	mockTokenRing(client)
+	for stmt, count := range map[string]int64{
+		`SELECT COUNT(*) FROM "ks"."users" WHERE TOKEN("id") < ?`:  3,
+		`SELECT COUNT(*) FROM "ks"."users" WHERE TOKEN("id") >= ?`: 4,
+	} {
+		n := count
+		q := new(cc.MockQuery)
+		q.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).([]interface{})[0].(*int64) = n
+		}).Return(nil)
+		client.On("Query", stmt, []interface{}{"0"}).Return(q)
+	}
+	isi := InfoSchemaImpl{
+		KeyspaceMetadata: mockKeyspace,
+		Client:           client,
+		SourceProfile:    profiles.SourceProfile{Conn: profiles.SourceProfileConnection{Cassandra: profiles.SourceProfileConnectionCassandra{Keyspace: "ks"}}},
+	}
+	count, err := isi.GetRowCount(common.SchemaAndName{Name: "users"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), count)
 
-	_, err = isi.GetRowCount(common.SchemaAndName{Name: "table1"})
-	assert.ErrorIs(t, err, errNotSupported)
+	_, err = InfoSchemaImpl{}.GetRowCount(common.SchemaAndName{Name: "users"})
+	assert.EqualError(t, err, "cassandra client not initialized")
+}
 
-	err = isi.ProcessData(conv, "table1", schema.Table{}, nil, ddl.CreateTable{}, internal.AdditionalDataAttributes{})
-	assert.ErrorIs(t, err, errNotSupported)
+func TestProcessData(t *testing.T) {
+	mockKeyspace := &cc.MockKeyspaceMetadata{}
+	mockKeyspace.On("Tables").Return(map[string]*gocql.TableMetadata{
+		"users": {Name: "users", PartitionKey: []*gocql.ColumnMetadata{{Name: "id"}}},
+	})
+	client := new(cc.MockCassandraCluster)
+	mockTokenRing(client)
+	for stmt, rows := range map[string][]map[string]interface{}{
+		`SELECT "id", "name" FROM "ks"."users" WHERE TOKEN("id") < ?`:  {{"id": 1, "name": "a"}},
+		`SELECT "id", "name" FROM "ks"."users" WHERE TOKEN("id") >= ?`: {{"id": 2, "name": nil}},
+	} {
+		iter := &cc.MockIter{Rows: rows}
+		iter.On("Close").Return(nil)
+		q := new(cc.MockQuery)
+		q.On("Iter").Return(iter)
+		client.On("Query", stmt, []interface{}{"0"}).Return(q)
+	}
 
+	colIds := []string{"c1", "c2"}
+	srcTable := schema.Table{Name: "users", Schema: "ks", Id: "t1", ColIds: colIds, ColDefs: map[string]schema.Column{
+		"c1": {Name: "id", Id: "c1", Type: schema.Type{Name: "int"}},
+		"c2": {Name: "name", Id: "c2", Type: schema.Type{Name: "text"}},
+	}}
+	spTable := ddl.CreateTable{Name: "users", Id: "t1", ColIds: colIds, ColDefs: map[string]ddl.ColumnDef{
+		"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}},
+		"c2": {Name: "name", Id: "c2", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+	}}
+	conv := internal.MakeConv()
+	conv.SrcSchema["t1"] = srcTable
+	conv.SpSchema["t1"] = spTable
+	conv.SetDataMode()
+	var rows []spannerData
+	conv.SetDataSink(func(table string, cols []string, vals []interface{}) {
+		rows = append(rows, spannerData{table: table, cols: cols, vals: vals})
+	})
 
+	isi := InfoSchemaImpl{
+		KeyspaceMetadata: mockKeyspace,
+		Client:           client,
+		SourceProfile:    profiles.SourceProfile{Conn: profiles.SourceProfileConnection{Cassandra: profiles.SourceProfileConnectionCassandra{Keyspace: "ks"}}},
+	}
+	err := isi.ProcessData(conv, "t1", srcTable, colIds, spTable, internal.AdditionalDataAttributes{})
+	assert.NoError(t, err)
+	sort.Slice(rows, func(i, j int) bool { return rows[i].vals[0].(int64) < rows[j].vals[0].(int64) })
+	assert.Equal(t, []spannerData{
+		{table: "users", cols: []string{"id", "name"}, vals: []interface{}{int64(1), "a"}},
+		{table: "users", cols: []string{"id"}, vals: []interface{}{int64(2)}},
+	}, rows)
+
+	err = InfoSchemaImpl{}.ProcessData(conv, "t1", srcTable, colIds, spTable, internal.AdditionalDataAttributes{})
+	assert.EqualError(t, err, "cassandra client not initialized")
+
+	// A token range that can't be read fails the table.
+	client = new(cc.MockCassandraCluster)
+	mockTokenRing(client)
+	for stmt, closeErr := range map[string]error{
+		`SELECT "id", "name" FROM "ks"."users" WHERE TOKEN("id") < ?`:  nil,
+		`SELECT "id", "name" FROM "ks"."users" WHERE TOKEN("id") >= ?`: errors.New("read timeout"),
+	} {
+		iter := &cc.MockIter{Rows: []map[string]interface{}{{"id": 1, "name": "a"}}}
+		iter.On("Close").Return(closeErr)
+		q := new(cc.MockQuery)
+		q.On("Iter").Return(iter)
+		client.On("Query", stmt, []interface{}{"0"}).Return(q)
+	}
+	isi.Client = client
+	err = isi.ProcessData(conv, "t1", srcTable, colIds, spTable, internal.AdditionalDataAttributes{})
+	assert.EqualError(t, err, "couldn't read 1 token ranges of table users: token range [0, ): read timeout")
 }
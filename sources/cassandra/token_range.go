@@ -0,0 +1,148 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassandra
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	cc "github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/clients/cassandra"
+)
+
+// tokenRange is a [Start, End) range of the token ring. An empty Start
+// or End means the range is unbounded on that side.
+type tokenRange struct {
+	Start string
+	End   string
+}
+
+// tableRows is returned by GetRowsFromTable. rows is closed once all
+// token ranges have been read; errs then receives one error per token
+// range that failed and is closed as well.
+type tableRows struct {
+	rows chan map[string]interface{}
+	errs chan error
+}
+
+// restrict adds the TOKEN() filter for tr to query q and returns the
+// statement along with its bind values.
+func (tr tokenRange) restrict(q string, partitionKeys []string) (string, []interface{}) {
+	var quoted []string
+	for _, k := range partitionKeys {
+		quoted = append(quoted, quoteIdent(k))
+	}
+	token := fmt.Sprintf("TOKEN(%s)", strings.Join(quoted, ", "))
+	var where []string
+	var args []interface{}
+	if tr.Start != "" {
+		where = append(where, token+" >= ?")
+		args = append(args, tr.Start)
+	}
+	if tr.End != "" {
+		where = append(where, token+" < ?")
+		args = append(args, tr.End)
+	}
+	if len(where) > 0 {
+		q += " WHERE " + strings.Join(where, " AND ")
+	}
+	return q, args
+}
+
+// getTokenRanges builds token ranges covering the whole ring from the
+// tokens owned by the local node and its peers, in the same way as
+// validations/count.
+func getTokenRanges(client cc.CassandraClusterInterface) ([]tokenRange, error) {
+	var tokens []string
+	if err := client.Query("SELECT tokens FROM system.local").Scan(&tokens); err != nil {
+		return nil, fmt.Errorf("failed to get local node tokens: %w", err)
+	}
+	peerTokens, err := getPeerTokens(client, "system.peers_v2")
+	if err != nil {
+		// system.peers_v2 only exists from Cassandra 4.0 onwards.
+		peerTokens, err = getPeerTokens(client, "system.peers")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get peer node tokens: %w", err)
+		}
+	}
+	tokens = append(tokens, peerTokens...)
+
+	seen := make(map[string]bool)
+	var sorted []*big.Int
+	for _, t := range tokens {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		b, ok := new(big.Int).SetString(t, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid token string: %s", t)
+		}
+		sorted = append(sorted, b)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Cmp(sorted[j]) < 0
+	})
+
+	ranges := make([]tokenRange, len(sorted)+1)
+	for i, t := range sorted {
+		ranges[i].End = t.String()
+		ranges[i+1].Start = t.String()
+	}
+	return ranges, nil
+}
+
+func getPeerTokens(client cc.CassandraClusterInterface, peersTable string) ([]string, error) {
+	var all, tokens []string
+	iter := client.Query(fmt.Sprintf("SELECT tokens FROM %s", peersTable)).Iter()
+	for iter.Scan(&tokens) {
+		all = append(all, tokens...)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// forEachTokenRange calls f for every token range using a pool of
+// workers, and returns the errors of the ranges that failed.
+func forEachTokenRange(ranges []tokenRange, workers int, f func(tokenRange) error) []error {
+	work := make(chan tokenRange, len(ranges))
+	for _, tr := range ranges {
+		work <- tr
+	}
+	close(work)
+
+	var mu sync.Mutex
+	var errs []error
+	var wg sync.WaitGroup
+	for i := 0; i < workers && i < len(ranges); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for tr := range work {
+				if err := f(tr); err != nil {
+					mu.Lock()
+					errs = append(errs, fmt.Errorf("token range [%s, %s): %w", tr.Start, tr.End, err))
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return errs
+}
@@ -0,0 +1,262 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"fmt"
+
+	sp "cloud.google.com/go/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+)
+
+// maxMutationsPerApply bounds the number of mutations written to Spanner
+// at once. Larger source transactions are applied in several Spanner
+// transactions, in order.
+const maxMutationsPerApply = 1000
+
+// ConvertRowFunc converts the values of columns colIds of a row of table
+// tableId, as the ConvertData function of a source does.
+type ConvertRowFunc func(tableId string, colIds []string, vals []string) (spTable string, cols []string, spVals []interface{}, err error)
+
+// ApplyFunc writes mutations to Spanner atomically.
+type ApplyFunc func(ctx context.Context, ms []*sp.Mutation) error
+
+// ApplyWithClient returns an ApplyFunc writing with client, or nil if
+// client is nil (in dry-run mode).
+func ApplyWithClient(client *sp.Client) ApplyFunc {
+	if client == nil {
+		return nil
+	}
+	return func(ctx context.Context, ms []*sp.Mutation) error {
+		_, err := client.Apply(ctx, ms)
+		return err
+	}
+}
+
+// ChangeWriter writes the row changes captured from a source database
+// (inserts, updates and deletes) to Spanner, for change data capture.
+// Rows are mapped to Spanner tables with conv.ToSpanner and converted with
+// the ConvertData function of the source, like the rows of a bulk
+// migration. Inserts and updates are written as InsertOrUpdate mutations
+// and deletes as Delete mutations. Mutations are buffered until Commit, so
+// that the changes of a source transaction are applied together.
+//
+// Rows that can't be converted are reported as bad rows of conv.
+type ChangeWriter struct {
+	conv    *internal.Conv
+	convert ConvertRowFunc
+	null    string
+	apply   ApplyFunc
+	pending []*sp.Mutation
+	// deleting is set while a delete is sent to the data sink.
+	deleting bool
+}
+
+// NewChangeWriter returns a ChangeWriter for conv, which converts rows
+// with convert and writes them with apply (nil in dry-run mode). null is
+// the string that represents NULL values in rows passed to convert. It
+// sets the data sink of conv.
+func NewChangeWriter(conv *internal.Conv, convert ConvertRowFunc, null string, apply ApplyFunc) *ChangeWriter {
+	cw := &ChangeWriter{conv: conv, convert: convert, null: null, apply: apply}
+	conv.SetDataSink(cw.sink)
+	return cw
+}
+
+// Migrated returns the id of source table srcTable, and whether its
+// changes are migrated.
+func (cw *ChangeWriter) Migrated(srcTable string) (string, bool) {
+	if _, ok := cw.conv.ToSpanner[srcTable]; !ok {
+		return "", false
+	}
+	tableId, err := internal.GetTableIdFromSrcName(cw.conv.SrcSchema, srcTable)
+	return tableId, err == nil
+}
+
+// Insert writes a row inserted in source table srcTable, given by the
+// values vals of its columns srcCols.
+func (cw *ChangeWriter) Insert(srcTable string, srcCols, vals []string) {
+	cw.conv.StatsAddRow(srcTable, cw.conv.DataMode())
+	cw.check(srcTable, srcCols, vals, cw.upsert(srcTable, srcCols, vals))
+}
+
+// Update writes a row updated in source table srcTable. oldCols and
+// oldVals give the values before the update of at least its primary key,
+// and can be nil if the primary key hasn't changed. Columns left out of
+// srcCols are not updated.
+func (cw *ChangeWriter) Update(srcTable string, oldCols, oldVals, srcCols, vals []string) {
+	cw.conv.StatsAddRow(srcTable, cw.conv.DataMode())
+	cw.check(srcTable, srcCols, vals, cw.update(srcTable, oldCols, oldVals, srcCols, vals))
+}
+
+// Delete deletes a row of source table srcTable, given by the values
+// vals of columns srcCols that include its primary key.
+func (cw *ChangeWriter) Delete(srcTable string, srcCols, vals []string) {
+	cw.conv.StatsAddRow(srcTable, cw.conv.DataMode())
+	cw.check(srcTable, srcCols, vals, cw.delete(srcTable, srcCols, vals))
+}
+
+// Commit applies the changes written since the last call to Commit.
+func (cw *ChangeWriter) Commit(ctx context.Context) error {
+	if cw.apply != nil {
+		for len(cw.pending) > 0 {
+			n := min(len(cw.pending), maxMutationsPerApply)
+			if err := cw.apply(ctx, cw.pending[:n]); err != nil {
+				return err
+			}
+			cw.pending = cw.pending[n:]
+		}
+	}
+	cw.pending = nil
+	return nil
+}
+
+func (cw *ChangeWriter) check(srcTable string, srcCols, vals []string, err error) {
+	if err != nil {
+		cw.conv.Unexpected(fmt.Sprintf("Error while converting change to table %s: %s", srcTable, err))
+		cw.conv.StatsAddBadRow(srcTable, cw.conv.DataMode())
+		cw.conv.CollectBadRow(srcTable, srcCols, vals, err)
+	}
+}
+
+// convertRow converts a row, but also returns the Spanner columns that are
+// NULL in the row, which ConvertData functions leave out.
+func (cw *ChangeWriter) convertRow(tableId string, srcCols, vals []string) (spTable string, cols []string, spVals []interface{}, nullCols []string, err error) {
+	srcSchema, spSchema := cw.conv.SrcSchema[tableId], cw.conv.SpSchema[tableId]
+	colNameIdMap := internal.GetSrcColNameIdMap(srcSchema)
+	var srcColIds []string
+	for _, name := range srcCols {
+		colId, ok := colNameIdMap[name]
+		if !ok {
+			return "", nil, nil, nil, fmt.Errorf("column id not found for source-db column %s", name)
+		}
+		srcColIds = append(srcColIds, colId)
+	}
+	colIds := IntersectionOfTwoStringSlices(spSchema.ColIds, srcColIds)
+	v, err := PrepareValues(cw.conv, tableId, colNameIdMap, colIds, srcCols, vals)
+	if err != nil {
+		return "", nil, nil, nil, err
+	}
+	spTable, cols, spVals, err = cw.convert(tableId, colIds, v)
+	if err != nil {
+		return "", nil, nil, nil, err
+	}
+	for i, colId := range colIds {
+		if v[i] == cw.null {
+			nullCols = append(nullCols, spSchema.ColDefs[colId].Name)
+		}
+	}
+	return spTable, cols, spVals, nullCols, nil
+}
+
+// key returns the Spanner primary key of a converted row of table tableId.
+func (cw *ChangeWriter) key(tableId string, cols []string, vals []interface{}) (sp.Key, error) {
+	if _, ok := cw.conv.SyntheticPKeys[tableId]; ok {
+		return nil, fmt.Errorf("can't update or delete rows of table %s, which has no primary key", cw.conv.SrcSchema[tableId].Name)
+	}
+	spSchema := cw.conv.SpSchema[tableId]
+	var key sp.Key
+	for _, pk := range spSchema.PrimaryKeys {
+		name := spSchema.ColDefs[pk.ColId].Name
+		found := false
+		for i, col := range cols {
+			if col == name {
+				key = append(key, vals[i])
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("primary key column %s is missing from the change", name)
+		}
+	}
+	return key, nil
+}
+
+func (cw *ChangeWriter) upsert(srcTable string, srcCols, vals []string) error {
+	tableId, err := internal.GetTableIdFromSrcName(cw.conv.SrcSchema, srcTable)
+	if err != nil {
+		return err
+	}
+	spTable, cols, spVals, nullCols, err := cw.convertRow(tableId, srcCols, vals)
+	if err != nil {
+		return err
+	}
+	for _, col := range nullCols {
+		cols = append(cols, col)
+		spVals = append(spVals, nil)
+	}
+	cw.conv.WriteRow(srcTable, spTable, cols, spVals)
+	return nil
+}
+
+func (cw *ChangeWriter) update(srcTable string, oldCols, oldVals, srcCols, vals []string) error {
+	tableId, err := internal.GetTableIdFromSrcName(cw.conv.SrcSchema, srcTable)
+	if err != nil {
+		return err
+	}
+	if _, ok := cw.conv.SyntheticPKeys[tableId]; ok {
+		return fmt.Errorf("can't update rows of table %s, which has no primary key", srcTable)
+	}
+	if oldCols != nil {
+		spTable, cols, spVals, _, err := cw.convertRow(tableId, oldCols, oldVals)
+		if err != nil {
+			return err
+		}
+		oldKey, err := cw.key(tableId, cols, spVals)
+		if err != nil {
+			return err
+		}
+		_, cols, spVals, _, err = cw.convertRow(tableId, srcCols, vals)
+		if err != nil {
+			return err
+		}
+		// A key missing from the new values hasn't changed.
+		newKey, err := cw.key(tableId, cols, spVals)
+		if err == nil && newKey.String() != oldKey.String() {
+			cw.pending = append(cw.pending, sp.Delete(spTable, oldKey))
+		}
+	}
+	return cw.upsert(srcTable, srcCols, vals)
+}
+
+func (cw *ChangeWriter) delete(srcTable string, srcCols, vals []string) error {
+	tableId, err := internal.GetTableIdFromSrcName(cw.conv.SrcSchema, srcTable)
+	if err != nil {
+		return err
+	}
+	spTable, cols, spVals, _, err := cw.convertRow(tableId, srcCols, vals)
+	if err != nil {
+		return err
+	}
+	key, err := cw.key(tableId, cols, spVals)
+	if err != nil {
+		return err
+	}
+	cw.deleting = true
+	cw.conv.WriteRow(srcTable, spTable, cols, key)
+	cw.deleting = false
+	return nil
+}
+
+// sink is the data sink of conv: it adds a mutation to the current
+// transaction.
+func (cw *ChangeWriter) sink(table string, cols []string, vals []interface{}) {
+	if cw.deleting {
+		cw.pending = append(cw.pending, sp.Delete(table, sp.Key(vals)))
+		return
+	}
+	cw.pending = append(cw.pending, sp.InsertOrUpdate(table, cols, vals))
+}
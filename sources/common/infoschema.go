@@ -88,6 +88,27 @@ type BatchedInfoSchema interface {
 	GetIndexesBatch(conv *internal.Conv, tables []SchemaAndName, colDefs map[string]TableColumns) (map[string][]schema.Index, error)
 }
 
+// RowFilterInfoSchema is implemented by sources that can restrict the
+// rows they read from a table to those satisfying a predicate (see
+// internal.TableSelection). Their GetRowsFromTable and ProcessData only
+// return the rows satisfying conv.TableSelection.Predicate of the table.
+type RowFilterInfoSchema interface {
+	InfoSchema
+	// GetFilteredRowCount returns the number of rows of a table that
+	// satisfy where, or of all its rows if where is "".
+	GetFilteredRowCount(table SchemaAndName, where string) (int64, error)
+}
+
+// ViewInfoSchema is implemented by sources that can list the views of the
+// source database. Views are converted after the tables they read from (see
+// ViewsToSpanner).
+type ViewInfoSchema interface {
+	InfoSchema
+	// GetViews returns the views of the source database, named like the
+	// tables of the source (see GetTableName).
+	GetViews(conv *internal.Conv) ([]schema.View, error)
+}
+
 // SchemaAndName contains the schema and name for a table
 type SchemaAndName struct {
 	Schema string
@@ -107,7 +128,7 @@ type FkConstraint struct {
 
 type InfoSchemaInterface interface {
 	GenerateSrcSchema(conv *internal.Conv, infoSchema InfoSchema, numWorkers int) (int, error)
-	ProcessData(conv *internal.Conv, infoSchema InfoSchema, additionalAttributes internal.AdditionalDataAttributes)
+	ProcessData(conv *internal.Conv, infoSchema InfoSchema, additionalAttributes internal.AdditionalDataAttributes) error
 	SetRowStats(conv *internal.Conv, infoSchema InfoSchema)
 	ProcessTable(conv *internal.Conv, table SchemaAndName, infoSchema StandardInfoSchema) (schema.Table, error)
 	GetIncludedSrcTablesFromConv(conv *internal.Conv) (schemaToTablesMap map[string]internal.SchemaDetails, err error)
@@ -150,6 +171,17 @@ func (is *InfoSchemaImpl) GenerateSrcSchema(conv *internal.Conv, infoSchema Info
 		return 0, err
 	}
 
+	if conv.TableSelection != nil {
+		var selected []SchemaAndName
+		for _, t := range tables {
+			if conv.TableSelection.Selected(infoSchema.GetTableName(t.Schema, t.Name)) {
+				selected = append(selected, t)
+			}
+		}
+		logger.Log.Info(fmt.Sprintf("selected %d of %d tables", len(selected), len(tables)))
+		tables = selected
+	}
+
 	if numWorkers < 1 {
 		numWorkers = DefaultWorkers
 	}
@@ -170,9 +202,40 @@ func (is *InfoSchemaImpl) GenerateSrcSchema(conv *internal.Conv, infoSchema Info
 	}
 
 	internal.ResolveForeignKeyIds(conv.SrcSchema)
+	if conv.TableSelection != nil {
+		dropUnselectedForeignKeys(conv)
+	}
+	if vis, ok := infoSchema.(ViewInfoSchema); ok {
+		views, err := vis.GetViews(conv)
+		if err != nil {
+			// Views are optional: the tables are converted even if the
+			// views can't be read.
+			logger.Log.Warn(fmt.Sprintf("Couldn't get views: %v", err))
+		}
+		for _, view := range views {
+			AddSrcView(conv, view)
+		}
+	}
 	return tableCount, nil
 }
 
+// dropUnselectedForeignKeys removes the foreign keys that reference
+// tables left out by conv.TableSelection, since they can't be created.
+func dropUnselectedForeignKeys(conv *internal.Conv) {
+	for id, t := range conv.SrcSchema {
+		var fks []schema.ForeignKey
+		for _, fk := range t.ForeignKeys {
+			if fk.ReferTableId == "" && !conv.TableSelection.Selected(fk.ReferTableName) {
+				logger.Log.Info(fmt.Sprintf("Dropping foreign key %s of table %s: table %s is not migrated", fk.Name, t.Name, fk.ReferTableName))
+				continue
+			}
+			fks = append(fks, fk)
+		}
+		t.ForeignKeys = fks
+		conv.SrcSchema[id] = t
+	}
+}
+
 func (is *InfoSchemaImpl) generateSrcSchemaBatched(conv *internal.Conv, bis BatchedInfoSchema, tables []SchemaAndName, numWorkers int) (int, error) {
 	batchSize := 50
 	var batches [][]SchemaAndName
@@ -247,12 +310,19 @@ func (is *InfoSchemaImpl) generateSrcSchemaStandard(conv *internal.Conv, sis Sta
 // ProcessData performs data conversion for source database
 // 'db'. For each table, we extract and convert the data to Spanner data
 // (based on the source and Spanner schemas), and write it to Spanner.
-// If we can't get/process data for a table, we skip that table and process
-// the remaining tables.
-func (is *InfoSchemaImpl) ProcessData(conv *internal.Conv, infoSchema InfoSchema, additionalAttributes internal.AdditionalDataAttributes) {
+// If we can't get/process data for a table, we stop and return the error,
+// so that a partially copied database isn't reported as migrated.
+func (is *InfoSchemaImpl) ProcessData(conv *internal.Conv, infoSchema InfoSchema, additionalAttributes internal.AdditionalDataAttributes) error {
+	if _, ok := infoSchema.(KeyRangeInfoSchema); ok && (conv.BulkRead.ParallelTables > 1 || conv.BulkRead.ChunksPerTable > 1 || conv.Checkpoint != nil) {
+		return is.processDataInParallel(conv, infoSchema, additionalAttributes)
+	}
 	// Tables are ordered in alphabetical order with one exception: interleaved
 	// tables appear after the population of their parent table.
 	tableIds := ddl.GetSortedTableIdsBySpName(conv.SpSchema)
+	if conv.Sampler != nil {
+		// Rows of a sample are only kept once the rows they reference are.
+		tableIds = conv.Sampler.SortTableIds(tableIds)
+	}
 
 	for _, tableId := range tableIds {
 		srcSchema := conv.SrcSchema[tableId]
@@ -263,17 +333,27 @@ func (is *InfoSchemaImpl) ProcessData(conv *internal.Conv, infoSchema InfoSchema
 				srcSchema.Name, ok))
 			continue
 		}
+		if !conv.TableSelection.Selected(srcSchema.Name) || skipDoneTable(conv, srcSchema.Name, spSchema.Name) {
+			continue
+		}
+		if conv.Checkpoint != nil {
+			conv.Checkpoint.StartTable(spSchema.Name, "", nil)
+		}
 		// Extract common spColds. We get column ids common to both source and
 		// spanner table so that we can read these records from source
 		colIds := GetCommonColumnIds(conv, tableId, spSchema.ColIds)
 		err := infoSchema.ProcessData(conv, tableId, srcSchema, colIds, spSchema, additionalAttributes)
 		if err != nil {
-			return
+			return fmt.Errorf("can't process data of table %s: %w", srcSchema.Name, err)
 		}
 		if conv.DataFlush != nil {
 			conv.DataFlush()
 		}
+		if conv.Checkpoint != nil {
+			conv.Checkpoint.TableDone(spSchema.Name)
+		}
 	}
+	return nil
 }
 
 // SetRowStats populates conv with the number of rows in each table.
@@ -285,7 +365,15 @@ func (is *InfoSchemaImpl) SetRowStats(conv *internal.Conv, infoSchema InfoSchema
 	}
 	for _, t := range tables {
 		tableName := infoSchema.GetTableName(t.Schema, t.Name)
-		count, err := infoSchema.GetRowCount(t)
+		if !conv.TableSelection.Selected(tableName) {
+			continue
+		}
+		var count int64
+		if rfis, ok := infoSchema.(RowFilterInfoSchema); ok {
+			count, err = rfis.GetFilteredRowCount(t, conv.TableSelection.Predicate(tableName))
+		} else {
+			count, err = infoSchema.GetRowCount(t)
+		}
 		if err != nil {
 			conv.Unexpected(fmt.Sprintf("Couldn't get number of rows for table %s", tableName))
 			continue
@@ -32,7 +32,8 @@ func (mis *MockInfoSchema) GenerateSrcSchema(conv *internal.Conv, infoSchema Inf
 	args := mis.Called(conv, infoSchema, numWorkers)
 	return args.Get(0).(int), args.Error(1)
 }
-func (mis *MockInfoSchema) ProcessData(conv *internal.Conv, infoSchema InfoSchema, additionalAttributes internal.AdditionalDataAttributes) {
+func (mis *MockInfoSchema) ProcessData(conv *internal.Conv, infoSchema InfoSchema, additionalAttributes internal.AdditionalDataAttributes) error {
+	return nil
 }
 func (mis *MockInfoSchema) SetRowStats(conv *internal.Conv, infoSchema InfoSchema) {}
 func (mis *MockInfoSchema) ProcessTable(conv *internal.Conv, table SchemaAndName, infoSchema StandardInfoSchema) (schema.Table, error) {
@@ -0,0 +1,404 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/task"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// KeyRangeInfoSchema is implemented by sources that can read a table in
+// ranges of its primary key (see internal.AdditionalDataAttributes), so
+// that a table can be read by several concurrent queries.
+//
+// Only sources implementing it are read in parallel. They must hold
+// conv.ConvLock while they update conv in ProcessData, since ProcessData
+// is then called concurrently, both for different tables and for
+// different key ranges of a table.
+type KeyRangeInfoSchema interface {
+	InfoSchema
+	// GetKeyBounds returns the smallest and largest values of the integer
+	// column colId of a table. ok is false if the table is empty.
+	GetKeyBounds(conv *internal.Conv, tableId string, colId string) (min int64, max int64, ok bool, err error)
+}
+
+// processDataInParallel is ProcessData for KeyRangeInfoSchema sources
+// when conv.BulkRead asks for parallel reads or the migration is
+// checkpointed. Up to ParallelTables tables
+// are processed at a time, each split into up to ChunksPerTable key
+// ranges. An interleaved table is only started once its parent has been
+// written and, when sampling, a table is only started once the tables it
+// references have been.
+func (is *InfoSchemaImpl) processDataInParallel(conv *internal.Conv, infoSchema InfoSchema, additionalAttributes internal.AdditionalDataAttributes) error {
+	tableIds := ddl.GetSortedTableIdsBySpName(conv.SpSchema)
+	if conv.Sampler != nil {
+		tableIds = conv.Sampler.SortTableIds(tableIds)
+	}
+	position := make(map[string]int)
+	for i, tableId := range tableIds {
+		position[tableId] = i
+	}
+	workers := conv.BulkRead.ParallelTables
+	if workers < 1 {
+		workers = 1
+	}
+
+	done := make(map[string]chan struct{})
+	for _, tableId := range tableIds {
+		done[tableId] = make(chan struct{})
+	}
+	sem := make(chan struct{}, workers)
+	var failed atomic.Bool
+	var firstErr error
+	var errOnce sync.Once
+	var wg sync.WaitGroup
+	for _, tableId := range tableIds {
+		wg.Add(1)
+		go func(tableId string) {
+			defer wg.Done()
+			defer close(done[tableId])
+			if parentDone, ok := done[conv.SpSchema[tableId].ParentTable.Id]; ok {
+				<-parentDone
+			}
+			if conv.Sampler != nil {
+				for _, dep := range conv.Sampler.DependsOn(tableId) {
+					// Tables in a cycle of foreign keys only wait for
+					// those before them, so that they can't deadlock.
+					if p, ok := position[dep]; ok && p < position[tableId] {
+						<-done[dep]
+					}
+				}
+			}
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			// Like ProcessData, stop at the first table that fails.
+			if failed.Load() {
+				return
+			}
+			if err := is.processTableInChunks(conv, infoSchema, tableId, additionalAttributes); err != nil {
+				failed.Store(true)
+				errOnce.Do(func() {
+					firstErr = fmt.Errorf("can't process data of table %s: %w", conv.SrcSchema[tableId].Name, err)
+				})
+			}
+		}(tableId)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// processTableInChunks reads a table over its key ranges concurrently and
+// flushes the rows written once all ranges are done.
+func (is *InfoSchemaImpl) processTableInChunks(conv *internal.Conv, infoSchema InfoSchema, tableId string, additionalAttributes internal.AdditionalDataAttributes) error {
+	srcSchema := conv.SrcSchema[tableId]
+	spSchema, ok := conv.SpSchema[tableId]
+	if !ok {
+		conv.ConvLock.Lock()
+		conv.Stats.BadRows[srcSchema.Name] += conv.Stats.Rows[srcSchema.Name]
+		conv.Unexpected(fmt.Sprintf("Can't get cols and schemas for table %s:ok=%t",
+			srcSchema.Name, ok))
+		conv.ConvLock.Unlock()
+		return nil
+	}
+	if !conv.TableSelection.Selected(srcSchema.Name) || skipDoneTable(conv, srcSchema.Name, spSchema.Name) {
+		return nil
+	}
+	colIds := GetCommonColumnIds(conv, tableId, spSchema.ColIds)
+	ranges := getKeyRanges(conv, infoSchema, tableId, conv.BulkRead.ChunksPerTable)
+	if conv.Checkpoint != nil {
+		ranges = resumeKeyRanges(conv, tableId, ranges)
+	}
+	logger.Log.Debug(fmt.Sprintf("reading table %s in %d key ranges", srcSchema.Name, len(ranges)))
+
+	processRange := func(kr *internal.KeyRange, mutex *sync.Mutex) task.TaskResult[*internal.KeyRange] {
+		attributes := additionalAttributes
+		attributes.KeyRange = kr
+		err := infoSchema.ProcessData(conv, tableId, srcSchema, colIds, spSchema, attributes)
+		if err == nil && kr != nil && conv.Checkpoint != nil {
+			conv.Checkpoint.RangeFinished(spSchema.Name, kr.End)
+		}
+		return task.TaskResult[*internal.KeyRange]{Result: kr, Err: err}
+	}
+	if len(ranges) > 0 {
+		r := task.RunParallelTasksImpl[*internal.KeyRange, *internal.KeyRange]{}
+		if _, err := r.RunParallelTasks(ranges, len(ranges), processRange, true); err != nil {
+			return err
+		}
+	}
+	if conv.DataFlush != nil {
+		conv.ConvLock.Lock()
+		conv.DataFlush()
+		conv.ConvLock.Unlock()
+	}
+	if conv.Checkpoint != nil {
+		conv.Checkpoint.TableDone(spSchema.Name)
+	}
+	return nil
+}
+
+// skipDoneTable reports whether a checkpoint from a previous run records
+// that all rows of a table have been written, in which case the table is
+// counted as migrated without being read.
+func skipDoneTable(conv *internal.Conv, srcTable, spTable string) bool {
+	if conv.Checkpoint == nil {
+		return false
+	}
+	tcp := conv.Checkpoint.Table(spTable)
+	if !tcp.Done {
+		return false
+	}
+	logger.Log.Info(fmt.Sprintf("Skipping table %s: all its rows were written by a previous run", srcTable))
+	conv.ConvLock.Lock()
+	conv.Stats.GoodRows[srcTable] += tcp.Rows
+	conv.ConvLock.Unlock()
+	return true
+}
+
+// resumeKeyRanges records the key ranges a table is read in with
+// conv.Checkpoint, and returns the parts of them that remain to be read.
+// If a previous run recorded ranges for the table, they replace ranges.
+func resumeKeyRanges(conv *internal.Conv, tableId string, ranges []*internal.KeyRange) []*internal.KeyRange {
+	srcTable, spTable := conv.SrcSchema[tableId].Name, conv.SpSchema[tableId].Name
+	if len(ranges) == 0 || ranges[0] == nil {
+		// Tables that can't be read in ranges are read again from the start.
+		conv.Checkpoint.StartTable(spTable, "", nil)
+		return ranges
+	}
+	colId := ranges[0].ColId
+	conv.Checkpoint.StartTable(spTable, conv.SpSchema[tableId].ColDefs[colId].Name, ranges)
+	tcp := conv.Checkpoint.Table(spTable)
+	var remaining []*internal.KeyRange
+	for _, r := range tcp.Ranges {
+		if r.Done {
+			continue
+		}
+		kr := &internal.KeyRange{ColId: colId, Start: r.Start, End: r.End, Ordered: true}
+		if r.LastKey != nil {
+			if *r.LastKey == math.MaxInt64 {
+				conv.Checkpoint.RangeFinished(spTable, r.End)
+				continue
+			}
+			next := *r.LastKey + 1
+			kr.Start = &next
+		}
+		remaining = append(remaining, kr)
+	}
+	if tcp.Rows > 0 {
+		logger.Log.Info(fmt.Sprintf("Resuming table %s: %d rows were written by a previous run", srcTable, tcp.Rows))
+		conv.ConvLock.Lock()
+		conv.Stats.GoodRows[srcTable] += tcp.Rows
+		conv.ConvLock.Unlock()
+	}
+	return remaining
+}
+
+// getKeyRanges returns the key ranges to read a table in. The table is
+// split on its leading primary key column when that column is an
+// integer and the source supports it; otherwise the whole table is read
+// as a single range, represented by nil. When the migration is
+// checkpointed, ranges are read in key order.
+func getKeyRanges(conv *internal.Conv, infoSchema InfoSchema, tableId string, n int) []*internal.KeyRange {
+	whole := []*internal.KeyRange{nil}
+	kis, ok := infoSchema.(KeyRangeInfoSchema)
+	srcSchema := conv.SrcSchema[tableId]
+	if (n <= 1 && conv.Checkpoint == nil) || !ok || len(srcSchema.PrimaryKeys) == 0 {
+		return whole
+	}
+	colId := srcSchema.PrimaryKeys[0].ColId
+	spCol, ok := conv.SpSchema[tableId].ColDefs[colId]
+	if !ok || spCol.T.Name != ddl.Int64 || spCol.T.IsArray {
+		return whole
+	}
+	if n <= 1 {
+		// A checkpointed table is read as a single ordered range, so that
+		// progress within it can be recorded.
+		return []*internal.KeyRange{{ColId: colId, Ordered: true}}
+	}
+	min, max, ok, err := kis.GetKeyBounds(conv, tableId, colId)
+	if err != nil {
+		conv.ConvLock.Lock()
+		conv.Unexpected(fmt.Sprintf("Couldn't get key bounds for table %s, reading it with a single query: %s", srcSchema.Name, err))
+		conv.ConvLock.Unlock()
+		return whole
+	}
+	if !ok {
+		return whole
+	}
+	ranges := SplitKeyRange(colId, min, max, n)
+	if conv.Checkpoint != nil {
+		for i, kr := range ranges {
+			if kr == nil {
+				kr = &internal.KeyRange{ColId: colId}
+			}
+			kr.Ordered = true
+			ranges[i] = kr
+		}
+	}
+	return ranges
+}
+
+// SplitKeyRange splits the values [min, max] of column colId into at
+// most n ranges of about equal width. The first and last ranges are
+// unbounded, so rows outside [min, max] (e.g. inserted after the bounds
+// were read) are still covered.
+func SplitKeyRange(colId string, min, max int64, n int) []*internal.KeyRange {
+	if n <= 1 || max <= min {
+		return []*internal.KeyRange{nil}
+	}
+	// Unsigned arithmetic avoids overflow for ranges wider than MaxInt64.
+	width := uint64(max) - uint64(min)
+	step := width / uint64(n)
+	if step == 0 {
+		step = 1
+		n = int(width) + 1
+	}
+	ranges := make([]*internal.KeyRange, n)
+	var start *int64
+	for i := 0; i < n; i++ {
+		ranges[i] = &internal.KeyRange{ColId: colId, Start: start}
+		if i < n-1 {
+			end := int64(uint64(min) + uint64(i+1)*step)
+			ranges[i].End = &end
+			start = &end
+		}
+	}
+	return ranges
+}
+
+// KeyRangeClause returns the WHERE and ORDER BY clauses (with a leading
+// space) that restrict a query to the rows of kr, given the quoted name of
+// its column. It returns "" when kr is nil.
+func KeyRangeClause(kr *internal.KeyRange, quotedCol string) string {
+	return SelectClause("", kr, quotedCol)
+}
+
+// SelectClause is KeyRangeClause for a query that is also restricted to
+// the rows satisfying where, if it isn't "".
+func SelectClause(where string, kr *internal.KeyRange, quotedCol string) string {
+	var conds []string
+	if where != "" {
+		conds = append(conds, "("+where+")")
+	}
+	if pred := KeyRangePredicate(kr, quotedCol); pred != "" {
+		conds = append(conds, pred)
+	}
+	var clause string
+	if len(conds) > 0 {
+		clause = " WHERE " + strings.Join(conds, " AND ")
+	}
+	if kr != nil && kr.Ordered {
+		clause += " ORDER BY " + quotedCol
+	}
+	return clause
+}
+
+// ReadPredicate returns the condition that the rows of table tableId read
+// from a SQL source must satisfy: the table selection predicate, and the
+// key filters of conv.Sampler, if any (see internal.Sampler.Pushdown).
+// Column names are quoted with quoteCol and strings with quoteString.
+// Only filters on INT64 columns, and on STRING columns read from character
+// columns, are pushed down. limit is the maximum number of rows to read,
+// or 0 if it can't be pushed down.
+func ReadPredicate(conv *internal.Conv, tableId string, quoteCol, quoteString func(string) string) (where string, limit int64) {
+	var conds []string
+	if pred := conv.TableSelection.Predicate(conv.SrcSchema[tableId].Name); pred != "" {
+		conds = append(conds, pred)
+	}
+	if conv.Sampler != nil {
+		var filters []internal.SampleKeyFilter
+		filters, limit = conv.Sampler.Pushdown(tableId)
+		for _, f := range filters {
+			cond, ok := sampleFilterCondition(conv, tableId, f, quoteCol, quoteString)
+			if !ok {
+				limit = 0
+				continue
+			}
+			conds = append(conds, cond)
+		}
+	}
+	if len(conds) > 1 {
+		for i := range conds {
+			conds[i] = "(" + conds[i] + ")"
+		}
+	}
+	return strings.Join(conds, " AND "), limit
+}
+
+// sampleFilterCondition returns the SQL condition for f, and false if it
+// can't be written.
+func sampleFilterCondition(conv *internal.Conv, tableId string, f internal.SampleKeyFilter, quoteCol, quoteString func(string) string) (string, bool) {
+	srcCol, ok := conv.SrcSchema[tableId].ColDefs[f.ColId]
+	if !ok {
+		return "", false
+	}
+	spType := conv.SpSchema[tableId].ColDefs[f.ColId].T
+	srcType := strings.ToLower(srcCol.Type.Name)
+	// Other values may have been changed by their conversion, e.g. the
+	// STRING value of a DECIMAL column.
+	isText := strings.Contains(srcType, "char") || strings.Contains(srcType, "text")
+	vals := make([]string, 0, len(f.Values))
+	for _, v := range f.Values {
+		switch x := v.(type) {
+		case int64:
+			if spType.Name != ddl.Int64 || spType.IsArray {
+				return "", false
+			}
+			vals = append(vals, strconv.FormatInt(x, 10))
+		case string:
+			if spType.Name != ddl.String || spType.IsArray || !isText {
+				return "", false
+			}
+			vals = append(vals, quoteString(x))
+		default:
+			return "", false
+		}
+	}
+	col := quoteCol(srcCol.Name)
+	if len(vals) == 0 {
+		return col + " IS NULL", true
+	}
+	return fmt.Sprintf("%s IS NULL OR %s IN (%s)", col, col, strings.Join(vals, ", ")), true
+}
+
+// QuoteString returns s as a standard SQL string literal.
+func QuoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// KeyRangePredicate returns the SQL condition that restricts rows to kr,
+// given the quoted name of its column. It returns "" when kr is nil or
+// unbounded on both sides.
+func KeyRangePredicate(kr *internal.KeyRange, quotedCol string) string {
+	if kr == nil {
+		return ""
+	}
+	var conds []string
+	if kr.Start != nil {
+		conds = append(conds, quotedCol+" >= "+strconv.FormatInt(*kr.Start, 10))
+	}
+	if kr.End != nil {
+		conds = append(conds, quotedCol+" < "+strconv.FormatInt(*kr.End, 10))
+	}
+	return strings.Join(conds, " AND ")
+}
@@ -0,0 +1,350 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// keyRangeInfoSchema is a KeyRangeInfoSchema over in-memory tables of
+// integer keys.
+type keyRangeInfoSchema struct {
+	rows map[string][]int64
+	// events records the order in which tables are read and flushed.
+	events []string
+	// failTable is a table whose reads fail.
+	failTable string
+}
+
+func (kis *keyRangeInfoSchema) GetToDdl() ToDdl                                     { return nil }
+func (kis *keyRangeInfoSchema) GetTableName(schema string, tableName string) string { return tableName }
+func (kis *keyRangeInfoSchema) GetTables() ([]SchemaAndName, error)                 { return nil, nil }
+func (kis *keyRangeInfoSchema) GetRowsFromTable(conv *internal.Conv, srcTable string) (interface{}, error) {
+	return nil, nil
+}
+func (kis *keyRangeInfoSchema) GetRowCount(table SchemaAndName) (int64, error) { return 0, nil }
+
+func (kis *keyRangeInfoSchema) GetKeyBounds(conv *internal.Conv, tableId string, colId string) (int64, int64, bool, error) {
+	rows := kis.rows[tableId]
+	if len(rows) == 0 {
+		return 0, 0, false, nil
+	}
+	min, max := rows[0], rows[0]
+	for _, r := range rows {
+		if r < min {
+			min = r
+		}
+		if r > max {
+			max = r
+		}
+	}
+	return min, max, true, nil
+}
+
+func (kis *keyRangeInfoSchema) ProcessData(conv *internal.Conv, tableId string, srcSchema schema.Table, colIds []string, spSchema ddl.CreateTable, additionalAttributes internal.AdditionalDataAttributes) error {
+	if tableId == kis.failTable {
+		return fmt.Errorf("connection reset")
+	}
+	kr := additionalAttributes.KeyRange
+	for _, r := range kis.rows[tableId] {
+		if kr != nil && ((kr.Start != nil && r < *kr.Start) || (kr.End != nil && r >= *kr.End)) {
+			continue
+		}
+		conv.ConvLock.Lock()
+		conv.WriteRow(srcSchema.Name, spSchema.Name, []string{"id"}, []interface{}{r})
+		conv.ConvLock.Unlock()
+	}
+	return nil
+}
+
+func TestSplitKeyRange(t *testing.T) {
+	i64 := func(i int64) *int64 { return &i }
+	testCases := []struct {
+		name     string
+		min, max int64
+		n        int
+		expected []*internal.KeyRange
+	}{
+		{"single chunk", 0, 100, 1, []*internal.KeyRange{nil}},
+		{"single value", 5, 5, 4, []*internal.KeyRange{nil}},
+		{"even split", 0, 100, 4, []*internal.KeyRange{
+			{ColId: "c1", End: i64(25)},
+			{ColId: "c1", Start: i64(25), End: i64(50)},
+			{ColId: "c1", Start: i64(50), End: i64(75)},
+			{ColId: "c1", Start: i64(75)},
+		}},
+		{"fewer values than chunks", 1, 3, 8, []*internal.KeyRange{
+			{ColId: "c1", End: i64(2)},
+			{ColId: "c1", Start: i64(2), End: i64(3)},
+			{ColId: "c1", Start: i64(3)},
+		}},
+		{"full int64 range", -1 << 63, 1<<63 - 1, 2, []*internal.KeyRange{
+			{ColId: "c1", End: i64(-1)},
+			{ColId: "c1", Start: i64(-1)},
+		}},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, SplitKeyRange("c1", tc.min, tc.max, tc.n), tc.name)
+	}
+}
+
+func TestKeyRangePredicate(t *testing.T) {
+	i64 := func(i int64) *int64 { return &i }
+	assert.Equal(t, "", KeyRangePredicate(nil, "`id`"))
+	assert.Equal(t, "", KeyRangePredicate(&internal.KeyRange{ColId: "c1"}, "`id`"))
+	assert.Equal(t, "`id` < 10", KeyRangePredicate(&internal.KeyRange{ColId: "c1", End: i64(10)}, "`id`"))
+	assert.Equal(t, "`id` >= -5", KeyRangePredicate(&internal.KeyRange{ColId: "c1", Start: i64(-5)}, "`id`"))
+	assert.Equal(t, "`id` >= 5 AND `id` < 10", KeyRangePredicate(&internal.KeyRange{ColId: "c1", Start: i64(5), End: i64(10)}, "`id`"))
+}
+
+func TestKeyRangeClause(t *testing.T) {
+	i64 := func(i int64) *int64 { return &i }
+	assert.Equal(t, "", KeyRangeClause(nil, "`id`"))
+	assert.Equal(t, "", KeyRangeClause(&internal.KeyRange{ColId: "c1"}, "`id`"))
+	assert.Equal(t, " ORDER BY `id`", KeyRangeClause(&internal.KeyRange{ColId: "c1", Ordered: true}, "`id`"))
+	assert.Equal(t, " WHERE `id` >= 5 ORDER BY `id`", KeyRangeClause(&internal.KeyRange{ColId: "c1", Start: i64(5), Ordered: true}, "`id`"))
+	assert.Equal(t, " WHERE `id` < 5", KeyRangeClause(&internal.KeyRange{ColId: "c1", End: i64(5)}, "`id`"))
+}
+
+func TestSelectClause(t *testing.T) {
+	i64 := func(i int64) *int64 { return &i }
+	assert.Equal(t, "", SelectClause("", nil, ""))
+	assert.Equal(t, " WHERE (a = 1 OR b = 2)", SelectClause("a = 1 OR b = 2", nil, ""))
+	assert.Equal(t, " WHERE (a = 1) AND `id` < 5 ORDER BY `id`", SelectClause("a = 1", &internal.KeyRange{ColId: "c1", End: i64(5), Ordered: true}, "`id`"))
+}
+
+func TestReadPredicate(t *testing.T) {
+	conv := internal.MakeConv()
+	conv.SrcSchema["t1"] = schema.Table{Id: "t1", Name: "users", ColIds: []string{"c1"}, ColDefs: map[string]schema.Column{
+		"c1": {Id: "c1", Name: "id", Type: schema.Type{Name: "varchar"}},
+	}}
+	conv.SpSchema["t1"] = ddl.CreateTable{Id: "t1", Name: "users", ColIds: []string{"c1"}, PrimaryKeys: []ddl.IndexKey{{ColId: "c1"}}, ColDefs: map[string]ddl.ColumnDef{
+		"c1": {Id: "c1", Name: "id", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+	}}
+	conv.SrcSchema["t2"] = schema.Table{Id: "t2", Name: "orders", ColIds: []string{"c2", "c3"}, ColDefs: map[string]schema.Column{
+		"c2": {Id: "c2", Name: "id", Type: schema.Type{Name: "bigint"}},
+		"c3": {Id: "c3", Name: "user_id", Type: schema.Type{Name: "varchar"}},
+	}}
+	conv.SpSchema["t2"] = ddl.CreateTable{Id: "t2", Name: "orders", ColIds: []string{"c2", "c3"}, PrimaryKeys: []ddl.IndexKey{{ColId: "c2"}}, ColDefs: map[string]ddl.ColumnDef{
+		"c2": {Id: "c2", Name: "id", T: ddl.Type{Name: ddl.Int64}},
+		"c3": {Id: "c3", Name: "user_id", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+	}, ForeignKeys: []ddl.Foreignkey{{Id: "f1", ColIds: []string{"c3"}, ReferTableId: "t1", ReferColumnIds: []string{"c1"}}}}
+	quoteCol := func(c string) string { return `"` + c + `"` }
+
+	where, limit := ReadPredicate(conv, "t2", quoteCol, QuoteString)
+	assert.Equal(t, "", where)
+	assert.Equal(t, int64(0), limit)
+
+	conv.TableSelection = &internal.TableSelection{Where: map[string]string{"orders": "id > 10"}}
+	conv.Sampler = internal.NewSampler(conv.SpSchema, internal.SampleOptions{MaxRows: 5})
+	where, limit = ReadPredicate(conv, "t2", quoteCol, QuoteString)
+	assert.Equal(t, `(id > 10) AND ("user_id" IS NULL)`, where)
+	assert.Equal(t, int64(5), limit)
+
+	conv.Sampler.Keep("users", []string{"id"}, []interface{}{"a"})
+	conv.Sampler.Keep("users", []string{"id"}, []interface{}{"o'b"})
+	where, limit = ReadPredicate(conv, "t2", quoteCol, QuoteString)
+	assert.Equal(t, `(id > 10) AND ("user_id" IS NULL OR "user_id" IN ('a', 'o''b'))`, where)
+	assert.Equal(t, int64(5), limit)
+
+	// Values converted from other types can't be compared in the source.
+	src := conv.SrcSchema["t2"]
+	src.ColDefs["c3"] = schema.Column{Id: "c3", Name: "user_id", Type: schema.Type{Name: "decimal"}}
+	where, limit = ReadPredicate(conv, "t2", quoteCol, QuoteString)
+	assert.Equal(t, "id > 10", where)
+	assert.Equal(t, int64(0), limit)
+
+	// Rows of root tables sampled by percentage aren't limited.
+	conv.Sampler = internal.NewSampler(conv.SpSchema, internal.SampleOptions{Percent: 10, MaxRows: 5})
+	where, limit = ReadPredicate(conv, "t1", quoteCol, QuoteString)
+	assert.Equal(t, "", where)
+	assert.Equal(t, int64(0), limit)
+}
+
+func TestProcessDataTableSelection(t *testing.T) {
+	conv := internal.MakeConv()
+	conv.SetDataMode()
+	for _, tbl := range []string{"orders", "orders_archive", "users"} {
+		conv.SrcSchema[tbl] = schema.Table{Id: tbl, Name: tbl, ColIds: []string{"c1"}, ColDefs: map[string]schema.Column{"c1": {Id: "c1", Name: "id"}}}
+		conv.SpSchema[tbl] = ddl.CreateTable{Id: tbl, Name: tbl, ColIds: []string{"c1"}, ColDefs: map[string]ddl.ColumnDef{"c1": {Id: "c1", Name: "id", T: ddl.Type{Name: ddl.Int64}}}}
+	}
+	conv.TableSelection = &internal.TableSelection{Include: []string{"orders*"}, Exclude: []string{"*_archive"}}
+	written := map[string]int{}
+	conv.SetDataSink(func(table string, cols []string, vals []interface{}) { written[table]++ })
+	kis := &keyRangeInfoSchema{rows: map[string][]int64{"orders": {1, 2}, "orders_archive": {3}, "users": {4}}}
+
+	is := InfoSchemaImpl{}
+	assert.Nil(t, is.ProcessData(conv, kis, internal.AdditionalDataAttributes{}))
+	assert.Equal(t, map[string]int{"orders": 2}, written)
+
+	written = map[string]int{}
+	conv.BulkRead = internal.BulkReadOptions{ParallelTables: 2}
+	assert.Nil(t, is.ProcessData(conv, kis, internal.AdditionalDataAttributes{}))
+	assert.Equal(t, map[string]int{"orders": 2}, written)
+}
+
+func TestProcessDataTableError(t *testing.T) {
+	for _, bulkRead := range []internal.BulkReadOptions{{}, {ParallelTables: 2}} {
+		conv := internal.MakeConv()
+		conv.SetDataMode()
+		for _, tbl := range []string{"orders", "users"} {
+			conv.SrcSchema[tbl] = schema.Table{Id: tbl, Name: tbl, ColIds: []string{"c1"}, ColDefs: map[string]schema.Column{"c1": {Id: "c1", Name: "id"}}}
+			conv.SpSchema[tbl] = ddl.CreateTable{Id: tbl, Name: tbl, ColIds: []string{"c1"}, ColDefs: map[string]ddl.ColumnDef{"c1": {Id: "c1", Name: "id", T: ddl.Type{Name: ddl.Int64}}}}
+		}
+		conv.BulkRead = bulkRead
+		conv.SetDataSink(func(table string, cols []string, vals []interface{}) {})
+		kis := &keyRangeInfoSchema{rows: map[string][]int64{"orders": {1, 2}, "users": {3}}, failTable: "users"}
+
+		is := InfoSchemaImpl{}
+		err := is.ProcessData(conv, kis, internal.AdditionalDataAttributes{})
+		assert.EqualError(t, err, "can't process data of table users: connection reset", "%+v", bulkRead)
+	}
+}
+
+func TestProcessDataInParallel(t *testing.T) {
+	table := func(id, name, parent string) (schema.Table, ddl.CreateTable) {
+		return schema.Table{
+			Id:          id,
+			Name:        name,
+			ColIds:      []string{"c1"},
+			ColDefs:     map[string]schema.Column{"c1": {Id: "c1", Name: "id"}},
+			PrimaryKeys: []schema.Key{{ColId: "c1"}},
+		}, ddl.CreateTable{
+			Id:          id,
+			Name:        name,
+			ColIds:      []string{"c1"},
+			ColDefs:     map[string]ddl.ColumnDef{"c1": {Id: "c1", Name: "id", T: ddl.Type{Name: ddl.Int64}}},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c1"}},
+			ParentTable: ddl.InterleavedParent{Id: parent},
+		}
+	}
+	conv := internal.MakeConv()
+	conv.SetDataMode()
+	conv.BulkRead = internal.BulkReadOptions{ParallelTables: 3, ChunksPerTable: 4}
+	for _, tbl := range [][]string{{"t1", "parent", ""}, {"t2", "child", "t1"}, {"t3", "other", ""}} {
+		conv.SrcSchema[tbl[0]], conv.SpSchema[tbl[0]] = table(tbl[0], tbl[1], tbl[2])
+	}
+	kis := &keyRangeInfoSchema{rows: map[string][]int64{
+		"t1": {1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+		"t2": {-3, 0, 100},
+		"t3": {},
+	}}
+	written := map[string][]int64{}
+	var pending []string
+	conv.SetDataSink(func(table string, cols []string, vals []interface{}) {
+		written[table] = append(written[table], vals[0].(int64))
+		pending = append(pending, table)
+	})
+	conv.DataFlush = func() {
+		for _, table := range pending {
+			if len(kis.events) == 0 || kis.events[len(kis.events)-1] != table {
+				kis.events = append(kis.events, table)
+			}
+		}
+		pending = nil
+	}
+
+	is := InfoSchemaImpl{}
+	is.ProcessData(conv, kis, internal.AdditionalDataAttributes{})
+
+	for table, rows := range written {
+		sort.Slice(rows, func(i, j int) bool { return rows[i] < rows[j] })
+		written[table] = rows
+	}
+	assert.Equal(t, map[string][]int64{
+		"parent": {1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+		"child":  {-3, 0, 100},
+	}, written)
+	// The interleaved child is only read once its parent has been flushed.
+	parentIdx, childIdx := -1, -1
+	for i, e := range kis.events {
+		switch e {
+		case "parent":
+			parentIdx = i
+		case "child":
+			childIdx = i
+		}
+	}
+	assert.True(t, parentIdx >= 0 && parentIdx < childIdx, kis.events)
+	assert.Equal(t, int64(0), conv.Unexpecteds())
+}
+
+func TestProcessDataResume(t *testing.T) {
+	conv := internal.MakeConv()
+	conv.SetDataMode()
+	for _, tbl := range []string{"t1", "t2"} {
+		conv.SrcSchema[tbl] = schema.Table{
+			Id:          tbl,
+			Name:        tbl,
+			ColIds:      []string{"c1"},
+			ColDefs:     map[string]schema.Column{"c1": {Id: "c1", Name: "id"}},
+			PrimaryKeys: []schema.Key{{ColId: "c1"}},
+		}
+		conv.SpSchema[tbl] = ddl.CreateTable{
+			Id:          tbl,
+			Name:        tbl,
+			ColIds:      []string{"c1"},
+			ColDefs:     map[string]ddl.ColumnDef{"c1": {Id: "c1", Name: "id", T: ddl.Type{Name: ddl.Int64}}},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c1"}},
+		}
+	}
+	kis := &keyRangeInfoSchema{rows: map[string][]int64{
+		"t1": {1, 2, 3},
+		"t2": {1, 2, 3, 4, 5},
+	}}
+
+	// A previous run wrote all of t1, and t2 up to key 2 before it stopped.
+	store := &internal.FileCheckpointStore{Path: filepath.Join(t.TempDir(), "checkpoint.json")}
+	cp, err := internal.OpenCheckpoint(store, "db", false)
+	assert.Nil(t, err)
+	cp.StartTable("t1", "id", []*internal.KeyRange{{ColId: "c1"}})
+	for i := 0; i < 3; i++ {
+		cp.RowAdded("t1", []string{"id"}, []interface{}{int64(i + 1)})
+	}
+	cp.TableDone("t1")
+	cp.StartTable("t2", "id", []*internal.KeyRange{{ColId: "c1"}})
+	cp.RowAdded("t2", []string{"id"}, []interface{}{int64(1)})
+	cp.RowAdded("t2", []string{"id"}, []interface{}{int64(2)})
+	cp.Flushed()
+	cp.RowAdded("t2", []string{"id"}, []interface{}{int64(3)})
+
+	conv.Checkpoint, err = internal.OpenCheckpoint(store, "db", true)
+	assert.Nil(t, err)
+	written := map[string][]int64{}
+	conv.SetDataSink(func(table string, cols []string, vals []interface{}) {
+		written[table] = append(written[table], vals[0].(int64))
+		conv.Checkpoint.RowAdded(table, cols, vals)
+	})
+	conv.DataFlush = conv.Checkpoint.Flushed
+
+	is := InfoSchemaImpl{}
+	is.ProcessData(conv, kis, internal.AdditionalDataAttributes{})
+
+	assert.Equal(t, map[string][]int64{"t2": {3, 4, 5}}, written)
+	assert.Equal(t, int64(3), conv.Stats.GoodRows["t1"])
+	assert.Equal(t, int64(5), conv.Stats.GoodRows["t2"])
+	tcp := conv.Checkpoint.Table("t2")
+	assert.True(t, tcp.Done)
+	assert.Equal(t, int64(5), tcp.Rows)
+}
@@ -125,6 +125,7 @@ func (ss *SchemaToSpannerImpl) SchemaToSpannerDDL(conv *internal.Conv, toddl ToD
 		}
 	}
 
+	ViewsToSpanner(conv, toddl)
 	internal.ResolveRefs(conv)
 	return nil
 }
@@ -420,7 +421,8 @@ func (ss *SchemaToSpannerImpl) SchemaToSpannerDDLHelper(conv *internal.Conv, tod
 			isNotNull = false
 		}
 		// Set the not null constraint to false for array datatype and add a warning.
-		if ty.IsArray {
+		// Vectors are arrays as well, but are fully supported.
+		if ty.IsArray && ty.VectorLength == 0 {
 			issues = append(issues, internal.ArrayTypeNotSupported)
 			isNotNull = false
 		}
@@ -467,6 +469,8 @@ func (ss *SchemaToSpannerImpl) SchemaToSpannerDDLHelper(conv *internal.Conv, tod
 			totalNonKeyColumnSize += getColumnSize(ty.Name, ty.Len)
 		}
 	}
+	spColIds, searchIndexes := cvtSearchIndexes(conv, srcTable, spColIds, spColDef, columnLevelIssues)
+	vectorIndexes := cvtVectorIndexes(conv, srcTable, spColDef, columnLevelIssues)
 	if totalNonKeyColumnSize > ddl.MaxNonKeyColumnLength {
 		tableLevelIssues = append(tableLevelIssues, internal.RowLimitExceeded)
 	}
@@ -483,6 +487,8 @@ func (ss *SchemaToSpannerImpl) SchemaToSpannerDDLHelper(conv *internal.Conv, tod
 		ForeignKeys:      cvtForeignKeys(conv, spTableName, srcTable.Id, srcTable.ForeignKeys, isRestore),
 		CheckConstraints: cvtCheckConstraint(conv, srcTable.CheckConstraints),
 		Indexes:          cvtIndexes(conv, srcTable.Id, srcTable.Indexes, spColIds, spColDef),
+		SearchIndexes:    searchIndexes,
+		VectorIndexes:    vectorIndexes,
 		Comment:          comment,
 		Id:               srcTable.Id,
 	}
@@ -602,14 +608,161 @@ func CvtForeignKeysHelper(conv *internal.Conv, spTableName string, srcTableId st
 func cvtIndexes(conv *internal.Conv, tableId string, srcIndexes []schema.Index, spColIds []string, spColDef map[string]ddl.ColumnDef) []ddl.CreateIndex {
 	var spIndexes []ddl.CreateIndex
 	for _, srcIndex := range srcIndexes {
+		if srcIndex.FullText || srcIndex.Vector {
+			// Full-text and vector indexes are converted by cvtSearchIndexes
+			// and cvtVectorIndexes.
+			continue
+		}
 		spIndex := CvtIndexHelper(conv, tableId, srcIndex, spColIds, spColDef)
 		if (!reflect.DeepEqual(spIndex, ddl.CreateIndex{})) {
 			spIndexes = append(spIndexes, spIndex)
 		}
+		if srcIndex.Partial && !spIndex.NullFiltered {
+			for _, k := range spIndex.Keys {
+				colIssues := conv.SchemaIssues[tableId].ColumnLevelIssues[k.ColId]
+				conv.SchemaIssues[tableId].ColumnLevelIssues[k.ColId] = append(colIssues, internal.PartialIndex)
+			}
+		}
 	}
 	return spIndexes
 }
 
+// cvtSearchIndexes converts the full-text indexes of srcTable to search
+// indexes. Each indexed column is tokenized by a hidden TOKENLIST column,
+// which is added to spColIds and spColDef and shared by all the search
+// indexes of the column.
+func cvtSearchIndexes(conv *internal.Conv, srcTable schema.Table, spColIds []string, spColDef map[string]ddl.ColumnDef, columnLevelIssues map[string][]internal.SchemaIssue) ([]string, []ddl.CreateSearchIndex) {
+	var searchIndexes []ddl.CreateSearchIndex
+	tokenColIds := make(map[string]string)
+	for _, srcIndex := range srcTable.Indexes {
+		if !srcIndex.FullText {
+			continue
+		}
+		var keys []ddl.IndexKey
+		for _, k := range srcIndex.Keys {
+			col, ok := spColDef[k.ColId]
+			if !ok {
+				conv.Unexpected(fmt.Sprintf("Can't map search index key column for tableId %s columnId %s", srcTable.Id, k.ColId))
+				continue
+			}
+			if col.T.Name != ddl.String {
+				conv.Unexpected(fmt.Sprintf("Can't tokenize column %s of type %s for search index %s", col.Name, col.T.Name, srcIndex.Name))
+				continue
+			}
+			tokenColId, ok := tokenColIds[k.ColId]
+			if !ok {
+				tokenColId = internal.GenerateColumnId()
+				tokenColIds[k.ColId] = tokenColId
+				spColIds = append(spColIds, tokenColId)
+				spColDef[tokenColId] = ddl.ColumnDef{
+					Name:    getTokenColName(conv, col.Name, spColDef),
+					T:       ddl.Type{Name: ddl.TokenList},
+					Comment: "Tokens of " + col.Name + " for full-text search",
+					Id:      tokenColId,
+					GeneratedColumn: ddl.GeneratedColumn{
+						IsPresent: true,
+						Value: ddl.Expression{
+							ExpressionId: internal.GenerateExpressionId(),
+							Statement:    GetTokenizeExpression(conv, col.Name),
+						},
+						Type: ddl.GeneratedColVirtual,
+					},
+					Hidden: true,
+				}
+				columnLevelIssues[k.ColId] = append(columnLevelIssues[k.ColId], internal.FullTextSearchIndex)
+			}
+			keys = append(keys, ddl.IndexKey{ColId: tokenColId, Order: len(keys) + 1})
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		name := srcIndex.Name
+		if name == "" {
+			name = fmt.Sprintf("SearchIndex_%s", srcTable.Name)
+		}
+		searchIndexes = append(searchIndexes, ddl.CreateSearchIndex{
+			Name:    internal.ToSpannerIndexName(conv, name),
+			TableId: srcTable.Id,
+			Keys:    keys,
+			Id:      srcIndex.Id,
+		})
+	}
+	return spColIds, searchIndexes
+}
+
+// cvtVectorIndexes converts the vector indexes of srcTable to Spanner vector
+// indexes. Spanner vector indexes are on a single ARRAY<FLOAT32> or
+// ARRAY<FLOAT64> column with a vector length, and support the cosine,
+// euclidean and dot product distances; other vector indexes are dropped.
+func cvtVectorIndexes(conv *internal.Conv, srcTable schema.Table, spColDef map[string]ddl.ColumnDef, columnLevelIssues map[string][]internal.SchemaIssue) []ddl.CreateVectorIndex {
+	var vectorIndexes []ddl.CreateVectorIndex
+	for _, srcIndex := range srcTable.Indexes {
+		if !srcIndex.Vector {
+			continue
+		}
+		if len(srcIndex.Keys) != 1 {
+			conv.Unexpected(fmt.Sprintf("Can't convert vector index %s with %d columns", srcIndex.Name, len(srcIndex.Keys)))
+			continue
+		}
+		colId := srcIndex.Keys[0].ColId
+		col, ok := spColDef[colId]
+		if !ok {
+			conv.Unexpected(fmt.Sprintf("Can't map vector index key column for tableId %s columnId %s", srcTable.Id, colId))
+			continue
+		}
+		isVector := col.T.IsArray && col.T.VectorLength > 0 && (col.T.Name == ddl.Float32 || col.T.Name == ddl.Float64)
+		if !isVector || srcIndex.DistanceType == "" {
+			columnLevelIssues[colId] = append(columnLevelIssues[colId], internal.VectorIndexNotSupported)
+			continue
+		}
+		columnLevelIssues[colId] = append(columnLevelIssues[colId], internal.VectorIndex)
+		name := srcIndex.Name
+		if name == "" {
+			name = fmt.Sprintf("VectorIndex_%s", srcTable.Name)
+		}
+		vectorIndexes = append(vectorIndexes, ddl.CreateVectorIndex{
+			Name:         internal.ToSpannerIndexName(conv, name),
+			TableId:      srcTable.Id,
+			Keys:         []ddl.IndexKey{{ColId: colId, Order: 1}},
+			DistanceType: srcIndex.DistanceType,
+			Id:           srcIndex.Id,
+		})
+	}
+	return vectorIndexes
+}
+
+// getTokenColName returns the name of the TOKENLIST column of column colName,
+// e.g. Title_Tokens, that doesn't collide with the columns of spColDef.
+func getTokenColName(conv *internal.Conv, colName string, spColDef map[string]ddl.ColumnDef) string {
+	base := colName + "_Tokens"
+	if conv.SpDialect == constants.DIALECT_POSTGRESQL {
+		base = colName + "_tokens"
+	}
+	name := base
+	for count := 0; ; count++ {
+		used := false
+		for _, col := range spColDef {
+			if strings.EqualFold(col.Name, name) {
+				used = true
+				break
+			}
+		}
+		if !used {
+			return name
+		}
+		name = fmt.Sprintf("%s%d", base, count)
+	}
+}
+
+// GetTokenizeExpression returns the expression that generates the TOKENLIST
+// column of column colName.
+func GetTokenizeExpression(conv *internal.Conv, colName string) string {
+	if conv.SpDialect == constants.DIALECT_POSTGRESQL {
+		return fmt.Sprintf("spanner.tokenize_fulltext(\"%s\")", colName)
+	}
+	return fmt.Sprintf("TOKENIZE_FULLTEXT(`%s`)", colName)
+}
+
 func SrcTableToSpannerDDL(conv *internal.Conv, toddl ToDdl, srcTable schema.Table, ddlVerifier expressions_api.DDLVerifier) error {
 	schemaToSpanner := SchemaToSpannerImpl{
 		DdlV: ddlVerifier,
@@ -693,17 +846,44 @@ func CvtIndexHelper(conv *internal.Conv, tableId string, srcIndex schema.Index,
 		srcIndex.Name = fmt.Sprintf("Index_%s", conv.SrcSchema[tableId].Name)
 	}
 	spIndexName := internal.ToSpannerIndexName(conv, srcIndex.Name)
+	nullFiltered := isNullFiltered(srcIndex, conv.SrcSchema[tableId])
 	spIndex := ddl.CreateIndex{
-		Name:            spIndexName,
-		TableId:         tableId,
-		Unique:          srcIndex.Unique,
+		Name:    spIndexName,
+		TableId: tableId,
+		// The predicate of a partial index that isn't null-filtered is
+		// dropped, so the index covers more rows than in the source, and
+		// uniqueness would be enforced on rows the source doesn't enforce
+		// it on.
+		Unique:          srcIndex.Unique && (!srcIndex.Partial || nullFiltered),
 		Keys:            spKeys,
 		StoredColumnIds: spStoredColIds,
 		Id:              srcIndex.Id,
+		NullFiltered:    nullFiltered,
 	}
 	return spIndex
 }
 
+// isNullFiltered returns true if the partial index srcIndex indexes exactly
+// the rows whose key columns are all non-NULL, so that it can be converted to
+// a null-filtered index. This is the case if its predicate only requires key
+// columns to be non-NULL, and requires it of every nullable key column.
+func isNullFiltered(srcIndex schema.Index, srcTable schema.Table) bool {
+	if len(srcIndex.NotNullColIds) == 0 {
+		return false
+	}
+	notNull := make(map[string]bool)
+	for _, colId := range srcIndex.NotNullColIds {
+		notNull[colId] = true
+	}
+	for _, k := range srcIndex.Keys {
+		if !notNull[k.ColId] && !srcTable.ColDefs[k.ColId].NotNull {
+			return false
+		}
+		delete(notNull, k.ColId)
+	}
+	return len(notNull) == 0
+}
+
 // For primary key with Generated expression, we remove the expression and add column error.
 // This happens when the expression itself is correct but the expression is not allowed by Spanner.
 // For eg, multi-column dependencies.
@@ -983,3 +983,27 @@ func TestApplyExpressionGeneratedColumnPKErrors(t *testing.T) {
 	applyExpressionGeneratedColumnPKErrors(conv, expressions)
 	assert.False(t, conv.SpSchema["t1"].ColDefs["c1"].GeneratedColumn.IsPresent)
 }
+
+func TestIsNullFiltered(t *testing.T) {
+	srcTable := schema.Table{
+		ColDefs: map[string]schema.Column{
+			"c1": {Name: "a"},
+			"c2": {Name: "b"},
+			"c3": {Name: "c", NotNull: true},
+		},
+	}
+	tests := []struct {
+		name     string
+		index    schema.Index
+		expected bool
+	}{
+		{"not partial", schema.Index{Keys: []schema.Key{{ColId: "c1"}}}, false},
+		{"all keys", schema.Index{Keys: []schema.Key{{ColId: "c1"}, {ColId: "c2"}}, NotNullColIds: []string{"c1", "c2"}}, true},
+		{"not null key", schema.Index{Keys: []schema.Key{{ColId: "c1"}, {ColId: "c3"}}, NotNullColIds: []string{"c1"}}, true},
+		{"nullable key missing", schema.Index{Keys: []schema.Key{{ColId: "c1"}, {ColId: "c2"}}, NotNullColIds: []string{"c1"}}, false},
+		{"non-key column", schema.Index{Keys: []schema.Key{{ColId: "c1"}}, NotNullColIds: []string{"c1", "c2"}}, false},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, isNullFiltered(tc.index, srcTable), tc.name)
+	}
+}
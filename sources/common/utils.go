@@ -167,7 +167,8 @@ func PrepareValues[T interface{}](conv *internal.Conv, tableId string, colNameId
 }
 
 func ToPGDialectType(standardType ddl.Type, isPk bool) (ddl.Type, []internal.SchemaIssue) {
-	if standardType.IsArray {
+	// Vectors are supported as arrays with a VECTOR LENGTH in PG.
+	if standardType.IsArray && standardType.VectorLength == 0 {
 		return ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: false},
 			[]internal.SchemaIssue{internal.ArrayTypeNotSupported}
 	}
@@ -0,0 +1,316 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// AddSrcView adds a source view to conv. A view with the same name as a
+// view already in conv replaces it, since dumps can define a view more than
+// once (mysqldump first defines a stand-in for each view).
+func AddSrcView(conv *internal.Conv, view schema.View) {
+	if conv.SrcViews == nil {
+		conv.SrcViews = make(map[string]schema.View)
+	}
+	view.Id = ""
+	for id, v := range conv.SrcViews {
+		if v.Name == view.Name {
+			view.Id = id
+		}
+	}
+	if view.Id == "" {
+		view.Id = internal.GenerateViewId()
+	}
+	conv.SrcViews[view.Id] = view
+}
+
+// ViewTranslator is an interface that can be implemented by ToDdl
+// implementations for sources whose views can be converted to Spanner views.
+type ViewTranslator interface {
+	// TranslateView translates the query of a source view to the Spanner
+	// dialect of q, looking up the tables, views and columns it reads from
+	// with q. The reasons it can't be translated are recorded with
+	// q.AddIssue.
+	TranslateView(q *ViewQuery, query string) string
+}
+
+// ViewsToSpanner translates the source views of conv to Spanner views,
+// reading from the Spanner tables of conv. Views whose query can't be
+// translated, or whose source has no ViewTranslator, are left out of
+// conv.SpViews, and the reason is recorded in conv.SchemaIssues. It can be
+// called again after the Spanner schema changes: views keep their Spanner
+// names.
+func ViewsToSpanner(conv *internal.Conv, toddl ToDdl) {
+	if len(conv.SrcViews) == 0 {
+		return
+	}
+	vt := newViewTranslator(conv, toddl)
+	var names []string
+	nameIdMap := map[string]string{}
+	for id, v := range conv.SrcViews {
+		names = append(names, v.Name)
+		nameIdMap[v.Name] = id
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		vt.translate(nameIdMap[name])
+	}
+	conv.SpViews = vt.spViews
+}
+
+// ViewFunction is a function of a source database whose name differs in
+// Spanner. An empty name means the dialect has no such function.
+type ViewFunction struct {
+	GoogleSql  string
+	PostgreSql string
+}
+
+// ViewRelation is a table or a view that a view reads from.
+type ViewRelation struct {
+	TableId string // Empty for views.
+	ViewId  string // Empty for tables.
+	SpName  string // The Spanner name of the table or view.
+}
+
+// ViewQuery is the translation of the query of a view in progress.
+type ViewQuery struct {
+	vt         *viewTranslator
+	names      []string
+	strings    []string
+	refViewIds []string
+	issues     []internal.SchemaIssue
+}
+
+var (
+	// namePlaceholderRegexp matches the placeholders returned by
+	// ViewQuery.Name, quoted or not.
+	namePlaceholderRegexp = regexp.MustCompile("[`\"]?__smt_name_([0-9]+)__[`\"]?")
+	// stringPlaceholderRegexp matches the placeholders returned by
+	// ViewQuery.String, once quoted.
+	stringPlaceholderRegexp = regexp.MustCompile(`'__smt_string_([0-9]+)__'`)
+)
+
+// PostgreSQL reports whether the Spanner dialect is PostgreSQL.
+func (q *ViewQuery) PostgreSQL() bool {
+	return q.vt.conv.SpDialect == constants.DIALECT_POSTGRESQL
+}
+
+// AddIssue records a reason the query can't be translated.
+func (q *ViewQuery) AddIssue(issue internal.SchemaIssue) {
+	if !internal.Contains(q.issues, issue) {
+		q.issues = append(q.issues, issue)
+	}
+}
+
+// Relation looks up the table or view named by names, the name of a
+// table or a view optionally qualified by its schema and database. The
+// views it reads from are translated first, and ViewMissingTable is
+// recorded if the table or view isn't migrated to Spanner.
+func (q *ViewQuery) Relation(names ...string) (ViewRelation, bool) {
+	var parts []string
+	for _, n := range names {
+		if n != "" {
+			parts = append(parts, strings.ToLower(n))
+		}
+	}
+	rel, ok := q.vt.relations[strings.Join(parts, ".")]
+	if !ok && len(parts) == 3 {
+		// The name is qualified by the database.
+		rel, ok = q.vt.relations[strings.Join(parts[1:], ".")]
+	}
+	if !ok {
+		return ViewRelation{}, false
+	}
+	if rel.TableId != "" {
+		spTable, ok := q.vt.conv.SpSchema[rel.TableId]
+		if !ok {
+			q.AddIssue(internal.ViewMissingTable)
+		}
+		rel.SpName = spTable.Name
+		return rel, true
+	}
+	q.vt.translate(rel.ViewId)
+	spView, ok := q.vt.spViews[rel.ViewId]
+	if !ok {
+		q.AddIssue(internal.ViewMissingTable)
+	}
+	if !internal.Contains(q.refViewIds, rel.ViewId) {
+		q.refViewIds = append(q.refViewIds, rel.ViewId)
+	}
+	rel.SpName = spView.Name
+	return rel, true
+}
+
+// Column returns the Spanner name of the column name of the source table
+// tableId.
+func (q *ViewQuery) Column(tableId, name string) (string, bool) {
+	for _, col := range q.vt.conv.SrcSchema[tableId].ColDefs {
+		if strings.EqualFold(col.Name, name) {
+			spCol, ok := q.vt.conv.SpSchema[tableId].ColDefs[col.Id]
+			return spCol.Name, ok
+		}
+	}
+	return "", false
+}
+
+// Function returns the Spanner name of f, recording ViewUnsupportedSql if
+// the dialect has no such function.
+func (q *ViewQuery) Function(f ViewFunction) string {
+	name := f.GoogleSql
+	if q.PostgreSQL() {
+		name = f.PostgreSql
+	}
+	if name == "" {
+		q.AddIssue(internal.ViewUnsupportedSql)
+	}
+	return name
+}
+
+// Quote quotes an identifier.
+func (q *ViewQuery) Quote(name string) string {
+	if q.PostgreSQL() {
+		return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+	}
+	return "`" + strings.ReplaceAll(name, "`", "\\`") + "`"
+}
+
+// Name returns a placeholder for the Spanner name of a table, view or
+// column, to be printed in place of name. Placeholders are replaced by
+// name, quoted as the dialect requires, once the query is translated.
+func (q *ViewQuery) Name(name string) string {
+	q.names = append(q.names, name)
+	return fmt.Sprintf("__smt_name_%d__", len(q.names)-1)
+}
+
+// String returns a placeholder for the string literal s, to be printed in
+// single quotes in place of s. Like names, placeholders are replaced once
+// the query is translated, which spares translators from escaping strings
+// for each dialect.
+func (q *ViewQuery) String(s string) string {
+	q.strings = append(q.strings, s)
+	return fmt.Sprintf("__smt_string_%d__", len(q.strings)-1)
+}
+
+// replacePlaceholders replaces the placeholders of names and strings of
+// spQuery.
+func (q *ViewQuery) replacePlaceholders(spQuery string) string {
+	spQuery = namePlaceholderRegexp.ReplaceAllStringFunc(spQuery, func(p string) string {
+		i, _ := strconv.Atoi(namePlaceholderRegexp.FindStringSubmatch(p)[1])
+		return q.Quote(q.names[i])
+	})
+	return stringPlaceholderRegexp.ReplaceAllStringFunc(spQuery, func(p string) string {
+		i, _ := strconv.Atoi(stringPlaceholderRegexp.FindStringSubmatch(p)[1])
+		return q.quoteString(q.strings[i])
+	})
+}
+
+// quoteString quotes a string literal.
+func (q *ViewQuery) quoteString(s string) string {
+	if q.PostgreSQL() {
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	}
+	s = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`, "\t", `\t`).Replace(s)
+	return "'" + s + "'"
+}
+
+// viewSource returns the source database whose SQL views of conv are in.
+func viewSource(conv *internal.Conv) string {
+	switch conv.Source {
+	case constants.MYSQL, constants.MYSQLDUMP:
+		return constants.MYSQL
+	case constants.POSTGRES, constants.PGDUMP:
+		return constants.POSTGRES
+	}
+	return conv.Source
+}
+
+type viewTranslator struct {
+	conv       *internal.Conv
+	translator ViewTranslator          // Nil if the source has none.
+	relations  map[string]ViewRelation // Keyed by lower-cased source name.
+	spViews    map[string]ddl.CreateView
+	done       map[string]bool // Views translated, or being translated.
+}
+
+func newViewTranslator(conv *internal.Conv, toddl ToDdl) *viewTranslator {
+	translator, _ := toddl.(ViewTranslator)
+	vt := &viewTranslator{
+		conv:       conv,
+		translator: translator,
+		relations:  map[string]ViewRelation{},
+		spViews:    map[string]ddl.CreateView{},
+		done:       map[string]bool{},
+	}
+	source := viewSource(conv)
+	add := func(schemaName, name string, rel ViewRelation) {
+		vt.relations[strings.ToLower(name)] = rel
+		if schemaName == "" && source == constants.POSTGRES && !strings.Contains(name, ".") {
+			// pg_dump leaves out the schema of tables in the public schema.
+			schemaName = "public"
+		}
+		if schemaName != "" {
+			// Table names may already be qualified by their schema.
+			vt.relations[strings.ToLower(schemaName+"."+name[strings.LastIndex(name, ".")+1:])] = rel
+		}
+	}
+	for id, t := range conv.SrcSchema {
+		add(t.Schema, t.Name, ViewRelation{TableId: id})
+	}
+	for id, v := range conv.SrcViews {
+		add(v.Schema, v.Name, ViewRelation{ViewId: id})
+	}
+	return vt
+}
+
+// translate translates a source view, after the views it reads from, and
+// records it in vt.spViews if it can be translated.
+func (vt *viewTranslator) translate(viewId string) {
+	if vt.done[viewId] {
+		return
+	}
+	vt.done[viewId] = true
+	srcView := vt.conv.SrcViews[viewId]
+	q := &ViewQuery{vt: vt}
+	query := strings.TrimRight(strings.TrimSpace(srcView.Definition), ";")
+	var spQuery string
+	if vt.translator == nil || strings.Contains(query, "__smt_") {
+		q.AddIssue(internal.ViewUnsupportedSql)
+	} else {
+		spQuery = vt.translator.TranslateView(q, query)
+	}
+	if len(q.issues) > 0 {
+		logger.Log.Warn(fmt.Sprintf("Couldn't convert view %s to Spanner", srcView.Name))
+		vt.conv.SchemaIssues[viewId] = internal.TableIssues{TableLevelIssues: q.issues}
+		return
+	}
+	spQuery = q.replacePlaceholders(spQuery)
+	delete(vt.conv.SchemaIssues, viewId)
+	spView, ok := vt.conv.SpViews[viewId]
+	if !ok {
+		spView.Name = internal.GetSpannerValidName(vt.conv, srcView.Name)
+	}
+	vt.spViews[viewId] = ddl.CreateView{Name: spView.Name, Id: viewId, Query: spQuery, RefViewIds: q.refViewIds}
+}
@@ -0,0 +1,197 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// getViewsConv returns a conv with a table singers, whose column first_name
+// is named FirstName in Spanner.
+func getViewsConv(source, dialect string) *internal.Conv {
+	conv := internal.MakeConv()
+	conv.Source = source
+	conv.SpDialect = dialect
+	conv.SrcSchema["t1"] = schema.Table{
+		Name:   "singers",
+		Schema: "music",
+		Id:     "t1",
+		ColIds: []string{"c1", "c2"},
+		ColDefs: map[string]schema.Column{
+			"c1": {Name: "id", Id: "c1"},
+			"c2": {Name: "first_name", Id: "c2"},
+		},
+	}
+	conv.SpSchema["t1"] = ddl.CreateTable{
+		Name:   "Singers",
+		Id:     "t1",
+		ColIds: []string{"c1", "c2"},
+		ColDefs: map[string]ddl.ColumnDef{
+			"c1": {Name: "id", Id: "c1"},
+			"c2": {Name: "FirstName", Id: "c2"},
+		},
+	}
+	conv.UsedNames["singers"] = true
+	return conv
+}
+
+// testViewTranslator translates queries made of words separated by spaces,
+// where "t:name" is a table or a view, "c:table.column" is a column of a
+// table, and "s:text" is a string.
+type testViewTranslator struct {
+	MockToDdl
+}
+
+func (tvt *testViewTranslator) TranslateView(q *ViewQuery, query string) string {
+	var words []string
+	for _, w := range strings.Fields(query) {
+		switch {
+		case strings.HasPrefix(w, "t:"):
+			rel, ok := q.Relation(strings.Split(w[2:], ".")...)
+			if !ok {
+				q.AddIssue(internal.ViewMissingTable)
+			}
+			w = q.Name(rel.SpName)
+		case strings.HasPrefix(w, "c:"):
+			parts := strings.Split(w[2:], ".")
+			rel, _ := q.Relation(parts[0])
+			col, ok := q.Column(rel.TableId, parts[1])
+			if !ok {
+				q.AddIssue(internal.ViewMissingTable)
+			}
+			w = q.Name(col)
+		case strings.HasPrefix(w, "s:"):
+			w = "'" + q.String(w[2:]) + "'"
+		case w == "now":
+			w = q.Function(ViewFunction{GoogleSql: "CURRENT_TIMESTAMP"})
+		}
+		words = append(words, w)
+	}
+	return strings.Join(words, " ")
+}
+
+func TestViewsToSpanner(t *testing.T) {
+	testCases := []struct {
+		name       string
+		dialect    string
+		definition string
+		want       string
+	}{
+		{
+			name:       "googlesql",
+			dialect:    constants.DIALECT_GOOGLESQL,
+			definition: "SELECT c:singers.first_name , s:it's FROM t:music.singers ;",
+			want:       "SELECT `FirstName` , 'it\\'s' FROM `Singers`",
+		},
+		{
+			name:       "postgresql",
+			dialect:    constants.DIALECT_POSTGRESQL,
+			definition: "SELECT c:singers.first_name , s:it's FROM t:singers",
+			want:       `SELECT "FirstName" , 'it''s' FROM "Singers"`,
+		},
+	}
+	for _, tc := range testCases {
+		conv := getViewsConv(constants.MYSQL, tc.dialect)
+		AddSrcView(conv, schema.View{Name: "singer_names", Schema: "music", Definition: tc.definition})
+		ViewsToSpanner(conv, &testViewTranslator{})
+		assert.Len(t, conv.SpViews, 1, tc.name)
+		for id, v := range conv.SpViews {
+			assert.Equal(t, ddl.CreateView{Name: "singer_names", Id: id, Query: tc.want}, v, tc.name)
+		}
+		assert.Empty(t, conv.SchemaIssues, tc.name)
+	}
+}
+
+func TestViewsToSpanner_ViewOfView(t *testing.T) {
+	conv := getViewsConv(constants.MYSQL, constants.DIALECT_GOOGLESQL)
+	AddSrcView(conv, schema.View{Name: "a_view", Definition: "SELECT id FROM t:b_view JOIN t:singers"})
+	AddSrcView(conv, schema.View{Name: "b_view", Definition: "SELECT id FROM t:singers"})
+	ViewsToSpanner(conv, &testViewTranslator{})
+	assert.Len(t, conv.SpViews, 2)
+	var aId, bId string
+	for id, v := range conv.SrcViews {
+		if v.Name == "a_view" {
+			aId = id
+		} else {
+			bId = id
+		}
+	}
+	assert.Equal(t, "SELECT id FROM `b_view` JOIN `Singers`", conv.SpViews[aId].Query)
+	assert.Equal(t, []string{bId}, conv.SpViews[aId].RefViewIds)
+	assert.Equal(t, []string{bId, aId}, ddl.GetSortedViewIds(conv.SpViews))
+
+	// Views follow changes of the Spanner schema, and keep their names.
+	sp := conv.SpSchema["t1"]
+	sp.Name = "Artists"
+	conv.SpSchema["t1"] = sp
+	conv.UsedNames["b_view_1"] = true
+	ViewsToSpanner(conv, &testViewTranslator{})
+	assert.Equal(t, "SELECT id FROM `Artists`", conv.SpViews[bId].Query)
+	assert.Equal(t, "b_view", conv.SpViews[bId].Name)
+}
+
+func TestViewsToSpanner_Issues(t *testing.T) {
+	testCases := []struct {
+		name       string
+		toddl      ToDdl
+		definition string
+		want       internal.SchemaIssue
+	}{
+		{"unsupported function", &testViewTranslator{}, "SELECT now", internal.ViewUnsupportedSql},
+		{"no translator", &MockToDdl{}, "SELECT 1", internal.ViewUnsupportedSql},
+		{"placeholder", &testViewTranslator{}, "SELECT '__smt_string_0__'", internal.ViewUnsupportedSql},
+		{"missing table", &testViewTranslator{}, "SELECT id FROM t:albums", internal.ViewMissingTable},
+		{"missing column", &testViewTranslator{}, "SELECT c:singers.last_name FROM t:singers", internal.ViewMissingTable},
+	}
+	for _, tc := range testCases {
+		conv := getViewsConv(constants.MYSQL, constants.DIALECT_POSTGRESQL)
+		AddSrcView(conv, schema.View{Name: "v", Definition: tc.definition})
+		ViewsToSpanner(conv, tc.toddl)
+		assert.Empty(t, conv.SpViews, tc.name)
+		for id := range conv.SrcViews {
+			assert.Equal(t, []internal.SchemaIssue{tc.want}, conv.SchemaIssues[id].TableLevelIssues, tc.name)
+		}
+	}
+
+	// A view that reads from a view that can't be converted can't be either.
+	conv := getViewsConv(constants.MYSQL, constants.DIALECT_GOOGLESQL)
+	AddSrcView(conv, schema.View{Name: "a", Definition: "SELECT id FROM t:b"})
+	AddSrcView(conv, schema.View{Name: "b", Definition: "SELECT id FROM t:albums"})
+	ViewsToSpanner(conv, &testViewTranslator{})
+	assert.Empty(t, conv.SpViews)
+	assert.Len(t, conv.SchemaIssues, 2)
+}
+
+func TestAddSrcView(t *testing.T) {
+	conv := internal.MakeConv()
+	AddSrcView(conv, schema.View{Name: "v", Definition: "SELECT 1 AS `a`"})
+	AddSrcView(conv, schema.View{Name: "w", Definition: "SELECT 2 AS `a`"})
+	AddSrcView(conv, schema.View{Name: "v", Definition: "SELECT `a` FROM `t`"})
+	assert.Len(t, conv.SrcViews, 2)
+	for id, v := range conv.SrcViews {
+		assert.Equal(t, id, v.Id)
+		if v.Name == "v" {
+			assert.Equal(t, "SELECT `a` FROM `t`", v.Definition)
+		}
+	}
+}
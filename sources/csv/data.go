@@ -63,10 +63,10 @@ func (c *CsvImpl) GetCSVFiles(conv *internal.Conv, sourceProfile profiles.Source
 		}
 	}
 
-	// Download gcs files if any.
-	tables, err = utils.PreloadGCSFiles(tables)
+	// Download gcs, s3 and http(s) files if any.
+	tables, err = utils.PreloadRemoteFiles(tables)
 	if err != nil {
-		return nil, fmt.Errorf("gcs file download error: %v", err)
+		return nil, fmt.Errorf("file download error: %v", err)
 	}
 	return tables, nil
 }
@@ -133,7 +133,7 @@ func VerifyManifest(conv *internal.Conv, tables []utils.ManifestTable) error {
 func (c *CsvImpl) SetRowStats(conv *internal.Conv, tables []utils.ManifestTable, delimiter rune) error {
 	for _, table := range tables {
 		for _, filePath := range table.File_patterns {
-			csvFile, err := os.Open(filePath)
+			csvFile, err := openCSVFile(filePath)
 			if err != nil {
 				return fmt.Errorf("can't read csv file: %s due to: %v", filePath, err)
 			}
@@ -149,6 +149,7 @@ func (c *CsvImpl) SetRowStats(conv *internal.Conv, tables []utils.ManifestTable,
 				colNames = append(colNames, conv.SpSchema[tableId].ColDefs[colIds].Name)
 			}
 			count, err := getCSVDataRowCount(r, colNames)
+			csvFile.Close()
 			if err != nil {
 				return fmt.Errorf("error reading file %s for table %s: %v", filePath, table.Table_name, err)
 			}
@@ -219,12 +220,13 @@ func (c *CsvImpl) ProcessCSV(conv *internal.Conv, tables []utils.ManifestTable,
 			}
 			colDefs := conv.SpSchema[tableId].ColDefs
 
-			csvFile, err := os.Open(filePath)
+			csvFile, err := openCSVFile(filePath)
 			if err != nil {
 				return fmt.Errorf("can't read csv file: %s due to: %v\n", filePath, err)
 			}
 			err = c.ProcessSingleCSV(conv, table.Table_name, colNames, colDefs,
 				csvFile, nullStr, delimiter)
+			csvFile.Close()
 			if err != nil {
 				return err
 			}
@@ -236,6 +238,32 @@ func (c *CsvImpl) ProcessCSV(conv *internal.Conv, tables []utils.ManifestTable,
 	return nil
 }
 
+// openCSVFile opens the csv file at filePath. Gzip, zstd and bzip2
+// compressed files are decompressed as they are read.
+func openCSVFile(filePath string) (io.ReadCloser, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	r, err := internal.Decompress(f, filePath)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return decompressedFile{ReadCloser: r, file: f}, nil
+}
+
+// decompressedFile closes the decompressor of a file and the file.
+type decompressedFile struct {
+	io.ReadCloser
+	file *os.File
+}
+
+func (f decompressedFile) Close() error {
+	f.ReadCloser.Close()
+	return f.file.Close()
+}
+
 func (c *CsvImpl) ProcessSingleCSV(conv *internal.Conv, tableName string,
 	columnNames []string, colDefs map[string]ddl.ColumnDef, csvFile io.Reader,
 	nullStr string, delimiter rune) error {
@@ -252,7 +280,8 @@ func (c *CsvImpl) ProcessSingleCSV(conv *internal.Conv, tableName string,
 		return fmt.Errorf("can't read row for file due to: %v", err)
 	}
 	// If first row is some permutation of Spanner schema columns, we assume the first row is headers.
-	if utils.CheckEqualSets(srcCols, columnNames) {
+	// The synthetic primary key may be missing from the headers, since it is filled in by its default value.
+	if utils.CheckEqualSets(srcCols, columnNames) || utils.CheckEqualSets(srcCols, withoutSyntheticKey(columnNames)) {
 		columnNames = srcCols
 	} else {
 		// Write the first row since it was not a column header.
@@ -272,6 +301,17 @@ func (c *CsvImpl) ProcessSingleCSV(conv *internal.Conv, tableName string,
 	return nil
 }
 
+// withoutSyntheticKey returns columnNames without the synthetic primary key.
+func withoutSyntheticKey(columnNames []string) []string {
+	var cols []string
+	for _, c := range columnNames {
+		if c != internal.SyntheticPrimaryKey {
+			cols = append(cols, c)
+		}
+	}
+	return cols
+}
+
 // processDataRow converts a row into go data types as per the client libs.
 func processDataRow(conv *internal.Conv, nullStr, tableName string,
 	srcCols []string, colDefs map[string]ddl.ColumnDef, values []string) {
@@ -302,12 +342,7 @@ func convertData(dialect, nullStr string, srcCols []string,
 		}
 		spColDef := colDefs[colId]
 
-		var x interface{}
-		if spColDef.T.IsArray {
-			x, err = convArray(spColDef.T, val)
-		} else {
-			x, err = convScalar(dialect, spColDef.T, val)
-		}
+		x, err := ConvertValue(dialect, spColDef.T, val)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -317,6 +352,15 @@ func convertData(dialect, nullStr string, srcCols []string,
 	return cvtCols, v, nil
 }
 
+// ConvertValue converts val, in the text form used for CSV data, to a
+// value of Spanner type spannerType.
+func ConvertValue(dialect string, spannerType ddl.Type, val string) (interface{}, error) {
+	if spannerType.IsArray {
+		return convArray(spannerType, val)
+	}
+	return convScalar(dialect, spannerType, val)
+}
+
 func convArray(spannerType ddl.Type, val string) (interface{}, error) {
 	val = strings.TrimSpace(val)
 	// Handle empty array. Note that we use an empty NullString array
@@ -585,6 +629,10 @@ func convNumeric(val string) (big.Rat, error) {
 
 func convTimestamp(val string) (t time.Time, err error) {
 	t, err = time.Parse("2006-01-02 15:04:05", val)
+	if err != nil {
+		t, err = time.Parse(time.RFC3339Nano, val)
+		t = t.UTC()
+	}
 	if err != nil {
 		return t, fmt.Errorf("can't convert to timestamp: %s", val)
 	}
@@ -15,9 +15,12 @@
 package csv
 
 import (
+	"bytes"
+	"compress/gzip"
 	"fmt"
 	"math/big"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -161,6 +164,55 @@ func TestProcessCSV(t *testing.T) {
 	}, rows)
 }
 
+func TestProcessCSV_Compressed(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "singers.csv.gz")
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	w.Write([]byte("SingerId,FirstName,LastName\n1,fn1,ln1\n2,fn2,ln2\n"))
+	assert.Nil(t, w.Close())
+	assert.Nil(t, os.WriteFile(fileName, b.Bytes(), 0644))
+	tables := []utils.ManifestTable{{Table_name: SINGERS_TABLE, File_patterns: []string{fileName}}}
+
+	conv := buildConv(getCreateTable())
+	csv := CsvImpl{}
+	assert.Nil(t, csv.SetRowStats(conv, tables, ','))
+	assert.Equal(t, map[string]int64{SINGERS_TABLE: 2}, conv.Stats.Rows)
+
+	var rows []spannerData
+	conv.SetDataMode()
+	conv.SetDataSink(
+		func(table string, cols []string, vals []interface{}) {
+			rows = append(rows, spannerData{table: table, cols: cols, vals: vals})
+		})
+	assert.Nil(t, csv.ProcessCSV(conv, tables, "", ','))
+	assert.Equal(t, []spannerData{
+		{table: SINGERS_TABLE, cols: []string{"SingerId", "FirstName", "LastName"}, vals: []interface{}{int64(1), "fn1", "ln1"}},
+		{table: SINGERS_TABLE, cols: []string{"SingerId", "FirstName", "LastName"}, vals: []interface{}{int64(2), "fn2", "ln2"}},
+	}, rows)
+}
+
+func TestProcessSingleCSV_SyntheticKeyNotInHeader(t *testing.T) {
+	colDefs := map[string]ddl.ColumnDef{
+		"c10": {Name: "SingerId", Id: "c10", T: ddl.Type{Name: ddl.Int64}},
+		"c11": {Name: "FirstName", Id: "c11", T: ddl.Type{Name: ddl.String}},
+		"c12": {Name: "synth_id", Id: "c12", T: ddl.Type{Name: ddl.String}},
+	}
+	conv := internal.MakeConv()
+	var rows []spannerData
+	conv.SetDataMode()
+	conv.SetDataSink(
+		func(table string, cols []string, vals []interface{}) {
+			rows = append(rows, spannerData{table: table, cols: cols, vals: vals})
+		})
+	csv := CsvImpl{}
+	err := csv.ProcessSingleCSV(conv, SINGERS_TABLE, []string{"SingerId", "FirstName", "synth_id"}, colDefs,
+		strings.NewReader("FirstName,SingerId\nfn1,1\n"), "", ',')
+	assert.Nil(t, err)
+	assert.Equal(t, []spannerData{
+		{table: SINGERS_TABLE, cols: []string{"FirstName", "SingerId"}, vals: []interface{}{"fn1", int64(1)}},
+	}, rows)
+}
+
 func TestGetCSVFilesWithoutManifest(t *testing.T) {
 	writeCSVs(t)
 	defer cleanupCSVs()
@@ -207,6 +259,7 @@ func TestConvertData(t *testing.T) {
 		{"numeric", ddl.Type{Name: ddl.Numeric}, "42.6", *big.NewRat(426, 10)},
 		{"string", ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, "eh", "eh"},
 		{"timestamp", ddl.Type{Name: ddl.Timestamp}, "2019-10-29 05:30:00", getTime(t, "2019-10-29T05:30:00Z")},
+		{"timestamp_rfc3339", ddl.Type{Name: ddl.Timestamp}, "2019-10-29T07:30:00+02:00", getTime(t, "2019-10-29T05:30:00Z")},
 		{"json", ddl.Type{Name: ddl.JSON}, "{\"key1\": \"value1\"}", "{\"key1\": \"value1\"}"},
 		{"int_array", ddl.Type{Name: ddl.Int64, IsArray: true}, "{1,2,NULL}", []spanner.NullInt64{{Int64: int64(1), Valid: true}, {Int64: int64(2), Valid: true}, {Valid: false}}},
 		{"string_array", ddl.Type{Name: ddl.String, IsArray: true}, "[ab,cd]", []spanner.NullString{{StringVal: "ab", Valid: true}, {StringVal: "cd", Valid: true}}},
@@ -0,0 +1,113 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	gomysql "github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+)
+
+// binlogValue returns the text form of value v of column i of table t,
+// as decoded by a parser from newBinlogParser, in the form returned by the
+// MySQL driver for the same column so that ConvertData can convert it.
+// The binlog doesn't say whether integers are unsigned before MySQL 8.0,
+// so that is given by unsigned.
+func binlogValue(t *replication.TableMapEvent, i int, v interface{}, unsigned bool) (string, error) {
+	// ENUM and SET values are written as an index and a bitmap of the
+	// values of the column, which are only in the binlog with
+	// binlog_row_metadata=FULL.
+	switch {
+	case t.IsEnumColumn(i):
+		values, ok := t.EnumStrValueMap()[i]
+		if !ok {
+			return "", fmt.Errorf("ENUM values are unknown, set binlog_row_metadata=FULL on the source")
+		}
+		idx, _ := v.(int64)
+		if idx == 0 {
+			// The value of invalid strings inserted in non-strict mode.
+			return "", nil
+		}
+		if idx > int64(len(values)) {
+			return "", fmt.Errorf("ENUM index %d out of range", idx)
+		}
+		return values[idx-1], nil
+	case t.IsSetColumn(i):
+		values, ok := t.SetStrValueMap()[i]
+		if !ok {
+			return "", fmt.Errorf("SET values are unknown, set binlog_row_metadata=FULL on the source")
+		}
+		bits, _ := v.(int64)
+		var members []string
+		for k, s := range values {
+			if bits&(1<<uint(k)) != 0 {
+				members = append(members, s)
+			}
+		}
+		return strings.Join(members, ","), nil
+	}
+	switch x := v.(type) {
+	case int8:
+		if unsigned {
+			return strconv.FormatUint(uint64(uint8(x)), 10), nil
+		}
+		return strconv.FormatInt(int64(x), 10), nil
+	case int16:
+		if unsigned {
+			return strconv.FormatUint(uint64(uint16(x)), 10), nil
+		}
+		return strconv.FormatInt(int64(x), 10), nil
+	case int32:
+		if unsigned && t.ColumnType[i] == gomysql.MYSQL_TYPE_INT24 {
+			return strconv.FormatUint(uint64(uint32(x)&0xffffff), 10), nil
+		}
+		if unsigned {
+			return strconv.FormatUint(uint64(uint32(x)), 10), nil
+		}
+		return strconv.FormatInt(int64(x), 10), nil
+	case int64:
+		if t.ColumnType[i] == gomysql.MYSQL_TYPE_BIT {
+			// The driver returns BIT(n) values as (n+7)/8 big-endian bytes.
+			meta := t.ColumnMeta[i]
+			b := make([]byte, (int(meta>>8)*8+int(meta&0xff)+7)/8)
+			for k := len(b) - 1; k >= 0; k-- {
+				b[k] = byte(x)
+				x >>= 8
+			}
+			return string(b), nil
+		}
+		if unsigned {
+			return strconv.FormatUint(uint64(x), 10), nil
+		}
+		return strconv.FormatInt(x, 10), nil
+	case int:
+		if t.ColumnType[i] == gomysql.MYSQL_TYPE_YEAR && x == 0 {
+			return "0000", nil
+		}
+		return strconv.Itoa(x), nil
+	case float32:
+		return strconv.FormatFloat(float64(x), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(x, 'g', -1, 64), nil
+	case string:
+		return x, nil
+	case []byte:
+		return string(x), nil
+	}
+	return fmt.Sprint(v), nil
+}
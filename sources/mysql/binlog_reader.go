@@ -0,0 +1,184 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	gomysql "github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+)
+
+// binlogHandler handles an event of a binlog, read from binlog file file.
+type binlogHandler func(file string, e *replication.BinlogEvent) error
+
+// binlogReader reads the events of a binlog, starting at a given position.
+type binlogReader interface {
+	// read calls handle with each event until handle returns an error, ctx
+	// is done or, if the binlog has an end, all events have been read.
+	read(ctx context.Context, handle binlogHandler) error
+	close()
+}
+
+var binlogFileRe = regexp.MustCompile(`\.[0-9]+$`)
+
+// binlogFileSeq returns the sequence number in the extension of binlog
+// file name, such as 10 for binlog.000010.
+func binlogFileSeq(name string) uint64 {
+	n, _ := strconv.ParseUint(binlogFileRe.FindString(name)[1:], 10, 64)
+	return n
+}
+
+// newBinlogParser returns a parser that decodes row values in the form
+// binlogValue expects, with TIMESTAMP values in location.
+func newBinlogParser(location *time.Location) *replication.BinlogParser {
+	p := replication.NewBinlogParser()
+	p.SetParseTime(false)
+	p.SetUseDecimal(false)
+	p.SetTimestampStringLocation(location)
+	return p
+}
+
+// fileBinlogReader reads binlog files from a directory, such as a copy of
+// the files of a MySQL server. Files are read in the order of their
+// numeric extension.
+type fileBinlogReader struct {
+	parser *replication.BinlogParser
+	files  []string
+	first  int
+	start  uint32
+}
+
+// newFileBinlogReader returns a reader for the binlog files in dir,
+// starting at start. If start.File is empty, it starts at the first file.
+func newFileBinlogReader(dir string, start BinlogPosition, location *time.Location) (*fileBinlogReader, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("can't read binlog directory: %v", err)
+	}
+	fr := &fileBinlogReader{parser: newBinlogParser(location)}
+	for _, e := range entries {
+		if !e.IsDir() && binlogFileRe.MatchString(e.Name()) {
+			fr.files = append(fr.files, filepath.Join(dir, e.Name()))
+		}
+	}
+	if len(fr.files) == 0 {
+		return nil, fmt.Errorf("no binlog files found in %s", dir)
+	}
+	// The sequence number isn't always zero-padded to the same width, e.g.
+	// binlog.999999 is followed by binlog.1000000.
+	sort.SliceStable(fr.files, func(i, j int) bool {
+		return binlogFileSeq(fr.files[i]) < binlogFileSeq(fr.files[j])
+	})
+	if start.File == "" {
+		return fr, nil
+	}
+	for i, f := range fr.files {
+		if filepath.Base(f) == start.File {
+			fr.first, fr.start = i, start.Pos
+			return fr, nil
+		}
+	}
+	return nil, fmt.Errorf("binlog file %s not found in %s", start.File, dir)
+}
+
+func (fr *fileBinlogReader) read(ctx context.Context, handle binlogHandler) error {
+	for i := fr.first; i < len(fr.files); i++ {
+		// The start position only applies to the first file read. Events
+		// before it are skipped, except for the format description event
+		// that the others depend on.
+		var offset int64
+		if i == fr.first {
+			offset = int64(fr.start)
+		}
+		file := filepath.Base(fr.files[i])
+		var handleErr error
+		err := fr.parser.ParseFile(fr.files[i], offset, func(e *replication.BinlogEvent) error {
+			if handleErr = ctx.Err(); handleErr == nil {
+				handleErr = handle(file, e)
+			}
+			return handleErr
+		})
+		if handleErr != nil {
+			return handleErr
+		}
+		if err != nil {
+			return fmt.Errorf("can't read binlog file %s: %v", fr.files[i], err)
+		}
+	}
+	return nil
+}
+
+func (fr *fileBinlogReader) close() {}
+
+// streamBinlogReader reads the binlog of a MySQL server over a
+// replication connection, as a replica does. It only stops when ctx is
+// done, since the server waits for new events.
+type streamBinlogReader struct {
+	syncer *replication.BinlogSyncer
+	start  BinlogPosition
+}
+
+// newStreamBinlogReader returns a reader for the binlog of the server of
+// src, starting at start.
+func newStreamBinlogReader(src BinlogSource, start BinlogPosition, location *time.Location) (*streamBinlogReader, error) {
+	port, err := strconv.ParseUint(src.Port, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %q: %v", src.Port, err)
+	}
+	syncer := replication.NewBinlogSyncer(replication.BinlogSyncerConfig{
+		ServerID:                src.ServerId,
+		Flavor:                  gomysql.MySQLFlavor,
+		Host:                    src.Host,
+		Port:                    uint16(port),
+		User:                    src.User,
+		Password:                src.Password,
+		TimestampStringLocation: location,
+	})
+	return &streamBinlogReader{syncer: syncer, start: start}, nil
+}
+
+func (sr *streamBinlogReader) read(ctx context.Context, handle binlogHandler) error {
+	streamer, err := sr.syncer.StartSync(gomysql.Position{Name: sr.start.File, Pos: sr.start.Pos})
+	if err != nil {
+		return fmt.Errorf("can't start replication at %s: %v", sr.start, err)
+	}
+	// The server starts with a rotate event naming the file of start, and
+	// sends one whenever it moves on to the next file.
+	file := sr.start.File
+	for {
+		e, err := streamer.GetEvent(ctx)
+		if err != nil {
+			return err
+		}
+		if rotate, ok := e.Event.(*replication.RotateEvent); ok {
+			file = string(rotate.NextLogName)
+		}
+		if err := handle(file, e); err != nil {
+			return err
+		}
+	}
+}
+
+func (sr *streamBinlogReader) close() {
+	sr.syncer.Close()
+}
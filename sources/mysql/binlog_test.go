@@ -0,0 +1,203 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gomysql "github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/stretchr/testify/assert"
+)
+
+// binlogWriter builds binlog events, as written by a server with
+// binlog_checksum=CRC32.
+type binlogWriter struct {
+	pos    uint32
+	events [][]byte
+}
+
+func (w *binlogWriter) event(typ replication.EventType, body []byte) []byte {
+	if w.pos == 0 {
+		w.pos = uint32(len(replication.BinLogFileHeader))
+	}
+	size := uint32(replication.EventHeaderSize + len(body) + 4)
+	w.pos += size
+	e := binary.LittleEndian.AppendUint32(nil, 1700000000)
+	e = append(e, byte(typ))
+	e = binary.LittleEndian.AppendUint32(e, 1)
+	e = binary.LittleEndian.AppendUint32(e, size)
+	e = binary.LittleEndian.AppendUint32(e, w.pos)
+	e = binary.LittleEndian.AppendUint16(e, 0)
+	e = append(e, body...)
+	e = binary.LittleEndian.AppendUint32(e, crc32.ChecksumIEEE(e))
+	w.events = append(w.events, e)
+	return e
+}
+
+func (w *binlogWriter) formatDescription() {
+	body := binary.LittleEndian.AppendUint16(nil, 4)
+	version := make([]byte, 50)
+	copy(version, "8.0.36")
+	body = append(body, version...)
+	body = append(body, make([]byte, 4)...)
+	body = append(body, replication.EventHeaderSize)
+	body = append(body, make([]byte, 40)...) // Post-header lengths.
+	body = append(body, 1)                   // CRC32.
+	w.event(replication.FORMAT_DESCRIPTION_EVENT, body)
+}
+
+// tableMap writes a table map event for table id, with column names if
+// names is not nil.
+func (w *binlogWriter) tableMap(id uint64, schema, table string, types []byte, meta []byte, names []string) {
+	body := binary.LittleEndian.AppendUint64(nil, id)[:6]
+	body = append(body, 0, 0)
+	body = append(append(append(body, byte(len(schema))), schema...), 0)
+	body = append(append(append(body, byte(len(table))), table...), 0)
+	body = append(append(body, byte(len(types))), types...)
+	body = append(append(body, byte(len(meta))), meta...)
+	body = append(body, make([]byte, (len(types)+7)/8)...)
+	if names != nil {
+		var opt []byte
+		for _, n := range names {
+			opt = append(append(opt, byte(len(n))), n...)
+		}
+		body = append(append(append(body, byte(replication.TABLE_MAP_OPT_META_COLUMN_NAME)), byte(len(opt))), opt...)
+	}
+	w.event(replication.TABLE_MAP_EVENT, body)
+}
+
+// rows writes a rows event of type typ for table id, whose rows have all
+// n columns.
+func (w *binlogWriter) rows(typ replication.EventType, id uint64, n int, rows ...[]byte) {
+	body := binary.LittleEndian.AppendUint64(nil, id)[:6]
+	body = append(body, 0, 0, 2, 0, byte(n))
+	bitmap := make([]byte, (n+7)/8)
+	for i := 0; i < n; i++ {
+		bitmap[i/8] |= 1 << (i % 8)
+	}
+	body = append(body, bitmap...)
+	if typ == replication.UPDATE_ROWS_EVENTv2 {
+		body = append(body, bitmap...)
+	}
+	for _, r := range rows {
+		body = append(body, r...)
+	}
+	w.event(typ, body)
+}
+
+func (w *binlogWriter) xid() {
+	w.event(replication.XID_EVENT, make([]byte, 8))
+}
+
+func (w *binlogWriter) writeFile(t *testing.T, path string) {
+	data := append([]byte(nil), replication.BinLogFileHeader...)
+	for _, e := range w.events {
+		data = append(data, e...)
+	}
+	assert.Nil(t, os.WriteFile(path, data, 0644))
+	w.events, w.pos = nil, 0
+}
+
+func TestBinlogValue(t *testing.T) {
+	testCases := []struct {
+		name     string
+		typ      byte
+		meta     uint16
+		unsigned bool
+		value    interface{}
+		expected string
+	}{
+		{"tinyint", gomysql.MYSQL_TYPE_TINY, 0, false, int8(-1), "-1"},
+		{"tinyint unsigned", gomysql.MYSQL_TYPE_TINY, 0, true, int8(-1), "255"},
+		{"mediumint", gomysql.MYSQL_TYPE_INT24, 0, false, int32(-2), "-2"},
+		{"mediumint unsigned", gomysql.MYSQL_TYPE_INT24, 0, true, int32(-2), "16777214"},
+		{"int unsigned", gomysql.MYSQL_TYPE_LONG, 0, true, int32(-1), "4294967295"},
+		{"bigint unsigned", gomysql.MYSQL_TYPE_LONGLONG, 0, true, int64(-1), "18446744073709551615"},
+		{"float", gomysql.MYSQL_TYPE_FLOAT, 4, false, float32(0.1), "0.1"},
+		{"double", gomysql.MYSQL_TYPE_DOUBLE, 8, false, 1.5, "1.5"},
+		{"decimal", gomysql.MYSQL_TYPE_NEWDECIMAL, 10<<8 | 2, false, "-1234.56", "-1234.56"},
+		{"datetime", gomysql.MYSQL_TYPE_DATETIME2, 0, false, "2024-01-02 03:04:05", "2024-01-02 03:04:05"},
+		{"year", gomysql.MYSQL_TYPE_YEAR, 0, false, 2024, "2024"},
+		{"zero year", gomysql.MYSQL_TYPE_YEAR, 0, false, 0, "0000"},
+		{"blob", gomysql.MYSQL_TYPE_BLOB, 2, false, []byte{1, 2, 3}, "\x01\x02\x03"},
+		{"bit(1)", gomysql.MYSQL_TYPE_BIT, 1, false, int64(1), "\x01"},
+		{"bit(12)", gomysql.MYSQL_TYPE_BIT, 1<<8 | 4, false, int64(0x123), "\x01\x23"},
+		{"json", gomysql.MYSQL_TYPE_JSON, 4, false, []byte(`{"a":[1,true,"x"]}`), `{"a":[1,true,"x"]}`},
+	}
+	for _, tc := range testCases {
+		tbl := &replication.TableMapEvent{ColumnCount: 1, ColumnType: []byte{tc.typ}, ColumnMeta: []uint16{tc.meta}}
+		s, err := binlogValue(tbl, 0, tc.value, tc.unsigned)
+		assert.Nil(t, err, tc.name)
+		assert.Equal(t, tc.expected, s, tc.name)
+	}
+}
+
+func TestBinlogValueEnumSet(t *testing.T) {
+	tbl := &replication.TableMapEvent{
+		ColumnCount:  2,
+		ColumnType:   []byte{gomysql.MYSQL_TYPE_STRING, gomysql.MYSQL_TYPE_STRING},
+		ColumnMeta:   []uint16{uint16(gomysql.MYSQL_TYPE_ENUM)<<8 | 1, uint16(gomysql.MYSQL_TYPE_SET)<<8 | 1},
+		EnumStrValue: [][][]byte{{[]byte("small"), []byte("large")}},
+		SetStrValue:  [][][]byte{{[]byte("a"), []byte("b"), []byte("c")}},
+	}
+	s, err := binlogValue(tbl, 0, int64(2), false)
+	assert.Nil(t, err)
+	assert.Equal(t, "large", s)
+	s, err = binlogValue(tbl, 1, int64(5), false)
+	assert.Nil(t, err)
+	assert.Equal(t, "a,c", s)
+	tbl.EnumStrValue = nil
+	_, err = binlogValue(tbl, 0, int64(2), false)
+	assert.NotNil(t, err)
+}
+
+func TestFileBinlogReader(t *testing.T) {
+	dir := t.TempDir()
+	w := &binlogWriter{}
+	w.formatDescription()
+	w.xid()
+	w.xid()
+	w.writeFile(t, filepath.Join(dir, "binlog.999999"))
+	w.formatDescription()
+	w.xid()
+	w.writeFile(t, filepath.Join(dir, "binlog.1000000"))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "binlog.index"), []byte("binlog.999999\n"), 0644))
+
+	read := func(start BinlogPosition) []string {
+		r, err := newFileBinlogReader(dir, start, time.UTC)
+		assert.Nil(t, err)
+		defer r.close()
+		var events []string
+		assert.Nil(t, r.read(context.Background(), func(file string, e *replication.BinlogEvent) error {
+			events = append(events, BinlogPosition{File: file, Pos: e.Header.LogPos}.String())
+			return nil
+		}))
+		return events
+	}
+	// Format description events end at 4+19+98+4, XID events are 19+8+4
+	// bytes. binlog.1000000 follows binlog.999999.
+	assert.Equal(t, []string{"binlog.999999:125", "binlog.999999:156", "binlog.999999:187", "binlog.1000000:125", "binlog.1000000:156"}, read(BinlogPosition{}))
+	assert.Equal(t, []string{"binlog.999999:125", "binlog.999999:187", "binlog.1000000:125", "binlog.1000000:156"}, read(BinlogPosition{File: "binlog.999999", Pos: 156}))
+	assert.Equal(t, []string{"binlog.1000000:125", "binlog.1000000:156"}, read(BinlogPosition{File: "binlog.1000000", Pos: 125}))
+	_, err := newFileBinlogReader(dir, BinlogPosition{File: "binlog.1000001", Pos: 4}, time.UTC)
+	assert.NotNil(t, err)
+}
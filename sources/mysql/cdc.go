@@ -0,0 +1,310 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	sp "cloud.google.com/go/spanner"
+	"github.com/go-mysql-org/go-mysql/replication"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+)
+
+// BinlogPosition is a position in the binlog of a MySQL server.
+type BinlogPosition struct {
+	File string `json:"file"`
+	Pos  uint32 `json:"pos"`
+}
+
+func (p BinlogPosition) String() string {
+	return fmt.Sprintf("%s:%d", p.File, p.Pos)
+}
+
+// BinlogSource describes where to read a binlog from: the MySQL server at
+// Host and Port, or the binlog files in Dir if it is set.
+type BinlogSource struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	// ServerId identifies the migration as a replica of the server, and
+	// must differ from the server ids of its other replicas.
+	ServerId uint32
+	Dir      string
+}
+
+// LoadBinlogPosition returns the binlog position saved in store, or nil if
+// there is none.
+func LoadBinlogPosition(store internal.CheckpointStore) (*BinlogPosition, error) {
+	data, err := store.Load()
+	if err != nil || data == nil {
+		return nil, err
+	}
+	var pos BinlogPosition
+	if err := json.Unmarshal(data, &pos); err != nil {
+		return nil, fmt.Errorf("can't parse binlog position in %s: %v", store, err)
+	}
+	return &pos, nil
+}
+
+// SaveBinlogPosition saves pos in store.
+func SaveBinlogPosition(store internal.CheckpointStore, pos BinlogPosition) error {
+	data, err := json.Marshal(pos)
+	if err != nil {
+		return err
+	}
+	return store.Save(data)
+}
+
+// GetBinlogPosition returns the current binlog position of the server
+// db is connected to.
+func GetBinlogPosition(db *sql.DB) (BinlogPosition, error) {
+	var pos BinlogPosition
+	// SHOW BINARY LOG STATUS replaces SHOW MASTER STATUS in MySQL 8.4.
+	for _, q := range []string{"SHOW MASTER STATUS", "SHOW BINARY LOG STATUS"} {
+		rows, err := db.Query(q)
+		if err != nil {
+			continue
+		}
+		defer rows.Close()
+		cols, err := rows.Columns()
+		if err != nil {
+			return pos, err
+		}
+		if !rows.Next() {
+			return pos, fmt.Errorf("binary logging is not enabled on the source database")
+		}
+		vals := make([]interface{}, len(cols))
+		vals[0], vals[1] = &pos.File, &pos.Pos
+		for i := 2; i < len(cols); i++ {
+			vals[i] = new(sql.RawBytes)
+		}
+		return pos, rows.Scan(vals...)
+	}
+	return pos, fmt.Errorf("can't get the binlog position of the source database")
+}
+
+// ReplicateBinlog applies the row changes made to the tables of database
+// dbName to Spanner, as described by the binlog in src from position start.
+// Changes are written with a common.ChangeWriter, which converts rows with
+// ConvertData, in binlog order. The changes of a source transaction are
+// applied when it commits, after which the binlog position is saved in
+// store, so that a later call can restart from it. With a live server,
+// ReplicateBinlog only returns when ctx is done or on error; with binlog
+// files, it returns once they have all been read.
+//
+// Rows that can't be converted are reported as bad rows. In dry-run mode,
+// client can be nil and no changes are applied.
+func ReplicateBinlog(ctx context.Context, conv *internal.Conv, dbName string, src BinlogSource, start BinlogPosition, store internal.CheckpointStore, client *sp.Client, additionalAttributes internal.AdditionalDataAttributes) error {
+	var r binlogReader
+	var err error
+	location := timezoneLocation(conv.TimezoneOffset)
+	if src.Dir != "" {
+		r, err = newFileBinlogReader(src.Dir, start, location)
+	} else {
+		r, err = newStreamBinlogReader(src, start, location)
+	}
+	if err != nil {
+		return err
+	}
+	defer r.close()
+	c := newBinlogCDC(conv, dbName, store, common.ApplyWithClient(client), additionalAttributes)
+	return c.run(ctx, r)
+}
+
+// timezoneLocation returns the timezone for a UTC offset such as "+05:30".
+func timezoneLocation(offset string) *time.Location {
+	t, err := time.Parse("-07:00", offset)
+	if err != nil {
+		return time.UTC
+	}
+	return t.Location()
+}
+
+// binlogCDC applies binlog events to Spanner.
+type binlogCDC struct {
+	conv   *internal.Conv
+	dbName string
+	store  internal.CheckpointStore
+	writer *common.ChangeWriter
+}
+
+func newBinlogCDC(conv *internal.Conv, dbName string, store internal.CheckpointStore, apply common.ApplyFunc, additionalAttributes internal.AdditionalDataAttributes) *binlogCDC {
+	convert := func(tableId string, colIds []string, vals []string) (string, []string, []interface{}, error) {
+		return ConvertData(conv, tableId, colIds, conv.SrcSchema[tableId], conv.SpSchema[tableId], vals, additionalAttributes)
+	}
+	return &binlogCDC{
+		conv:   conv,
+		dbName: dbName,
+		store:  store,
+		writer: common.NewChangeWriter(conv, convert, "NULL", apply),
+	}
+}
+
+func (c *binlogCDC) run(ctx context.Context, r binlogReader) error {
+	err := r.read(ctx, func(file string, e *replication.BinlogEvent) error {
+		return c.handle(ctx, file, e)
+	})
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+func (c *binlogCDC) handle(ctx context.Context, file string, e *replication.BinlogEvent) error {
+	pos := BinlogPosition{File: file, Pos: e.Header.LogPos}
+	switch ev := e.Event.(type) {
+	case *replication.RowsEvent:
+		if string(ev.Table.Schema) == c.dbName {
+			return c.handleRows(e.Header.EventType, ev)
+		}
+	case *replication.QueryEvent:
+		q := strings.ToUpper(strings.TrimSpace(string(ev.Query)))
+		switch {
+		case q == "COMMIT":
+			return c.commit(ctx, pos)
+		case q != "BEGIN" && q != "ROLLBACK":
+			// Schema changes aren't replicated: the Spanner schema is
+			// the one in conv.
+			logger.Log.Warn(fmt.Sprintf("Ignoring statement in binlog at %s: %s", pos, ev.Query))
+		}
+	case *replication.XIDEvent:
+		return c.commit(ctx, pos)
+	}
+	return nil
+}
+
+func (c *binlogCDC) handleRows(typ replication.EventType, ev *replication.RowsEvent) error {
+	srcTable := string(ev.Table.Table)
+	if _, ok := c.writer.Migrated(srcTable); !ok {
+		return nil
+	}
+	colNames, unsigned := c.srcColumns(ev.Table)
+	row := func(i int) ([]string, []string, error) {
+		// Update events hold the rows before and after the update, whose
+		// columns are given by the first and second bitmap.
+		present := ev.ColumnBitmap1
+		if i%2 == 1 && isUpdateRowsEvent(typ) {
+			present = ev.ColumnBitmap2
+		}
+		srcCols, vals, err := c.srcRow(ev.Table, colNames, unsigned, present, ev.Rows[i])
+		if err != nil {
+			return nil, nil, fmt.Errorf("can't decode row of table %s: %v", srcTable, err)
+		}
+		return srcCols, vals, nil
+	}
+	step := 1
+	if isUpdateRowsEvent(typ) {
+		step = 2
+	}
+	for i := 0; i+step <= len(ev.Rows); i += step {
+		srcCols, vals, err := row(i)
+		if err != nil {
+			return err
+		}
+		switch {
+		case isUpdateRowsEvent(typ):
+			newCols, newVals, err := row(i + 1)
+			if err != nil {
+				return err
+			}
+			c.writer.Update(srcTable, srcCols, vals, newCols, newVals)
+		case typ == replication.WRITE_ROWS_EVENTv0 || typ == replication.WRITE_ROWS_EVENTv1 || typ == replication.WRITE_ROWS_EVENTv2:
+			c.writer.Insert(srcTable, srcCols, vals)
+		case typ == replication.DELETE_ROWS_EVENTv0 || typ == replication.DELETE_ROWS_EVENTv1 || typ == replication.DELETE_ROWS_EVENTv2:
+			c.writer.Delete(srcTable, srcCols, vals)
+		}
+	}
+	return nil
+}
+
+func isUpdateRowsEvent(typ replication.EventType) bool {
+	return typ == replication.UPDATE_ROWS_EVENTv0 || typ == replication.UPDATE_ROWS_EVENTv1 || typ == replication.UPDATE_ROWS_EVENTv2
+}
+
+// srcColumns returns the column names of t and whether they are unsigned.
+// Servers before MySQL 8.0 write neither to the binlog, so they are then
+// taken from the source schema.
+func (c *binlogCDC) srcColumns(t *replication.TableMapEvent) ([]string, map[int]bool) {
+	colNames, unsigned := t.ColumnNameString(), t.UnsignedMap()
+	if len(colNames) > 0 && unsigned != nil {
+		return colNames, unsigned
+	}
+	tableId, err := internal.GetTableIdFromSrcName(c.conv.SrcSchema, string(t.Table))
+	if err != nil {
+		return colNames, unsigned
+	}
+	srcSchema := c.conv.SrcSchema[tableId]
+	if len(colNames) == 0 {
+		for _, colId := range srcSchema.ColIds {
+			colNames = append(colNames, srcSchema.ColDefs[colId].Name)
+		}
+	}
+	if unsigned == nil {
+		unsigned = make(map[int]bool)
+		for i, colId := range srcSchema.ColIds {
+			if strings.Contains(srcSchema.ColDefs[colId].Type.Name, "unsigned") {
+				unsigned[i] = true
+			}
+		}
+	}
+	return colNames, unsigned
+}
+
+// srcRow returns the source column names and values of row, for the
+// columns set in bitmap present. NULLs are represented as "NULL" as by the
+// MySQL driver.
+func (c *binlogCDC) srcRow(t *replication.TableMapEvent, colNames []string, unsigned map[int]bool, present []byte, row []interface{}) ([]string, []string, error) {
+	var srcCols, vals []string
+	for i, v := range row {
+		if i >= len(colNames) || i/8 >= len(present) || present[i/8]&(1<<uint(i%8)) == 0 {
+			continue
+		}
+		srcCols = append(srcCols, colNames[i])
+		if v == nil {
+			vals = append(vals, "NULL")
+			continue
+		}
+		s, err := binlogValue(t, i, v, unsigned[i])
+		if err != nil {
+			return nil, nil, fmt.Errorf("column %s: %v", colNames[i], err)
+		}
+		vals = append(vals, s)
+	}
+	return srcCols, vals, nil
+}
+
+// commit applies the mutations of the current transaction and saves pos
+// as the position to restart from.
+func (c *binlogCDC) commit(ctx context.Context, pos BinlogPosition) error {
+	if err := c.writer.Commit(ctx); err != nil {
+		return fmt.Errorf("can't apply changes of the transaction ending at %s: %v", pos, err)
+	}
+	if c.store == nil {
+		return nil
+	}
+	if err := SaveBinlogPosition(c.store, pos); err != nil {
+		return fmt.Errorf("can't save binlog position to %s: %v", c.store, err)
+	}
+	return nil
+}
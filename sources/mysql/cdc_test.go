@@ -0,0 +1,152 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	sp "cloud.google.com/go/spanner"
+	gomysql "github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+func TestReplicateBinlog(t *testing.T) {
+	conv := internal.MakeConv()
+	conv.SetDataMode()
+	conv.SrcSchema["t1"] = schema.Table{
+		Id:          "t1",
+		Name:        "items",
+		ColIds:      []string{"c1", "c2", "c3"},
+		PrimaryKeys: []schema.Key{{ColId: "c1"}},
+		ColDefs: map[string]schema.Column{
+			"c1": {Id: "c1", Name: "id", Type: schema.Type{Name: "int"}},
+			"c2": {Id: "c2", Name: "name", Type: schema.Type{Name: "varchar"}},
+			"c3": {Id: "c3", Name: "price", Type: schema.Type{Name: "decimal"}},
+		},
+	}
+	conv.SpSchema["t1"] = ddl.CreateTable{
+		Id:          "t1",
+		Name:        "items",
+		ColIds:      []string{"c1", "c2", "c3"},
+		PrimaryKeys: []ddl.IndexKey{{ColId: "c1"}},
+		ColDefs: map[string]ddl.ColumnDef{
+			"c1": {Id: "c1", Name: "id", T: ddl.Type{Name: ddl.Int64}},
+			"c2": {Id: "c2", Name: "name", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+			"c3": {Id: "c3", Name: "price", T: ddl.Type{Name: ddl.Float64}},
+		},
+	}
+	conv.SrcSchema["t2"] = schema.Table{
+		Id:      "t2",
+		Name:    "logs",
+		ColIds:  []string{"c4"},
+		ColDefs: map[string]schema.Column{"c4": {Id: "c4", Name: "msg", Type: schema.Type{Name: "varchar"}}},
+	}
+	conv.SpSchema["t2"] = ddl.CreateTable{
+		Id:          "t2",
+		Name:        "logs",
+		ColIds:      []string{"c4", "c5"},
+		PrimaryKeys: []ddl.IndexKey{{ColId: "c5"}},
+		ColDefs: map[string]ddl.ColumnDef{
+			"c4": {Id: "c4", Name: "msg", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+			"c5": {Id: "c5", Name: "synth_id", T: ddl.Type{Name: ddl.String, Len: 50}},
+		},
+	}
+	conv.SyntheticPKeys["t2"] = internal.SyntheticPKey{ColId: "c5"}
+	conv.ToSpanner["items"] = internal.NameAndCols{Name: "items", Cols: map[string]string{"id": "id", "name": "name", "price": "price"}}
+	conv.ToSpanner["logs"] = internal.NameAndCols{Name: "logs", Cols: map[string]string{"msg": "msg"}}
+
+	// Rows of items: id INT, name VARCHAR(20), price DOUBLE.
+	item := func(id byte, name string, price []byte) []byte {
+		row := []byte{0}
+		if name == "" {
+			row[0] = 0x02 // name is NULL.
+		}
+		row = append(row, id, 0, 0, 0)
+		if name != "" {
+			row = append(append(row, byte(len(name))), name...)
+		}
+		return append(row, price...)
+	}
+	price := []byte{0, 0, 0, 0, 0, 0, 0xf8, 0x3f} // 1.5
+	log := func(msg string) []byte {
+		return append([]byte{0, byte(len(msg))}, msg...)
+	}
+	itemTypes, itemMeta := []byte{gomysql.MYSQL_TYPE_LONG, gomysql.MYSQL_TYPE_VARCHAR, gomysql.MYSQL_TYPE_DOUBLE}, []byte{20, 0, 8}
+	dir := t.TempDir()
+	w := &binlogWriter{}
+	w.formatDescription()
+	w.tableMap(7, "db", "items", itemTypes, itemMeta, []string{"id", "name", "price"})
+	w.tableMap(8, "db", "logs", []byte{gomysql.MYSQL_TYPE_VARCHAR}, []byte{20, 0}, nil)
+	w.tableMap(9, "other", "items", itemTypes, itemMeta, nil)
+	w.rows(replication.WRITE_ROWS_EVENTv2, 7, 3, item(1, "a", price), item(2, "", price))
+	w.rows(replication.WRITE_ROWS_EVENTv2, 8, 1, log("hi"))
+	w.rows(replication.WRITE_ROWS_EVENTv2, 9, 3, item(5, "x", price))
+	w.xid()
+	w.rows(replication.UPDATE_ROWS_EVENTv2, 7, 3, item(1, "a", price), item(1, "b", price), item(2, "", price), item(3, "", price))
+	w.rows(replication.DELETE_ROWS_EVENTv2, 7, 3, item(3, "", price))
+	w.rows(replication.DELETE_ROWS_EVENTv2, 8, 1, log("hi"))
+	w.xid()
+	end := w.pos
+	w.writeFile(t, filepath.Join(dir, "binlog.000001"))
+
+	var applied [][]*sp.Mutation
+	store := &internal.FileCheckpointStore{Path: filepath.Join(dir, "position.json")}
+	apply := func(ctx context.Context, ms []*sp.Mutation) error {
+		applied = append(applied, ms)
+		return nil
+	}
+	c := newBinlogCDC(conv, "db", store, apply, internal.AdditionalDataAttributes{})
+	r, err := newFileBinlogReader(dir, BinlogPosition{}, time.UTC)
+	assert.Nil(t, err)
+	assert.Nil(t, c.run(context.Background(), r))
+
+	cols := []string{"id", "name", "price"}
+	nullName := []string{"id", "price", "name"}
+	assert.Equal(t, [][]*sp.Mutation{
+		{
+			sp.InsertOrUpdate("items", cols, []interface{}{int64(1), "a", 1.5}),
+			sp.InsertOrUpdate("items", nullName, []interface{}{int64(2), 1.5, nil}),
+			sp.InsertOrUpdate("logs", []string{"msg", "synth_id"}, []interface{}{"hi", "0"}),
+		},
+		{
+			sp.InsertOrUpdate("items", cols, []interface{}{int64(1), "b", 1.5}),
+			sp.Delete("items", sp.Key{int64(2)}),
+			sp.InsertOrUpdate("items", nullName, []interface{}{int64(3), 1.5, nil}),
+			sp.Delete("items", sp.Key{int64(3)}),
+		},
+	}, applied)
+	assert.Equal(t, int64(5), conv.Stats.Rows["items"])
+	assert.Equal(t, int64(5), conv.Stats.GoodRows["items"])
+	assert.Equal(t, int64(2), conv.Stats.Rows["logs"])
+	assert.Equal(t, int64(1), conv.Stats.BadRows["logs"])
+	pos, err := LoadBinlogPosition(store)
+	assert.Nil(t, err)
+	assert.Equal(t, &BinlogPosition{File: "binlog.000001", Pos: end}, pos)
+
+	// Restarting from the saved position applies nothing again.
+	applied = nil
+	r, err = newFileBinlogReader(dir, *pos, time.UTC)
+	assert.Nil(t, err)
+	assert.Nil(t, c.run(context.Background(), r))
+	assert.Nil(t, applied)
+}
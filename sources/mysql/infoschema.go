@@ -55,6 +55,12 @@ func (isi InfoSchemaImpl) GetTableName(dbName string, tableName string) string {
 
 // GetRowsFromTable returns a sql Rows object for a table.
 func (isi InfoSchemaImpl) GetRowsFromTable(conv *internal.Conv, tableId string) (interface{}, error) {
+	return isi.getRowsFromTable(conv, tableId, nil)
+}
+
+// getRowsFromTable returns a sql Rows object for the rows of a table in
+// keyRange, or for the whole table if keyRange is nil.
+func (isi InfoSchemaImpl) getRowsFromTable(conv *internal.Conv, tableId string, keyRange *internal.KeyRange) (interface{}, error) {
 	srcSchema := conv.SrcSchema[tableId]
 	srcCols := []string{}
 
@@ -69,11 +75,40 @@ func (isi InfoSchemaImpl) GetRowsFromTable(conv *internal.Conv, tableId string)
 	// Ideally we would pass schema/name as a query parameter,
 	// but MySQL doesn't support this. So we quote it instead.
 	colNameList := buildColNameList(srcSchema, srcCols)
-	q := fmt.Sprintf("SELECT %s FROM `%s`.`%s`;", colNameList, isi.DbName, srcSchema.Name)
-	rows, err := isi.Db.Query(q)
+	q := fmt.Sprintf("SELECT %s FROM `%s`.`%s`", colNameList, isi.DbName, srcSchema.Name)
+	var keyCol string
+	if keyRange != nil {
+		keyCol = "`" + srcSchema.ColDefs[keyRange.ColId].Name + "`"
+	}
+	quoteCol := func(c string) string { return "`" + c + "`" }
+	where, limit := common.ReadPredicate(conv, tableId, quoteCol, quoteString)
+	q += common.SelectClause(where, keyRange, keyCol)
+	if limit > 0 {
+		q += fmt.Sprintf(" LIMIT %d", limit)
+	}
+	rows, err := isi.Db.Query(q + ";")
 	return rows, err
 }
 
+// quoteString returns s as a MySQL string literal, in which backslashes
+// are escape characters.
+func quoteString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", "''").Replace(s) + "'"
+}
+
+// GetKeyBounds returns the smallest and largest values of column colId
+// of a table, so that the table can be read in key ranges.
+func (isi InfoSchemaImpl) GetKeyBounds(conv *internal.Conv, tableId string, colId string) (int64, int64, bool, error) {
+	srcSchema := conv.SrcSchema[tableId]
+	col := srcSchema.ColDefs[colId].Name
+	q := fmt.Sprintf("SELECT MIN(`%s`), MAX(`%s`) FROM `%s`.`%s`;", col, col, isi.DbName, srcSchema.Name)
+	var min, max sql.NullInt64
+	if err := isi.Db.QueryRow(q).Scan(&min, &max); err != nil {
+		return 0, 0, false, err
+	}
+	return min.Int64, max.Int64, min.Valid && max.Valid, nil
+}
+
 // Building list of column names to support mysql spatial datatypes instead of
 // using 'SELECT *' because spatial columns will be fetched using ST_AsText(colName).
 func buildColNameList(srcSchema schema.Table, srcColName []string) string {
@@ -94,12 +129,17 @@ func buildColNameList(srcSchema schema.Table, srcColName []string) string {
 	return colList[:len(colList)-1]
 }
 
-// ProcessData performs data conversion for source database.
+// ProcessData performs data conversion for source database. Only the
+// rows in additionalAttributes.KeyRange are read when it is set. ProcessData
+// may be called concurrently (see common.KeyRangeInfoSchema), so conv is
+// only updated while holding conv.ConvLock.
 func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSchema schema.Table, commonColIds []string, spSchema ddl.CreateTable, additionalAttributes internal.AdditionalDataAttributes) error {
 	srcTableName := conv.SrcSchema[tableId].Name
-	rowsInterface, err := isi.GetRowsFromTable(conv, tableId)
+	rowsInterface, err := isi.getRowsFromTable(conv, tableId, additionalAttributes.KeyRange)
 	if err != nil {
+		conv.ConvLock.Lock()
 		conv.Unexpected(fmt.Sprintf("Couldn't get data for table %s : err = %s", srcTableName, err))
+		conv.ConvLock.Unlock()
 		return err
 	}
 	rows := rowsInterface.(*sql.Rows)
@@ -107,36 +147,51 @@ func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSc
 	srcCols, _ := rows.Columns()
 	v, scanArgs := buildVals(len(srcCols))
 	colNameIdMap := internal.GetSrcColNameIdMap(conv.SrcSchema[tableId])
+	var cvtSrcCols []string
+	for _, colId := range commonColIds {
+		cvtSrcCols = append(cvtSrcCols, srcSchema.ColDefs[colId].Name)
+	}
+	// Rows are converted and written without holding conv.ConvLock, so
+	// that the key ranges of a table are processed in parallel.
 	for rows.Next() {
 		// get RawBytes from data.
 		err := rows.Scan(scanArgs...)
 		if err != nil {
+			conv.ConvLock.Lock()
 			conv.Unexpected(fmt.Sprintf("Couldn't process sql data row: %s", err))
 			// Scan failed, so we don't have any data to add to bad rows.
 			conv.StatsAddBadRow(srcTableName, conv.DataMode())
+			conv.ConvLock.Unlock()
 			continue
 		}
 		values := valsToStrings(v)
-
 		newValues, err := common.PrepareValues(conv, tableId, colNameIdMap, commonColIds, srcCols, values)
 		if err != nil {
-			conv.Unexpected(fmt.Sprintf("Error while converting data: %s\n", err))
-			conv.StatsAddBadRow(srcTableName, conv.DataMode())
-			conv.CollectBadRow(srcTableName, srcCols, values)
+			conv.BadRowConcurrently(srcTableName, srcCols, values, err)
 			continue
 		}
-
-		ProcessDataRow(conv, tableId, commonColIds, srcSchema, spSchema, newValues, additionalAttributes)
+		spTableName, cvtCols, cvtVals, err := ConvertData(conv, tableId, commonColIds, srcSchema, spSchema, newValues, additionalAttributes)
+		if err != nil {
+			conv.BadRowConcurrently(srcTableName, cvtSrcCols, newValues, err)
+			continue
+		}
+		conv.WriteRowConcurrently(srcTableName, spTableName, cvtCols, cvtVals)
 	}
 	return nil
 }
 
 // GetRowCount with number of rows in each table.
 func (isi InfoSchemaImpl) GetRowCount(table common.SchemaAndName) (int64, error) {
+	return isi.GetFilteredRowCount(table, "")
+}
+
+// GetFilteredRowCount returns the number of rows of a table that satisfy
+// where, or of all its rows if where is "".
+func (isi InfoSchemaImpl) GetFilteredRowCount(table common.SchemaAndName, where string) (int64, error) {
 	// MySQL schema and name can be arbitrary strings.
 	// Ideally we would pass schema/name as a query parameter,
 	// but MySQL doesn't support this. So we quote it instead.
-	q := fmt.Sprintf("SELECT COUNT(*) FROM `%s`.`%s`;", table.Schema, table.Name)
+	q := fmt.Sprintf("SELECT COUNT(*) FROM `%s`.`%s`%s;", table.Schema, table.Name, common.SelectClause(where, nil, ""))
 	rows, err := isi.Db.Query(q)
 	if err != nil {
 		return 0, err
@@ -176,6 +231,26 @@ func (isi InfoSchemaImpl) GetTables() ([]common.SchemaAndName, error) {
 
 
 
+// GetViews returns the views of the database.
+func (isi InfoSchemaImpl) GetViews(conv *internal.Conv) ([]schema.View, error) {
+	q := "SELECT table_name, view_definition FROM information_schema.views WHERE table_schema = ?"
+	rows, err := isi.Db.Query(q, isi.DbName)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get views: %w", err)
+	}
+	defer rows.Close()
+	var name, definition string
+	var views []schema.View
+	for rows.Next() {
+		if err := rows.Scan(&name, &definition); err != nil {
+			conv.Unexpected(fmt.Sprintf("Can't scan: %v", err))
+			continue
+		}
+		views = append(views, schema.View{Name: isi.GetTableName(isi.DbName, name), Schema: isi.DbName, Definition: definition})
+	}
+	return views, nil
+}
+
 // GetColumnsBatch returns a list of Column objects and names for a batch of tables.
 func (isi InfoSchemaImpl) GetColumnsBatch(conv *internal.Conv, tables []common.SchemaAndName) (map[string]common.TableColumns, error) {
 	if len(tables) == 0 {
@@ -603,7 +678,7 @@ func (isi InfoSchemaImpl) GetIndexesBatch(conv *internal.Conv, tables []common.S
 		placeholders[i] = "?"
 	}
 
-	q := fmt.Sprintf(`SELECT DISTINCT TABLE_NAME, INDEX_NAME,COLUMN_NAME,SEQ_IN_INDEX,COLLATION,NON_UNIQUE
+	q := fmt.Sprintf(`SELECT DISTINCT TABLE_NAME, INDEX_NAME,COLUMN_NAME,SEQ_IN_INDEX,COLLATION,NON_UNIQUE,INDEX_TYPE
 		FROM INFORMATION_SCHEMA.STATISTICS 
 		WHERE TABLE_SCHEMA = ?
 			AND TABLE_NAME IN (%s)
@@ -622,13 +697,13 @@ func (isi InfoSchemaImpl) GetIndexesBatch(conv *internal.Conv, tables []common.S
 	}
 	defer rows.Close()
 
-	var tableName, name, column, sequence, nonUnique string
+	var tableName, name, column, sequence, nonUnique, indexType string
 	var collation sql.NullString
 	indexMap := make(map[string]map[string]schema.Index)
 	indexNames := make(map[string][]string)
 
 	for rows.Next() {
-		if err := rows.Scan(&tableName, &name, &column, &sequence, &collation, &nonUnique); err != nil {
+		if err := rows.Scan(&tableName, &name, &column, &sequence, &collation, &nonUnique, &indexType); err != nil {
 			conv.Unexpected(fmt.Sprintf("Can't scan: %v", err))
 			continue
 		}
@@ -638,9 +713,10 @@ func (isi InfoSchemaImpl) GetIndexesBatch(conv *internal.Conv, tables []common.S
 		if _, found := indexMap[tableName][name]; !found {
 			indexNames[tableName] = append(indexNames[tableName], name)
 			indexMap[tableName][name] = schema.Index{
-				Id:     internal.GenerateIndexesId(),
-				Name:   name,
-				Unique: (nonUnique == "0"),
+				Id:       internal.GenerateIndexesId(),
+				Name:     name,
+				Unique:   (nonUnique == "0"),
+				FullText: (indexType == "FULLTEXT"),
 			}
 		}
 		index := indexMap[tableName][name]
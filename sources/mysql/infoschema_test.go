@@ -144,13 +144,14 @@ func TestProcessSchemaMYSQL(t *testing.T) {
 		{
 			query: "(?is)SELECT DISTINCT TABLE_NAME, (.+) FROM INFORMATION_SCHEMA.STATISTICS (.+) table_name IN \\(.+\\) (.+)",
 			args:  []driver.Value{"test", "user", "cart", "product", "test", "test_ref"},
-			cols:  []string{"table_name", "index_name", "column_name", "seq_in_index", "collation", "non_unique"},
+			cols:  []string{"table_name", "index_name", "column_name", "seq_in_index", "collation", "non_unique", "index_type"},
 			rows: [][]driver.Value{
-				{"cart", "index1", "userid", 1, sql.NullString{Valid: false}, "0"},
-				{"cart", "index2", "userid", 1, "A", "1"},
-				{"cart", "index2", "productid", 2, "D", "1"},
-				{"cart", "index3", "productid", 1, "A", "0"},
-				{"cart", "index3", "userid", 2, "D", "0"},
+				{"cart", "index1", "userid", 1, sql.NullString{Valid: false}, "0", "BTREE"},
+				{"cart", "index2", "userid", 1, "A", "1", "BTREE"},
+				{"cart", "index2", "productid", 2, "D", "1", "BTREE"},
+				{"cart", "index3", "productid", 1, "A", "0", "BTREE"},
+				{"cart", "index3", "userid", 2, "D", "0", "BTREE"},
+				{"cart", "index4", "productid", 1, sql.NullString{Valid: false}, "1", "FULLTEXT"},
 			},
 		},
 	}
@@ -170,7 +171,7 @@ func TestProcessSchemaMYSQL(t *testing.T) {
 			},
 			PrimaryKeys: []schema.Key{{ColId: "productid", Desc: false, Order: 0}, {ColId: "userid", Desc: false, Order: 0}},
 			ForeignKeys: []schema.ForeignKey{{Name: "fk_test2", ColIds: []string{"productid"}, ReferTableId: "product", ReferColumnIds: []string{"product_id"}, OnDelete: constants.FK_NO_ACTION, OnUpdate: constants.FK_NO_ACTION, Id: ""}, {Name: "fk_test3", ColIds: []string{"userid"}, ReferTableId: "user", ReferColumnIds: []string{"user_id"}, OnUpdate: constants.FK_SET_NULL, OnDelete: constants.FK_RESTRICT, Id: ""}},
-			Indexes:     []schema.Index{{Name: "index1", Unique: true, Keys: []schema.Key{{ColId: "userid", Desc: false, Order: 0}}, Id: "", StoredColumnIds: []string(nil)}, {Name: "index2", Unique: false, Keys: []schema.Key{{ColId: "userid", Desc: false, Order: 0}, {ColId: "productid", Desc: true, Order: 0}}, Id: "", StoredColumnIds: []string(nil)}, {Name: "index3", Unique: true, Keys: []schema.Key{{ColId: "productid", Desc: false, Order: 0}, {ColId: "userid", Desc: true, Order: 0}}, Id: "", StoredColumnIds: []string(nil)}}, Id: "",
+			Indexes:     []schema.Index{{Name: "index1", Unique: true, Keys: []schema.Key{{ColId: "userid", Desc: false, Order: 0}}, Id: "", StoredColumnIds: []string(nil)}, {Name: "index2", Unique: false, Keys: []schema.Key{{ColId: "userid", Desc: false, Order: 0}, {ColId: "productid", Desc: true, Order: 0}}, Id: "", StoredColumnIds: []string(nil)}, {Name: "index3", Unique: true, Keys: []schema.Key{{ColId: "productid", Desc: false, Order: 0}, {ColId: "userid", Desc: true, Order: 0}}, Id: "", StoredColumnIds: []string(nil)}, {Name: "index4", Keys: []schema.Key{{ColId: "productid"}}, FullText: true}}, Id: "",
 		},
 
 		"product": {
@@ -268,7 +269,7 @@ func TestProcessSchemaMYSQLPKOrdering(t *testing.T) {
 		{
 			query: "(?is)SELECT DISTINCT TABLE_NAME, (.+) FROM INFORMATION_SCHEMA.STATISTICS (.+) table_name IN \\(.+\\) (.+)",
 			args:  []driver.Value{"test", "pk_order"},
-			cols:  []string{"table_name", "index_name", "column_name", "seq_in_index", "collation", "non_unique"},
+			cols:  []string{"table_name", "index_name", "column_name", "seq_in_index", "collation", "non_unique", "index_type"},
 		},
 	}
 	db := mkMockDB(t, ms)
@@ -399,7 +400,7 @@ func TestProcessData_MultiCol(t *testing.T) {
 		{
 			query: "(?is)SELECT DISTINCT TABLE_NAME, (.+) FROM INFORMATION_SCHEMA.STATISTICS (.+) table_name IN \\(.+\\) (.+)",
 			args:  []driver.Value{"test", "test"},
-			cols:  []string{"table_name", "index_name", "column_name", "seq_in_index", "collation", "non_unique"},
+			cols:  []string{"table_name", "index_name", "column_name", "seq_in_index", "collation", "non_unique", "index_type"},
 		},
 		{
 			query: "SELECT (.+) FROM `test`.`test`",
@@ -559,7 +560,7 @@ func TestProcessSchema_Sharded(t *testing.T) {
 		{
 			query: "(?is)SELECT DISTINCT TABLE_NAME, (.+) FROM INFORMATION_SCHEMA.STATISTICS (.+) table_name IN \\(.+\\) (.+)",
 			args:  []driver.Value{"test", "test"},
-			cols:  []string{"table_name", "index_name", "column_name", "seq_in_index", "collation", "non_unique"},
+			cols:  []string{"table_name", "index_name", "column_name", "seq_in_index", "collation", "non_unique", "index_type"},
 		},
 		{
 			query: "SELECT (.+) FROM `test`.`test`",
@@ -648,6 +649,63 @@ func TestProcessSchema_Sharded(t *testing.T) {
 	internal.AssertSpSchema(conv, t, expectedSchema, stripSchemaComments(conv.SpSchema))
 }
 
+func TestProcessData_KeyRanges(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	// Key ranges are read concurrently, so their queries arrive in any order.
+	mock.MatchExpectationsInOrder(false)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT MIN(`id`), MAX(`id`) FROM `test`.`t`;")).
+		WillReturnRows(sqlmock.NewRows([]string{"min", "max"}).AddRow(1, 9))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `id`,`v` FROM `test`.`t` WHERE `id` < 5;")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "v"}).AddRow(1, "a"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `id`,`v` FROM `test`.`t` WHERE `id` >= 5;")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "v"}).AddRow(9, "b"))
+	conv := buildConv(
+		ddl.CreateTable{
+			Name:   "t",
+			Id:     "t1",
+			ColIds: []string{"c1", "c2"},
+			ColDefs: map[string]ddl.ColumnDef{
+				"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}},
+				"c2": {Name: "v", Id: "c2", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+			},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c1"}},
+		},
+		schema.Table{
+			Name:   "t",
+			Id:     "t1",
+			Schema: "test",
+			ColIds: []string{"c1", "c2"},
+			ColDefs: map[string]schema.Column{
+				"c1": {Name: "id", Id: "c1", Type: schema.Type{Name: "int"}},
+				"c2": {Name: "v", Id: "c2", Type: schema.Type{Name: "text"}},
+			},
+			PrimaryKeys: []schema.Key{{ColId: "c1"}},
+			ColNameIdMap: map[string]string{
+				"id": "c1",
+				"v":  "c2",
+			},
+		})
+	conv.SetDataMode()
+	conv.BulkRead = internal.BulkReadOptions{ParallelTables: 1, ChunksPerTable: 2}
+	var rows []spannerData
+	conv.SetDataSink(
+		func(table string, cols []string, vals []interface{}) {
+			rows = append(rows, spannerData{table: table, cols: cols, vals: vals})
+		})
+	isi := InfoSchemaImpl{"test", db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}}
+	commonInfoSchema := common.InfoSchemaImpl{}
+	commonInfoSchema.ProcessData(conv, isi, internal.AdditionalDataAttributes{})
+	assert.ElementsMatch(t,
+		[]spannerData{
+			{table: "t", cols: []string{"id", "v"}, vals: []interface{}{int64(1), "a"}},
+			{table: "t", cols: []string{"id", "v"}, vals: []interface{}{int64(9), "b"}},
+		},
+		rows)
+	assert.Nil(t, mock.ExpectationsWereMet())
+	assert.Equal(t, int64(0), conv.Unexpecteds())
+}
+
 func TestSetRowStats(t *testing.T) {
 	ms := []mockSpec{
 		{
@@ -822,3 +880,19 @@ func TestBuildColumn(t *testing.T) {
 	c = buildColumn(conv, colId, colName, "int", "int(11)", "NO", sql.NullString{Valid: false}, sql.NullString{Valid: true, String: "auto_increment"}, sql.NullString{Valid: false}, sql.NullInt64{Valid: false}, sql.NullInt64{Valid: false}, sql.NullInt64{Valid: false})
 	assert.Equal(t, constants.AUTO_INCREMENT, c.AutoGen.Name)
 }
+
+func TestGetViews(t *testing.T) {
+	ms := []mockSpec{
+		{
+			query: regexp.QuoteMeta("SELECT table_name, view_definition FROM information_schema.views WHERE table_schema = ?"),
+			args:  []driver.Value{"test"},
+			cols:  []string{"table_name", "view_definition"},
+			rows:  [][]driver.Value{{"user_ids", "select `test`.`user`.`user_id` AS `user_id` from `test`.`user`"}},
+		},
+	}
+	db := mkMockDB(t, ms)
+	isi := InfoSchemaImpl{"test", db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}}
+	views, err := isi.GetViews(internal.MakeConv())
+	assert.NoError(t, err)
+	assert.Equal(t, []schema.View{{Name: "user_ids", Schema: "test", Definition: "select `test`.`user`.`user_id` AS `user_id` from `test`.`user`"}}, views)
+}
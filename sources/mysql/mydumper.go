@@ -0,0 +1,249 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/task"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+)
+
+// mydumperMetadataFile is the file in which mydumper records the
+// position of the dump and, in recent versions, the rows of each table.
+const mydumperMetadataFile = "metadata"
+
+// Sections of the metadata file describing a table are headed by
+// [`db`.`table`].
+var mydumperTableSectionRegexp = regexp.MustCompile("^\\[`(.+)`\\.`(.+)`\\]$")
+var mydumperRowsRegexp = regexp.MustCompile("(?i)^rows\\s*=\\s*(\\d+)$")
+
+// MydumperDir describes the files of a directory written by mydumper for
+// a single database. Schema files are named <db>.<table>-schema.sql and
+// data files <db>.<table>.sql, or <db>.<table>.<chunk>.sql when mydumper
+// splits the rows of a table over several files.
+type MydumperDir struct {
+	Path     string
+	Database string
+	// SchemaFiles holds the CREATE TABLE files, sorted by name.
+	SchemaFiles []string
+	// DataFiles maps a table to its data files, sorted by name.
+	DataFiles map[string][]string
+	// Rows maps a table to its number of rows, for the tables listed in
+	// the metadata file.
+	Rows map[string]int64
+	// Bytes is the total size of the schema and data files.
+	Bytes int64
+}
+
+// ReadMydumperDir lists the schema and data files of the mydumper
+// directory dir and reads its metadata file, if any.
+func ReadMydumperDir(dir string) (*MydumperDir, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("can't read mydumper directory %s: %v", dir, err)
+	}
+	d := &MydumperDir{Path: dir, DataFiles: make(map[string][]string), Rows: make(map[string]int64)}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() {
+			continue
+		}
+		// Files written with --compress are decompressed as they are read.
+		base := internal.TrimCompressionExt(name)
+		if !strings.HasSuffix(base, ".sql") {
+			continue
+		}
+		base = strings.TrimSuffix(base, ".sql")
+		if strings.HasSuffix(base, "-schema-create") || strings.HasSuffix(base, "-schema-post") {
+			// Database creation, routines and events.
+			continue
+		}
+		db, table, ok := strings.Cut(base, ".")
+		if !ok {
+			logger.Log.Info(fmt.Sprintf("Skipping file %s of mydumper directory %s", name, dir))
+			continue
+		}
+		if d.Database == "" {
+			d.Database = db
+		} else if d.Database != db {
+			return nil, fmt.Errorf("mydumper directory %s holds several databases (%s and %s), please dump a single database", dir, d.Database, db)
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, fmt.Errorf("can't read mydumper file %s: %v", name, err)
+		}
+		switch {
+		case strings.HasSuffix(table, "-schema"):
+			d.SchemaFiles = append(d.SchemaFiles, name)
+		case strings.Contains(table, "-schema-"):
+			// Views and triggers.
+			continue
+		default:
+			table = trimChunkSuffix(table)
+			d.DataFiles[table] = append(d.DataFiles[table], name)
+		}
+		d.Bytes += info.Size()
+	}
+	if len(d.SchemaFiles) == 0 {
+		return nil, fmt.Errorf("no schema files found in mydumper directory %s", dir)
+	}
+	sort.Strings(d.SchemaFiles)
+	for _, files := range d.DataFiles {
+		sort.Strings(files)
+	}
+	if err := d.readMetadata(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// trimChunkSuffix removes the chunk numbers from the name of a data file
+// without its extension, e.g. "table.00001.00002" becomes "table".
+func trimChunkSuffix(table string) string {
+	for {
+		i := strings.LastIndex(table, ".")
+		if i < 0 {
+			return table
+		}
+		if _, err := strconv.Atoi(table[i+1:]); err != nil {
+			return table
+		}
+		table = table[:i]
+	}
+}
+
+// readMetadata reads the rows of each table from the metadata file
+// written by mydumper 0.11 and later. Older versions don't record rows,
+// and their metadata file is ignored.
+func (d *MydumperDir) readMetadata() error {
+	f, err := os.Open(filepath.Join(d.Path, mydumperMetadataFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("can't read mydumper metadata file: %v", err)
+	}
+	defer f.Close()
+	table := ""
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if strings.HasPrefix(line, "[") {
+			table = ""
+			if m := mydumperTableSectionRegexp.FindStringSubmatch(line); m != nil && m[1] == d.Database {
+				table = m[2]
+			}
+			continue
+		}
+		if m := mydumperRowsRegexp.FindStringSubmatch(line); m != nil && table != "" {
+			n, err := strconv.ParseInt(m[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("can't parse rows of table %s in mydumper metadata file: %v", table, err)
+			}
+			d.Rows[table] = n
+		}
+	}
+	return s.Err()
+}
+
+// SchemaDump returns the contents of the schema files, one after the
+// other, so that they can be processed as a single mysqldump file.
+func (d *MydumperDir) SchemaDump() ([]byte, error) {
+	var b bytes.Buffer
+	for _, name := range d.SchemaFiles {
+		if err := d.readFile(name, &b); err != nil {
+			return nil, fmt.Errorf("can't read mydumper schema file %s: %v", name, err)
+		}
+		b.WriteString("\n")
+	}
+	return b.Bytes(), nil
+}
+
+// readFile writes the contents of the file name of the directory to w,
+// decompressed if the file is compressed.
+func (d *MydumperDir) readFile(name string, w io.Writer) error {
+	f, err := os.Open(filepath.Join(d.Path, name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r, err := internal.Decompress(f, name)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	_, err = io.Copy(w, r)
+	return err
+}
+
+// SetRowStats sets the rows of each table in conv. Rows are taken from the
+// metadata file if it lists the table, otherwise they are counted by
+// processing the data files of the table in schema mode.
+func (d *MydumperDir) SetRowStats(conv *internal.Conv, workers int) error {
+	var files []string
+	for table, tableFiles := range d.DataFiles {
+		if n, ok := d.Rows[table]; ok {
+			conv.Stats.Rows[table] = n
+			continue
+		}
+		files = append(files, tableFiles...)
+	}
+	sort.Strings(files)
+	return d.processFiles(conv, files, workers)
+}
+
+// ProcessData processes the data files in parallel, writing their rows
+// to the data sink of conv.
+func (d *MydumperDir) ProcessData(conv *internal.Conv, workers int) error {
+	var files []string
+	for _, tableFiles := range d.DataFiles {
+		files = append(files, tableFiles...)
+	}
+	sort.Strings(files)
+	return d.processFiles(conv, files, workers)
+}
+
+func (d *MydumperDir) processFiles(conv *internal.Conv, files []string, workers int) error {
+	processFile := func(name string, mutex *sync.Mutex) task.TaskResult[string] {
+		f, err := os.Open(filepath.Join(d.Path, name))
+		if err != nil {
+			return task.TaskResult[string]{Result: name, Err: fmt.Errorf("can't open mydumper file %s: %v", name, err)}
+		}
+		defer f.Close()
+		r, err := internal.NewCompressedReader(f, name, nil)
+		if err != nil {
+			return task.TaskResult[string]{Result: name, Err: fmt.Errorf("can't read mydumper file %s: %v", name, err)}
+		}
+		if err := (DbDumpImpl{}).ProcessDump(conv, r); err != nil {
+			return task.TaskResult[string]{Result: name, Err: fmt.Errorf("can't process mydumper file %s: %v", name, err)}
+		}
+		return task.TaskResult[string]{Result: name}
+	}
+	r := task.RunParallelTasksImpl[string, string]{}
+	_, err := r.RunParallelTasks(files, workers, processFile, true)
+	return err
+}
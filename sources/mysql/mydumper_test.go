@@ -0,0 +1,134 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+)
+
+func writeMydumperDir(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, contents := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644))
+	}
+	return dir
+}
+
+func TestReadMydumperDir(t *testing.T) {
+	dir := writeMydumperDir(t, map[string]string{
+		"metadata": "[config]\nquote_character = BACKTICK\n\n" +
+			"[`shop`.`orders`]\nreal_table_name=orders\nrows = 3\n\n" +
+			"[`other`.`orders`]\nrows = 7\n",
+		"shop-schema-create.sql":          "CREATE DATABASE `shop`;\n",
+		"shop.customers-schema.sql":       "CREATE TABLE `customers` (`id` bigint NOT NULL, PRIMARY KEY (`id`));\n",
+		"shop.orders-schema.sql":          "CREATE TABLE `orders` (`id` bigint NOT NULL, PRIMARY KEY (`id`));",
+		"shop.orders-schema-triggers.sql": "CREATE TRIGGER t BEFORE INSERT ON `orders` FOR EACH ROW SET @x = 1;\n",
+		"shop.recent-schema-view.sql":     "CREATE VIEW `recent` AS SELECT 1;\n",
+		"shop.customers.sql":              "INSERT INTO `customers` VALUES (1),(2);\n",
+		"shop.orders.00001.sql":           "INSERT INTO `orders` VALUES (2);\n",
+		"shop.orders.00000.sql":           "INSERT INTO `orders` VALUES (1);\n",
+		"shop.orders.00002.00001.sql":     "INSERT INTO `orders` VALUES (3);\n",
+		"README":                          "not a dump file",
+	})
+	d, err := ReadMydumperDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "shop", d.Database)
+	assert.Equal(t, []string{"shop.customers-schema.sql", "shop.orders-schema.sql"}, d.SchemaFiles)
+	assert.Equal(t, map[string][]string{
+		"customers": {"shop.customers.sql"},
+		"orders":    {"shop.orders.00000.sql", "shop.orders.00001.sql", "shop.orders.00002.00001.sql"},
+	}, d.DataFiles)
+	assert.Equal(t, map[string]int64{"orders": 3}, d.Rows)
+
+	schema, err := d.SchemaDump()
+	require.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE `customers` (`id` bigint NOT NULL, PRIMARY KEY (`id`));\n\n"+
+		"CREATE TABLE `orders` (`id` bigint NOT NULL, PRIMARY KEY (`id`));\n", string(schema))
+}
+
+func TestReadMydumperDirErrors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		files map[string]string
+	}{
+		{"no schema files", map[string]string{"shop.t.sql": "INSERT INTO `t` VALUES (1);\n"}},
+		{"several databases", map[string]string{
+			"shop.t-schema.sql": "CREATE TABLE `t` (`id` bigint);\n",
+			"crm.t-schema.sql":  "CREATE TABLE `t` (`id` bigint);\n",
+		}},
+	}
+	for _, tc := range testCases {
+		_, err := ReadMydumperDir(writeMydumperDir(t, tc.files))
+		assert.Error(t, err, tc.name)
+	}
+	_, err := ReadMydumperDir(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func TestReadMydumperDirCompressed(t *testing.T) {
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	gw.Write([]byte("CREATE TABLE `customers` (`id` bigint NOT NULL, PRIMARY KEY (`id`));"))
+	require.NoError(t, gw.Close())
+	zw, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	zst := zw.EncodeAll([]byte("CREATE TABLE `orders` (`id` bigint NOT NULL, PRIMARY KEY (`id`));"), nil)
+
+	dir := writeMydumperDir(t, map[string]string{
+		"shop.customers-schema.sql.gz": gz.String(),
+		"shop.orders-schema.sql.zst":   string(zst),
+		"shop.customers.sql.gz":        "",
+		"shop.orders.00000.sql.zst":    "",
+	})
+	d, err := ReadMydumperDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"shop.customers-schema.sql.gz", "shop.orders-schema.sql.zst"}, d.SchemaFiles)
+	assert.Equal(t, map[string][]string{
+		"customers": {"shop.customers.sql.gz"},
+		"orders":    {"shop.orders.00000.sql.zst"},
+	}, d.DataFiles)
+
+	schema, err := d.SchemaDump()
+	require.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE `customers` (`id` bigint NOT NULL, PRIMARY KEY (`id`));\n"+
+		"CREATE TABLE `orders` (`id` bigint NOT NULL, PRIMARY KEY (`id`));\n", string(schema))
+}
+
+func TestMydumperSetRowStatsFromMetadata(t *testing.T) {
+	d := &MydumperDir{
+		DataFiles: map[string][]string{"orders": {"shop.orders.00000.sql"}},
+		Rows:      map[string]int64{"orders": 42, "empty": 0},
+	}
+	conv := internal.MakeConv()
+	require.NoError(t, d.SetRowStats(conv, 2))
+	assert.Equal(t, int64(42), conv.Stats.Rows["orders"])
+}
+
+func TestTrimChunkSuffix(t *testing.T) {
+	assert.Equal(t, "orders", trimChunkSuffix("orders"))
+	assert.Equal(t, "orders", trimChunkSuffix("orders.00003"))
+	assert.Equal(t, "orders", trimChunkSuffix("orders.00003.00001"))
+	assert.Equal(t, "v1.orders", trimChunkSuffix("v1.orders.00002"))
+}
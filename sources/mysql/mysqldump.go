@@ -88,7 +88,9 @@ func processMySQLDump(conv *internal.Conv, r *internal.Reader) error {
 			break
 		}
 	}
+	conv.ConvLock.Lock()
 	internal.ResolveForeignKeyIds(conv.SrcSchema)
+	conv.ConvLock.Unlock()
 	return nil
 }
 
@@ -136,7 +138,9 @@ func readAndParseChunk(conv *internal.Conv, r *internal.Reader) ([]byte, []ast.S
 			//remember the last error if it was not nil
 			lastError = err
 
+			conv.ConvLock.Lock()
 			newTree, ok := handleParseError(conv, chunk, err, l)
+			conv.ConvLock.Unlock()
 			if ok {
 				return s, newTree, nil
 			}
@@ -145,7 +149,9 @@ func readAndParseChunk(conv *internal.Conv, r *internal.Reader) ([]byte, []ast.S
 			// b) a semicolon embedded in a multi-line comment, or
 			// c) a semicolon embedded a string constant or column/table name.
 			// We deal with this case by reading another line and trying again.
+			conv.ConvLock.Lock()
 			conv.Stats.Reparsed++
+			conv.ConvLock.Unlock()
 		}
 		if r.EOF {
 			return nil, nil, fmt.Errorf("could not parse last %d line(s) of input due to error: %w", len(l), lastError)
@@ -157,6 +163,15 @@ func readAndParseChunk(conv *internal.Conv, r *internal.Reader) ([]byte, []ast.S
 // statements, updating Conv with new schema information, and returning
 // true if INSERT statement is encountered.
 func processStatement(conv *internal.Conv, stmt ast.StmtNode) bool {
+	// Files of a mydumper directory are processed concurrently, so conv
+	// is only updated while holding conv.ConvLock. processInsertStmt
+	// takes it itself, so that it isn't held while writing rows.
+	if s, ok := stmt.(*ast.InsertStmt); ok {
+		processInsertStmt(conv, s)
+		return true
+	}
+	conv.ConvLock.Lock()
+	defer conv.ConvLock.Unlock()
 	switch s := stmt.(type) {
 	case *ast.CreateTableStmt:
 		if conv.SchemaMode() {
@@ -170,13 +185,14 @@ func processStatement(conv *internal.Conv, stmt ast.StmtNode) bool {
 		if conv.SchemaMode() {
 			processSetStmt(conv, s)
 		}
-	case *ast.InsertStmt:
-		processInsertStmt(conv, s)
-		return true
 	case *ast.CreateIndexStmt:
 		if conv.SchemaMode() {
 			processCreateIndex(conv, s)
 		}
+	case *ast.CreateViewStmt:
+		if conv.SchemaMode() {
+			processCreateView(conv, s)
+		}
 	default:
 		conv.SkipStatement(NodeType(stmt))
 	}
@@ -197,10 +213,11 @@ func processCreateIndex(conv *internal.Conv, stmt *ast.CreateIndexStmt) {
 	if tbl, ok := internal.GetSrcTableByName(conv.SrcSchema, tableName); ok {
 		ctable := conv.SrcSchema[tbl.Id]
 		ctable.Indexes = append(ctable.Indexes, schema.Index{
-			Id:     internal.GenerateIndexesId(),
-			Name:   stmt.IndexName,
-			Unique: (stmt.KeyType == ast.IndexKeyTypeUnique),
-			Keys:   toSchemaKeys(stmt.IndexPartSpecifications, tbl.ColNameIdMap),
+			Id:       internal.GenerateIndexesId(),
+			Name:     stmt.IndexName,
+			Unique:   (stmt.KeyType == ast.IndexKeyTypeUnique),
+			Keys:     toSchemaKeys(stmt.IndexPartSpecifications, tbl.ColNameIdMap),
+			FullText: (stmt.KeyType == ast.IndexKeyTypeFullText),
 		})
 		conv.SrcSchema[tbl.Id] = ctable
 	} else {
@@ -234,6 +251,31 @@ func processSetStmt(conv *internal.Conv, stmt *ast.SetStmt) {
 	}
 }
 
+// processCreateView adds a view to conv. mysqldump first defines a stand-in
+// table or view for each view, so that views can read from views defined
+// later in the dump: the view replaces it.
+func processCreateView(conv *internal.Conv, stmt *ast.CreateViewStmt) {
+	if stmt.ViewName == nil || stmt.Select == nil {
+		logStmtError(conv, stmt, fmt.Errorf("view name or query is nil"))
+		return
+	}
+	viewName, err := getTableName(stmt.ViewName)
+	if err != nil {
+		logStmtError(conv, stmt, fmt.Errorf("can't get view name: %w", err))
+		return
+	}
+	var sb strings.Builder
+	restoreCtx := format.NewRestoreCtx(format.RestoreStringSingleQuotes|format.RestoreKeyWordUppercase|format.RestoreNameBackQuotes, &sb)
+	if err := stmt.Select.Restore(restoreCtx); err != nil {
+		logStmtError(conv, stmt, fmt.Errorf("can't restore query of view %s: %w", viewName, err))
+		return
+	}
+	if tbl, ok := internal.GetSrcTableByName(conv.SrcSchema, viewName); ok {
+		delete(conv.SrcSchema, tbl.Id)
+	}
+	common.AddSrcView(conv, schema.View{Name: viewName, Definition: sb.String()})
+}
+
 func processCreateTable(conv *internal.Conv, stmt *ast.CreateTableStmt) {
 	if stmt.Table == nil {
 		logStmtError(conv, stmt, fmt.Errorf("table is nil"))
@@ -332,6 +374,10 @@ func processConstraint(conv *internal.Conv, tableId string, constraint *ast.Cons
 		// Convert unique column constraint in mysql to a corresponding unique index in schema
 		// Note that schema represents all unique constraints as indexes.
 		st.Indexes = append(st.Indexes, schema.Index{Name: constraint.Name, Id: idxId, Unique: true, Keys: toSchemaKeys(constraint.Keys, colNameToIdMap)})
+	case ast.ConstraintFulltext:
+		idxId := internal.GenerateIndexesId()
+		// FULLTEXT indexes are converted to Spanner search indexes.
+		st.Indexes = append(st.Indexes, schema.Index{Name: constraint.Name, Id: idxId, Keys: toSchemaKeys(constraint.Keys, colNameToIdMap), FullText: true})
 	default:
 		updateCols(conv, ct, constraint.Keys, st.ColDefs, colNameToIdMap)
 	}
@@ -775,27 +821,56 @@ func getArrayBounds(ft string, elem []string) []int64 {
 }
 
 func processInsertStmt(conv *internal.Conv, stmt *ast.InsertStmt) {
+	conv.ConvLock.Lock()
+	ins, ok := prepareInsert(conv, stmt)
+	conv.ConvLock.Unlock()
+	if !ok {
+		return
+	}
+	for _, row := range stmt.Lists {
+		values, err := getVals(row)
+		spTable, cvtCols, cvtVals, ok := ins.convertRow(conv, values, err)
+		if ok {
+			conv.WriteRowConcurrently(ins.srcSchema.Name, spTable, cvtCols, cvtVals)
+		}
+	}
+}
+
+// insert holds what is needed to convert the rows of an INSERT statement.
+type insert struct {
+	tableId      string
+	srcSchema    schema.Table
+	spSchema     ddl.CreateTable
+	srcCols      []string
+	commonColIds []string
+	colNameIdMap map[string]string
+}
+
+// prepareInsert returns the insert for the rows of stmt, or false if
+// there are no rows to convert. It must be called while holding
+// conv.ConvLock.
+func prepareInsert(conv *internal.Conv, stmt *ast.InsertStmt) (insert, bool) {
 	if stmt.Table == nil {
 		logStmtError(conv, stmt, fmt.Errorf("source table is nil"))
-		return
+		return insert{}, false
 	}
 	srcTable, err := getTableNameInsert(stmt.Table)
 	if err != nil {
 		logStmtError(conv, stmt, fmt.Errorf("can't get source table name: %w", err))
-		return
+		return insert{}, false
 	}
 	tableId, _ := internal.GetTableIdFromSrcName(conv.SrcSchema, srcTable)
 	if conv.SchemaMode() {
 		conv.Stats.Rows[srcTable] += int64(len(stmt.Lists))
 		conv.DataStatement(NodeType(stmt))
-		return
+		return insert{}, false
 	}
 
 	srcSchema, ok2 := conv.SrcSchema[tableId]
 	if !ok2 {
 		conv.Unexpected(fmt.Sprintf("Can't get schemas for table %s", conv.SrcSchema[tableId].Name))
 		conv.Stats.BadRows[srcTable] += conv.Stats.Rows[srcTable]
-		return
+		return insert{}, false
 	}
 	srcColIds := []string{}
 	srcCols, err2 := getCols(stmt)
@@ -809,7 +884,7 @@ func processInsertStmt(conv *internal.Conv, stmt *ast.InsertStmt) {
 		if len(srcColIds) == 0 {
 			conv.Unexpected(fmt.Sprintf("Can't get columns for table %s", srcTable))
 			conv.Stats.BadRows[srcTable] += conv.Stats.Rows[srcTable]
-			return
+			return insert{}, false
 		}
 	} else {
 		for _, srcColName := range srcCols {
@@ -818,26 +893,44 @@ func processInsertStmt(conv *internal.Conv, stmt *ast.InsertStmt) {
 		}
 	}
 
-	var values []string
 	if stmt.Lists == nil {
 		logStmtError(conv, stmt, fmt.Errorf("can't get column values"))
-		return
+		return insert{}, false
+	}
+	return insert{
+		tableId:      tableId,
+		srcSchema:    srcSchema,
+		spSchema:     conv.SpSchema[tableId],
+		srcCols:      srcCols,
+		commonColIds: common.IntersectionOfTwoStringSlices(conv.SpSchema[tableId].ColIds, srcColIds),
+		colNameIdMap: internal.GetSrcColNameIdMap(conv.SrcSchema[tableId]),
+	}, true
+}
+
+// convertRow converts the values of a row of ins to Spanner values, as
+// ProcessDataRow does, and records the row as bad if it can't be
+// converted. err is the error from getting values. It is called without
+// holding conv.ConvLock, so that the files of a mydumper export are
+// converted in parallel.
+func (ins insert) convertRow(conv *internal.Conv, values []string, err error) (string, []string, []interface{}, bool) {
+	var newValues []string
+	if err == nil {
+		newValues, err = common.PrepareValues(conv, ins.tableId, ins.colNameIdMap, ins.commonColIds, ins.srcCols, values)
 	}
-	commonColIds := common.IntersectionOfTwoStringSlices(conv.SpSchema[tableId].ColIds, srcColIds)
-	spSchema := conv.SpSchema[tableId]
-	colNameIdMap := internal.GetSrcColNameIdMap(conv.SrcSchema[tableId])
-	for _, row := range stmt.Lists {
-		values, err = getVals(row)
-		//prepare values
-		newValues, err2 := common.PrepareValues(conv, tableId, colNameIdMap, commonColIds, srcCols, values)
-		if err2 != nil {
-			conv.Unexpected(fmt.Sprintf("Error while converting data: %s\n", err))
-			conv.StatsAddBadRow(srcSchema.Name, conv.DataMode())
-			conv.CollectBadRow(srcSchema.Name, srcCols, values)
-			continue
+	if err != nil {
+		conv.BadRowConcurrently(ins.srcSchema.Name, ins.srcCols, values, err)
+		return "", nil, nil, false
+	}
+	spTable, cvtCols, cvtVals, err3 := ConvertData(conv, ins.tableId, ins.commonColIds, ins.srcSchema, ins.spSchema, newValues, internal.AdditionalDataAttributes{ShardId: ""})
+	if err3 != nil {
+		srcCols := []string{}
+		for _, colId := range ins.commonColIds {
+			srcCols = append(srcCols, ins.srcSchema.ColDefs[colId].Name)
 		}
-		ProcessDataRow(conv, tableId, commonColIds, srcSchema, spSchema, newValues, internal.AdditionalDataAttributes{ShardId: ""})
+		conv.BadRowConcurrently(ins.srcSchema.Name, srcCols, newValues, err3)
+		return "", nil, nil, false
 	}
+	return spTable, cvtCols, cvtVals, true
 }
 
 func getCols(stmt *ast.InsertStmt) ([]string, error) {
@@ -903,7 +903,44 @@ func TestProcessMySQLDump_GetDDL(t *testing.T) {
 			"	quantity INT64,\n" +
 			") PRIMARY KEY (productid, userid)"
 	c := ddl.Config{Tables: true}
-	assert.Equal(t, expected, strings.Join(ddl.GetDDL(c, conv.SpSchema, conv.SpSequences, conv.DatabaseOptions), " "))
+	assert.Equal(t, expected, strings.Join(ddl.GetDDL(c, conv.SpSchema, conv.SpSequences, conv.SpViews, conv.DatabaseOptions), " "))
+}
+
+func TestProcessMySQLDump_Views(t *testing.T) {
+	conv, _ := runProcessMySQLDump("CREATE TABLE cart (productid text, userid text, quantity bigint);\n" +
+		"ALTER TABLE cart ADD CONSTRAINT cart_pkey PRIMARY KEY (productid, userid);\n" +
+		// Stand-ins for the views, as dumped by older and newer versions of mysqldump.
+		"CREATE TABLE `big_carts` (`productid` tinyint NOT NULL);\n" +
+		"/*!50001 CREATE VIEW `user_carts` AS SELECT 1 AS `userid`*/;\n" +
+		"/*!50001 DROP VIEW IF EXISTS `big_carts`*/;\n" +
+		"/*!50001 CREATE ALGORITHM=UNDEFINED */\n" +
+		"/*!50013 DEFINER=`root`@`localhost` SQL SECURITY DEFINER */\n" +
+		"/*!50001 VIEW `big_carts` AS select `cart`.`productid` AS `productid` from `cart` where (`cart`.`quantity` > 10) */;\n" +
+		"/*!50001 CREATE VIEW `user_carts` AS select `cart`.`userid` AS `userid` from `cart` */;")
+	assert.Len(t, conv.SrcSchema, 1)
+	assert.Len(t, conv.SrcViews, 2)
+	assert.Len(t, conv.SpViews, 2)
+	for _, v := range conv.SpViews {
+		assert.Contains(t, []string{"big_carts", "user_carts"}, v.Name)
+		assert.Contains(t, v.Query, "FROM `cart`")
+	}
+}
+
+func TestProcessMySQLDump_FullText(t *testing.T) {
+	conv, _ := runProcessMySQLDump("CREATE TABLE articles (id bigint NOT NULL PRIMARY KEY, title varchar(200), body text, FULLTEXT KEY ft_title_body (title, body));\n" +
+		"CREATE FULLTEXT INDEX ft_body ON articles (body);")
+	expected :=
+		"CREATE TABLE articles (\n" +
+			"	id INT64 NOT NULL ,\n" +
+			"	title STRING(200),\n" +
+			"	body STRING(MAX),\n" +
+			"	title_Tokens TOKENLIST AS (TOKENIZE_FULLTEXT(`title`)) HIDDEN,\n" +
+			"	body_Tokens TOKENLIST AS (TOKENIZE_FULLTEXT(`body`)) HIDDEN,\n" +
+			") PRIMARY KEY (id) " +
+			"CREATE SEARCH INDEX ft_title_body ON articles (title_Tokens, body_Tokens) " +
+			"CREATE SEARCH INDEX ft_body ON articles (body_Tokens)"
+	c := ddl.Config{Tables: true}
+	assert.Equal(t, expected, strings.Join(ddl.GetDDL(c, conv.SpSchema, conv.SpSequences, conv.SpViews, conv.DatabaseOptions), " "))
 }
 
 func TestProcessMySQLDump_Rows(t *testing.T) {
@@ -0,0 +1,244 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/format"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/types"
+	driver "github.com/pingcap/tidb/pkg/types/parser_driver"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+)
+
+// viewFunctions maps the lower-cased names of the MySQL functions that
+// can't be used as they are in Spanner.
+var viewFunctions = map[string]common.ViewFunction{
+	"curdate":      {GoogleSql: "CURRENT_DATE"},
+	"date_format":  {},
+	"datediff":     {},
+	"group_concat": {},
+	"if":           {GoogleSql: "IF"},
+	"ifnull":       {GoogleSql: "IFNULL", PostgreSql: "COALESCE"},
+	"now":          {GoogleSql: "CURRENT_TIMESTAMP", PostgreSql: "NOW"},
+	"str_to_date":  {},
+}
+
+// backQuotedRegexp matches the quoted identifiers of restored queries.
+var backQuotedRegexp = regexp.MustCompile("`(?:[^`]|``)*`")
+
+// TranslateView implements common.ViewTranslator. The query is parsed and
+// its tables, views and columns are renamed in the syntax tree, which is
+// then restored.
+func (tdi ToDdlImpl) TranslateView(q *common.ViewQuery, query string) string {
+	stmt, err := parser.New().ParseOneStmt(query, "", "")
+	if err != nil {
+		logger.Log.Debug(fmt.Sprintf("Can't parse view query %q: %v", query, err))
+		q.AddIssue(internal.ViewUnsupportedSql)
+		return ""
+	}
+	vv := &viewVisitor{
+		q:       q,
+		rels:    map[*ast.TableName]common.ViewRelation{},
+		aliases: map[string]common.ViewRelation{},
+		names:   map[string]common.ViewRelation{},
+		ctes:    map[string]bool{},
+		renamed: map[*ast.ColumnName]bool{},
+	}
+	// The tables of the query are found first, as columns can be qualified
+	// by their aliases.
+	vv.findTables = true
+	stmt.Accept(vv)
+	for _, name := range vv.missing {
+		if !vv.ctes[name] {
+			q.AddIssue(internal.ViewMissingTable)
+		}
+	}
+	vv.findTables = false
+	stmt.Accept(vv)
+	var sb strings.Builder
+	flags := format.RestoreStringSingleQuotes | format.RestoreStringWithoutCharset | format.RestoreKeyWordUppercase | format.RestoreNameBackQuotes
+	if err := stmt.Restore(format.NewRestoreCtx(flags, &sb)); err != nil {
+		logger.Log.Debug(fmt.Sprintf("Can't restore view query %q: %v", query, err))
+		q.AddIssue(internal.ViewUnsupportedSql)
+		return ""
+	}
+	// String literals are placeholders at this point, so only identifiers
+	// are quoted.
+	return backQuotedRegexp.ReplaceAllStringFunc(sb.String(), func(s string) string {
+		return q.Quote(strings.ReplaceAll(s[1:len(s)-1], "``", "`"))
+	})
+}
+
+// viewVisitor maps the tables, views, columns and functions of the query of
+// a view to Spanner.
+type viewVisitor struct {
+	q          *common.ViewQuery
+	findTables bool // Whether tables are being found, rather than renamed.
+	rels       map[*ast.TableName]common.ViewRelation
+	aliases    map[string]common.ViewRelation // Keyed by lower-cased alias.
+	names      map[string]common.ViewRelation // Tables without alias, keyed by lower-cased name.
+	ctes       map[string]bool                // Lower-cased names of the common table expressions.
+	missing    []string                       // Lower-cased names of the tables not found, unless common table expressions.
+	tableIds   []string                       // The tables the query reads from.
+	renamed    map[*ast.ColumnName]bool
+}
+
+func (vv *viewVisitor) Enter(in ast.Node) (ast.Node, bool) {
+	if vv.findTables {
+		vv.findTable(in)
+		return in, false
+	}
+	switch n := in.(type) {
+	case *ast.TableName:
+		if rel, ok := vv.rels[n]; ok {
+			n.Schema, n.Name = model.CIStr{}, model.NewCIStr(vv.q.Name(rel.SpName))
+		}
+	case *ast.ColumnName:
+		vv.renameColumn(n)
+	case *ast.ColumnNameExpr:
+		vv.renameColumn(n.Name)
+	case *ast.Join:
+		for _, c := range n.Using {
+			vv.renameColumn(c)
+		}
+	case *ast.SelectField:
+		if w := n.WildCard; w != nil && w.Table.L != "" {
+			// The "t.*" of a table.
+			if rel, ok := vv.relation(w.Schema, w.Table); ok {
+				w.Schema, w.Table = model.CIStr{}, model.NewCIStr(vv.q.Name(rel.SpName))
+			}
+		}
+	case *ast.FuncCallExpr:
+		if f, ok := viewFunctions[n.FnName.L]; ok {
+			if name := vv.q.Function(f); name != "" {
+				n.FnName = model.NewCIStr(name)
+			}
+		}
+	case *ast.AggregateFuncExpr:
+		if f, ok := viewFunctions[strings.ToLower(n.F)]; ok {
+			if name := vv.q.Function(f); name != "" {
+				n.F = name
+			}
+		}
+	case *ast.VariableExpr:
+		vv.q.AddIssue(internal.ViewUnsupportedSql)
+	case *driver.ValueExpr:
+		if n.Kind() == types.KindString {
+			n.SetString(vv.q.String(n.GetString()), n.Collation())
+		}
+	}
+	return in, false
+}
+
+func (vv *viewVisitor) Leave(n ast.Node) (ast.Node, bool) {
+	return n, true
+}
+
+func (vv *viewVisitor) findTable(in ast.Node) {
+	switch n := in.(type) {
+	case *ast.SelectStmt:
+		vv.addCtes(n.With)
+	case *ast.SetOprStmt:
+		vv.addCtes(n.With)
+	case *ast.TableSource:
+		t, ok := n.Source.(*ast.TableName)
+		if !ok {
+			return
+		}
+		rel, ok := vv.q.Relation(t.Schema.O, t.Name.O)
+		switch {
+		case !ok && t.Schema.L == "":
+			vv.missing = append(vv.missing, t.Name.L)
+			return
+		case !ok:
+			vv.q.AddIssue(internal.ViewMissingTable)
+			return
+		}
+		vv.rels[t] = rel
+		if rel.TableId != "" {
+			vv.tableIds = append(vv.tableIds, rel.TableId)
+		}
+		if n.AsName.L != "" {
+			vv.aliases[n.AsName.L] = rel
+		} else {
+			vv.names[t.Name.L] = rel
+		}
+	}
+}
+
+func (vv *viewVisitor) addCtes(with *ast.WithClause) {
+	if with == nil {
+		return
+	}
+	for _, cte := range with.CTEs {
+		vv.ctes[cte.Name.L] = true
+	}
+}
+
+// relation looks up the table or view that qualifies a column. Aliases
+// aren't returned, as they aren't renamed.
+func (vv *viewVisitor) relation(schemaName, table model.CIStr) (common.ViewRelation, bool) {
+	if schemaName.L == "" {
+		if _, ok := vv.aliases[table.L]; ok {
+			return common.ViewRelation{}, false
+		}
+		if rel, ok := vv.names[table.L]; ok {
+			return rel, true
+		}
+	}
+	return vv.q.Relation(schemaName.O, table.O)
+}
+
+// renameColumn maps a column to Spanner.
+func (vv *viewVisitor) renameColumn(c *ast.ColumnName) {
+	if c == nil || vv.renamed[c] {
+		return
+	}
+	vv.renamed[c] = true
+	if c.Table.L == "" {
+		for _, tableId := range vv.tableIds {
+			if spCol, ok := vv.q.Column(tableId, c.Name.O); ok {
+				c.Name = model.NewCIStr(vv.q.Name(spCol))
+				return
+			}
+		}
+		return
+	}
+	rel, ok := vv.aliases[c.Table.L]
+	if !ok || c.Schema.L != "" {
+		if rel, ok = vv.relation(c.Schema, c.Table); !ok {
+			return
+		}
+		c.Schema, c.Table = model.CIStr{}, model.NewCIStr(vv.q.Name(rel.SpName))
+	}
+	if rel.TableId == "" {
+		return
+	}
+	spCol, ok := vv.q.Column(rel.TableId, c.Name.O)
+	if !ok {
+		vv.q.AddIssue(internal.ViewMissingTable)
+		return
+	}
+	c.Name = model.NewCIStr(vv.q.Name(spCol))
+}
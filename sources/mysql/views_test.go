@@ -0,0 +1,120 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// getViewsConv returns a conv with a table singers, whose column first_name
+// is named FirstName in Spanner, and a view of it.
+func getViewsConv(dialect, definition string) *internal.Conv {
+	conv := internal.MakeConv()
+	conv.Source = constants.MYSQL
+	conv.SpDialect = dialect
+	conv.SrcSchema["t1"] = schema.Table{
+		Name:   "singers",
+		Schema: "music",
+		Id:     "t1",
+		ColIds: []string{"c1", "c2"},
+		ColDefs: map[string]schema.Column{
+			"c1": {Name: "id", Id: "c1"},
+			"c2": {Name: "first_name", Id: "c2"},
+		},
+	}
+	conv.SpSchema["t1"] = ddl.CreateTable{
+		Name:   "Singers",
+		Id:     "t1",
+		ColIds: []string{"c1", "c2"},
+		ColDefs: map[string]ddl.ColumnDef{
+			"c1": {Name: "id", Id: "c1"},
+			"c2": {Name: "FirstName", Id: "c2"},
+		},
+	}
+	conv.UsedNames["singers"] = true
+	common.AddSrcView(conv, schema.View{Name: "singer_names", Schema: "music", Definition: definition})
+	return conv
+}
+
+func TestTranslateView(t *testing.T) {
+	testCases := []struct {
+		name       string
+		dialect    string
+		definition string
+		want       []string
+	}{
+		{
+			name:       "information schema",
+			dialect:    constants.DIALECT_GOOGLESQL,
+			definition: "select `s`.`id` AS `id`,upper(`s`.`first_name`) AS `upper` from `music`.`singers` `s` where ((`s`.`id` > 0) and (`s`.`first_name` <> 'it''s'))",
+			want:       []string{"`s`.`id`", "UPPER(`s`.`FirstName`)", "FROM `Singers` AS `s`", "'it\\'s'"},
+		},
+		{
+			name:       "postgresql",
+			dialect:    constants.DIALECT_POSTGRESQL,
+			definition: "SELECT singers.id, ifnull(first_name, 'a') AS name, now() AS ts FROM singers JOIN singers t USING (id)",
+			want:       []string{`"Singers"."id"`, `COALESCE("FirstName", 'a')`, `NOW()`, `FROM "Singers" JOIN "Singers" AS "t" USING ("id")`},
+		},
+		{
+			name:       "common table expression",
+			dialect:    constants.DIALECT_GOOGLESQL,
+			definition: "WITH s AS (SELECT * FROM singers) SELECT s.* FROM s",
+			want:       []string{"FROM `Singers`", "FROM `s`"},
+		},
+	}
+	for _, tc := range testCases {
+		conv := getViewsConv(tc.dialect, tc.definition)
+		common.ViewsToSpanner(conv, ToDdlImpl{})
+		assert.Empty(t, conv.SchemaIssues, tc.name)
+		assert.Len(t, conv.SpViews, 1, tc.name)
+		for _, v := range conv.SpViews {
+			for _, want := range tc.want {
+				assert.Contains(t, v.Query, want, tc.name)
+			}
+		}
+	}
+}
+
+func TestTranslateView_Issues(t *testing.T) {
+	testCases := []struct {
+		name       string
+		dialect    string
+		definition string
+		want       internal.SchemaIssue
+	}{
+		{"function", constants.DIALECT_GOOGLESQL, "SELECT date_format(first_name, '%Y') FROM singers", internal.ViewUnsupportedSql},
+		{"aggregate function", constants.DIALECT_GOOGLESQL, "SELECT group_concat(first_name) FROM singers", internal.ViewUnsupportedSql},
+		{"variable", constants.DIALECT_GOOGLESQL, "SELECT @@sql_mode FROM singers", internal.ViewUnsupportedSql},
+		{"syntax error", constants.DIALECT_GOOGLESQL, "SELECT 'a FROM singers", internal.ViewUnsupportedSql},
+		{"missing table", constants.DIALECT_GOOGLESQL, "SELECT id FROM albums", internal.ViewMissingTable},
+		{"missing column", constants.DIALECT_GOOGLESQL, "SELECT s.last_name FROM singers s", internal.ViewMissingTable},
+	}
+	for _, tc := range testCases {
+		conv := getViewsConv(tc.dialect, tc.definition)
+		common.ViewsToSpanner(conv, ToDdlImpl{})
+		assert.Empty(t, conv.SpViews, tc.name)
+		for id := range conv.SrcViews {
+			assert.Equal(t, []internal.SchemaIssue{tc.want}, conv.SchemaIssues[id].TableLevelIssues, tc.name)
+		}
+	}
+}
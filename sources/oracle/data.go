@@ -42,7 +42,7 @@ func ProcessDataRow(conv *internal.Conv, tableId string, colIds []string, srcSch
 	if err != nil {
 		conv.Unexpected(fmt.Sprintf("Error while converting data: %s\n", err))
 		conv.StatsAddBadRow(srcTableName, conv.DataMode())
-		conv.CollectBadRow(srcTableName, srcCols, vals)
+		conv.CollectBadRow(srcTableName, srcCols, vals, err)
 	} else {
 		conv.WriteRow(srcTableName, spTableName, cvtCols, cvtVals)
 	}
@@ -84,11 +84,9 @@ func convertData(conv *internal.Conv, tableId string, colIds []string, srcSchema
 		v = append(v, x)
 		c = append(c, spColDef.Name)
 	}
-	if aux, ok := conv.SyntheticPKeys[tableId]; ok {
+	if aux, ok := conv.NextSyntheticPKey(tableId); ok {
 		c = append(c, conv.SpSchema[tableId].ColDefs[aux.ColId].Name)
 		v = append(v, fmt.Sprintf("%d", int64(bits.Reverse64(uint64(aux.Sequence)))))
-		aux.Sequence++
-		conv.SyntheticPKeys[tableId] = aux
 	}
 	return spSchema.Name, c, v, nil
 }
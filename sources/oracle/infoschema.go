@@ -48,6 +48,12 @@ func (isi InfoSchemaImpl) GetTableName(dbName string, tableName string) string {
 
 // GetRowsFromTable returns a sql Rows object for a table.
 func (isi InfoSchemaImpl) GetRowsFromTable(conv *internal.Conv, tableId string) (interface{}, error) {
+	return isi.getRowsFromTable(conv, tableId, nil)
+}
+
+// getRowsFromTable returns a sql Rows object for the rows of a table in
+// keyRange, or for the whole table if keyRange is nil.
+func (isi InfoSchemaImpl) getRowsFromTable(conv *internal.Conv, tableId string, keyRange *internal.KeyRange) (interface{}, error) {
 	tbl := conv.SrcSchema[tableId]
 	srcCols := tbl.ColIds
 	if len(srcCols) == 0 {
@@ -55,10 +61,37 @@ func (isi InfoSchemaImpl) GetRowsFromTable(conv *internal.Conv, tableId string)
 		return nil, nil
 	}
 	q := getSelectQuery(isi.DbName, tbl.Schema, tbl.Name, tbl.ColIds, tbl.ColDefs)
+	var keyCol string
+	if keyRange != nil {
+		keyCol = `"` + tbl.ColDefs[keyRange.ColId].Name + `"`
+	}
+	quoteCol := func(c string) string { return `"` + c + `"` }
+	where, limit := common.ReadPredicate(conv, tableId, quoteCol, common.QuoteString)
+	if limit > 0 {
+		// ROWNUM is supported by all Oracle versions, unlike FETCH FIRST.
+		if where != "" {
+			where = "(" + where + ") AND "
+		}
+		where += fmt.Sprintf("ROWNUM <= %d", limit)
+	}
+	q += common.SelectClause(where, keyRange, keyCol)
 	rows, err := isi.Db.Query(q)
 	return rows, err
 }
 
+// GetKeyBounds returns the smallest and largest values of column colId
+// of a table, so that the table can be read in key ranges.
+func (isi InfoSchemaImpl) GetKeyBounds(conv *internal.Conv, tableId string, colId string) (int64, int64, bool, error) {
+	tbl := conv.SrcSchema[tableId]
+	col := tbl.ColDefs[colId].Name
+	q := fmt.Sprintf(`SELECT MIN("%s"), MAX("%s") FROM "%s"."%s"`, col, col, tbl.Schema, tbl.Name)
+	var min, max sql.NullInt64
+	if err := isi.Db.QueryRow(q).Scan(&min, &max); err != nil {
+		return 0, 0, false, err
+	}
+	return min.Int64, max.Int64, min.Valid && max.Valid, nil
+}
+
 func getSelectQuery(srcDb string, schemaName string, tableName string, colIds []string, colDefs map[string]schema.Column) string {
 	var selects = make([]string, len(colIds))
 
@@ -98,11 +131,17 @@ func getSelectQuery(srcDb string, schemaName string, tableName string, colIds []
 }
 
 // ProcessData performs data conversion for source database.
+//
+// Only the rows in additionalAttributes.KeyRange are read when it is set.
+// ProcessData may be called concurrently (see common.KeyRangeInfoSchema),
+// so conv is only updated while holding conv.ConvLock.
 func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSchema schema.Table, commonColIds []string, spSchema ddl.CreateTable, additionalAttributes internal.AdditionalDataAttributes) error {
 	srcTableName := conv.SrcSchema[tableId].Name
-	rowsInterface, err := isi.GetRowsFromTable(conv, tableId)
+	rowsInterface, err := isi.getRowsFromTable(conv, tableId, additionalAttributes.KeyRange)
 	if err != nil {
+		conv.ConvLock.Lock()
 		conv.Unexpected(fmt.Sprintf("Couldn't get data for table %s : err = %s", srcTableName, err))
+		conv.ConvLock.Unlock()
 		return err
 	}
 	rows := rowsInterface.(*sql.Rows)
@@ -110,31 +149,48 @@ func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSc
 	srcCols, _ := rows.Columns()
 	v, scanArgs := buildVals(len(srcCols))
 	colNameIdMap := internal.GetSrcColNameIdMap(conv.SrcSchema[tableId])
+	var cvtSrcCols []string
+	for _, colId := range commonColIds {
+		cvtSrcCols = append(cvtSrcCols, srcSchema.ColDefs[colId].Name)
+	}
+	// Rows are converted and written without holding conv.ConvLock, so
+	// that the key ranges of a table are processed in parallel.
 	for rows.Next() {
 		// get RawBytes from data.
 		err := rows.Scan(scanArgs...)
 		if err != nil {
+			conv.ConvLock.Lock()
 			conv.Unexpected(fmt.Sprintf("Couldn't process sql data row: %s", err))
 			// Scan failed, so we don't have any data to add to bad rows.
 			conv.StatsAddBadRow(srcTableName, conv.DataMode())
+			conv.ConvLock.Unlock()
 			continue
 		}
 		values := valsToStrings(v)
 		newValues, err := common.PrepareValues(conv, tableId, colNameIdMap, commonColIds, srcCols, values)
 		if err != nil {
-			conv.Unexpected(fmt.Sprintf("Error while converting data: %s\n", err))
-			conv.StatsAddBadRow(srcTableName, conv.DataMode())
-			conv.CollectBadRow(srcTableName, srcCols, values)
+			conv.BadRowConcurrently(srcTableName, srcCols, values, err)
+			continue
+		}
+		spTableName, cvtCols, cvtVals, err := convertData(conv, tableId, commonColIds, srcSchema, spSchema, newValues)
+		if err != nil {
+			conv.BadRowConcurrently(srcTableName, cvtSrcCols, newValues, err)
 			continue
 		}
-		ProcessDataRow(conv, tableId, commonColIds, srcSchema, spSchema, newValues)
+		conv.WriteRowConcurrently(srcTableName, spTableName, cvtCols, cvtVals)
 	}
 	return nil
 }
 
 // GetRowCount with number of rows in each table.
 func (isi InfoSchemaImpl) GetRowCount(table common.SchemaAndName) (int64, error) {
-	q := fmt.Sprintf(`SELECT count(*) FROM "%s"`, table.Name)
+	return isi.GetFilteredRowCount(table, "")
+}
+
+// GetFilteredRowCount returns the number of rows of a table that satisfy
+// where, or of all its rows if where is "".
+func (isi InfoSchemaImpl) GetFilteredRowCount(table common.SchemaAndName, where string) (int64, error) {
+	q := fmt.Sprintf(`SELECT count(*) FROM "%s"%s`, table.Name, common.SelectClause(where, nil, ""))
 	rows, err := isi.Db.Query(q)
 	if err != nil {
 		return 0, err
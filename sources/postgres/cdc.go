@@ -0,0 +1,439 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	sp "cloud.google.com/go/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+)
+
+// Defaults for reading changes from a replication slot.
+const (
+	defaultSlotBatchSize    = 1000
+	defaultSlotPollInterval = time.Second
+)
+
+// LogicalSource describes where to read logical decoding output from:
+// replication slot Slot of the database Db is connected to, or the
+// recorded output in File if it is set. Plugin is the output plugin of the
+// slot, pgoutput or wal2json.
+//
+// Recorded wal2json output is a sequence of JSON messages, such as the data
+// column of pg_logical_slot_get_changes. Recorded pgoutput output has a
+// line per message with its LSN, transaction id and data in hex, separated
+// by tabs, as written by
+//
+//	COPY (SELECT * FROM pg_logical_slot_get_binary_changes(...)) TO STDOUT
+type LogicalSource struct {
+	Plugin string
+	Db     *sql.DB
+	Slot   string
+	// Publication is the publication that pgoutput sends the changes of.
+	Publication string
+	// PollInterval is how long to wait for new changes when the slot has
+	// none; 1s by default.
+	PollInterval time.Duration
+	File         string
+}
+
+type lsnCheckpoint struct {
+	LSN string `json:"lsn"`
+}
+
+// LoadLSN returns the LSN saved in store, or 0 if there is none.
+func LoadLSN(store internal.CheckpointStore) (LSN, error) {
+	data, err := store.Load()
+	if err != nil || data == nil {
+		return 0, err
+	}
+	var c lsnCheckpoint
+	if err := json.Unmarshal(data, &c); err != nil {
+		return 0, fmt.Errorf("can't parse LSN in %s: %v", store, err)
+	}
+	return ParseLSN(c.LSN)
+}
+
+// SaveLSN saves lsn in store.
+func SaveLSN(store internal.CheckpointStore, lsn LSN) error {
+	data, err := json.Marshal(lsnCheckpoint{LSN: lsn.String()})
+	if err != nil {
+		return err
+	}
+	return store.Save(data)
+}
+
+// ReplicateLogical applies the row changes described by the logical
+// decoding output in src to Spanner. Changes are written with a
+// common.ChangeWriter, which converts rows with ConvertData, in commit
+// order. The changes of a source transaction are applied together, after
+// which the LSN of its commit is saved in store and, with a replication
+// slot, confirmed to the slot. Transactions at or before the LSN saved in
+// store are skipped, so a later call restarts after the last transaction
+// applied; a transaction may be applied again if the migration stops
+// between applying it and saving its LSN, which is harmless since inserts
+// and updates are applied as upserts. With a replication slot,
+// ReplicateLogical only returns when ctx is done or on error; with a
+// recorded file, it returns once it has been read.
+//
+// Rows that can't be converted are reported as bad rows. In dry-run mode,
+// client can be nil and no changes are applied.
+func ReplicateLogical(ctx context.Context, conv *internal.Conv, src LogicalSource, store internal.CheckpointStore, client *sp.Client) error {
+	var r logicalReader
+	var err error
+	if src.File != "" {
+		r, err = newFileLogicalReader(src.File, src.Plugin)
+	} else {
+		r, err = newSlotLogicalReader(src)
+	}
+	if err != nil {
+		return err
+	}
+	defer r.close()
+	c, err := newLogicalCDC(conv, src.Plugin, store, common.ApplyWithClient(client))
+	if err != nil {
+		return err
+	}
+	return c.run(ctx, r)
+}
+
+// logicalCDC applies logical decoding output to Spanner.
+type logicalCDC struct {
+	conv    *internal.Conv
+	store   internal.CheckpointStore
+	decoder logicalDecoder
+	writer  *common.ChangeWriter
+}
+
+func newLogicalCDC(conv *internal.Conv, plugin string, store internal.CheckpointStore, apply common.ApplyFunc) (*logicalCDC, error) {
+	decoder, err := newLogicalDecoder(plugin)
+	if err != nil {
+		return nil, err
+	}
+	convert := func(tableId string, colIds []string, vals []string) (string, []string, []interface{}, error) {
+		return ConvertData(conv, tableId, colIds, vals)
+	}
+	return &logicalCDC{
+		conv:    conv,
+		store:   store,
+		decoder: decoder,
+		writer:  common.NewChangeWriter(conv, convert, "\\N", apply),
+	}, nil
+}
+
+func (c *logicalCDC) run(ctx context.Context, r logicalReader) error {
+	var start LSN
+	if c.store != nil {
+		var err error
+		if start, err = LoadLSN(c.store); err != nil {
+			return err
+		}
+	}
+	for {
+		data, lsn, err := r.next(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		txn, err := c.decoder.decode(data, lsn)
+		if err != nil {
+			return err
+		}
+		if txn == nil {
+			continue
+		}
+		if txn.LSN <= start {
+			// Applied by an earlier run.
+			r.confirm(txn.LSN)
+			continue
+		}
+		for _, ch := range txn.Changes {
+			c.handleChange(ch)
+		}
+		if err := c.commit(ctx, txn.LSN); err != nil {
+			return err
+		}
+		r.confirm(txn.LSN)
+	}
+}
+
+// srcTable returns the source table name of a table, which is qualified
+// with its schema unless the schema is public or the only one migrated.
+func (c *logicalCDC) srcTable(schema, table string) (string, bool) {
+	if _, ok := c.writer.Migrated(schema + "." + table); ok {
+		return schema + "." + table, true
+	}
+	if schema != "public" {
+		for name := range c.conv.ToSpanner {
+			if strings.Contains(name, ".") {
+				// Tables of several schemas were migrated.
+				return "", false
+			}
+		}
+	}
+	_, ok := c.writer.Migrated(table)
+	return table, ok
+}
+
+func (c *logicalCDC) handleChange(ch logicalChange) {
+	srcTable, ok := c.srcTable(ch.Schema, ch.Table)
+	if !ok {
+		return
+	}
+	vals := srcVals(ch.Vals)
+	switch ch.Kind {
+	case 'I':
+		c.writer.Insert(srcTable, ch.Cols, vals)
+	case 'U':
+		var oldVals []string
+		if ch.OldCols != nil {
+			oldVals = srcVals(ch.OldVals)
+		}
+		c.writer.Update(srcTable, ch.OldCols, oldVals, ch.Cols, vals)
+	case 'D':
+		c.writer.Delete(srcTable, ch.OldCols, srcVals(ch.OldVals))
+	}
+}
+
+// srcVals returns values with NULLs represented as "\N", as in pg_dump
+// output.
+func srcVals(vals []*string) []string {
+	out := make([]string, len(vals))
+	for i, v := range vals {
+		if v == nil {
+			out[i] = "\\N"
+		} else {
+			out[i] = *v
+		}
+	}
+	return out
+}
+
+// commit applies the mutations of the current transaction and saves lsn
+// as the LSN to restart after.
+func (c *logicalCDC) commit(ctx context.Context, lsn LSN) error {
+	if err := c.writer.Commit(ctx); err != nil {
+		return fmt.Errorf("can't apply changes of the transaction committed at %s: %v", lsn, err)
+	}
+	if c.store == nil {
+		return nil
+	}
+	if err := SaveLSN(c.store, lsn); err != nil {
+		return fmt.Errorf("can't save LSN to %s: %v", c.store, err)
+	}
+	return nil
+}
+
+// logicalReader reads logical decoding messages.
+type logicalReader interface {
+	// next returns the next message and its LSN (0 if unknown), or io.EOF
+	// at the end of a recorded file.
+	next(ctx context.Context) ([]byte, LSN, error)
+	// confirm reports that the transactions up to lsn have been applied.
+	confirm(lsn LSN)
+	close()
+}
+
+// fileLogicalReader reads recorded logical decoding output.
+type fileLogicalReader struct {
+	f       *os.File
+	plugin  string
+	json    *json.Decoder
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newFileLogicalReader(path, plugin string) (*fileLogicalReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't open logical decoding output %s: %v", path, err)
+	}
+	r := &fileLogicalReader{f: f, plugin: plugin}
+	if plugin == PluginWal2json {
+		r.json = json.NewDecoder(f)
+	} else {
+		r.scanner = bufio.NewScanner(f)
+		r.scanner.Buffer(nil, 1<<30)
+	}
+	return r, nil
+}
+
+func (r *fileLogicalReader) next(ctx context.Context) ([]byte, LSN, error) {
+	if r.json != nil {
+		var m json.RawMessage
+		if err := r.json.Decode(&m); err != nil {
+			if err == io.EOF {
+				return nil, 0, err
+			}
+			return nil, 0, fmt.Errorf("can't read %s: %v", r.f.Name(), err)
+		}
+		return m, 0, nil
+	}
+	for r.scanner.Scan() {
+		r.line++
+		line := r.scanner.Text()
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			return nil, 0, fmt.Errorf("%s:%d: expected LSN, transaction id and data separated by tabs", r.f.Name(), r.line)
+		}
+		lsn, err := ParseLSN(fields[0])
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s:%d: %v", r.f.Name(), r.line, err)
+		}
+		// COPY escapes the backslash of bytea hex output.
+		s := strings.TrimPrefix(strings.TrimPrefix(fields[2], "\\"), "\\x")
+		data, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s:%d: invalid message data: %v", r.f.Name(), r.line, err)
+		}
+		return data, lsn, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("can't read %s: %v", r.f.Name(), err)
+	}
+	return nil, 0, io.EOF
+}
+
+func (r *fileLogicalReader) confirm(lsn LSN) {}
+
+func (r *fileLogicalReader) close() {
+	r.f.Close()
+}
+
+// slotLogicalReader reads changes from a replication slot with SQL
+// functions, peeking at them in batches. Changes are only consumed from
+// the slot once confirmed, by advancing it before the next batch; changes
+// that weren't confirmed are sent again by the next batch, or to the next
+// migration if this one stops.
+type slotLogicalReader struct {
+	src       LogicalSource
+	query     string
+	args      []interface{}
+	batch     []slotMessage
+	confirmed LSN
+	advanced  LSN
+}
+
+type slotMessage struct {
+	data []byte
+	lsn  LSN
+}
+
+func newSlotLogicalReader(src LogicalSource) (*slotLogicalReader, error) {
+	if src.Db == nil || src.Slot == "" {
+		return nil, fmt.Errorf("a replication slot or a recorded file is required")
+	}
+	r := &slotLogicalReader{src: src}
+	switch src.Plugin {
+	case PluginPgoutput:
+		if src.Publication == "" {
+			return nil, fmt.Errorf("a publication is required with pgoutput")
+		}
+		r.query = "SELECT lsn::text, data FROM pg_logical_slot_peek_binary_changes($1, NULL, $2, 'proto_version', '1', 'publication_names', $3)"
+		r.args = []interface{}{src.Slot, defaultSlotBatchSize, src.Publication}
+	case PluginWal2json:
+		r.query = "SELECT lsn::text, data FROM pg_logical_slot_peek_changes($1, NULL, $2, 'format-version', '2', 'include-lsn', '1')"
+		r.args = []interface{}{src.Slot, defaultSlotBatchSize}
+	default:
+		_, err := newLogicalDecoder(src.Plugin)
+		return nil, err
+	}
+	if r.src.PollInterval == 0 {
+		r.src.PollInterval = defaultSlotPollInterval
+	}
+	return r, nil
+}
+
+func (r *slotLogicalReader) next(ctx context.Context) ([]byte, LSN, error) {
+	for len(r.batch) == 0 {
+		if err := r.advance(ctx); err != nil {
+			return nil, 0, err
+		}
+		if err := r.fetch(ctx); err != nil {
+			return nil, 0, err
+		}
+		if len(r.batch) > 0 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		case <-time.After(r.src.PollInterval):
+		}
+	}
+	m := r.batch[0]
+	r.batch = r.batch[1:]
+	return m.data, m.lsn, nil
+}
+
+func (r *slotLogicalReader) fetch(ctx context.Context) error {
+	rows, err := r.src.Db.QueryContext(ctx, r.query, r.args...)
+	if err != nil {
+		return fmt.Errorf("can't read changes from replication slot %s: %v", r.src.Slot, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var lsn string
+		var data []byte
+		if err := rows.Scan(&lsn, &data); err != nil {
+			return err
+		}
+		m := slotMessage{data: data}
+		if m.lsn, err = ParseLSN(lsn); err != nil {
+			return err
+		}
+		r.batch = append(r.batch, m)
+	}
+	return rows.Err()
+}
+
+// advance consumes the confirmed changes from the slot.
+func (r *slotLogicalReader) advance(ctx context.Context) error {
+	if r.confirmed <= r.advanced {
+		return nil
+	}
+	if _, err := r.src.Db.ExecContext(ctx, "SELECT pg_replication_slot_advance($1, $2::pg_lsn)", r.src.Slot, r.confirmed.String()); err != nil {
+		return fmt.Errorf("can't advance replication slot %s to %s: %v", r.src.Slot, r.confirmed, err)
+	}
+	r.advanced = r.confirmed
+	return nil
+}
+
+func (r *slotLogicalReader) confirm(lsn LSN) {
+	r.confirmed = lsn
+}
+
+func (r *slotLogicalReader) close() {
+	if err := r.advance(context.Background()); err != nil {
+		logger.Log.Warn(err.Error())
+	}
+}
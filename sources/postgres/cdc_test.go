@@ -0,0 +1,159 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sp "cloud.google.com/go/spanner"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+func cdcConv() *internal.Conv {
+	conv := internal.MakeConv()
+	conv.SetDataMode()
+	conv.SrcSchema["t1"] = schema.Table{
+		Id:          "t1",
+		Name:        "items",
+		ColIds:      []string{"c1", "c2"},
+		PrimaryKeys: []schema.Key{{ColId: "c1"}},
+		ColDefs: map[string]schema.Column{
+			"c1": {Id: "c1", Name: "id", Type: schema.Type{Name: "int8"}},
+			"c2": {Id: "c2", Name: "data", Type: schema.Type{Name: "bytea"}},
+		},
+	}
+	conv.SpSchema["t1"] = ddl.CreateTable{
+		Id:          "t1",
+		Name:        "items",
+		ColIds:      []string{"c1", "c2"},
+		PrimaryKeys: []ddl.IndexKey{{ColId: "c1"}},
+		ColDefs: map[string]ddl.ColumnDef{
+			"c1": {Id: "c1", Name: "id", T: ddl.Type{Name: ddl.Int64}},
+			"c2": {Id: "c2", Name: "data", T: ddl.Type{Name: ddl.Bytes, Len: ddl.MaxLength}},
+		},
+	}
+	conv.ToSpanner["items"] = internal.NameAndCols{Name: "items", Cols: map[string]string{"id": "id", "data": "data"}}
+	conv.SrcSchema["t2"] = schema.Table{Id: "t2", Name: "other.logs"}
+	conv.ToSpanner["other.logs"] = internal.NameAndCols{Name: "other_logs"}
+	return conv
+}
+
+func TestReplicateLogicalPgoutput(t *testing.T) {
+	msgs := [][]byte{
+		pgoutputBegin(),
+		pgoutputRelationMsg(1, "public", "items", "id", "data"),
+		pgoutputRelationMsg(2, "other", "items", "id", "data"),
+		pgoutputChange('I', 1, pgoutputTuple('N', str("1"), str(`\x0102`))),
+		pgoutputChange('I', 2, pgoutputTuple('N', str("9"), nil)),
+		pgoutputChange('I', 1, pgoutputTuple('N', str("2"), nil)),
+		pgoutputCommit(0x100),
+		pgoutputBegin(),
+		pgoutputChange('U', 1, pgoutputTuple('K', str("1"), nil), pgoutputTuple('N', str("3"), str(`\x03`))),
+		pgoutputChange('U', 1, pgoutputTuple('N', str("2"), str("bad"))),
+		pgoutputChange('D', 1, pgoutputTuple('K', str("3"), nil)),
+		pgoutputCommit(0x200),
+	}
+	// As written by COPY (SELECT * FROM pg_logical_slot_get_binary_changes(...)) TO STDOUT.
+	var lines []string
+	for i, m := range msgs {
+		lines = append(lines, fmt.Sprintf("0/%X\t%d\t\\\\x%s", 0x10*(i+1), 700, hex.EncodeToString(m)))
+	}
+	dir := t.TempDir()
+	file := filepath.Join(dir, "changes.tsv")
+	assert.Nil(t, os.WriteFile(file, []byte(strings.Join(lines, "\n")+"\n"), 0644))
+
+	conv := cdcConv()
+	var applied [][]*sp.Mutation
+	apply := func(ctx context.Context, ms []*sp.Mutation) error {
+		applied = append(applied, ms)
+		return nil
+	}
+	store := &internal.FileCheckpointStore{Path: filepath.Join(dir, "lsn.json")}
+	c, err := newLogicalCDC(conv, PluginPgoutput, store, apply)
+	assert.Nil(t, err)
+	r, err := newFileLogicalReader(file, PluginPgoutput)
+	assert.Nil(t, err)
+	assert.Nil(t, c.run(context.Background(), r))
+	r.close()
+
+	cols := []string{"id", "data"}
+	assert.Equal(t, [][]*sp.Mutation{
+		{
+			sp.InsertOrUpdate("items", cols, []interface{}{int64(1), []byte{1, 2}}),
+			sp.InsertOrUpdate("items", cols, []interface{}{int64(2), nil}),
+		},
+		{
+			sp.Delete("items", sp.Key{int64(1)}),
+			sp.InsertOrUpdate("items", cols, []interface{}{int64(3), []byte{3}}),
+			sp.Delete("items", sp.Key{int64(3)}),
+		},
+	}, applied)
+	assert.Equal(t, int64(5), conv.Stats.Rows["items"])
+	assert.Equal(t, int64(1), conv.Stats.BadRows["items"])
+	lsn, err := LoadLSN(store)
+	assert.Nil(t, err)
+	assert.Equal(t, LSN(0x200), lsn)
+
+	// Restarting from the saved LSN applies nothing again.
+	applied = nil
+	c, err = newLogicalCDC(conv, PluginPgoutput, store, apply)
+	assert.Nil(t, err)
+	r, err = newFileLogicalReader(file, PluginPgoutput)
+	assert.Nil(t, err)
+	assert.Nil(t, c.run(context.Background(), r))
+	r.close()
+	assert.Nil(t, applied)
+}
+
+func TestReplicateLogicalWal2json(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "changes.json")
+	assert.Nil(t, os.WriteFile(file, []byte(`
+{"action": "B", "lsn": "0/100"}
+{"action": "I", "schema": "public", "table": "items", "columns": [{"name": "id", "value": 1}, {"name": "data", "value": "\\x0a"}]}
+{"action": "I", "schema": "public", "table": "logs", "columns": [{"name": "id", "value": 1}]}
+{"action": "C", "lsn": "0/100", "nextlsn": "0/180"}
+{"action": "B", "lsn": "0/200"}
+{"action": "D", "schema": "public", "table": "items", "identity": [{"name": "id", "value": 1}]}
+{"action": "C", "lsn": "0/200", "nextlsn": "0/280"}
+`), 0644))
+
+	conv := cdcConv()
+	var applied [][]*sp.Mutation
+	apply := func(ctx context.Context, ms []*sp.Mutation) error {
+		applied = append(applied, ms)
+		return nil
+	}
+	c, err := newLogicalCDC(conv, PluginWal2json, nil, apply)
+	assert.Nil(t, err)
+	r, err := newFileLogicalReader(file, PluginWal2json)
+	assert.Nil(t, err)
+	defer r.close()
+	assert.Nil(t, c.run(context.Background(), r))
+	assert.Equal(t, [][]*sp.Mutation{
+		{sp.InsertOrUpdate("items", []string{"id", "data"}, []interface{}{int64(1), []byte{10}})},
+		{sp.Delete("items", sp.Key{int64(1)})},
+	}, applied)
+}
@@ -46,7 +46,7 @@ func ProcessDataRow(conv *internal.Conv, tableId string, colIds, vals []string)
 	if err != nil {
 		conv.Unexpected(fmt.Sprintf("Error while converting data: %s\n", err))
 		conv.StatsAddBadRow(srcTableName, conv.DataMode())
-		conv.CollectBadRow(srcTableName, srcCols, vals)
+		conv.CollectBadRow(srcTableName, srcCols, vals, err)
 	} else {
 		conv.WriteRow(srcTableName, spTableName, spCols, spVals)
 	}
@@ -97,11 +97,9 @@ func ConvertData(conv *internal.Conv, tableId string, colIds []string, vals []st
 		v = append(v, x)
 		c = append(c, spColDef.Name)
 	}
-	if aux, ok := conv.SyntheticPKeys[tableId]; ok {
+	if aux, ok := conv.NextSyntheticPKey(tableId); ok {
 		c = append(c, conv.SpSchema[tableId].ColDefs[aux.ColId].Name)
 		v = append(v, fmt.Sprintf("%d", int64(bits.Reverse64(uint64(aux.Sequence)))))
-		aux.Sequence++
-		conv.SyntheticPKeys[tableId] = aux
 	}
 	return spSchema.Name, c, v, nil
 }
@@ -255,6 +253,10 @@ func convTimestamp(srcTypeName string, location *time.Location, val string) (t t
 // NULL, 2}", but it does not handle "NULL" (it returns error).
 func convArray(spannerType ddl.Type, srcTypeName string, location *time.Location, v string) (interface{}, error) {
 	v = strings.TrimSpace(v)
+	// pgvector values are written as [v1,v2,...].
+	if (srcTypeName == "vector" || srcTypeName == "halfvec") && strings.HasPrefix(v, "[") && strings.HasSuffix(v, "]") {
+		v = "{" + v[1:len(v)-1] + "}"
+	}
 	// Handle empty array. Note that we use an empty NullString array
 	// for all Spanner array types since this will be converted to the
 	// appropriate type by the Spanner client.
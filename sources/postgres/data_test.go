@@ -119,6 +119,13 @@ func TestConvertData(t *testing.T) {
 			spanner.NullTime{Time: getTime(t, "2019-10-29T05:30:00+10:00"), Valid: true},
 			spanner.NullTime{Valid: false}}},
 		{"empty array", ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: true}, "", "{}", []spanner.NullString{}},
+		{"vector", ddl.Type{Name: ddl.Float32, IsArray: true, VectorLength: 3}, "vector", "[1,2.5,-3e-05]", []spanner.NullFloat32{
+			spanner.NullFloat32{Float32: 1, Valid: true},
+			spanner.NullFloat32{Float32: 2.5, Valid: true},
+			spanner.NullFloat32{Float32: -3e-05, Valid: true}}},
+		{"halfvec", ddl.Type{Name: ddl.Float32, IsArray: true, VectorLength: 2}, "halfvec", "[0.5,1]", []spanner.NullFloat32{
+			spanner.NullFloat32{Float32: 0.5, Valid: true},
+			spanner.NullFloat32{Float32: 1, Valid: true}}},
 	}
 	tableName := "testtable"
 	tableId := "t1"
@@ -16,6 +16,7 @@ package postgres
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"math/bits"
 	"reflect"
@@ -27,6 +28,7 @@ import (
 
 	"cloud.google.com/go/civil"
 	_ "github.com/lib/pq" // we will use database/sql package instead of using this package directly
+	pg_query "github.com/pganalyze/pg_query_go/v6"
 
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
@@ -78,24 +80,52 @@ func (isi InfoSchemaImpl) GetTableName(schema string, tableName string) string {
 
 // GetRowsFromTable returns a sql Rows object for a table.
 func (isi InfoSchemaImpl) GetRowsFromTable(conv *internal.Conv, tableId string) (interface{}, error) {
-	// PostgreSQL schema and name can be arbitrary strings.
-	// Ideally we would pass schema/name as a query parameter,
-	// but PostgreSQL doesn't support this. So we quote it instead.
-	isSchemaNamePrefixed := strings.HasPrefix(conv.SrcSchema[tableId].Name, conv.SrcSchema[tableId].Schema+".")
-	var tableName string
-	if isSchemaNamePrefixed {
-		tableName = strings.TrimPrefix(conv.SrcSchema[tableId].Name, conv.SrcSchema[tableId].Schema+".")
-	} else {
-		tableName = conv.SrcSchema[tableId].Name
-	}
-	q := fmt.Sprintf(`SELECT * FROM "%s"."%s";`, conv.SrcSchema[tableId].Schema, tableName)
-	rows, err := isi.Db.Query(q)
+	return isi.getRowsFromTable(conv, tableId, nil)
+}
+
+// getRowsFromTable returns a sql Rows object for the rows of a table in
+// keyRange, or for the whole table if keyRange is nil.
+func (isi InfoSchemaImpl) getRowsFromTable(conv *internal.Conv, tableId string, keyRange *internal.KeyRange) (interface{}, error) {
+	q := fmt.Sprintf(`SELECT * FROM %s`, quotedTableName(conv.SrcSchema[tableId]))
+	var keyCol string
+	if keyRange != nil {
+		keyCol = `"` + conv.SrcSchema[tableId].ColDefs[keyRange.ColId].Name + `"`
+	}
+	quoteCol := func(c string) string { return `"` + c + `"` }
+	where, limit := common.ReadPredicate(conv, tableId, quoteCol, common.QuoteString)
+	q += common.SelectClause(where, keyRange, keyCol)
+	if limit > 0 {
+		q += fmt.Sprintf(" LIMIT %d", limit)
+	}
+	rows, err := isi.Db.Query(q + ";")
 	if err != nil {
 		return nil, err
 	}
 	return rows, err
 }
 
+// quotedTableName returns the quoted, schema-qualified name of a table.
+func quotedTableName(tbl schema.Table) string {
+	// PostgreSQL schema and name can be arbitrary strings.
+	// Ideally we would pass schema/name as a query parameter,
+	// but PostgreSQL doesn't support this. So we quote it instead.
+	tableName := strings.TrimPrefix(tbl.Name, tbl.Schema+".")
+	return fmt.Sprintf(`"%s"."%s"`, tbl.Schema, tableName)
+}
+
+// GetKeyBounds returns the smallest and largest values of column colId
+// of a table, so that the table can be read in key ranges.
+func (isi InfoSchemaImpl) GetKeyBounds(conv *internal.Conv, tableId string, colId string) (int64, int64, bool, error) {
+	tbl := conv.SrcSchema[tableId]
+	col := tbl.ColDefs[colId].Name
+	q := fmt.Sprintf(`SELECT MIN("%s"), MAX("%s") FROM %s;`, col, col, quotedTableName(tbl))
+	var min, max sql.NullInt64
+	if err := isi.Db.QueryRow(q).Scan(&min, &max); err != nil {
+		return 0, 0, false, err
+	}
+	return min.Int64, max.Int64, min.Valid && max.Valid, nil
+}
+
 // ProcessDataRows performs data conversion for source database
 // 'db'. For each table, we extract data using a "SELECT *" query,
 // convert the data to Spanner data (based on the source and Spanner
@@ -115,11 +145,17 @@ func (isi InfoSchemaImpl) GetRowsFromTable(conv *internal.Conv, tableId string)
 // We choose to do all type conversions explicitly ourselves so that
 // we can generate more targeted error messages: hence we pass
 // *interface{} parameters to row.Scan.
+//
+// Only the rows in additionalAttributes.KeyRange are read when it is set.
+// ProcessData may be called concurrently (see common.KeyRangeInfoSchema),
+// so conv is only updated while holding conv.ConvLock.
 func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSchema schema.Table, colIds []string, spSchema ddl.CreateTable, additionalAttributes internal.AdditionalDataAttributes) error {
 	srcTableName := conv.SrcSchema[tableId].Name
-	rowsInterface, err := isi.GetRowsFromTable(conv, tableId)
+	rowsInterface, err := isi.getRowsFromTable(conv, tableId, additionalAttributes.KeyRange)
 	if err != nil {
+		conv.ConvLock.Lock()
 		conv.Unexpected(fmt.Sprintf("Couldn't get data for table %s : err = %s", srcTableName, err))
+		conv.ConvLock.Unlock()
 		return err
 	}
 	rows := rowsInterface.(*sql.Rows)
@@ -127,23 +163,25 @@ func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSc
 	srcCols, _ := rows.Columns()
 	v, iv := buildVals(len(srcCols))
 	colNameIdMap := internal.GetSrcColNameIdMap(conv.SrcSchema[tableId])
+	// Rows are converted and written without holding conv.ConvLock, so
+	// that the key ranges of a table are processed in parallel.
 	for rows.Next() {
 		err := rows.Scan(iv...)
 		if err != nil {
+			conv.ConvLock.Lock()
 			conv.Unexpected(fmt.Sprintf("Couldn't process sql data row: %s", err))
 			// Scan failed, so we don't have any data to add to bad rows.
 			conv.StatsAddBadRow(srcTableName, conv.DataMode())
+			conv.ConvLock.Unlock()
 			continue
 		}
 		newValues, err1 := common.PrepareValues(conv, tableId, colNameIdMap, colIds, srcCols, v)
 		cvtCols, cvtVals, err2 := convertSQLRow(conv, tableId, colIds, srcSchema, spSchema, newValues)
 		if err1 != nil || err2 != nil {
-			conv.Unexpected(fmt.Sprintf("Couldn't process sql data row: %s", err))
-			conv.StatsAddBadRow(srcTableName, conv.DataMode())
-			conv.CollectBadRow(srcTableName, srcCols, valsToStrings(v))
+			conv.BadRowConcurrently(srcTableName, srcCols, valsToStrings(v), errors.Join(err1, err2))
 			continue
 		}
-		conv.WriteRow(srcTableName, conv.SpSchema[tableId].Name, cvtCols, cvtVals)
+		conv.WriteRowConcurrently(srcTableName, spSchema.Name, cvtCols, cvtVals)
 	}
 	return nil
 }
@@ -179,21 +217,25 @@ func convertSQLRow(conv *internal.Conv, tableId string, colIds []string, srcSche
 		vs = append(vs, spVal)
 		cs = append(cs, spCd.Name)
 	}
-	if aux, ok := conv.SyntheticPKeys[tableId]; ok {
+	if aux, ok := conv.NextSyntheticPKey(tableId); ok {
 		cs = append(cs, conv.SpSchema[tableId].ColDefs[aux.ColId].Name)
 		vs = append(vs, fmt.Sprintf("%d", int64(bits.Reverse64(uint64(aux.Sequence)))))
-		aux.Sequence++
-		conv.SyntheticPKeys[tableId] = aux
 	}
 	return cs, vs, nil
 }
 
 // GetRowCount with number of rows in each table.
 func (isi InfoSchemaImpl) GetRowCount(table common.SchemaAndName) (int64, error) {
+	return isi.GetFilteredRowCount(table, "")
+}
+
+// GetFilteredRowCount returns the number of rows of a table that satisfy
+// where, or of all its rows if where is "".
+func (isi InfoSchemaImpl) GetFilteredRowCount(table common.SchemaAndName, where string) (int64, error) {
 	// PostgreSQL schema and name can be arbitrary strings.
 	// Ideally we would pass schema/name as a query parameter,
 	// but PostgreSQL doesn't support this. So we quote it instead.
-	q := fmt.Sprintf(`SELECT COUNT(*) FROM "%s"."%s";`, table.Schema, table.Name)
+	q := fmt.Sprintf(`SELECT COUNT(*) FROM "%s"."%s"%s;`, table.Schema, table.Name, common.SelectClause(where, nil, ""))
 	rows, err := isi.Db.Query(q)
 	if err != nil {
 		return 0, err
@@ -236,9 +278,44 @@ func (isi InfoSchemaImpl) GetTables() ([]common.SchemaAndName, error) {
 	return tables, nil
 }
 
+// GetViews returns the views of the user schemas of the database.
+func (isi InfoSchemaImpl) GetViews(conv *internal.Conv) ([]schema.View, error) {
+	q := `SELECT table_schema, table_name, view_definition FROM information_schema.views
+              WHERE table_schema NOT IN ('information_schema', 'pg_catalog')`
+	rows, err := isi.Db.Query(q)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get views: %w", err)
+	}
+	defer rows.Close()
+	var viewSchema, name string
+	var definition sql.NullString
+	var views []schema.View
+	for rows.Next() {
+		if err := rows.Scan(&viewSchema, &name, &definition); err != nil {
+			conv.Unexpected(fmt.Sprintf("Can't scan: %v", err))
+			continue
+		}
+		// The definition is null for views the user doesn't own.
+		if !definition.Valid {
+			conv.Unexpected(fmt.Sprintf("Can't read the definition of view %s.%s", viewSchema, name))
+			continue
+		}
+		views = append(views, schema.View{Name: isi.GetTableName(viewSchema, name), Schema: viewSchema, Definition: definition.String})
+	}
+	return views, nil
+}
+
 // GetColumns returns a list of Column objects and names
 func (isi InfoSchemaImpl) GetColumns(conv *internal.Conv, table common.SchemaAndName, constraints map[string][]string, primaryKeys []string) (map[string]schema.Column, []string, error) {
-	q := `SELECT c.column_name, c.data_type, e.data_type, c.is_nullable, c.column_default, c.character_maximum_length, c.numeric_precision, c.numeric_scale
+	// The type modifiers of user-defined types, e.g. the dimension of pgvector
+	// vector(3) columns, are only available through format_type.
+	q := `SELECT c.column_name,
+                CASE WHEN c.data_type = 'USER-DEFINED' THEN
+                  (SELECT format_type(a.atttypid, a.atttypmod) FROM pg_attribute a
+                     WHERE a.attrelid = (quote_ident(c.table_schema) || '.' || quote_ident(c.table_name))::regclass
+                       AND a.attname = c.column_name)
+                ELSE c.data_type END,
+                e.data_type, c.is_nullable, c.column_default, c.character_maximum_length, c.numeric_precision, c.numeric_scale
               FROM information_schema.COLUMNS c LEFT JOIN information_schema.element_types e
                  ON ((c.table_catalog, c.table_schema, c.table_name, 'TABLE', c.dtd_identifier)
                      = (e.object_catalog, e.object_schema, e.object_name, e.object_type, e.collection_type_identifier))
@@ -483,12 +560,80 @@ func (isi InfoSchemaImpl) GetIndexes(conv *internal.Conv, table common.SchemaAnd
 			Desc:  (collation == "DESC")})
 		indexMap[name] = index
 	}
+	parsedIndexes, err := isi.getParsedIndexes(conv, table, colNameIdMap)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range parsedIndexes {
+		if _, found := indexMap[index.Name]; !found {
+			indexNames = append(indexNames, index.Name)
+		}
+		indexMap[index.Name] = index
+	}
 	for _, k := range indexNames {
 		indexes = append(indexes, indexMap[k])
 	}
 	return indexes, nil
 }
 
+// getParsedIndexes returns the GIN indexes on tsvector values of table, as
+// full-text indexes, its pgvector indexes, as vector indexes, and its other
+// partial indexes. They are converted from their parsed definitions, since
+// the query of GetIndexes returns neither expression keys, e.g. to_tsvector(),
+// nor operator classes, nor predicates.
+func (isi InfoSchemaImpl) getParsedIndexes(conv *internal.Conv, table common.SchemaAndName, colNameIdMap map[string]string) ([]schema.Index, error) {
+	q := `SELECT
+			irel.relname AS index_name,
+			pg_get_indexdef(i.indexrelid) AS index_def
+		FROM pg_index AS i
+		JOIN pg_class AS trel
+		ON trel.oid = i.indrelid
+		JOIN pg_namespace AS tnsp
+		ON trel.relnamespace = tnsp.oid
+		JOIN pg_class AS irel
+		ON irel.oid = i.indexrelid
+		JOIN pg_am AS am
+		ON am.oid = irel.relam
+		WHERE tnsp.nspname= $1
+			AND trel.relname= $2
+			AND i.indisprimary = false
+			AND (am.amname IN ('hnsw', 'ivfflat')
+				OR (am.amname = 'btree' AND i.indpred IS NOT NULL)
+				OR (am.amname = 'gin' AND EXISTS (
+					SELECT 1 FROM UNNEST (i.indclass::oid[]) AS c (opclass)
+					JOIN pg_opclass AS oc ON oc.oid = c.opclass
+					WHERE oc.opcname = 'tsvector_ops')))
+		ORDER BY irel.relname;`
+	rows, err := isi.Db.Query(q, table.Schema, table.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var name, indexDef string
+	var indexes []schema.Index
+	for rows.Next() {
+		if err := rows.Scan(&name, &indexDef); err != nil {
+			conv.Unexpected(fmt.Sprintf("Can't scan: %v", err))
+			continue
+		}
+		tree, err := pg_query.Parse(indexDef)
+		if err != nil || len(tree.Stmts) != 1 || tree.Stmts[0].Stmt.GetIndexStmt() == nil {
+			conv.Unexpected(fmt.Sprintf("Can't parse definition of index %s: %s", name, indexDef))
+			continue
+		}
+		n := tree.Stmts[0].Stmt.GetIndexStmt()
+		switch {
+		case isVectorIndex(n):
+			indexes = append(indexes, toVectorIndex(conv, n, colNameIdMap))
+		case n.AccessMethod == "gin":
+			indexes = append(indexes, toFullTextIndex(conv, n, colNameIdMap))
+		default:
+			indexes = append(indexes, toIndex(conv, n, colNameIdMap))
+		}
+	}
+	return indexes, nil
+}
+
 func toType(dataType string, elementDataType sql.NullString, charLen sql.NullInt64, numericPrecision, numericScale sql.NullInt64) schema.Type {
 	switch {
 	case dataType == "ARRAY" && elementDataType.Valid:
@@ -502,8 +647,26 @@ func toType(dataType string, elementDataType sql.NullString, charLen sql.NullInt
 	case numericPrecision.Valid:
 		return schema.Type{Name: dataType, Mods: []int64{numericPrecision.Int64}}
 	default:
+		return toUserDefinedType(dataType)
+	}
+}
+
+// toUserDefinedType splits the type modifiers off a type formatted by
+// format_type, e.g. vector(3).
+func toUserDefinedType(dataType string) schema.Type {
+	open := strings.Index(dataType, "(")
+	if open == -1 || !strings.HasSuffix(dataType, ")") {
 		return schema.Type{Name: dataType}
 	}
+	var mods []int64
+	for _, m := range strings.Split(dataType[open+1:len(dataType)-1], ",") {
+		mod, err := strconv.ParseInt(strings.TrimSpace(m), 10, 64)
+		if err != nil {
+			return schema.Type{Name: dataType}
+		}
+		mods = append(mods, mod)
+	}
+	return schema.Type{Name: dataType[:open], Mods: mods}
 }
 
 func toAutoGen(isSerial bool) ddl.AutoGenCol {
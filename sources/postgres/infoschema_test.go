@@ -97,6 +97,11 @@ func TestProcessSchema(t *testing.T) {
 			args:  []driver.Value{"public", "user"},
 			cols:  []string{"index_name", "column_name", "column_position", "is_unique", "order"},
 		},
+		{
+			query: "SELECT (.+) FROM pg_index (.+) JOIN pg_am (.+)",
+			args:  []driver.Value{"public", "user"},
+			cols:  []string{"index_name", "index_def"},
+		},
 
 		{
 			query: "SELECT (.+) FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS (.+)",
@@ -140,6 +145,11 @@ func TestProcessSchema(t *testing.T) {
 				{"index3", "userid", 2, "true", "ASC"},
 			},
 		},
+		{
+			query: "SELECT (.+) FROM pg_index (.+) JOIN pg_am (.+)",
+			args:  []driver.Value{"public", "cart"},
+			cols:  []string{"index_name", "index_def"},
+		},
 		{
 			query: "SELECT (.+) FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS (.+)",
 			args:  []driver.Value{"public", "product"},
@@ -171,6 +181,11 @@ func TestProcessSchema(t *testing.T) {
 			args:  []driver.Value{"public", "product"},
 			cols:  []string{"index_name", "column_name", "column_position", "is_unique", "order"},
 		},
+		{
+			query: "SELECT (.+) FROM pg_index (.+) JOIN pg_am (.+)",
+			args:  []driver.Value{"public", "product"},
+			cols:  []string{"index_name", "index_def"},
+		},
 
 		{
 			query: "SELECT (.+) FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS (.+)",
@@ -224,6 +239,11 @@ func TestProcessSchema(t *testing.T) {
 			args:  []driver.Value{"public", "test"},
 			cols:  []string{"index_name", "column_name", "column_position", "is_unique", "order"},
 		},
+		{
+			query: "SELECT (.+) FROM pg_index (.+) JOIN pg_am (.+)",
+			args:  []driver.Value{"public", "test"},
+			cols:  []string{"index_name", "index_def"},
+		},
 
 		{
 			query: "SELECT (.+) FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS (.+)",
@@ -257,6 +277,11 @@ func TestProcessSchema(t *testing.T) {
 			args:  []driver.Value{"public", "test_ref"},
 			cols:  []string{"index_name", "column_name", "column_position", "is_unique", "order"},
 		},
+		{
+			query: "SELECT (.+) FROM pg_index (.+) JOIN pg_am (.+)",
+			args:  []driver.Value{"public", "test_ref"},
+			cols:  []string{"index_name", "index_def"},
+		},
 	}
 	db := mkMockDB(t, ms)
 	conv := internal.MakeConv()
@@ -552,6 +577,11 @@ func TestConvertSqlRow_MultiCol(t *testing.T) {
 			args:  []driver.Value{"public", "test"},
 			cols:  []string{"index_name", "column_name", "column_position", "is_unique", "order"},
 		},
+		{
+			query: "SELECT (.+) FROM pg_index (.+) JOIN pg_am (.+)",
+			args:  []driver.Value{"public", "test"},
+			cols:  []string{"index_name", "index_def"},
+		},
 		{
 			query: `SELECT [*] FROM "public"."test"`, // query is a regexp!
 			cols:  []string{"a", "b", "c"},
@@ -639,3 +669,154 @@ func newFalsePtr() *bool {
 	temp := false
 	return &temp
 }
+
+func TestGetViews(t *testing.T) {
+	ms := []mockSpec{
+		{
+			query: "SELECT table_schema, table_name, view_definition FROM information_schema.views",
+			cols:  []string{"table_schema", "table_name", "view_definition"},
+			rows: [][]driver.Value{
+				{"public", "user_ids", " SELECT user_id\n   FROM users;"},
+				{"sales", "big_orders", " SELECT id\n   FROM sales.orders\n  WHERE total > 100;"},
+				{"sales", "hidden", nil},
+			},
+		},
+	}
+	db := mkMockDB(t, ms)
+	isi := InfoSchemaImpl{db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}, newFalsePtr()}
+	conv := internal.MakeConv()
+	views, err := isi.GetViews(conv)
+	assert.NoError(t, err)
+	assert.Equal(t, []schema.View{
+		{Name: "user_ids", Schema: "public", Definition: " SELECT user_id\n   FROM users;"},
+		{Name: "sales.big_orders", Schema: "sales", Definition: " SELECT id\n   FROM sales.orders\n  WHERE total > 100;"},
+	}, views)
+	assert.Equal(t, int64(1), conv.Unexpecteds())
+}
+
+func TestGetIndexes_FullText(t *testing.T) {
+	ms := []mockSpec{
+		{
+			query: "SELECT (.+) FROM pg_index (.+)",
+			args:  []driver.Value{"public", "articles"},
+			cols:  []string{"index_name", "column_name", "column_position", "is_unique", "order"},
+			rows: [][]driver.Value{
+				{"ft_tsv", "tsv", 1, "false", "ASC"},
+				{"title_idx", "title", 1, "false", "ASC"},
+			},
+		},
+		{
+			query: "SELECT (.+) FROM pg_index (.+) JOIN pg_am (.+)",
+			args:  []driver.Value{"public", "articles"},
+			cols:  []string{"index_name", "index_def"},
+			rows: [][]driver.Value{
+				{"ft_title_body", "CREATE INDEX ft_title_body ON public.articles USING gin (to_tsvector('english'::regconfig, ((COALESCE(title, ''::text) || ' '::text) || body)))"},
+				{"ft_tsv", "CREATE INDEX ft_tsv ON public.articles USING gin (tsv)"},
+			},
+		},
+	}
+	db := mkMockDB(t, ms)
+	isi := InfoSchemaImpl{db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}, newFalsePtr()}
+	conv := internal.MakeConv()
+	colNameIdMap := map[string]string{"id": "c1", "title": "c2", "body": "c3", "tsv": "c4"}
+	indexes, err := isi.GetIndexes(conv, common.SchemaAndName{Schema: "public", Name: "articles"}, colNameIdMap)
+	assert.NoError(t, err)
+	for i := range indexes {
+		indexes[i].Id = ""
+	}
+	assert.Equal(t, []schema.Index{
+		{Name: "ft_tsv", Keys: []schema.Key{{ColId: "c4"}}, FullText: true},
+		{Name: "title_idx", Keys: []schema.Key{{ColId: "c2"}}},
+		{Name: "ft_title_body", Keys: []schema.Key{{ColId: "c2"}, {ColId: "c3"}}, FullText: true},
+	}, indexes)
+	assert.Equal(t, int64(0), conv.Unexpecteds())
+}
+
+func TestGetIndexes_Vector(t *testing.T) {
+	ms := []mockSpec{
+		{
+			query: "SELECT (.+) FROM pg_index (.+)",
+			args:  []driver.Value{"public", "items"},
+			cols:  []string{"index_name", "column_name", "column_position", "is_unique", "order"},
+			rows: [][]driver.Value{
+				{"items_embedding_idx", "embedding", 1, "false", "ASC"},
+				{"items_summary_idx", "summary", 1, "false", "ASC"},
+			},
+		},
+		{
+			query: "SELECT (.+) FROM pg_index (.+) JOIN pg_am (.+)",
+			args:  []driver.Value{"public", "items"},
+			cols:  []string{"index_name", "index_def"},
+			rows: [][]driver.Value{
+				{"items_embedding_idx", "CREATE INDEX items_embedding_idx ON public.items USING hnsw (embedding vector_ip_ops) WITH (m='16')"},
+				{"items_summary_idx", "CREATE INDEX items_summary_idx ON public.items USING ivfflat (summary)"},
+			},
+		},
+	}
+	db := mkMockDB(t, ms)
+	isi := InfoSchemaImpl{db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}, newFalsePtr()}
+	conv := internal.MakeConv()
+	colNameIdMap := map[string]string{"id": "c1", "embedding": "c2", "summary": "c3"}
+	indexes, err := isi.GetIndexes(conv, common.SchemaAndName{Schema: "public", Name: "items"}, colNameIdMap)
+	assert.NoError(t, err)
+	for i := range indexes {
+		indexes[i].Id = ""
+	}
+	assert.Equal(t, []schema.Index{
+		{Name: "items_embedding_idx", Keys: []schema.Key{{ColId: "c2"}}, Vector: true, DistanceType: ddl.DistanceDotProduct},
+		{Name: "items_summary_idx", Keys: []schema.Key{{ColId: "c3"}}, Vector: true, DistanceType: ddl.DistanceEuclidean},
+	}, indexes)
+	assert.Equal(t, int64(0), conv.Unexpecteds())
+}
+
+func TestGetIndexes_Partial(t *testing.T) {
+	ms := []mockSpec{
+		{
+			query: "SELECT (.+) FROM pg_index (.+)",
+			args:  []driver.Value{"public", "orders"},
+			cols:  []string{"index_name", "column_name", "column_position", "is_unique", "order"},
+			rows: [][]driver.Value{
+				{"orders_coupon_idx", "coupon", 1, "true", "DESC"},
+				{"orders_placed_idx", "placed", 1, "false", "ASC"},
+			},
+		},
+		{
+			query: "SELECT (.+) FROM pg_index (.+) JOIN pg_am (.+)",
+			args:  []driver.Value{"public", "orders"},
+			cols:  []string{"index_name", "index_def"},
+			rows: [][]driver.Value{
+				{"orders_coupon_idx", "CREATE UNIQUE INDEX orders_coupon_idx ON public.orders USING btree (coupon DESC) WHERE (coupon IS NOT NULL)"},
+				{"orders_placed_idx", "CREATE INDEX orders_placed_idx ON public.orders USING btree (placed) WHERE (placed > '2024-01-01'::date)"},
+			},
+		},
+	}
+	db := mkMockDB(t, ms)
+	isi := InfoSchemaImpl{db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}, newFalsePtr()}
+	conv := internal.MakeConv()
+	colNameIdMap := map[string]string{"id": "c1", "coupon": "c2", "placed": "c3"}
+	indexes, err := isi.GetIndexes(conv, common.SchemaAndName{Schema: "public", Name: "orders"}, colNameIdMap)
+	assert.NoError(t, err)
+	for i := range indexes {
+		indexes[i].Id = ""
+	}
+	assert.Equal(t, []schema.Index{
+		{Name: "orders_coupon_idx", Unique: true, Keys: []schema.Key{{ColId: "c2", Desc: true}}, Partial: true, NotNullColIds: []string{"c2"}},
+		{Name: "orders_placed_idx", Keys: []schema.Key{{ColId: "c3"}}, Partial: true},
+	}, indexes)
+	assert.Equal(t, int64(0), conv.Unexpecteds())
+}
+
+func TestToUserDefinedType(t *testing.T) {
+	tests := []struct {
+		dataType string
+		expected schema.Type
+	}{
+		{"vector(3)", schema.Type{Name: "vector", Mods: []int64{3}}},
+		{"vector", schema.Type{Name: "vector"}},
+		{"mood", schema.Type{Name: "mood"}},
+		{"geometry(Point,4326)", schema.Type{Name: "geometry(Point,4326)"}},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, toUserDefinedType(tc.dataType), tc.dataType)
+	}
+}
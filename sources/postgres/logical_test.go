@@ -0,0 +1,151 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pgoutput message builders. Tuple values are text, or NULL if nil.
+func pgoutputBegin() []byte {
+	return append([]byte{'B'}, make([]byte, 20)...)
+}
+
+func pgoutputCommit(end LSN) []byte {
+	m := append([]byte{'C', 0}, make([]byte, 8)...)
+	m = binary.BigEndian.AppendUint64(m, uint64(end))
+	return append(m, make([]byte, 8)...)
+}
+
+func pgoutputRelationMsg(id uint32, schema, table string, cols ...string) []byte {
+	m := binary.BigEndian.AppendUint32([]byte{'R'}, id)
+	m = append(append(m, schema...), 0)
+	m = append(append(m, table...), 0)
+	m = append(m, 'd')
+	m = binary.BigEndian.AppendUint16(m, uint16(len(cols)))
+	for _, c := range cols {
+		m = append(append(append(m, 0), c...), 0)
+		m = append(m, make([]byte, 8)...)
+	}
+	return m
+}
+
+func pgoutputTuple(tag byte, vals ...*string) []byte {
+	m := binary.BigEndian.AppendUint16([]byte{tag}, uint16(len(vals)))
+	for _, v := range vals {
+		if v == nil {
+			m = append(m, 'n')
+			continue
+		}
+		m = binary.BigEndian.AppendUint32(append(m, 't'), uint32(len(*v)))
+		m = append(m, *v...)
+	}
+	return m
+}
+
+func pgoutputChange(kind byte, id uint32, tuples ...[]byte) []byte {
+	m := binary.BigEndian.AppendUint32([]byte{kind}, id)
+	for _, t := range tuples {
+		m = append(m, t...)
+	}
+	return m
+}
+
+func str(s string) *string { return &s }
+
+func TestParseLSN(t *testing.T) {
+	lsn, err := ParseLSN("16/B374D848")
+	assert.Nil(t, err)
+	assert.Equal(t, LSN(0x16B374D848), lsn)
+	assert.Equal(t, "16/B374D848", lsn.String())
+	_, err = ParseLSN("16B374D848")
+	assert.NotNil(t, err)
+}
+
+func TestPgoutputDecoder(t *testing.T) {
+	d, err := newLogicalDecoder(PluginPgoutput)
+	assert.Nil(t, err)
+	msgs := [][]byte{
+		pgoutputBegin(),
+		pgoutputRelationMsg(1, "public", "items", "id", "name"),
+		pgoutputChange('I', 1, pgoutputTuple('N', str("1"), nil)),
+		pgoutputChange('U', 1, pgoutputTuple('K', str("1"), nil), pgoutputTuple('N', str("2"), str("b"))),
+		pgoutputChange('D', 1, pgoutputTuple('K', str("2"), nil)),
+		pgoutputCommit(0x100),
+	}
+	var txn *logicalTxn
+	for i, m := range msgs {
+		txn, err = d.decode(m, 0)
+		assert.Nil(t, err)
+		if i < len(msgs)-1 {
+			assert.Nil(t, txn)
+		}
+	}
+	assert.Equal(t, &logicalTxn{LSN: 0x100, Changes: []logicalChange{
+		{Kind: 'I', Schema: "public", Table: "items", Cols: []string{"id", "name"}, Vals: []*string{str("1"), nil}},
+		{Kind: 'U', Schema: "public", Table: "items", Cols: []string{"id", "name"}, Vals: []*string{str("2"), str("b")}, OldCols: []string{"id", "name"}, OldVals: []*string{str("1"), nil}},
+		{Kind: 'D', Schema: "public", Table: "items", OldCols: []string{"id", "name"}, OldVals: []*string{str("2"), nil}},
+	}}, txn)
+
+	_, err = d.decode(pgoutputChange('I', 2, pgoutputTuple('N', str("1"))), 0)
+	assert.NotNil(t, err)
+	_, err = d.decode([]byte{'C', 0, 1}, 0)
+	assert.NotNil(t, err)
+}
+
+func TestWal2jsonDecoder(t *testing.T) {
+	d, err := newLogicalDecoder(PluginWal2json)
+	assert.Nil(t, err)
+
+	// Format version 1.
+	txn, err := d.decode([]byte(`{"xid": 1, "nextlsn": "0/1A0", "change": [
+		{"kind": "insert", "schema": "public", "table": "items", "columnnames": ["id", "name", "ok"], "columnvalues": [1, "a", true]},
+		{"kind": "delete", "schema": "public", "table": "items", "oldkeys": {"keynames": ["id"], "keyvalues": [1]}},
+		{"kind": "message", "prefix": "x"}]}`), 0)
+	assert.Nil(t, err)
+	assert.Equal(t, &logicalTxn{LSN: 0x1A0, Changes: []logicalChange{
+		{Kind: 'I', Schema: "public", Table: "items", Cols: []string{"id", "name", "ok"}, Vals: []*string{str("1"), str("a"), str("true")}},
+		{Kind: 'D', Schema: "public", Table: "items", Vals: []*string{}, OldCols: []string{"id"}, OldVals: []*string{str("1")}},
+	}}, txn)
+
+	// Format version 2.
+	for _, m := range []string{
+		`{"action": "B", "lsn": "0/200"}`,
+		`{"action": "U", "schema": "s", "table": "t", "columns": [{"name": "id", "value": 2}, {"name": "v", "value": null}], "identity": [{"name": "id", "value": 1}]}`,
+		`{"action": "T", "schema": "s", "table": "t"}`,
+	} {
+		txn, err = d.decode([]byte(m), 0)
+		assert.Nil(t, err)
+		assert.Nil(t, txn)
+	}
+	txn, err = d.decode([]byte(`{"action": "C", "lsn": "0/280", "nextlsn": "0/2B0"}`), 0)
+	assert.Nil(t, err)
+	assert.Equal(t, &logicalTxn{LSN: 0x2B0, Changes: []logicalChange{
+		{Kind: 'U', Schema: "s", Table: "t", Cols: []string{"id", "v"}, Vals: []*string{str("2"), nil}, OldCols: []string{"id"}, OldVals: []*string{str("1")}},
+	}}, txn)
+
+	// Without LSNs, the LSN of the message is used.
+	_, err = d.decode([]byte(`{"change": []}`), 0)
+	assert.NotNil(t, err)
+	txn, err = d.decode([]byte(`{"change": []}`), 0x300)
+	assert.Nil(t, err)
+	assert.Equal(t, LSN(0x300), txn.LSN)
+
+	_, err = newLogicalDecoder("test_decoding")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,746 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/task"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+)
+
+// pg_dump archives (pg_dump -Fc and -Fd) start with a header and a table
+// of contents (TOC), whose layout is described in
+// src/bin/pg_dump/pg_backup_archiver.c of the PostgreSQL sources. The TOC
+// has an entry for each object dumped, holding the statement creating it.
+// The rows of a table are in a separate data block: in custom archives,
+// blocks follow the TOC in the same file, and in directory archives, each
+// block is a file of the directory, next to the TOC in toc.dat.
+const (
+	archiveMagic   = "PGDMP"
+	archiveTocFile = "toc.dat"
+
+	archiveCustom    = 1
+	archiveDirectory = 5
+
+	// States of the data of a TOC entry of a custom archive.
+	offsetPosNotSet = 1
+	offsetPosSet    = 2
+	offsetNoData    = 3
+
+	// Types of the data blocks of a custom archive.
+	blockData  = 1
+	blockBlobs = 3
+
+	compressionNone = 0
+	compressionGzip = 1
+	compressionLz4  = 2
+	compressionZstd = 3
+)
+
+// archiveVersion returns the archive format version 1.minor, as encoded by
+// pg_dump. Versions 1.10 (PostgreSQL 8.4) to 1.16 (PostgreSQL 17) are
+// supported.
+func archiveVersion(minor int) int {
+	return 1<<16 | minor<<8
+}
+
+// Archive is a pg_dump archive in the custom or directory format.
+type Archive struct {
+	Path        string
+	Entries     []ArchiveEntry
+	format      int
+	version     int
+	intSize     int
+	offSize     int
+	compression int
+}
+
+// ArchiveEntry is an entry of the TOC of an archive.
+type ArchiveEntry struct {
+	DumpId    int
+	Desc      string
+	Namespace string
+	Tag       string
+	// Defn is the statement creating the object, empty for data entries.
+	Defn string
+	// CopyStmt is the COPY statement of the rows of a TABLE DATA entry,
+	// empty if they were dumped as INSERT statements.
+	CopyStmt  string
+	dataState int
+	dataPos   int64
+	filename  string
+}
+
+// IsArchive reports whether path is a local pg_dump archive, i.e. a
+// directory with a toc.dat file or a file starting with the archive magic
+// string.
+func IsArchive(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	if info.IsDir() {
+		_, err := os.Stat(filepath.Join(path, archiveTocFile))
+		return err == nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	b := make([]byte, len(archiveMagic))
+	_, err = io.ReadFull(f, b)
+	return err == nil && string(b) == archiveMagic
+}
+
+// OpenArchive reads the TOC of the custom archive file or directory
+// archive at path.
+func OpenArchive(path string) (*Archive, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't read pg_dump archive %s: %v", path, err)
+	}
+	tocPath, format := path, archiveCustom
+	if info.IsDir() {
+		tocPath, format = filepath.Join(path, archiveTocFile), archiveDirectory
+	}
+	f, err := os.Open(tocPath)
+	if err != nil {
+		return nil, fmt.Errorf("can't read pg_dump archive %s: %v", path, err)
+	}
+	defer f.Close()
+	ar := &archiveReader{r: bufio.NewReader(f)}
+	a, err := readArchiveToc(ar)
+	if err != nil {
+		return nil, fmt.Errorf("can't read pg_dump archive %s: %v", path, err)
+	}
+	if a.format != format {
+		return nil, fmt.Errorf("can't read pg_dump archive %s: unexpected archive format %d", path, a.format)
+	}
+	a.Path = path
+	if format == archiveCustom && a.hasUnsetOffsets() {
+		// Archives written to a pipe don't record where their data blocks
+		// are.
+		if err := a.indexBlocks(f, ar.n); err != nil {
+			return nil, fmt.Errorf("can't read pg_dump archive %s: %v", path, err)
+		}
+	}
+	return a, nil
+}
+
+// Process processes the archive in the mode of conv: in schema mode, the
+// statements of the TOC are processed as a plain text dump would be, and
+// the rows of the data entries are counted. In data mode, the rows are
+// converted and written to the data sink of conv. Data entries are
+// processed in parallel.
+func (a *Archive) Process(conv *internal.Conv, workers int) error {
+	if conv.SchemaMode() {
+		if err := a.processSchema(conv); err != nil {
+			return err
+		}
+	}
+	processEntry := func(e ArchiveEntry, mutex *sync.Mutex) task.TaskResult[int] {
+		data, err := a.openData(e)
+		if err != nil {
+			return task.TaskResult[int]{Result: e.DumpId, Err: err}
+		}
+		defer data.Close()
+		if err := processDataEntry(conv, e, data); err != nil {
+			return task.TaskResult[int]{Result: e.DumpId, Err: err}
+		}
+		return task.TaskResult[int]{Result: e.DumpId}
+	}
+	r := task.RunParallelTasksImpl[ArchiveEntry, int]{}
+	_, err := r.RunParallelTasks(a.dataEntries(), workers, processEntry, true)
+	return err
+}
+
+// isArchiveStream reports whether r starts with the archive magic string.
+func isArchiveStream(r *internal.Reader) bool {
+	b, err := r.Peek(len(archiveMagic))
+	return err == nil && string(b) == archiveMagic
+}
+
+// processArchiveStream processes a custom archive read from r, such as
+// stdin, that doesn't allow random access: data blocks are processed one
+// after the other, in the order they appear in the archive.
+func processArchiveStream(conv *internal.Conv, r io.Reader) error {
+	ar := &archiveReader{r: r}
+	a, err := readArchiveToc(ar)
+	if err != nil {
+		return fmt.Errorf("can't read pg_dump archive: %v", err)
+	}
+	if a.format != archiveCustom {
+		return fmt.Errorf("can't read pg_dump archive: directory archives must be read from their directory")
+	}
+	if conv.SchemaMode() {
+		if err := a.processSchema(conv); err != nil {
+			return err
+		}
+	}
+	entries := make(map[int]ArchiveEntry)
+	for _, e := range a.dataEntries() {
+		entries[e.DumpId] = e
+	}
+	for {
+		blockType, ok := ar.readBlockType()
+		if !ok {
+			break
+		}
+		dumpId := ar.readInt()
+		if ar.err != nil {
+			return fmt.Errorf("can't read pg_dump archive: %v", ar.err)
+		}
+		switch blockType {
+		case blockData:
+			chunks := &chunkReader{ar: ar}
+			if e, ok := entries[dumpId]; ok {
+				data, err := a.decompress(chunks)
+				if err != nil {
+					return err
+				}
+				err = processDataEntry(conv, e, data)
+				data.Close()
+				if err != nil {
+					return err
+				}
+			}
+			// Skip what the decompressor didn't need to read.
+			if _, err := io.Copy(io.Discard, chunks); err != nil {
+				return fmt.Errorf("can't read pg_dump archive: %v", err)
+			}
+		case blockBlobs:
+			if err := ar.skipBlobs(); err != nil {
+				return fmt.Errorf("can't read pg_dump archive: %v", err)
+			}
+		default:
+			return fmt.Errorf("can't read pg_dump archive: unknown data block type %d", blockType)
+		}
+	}
+	return ar.err
+}
+
+// processSchema processes the statements of the TOC, in order, as a plain
+// text dump.
+func (a *Archive) processSchema(conv *internal.Conv) error {
+	var b strings.Builder
+	for _, e := range a.Entries {
+		if e.Defn == "" {
+			continue
+		}
+		b.WriteString(e.Defn)
+		b.WriteString("\n")
+	}
+	return processPgDump(conv, internal.NewReader(bufio.NewReader(strings.NewReader(b.String())), nil))
+}
+
+// processDataEntry processes the rows of a TABLE DATA entry: its COPY
+// statement followed by data, or INSERT statements.
+func processDataEntry(conv *internal.Conv, e ArchiveEntry, data io.Reader) error {
+	r := data
+	if e.CopyStmt != "" {
+		r = io.MultiReader(strings.NewReader(e.CopyStmt), &copyDataReader{r: data})
+	}
+	if err := processPgDump(conv, internal.NewReader(bufio.NewReader(r), nil)); err != nil {
+		return fmt.Errorf("can't process data of table %s: %v", e.Tag, err)
+	}
+	return nil
+}
+
+func (a *Archive) dataEntries() []ArchiveEntry {
+	var entries []ArchiveEntry
+	for _, e := range a.Entries {
+		if e.Desc != "TABLE DATA" {
+			continue
+		}
+		if a.format == archiveCustom && e.dataState == offsetNoData {
+			continue
+		}
+		if a.format == archiveDirectory && e.filename == "" {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func (a *Archive) hasUnsetOffsets() bool {
+	for _, e := range a.Entries {
+		if e.dataState == offsetPosNotSet {
+			return true
+		}
+	}
+	return false
+}
+
+// indexBlocks finds the data blocks of a custom archive that doesn't
+// record their positions, by skipping from one block to the next starting
+// at offset start.
+func (a *Archive) indexBlocks(f *os.File, start int64) error {
+	pos := make(map[int]int64)
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		return err
+	}
+	ar := &archiveReader{r: f, intSize: a.intSize, offSize: a.offSize, version: a.version, seeker: f, n: start}
+	for {
+		blockStart := ar.n
+		blockType, ok := ar.readBlockType()
+		if !ok {
+			break
+		}
+		dumpId := ar.readInt()
+		switch blockType {
+		case blockData:
+			pos[dumpId] = blockStart
+			ar.skipChunks()
+		case blockBlobs:
+			ar.skipBlobs()
+		default:
+			return fmt.Errorf("unknown data block type %d", blockType)
+		}
+		if ar.err != nil {
+			return ar.err
+		}
+	}
+	if ar.err != nil {
+		return ar.err
+	}
+	for i, e := range a.Entries {
+		if p, ok := pos[e.DumpId]; ok && e.dataState == offsetPosNotSet {
+			a.Entries[i].dataState = offsetPosSet
+			a.Entries[i].dataPos = p
+		}
+	}
+	return nil
+}
+
+// openData returns the decompressed data of entry e.
+func (a *Archive) openData(e ArchiveEntry) (io.ReadCloser, error) {
+	if a.format == archiveDirectory {
+		return a.openDataFile(e)
+	}
+	if e.dataState != offsetPosSet {
+		return nil, fmt.Errorf("can't find the data of table %s in pg_dump archive", e.Tag)
+	}
+	f, err := os.Open(a.Path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(e.dataPos, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	ar := &archiveReader{r: bufio.NewReader(f), intSize: a.intSize, offSize: a.offSize, version: a.version}
+	blockType, _ := ar.readBlockType()
+	dumpId := ar.readInt()
+	if ar.err != nil || blockType != blockData || dumpId != e.DumpId {
+		f.Close()
+		return nil, fmt.Errorf("can't find the data of table %s in pg_dump archive", e.Tag)
+	}
+	data, err := a.decompress(&chunkReader{ar: ar})
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &closers{Reader: data, closers: []io.Closer{data, f}}, nil
+}
+
+// openDataFile opens the data file of entry e of a directory archive. The
+// TOC names the file without the suffix of its compression method.
+func (a *Archive) openDataFile(e ArchiveEntry) (io.ReadCloser, error) {
+	for _, suffix := range []string{"", ".gz", ".lz4", ".zst"} {
+		f, err := os.Open(filepath.Join(a.Path, e.filename+suffix))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var data io.Reader
+		switch suffix {
+		case "":
+			return f, nil
+		case ".gz":
+			data, err = gzip.NewReader(bufio.NewReader(f))
+		case ".lz4":
+			data = lz4.NewReader(f)
+		case ".zst":
+			var d *zstd.Decoder
+			d, err = zstd.NewReader(f, zstd.WithDecoderConcurrency(1))
+			if err == nil {
+				data = d.IOReadCloser()
+			}
+		}
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("can't read data file %s of pg_dump archive: %v", e.filename+suffix, err)
+		}
+		return &closers{Reader: data, closers: []io.Closer{asCloser(data), f}}, nil
+	}
+	return nil, fmt.Errorf("can't find data file %s of table %s in pg_dump archive", e.filename, e.Tag)
+}
+
+// decompress returns the data read from the chunks of a data block of a
+// custom archive, which are compressed as a single stream.
+func (a *Archive) decompress(r io.Reader) (io.ReadCloser, error) {
+	switch a.compression {
+	case compressionNone:
+		return io.NopCloser(r), nil
+	case compressionGzip:
+		// Blocks are compressed with zlib, not in the gzip format.
+		br := bufio.NewReader(r)
+		if _, err := br.Peek(1); err == io.EOF {
+			return io.NopCloser(br), nil
+		}
+		z, err := zlib.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("can't decompress pg_dump archive data: %v", err)
+		}
+		return z, nil
+	case compressionLz4:
+		return io.NopCloser(lz4.NewReader(r)), nil
+	case compressionZstd:
+		d, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, fmt.Errorf("can't decompress pg_dump archive data: %v", err)
+		}
+		return d.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression method %d in pg_dump archive", a.compression)
+	}
+}
+
+// readArchiveToc reads the header and TOC of an archive.
+func readArchiveToc(ar *archiveReader) (*Archive, error) {
+	magic := make([]byte, len(archiveMagic))
+	if _, err := io.ReadFull(ar, magic); err != nil || string(magic) != archiveMagic {
+		return nil, fmt.Errorf("not a pg_dump archive")
+	}
+	a := &Archive{}
+	major, minor, rev := ar.readByte(), ar.readByte(), ar.readByte()
+	a.version = int(major)<<16 | int(minor)<<8 | int(rev)
+	if major != 1 || a.version < archiveVersion(10) || a.version >= archiveVersion(17) {
+		return nil, fmt.Errorf("unsupported archive version %d.%d", major, minor)
+	}
+	a.intSize = int(ar.readByte())
+	a.offSize = int(ar.readByte())
+	a.format = int(ar.readByte())
+	ar.intSize, ar.offSize, ar.version = a.intSize, a.offSize, a.version
+	if a.intSize == 0 || a.intSize > 8 || a.offSize == 0 || a.offSize > 8 {
+		return nil, fmt.Errorf("unsupported integer sizes %d and %d", a.intSize, a.offSize)
+	}
+	if a.version >= archiveVersion(15) {
+		a.compression = int(ar.readByte())
+	} else if ar.readInt() != 0 {
+		a.compression = compressionGzip
+	}
+	for i := 0; i < 7; i++ {
+		ar.readInt() // Creation time.
+	}
+	ar.readStr() // Database name.
+	ar.readStr() // Server version.
+	ar.readStr() // pg_dump version.
+	n := ar.readInt()
+	for i := 0; i < n && ar.err == nil; i++ {
+		a.Entries = append(a.Entries, ar.readTocEntry(a.format))
+	}
+	if ar.err != nil {
+		return nil, ar.err
+	}
+	return a, nil
+}
+
+// archiveReader reads the integers and strings of an archive. The first
+// error encountered is kept in err, after which reads return zero values.
+type archiveReader struct {
+	r       io.Reader
+	seeker  io.Seeker
+	n       int64
+	err     error
+	intSize int
+	offSize int
+	version int
+}
+
+func (ar *archiveReader) Read(p []byte) (int, error) {
+	if ar.err != nil {
+		return 0, ar.err
+	}
+	n, err := ar.r.Read(p)
+	ar.n += int64(n)
+	return n, err
+}
+
+func (ar *archiveReader) readByte() byte {
+	var b [1]byte
+	if ar.err != nil {
+		return 0
+	}
+	if _, err := io.ReadFull(ar, b[:]); err != nil {
+		ar.err = fmt.Errorf("unexpected end of archive: %v", err)
+		return 0
+	}
+	return b[0]
+}
+
+// readBlockType reads the type of the next data block, returning false at
+// the end of the archive.
+func (ar *archiveReader) readBlockType() (int, bool) {
+	var b [1]byte
+	if ar.err != nil {
+		return 0, false
+	}
+	if _, err := io.ReadFull(ar, b[:]); err != nil {
+		if err != io.EOF {
+			ar.err = err
+		}
+		return 0, false
+	}
+	return int(b[0]), true
+}
+
+// readInt reads an integer, stored as a sign byte followed by intSize
+// bytes, least significant first.
+func (ar *archiveReader) readInt() int {
+	sign := ar.readByte()
+	v := 0
+	for i := 0; i < ar.intSize; i++ {
+		v |= int(ar.readByte()) << (8 * i)
+	}
+	if sign != 0 {
+		v = -v
+	}
+	return v
+}
+
+// readStr reads a string, stored as its length followed by its bytes. A
+// length of -1 stands for NULL, returned as "" with ok false.
+func (ar *archiveReader) readStr() (s string, ok bool) {
+	n := ar.readInt()
+	if ar.err != nil || n < 0 {
+		return "", false
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(ar, b); err != nil {
+		ar.err = fmt.Errorf("unexpected end of archive: %v", err)
+		return "", false
+	}
+	return string(b), true
+}
+
+// readOffset reads the state and position of the data of a TOC entry.
+func (ar *archiveReader) readOffset() (int, int64) {
+	state := int(ar.readByte())
+	var pos int64
+	for i := 0; i < ar.offSize; i++ {
+		pos |= int64(ar.readByte()) << (8 * i)
+	}
+	return state, pos
+}
+
+func (ar *archiveReader) readTocEntry(format int) ArchiveEntry {
+	e := ArchiveEntry{}
+	e.DumpId = ar.readInt()
+	ar.readInt() // Whether the entry has data.
+	ar.readStr() // Table OID of the catalog.
+	ar.readStr() // OID.
+	e.Tag, _ = ar.readStr()
+	e.Desc, _ = ar.readStr()
+	if ar.version >= archiveVersion(11) {
+		ar.readInt() // Section.
+	}
+	e.Defn, _ = ar.readStr()
+	ar.readStr() // DROP statement.
+	e.CopyStmt, _ = ar.readStr()
+	e.Namespace, _ = ar.readStr()
+	ar.readStr() // Tablespace.
+	if ar.version >= archiveVersion(14) {
+		ar.readStr() // Table access method.
+	}
+	if ar.version >= archiveVersion(16) {
+		ar.readInt() // Relation kind.
+	}
+	ar.readStr() // Owner.
+	ar.readStr() // WITH OIDS.
+	for ar.err == nil {
+		if _, ok := ar.readStr(); !ok {
+			break // End of the dependencies.
+		}
+	}
+	switch format {
+	case archiveCustom:
+		e.dataState, e.dataPos = ar.readOffset()
+	case archiveDirectory:
+		e.filename, _ = ar.readStr()
+	}
+	return e
+}
+
+// skip skips n bytes of input.
+func (ar *archiveReader) skip(n int) {
+	if ar.err != nil {
+		return
+	}
+	if ar.seeker != nil {
+		if _, err := ar.seeker.Seek(int64(n), io.SeekCurrent); err != nil {
+			ar.err = err
+		}
+		ar.n += int64(n)
+		return
+	}
+	if _, err := io.CopyN(io.Discard, ar, int64(n)); err != nil {
+		ar.err = fmt.Errorf("unexpected end of archive: %v", err)
+	}
+}
+
+// skipChunks skips the chunks of a data block.
+func (ar *archiveReader) skipChunks() {
+	for ar.err == nil {
+		n := ar.readInt()
+		if n == 0 {
+			return
+		}
+		ar.skip(n)
+	}
+}
+
+// skipBlobs skips a block of large objects, each stored as its OID followed
+// by its chunks, up to an OID of 0.
+func (ar *archiveReader) skipBlobs() error {
+	for ar.err == nil {
+		if oid := ar.readInt(); oid == 0 {
+			break
+		}
+		ar.skipChunks()
+	}
+	return ar.err
+}
+
+// chunkReader reads the data of a block of a custom archive, stored as
+// chunks each preceded by its length, up to a chunk of length 0.
+type chunkReader struct {
+	ar   *archiveReader
+	left int
+	done bool
+}
+
+func (c *chunkReader) Read(p []byte) (int, error) {
+	for c.left == 0 {
+		if c.done {
+			return 0, io.EOF
+		}
+		n := c.ar.readInt()
+		if c.ar.err != nil {
+			return 0, c.ar.err
+		}
+		if n < 0 {
+			return 0, fmt.Errorf("invalid chunk length %d in pg_dump archive", n)
+		}
+		if n == 0 {
+			c.done = true
+			return 0, io.EOF
+		}
+		c.left = n
+	}
+	if len(p) > c.left {
+		p = p[:c.left]
+	}
+	n, err := c.ar.Read(p)
+	c.left -= n
+	if err == io.EOF && c.left > 0 {
+		err = io.ErrUnexpectedEOF
+	} else if err == io.EOF {
+		err = nil
+	}
+	return n, err
+}
+
+// copyDataReader reads the data of a COPY statement, adding the \. line
+// that ends it if missing.
+type copyDataReader struct {
+	r    io.Reader
+	tail []byte
+	rest []byte
+	eof  bool
+}
+
+func (c *copyDataReader) Read(p []byte) (int, error) {
+	if !c.eof {
+		n, err := c.r.Read(p)
+		if n > 0 {
+			c.tail = append(c.tail, p[:n]...)
+			if len(c.tail) > 16 {
+				c.tail = append([]byte{}, c.tail[len(c.tail)-16:]...)
+			}
+		}
+		if err != io.EOF {
+			return n, err
+		}
+		c.eof = true
+		last := bytes.TrimRight(c.tail, "\r\n")
+		switch {
+		case string(last) == `\.` || bytes.HasSuffix(last, []byte("\n\\.")):
+		case len(c.tail) > 0 && c.tail[len(c.tail)-1] != '\n':
+			c.rest = []byte("\n\\.\n")
+		default:
+			c.rest = []byte("\\.\n")
+		}
+		if n > 0 {
+			return n, nil
+		}
+	}
+	if len(c.rest) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, c.rest)
+	c.rest = c.rest[n:]
+	return n, nil
+}
+
+// closers closes all its closers when closed.
+type closers struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (c *closers) Close() error {
+	var err error
+	for _, cl := range c.closers {
+		if e := cl.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func asCloser(r io.Reader) io.Closer {
+	if c, ok := r.(io.Closer); ok {
+		return c
+	}
+	return io.NopCloser(r)
+}
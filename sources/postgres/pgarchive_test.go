@@ -0,0 +1,368 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/expressions_api"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/mocks"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+)
+
+// testArchiveEntry is a TOC entry written by testArchiveWriter.
+type testArchiveEntry struct {
+	dumpId   int
+	desc     string
+	tag      string
+	defn     string
+	copyStmt string
+	data     string
+}
+
+// testArchiveWriter writes archives in the layout of pg_dump, with 4-byte
+// integers and 8-byte offsets.
+type testArchiveWriter struct {
+	b        bytes.Buffer
+	minor    int
+	compress bool
+}
+
+func (w *testArchiveWriter) writeInt(v int) {
+	sign := byte(0)
+	if v < 0 {
+		sign, v = 1, -v
+	}
+	w.b.WriteByte(sign)
+	for i := 0; i < 4; i++ {
+		w.b.WriteByte(byte(v >> (8 * i)))
+	}
+}
+
+func (w *testArchiveWriter) writeStr(s string, null bool) {
+	if null {
+		w.writeInt(-1)
+		return
+	}
+	w.writeInt(len(s))
+	w.b.WriteString(s)
+}
+
+func (w *testArchiveWriter) writeHeader(format int) {
+	w.b.WriteString(archiveMagic)
+	w.b.Write([]byte{1, byte(w.minor), 0, 4, 8, byte(format)})
+	switch {
+	case w.minor >= 15 && w.compress:
+		w.b.WriteByte(compressionGzip)
+	case w.minor >= 15:
+		w.b.WriteByte(compressionNone)
+	case w.compress:
+		w.writeInt(-1)
+	default:
+		w.writeInt(0)
+	}
+	for i := 0; i < 7; i++ {
+		w.writeInt(0)
+	}
+	w.writeStr("db", false)
+	w.writeStr("16.1", false)
+	w.writeStr("16.1", false)
+}
+
+func (w *testArchiveWriter) writeTocEntry(e testArchiveEntry, format int, pos int64, posSet bool) {
+	w.writeInt(e.dumpId)
+	w.writeInt(0)
+	w.writeStr("0", false)
+	w.writeStr("0", false)
+	w.writeStr(e.tag, false)
+	w.writeStr(e.desc, false)
+	if w.minor >= 11 {
+		w.writeInt(2)
+	}
+	w.writeStr(e.defn, false)
+	w.writeStr("", false)
+	w.writeStr(e.copyStmt, e.copyStmt == "")
+	w.writeStr("public", false)
+	w.writeStr("", false)
+	if w.minor >= 14 {
+		w.writeStr("heap", false)
+	}
+	if w.minor >= 16 {
+		w.writeInt('r')
+	}
+	w.writeStr("postgres", false)
+	w.writeStr("false", false)
+	w.writeStr("1", false)
+	w.writeStr("", true)
+	switch {
+	case format == archiveDirectory && e.desc == "TABLE DATA":
+		w.writeStr(filepath.Base(testDataFile(e)), false)
+	case format == archiveDirectory:
+		w.writeStr("", true)
+	case e.desc != "TABLE DATA":
+		w.b.WriteByte(offsetNoData)
+		w.b.Write(make([]byte, 8))
+	case posSet:
+		w.b.WriteByte(offsetPosSet)
+		for i := 0; i < 8; i++ {
+			w.b.WriteByte(byte(pos >> (8 * i)))
+		}
+	default:
+		w.b.WriteByte(offsetPosNotSet)
+		w.b.Write(make([]byte, 8))
+	}
+}
+
+func testDataFile(e testArchiveEntry) string {
+	return fmt.Sprintf("%d.dat", e.dumpId)
+}
+
+func (w *testArchiveWriter) blockData(data string) []byte {
+	if !w.compress {
+		return []byte(data)
+	}
+	var b bytes.Buffer
+	z := zlib.NewWriter(&b)
+	z.Write([]byte(data))
+	z.Close()
+	return b.Bytes()
+}
+
+// customArchive returns a custom archive holding entries. Data blocks are
+// split in chunks of 10 bytes, and their positions are recorded in the
+// TOC if posSet.
+func (w *testArchiveWriter) customArchive(entries []testArchiveEntry, posSet bool) []byte {
+	// The TOC has the same length whatever the positions, so write it once
+	// to find where the blocks start.
+	pos := make(map[int]int64)
+	for pass := 0; pass < 2; pass++ {
+		w.b.Reset()
+		w.writeHeader(archiveCustom)
+		w.writeInt(len(entries))
+		for _, e := range entries {
+			w.writeTocEntry(e, archiveCustom, pos[e.dumpId], posSet)
+		}
+		for _, e := range entries {
+			if e.desc != "TABLE DATA" {
+				continue
+			}
+			pos[e.dumpId] = int64(w.b.Len())
+			w.b.WriteByte(blockData)
+			w.writeInt(e.dumpId)
+			data := w.blockData(e.data)
+			for len(data) > 0 {
+				n := min(10, len(data))
+				w.writeInt(n)
+				w.b.Write(data[:n])
+				data = data[n:]
+			}
+			w.writeInt(0)
+		}
+	}
+	return w.b.Bytes()
+}
+
+// directoryArchive writes a directory archive holding entries to dir, with
+// gzip compressed data files if compress.
+func (w *testArchiveWriter) directoryArchive(t *testing.T, dir string, entries []testArchiveEntry) {
+	w.b.Reset()
+	w.writeHeader(archiveDirectory)
+	w.writeInt(len(entries))
+	for _, e := range entries {
+		w.writeTocEntry(e, archiveDirectory, 0, false)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, archiveTocFile), w.b.Bytes(), 0644))
+	for _, e := range entries {
+		if e.desc != "TABLE DATA" {
+			continue
+		}
+		if !w.compress {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, testDataFile(e)), []byte(e.data), 0644))
+			continue
+		}
+		var b bytes.Buffer
+		z := gzip.NewWriter(&b)
+		z.Write([]byte(e.data))
+		z.Close()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, testDataFile(e)+".gz"), b.Bytes(), 0644))
+	}
+}
+
+var testArchiveEntries = []testArchiveEntry{
+	{dumpId: 1, desc: "TABLE", tag: "t1", defn: "CREATE TABLE public.t1 (\n    a bigint NOT NULL,\n    b text\n);"},
+	{dumpId: 2, desc: "TABLE", tag: "t2", defn: "CREATE TABLE public.t2 (\n    c bigint NOT NULL\n);"},
+	{dumpId: 3, desc: "TABLE DATA", tag: "t1", copyStmt: "COPY public.t1 (a, b) FROM stdin;\n", data: "1\tone\n2\ttwo\n3\tthree\n\\.\n\n\n"},
+	{dumpId: 4, desc: "TABLE DATA", tag: "t2", copyStmt: "COPY public.t2 (c) FROM stdin;\n", data: "7\n8\n"},
+	{dumpId: 5, desc: "CONSTRAINT", tag: "t1 t1_pkey", defn: "ALTER TABLE ONLY public.t1\n    ADD CONSTRAINT t1_pkey PRIMARY KEY (a);"},
+	{dumpId: 6, desc: "CONSTRAINT", tag: "t2 t2_pkey", defn: "ALTER TABLE ONLY public.t2\n    ADD CONSTRAINT t2_pkey PRIMARY KEY (c);"},
+}
+
+// runProcessArchive processes an archive in schema mode and then in data
+// mode, as runProcessPgDump does for plain text dumps.
+func runProcessArchive(t *testing.T, pgDump DbDumpImpl, dump []byte) (*internal.Conv, []spannerData) {
+	conv := internal.MakeConv()
+	conv.SetLocation(time.UTC)
+	conv.SetSchemaMode()
+	mockAccessor := new(mocks.MockExpressionVerificationAccessor)
+	mockAccessor.On("VerifyExpressions", context.Background(), mock.Anything).Return(internal.VerifyExpressionsOutput{})
+	require.NoError(t, common.ProcessDbDump(conv, internal.NewReader(bufio.NewReader(bytes.NewReader(dump)), nil), pgDump, &expressions_api.MockDDLVerifier{}, mockAccessor))
+	conv.SetDataMode()
+	var rows []spannerData
+	var mu sync.Mutex
+	conv.SetDataSink(
+		func(table string, cols []string, vals []interface{}) {
+			mu.Lock()
+			defer mu.Unlock()
+			rows = append(rows, spannerData{table: table, cols: cols, vals: vals})
+		})
+	require.NoError(t, common.ProcessDbDump(conv, internal.NewReader(bufio.NewReader(bytes.NewReader(dump)), nil), pgDump, &expressions_api.MockDDLVerifier{}, mockAccessor))
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].table != rows[j].table {
+			return rows[i].table < rows[j].table
+		}
+		return rows[i].vals[0].(int64) < rows[j].vals[0].(int64)
+	})
+	return conv, rows
+}
+
+func checkArchiveConv(t *testing.T, name string, conv *internal.Conv, rows []spannerData) {
+	noIssues(conv, t, name)
+	assert.Equal(t, int64(5), conv.Rows(), name)
+	tableId, err := internal.GetTableIdFromSpName(conv.SpSchema, "t1")
+	require.NoError(t, err, name)
+	assert.Equal(t, 1, len(conv.SpSchema[tableId].PrimaryKeys), name)
+	assert.Equal(t, []spannerData{
+		{table: "t1", cols: []string{"a", "b"}, vals: []interface{}{int64(1), "one"}},
+		{table: "t1", cols: []string{"a", "b"}, vals: []interface{}{int64(2), "two"}},
+		{table: "t1", cols: []string{"a", "b"}, vals: []interface{}{int64(3), "three"}},
+		{table: "t2", cols: []string{"c"}, vals: []interface{}{int64(7)}},
+		{table: "t2", cols: []string{"c"}, vals: []interface{}{int64(8)}},
+	}, rows, name)
+}
+
+func TestProcessPgArchive_Custom(t *testing.T) {
+	testCases := []struct {
+		name     string
+		minor    int
+		compress bool
+		posSet   bool
+	}{
+		{"uncompressed 1.14", 14, false, true},
+		{"gzip 1.14", 14, true, true},
+		{"gzip 1.15", 15, true, true},
+		{"written to a pipe 1.16", 16, true, false},
+		{"uncompressed 1.10", 10, false, false},
+	}
+	for _, tc := range testCases {
+		w := &testArchiveWriter{minor: tc.minor, compress: tc.compress}
+		dump := w.customArchive(testArchiveEntries, tc.posSet)
+
+		conv, rows := runProcessArchive(t, DbDumpImpl{}, dump)
+		checkArchiveConv(t, tc.name+" stream", conv, rows)
+
+		path := filepath.Join(t.TempDir(), "dump.custom")
+		require.NoError(t, os.WriteFile(path, dump, 0644))
+		assert.True(t, IsArchive(path), tc.name)
+		conv, rows = runProcessArchive(t, DbDumpImpl{ArchivePath: path}, nil)
+		checkArchiveConv(t, tc.name+" file", conv, rows)
+	}
+}
+
+func TestProcessPgArchive_Directory(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		dir := t.TempDir()
+		w := &testArchiveWriter{minor: 16, compress: compress}
+		w.directoryArchive(t, dir, testArchiveEntries)
+		assert.True(t, IsArchive(dir))
+		conv, rows := runProcessArchive(t, DbDumpImpl{ArchivePath: dir}, nil)
+		checkArchiveConv(t, "directory", conv, rows)
+	}
+}
+
+func TestOpenArchive(t *testing.T) {
+	w := &testArchiveWriter{minor: 15}
+	path := filepath.Join(t.TempDir(), "dump.custom")
+	require.NoError(t, os.WriteFile(path, w.customArchive(testArchiveEntries, true), 0644))
+	a, err := OpenArchive(path)
+	require.NoError(t, err)
+	assert.Equal(t, 6, len(a.Entries))
+	assert.Equal(t, "t1", a.Entries[0].Tag)
+	assert.Equal(t, "public", a.Entries[0].Namespace)
+	assert.Equal(t, "COPY public.t1 (a, b) FROM stdin;\n", a.Entries[2].CopyStmt)
+	assert.Equal(t, []int{3, 4}, []int{a.dataEntries()[0].DumpId, a.dataEntries()[1].DumpId})
+
+	// Directory archives must be opened by their directory.
+	_, err = OpenArchive(path + ".missing")
+	assert.Error(t, err)
+	dir := t.TempDir()
+	w.directoryArchive(t, dir, testArchiveEntries)
+	_, err = OpenArchive(filepath.Join(dir, archiveTocFile))
+	assert.Error(t, err)
+
+	// Unsupported versions.
+	for _, minor := range []int{9, 17} {
+		w := &testArchiveWriter{minor: minor}
+		require.NoError(t, os.WriteFile(path, w.customArchive(nil, true), 0644))
+		_, err := OpenArchive(path)
+		assert.Error(t, err)
+	}
+}
+
+func TestIsArchive(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "dump.sql")
+	require.NoError(t, os.WriteFile(plain, []byte("CREATE TABLE t (a bigint);\n"), 0644))
+	assert.False(t, IsArchive(plain))
+	assert.False(t, IsArchive(dir))
+	assert.False(t, IsArchive(filepath.Join(dir, "missing")))
+}
+
+func TestCopyDataReader(t *testing.T) {
+	testCases := []struct {
+		data     string
+		expected string
+	}{
+		{"", "\\.\n"},
+		{"1\n2\n", "1\n2\n\\.\n"},
+		{"1\n2", "1\n2\n\\.\n"},
+		{"1\n2\n\\.\n", "1\n2\n\\.\n"},
+		{"1\n2\n\\.\n\n\n", "1\n2\n\\.\n\n\n"},
+		{"\\.\n", "\\.\n"},
+		{"a\\.\n", "a\\.\n\\.\n"},
+	}
+	for _, tc := range testCases {
+		b, err := io.ReadAll(&copyDataReader{r: strings.NewReader(tc.data)})
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, string(b), tc.data)
+	}
+}
@@ -34,6 +34,11 @@ import (
 
 // DbDumpImpl Postgres specific implementation for DdlDumpImpl.
 type DbDumpImpl struct {
+	// ArchivePath is the path of a local pg_dump archive in the custom or
+	// directory format. If set, the archive is read from there instead of
+	// from the reader passed to ProcessDump, so that its tables can be
+	// processed in parallel.
+	ArchivePath string
 }
 
 type copyOrInsert struct {
@@ -55,8 +60,19 @@ func (ddi DbDumpImpl) GetToDdl() common.ToDdl {
 	return ToDdlImpl{}
 }
 
-// ProcessDump calls processPgDump to read a Postgres dump file
+// ProcessDump calls processPgDump to read a Postgres dump file. Archives
+// in the custom format (pg_dump -Fc) are recognized and read as well.
 func (ddi DbDumpImpl) ProcessDump(conv *internal.Conv, r *internal.Reader) error {
+	if ddi.ArchivePath != "" {
+		a, err := OpenArchive(ddi.ArchivePath)
+		if err != nil {
+			return err
+		}
+		return a.Process(conv, common.DefaultWorkers)
+	}
+	if isArchiveStream(r) {
+		return processArchiveStream(conv, r)
+	}
 	return processPgDump(conv, r)
 }
 
@@ -73,7 +89,10 @@ func processPgDump(conv *internal.Conv, r *internal.Reader) error {
 		if err != nil {
 			return err
 		}
+		// The tables of an archive are processed concurrently.
+		conv.ConvLock.Lock()
 		ci := processStatements(conv, stmts)
+		conv.ConvLock.Unlock()
 		internal.VerbosePrintf("Parsed SQL command at line=%d/fpos=%d: %d stmts (%d lines, %d bytes) ci=%v\n", startLine, startOffset, len(stmts), r.LineNumber-startLine, len(b), ci != nil)
 		logger.Log.Debug(fmt.Sprintf("Parsed SQL command at line=%d/fpos=%d: %d stmts (%d lines, %d bytes) ci=%v\n", startLine, startOffset, len(stmts), r.LineNumber-startLine, len(b), ci != nil))
 		if ci != nil {
@@ -103,23 +122,27 @@ func processPgDump(conv *internal.Conv, r *internal.Reader) error {
 					return err
 				}
 				colNameIdMap := internal.GetSrcColNameIdMap(conv.SrcSchema[ci.table])
+				conv.ConvLock.Lock()
 				for _, vals := range ci.rows {
 					newVals, err := common.PrepareValues(conv, ci.table, colNameIdMap, commonColIds, colNames, vals)
 					if err != nil {
 						srcTableName := conv.SrcSchema[ci.table].Name
 						conv.Unexpected(fmt.Sprintf("Error while converting data: %s\n", err))
 						conv.StatsAddBadRow(srcTableName, conv.DataMode())
-						conv.CollectBadRow(srcTableName, colNames, vals)
+						conv.CollectBadRow(srcTableName, colNames, vals, err)
 						continue
 					}
 					ProcessDataRow(conv, ci.table, commonColIds, newVals)
 				}
+				conv.ConvLock.Unlock()
 			}
 		}
 		if r.EOF {
 			break
 		}
 	}
+	conv.ConvLock.Lock()
+	defer conv.ConvLock.Unlock()
 	internal.ResolveForeignKeyIds(conv.SrcSchema)
 	// We don't actually support migration of sequences for Postgres, but some get set in order to properly
 	// identify SERIAL columns. In order to avoid migrating these sequences, we unset them here.
@@ -155,7 +178,9 @@ func readAndParseChunk(conv *internal.Conv, r *internal.Reader) ([]byte, []*pg_q
 			// b) a semicolon embedded in a multi-line comment, or
 			// c) a semicolon embedded a string constant or column/table name.
 			// We deal with this case by reading another line and trying again.
+			conv.ConvLock.Lock()
 			conv.Stats.Reparsed++
+			conv.ConvLock.Unlock()
 		}
 		if r.EOF {
 			return nil, nil, fmt.Errorf("error parsing last %d line(s) of input", len(l))
@@ -164,7 +189,6 @@ func readAndParseChunk(conv *internal.Conv, r *internal.Reader) ([]byte, []*pg_q
 }
 
 func processCopyBlock(conv *internal.Conv, tableId string, commonColIds, srcCols []string, r *internal.Reader) {
-	srcTableName := conv.SrcSchema[tableId].Name
 	internal.VerbosePrintf("Parsing COPY-FROM stdin block starting at line=%d/fpos=%d\n", r.LineNumber, r.Offset)
 	logger.Log.Debug(fmt.Sprintf("Parsing COPY-FROM stdin block starting at line=%d/fpos=%d\n", r.LineNumber, r.Offset))
 	for {
@@ -175,38 +199,47 @@ func processCopyBlock(conv *internal.Conv, tableId string, commonColIds, srcCols
 			return
 		}
 		if r.EOF {
+			conv.ConvLock.Lock()
 			conv.Unexpected("Reached eof while parsing copy-block")
+			conv.ConvLock.Unlock()
 			return
 		}
-		conv.StatsAddRow(srcTableName, conv.SchemaMode())
-		// We have to read the copy-block data so that we can process the remaining
-		// pg_dump content. However, if we don't want the data, stop here.
-		// In particular, avoid the strings.Split and ProcessDataRow calls below, which
-		// will be expensive for huge datasets.
-		if !conv.DataMode() {
-			continue
-		}
-		// pg_dump escapes backslash in copy-block statements. For example:
-		// a) a\"b becomes a\\"b in COPY-BLOCK (but 'a\"b' in INSERT-INTO)
-		// b) {"a\"b"} becomes {"a\\"b"} in COPY-BLOCK (but '{"a\"b"}' in INSERT-INTO)
-		// Note: a'b and {a'b} are unchanged in COPY-BLOCK and INSERT-INTO.
-		s := strings.ReplaceAll(string(b), `\\`, `\`)
-		// COPY-FROM blocks use tabs to separate data items. Note that space within data
-		// items is significant e.g. if a table row contains data items "a ", " b "
-		// it will be shown in the COPY-FROM block as "a \t b ".
-		values := strings.Split(strings.Trim(s, "\r\n"), "\t")
-		colNameIdMap := internal.GetSrcColNameIdMap(conv.SrcSchema[tableId])
-		newValues, err := common.PrepareValues(conv, tableId, colNameIdMap, commonColIds, srcCols, values)
-		if err != nil {
-			conv.Unexpected(fmt.Sprintf("Error while converting data: %s\n", err))
-			conv.StatsAddBadRow(srcTableName, conv.DataMode())
-			conv.CollectBadRow(srcTableName, srcCols, values)
-			continue
-		}
-		ProcessDataRow(conv, tableId, commonColIds, newValues)
+		conv.ConvLock.Lock()
+		processCopyRow(conv, tableId, commonColIds, srcCols, b)
+		conv.ConvLock.Unlock()
 	}
 }
 
+func processCopyRow(conv *internal.Conv, tableId string, commonColIds, srcCols []string, b []byte) {
+	srcTableName := conv.SrcSchema[tableId].Name
+	conv.StatsAddRow(srcTableName, conv.SchemaMode())
+	// We have to read the copy-block data so that we can process the remaining
+	// pg_dump content. However, if we don't want the data, stop here.
+	// In particular, avoid the strings.Split and ProcessDataRow calls below, which
+	// will be expensive for huge datasets.
+	if !conv.DataMode() {
+		return
+	}
+	// pg_dump escapes backslash in copy-block statements. For example:
+	// a) a\"b becomes a\\"b in COPY-BLOCK (but 'a\"b' in INSERT-INTO)
+	// b) {"a\"b"} becomes {"a\\"b"} in COPY-BLOCK (but '{"a\"b"}' in INSERT-INTO)
+	// Note: a'b and {a'b} are unchanged in COPY-BLOCK and INSERT-INTO.
+	s := strings.ReplaceAll(string(b), `\\`, `\`)
+	// COPY-FROM blocks use tabs to separate data items. Note that space within data
+	// items is significant e.g. if a table row contains data items "a ", " b "
+	// it will be shown in the COPY-FROM block as "a \t b ".
+	values := strings.Split(strings.Trim(s, "\r\n"), "\t")
+	colNameIdMap := internal.GetSrcColNameIdMap(conv.SrcSchema[tableId])
+	newValues, err := common.PrepareValues(conv, tableId, colNameIdMap, commonColIds, srcCols, values)
+	if err != nil {
+		conv.Unexpected(fmt.Sprintf("Error while converting data: %s\n", err))
+		conv.StatsAddBadRow(srcTableName, conv.DataMode())
+		conv.CollectBadRow(srcTableName, srcCols, values, err)
+		return
+	}
+	ProcessDataRow(conv, tableId, commonColIds, newValues)
+}
+
 // processStatements extracts schema information and data from PostgreSQL
 // statements, updating Conv with new schema information, and returning
 // copyOrInsert if a COPY-FROM or INSERT statement is encountered.
@@ -249,6 +282,10 @@ func processStatements(conv *internal.Conv, rawStmts []*pg_query.RawStmt) *copyO
 			if conv.SchemaMode() {
 				processAlterSeqStmt(conv, n.AlterSeqStmt)
 			}
+		case *pg_query.Node_ViewStmt:
+			if conv.SchemaMode() {
+				processViewStmt(conv, n.ViewStmt)
+			}
 		default:
 			conv.SkipStatement(printNodeType(n))
 		}
@@ -256,6 +293,24 @@ func processStatements(conv *internal.Conv, rawStmts []*pg_query.RawStmt) *copyO
 	return nil
 }
 
+func processViewStmt(conv *internal.Conv, n *pg_query.ViewStmt) {
+	if n.View == nil || n.Query == nil {
+		logStmtError(conv, n, fmt.Errorf("cannot process view statement with nil view or query"))
+		return
+	}
+	viewName, err := getTableName(conv, n.View)
+	if err != nil {
+		logStmtError(conv, n, fmt.Errorf("can't get view name: %w", err))
+		return
+	}
+	query, err := pg_query.Deparse(&pg_query.ParseResult{Stmts: []*pg_query.RawStmt{{Stmt: n.Query}}})
+	if err != nil {
+		logStmtError(conv, n, fmt.Errorf("can't deparse query of view %s: %w", viewName, err))
+		return
+	}
+	common.AddSrcView(conv, schema.View{Name: viewName, Schema: n.View.Schemaname, Definition: query})
+}
+
 func processIndexStmt(conv *internal.Conv, n *pg_query.IndexStmt) {
 	if n.Relation == nil {
 		logStmtError(conv, n, fmt.Errorf("cannot process index statement with nil relation"))
@@ -268,12 +323,13 @@ func processIndexStmt(conv *internal.Conv, n *pg_query.IndexStmt) {
 	}
 	if tbl, ok := internal.GetSrcTableByName(conv.SrcSchema, tableName); ok {
 		ctable := conv.SrcSchema[tbl.Id]
-		ctable.Indexes = append(ctable.Indexes, schema.Index{
-			Id:     internal.GenerateIndexesId(),
-			Name:   n.Idxname,
-			Unique: n.Unique,
-			Keys:   toIndexKeys(conv, n.Idxname, n.IndexParams, ctable.ColNameIdMap),
-		})
+		if isFullTextIndex(n, ctable) {
+			ctable.Indexes = append(ctable.Indexes, toFullTextIndex(conv, n, ctable.ColNameIdMap))
+		} else if isVectorIndex(n) {
+			ctable.Indexes = append(ctable.Indexes, toVectorIndex(conv, n, ctable.ColNameIdMap))
+		} else {
+			ctable.Indexes = append(ctable.Indexes, toIndex(conv, n, ctable.ColNameIdMap))
+		}
 		conv.SrcSchema[tbl.Id] = ctable
 	} else {
 		conv.Unexpected(fmt.Sprintf("Table %s not found while processing index statement", tableName))
@@ -538,8 +594,6 @@ func processVariableSetStmt(conv *internal.Conv, n *pg_query.VariableSetStmt) {
 
 func processCreateSeqStmt(conv *internal.Conv, n *pg_query.CreateSeqStmt) {
 	name := getSeqName(n.GetSequence())
-	conv.ConvLock.Lock()
-	defer conv.ConvLock.Unlock()
 	seq := ddl.Sequence{
 		Id: internal.GenerateSequenceId(),
 		Name: name,
@@ -551,8 +605,6 @@ func processCreateSeqStmt(conv *internal.Conv, n *pg_query.CreateSeqStmt) {
 
 func processAlterSeqStmt(conv *internal.Conv, n *pg_query.AlterSeqStmt) {
 	name := getSeqName(n.Sequence)
-	conv.ConvLock.Lock()
-	defer conv.ConvLock.Unlock()
 	seq := conv.SrcSequences[name]
 	addOwnedBy(&seq, n.Options)
 	conv.SrcSequences[name] = seq
@@ -970,6 +1022,190 @@ func toIndexKeys(conv *internal.Conv, idxName string, s []*pg_query.Node, colNam
 	return
 }
 
+// toIndex converts a regular index to a schema index. The predicate of a
+// partial index is kept only if it just requires columns to be non-NULL,
+// which Spanner supports with null-filtered indexes; other partial indexes
+// are reported with a PartialIndex issue when converted.
+func toIndex(conv *internal.Conv, n *pg_query.IndexStmt, colNameIdMap map[string]string) schema.Index {
+	index := schema.Index{
+		Id:      internal.GenerateIndexesId(),
+		Name:    n.Idxname,
+		Unique:  n.Unique,
+		Keys:    toIndexKeys(conv, n.Idxname, n.IndexParams, colNameIdMap),
+		Partial: n.WhereClause != nil,
+	}
+	if n.WhereClause != nil {
+		cols, ok := getNotNullColumns(n.WhereClause)
+		for _, col := range cols {
+			colId, found := colNameIdMap[col]
+			if !found {
+				ok = false
+				break
+			}
+			index.NotNullColIds = append(index.NotNullColIds, colId)
+		}
+		if !ok {
+			index.NotNullColIds = nil
+		}
+	}
+	return index
+}
+
+// getNotNullColumns returns the columns that predicate node requires to be
+// non-NULL, and true if the predicate is a conjunction of such tests, e.g.
+// a IS NOT NULL AND b IS NOT NULL.
+func getNotNullColumns(node *pg_query.Node) ([]string, bool) {
+	switch e := node.GetNode().(type) {
+	case *pg_query.Node_NullTest:
+		if e.NullTest.Nulltesttype != pg_query.NullTestType_IS_NOT_NULL || e.NullTest.Arg.GetColumnRef() == nil {
+			return nil, false
+		}
+		return getColumnRefs(e.NullTest.Arg), true
+	case *pg_query.Node_BoolExpr:
+		if e.BoolExpr.Boolop != pg_query.BoolExprType_AND_EXPR {
+			return nil, false
+		}
+		var cols []string
+		for _, arg := range e.BoolExpr.Args {
+			c, ok := getNotNullColumns(arg)
+			if !ok {
+				return nil, false
+			}
+			cols = append(cols, c...)
+		}
+		return cols, true
+	}
+	return nil, false
+}
+
+// isFullTextIndex returns true if n is a GIN index on tsvector values of
+// table, i.e. on to_tsvector() expressions or on tsvector columns.
+func isFullTextIndex(n *pg_query.IndexStmt, table schema.Table) bool {
+	if n.AccessMethod != "gin" || len(n.IndexParams) == 0 {
+		return false
+	}
+	for _, k := range n.IndexParams {
+		e := k.GetIndexElem()
+		if e == nil {
+			return false
+		}
+		if e.Name != "" {
+			if table.ColDefs[table.ColNameIdMap[e.Name]].Type.Name != "tsvector" {
+				return false
+			}
+		} else if !callsFunc(e.Expr, "to_tsvector") {
+			return false
+		}
+	}
+	return true
+}
+
+// toFullTextIndex converts a GIN index on tsvector values to a full-text
+// schema index, whose keys are the columns that the index tokenizes.
+func toFullTextIndex(conv *internal.Conv, n *pg_query.IndexStmt, colNameIdMap map[string]string) schema.Index {
+	index := schema.Index{
+		Id:       internal.GenerateIndexesId(),
+		Name:     n.Idxname,
+		FullText: true,
+	}
+	seen := make(map[string]bool)
+	for _, k := range n.IndexParams {
+		e := k.GetIndexElem()
+		cols := []string{e.Name}
+		if e.Name == "" {
+			cols = getColumnRefs(e.Expr)
+		}
+		for _, col := range cols {
+			colId, ok := colNameIdMap[col]
+			if !ok {
+				conv.Unexpected(fmt.Sprintf("Failed to process index %s: unknown column %s", n.Idxname, col))
+				continue
+			}
+			if !seen[colId] {
+				seen[colId] = true
+				index.Keys = append(index.Keys, schema.Key{ColId: colId})
+			}
+		}
+	}
+	return index
+}
+
+// pgvectorDistanceTypes maps pgvector operator classes to the Spanner
+// distance types of vector indexes. Other operator classes, e.g. vector_l1_ops,
+// have no Spanner equivalent.
+var pgvectorDistanceTypes = map[string]string{
+	"vector_l2_ops":      ddl.DistanceEuclidean,
+	"vector_ip_ops":      ddl.DistanceDotProduct,
+	"vector_cosine_ops":  ddl.DistanceCosine,
+	"halfvec_l2_ops":     ddl.DistanceEuclidean,
+	"halfvec_ip_ops":     ddl.DistanceDotProduct,
+	"halfvec_cosine_ops": ddl.DistanceCosine,
+}
+
+// isVectorIndex returns true if n is a pgvector HNSW or IVFFlat index.
+func isVectorIndex(n *pg_query.IndexStmt) bool {
+	return n.AccessMethod == "hnsw" || n.AccessMethod == "ivfflat"
+}
+
+// toVectorIndex converts a pgvector index to a vector schema index. The
+// distance type is given by the operator class of the index, which defaults
+// to vector_l2_ops.
+func toVectorIndex(conv *internal.Conv, n *pg_query.IndexStmt, colNameIdMap map[string]string) schema.Index {
+	index := schema.Index{
+		Id:     internal.GenerateIndexesId(),
+		Name:   n.Idxname,
+		Vector: true,
+		Keys:   toIndexKeys(conv, n.Idxname, n.IndexParams, colNameIdMap),
+	}
+	opclass := "vector_l2_ops"
+	for _, k := range n.IndexParams {
+		if e := k.GetIndexElem(); e != nil && len(e.Opclass) > 0 {
+			opclass, _ = getString(e.Opclass[len(e.Opclass)-1])
+		}
+	}
+	index.DistanceType = pgvectorDistanceTypes[opclass]
+	return index
+}
+
+// callsFunc returns true if expression node calls function name.
+func callsFunc(node *pg_query.Node, name string) bool {
+	f := node.GetFuncCall()
+	if f == nil || len(f.Funcname) == 0 {
+		return false
+	}
+	funcName, _ := getString(f.Funcname[len(f.Funcname)-1])
+	return funcName == name
+}
+
+// getColumnRefs returns the names of the columns that expression node reads,
+// e.g. title and body for to_tsvector('english', title || ' ' || body).
+func getColumnRefs(node *pg_query.Node) []string {
+	var cols []string
+	switch e := node.GetNode().(type) {
+	case *pg_query.Node_ColumnRef:
+		fields := e.ColumnRef.Fields
+		if len(fields) > 0 {
+			if col, err := getString(fields[len(fields)-1]); err == nil {
+				cols = append(cols, col)
+			}
+		}
+	case *pg_query.Node_FuncCall:
+		for _, arg := range e.FuncCall.Args {
+			cols = append(cols, getColumnRefs(arg)...)
+		}
+	case *pg_query.Node_AExpr:
+		cols = append(cols, getColumnRefs(e.AExpr.Lexpr)...)
+		cols = append(cols, getColumnRefs(e.AExpr.Rexpr)...)
+	case *pg_query.Node_CoalesceExpr:
+		for _, arg := range e.CoalesceExpr.Args {
+			cols = append(cols, getColumnRefs(arg)...)
+		}
+	case *pg_query.Node_TypeCast:
+		cols = append(cols, getColumnRefs(e.TypeCast.Arg)...)
+	}
+	return cols
+}
+
 // toForeignKeys converts a string list of PostgreSQL foreign keys to schema
 // foreign keys.
 func toForeignKeys(fk constraint) (fkey schema.ForeignKey) {
@@ -1671,7 +1671,7 @@ func TestProcessPgDump_GetDDL(t *testing.T) {
 			"	quantity INT64,\n" +
 			") PRIMARY KEY (productid, userid)"
 	c := ddl.Config{Tables: true}
-	assert.Equal(t, expected, strings.Join(ddl.GetDDL(c, conv.SpSchema, conv.SpSequences, conv.DatabaseOptions), " "))
+	assert.Equal(t, expected, strings.Join(ddl.GetDDL(c, conv.SpSchema, conv.SpSequences, conv.SpViews, conv.DatabaseOptions), " "))
 }
 
 func TestProcessPgDump_GetPGDDL(t *testing.T) {
@@ -1685,7 +1685,160 @@ func TestProcessPgDump_GetPGDDL(t *testing.T) {
 			"	PRIMARY KEY (productid, userid)\n" +
 			")"
 	c := ddl.Config{Tables: true, SpDialect: conv.SpDialect}
-	assert.Equal(t, expected, strings.Join(ddl.GetDDL(c, conv.SpSchema, conv.SpSequences, conv.DatabaseOptions), " "))
+	assert.Equal(t, expected, strings.Join(ddl.GetDDL(c, conv.SpSchema, conv.SpSequences, conv.SpViews, conv.DatabaseOptions), " "))
+}
+
+func TestProcessPgDump_Views(t *testing.T) {
+	conv, _ := runProcessPgDump("CREATE TABLE cart (productid text, userid text, quantity bigint);\n" +
+		"CREATE VIEW big_carts AS SELECT productid, quantity FROM cart WHERE quantity > 10;")
+	assert.Len(t, conv.SrcViews, 1)
+	assert.Len(t, conv.SpViews, 1)
+	for _, v := range conv.SpViews {
+		assert.Equal(t, "big_carts", v.Name)
+		assert.Contains(t, v.Query, "FROM `cart`")
+	}
+}
+
+func TestProcessPgDump_FullText(t *testing.T) {
+	dump := "CREATE TABLE articles (id bigint PRIMARY KEY, title varchar(200), body text, tsv tsvector, tags jsonb);\n" +
+		"CREATE INDEX ft_title_body ON articles USING gin (to_tsvector('english', coalesce(title, '') || ' ' || body));\n" +
+		"CREATE INDEX ft_tsv ON articles USING gin (tsv);\n" +
+		"CREATE INDEX tags_idx ON articles USING gin (tags);"
+	conv, _ := runProcessPgDump(dump)
+	expected :=
+		"CREATE TABLE articles (\n" +
+			"	id INT64 NOT NULL ,\n" +
+			"	title STRING(200),\n" +
+			"	body STRING(MAX),\n" +
+			"	tsv STRING(MAX),\n" +
+			"	tags JSON,\n" +
+			"	title_Tokens TOKENLIST AS (TOKENIZE_FULLTEXT(`title`)) HIDDEN,\n" +
+			"	body_Tokens TOKENLIST AS (TOKENIZE_FULLTEXT(`body`)) HIDDEN,\n" +
+			"	tsv_Tokens TOKENLIST AS (TOKENIZE_FULLTEXT(`tsv`)) HIDDEN,\n" +
+			") PRIMARY KEY (id) " +
+			"CREATE INDEX tags_idx ON articles (tags) " +
+			"CREATE SEARCH INDEX ft_title_body ON articles (title_Tokens, body_Tokens) " +
+			"CREATE SEARCH INDEX ft_tsv ON articles (tsv_Tokens)"
+	c := ddl.Config{Tables: true}
+	assert.Equal(t, expected, strings.Join(ddl.GetDDL(c, conv.SpSchema, conv.SpSequences, conv.SpViews, conv.DatabaseOptions), " "))
+
+	conv, _ = runProcessPgDumpPGTarget(dump)
+	expected =
+		"CREATE TABLE articles (\n" +
+			"	id INT8 NOT NULL ,\n" +
+			"	title VARCHAR(200),\n" +
+			"	body VARCHAR(2621440),\n" +
+			"	tsv VARCHAR(2621440),\n" +
+			"	tags JSONB,\n" +
+			"	title_tokens SPANNER.TOKENLIST GENERATED ALWAYS AS (spanner.tokenize_fulltext(\"title\")) VIRTUAL HIDDEN,\n" +
+			"	body_tokens SPANNER.TOKENLIST GENERATED ALWAYS AS (spanner.tokenize_fulltext(\"body\")) VIRTUAL HIDDEN,\n" +
+			"	tsv_tokens SPANNER.TOKENLIST GENERATED ALWAYS AS (spanner.tokenize_fulltext(\"tsv\")) VIRTUAL HIDDEN,\n" +
+			"	PRIMARY KEY (id)\n" +
+			") " +
+			"CREATE INDEX tags_idx ON articles (tags) " +
+			"CREATE SEARCH INDEX ft_title_body ON articles (title_tokens, body_tokens) " +
+			"CREATE SEARCH INDEX ft_tsv ON articles (tsv_tokens)"
+	c = ddl.Config{Tables: true, SpDialect: conv.SpDialect}
+	assert.Equal(t, expected, strings.Join(ddl.GetDDL(c, conv.SpSchema, conv.SpSequences, conv.SpViews, conv.DatabaseOptions), " "))
+	tableId, _ := internal.GetTableIdFromSpName(conv.SpSchema, "articles")
+	titleId := conv.SrcSchema[tableId].ColNameIdMap["title"]
+	assert.Contains(t, conv.SchemaIssues[tableId].ColumnLevelIssues[titleId], internal.FullTextSearchIndex)
+}
+
+func TestProcessPgDump_Vector(t *testing.T) {
+	dump := "CREATE TABLE items (id bigint PRIMARY KEY, embedding vector(3) NOT NULL, summary halfvec(2), raw vector);\n" +
+		"CREATE INDEX items_embedding_idx ON items USING hnsw (embedding vector_cosine_ops) WITH (m = 16);\n" +
+		"CREATE INDEX items_summary_idx ON items USING ivfflat (summary) WITH (lists = 100);\n" +
+		"CREATE INDEX items_raw_idx ON items USING hnsw (raw vector_l1_ops);\n" +
+		"COPY public.items (id, embedding, summary, raw) FROM stdin;\n" +
+		"1\t[1,2.5,-3]\t[0.5,1]\t\\N\n" +
+		"\\.\n"
+	conv, rows := runProcessPgDump(dump)
+	expected :=
+		"CREATE TABLE items (\n" +
+			"	id INT64 NOT NULL ,\n" +
+			"	embedding ARRAY<FLOAT32>(vector_length=>3) NOT NULL ,\n" +
+			"	summary ARRAY<FLOAT32>(vector_length=>2),\n" +
+			"	raw ARRAY<FLOAT32>,\n" +
+			") PRIMARY KEY (id) " +
+			"CREATE VECTOR INDEX items_embedding_idx ON items (embedding) OPTIONS (distance_type = 'COSINE') " +
+			"CREATE VECTOR INDEX items_summary_idx ON items (summary) WHERE summary IS NOT NULL OPTIONS (distance_type = 'EUCLIDEAN')"
+	c := ddl.Config{Tables: true}
+	assert.Equal(t, expected, strings.Join(ddl.GetDDL(c, conv.SpSchema, conv.SpSequences, conv.SpViews, conv.DatabaseOptions), " "))
+	assert.Equal(t, []spannerData{
+		{
+			table: "items",
+			cols:  []string{"id", "embedding", "summary"},
+			vals: []interface{}{int64(1),
+				[]spanner.NullFloat32{{Float32: 1, Valid: true}, {Float32: 2.5, Valid: true}, {Float32: -3, Valid: true}},
+				[]spanner.NullFloat32{{Float32: 0.5, Valid: true}, {Float32: 1, Valid: true}}},
+		},
+	}, rows)
+	tableId, _ := internal.GetTableIdFromSpName(conv.SpSchema, "items")
+	embeddingId := conv.SrcSchema[tableId].ColNameIdMap["embedding"]
+	rawId := conv.SrcSchema[tableId].ColNameIdMap["raw"]
+	assert.Contains(t, conv.SchemaIssues[tableId].ColumnLevelIssues[embeddingId], internal.VectorIndex)
+	assert.Contains(t, conv.SchemaIssues[tableId].ColumnLevelIssues[rawId], internal.VectorIndexNotSupported)
+
+	conv, _ = runProcessPgDumpPGTarget(dump)
+	expected =
+		"CREATE TABLE items (\n" +
+			"	id INT8 NOT NULL ,\n" +
+			"	embedding FLOAT4[] VECTOR LENGTH 3 NOT NULL ,\n" +
+			"	summary FLOAT4[] VECTOR LENGTH 2,\n" +
+			"	raw VARCHAR(2621440),\n" +
+			"	PRIMARY KEY (id)\n" +
+			") " +
+			"CREATE INDEX items_embedding_idx ON items USING ScaNN (embedding) WITH (distance_type = 'COSINE') " +
+			"CREATE INDEX items_summary_idx ON items USING ScaNN (summary) WITH (distance_type = 'EUCLIDEAN') WHERE summary IS NOT NULL"
+	c = ddl.Config{Tables: true, SpDialect: conv.SpDialect}
+	assert.Equal(t, expected, strings.Join(ddl.GetDDL(c, conv.SpSchema, conv.SpSequences, conv.SpViews, conv.DatabaseOptions), " "))
+}
+
+func TestProcessPgDump_PartialIndex(t *testing.T) {
+	dump := "CREATE TABLE orders (id bigint PRIMARY KEY, customer bigint, coupon text, placed date NOT NULL);\n" +
+		"CREATE INDEX orders_customer_idx ON orders (customer) WHERE customer IS NOT NULL;\n" +
+		"CREATE UNIQUE INDEX orders_coupon_idx ON orders (coupon, placed) WHERE (coupon IS NOT NULL AND placed IS NOT NULL);\n" +
+		"CREATE INDEX orders_placed_idx ON orders (customer, placed) WHERE customer IS NOT NULL AND coupon IS NOT NULL;\n" +
+		"CREATE INDEX orders_recent_idx ON orders (placed) WHERE placed > '2024-01-01';\n" +
+		"CREATE UNIQUE INDEX orders_open_idx ON orders (customer) WHERE coupon IS NULL;\n"
+	conv, _ := runProcessPgDump(dump)
+	expected :=
+		"CREATE TABLE orders (\n" +
+			"	id INT64 NOT NULL ,\n" +
+			"	customer INT64,\n" +
+			"	coupon STRING(MAX),\n" +
+			"	placed DATE NOT NULL ,\n" +
+			") PRIMARY KEY (id) " +
+			"CREATE NULL_FILTERED INDEX orders_customer_idx ON orders (customer) " +
+			"CREATE UNIQUE NULL_FILTERED INDEX orders_coupon_idx ON orders (coupon, placed) " +
+			"CREATE INDEX orders_placed_idx ON orders (customer, placed) " +
+			"CREATE INDEX orders_recent_idx ON orders (placed) " +
+			"CREATE INDEX orders_open_idx ON orders (customer)"
+	c := ddl.Config{Tables: true}
+	assert.Equal(t, expected, strings.Join(ddl.GetDDL(c, conv.SpSchema, conv.SpSequences, conv.SpViews, conv.DatabaseOptions), " "))
+	tableId, _ := internal.GetTableIdFromSpName(conv.SpSchema, "orders")
+	issues := conv.SchemaIssues[tableId].ColumnLevelIssues
+	assert.NotContains(t, issues[conv.SrcSchema[tableId].ColNameIdMap["coupon"]], internal.PartialIndex)
+	assert.Contains(t, issues[conv.SrcSchema[tableId].ColNameIdMap["placed"]], internal.PartialIndex)
+	assert.Contains(t, issues[conv.SrcSchema[tableId].ColNameIdMap["customer"]], internal.PartialIndex)
+
+	conv, _ = runProcessPgDumpPGTarget(dump)
+	expected =
+		"CREATE TABLE orders (\n" +
+			"	id INT8 NOT NULL ,\n" +
+			"	customer INT8,\n" +
+			"	coupon VARCHAR(2621440),\n" +
+			"	placed DATE NOT NULL ,\n" +
+			"	PRIMARY KEY (id)\n" +
+			") " +
+			"CREATE INDEX orders_customer_idx ON orders (customer) WHERE customer IS NOT NULL " +
+			"CREATE UNIQUE INDEX orders_coupon_idx ON orders (coupon, placed) WHERE coupon IS NOT NULL AND placed IS NOT NULL " +
+			"CREATE INDEX orders_placed_idx ON orders (customer, placed) " +
+			"CREATE INDEX orders_recent_idx ON orders (placed) " +
+			"CREATE INDEX orders_open_idx ON orders (customer)"
+	c = ddl.Config{Tables: true, SpDialect: conv.SpDialect}
+	assert.Equal(t, expected, strings.Join(ddl.GetDDL(c, conv.SpSchema, conv.SpSequences, conv.SpViews, conv.DatabaseOptions), " "))
 }
 
 func TestProcessPgDump_Rows(t *testing.T) {
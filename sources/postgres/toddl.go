@@ -187,6 +187,17 @@ func toSpannerTypeInternal(srcType schema.Type, spType string) (ddl.Type, []inte
 		default:
 			return ddl.Type{Name: ddl.JSON}, nil
 		}
+	case "vector", "halfvec":
+		// pgvector types, e.g. vector(3) for vectors of 3 dimensions.
+		switch spType {
+		case ddl.String:
+			return ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, nil
+		default:
+			if len(srcType.Mods) > 0 {
+				return ddl.Type{Name: ddl.Float32, IsArray: true, VectorLength: srcType.Mods[0]}, nil
+			}
+			return ddl.Type{Name: ddl.Float32, IsArray: true}, nil
+		}
 	case "varchar", "character varying":
 		switch spType {
 		case ddl.Bytes:
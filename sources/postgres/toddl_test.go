@@ -123,6 +123,12 @@ func TestToSpannerTypeInternal(t *testing.T) {
 	if errCheck != nil {
 		t.Errorf("Error in varchar to bytes conversion")
 	}
+	ty, errCheck := toSpannerTypeInternal(schema.Type{Name: "vector", Mods: []int64{3}}, "")
+	assert.Nil(t, errCheck)
+	assert.Equal(t, ddl.Type{Name: ddl.Float32, IsArray: true, VectorLength: 3}, ty)
+	ty, errCheck = toSpannerTypeInternal(schema.Type{Name: "vector", Mods: []int64{3}}, "STRING")
+	assert.Nil(t, errCheck)
+	assert.Equal(t, ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, ty)
 }
 
 // This is just a very basic smoke-test for toSpannerType.
@@ -0,0 +1,240 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	pg_query "github.com/pganalyze/pg_query_go/v6"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+)
+
+// viewFunctions maps the names of the PostgreSQL functions that can't be
+// used as they are in Spanner.
+var viewFunctions = map[string]common.ViewFunction{
+	"date_trunc": {PostgreSql: "date_trunc"},
+	"now":        {GoogleSql: "CURRENT_TIMESTAMP", PostgreSql: "now"},
+	"to_char":    {PostgreSql: "to_char"},
+}
+
+// doubleQuotedRegexp matches the quoted identifiers of deparsed queries.
+var doubleQuotedRegexp = regexp.MustCompile(`"(?:[^"]|"")*"`)
+
+// TranslateView implements common.ViewTranslator. The query is parsed and
+// its tables, views and columns are renamed in the parse tree, which is
+// then deparsed.
+func (tdi ToDdlImpl) TranslateView(q *common.ViewQuery, query string) string {
+	tree, err := pg_query.Parse(query)
+	if err != nil || len(tree.Stmts) != 1 || tree.Stmts[0].Stmt.GetSelectStmt() == nil {
+		logger.Log.Debug(fmt.Sprintf("Can't parse view query %q: %v", query, err))
+		q.AddIssue(internal.ViewUnsupportedSql)
+		return ""
+	}
+	vv := &viewVisitor{
+		q:       q,
+		rels:    map[*pg_query.RangeVar]common.ViewRelation{},
+		aliases: map[string]common.ViewRelation{},
+		names:   map[string]common.ViewRelation{},
+		ctes:    map[string]bool{},
+	}
+	// The tables of the query are found first, as columns can be qualified
+	// by their aliases.
+	walkView(tree.ProtoReflect(), vv.findTables)
+	for _, name := range vv.missing {
+		if !vv.ctes[name] {
+			q.AddIssue(internal.ViewMissingTable)
+		}
+	}
+	walkView(tree.ProtoReflect(), vv.rename)
+	spQuery, err := pg_query.Deparse(tree)
+	if err != nil {
+		logger.Log.Debug(fmt.Sprintf("Can't deparse view query %q: %v", query, err))
+		q.AddIssue(internal.ViewUnsupportedSql)
+		return ""
+	}
+	if !q.PostgreSQL() {
+		// String literals are placeholders at this point, so only
+		// identifiers are quoted.
+		spQuery = doubleQuotedRegexp.ReplaceAllStringFunc(spQuery, func(s string) string {
+			return q.Quote(strings.ReplaceAll(s[1:len(s)-1], `""`, `"`))
+		})
+	}
+	return spQuery
+}
+
+// walkView calls f with each message of the parse tree m, before the
+// messages it contains.
+func walkView(m protoreflect.Message, f func(proto.Message)) {
+	f(m.Interface())
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.Message() == nil || fd.IsMap():
+		case fd.IsList():
+			for i := 0; i < v.List().Len(); i++ {
+				walkView(v.List().Get(i).Message(), f)
+			}
+		default:
+			walkView(v.Message(), f)
+		}
+		return true
+	})
+}
+
+// viewVisitor maps the tables, views, columns and functions of the query of
+// a view to Spanner.
+type viewVisitor struct {
+	q        *common.ViewQuery
+	rels     map[*pg_query.RangeVar]common.ViewRelation
+	aliases  map[string]common.ViewRelation // Keyed by lower-cased alias.
+	names    map[string]common.ViewRelation // Tables without alias, keyed by lower-cased name.
+	ctes     map[string]bool                // Lower-cased names of the common table expressions.
+	missing  []string                       // Lower-cased names of the tables not found, unless common table expressions.
+	tableIds []string                       // The tables the query reads from.
+}
+
+func (vv *viewVisitor) findTables(m proto.Message) {
+	switch n := m.(type) {
+	case *pg_query.CommonTableExpr:
+		vv.ctes[strings.ToLower(n.Ctename)] = true
+	case *pg_query.RangeVar:
+		rel, ok := vv.q.Relation(n.Catalogname, n.Schemaname, n.Relname)
+		switch {
+		case !ok && n.Schemaname == "":
+			vv.missing = append(vv.missing, strings.ToLower(n.Relname))
+			return
+		case !ok:
+			vv.q.AddIssue(internal.ViewMissingTable)
+			return
+		}
+		vv.rels[n] = rel
+		if rel.TableId != "" {
+			vv.tableIds = append(vv.tableIds, rel.TableId)
+		}
+		if n.Alias != nil {
+			vv.aliases[strings.ToLower(n.Alias.Aliasname)] = rel
+		} else {
+			vv.names[strings.ToLower(n.Relname)] = rel
+		}
+	}
+}
+
+func (vv *viewVisitor) rename(m proto.Message) {
+	pg := vv.q.PostgreSQL()
+	switch n := m.(type) {
+	case *pg_query.Node:
+		// GoogleSQL has no NOW function.
+		if fc := n.GetFuncCall(); fc != nil && !pg && len(fc.Funcname) == 1 && strings.EqualFold(fc.Funcname[0].GetString_().GetSval(), "now") {
+			n.Node = &pg_query.Node_SqlvalueFunction{SqlvalueFunction: &pg_query.SQLValueFunction{Op: pg_query.SQLValueFunctionOp_SVFOP_CURRENT_TIMESTAMP, Typmod: -1}}
+		}
+	case *pg_query.RangeVar:
+		if rel, ok := vv.rels[n]; ok {
+			n.Catalogname, n.Schemaname, n.Relname = "", "", vv.q.Name(rel.SpName)
+		}
+	case *pg_query.ColumnRef:
+		vv.renameColumnRef(n)
+	case *pg_query.JoinExpr:
+		for _, u := range n.UsingClause {
+			if s := u.GetString_(); s != nil {
+				s.Sval = vv.column(s.Sval)
+			}
+		}
+	case *pg_query.FuncCall:
+		var names []string
+		for _, f := range n.Funcname {
+			names = append(names, f.GetString_().GetSval())
+		}
+		if len(names) > 1 && !(len(names) == 2 && names[0] == "pg_catalog") {
+			// User-defined functions aren't migrated.
+			vv.q.AddIssue(internal.ViewUnsupportedSql)
+			return
+		}
+		if f, ok := viewFunctions[strings.ToLower(names[len(names)-1])]; ok {
+			if name := vv.q.Function(f); name != "" {
+				n.Funcname = []*pg_query.Node{pg_query.MakeStrNode(name)}
+			}
+		}
+	case *pg_query.TypeCast:
+		if !pg {
+			vv.q.AddIssue(internal.ViewUnsupportedSql)
+		}
+	case *pg_query.A_Expr:
+		if n.Kind == pg_query.A_Expr_Kind_AEXPR_ILIKE && !pg {
+			vv.q.AddIssue(internal.ViewUnsupportedSql)
+		}
+	case *pg_query.SelectStmt:
+		// DISTINCT ON.
+		if len(n.DistinctClause) > 0 && n.DistinctClause[0].GetNode() != nil {
+			vv.q.AddIssue(internal.ViewUnsupportedSql)
+		}
+	case *pg_query.A_Const:
+		if s := n.GetSval(); s != nil {
+			s.Sval = vv.q.String(s.Sval)
+		}
+	}
+}
+
+// renameColumnRef maps a column, or the "t.*" of a table, to Spanner.
+func (vv *viewVisitor) renameColumnRef(n *pg_query.ColumnRef) {
+	last := n.Fields[len(n.Fields)-1]
+	var qualifier []string
+	for _, f := range n.Fields[:len(n.Fields)-1] {
+		qualifier = append(qualifier, f.GetString_().GetSval())
+	}
+	if len(qualifier) == 0 {
+		if s := last.GetString_(); s != nil {
+			s.Sval = vv.column(s.Sval)
+		}
+		return
+	}
+	rel, ok := common.ViewRelation{}, false
+	if len(qualifier) == 1 {
+		rel, ok = vv.aliases[strings.ToLower(qualifier[0])]
+	}
+	if !ok {
+		// The qualifier is the name of a table, which is renamed.
+		if rel, ok = vv.names[strings.ToLower(qualifier[0])]; len(qualifier) > 1 || !ok {
+			if rel, ok = vv.q.Relation(qualifier...); !ok {
+				return
+			}
+		}
+		n.Fields = []*pg_query.Node{pg_query.MakeStrNode(vv.q.Name(rel.SpName)), last}
+	}
+	if s := last.GetString_(); s != nil && rel.TableId != "" {
+		spCol, ok := vv.q.Column(rel.TableId, s.Sval)
+		if !ok {
+			vv.q.AddIssue(internal.ViewMissingTable)
+			return
+		}
+		s.Sval = vv.q.Name(spCol)
+	}
+}
+
+// column returns the Spanner name of a column of the tables of the query,
+// or name if it isn't one.
+func (vv *viewVisitor) column(name string) string {
+	for _, tableId := range vv.tableIds {
+		if spCol, ok := vv.q.Column(tableId, name); ok {
+			return vv.q.Name(spCol)
+		}
+	}
+	return name
+}
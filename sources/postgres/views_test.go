@@ -0,0 +1,120 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// getViewsConv returns a conv with a table music.singers, whose column
+// first_name is named FirstName in Spanner, and a view of it.
+func getViewsConv(dialect, definition string) *internal.Conv {
+	conv := internal.MakeConv()
+	conv.Source = constants.POSTGRES
+	conv.SpDialect = dialect
+	conv.SrcSchema["t1"] = schema.Table{
+		Name:   "music.singers",
+		Schema: "music",
+		Id:     "t1",
+		ColIds: []string{"c1", "c2"},
+		ColDefs: map[string]schema.Column{
+			"c1": {Name: "id", Id: "c1"},
+			"c2": {Name: "first_name", Id: "c2"},
+		},
+	}
+	conv.SpSchema["t1"] = ddl.CreateTable{
+		Name:   "Singers",
+		Id:     "t1",
+		ColIds: []string{"c1", "c2"},
+		ColDefs: map[string]ddl.ColumnDef{
+			"c1": {Name: "id", Id: "c1"},
+			"c2": {Name: "FirstName", Id: "c2"},
+		},
+	}
+	conv.UsedNames["singers"] = true
+	common.AddSrcView(conv, schema.View{Name: "music.singer_names", Schema: "music", Definition: definition})
+	return conv
+}
+
+func TestTranslateView(t *testing.T) {
+	testCases := []struct {
+		name       string
+		dialect    string
+		definition string
+		want       string
+	}{
+		{
+			name:       "information schema",
+			dialect:    constants.DIALECT_POSTGRESQL,
+			definition: " SELECT s.id,\n    upper(s.first_name) AS upper\n   FROM music.singers s\n  WHERE s.id > 0 AND s.first_name <> 'it''s';",
+			want:       `SELECT s."id", upper(s."FirstName") AS upper FROM "Singers" s WHERE s."id" > 0 AND s."FirstName" <> 'it''s'`,
+		},
+		{
+			name:       "googlesql",
+			dialect:    constants.DIALECT_GOOGLESQL,
+			definition: `SELECT singers.id, coalesce(first_name, E'it\'s') AS "Name", now() AS ts FROM music.singers JOIN music.singers t USING (id)`,
+			want:       "SELECT `Singers`.`id`, COALESCE(`FirstName`, 'it\\'s') AS `Name`, current_timestamp AS ts FROM `Singers` JOIN `Singers` t USING (`id`)",
+		},
+		{
+			name:       "common table expression",
+			dialect:    constants.DIALECT_POSTGRESQL,
+			definition: `WITH s AS (SELECT * FROM music.singers) SELECT s.* FROM s`,
+			want:       `WITH s AS (SELECT * FROM "Singers") SELECT s.* FROM s`,
+		},
+	}
+	for _, tc := range testCases {
+		conv := getViewsConv(tc.dialect, tc.definition)
+		common.ViewsToSpanner(conv, ToDdlImpl{})
+		assert.Empty(t, conv.SchemaIssues, tc.name)
+		assert.Len(t, conv.SpViews, 1, tc.name)
+		for _, v := range conv.SpViews {
+			assert.Equal(t, tc.want, v.Query, tc.name)
+		}
+	}
+}
+
+func TestTranslateView_Issues(t *testing.T) {
+	testCases := []struct {
+		name       string
+		dialect    string
+		definition string
+		want       internal.SchemaIssue
+	}{
+		{"cast", constants.DIALECT_GOOGLESQL, "SELECT id::text AS id FROM music.singers", internal.ViewUnsupportedSql},
+		{"ilike", constants.DIALECT_GOOGLESQL, "SELECT id FROM music.singers WHERE first_name ILIKE 'a%'", internal.ViewUnsupportedSql},
+		{"distinct on", constants.DIALECT_POSTGRESQL, "SELECT DISTINCT ON (first_name) id FROM music.singers", internal.ViewUnsupportedSql},
+		{"function", constants.DIALECT_GOOGLESQL, "SELECT to_char(id, '999') FROM music.singers", internal.ViewUnsupportedSql},
+		{"user-defined function", constants.DIALECT_POSTGRESQL, "SELECT music.f(id) FROM music.singers", internal.ViewUnsupportedSql},
+		{"syntax error", constants.DIALECT_POSTGRESQL, "SELECT 'a FROM music.singers", internal.ViewUnsupportedSql},
+		{"missing table", constants.DIALECT_POSTGRESQL, "SELECT id FROM music.albums", internal.ViewMissingTable},
+		{"missing column", constants.DIALECT_POSTGRESQL, "SELECT s.last_name FROM music.singers s", internal.ViewMissingTable},
+	}
+	for _, tc := range testCases {
+		conv := getViewsConv(tc.dialect, tc.definition)
+		common.ViewsToSpanner(conv, ToDdlImpl{})
+		assert.Empty(t, conv.SpViews, tc.name)
+		for id := range conv.SrcViews {
+			assert.Equal(t, []internal.SchemaIssue{tc.want}, conv.SchemaIssues[id].TableLevelIssues, tc.name)
+		}
+	}
+}
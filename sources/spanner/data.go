@@ -0,0 +1,395 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanner
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+	sppb "cloud.google.com/go/spanner/apiv1/spannerpb"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// ProcessDataRow converts a row of data and writes it out to Spanner.
+// srcSchema and spSchema are the source and Spanner tables, and vals
+// contains the decoded values (see decodeValue) of the columns in colIds.
+// ProcessDataRow is only called in DataMode.
+func ProcessDataRow(conv *internal.Conv, tableId string, colIds []string, srcSchema schema.Table, spSchema ddl.CreateTable, vals []interface{}) {
+	spTableName, cvtCols, cvtVals, err := ConvertData(conv, tableId, colIds, srcSchema, spSchema, vals)
+	srcTableName := srcSchema.Name
+	if err != nil {
+		srcCols := []string{}
+		for _, colId := range colIds {
+			srcCols = append(srcCols, srcSchema.ColDefs[colId].Name)
+		}
+		conv.Unexpected(fmt.Sprintf("Error while converting data: %s\n", err))
+		conv.StatsAddBadRow(srcTableName, conv.DataMode())
+		conv.CollectBadRow(srcTableName, srcCols, valsToStrings(vals), err)
+	} else {
+		conv.WriteRow(srcTableName, spTableName, cvtCols, cvtVals)
+	}
+}
+
+// ConvertData maps the source Spanner values in vals into values for the
+// target Spanner table. Values of the same type are passed through (with
+// NUMERIC adapted to the target dialect), and any type can be written to
+// a STRING column, which is how arrays reach a PostgreSQL-dialect target.
+// Null values are dropped, so we also return the list of columns written.
+func ConvertData(conv *internal.Conv, tableId string, colIds []string, srcSchema schema.Table, spSchema ddl.CreateTable, vals []interface{}) (string, []string, []interface{}, error) {
+	var c []string
+	var v []interface{}
+	if len(colIds) != len(vals) {
+		return "", []string{}, []interface{}{}, fmt.Errorf("ConvertData: colIds and vals don't all have the same lengths: len(colIds)=%d, len(vals)=%d", len(colIds), len(vals))
+	}
+	for i, colId := range colIds {
+		if vals[i] == nil {
+			continue
+		}
+		spColDef, ok1 := spSchema.ColDefs[colId]
+		srcColDef, ok2 := srcSchema.ColDefs[colId]
+		if !ok1 || !ok2 {
+			return "", []string{}, []interface{}{}, fmt.Errorf("can't find Spanner and source-db schema for colId %s", colId)
+		}
+		var x interface{}
+		var err error
+		if spColDef.T.IsArray {
+			x, err = convArray(conv, spColDef.T, vals[i])
+		} else {
+			x, err = convScalar(conv, spColDef.T, vals[i])
+		}
+		if err != nil {
+			return "", []string{}, []interface{}{}, fmt.Errorf("column %s: %w", srcColDef.Name, err)
+		}
+		v = append(v, x)
+		c = append(c, spColDef.Name)
+	}
+	if aux, ok := conv.NextSyntheticPKey(tableId); ok {
+		c = append(c, conv.SpSchema[tableId].ColDefs[aux.ColId].Name)
+		v = append(v, fmt.Sprintf("%d", int64(bits.Reverse64(uint64(aux.Sequence)))))
+	}
+	return spSchema.Name, c, v, nil
+}
+
+// decodeValue decodes a column read from the source database into a
+// plain Go value: bool, int64, float32, float64, string, []byte,
+// civil.Date, time.Time, *big.Rat for GoogleSQL NUMERIC, string for
+// PostgreSQL numeric and JSON of either dialect, and []interface{} for
+// arrays. Nulls, including null array elements, decode to nil.
+func decodeValue(gcv spanner.GenericColumnValue) (interface{}, error) {
+	if _, ok := gcv.Value.GetKind().(*structpb.Value_NullValue); ok {
+		return nil, nil
+	}
+	switch gcv.Type.GetCode() {
+	case sppb.TypeCode_ARRAY:
+		lv := gcv.Value.GetListValue()
+		if lv == nil {
+			return nil, fmt.Errorf("expected list value for %s", gcv.Type)
+		}
+		elems := make([]interface{}, len(lv.Values))
+		for i, e := range lv.Values {
+			x, err := decodeValue(spanner.GenericColumnValue{Type: gcv.Type.ArrayElementType, Value: e})
+			if err != nil {
+				return nil, err
+			}
+			elems[i] = x
+		}
+		return elems, nil
+	case sppb.TypeCode_BOOL:
+		var b bool
+		err := gcv.Decode(&b)
+		return b, err
+	case sppb.TypeCode_INT64:
+		var i int64
+		err := gcv.Decode(&i)
+		return i, err
+	case sppb.TypeCode_FLOAT32:
+		var f float32
+		err := gcv.Decode(&f)
+		return f, err
+	case sppb.TypeCode_FLOAT64:
+		var f float64
+		err := gcv.Decode(&f)
+		return f, err
+	case sppb.TypeCode_STRING:
+		var s string
+		err := gcv.Decode(&s)
+		return s, err
+	case sppb.TypeCode_BYTES:
+		var b []byte
+		err := gcv.Decode(&b)
+		return b, err
+	case sppb.TypeCode_DATE:
+		var d civil.Date
+		err := gcv.Decode(&d)
+		return d, err
+	case sppb.TypeCode_TIMESTAMP:
+		var t time.Time
+		err := gcv.Decode(&t)
+		return t, err
+	case sppb.TypeCode_NUMERIC:
+		if gcv.Type.GetTypeAnnotation() == sppb.TypeAnnotationCode_PG_NUMERIC {
+			// PostgreSQL numerics can be NaN, which big.Rat can't represent.
+			return gcv.Value.GetStringValue(), nil
+		}
+		var r big.Rat
+		if err := gcv.Decode(&r); err != nil {
+			return nil, err
+		}
+		return &r, nil
+	case sppb.TypeCode_JSON:
+		// Both JSON and PostgreSQL jsonb are sent as their text encoding.
+		return gcv.Value.GetStringValue(), nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", gcv.Type)
+}
+
+// convScalar converts a decoded value to the Go value expected by the
+// Spanner client for spannerType.
+func convScalar(conv *internal.Conv, spannerType ddl.Type, val interface{}) (interface{}, error) {
+	switch spannerType.Name {
+	case ddl.String:
+		return toString(val), nil
+	case ddl.Numeric:
+		return convNumeric(conv, val)
+	case ddl.Float64:
+		// Widening FLOAT32 to FLOAT64 is lossless.
+		if f, ok := val.(float32); ok {
+			return float64(f), nil
+		}
+	}
+	if !hasGoType(spannerType.Name, val) {
+		return nil, fmt.Errorf("can't convert value of type %T to %s", val, spannerType.Name)
+	}
+	return val, nil
+}
+
+// hasGoType reports whether val is of the Go type decodeValue returns for
+// values of Spanner type typeName.
+func hasGoType(typeName string, val interface{}) bool {
+	var ok bool
+	switch typeName {
+	case ddl.Bool:
+		_, ok = val.(bool)
+	case ddl.Int64:
+		_, ok = val.(int64)
+	case ddl.Float32:
+		_, ok = val.(float32)
+	case ddl.Float64:
+		_, ok = val.(float64)
+	case ddl.Bytes:
+		_, ok = val.([]byte)
+	case ddl.Date:
+		_, ok = val.(civil.Date)
+	case ddl.Timestamp:
+		_, ok = val.(time.Time)
+	case ddl.JSON:
+		_, ok = val.(string)
+	}
+	return ok
+}
+
+// convNumeric converts a NUMERIC value to the representation used by
+// the target dialect: *big.Rat for GoogleSQL and spanner.PGNumeric for
+// PostgreSQL.
+func convNumeric(conv *internal.Conv, val interface{}) (interface{}, error) {
+	var s string
+	switch v := val.(type) {
+	case *big.Rat:
+		if conv.SpDialect != constants.DIALECT_POSTGRESQL {
+			return v, nil
+		}
+		s = spanner.NumericString(v)
+	case string:
+		s = v
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	default:
+		return nil, fmt.Errorf("can't convert value of type %T to %s", val, ddl.Numeric)
+	}
+	if conv.SpDialect == constants.DIALECT_POSTGRESQL {
+		return spanner.PGNumeric{Numeric: s, Valid: true}, nil
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("can't convert %q to %s", s, ddl.Numeric)
+	}
+	return r, nil
+}
+
+// convArray converts a decoded array to the typed slice expected by the
+// Spanner client for an array of spannerType.Name. Null elements are
+// preserved.
+func convArray(conv *internal.Conv, spannerType ddl.Type, val interface{}) (interface{}, error) {
+	elems, ok := val.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("can't convert value of type %T to ARRAY<%s>", val, spannerType.Name)
+	}
+	elemType := ddl.Type{Name: spannerType.Name, Len: spannerType.Len}
+	cvt := make([]interface{}, len(elems))
+	for i, e := range elems {
+		if e == nil {
+			continue
+		}
+		x, err := convScalar(conv, elemType, e)
+		if err != nil {
+			return nil, err
+		}
+		cvt[i] = x
+	}
+	switch spannerType.Name {
+	case ddl.Bool:
+		r := make([]spanner.NullBool, len(cvt))
+		for i, x := range cvt {
+			if x != nil {
+				r[i] = spanner.NullBool{Bool: x.(bool), Valid: true}
+			}
+		}
+		return r, nil
+	case ddl.Int64:
+		r := make([]spanner.NullInt64, len(cvt))
+		for i, x := range cvt {
+			if x != nil {
+				r[i] = spanner.NullInt64{Int64: x.(int64), Valid: true}
+			}
+		}
+		return r, nil
+	case ddl.Float32:
+		r := make([]spanner.NullFloat32, len(cvt))
+		for i, x := range cvt {
+			if x != nil {
+				r[i] = spanner.NullFloat32{Float32: x.(float32), Valid: true}
+			}
+		}
+		return r, nil
+	case ddl.Float64:
+		r := make([]spanner.NullFloat64, len(cvt))
+		for i, x := range cvt {
+			if x != nil {
+				r[i] = spanner.NullFloat64{Float64: x.(float64), Valid: true}
+			}
+		}
+		return r, nil
+	case ddl.String, ddl.JSON:
+		r := make([]spanner.NullString, len(cvt))
+		for i, x := range cvt {
+			if x != nil {
+				r[i] = spanner.NullString{StringVal: x.(string), Valid: true}
+			}
+		}
+		return r, nil
+	case ddl.Bytes:
+		r := make([][]byte, len(cvt))
+		for i, x := range cvt {
+			if x != nil {
+				r[i] = x.([]byte)
+			}
+		}
+		return r, nil
+	case ddl.Date:
+		r := make([]spanner.NullDate, len(cvt))
+		for i, x := range cvt {
+			if x != nil {
+				r[i] = spanner.NullDate{Date: x.(civil.Date), Valid: true}
+			}
+		}
+		return r, nil
+	case ddl.Timestamp:
+		r := make([]spanner.NullTime, len(cvt))
+		for i, x := range cvt {
+			if x != nil {
+				r[i] = spanner.NullTime{Time: x.(time.Time), Valid: true}
+			}
+		}
+		return r, nil
+	case ddl.Numeric:
+		if conv.SpDialect == constants.DIALECT_POSTGRESQL {
+			r := make([]spanner.PGNumeric, len(cvt))
+			for i, x := range cvt {
+				if x != nil {
+					r[i] = x.(spanner.PGNumeric)
+				}
+			}
+			return r, nil
+		}
+		r := make([]spanner.NullNumeric, len(cvt))
+		for i, x := range cvt {
+			if x != nil {
+				r[i] = spanner.NullNumeric{Numeric: *x.(*big.Rat), Valid: true}
+			}
+		}
+		return r, nil
+	}
+	return nil, fmt.Errorf("array type %s not supported", spannerType.Name)
+}
+
+// toString renders a decoded value as text. Arrays use the PostgreSQL
+// array literal syntax, since an array column is mapped to a STRING
+// column when the target is a PostgreSQL-dialect database.
+func toString(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	case civil.Date:
+		return v.String()
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case *big.Rat:
+		return spanner.NumericString(v)
+	case []interface{}:
+		elems := make([]string, len(v))
+		for i, e := range v {
+			if e == nil {
+				elems[i] = "NULL"
+				continue
+			}
+			s := strings.ReplaceAll(toString(e), `\`, `\\`)
+			elems[i] = `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+		}
+		return "{" + strings.Join(elems, ",") + "}"
+	}
+	return fmt.Sprintf("%v", val)
+}
+
+func valsToStrings(vals []interface{}) []string {
+	s := make([]string, len(vals))
+	for i, v := range vals {
+		s[i] = toString(v)
+	}
+	return s
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanner
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+	sppb "cloud.google.com/go/spanner/apiv1/spannerpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+func TestDecodeValue(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	scalar := func(code sppb.TypeCode) *sppb.Type { return &sppb.Type{Code: code} }
+	str := structpb.NewStringValue
+	testCases := []struct {
+		name     string
+		gcv      spanner.GenericColumnValue
+		expected interface{}
+	}{
+		{"null", spanner.GenericColumnValue{Type: scalar(sppb.TypeCode_INT64), Value: structpb.NewNullValue()}, nil},
+		{"bool", spanner.GenericColumnValue{Type: scalar(sppb.TypeCode_BOOL), Value: structpb.NewBoolValue(true)}, true},
+		{"int64", spanner.GenericColumnValue{Type: scalar(sppb.TypeCode_INT64), Value: str("42")}, int64(42)},
+		{"float64", spanner.GenericColumnValue{Type: scalar(sppb.TypeCode_FLOAT64), Value: structpb.NewNumberValue(1.5)}, float64(1.5)},
+		{"string", spanner.GenericColumnValue{Type: scalar(sppb.TypeCode_STRING), Value: str("abc")}, "abc"},
+		{"bytes", spanner.GenericColumnValue{Type: scalar(sppb.TypeCode_BYTES), Value: str("AQI=")}, []byte{1, 2}},
+		{"date", spanner.GenericColumnValue{Type: scalar(sppb.TypeCode_DATE), Value: str("2024-05-06")}, civil.Date{Year: 2024, Month: 5, Day: 6}},
+		{"timestamp", spanner.GenericColumnValue{Type: scalar(sppb.TypeCode_TIMESTAMP), Value: str("2024-05-06T07:08:09Z")}, ts},
+		{"numeric", spanner.GenericColumnValue{Type: scalar(sppb.TypeCode_NUMERIC), Value: str("1.250000000")}, big.NewRat(5, 4)},
+		{"pg numeric", spanner.GenericColumnValue{Type: &sppb.Type{Code: sppb.TypeCode_NUMERIC, TypeAnnotation: sppb.TypeAnnotationCode_PG_NUMERIC}, Value: str("NaN")}, "NaN"},
+		{"json", spanner.GenericColumnValue{Type: scalar(sppb.TypeCode_JSON), Value: str(`{"a":1}`)}, `{"a":1}`},
+		{"array", spanner.GenericColumnValue{
+			Type:  &sppb.Type{Code: sppb.TypeCode_ARRAY, ArrayElementType: scalar(sppb.TypeCode_INT64)},
+			Value: structpb.NewListValue(&structpb.ListValue{Values: []*structpb.Value{str("1"), structpb.NewNullValue()}}),
+		}, []interface{}{int64(1), nil}},
+	}
+	for _, tc := range testCases {
+		v, err := decodeValue(tc.gcv)
+		assert.Nil(t, err, tc.name)
+		assert.Equal(t, tc.expected, v, tc.name)
+	}
+}
+
+func TestConvertData(t *testing.T) {
+	srcSchema := schema.Table{
+		Name:   "t",
+		ColIds: []string{"c1", "c2", "c3", "c4"},
+		ColDefs: map[string]schema.Column{
+			"c1": {Name: "id", Type: schema.Type{Name: "INT64"}},
+			"c2": {Name: "price", Type: schema.Type{Name: "NUMERIC"}},
+			"c3": {Name: "tags", Type: schema.Type{Name: "STRING", Mods: []int64{ddl.MaxLength}, ArrayBounds: []int64{-1}}},
+			"c4": {Name: "note", Type: schema.Type{Name: "STRING", Mods: []int64{ddl.MaxLength}}},
+		},
+	}
+	spSchema := func(tagsT ddl.Type) ddl.CreateTable {
+		return ddl.CreateTable{
+			Name:   "t",
+			ColIds: []string{"c1", "c2", "c3", "c4"},
+			ColDefs: map[string]ddl.ColumnDef{
+				"c1": {Name: "id", T: ddl.Type{Name: ddl.Int64}},
+				"c2": {Name: "price", T: ddl.Type{Name: ddl.Numeric}},
+				"c3": {Name: "tags", T: tagsT},
+				"c4": {Name: "note", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+			},
+		}
+	}
+	vals := []interface{}{int64(7), big.NewRat(5, 2), []interface{}{"a", nil, `q"b`}, nil}
+
+	t.Run("googlesql", func(t *testing.T) {
+		conv := internal.MakeConv()
+		_, cols, cvtVals, err := ConvertData(conv, "t1", srcSchema.ColIds, srcSchema, spSchema(ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: true}), vals)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"id", "price", "tags"}, cols)
+		assert.Equal(t, []interface{}{
+			int64(7),
+			big.NewRat(5, 2),
+			[]spanner.NullString{{StringVal: "a", Valid: true}, {}, {StringVal: `q"b`, Valid: true}},
+		}, cvtVals)
+	})
+
+	t.Run("postgresql", func(t *testing.T) {
+		conv := internal.MakeConv()
+		conv.SpDialect = constants.DIALECT_POSTGRESQL
+		_, cols, cvtVals, err := ConvertData(conv, "t1", srcSchema.ColIds, srcSchema, spSchema(ddl.Type{Name: ddl.String, Len: ddl.MaxLength}), vals)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"id", "price", "tags"}, cols)
+		assert.Equal(t, []interface{}{
+			int64(7),
+			spanner.PGNumeric{Numeric: "2.500000000", Valid: true},
+			`{"a",NULL,"q\"b"}`,
+		}, cvtVals)
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+		conv := internal.MakeConv()
+		_, _, _, err := ConvertData(conv, "t1", []string{"c1"}, srcSchema, spSchema(ddl.Type{}), []interface{}{"x"})
+		assert.NotNil(t, err)
+	})
+}
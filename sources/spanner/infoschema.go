@@ -16,13 +16,17 @@ package spanner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"cloud.google.com/go/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/clients"
 	spannerclient "github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/clients/spanner/client"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/expressions_api"
 	_ "github.com/lib/pq" // we will use database/sql package instead of using this package directly
@@ -30,6 +34,7 @@ import (
 
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
@@ -44,6 +49,14 @@ type InfoSchemaImpl struct {
 	SpannerClient spannerclient.SpannerClient
 	Ctx           context.Context
 	SpDialect     string
+	// ReadTimestamp is the timestamp at which data is read when the
+	// database is the source of a migration. A zero value means strong reads.
+	ReadTimestamp time.Time
+	// VersionRetention is the version_retention_period of a source
+	// database: reads at ReadTimestamp fail once it is older than this.
+	VersionRetention time.Duration
+	SourceProfile    profiles.SourceProfile
+	TargetProfile    profiles.TargetProfile
 }
 
 func newInfoSchemaImplWithSpannerClient(ctx context.Context, dbURI string, spDialect string) (*InfoSchemaImpl, error) {
@@ -54,13 +67,140 @@ func newInfoSchemaImplWithSpannerClient(ctx context.Context, dbURI string, spDia
 	return &InfoSchemaImpl{SpannerClient: spannerClient, Ctx: ctx, SpDialect: spDialect}, nil
 }
 
+// NewInfoSchemaImplForSource returns an InfoSchemaImpl for a Spanner
+// database that is the source of a migration. The dialect and version
+// retention period of the database are detected, and the timestamp of
+// that read is used for all data reads so that every table is copied as
+// of the same point in time. The copy must therefore finish within the
+// version retention period; reads of tables started later fail with an
+// error naming the limit. The caller must Close the returned InfoSchemaImpl.
+func NewInfoSchemaImplForSource(ctx context.Context, dbURI string) (*InfoSchemaImpl, error) {
+	client, err := spanner.NewClient(ctx, dbURI, clients.FetchSpannerClientOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create spanner client for source database: %v", err)
+	}
+	txn := client.Single()
+	defer txn.Close()
+	stmt := spanner.Statement{SQL: `SELECT option_name, option_value FROM information_schema.database_options WHERE option_name IN ('database_dialect', 'version_retention_period')`}
+	options := map[string]string{}
+	err = txn.Query(ctx, stmt).Do(func(row *spanner.Row) error {
+		var name, value string
+		if err := row.Columns(&name, &value); err != nil {
+			return err
+		}
+		options[name] = value
+		return nil
+	})
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("couldn't get options of source database: %w", err)
+	}
+	ts, err := txn.Timestamp()
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("couldn't get read timestamp of source database: %w", err)
+	}
+	spDialect := constants.DIALECT_GOOGLESQL
+	if strings.EqualFold(options["database_dialect"], constants.DIALECT_POSTGRESQL) {
+		spDialect = constants.DIALECT_POSTGRESQL
+	}
+	retention, err := parseVersionRetentionPeriod(options["version_retention_period"])
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &InfoSchemaImpl{Client: client, Ctx: ctx, SpDialect: spDialect, ReadTimestamp: ts, VersionRetention: retention}, nil
+}
+
+// defaultVersionRetention is the version retention period of databases
+// that don't set version_retention_period.
+const defaultVersionRetention = time.Hour
+
+// parseVersionRetentionPeriod parses a version_retention_period option
+// value such as "1h", "90m" or "7d".
+func parseVersionRetentionPeriod(s string) (time.Duration, error) {
+	if s == "" {
+		return defaultVersionRetention, nil
+	}
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return 0, fmt.Errorf("can't parse version_retention_period %q of source database: %w", s, err)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("can't parse version_retention_period %q of source database: %w", s, err)
+	}
+	return d, nil
+}
+
+// Close closes the client created by NewInfoSchemaImplForSource.
+func (isi InfoSchemaImpl) Close() error {
+	if isi.Client != nil {
+		isi.Client.Close()
+	}
+	return nil
+}
+
+// checkReadTimestamp returns an error if the read timestamp of a source
+// database has fallen out of its version retention period, in which case
+// Spanner rejects reads with a less helpful "timestamp too old" error.
+func (isi InfoSchemaImpl) checkReadTimestamp(now time.Time) error {
+	if isi.ReadTimestamp.IsZero() || isi.VersionRetention == 0 {
+		return nil
+	}
+	if now.Sub(isi.ReadTimestamp) >= isi.VersionRetention {
+		return fmt.Errorf("read timestamp %s of the source database is older than its version_retention_period of %s: "+
+			"increase version_retention_period of the source database to cover the length of the copy and rerun the migration",
+			isi.ReadTimestamp.Format(time.RFC3339), isi.VersionRetention)
+	}
+	return nil
+}
+
 // GetToDdl function below implement the common.InfoSchema interface.
 func (isi InfoSchemaImpl) GetToDdl() common.ToDdl {
-	return ToDdlImpl{}
+	return ToDdlImpl{SrcDialect: isi.SpDialect}
 }
 
-// We leave the 5 functions below empty to be able to pass this as an infoSchema interface. We don't need these for now.
-func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSchema schema.Table, spCols []string, spSchema ddl.CreateTable, additionalAttributes internal.AdditionalDataAttributes) error {
+// ProcessData performs data conversion for a source Spanner table. The
+// partitions of the table are read concurrently, but conversion and
+// writes happen on this goroutine since conv is not safe for concurrent use.
+func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSchema schema.Table, commonColIds []string, spSchema ddl.CreateTable, additionalAttributes internal.AdditionalDataAttributes) error {
+	srcTableName := conv.SrcSchema[tableId].Name
+	rowsInterface, err := isi.GetRowsFromTable(conv, tableId)
+	if err != nil {
+		conv.Unexpected(fmt.Sprintf("Couldn't get data for table %s : err = %s", srcTableName, err))
+		return err
+	}
+	rows := rowsInterface.(*tableRows)
+	for row := range rows.rows {
+		vals := make([]interface{}, len(commonColIds))
+		for i, colId := range commonColIds {
+			var gcv spanner.GenericColumnValue
+			if err = row.ColumnByName(srcSchema.ColDefs[colId].Name, &gcv); err == nil {
+				vals[i], err = decodeValue(gcv)
+			}
+			if err != nil {
+				break
+			}
+		}
+		if err != nil {
+			conv.Unexpected(fmt.Sprintf("Couldn't decode row of table %s : err = %s", srcTableName, err))
+			conv.StatsAddBadRow(srcTableName, conv.DataMode())
+			continue
+		}
+		ProcessDataRow(conv, tableId, commonColIds, srcSchema, spSchema, vals)
+	}
+	var errs []error
+	for err := range rows.errs {
+		conv.Unexpected(fmt.Sprintf("Couldn't read partition of table %s : err = %s", srcTableName, err))
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("couldn't read %d partitions of table %s: %w", len(errs), srcTableName, errors.Join(errs...))
+	}
 	return nil
 }
 
@@ -85,8 +225,93 @@ func (isi InfoSchemaImpl) GetRowCount(table common.SchemaAndName) (int64, error)
 
 }
 
-func (isi InfoSchemaImpl) GetRowsFromTable(conv *internal.Conv, srcTable string) (interface{}, error) {
-	return nil, nil
+// tableRows is returned by GetRowsFromTable. rows is closed once all
+// partitions have been read; errs then receives one error per partition
+// that failed and is closed as well.
+type tableRows struct {
+	rows chan *spanner.Row
+	errs chan error
+}
+
+// GetRowsFromTable starts reading all rows of a table. The table is read
+// with a partitioned query at isi.ReadTimestamp, with the partitions
+// executed in parallel, and rows are delivered on the returned
+// *tableRows as they arrive.
+func (isi InfoSchemaImpl) GetRowsFromTable(conv *internal.Conv, tableId string) (interface{}, error) {
+	if isi.Client == nil {
+		return nil, fmt.Errorf("spanner client not initialized")
+	}
+	tbl := conv.SrcSchema[tableId]
+	var cols []string
+	for _, colId := range tbl.ColIds {
+		cols = append(cols, isi.quoteIdent(tbl.ColDefs[colId].Name))
+	}
+	stmt := spanner.Statement{SQL: fmt.Sprintf("SELECT %s FROM %s", strings.Join(cols, ", "), isi.quoteIdent(tbl.Name))}
+
+	if err := isi.checkReadTimestamp(time.Now()); err != nil {
+		return nil, err
+	}
+	tb := spanner.StrongRead()
+	if !isi.ReadTimestamp.IsZero() {
+		tb = spanner.ReadTimestamp(isi.ReadTimestamp)
+	}
+	txn, err := isi.Client.BatchReadOnlyTransaction(isi.Ctx, tb)
+	if err != nil {
+		return nil, err
+	}
+	partitions, err := txn.PartitionQuery(isi.Ctx, stmt, spanner.PartitionOptions{})
+	if err != nil {
+		txn.Close()
+		return nil, fmt.Errorf("couldn't partition query for table %s: %w", tbl.Name, err)
+	}
+
+	rows := &tableRows{
+		rows: make(chan *spanner.Row, common.DefaultWorkers),
+		errs: make(chan error, len(partitions)),
+	}
+	go func() {
+		defer txn.Cleanup(isi.Ctx)
+		work := make(chan *spanner.Partition, len(partitions))
+		for _, p := range partitions {
+			work <- p
+		}
+		close(work)
+		var mu sync.Mutex
+		var errs []error
+		var wg sync.WaitGroup
+		for i := 0; i < common.DefaultWorkers && i < len(partitions); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for p := range work {
+					err := txn.Execute(isi.Ctx, p).Do(func(row *spanner.Row) error {
+						rows.rows <- row
+						return nil
+					})
+					if err != nil {
+						mu.Lock()
+						errs = append(errs, err)
+						mu.Unlock()
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		close(rows.rows)
+		for _, err := range errs {
+			rows.errs <- err
+		}
+		close(rows.errs)
+	}()
+	return rows, nil
+}
+
+// quoteIdent quotes an identifier in the dialect of the database.
+func (isi InfoSchemaImpl) quoteIdent(name string) string {
+	if isi.SpDialect == constants.DIALECT_POSTGRESQL {
+		return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+	}
+	return "`" + name + "`"
 }
 
 
@@ -495,6 +720,11 @@ func (isi InfoSchemaImpl) GetInterleaveTables(spSchema ddl.Schema) (map[string]d
 
 func toType(dataType string) schema.Type {
 	switch {
+	case strings.HasSuffix(dataType, "[]"):
+		// PostgreSQL dialect array, e.g. "character varying(10)[]".
+		schemaType := toType(strings.TrimSuffix(dataType, "[]"))
+		schemaType.ArrayBounds = []int64{-1}
+		return schemaType
 	case strings.Contains(dataType, "ARRAY"):
 		typeLenStr := dataType[(strings.Index(dataType, "<") + 1):(len(dataType) - 1)]
 		schemaType := toType(typeLenStr)
@@ -16,6 +16,7 @@ package spanner
 
 import (
 	"testing"
+	"time"
 
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
@@ -47,9 +48,38 @@ func TestToType(t *testing.T) {
 		{"float32_arr", "ARRAY<FLOAT32>", schema.Type{Name: "FLOAT32", ArrayBounds: []int64{-1}}},
 		{"float64_arr", "ARRAY<FLOAT64>", schema.Type{Name: "FLOAT64", ArrayBounds: []int64{-1}}},
 		{"numeric_arr", "ARRAY<NUMERIC>", schema.Type{Name: "NUMERIC", ArrayBounds: []int64{-1}}},
+		// PostgreSQL dialect array types.
+		{"pg_bigint_arr", "bigint[]", schema.Type{Name: "bigint", ArrayBounds: []int64{-1}}},
+		{"pg_varchar_arr", "character varying(10)[]", schema.Type{Name: "character varying", Mods: []int64{10}, ArrayBounds: []int64{-1}}},
 	}
 	for _, tc := range testCases {
 		ty := toType(tc.dataType)
 		assert.Equal(t, tc.expColumnType, ty, tc.name)
 	}
 }
+
+func TestParseVersionRetentionPeriod(t *testing.T) {
+	for s, want := range map[string]time.Duration{
+		"":      time.Hour,
+		"1h":    time.Hour,
+		"90m":   90 * time.Minute,
+		"3600s": time.Hour,
+		"7d":    7 * 24 * time.Hour,
+	} {
+		got, err := parseVersionRetentionPeriod(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, want, got, s)
+	}
+	_, err := parseVersionRetentionPeriod("1w")
+	assert.Error(t, err)
+}
+
+func TestCheckReadTimestamp(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+	isi := InfoSchemaImpl{ReadTimestamp: ts, VersionRetention: time.Hour}
+	assert.NoError(t, isi.checkReadTimestamp(ts.Add(59*time.Minute)))
+	assert.EqualError(t, isi.checkReadTimestamp(ts.Add(time.Hour)), "read timestamp 2024-01-02T03:00:00Z of the source database is older than its version_retention_period of 1h0m0s: "+
+		"increase version_retention_period of the source database to cover the length of the copy and rerun the migration")
+	// Strong reads aren't limited.
+	assert.NoError(t, InfoSchemaImpl{}.checkReadTimestamp(ts))
+}
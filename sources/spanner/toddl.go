@@ -15,12 +15,17 @@
 package spanner
 
 import (
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
 )
 
 type ToDdlImpl struct {
+	// SrcDialect is the dialect of the Spanner database the schema is read
+	// from. Types are remapped when it differs from the target dialect.
+	SrcDialect string
 }
 
 // ToSpannerType maps a scalar source schema type (defined by id and
@@ -31,6 +36,11 @@ type ToDdlImpl struct {
 func (tdi ToDdlImpl) ToSpannerType(conv *internal.Conv, spType string, srcType schema.Type, isPk bool) (ddl.Type, []internal.SchemaIssue) {
 	ty, issues := toSpannerTypeInternal(conv, srcType)
 	ty.IsArray = len(srcType.ArrayBounds) == 1
+	if conv.SpDialect == constants.DIALECT_POSTGRESQL && tdi.SrcDialect != constants.DIALECT_POSTGRESQL {
+		var pgIssues []internal.SchemaIssue
+		ty, pgIssues = common.ToPGDialectType(ty, isPk)
+		issues = append(issues, pgIssues...)
+	}
 	return ty, issues
 }
 
@@ -69,3 +69,32 @@ func TestToSpannerType(t *testing.T) {
 		assert.Equal(t, tc.expDDLType, ty, tc.name)
 	}
 }
+
+func TestToSpannerTypeGoogleSQLToPostgreSQL(t *testing.T) {
+	conv := internal.MakeConv()
+	conv.SpDialect = constants.DIALECT_POSTGRESQL
+	toDDLImpl := ToDdlImpl{SrcDialect: constants.DIALECT_GOOGLESQL}
+	toDDLTests := []struct {
+		name       string
+		columnType schema.Type
+		isPk       bool
+		expDDLType ddl.Type
+		expIssues  []internal.SchemaIssue
+	}{
+		{"int", schema.Type{Name: "INT64"}, false, ddl.Type{Name: ddl.Int64}, nil},
+		{"numeric", schema.Type{Name: "NUMERIC"}, false, ddl.Type{Name: ddl.Numeric}, nil},
+		{"numeric_pk", schema.Type{Name: "NUMERIC"}, true, ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, []internal.SchemaIssue{internal.NumericPKNotSupported}},
+		{"int_arr", schema.Type{Name: "INT64", ArrayBounds: []int64{-1}}, false, ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, []internal.SchemaIssue{internal.ArrayTypeNotSupported}},
+	}
+	for _, tc := range toDDLTests {
+		ty, issues := toDDLImpl.ToSpannerType(conv, "", tc.columnType, tc.isPk)
+		assert.Equal(t, tc.expDDLType, ty, tc.name)
+		assert.Equal(t, tc.expIssues, issues, tc.name)
+	}
+
+	// Types are not remapped between databases of the same dialect.
+	toDDLImpl = ToDdlImpl{SrcDialect: constants.DIALECT_POSTGRESQL}
+	ty, issues := toDDLImpl.ToSpannerType(conv, "", schema.Type{Name: "bigint", ArrayBounds: []int64{-1}}, false)
+	assert.Equal(t, ddl.Type{Name: ddl.Int64, IsArray: true}, ty)
+	assert.Nil(t, issues)
+}
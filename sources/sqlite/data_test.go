@@ -0,0 +1,85 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+func TestConvScalar(t *testing.T) {
+	conv := internal.MakeConv()
+	testCases := []struct {
+		ty       string
+		srcType  string
+		in       string
+		expected interface{}
+	}{
+		{ddl.Bool, "BOOLEAN", "1", true},
+		{ddl.Bool, "BOOLEAN", "false", false},
+		{ddl.Bytes, "BLOB", "\x01\x02", []byte{1, 2}},
+		{ddl.Date, "DATE", "2024-03-04", civil.Date{Year: 2024, Month: 3, Day: 4}},
+		{ddl.Date, "DATE", "2024-03-04 10:00:00", civil.Date{Year: 2024, Month: 3, Day: 4}},
+		{ddl.Float32, "REAL", "1.5", float32(1.5)},
+		{ddl.Float64, "REAL", "-2", float64(-2)},
+		{ddl.Int64, "INTEGER", "42", int64(42)},
+		{ddl.Numeric, "DECIMAL", "12.5", big.NewRat(25, 2)},
+		{ddl.String, "TEXT", "abc", "abc"},
+		{ddl.JSON, "JSON", `{"a": 1}`, `{"a": 1}`},
+		{ddl.Timestamp, "DATETIME", "2024-01-02 03:04:05.5", time.Date(2024, 1, 2, 3, 4, 5, 500000000, time.UTC)},
+		{ddl.Timestamp, "DATETIME", "2024-01-02T03:04:05+01:00", time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 3600))},
+		{ddl.Timestamp, "DATETIME", "2024-01-02", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{ddl.Timestamp, "INTEGER", "86400", time.Date(1970, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range testCases {
+		v, err := convScalar(conv, ddl.Type{Name: tc.ty}, tc.srcType, tc.in)
+		assert.Nil(t, err, tc.in)
+		if ts, ok := tc.expected.(time.Time); ok {
+			assert.True(t, ts.Equal(v.(time.Time)), "%s: got %v", tc.in, v)
+			continue
+		}
+		assert.Equal(t, tc.expected, v, tc.in)
+	}
+
+	for _, tc := range []struct{ ty, in string }{
+		{ddl.Bool, "yes"},
+		{ddl.Int64, "1.5"},
+		{ddl.Numeric, "abc"},
+		{ddl.Date, "March 4"},
+		{ddl.Timestamp, "yesterday"},
+	} {
+		_, err := convScalar(conv, ddl.Type{Name: tc.ty}, "", tc.in)
+		assert.NotNil(t, err, tc.in)
+	}
+
+	conv.SpDialect = constants.DIALECT_POSTGRESQL
+	v, err := convScalar(conv, ddl.Type{Name: ddl.Numeric}, "DECIMAL", "12.5")
+	assert.Nil(t, err)
+	assert.Equal(t, spanner.PGNumeric{Numeric: "12.5", Valid: true}, v)
+}
+
+func TestValsToStrings(t *testing.T) {
+	vals := []interface{}{nil, []byte("ab"), "c", int64(-3), 1.25, true, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	assert.Equal(t, []string{"NULL", "ab", "c", "-3", "1.25", "true", "2024-01-02T03:04:05Z"}, valsToStrings(vals))
+}
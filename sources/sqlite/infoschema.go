@@ -0,0 +1,427 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	_ "modernc.org/sqlite" // The driver should be used via the database/sql package.
+)
+
+// SchemaName is the name SQLite gives to the database of the file opened.
+const SchemaName = "main"
+
+// InfoSchemaImpl reads the schema of a SQLite database file from the
+// table_list, table_info, foreign_key_list and index_list pragmas, since
+// SQLite has no information schema.
+type InfoSchemaImpl struct {
+	DbName string
+	Db     *sql.DB
+}
+
+// GetToDdl function below implement the common.InfoSchema interface.
+func (isi InfoSchemaImpl) GetToDdl() common.ToDdl {
+	return ToDdlImpl{}
+}
+
+// GetTableName returns table name.
+func (isi InfoSchemaImpl) GetTableName(dbName string, tableName string) string {
+	return tableName
+}
+
+// GetRowsFromTable returns a sql Rows object for a table.
+func (isi InfoSchemaImpl) GetRowsFromTable(conv *internal.Conv, tableId string) (interface{}, error) {
+	return isi.getRowsFromTable(conv, tableId, nil)
+}
+
+// getRowsFromTable returns a sql Rows object for the rows of a table in
+// keyRange, or for the whole table if keyRange is nil.
+func (isi InfoSchemaImpl) getRowsFromTable(conv *internal.Conv, tableId string, keyRange *internal.KeyRange) (interface{}, error) {
+	tbl := conv.SrcSchema[tableId]
+	if len(tbl.ColIds) == 0 {
+		conv.Unexpected(fmt.Sprintf("Couldn't get source columns for table %s ", tbl.Name))
+		return nil, nil
+	}
+	var cols []string
+	for _, colId := range tbl.ColIds {
+		cols = append(cols, quoteIdent(tbl.ColDefs[colId].Name))
+	}
+	q := fmt.Sprintf("SELECT %s FROM %s", strings.Join(cols, ", "), quoteIdent(tbl.Name))
+	var keyCol string
+	if keyRange != nil {
+		keyCol = quoteIdent(tbl.ColDefs[keyRange.ColId].Name)
+	}
+	where, limit := common.ReadPredicate(conv, tableId, quoteIdent, common.QuoteString)
+	q += common.SelectClause(where, keyRange, keyCol)
+	if limit > 0 {
+		q += fmt.Sprintf(" LIMIT %d", limit)
+	}
+	return isi.Db.Query(q)
+}
+
+// GetKeyBounds returns the smallest and largest values of column colId
+// of a table, so that the table can be read in key ranges.
+func (isi InfoSchemaImpl) GetKeyBounds(conv *internal.Conv, tableId string, colId string) (int64, int64, bool, error) {
+	tbl := conv.SrcSchema[tableId]
+	col := quoteIdent(tbl.ColDefs[colId].Name)
+	q := fmt.Sprintf("SELECT MIN(%s), MAX(%s) FROM %s", col, col, quoteIdent(tbl.Name))
+	var min, max sql.NullInt64
+	if err := isi.Db.QueryRow(q).Scan(&min, &max); err != nil {
+		return 0, 0, false, err
+	}
+	return min.Int64, max.Int64, min.Valid && max.Valid, nil
+}
+
+// ProcessData performs data conversion for source database.
+//
+// Only the rows in additionalAttributes.KeyRange are read when it is set.
+// ProcessData may be called concurrently (see common.KeyRangeInfoSchema),
+// so conv is only updated while holding conv.ConvLock.
+func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSchema schema.Table, commonColIds []string, spSchema ddl.CreateTable, additionalAttributes internal.AdditionalDataAttributes) error {
+	srcTableName := conv.SrcSchema[tableId].Name
+	rowsInterface, err := isi.getRowsFromTable(conv, tableId, additionalAttributes.KeyRange)
+	if err != nil {
+		conv.ConvLock.Lock()
+		conv.Unexpected(fmt.Sprintf("Couldn't get data for table %s : err = %s", srcTableName, err))
+		conv.ConvLock.Unlock()
+		return err
+	}
+	if rowsInterface == nil {
+		return nil
+	}
+	rows := rowsInterface.(*sql.Rows)
+	defer rows.Close()
+	srcCols, _ := rows.Columns()
+	v, scanArgs := buildVals(len(srcCols))
+	colNameIdMap := internal.GetSrcColNameIdMap(conv.SrcSchema[tableId])
+	var cvtSrcCols []string
+	for _, colId := range commonColIds {
+		cvtSrcCols = append(cvtSrcCols, srcSchema.ColDefs[colId].Name)
+	}
+	// Rows are converted and written without holding conv.ConvLock, so
+	// that the key ranges of a table are processed in parallel.
+	for rows.Next() {
+		err := rows.Scan(scanArgs...)
+		if err != nil {
+			conv.ConvLock.Lock()
+			conv.Unexpected(fmt.Sprintf("Couldn't process sql data row: %s", err))
+			// Scan failed, so we don't have any data to add to bad rows.
+			conv.StatsAddBadRow(srcTableName, conv.DataMode())
+			conv.ConvLock.Unlock()
+			continue
+		}
+		values := valsToStrings(v)
+		newValues, err := common.PrepareValues(conv, tableId, colNameIdMap, commonColIds, srcCols, values)
+		if err != nil {
+			conv.BadRowConcurrently(srcTableName, srcCols, values, err)
+			continue
+		}
+		spTableName, cvtCols, cvtVals, err := convertData(conv, tableId, commonColIds, srcSchema, spSchema, newValues)
+		if err != nil {
+			conv.BadRowConcurrently(srcTableName, cvtSrcCols, newValues, err)
+			continue
+		}
+		conv.WriteRowConcurrently(srcTableName, spTableName, cvtCols, cvtVals)
+	}
+	return rows.Err()
+}
+
+// GetRowCount with number of rows in each table.
+func (isi InfoSchemaImpl) GetRowCount(table common.SchemaAndName) (int64, error) {
+	return isi.GetFilteredRowCount(table, "")
+}
+
+// GetFilteredRowCount returns the number of rows of a table that satisfy
+// where, or of all its rows if where is "".
+func (isi InfoSchemaImpl) GetFilteredRowCount(table common.SchemaAndName, where string) (int64, error) {
+	q := fmt.Sprintf("SELECT count(*) FROM %s%s", quoteIdent(table.Name), common.SelectClause(where, nil, ""))
+	var count int64
+	err := isi.Db.QueryRow(q).Scan(&count)
+	return count, err
+}
+
+// GetTables return list of tables in the database file, leaving out
+// SQLite's internal tables, virtual tables and the tables backing them.
+func (isi InfoSchemaImpl) GetTables() ([]common.SchemaAndName, error) {
+	q := `SELECT name FROM pragma_table_list
+		WHERE schema = ? AND type = 'table' AND name NOT LIKE 'sqlite\_%' ESCAPE '\'
+		ORDER BY name`
+	rows, err := isi.Db.Query(q, SchemaName)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get tables: %w", err)
+	}
+	defer rows.Close()
+	var tableName string
+	var tables []common.SchemaAndName
+	for rows.Next() {
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, fmt.Errorf("couldn't get tables: %w", err)
+		}
+		tables = append(tables, common.SchemaAndName{Schema: SchemaName, Name: tableName})
+	}
+	return tables, rows.Err()
+}
+
+// GetColumns returns a list of Column objects and names
+func (isi InfoSchemaImpl) GetColumns(conv *internal.Conv, table common.SchemaAndName, constraints map[string][]string, primaryKeys []string) (map[string]schema.Column, []string, error) {
+	cols, err := isi.Db.Query(`SELECT name, type, "notnull", dflt_value FROM pragma_table_info(?) ORDER BY cid`, table.Name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't get schema for table %s: %s", table.Name, err)
+	}
+	defer cols.Close()
+	colDefs := make(map[string]schema.Column)
+	var colIds []string
+	var colName, dataType string
+	var notNull bool
+	var colDefault sql.NullString
+	for cols.Next() {
+		if err := cols.Scan(&colName, &dataType, &notNull, &colDefault); err != nil {
+			conv.Unexpected(fmt.Sprintf("Can't scan: %v", err))
+			continue
+		}
+		colId := internal.GenerateColumnId()
+		colDefs[colId] = schema.Column{
+			Id:      colId,
+			Name:    colName,
+			Type:    toType(dataType),
+			NotNull: notNull,
+			Ignored: schema.Ignored{Default: colDefault.Valid},
+		}
+		colIds = append(colIds, colId)
+	}
+	return colDefs, colIds, cols.Err()
+}
+
+// GetConstraints returns a list of primary keys and by-column map of
+// other constraints. Note: we need to preserve ordinal order of
+// columns in primary key constraints.
+// Check constraints are only available as the text of the CREATE TABLE
+// statement in SQLite, and are dropped.
+func (isi InfoSchemaImpl) GetConstraints(conv *internal.Conv, table common.SchemaAndName) ([]string, []schema.CheckConstraint, map[string][]string, error) {
+	rows, err := isi.Db.Query(`SELECT name FROM pragma_table_info(?) WHERE pk > 0 ORDER BY pk`, table.Name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer rows.Close()
+	var primaryKeys []string
+	var col string
+	for rows.Next() {
+		if err := rows.Scan(&col); err != nil {
+			conv.Unexpected(fmt.Sprintf("Can't scan: %v", err))
+			continue
+		}
+		primaryKeys = append(primaryKeys, col)
+	}
+	return primaryKeys, nil, map[string][]string{}, rows.Err()
+}
+
+// GetForeignKeys return list all the foreign keys constraints.
+// SQLite foreign keys are unnamed, and may leave out the columns they
+// reference when these are the primary key of the referenced table.
+func (isi InfoSchemaImpl) GetForeignKeys(conv *internal.Conv, table common.SchemaAndName) (foreignKeys []schema.ForeignKey, err error) {
+	rows, err := isi.Db.Query(`SELECT id, "table", "from", "to", on_update, on_delete FROM pragma_foreign_key_list(?) ORDER BY id, seq`, table.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var id int
+	var col, refTable, onUpdate, onDelete string
+	var refCol sql.NullString
+	fKeys := make(map[int]common.FkConstraint)
+	var ids []int
+	for rows.Next() {
+		if err := rows.Scan(&id, &refTable, &col, &refCol, &onUpdate, &onDelete); err != nil {
+			conv.Unexpected(fmt.Sprintf("Can't scan: %v", err))
+			continue
+		}
+		fk, found := fKeys[id]
+		if !found {
+			ids = append(ids, id)
+			fk = common.FkConstraint{Name: fmt.Sprintf("fk_%s_%d", table.Name, id), Table: refTable, OnDelete: onDelete, OnUpdate: onUpdate}
+		}
+		fk.Cols = append(fk.Cols, col)
+		fk.Refcols = append(fk.Refcols, refCol.String)
+		fKeys[id] = fk
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		fk := fKeys[id]
+		if fk.Refcols[0] == "" {
+			refCols, _, _, err := isi.GetConstraints(conv, common.SchemaAndName{Schema: table.Schema, Name: fk.Table})
+			if err != nil {
+				return nil, err
+			}
+			if len(refCols) != len(fk.Cols) {
+				conv.Unexpected(fmt.Sprintf("Can't find the columns referenced by foreign key %s of table %s", fk.Name, table.Name))
+				continue
+			}
+			fk.Refcols = refCols
+		}
+		foreignKeys = append(foreignKeys,
+			schema.ForeignKey{
+				Id:               internal.GenerateForeignkeyId(),
+				Name:             fk.Name,
+				ColumnNames:      fk.Cols,
+				ReferTableName:   fk.Table,
+				ReferColumnNames: fk.Refcols,
+				OnDelete:         fk.OnDelete,
+				OnUpdate:         fk.OnUpdate,
+			})
+	}
+	return foreignKeys, nil
+}
+
+// GetIndexes return a list of all indexes for the specified table.
+// The indexes implementing primary keys, partial indexes and indexes on
+// expressions are left out, since Spanner has no equivalent for them.
+func (isi InfoSchemaImpl) GetIndexes(conv *internal.Conv, table common.SchemaAndName, colNameIdMap map[string]string) ([]schema.Index, error) {
+	rows, err := isi.Db.Query(`SELECT name, "unique", origin, partial FROM pragma_index_list(?) ORDER BY name`, table.Name)
+	if err != nil {
+		return nil, err
+	}
+	var name, origin string
+	var unique, partial bool
+	var indexes []schema.Index
+	for rows.Next() {
+		if err := rows.Scan(&name, &unique, &origin, &partial); err != nil {
+			conv.Unexpected(fmt.Sprintf("Can't scan: %v", err))
+			continue
+		}
+		if origin == "pk" || partial {
+			continue
+		}
+		indexes = append(indexes, schema.Index{Id: internal.GenerateIndexesId(), Name: name, Unique: unique})
+	}
+	// Close rows before reading the columns of each index: the database
+	// may have a single connection.
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	var result []schema.Index
+	for _, index := range indexes {
+		keys, ok, err := isi.getIndexKeys(conv, index.Name, colNameIdMap)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			index.Keys = keys
+			result = append(result, index)
+		}
+	}
+	return result, nil
+}
+
+// getIndexKeys returns the keys of an index. ok is false if the index has
+// keys that are expressions rather than columns.
+func (isi InfoSchemaImpl) getIndexKeys(conv *internal.Conv, index string, colNameIdMap map[string]string) ([]schema.Key, bool, error) {
+	rows, err := isi.Db.Query(`SELECT name, "desc" FROM pragma_index_xinfo(?) WHERE "key" = 1 ORDER BY seqno`, index)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+	var column sql.NullString
+	var desc bool
+	var keys []schema.Key
+	ok := true
+	for rows.Next() {
+		if err := rows.Scan(&column, &desc); err != nil {
+			conv.Unexpected(fmt.Sprintf("Can't scan: %v", err))
+			continue
+		}
+		colId, found := colNameIdMap[column.String]
+		if !column.Valid || !found {
+			ok = false
+			continue
+		}
+		keys = append(keys, schema.Key{ColId: colId, Desc: desc})
+	}
+	return keys, ok && len(keys) > 0, rows.Err()
+}
+
+// toType parses the type a SQLite column is declared with, e.g.
+// "VARCHAR(20)" or "decimal(10, 2)".
+func toType(dataType string) schema.Type {
+	name := strings.ToUpper(strings.TrimSpace(dataType))
+	var mods []int64
+	if i := strings.Index(name, "("); i >= 0 {
+		for _, m := range strings.Split(strings.TrimSuffix(strings.TrimSpace(name[i+1:]), ")"), ",") {
+			if n, err := strconv.ParseInt(strings.TrimSpace(m), 10, 64); err == nil {
+				mods = append(mods, n)
+			}
+		}
+		name = name[:i]
+	}
+	return schema.Type{Name: strings.Join(strings.Fields(name), " "), Mods: mods}
+}
+
+// quoteIdent quotes a SQLite identifier.
+func quoteIdent(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// buildVals constructs value containers to scan row results into.
+// Returns both the underlying containers (as a slice) as well as an
+// interface{} of pointers to containers to pass to rows.Scan.
+func buildVals(n int) (v []interface{}, iv []interface{}) {
+	v = make([]interface{}, n)
+	iv = make([]interface{}, len(v))
+	for i := range v {
+		iv[i] = &v[i]
+	}
+	return v, iv
+}
+
+// valsToStrings converts the values of a row, which SQLite types
+// dynamically, to strings.
+func valsToStrings(vals []interface{}) []string {
+	toString := func(val interface{}) string {
+		switch x := val.(type) {
+		case nil:
+			return "NULL"
+		case []byte:
+			return string(x)
+		case string:
+			return x
+		case int64:
+			return strconv.FormatInt(x, 10)
+		case float64:
+			return strconv.FormatFloat(x, 'g', -1, 64)
+		case bool:
+			return strconv.FormatBool(x)
+		case time.Time:
+			return x.Format(time.RFC3339Nano)
+		default:
+			return fmt.Sprint(x)
+		}
+	}
+	var s []string
+	for _, v := range vals {
+		s = append(s, toString(v))
+	}
+	return s
+}
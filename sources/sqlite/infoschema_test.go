@@ -0,0 +1,226 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/expressions_api"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/mocks"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// mkTestDB creates a SQLite database file with the given statements.
+func mkTestDB(t *testing.T, stmts ...string) *sql.DB {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	assert.Nil(t, err)
+	t.Cleanup(func() { db.Close() })
+	for _, s := range stmts {
+		_, err := db.Exec(s)
+		assert.Nil(t, err, s)
+	}
+	return db
+}
+
+var testStmts = []string{
+	`CREATE TABLE customers (
+		id INTEGER PRIMARY KEY,
+		name VARCHAR(40) NOT NULL,
+		email TEXT,
+		vip BOOLEAN DEFAULT 0,
+		balance DECIMAL(10, 2),
+		created DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`,
+	`CREATE UNIQUE INDEX idx_email ON customers (email DESC)`,
+	`CREATE INDEX idx_lower_name ON customers (lower(name))`,
+	`CREATE INDEX idx_partial ON customers (name) WHERE vip`,
+	`CREATE TABLE orders (
+		customer_id INTEGER NOT NULL REFERENCES customers ON DELETE CASCADE,
+		order_id INTEGER NOT NULL,
+		day DATE,
+		amount REAL,
+		data BLOB,
+		note,
+		PRIMARY KEY (customer_id, order_id)
+	)`,
+	`CREATE TABLE logs (msg TEXT)`,
+	`CREATE TABLE items (id INTEGER PRIMARY KEY AUTOINCREMENT, payload JSON)`,
+	`INSERT INTO customers VALUES (1, 'Ann', 'ann@example.com', 1, 12.5, '2024-01-02 03:04:05')`,
+	`INSERT INTO customers VALUES (2, 'Bob', NULL, 0, 7, 1700000000)`,
+	`INSERT INTO orders VALUES (1, 10, '2024-03-04', 1.5, x'0102', 'n')`,
+	`INSERT INTO orders VALUES (2, 20, NULL, 2, NULL, 3)`,
+	`INSERT INTO logs VALUES ('hello')`,
+}
+
+func processSchema(t *testing.T, db *sql.DB) *internal.Conv {
+	conv := internal.MakeConv()
+	mockAccessor := new(mocks.MockExpressionVerificationAccessor)
+	mockAccessor.On("VerifyExpressions", context.Background(), mock.Anything).Return(internal.VerifyExpressionsOutput{})
+	schemaToSpanner := common.SchemaToSpannerImpl{
+		ExpressionVerificationAccessor: mockAccessor,
+		DdlV:                           &expressions_api.MockDDLVerifier{},
+	}
+	processSchema := common.ProcessSchemaImpl{}
+	err := processSchema.ProcessSchema(conv, InfoSchemaImpl{DbName: "test", Db: db}, 1, internal.AdditionalSchemaAttributes{}, &schemaToSpanner, &common.UtilsOrderImpl{}, &common.InfoSchemaImpl{})
+	assert.Nil(t, err)
+	return conv
+}
+
+func TestProcessSchema(t *testing.T) {
+	conv := processSchema(t, mkTestDB(t, testStmts...))
+	expectedSchema := map[string]ddl.CreateTable{
+		"customers": {
+			Name:   "customers",
+			ColIds: []string{"id", "name", "email", "vip", "balance", "created"},
+			ColDefs: map[string]ddl.ColumnDef{
+				"id":      {Name: "id", T: ddl.Type{Name: ddl.Int64}},
+				"name":    {Name: "name", T: ddl.Type{Name: ddl.String, Len: 40}, NotNull: true},
+				"email":   {Name: "email", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+				"vip":     {Name: "vip", T: ddl.Type{Name: ddl.Bool}},
+				"balance": {Name: "balance", T: ddl.Type{Name: ddl.Numeric}},
+				"created": {Name: "created", T: ddl.Type{Name: ddl.Timestamp}},
+			},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "id", Order: 1}},
+			Indexes: []ddl.CreateIndex{{
+				Name:    "idx_email",
+				TableId: "customers",
+				Unique:  true,
+				Keys:    []ddl.IndexKey{{ColId: "email", Desc: true, Order: 1}},
+			}},
+		},
+		"orders": {
+			Name:   "orders",
+			ColIds: []string{"customer_id", "order_id", "day", "amount", "data", "note"},
+			ColDefs: map[string]ddl.ColumnDef{
+				"customer_id": {Name: "customer_id", T: ddl.Type{Name: ddl.Int64}, NotNull: true},
+				"order_id":    {Name: "order_id", T: ddl.Type{Name: ddl.Int64}, NotNull: true},
+				"day":         {Name: "day", T: ddl.Type{Name: ddl.Date}},
+				"amount":      {Name: "amount", T: ddl.Type{Name: ddl.Float64}},
+				"data":        {Name: "data", T: ddl.Type{Name: ddl.Bytes, Len: ddl.MaxLength}},
+				"note":        {Name: "note", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+			},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "customer_id", Order: 1}, {ColId: "order_id", Order: 2}},
+			ForeignKeys: []ddl.Foreignkey{{Name: "fk_orders_0", ColIds: []string{"customer_id"}, ReferTableId: "customers", ReferColumnIds: []string{"id"}, OnDelete: "CASCADE", OnUpdate: "NO ACTION"}},
+		},
+		"logs": {
+			Name:   "logs",
+			ColIds: []string{"msg", "synth_id"},
+			ColDefs: map[string]ddl.ColumnDef{
+				"msg":      {Name: "msg", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+				"synth_id": {Name: "synth_id", T: ddl.Type{Name: ddl.String, Len: 50}},
+			},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "synth_id", Order: 1}},
+		},
+		"items": {
+			Name:   "items",
+			ColIds: []string{"id", "payload"},
+			ColDefs: map[string]ddl.ColumnDef{
+				"id":      {Name: "id", T: ddl.Type{Name: ddl.Int64}},
+				"payload": {Name: "payload", T: ddl.Type{Name: ddl.JSON}},
+			},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "id", Order: 1}},
+		},
+	}
+	internal.AssertSpSchema(conv, t, expectedSchema, stripSchemaComments(conv.SpSchema))
+	assert.Equal(t, int64(0), conv.Unexpecteds())
+}
+
+func TestProcessData(t *testing.T) {
+	db := mkTestDB(t, testStmts...)
+	conv := processSchema(t, db)
+	conv.SetDataMode()
+	type row struct {
+		table string
+		cols  []string
+		vals  []interface{}
+	}
+	var rows []row
+	conv.SetDataSink(func(table string, cols []string, vals []interface{}) {
+		rows = append(rows, row{table, cols, vals})
+	})
+	is := InfoSchemaImpl{DbName: "test", Db: db}
+	commonInfoSchema := common.InfoSchemaImpl{}
+	commonInfoSchema.SetRowStats(conv, is)
+	assert.Equal(t, int64(2), conv.Stats.Rows["customers"])
+	assert.Equal(t, int64(0), conv.Stats.Rows["items"])
+	commonInfoSchema.ProcessData(conv, is, internal.AdditionalDataAttributes{})
+
+	assert.ElementsMatch(t, []row{
+		{"customers", []string{"id", "name", "email", "vip", "balance", "created"},
+			[]interface{}{int64(1), "Ann", "ann@example.com", true, big.NewRat(25, 2), time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}},
+		{"customers", []string{"id", "name", "vip", "balance", "created"},
+			[]interface{}{int64(2), "Bob", false, big.NewRat(7, 1), time.Unix(1700000000, 0).UTC()}},
+		{"orders", []string{"customer_id", "order_id", "day", "amount", "data", "note"},
+			[]interface{}{int64(1), int64(10), civil.Date{Year: 2024, Month: 3, Day: 4}, 1.5, []byte{1, 2}, "n"}},
+		{"orders", []string{"customer_id", "order_id", "amount", "note"},
+			[]interface{}{int64(2), int64(20), float64(2), "3"}},
+		{"logs", []string{"msg", "synth_id"}, []interface{}{"hello", "0"}},
+	}, rows)
+	assert.Equal(t, int64(0), conv.BadRows())
+}
+
+func TestRowCountAndKeyBounds(t *testing.T) {
+	db := mkTestDB(t, testStmts...)
+	is := InfoSchemaImpl{DbName: "test", Db: db}
+	count, err := is.GetFilteredRowCount(common.SchemaAndName{Schema: SchemaName, Name: "customers"}, "vip")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+
+	conv := processSchema(t, db)
+	tableId, err := internal.GetTableIdFromSpName(conv.SpSchema, "customers")
+	assert.Nil(t, err)
+	colId, err := internal.GetColIdFromSrcName(conv.SrcSchema[tableId].ColDefs, "id")
+	assert.Nil(t, err)
+	min, max, ok, err := is.GetKeyBounds(conv, tableId, colId)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{int64(1), int64(2), true}, []interface{}{min, max, ok})
+}
+
+func TestGetTables(t *testing.T) {
+	db := mkTestDB(t,
+		`CREATE TABLE t (id INTEGER PRIMARY KEY AUTOINCREMENT)`,
+		`CREATE VIEW v AS SELECT id FROM t`,
+		`CREATE VIRTUAL TABLE r USING rtree(id, x0, x1)`,
+	)
+	tables, err := InfoSchemaImpl{Db: db}.GetTables()
+	assert.Nil(t, err)
+	assert.Equal(t, []common.SchemaAndName{{Schema: SchemaName, Name: "t"}}, tables)
+}
+
+// stripSchemaComments returns a schema with all comments removed.
+// We mostly ignore schema comments in testing since schema comments
+// are often changed and are not a core part of conversion functionality.
+func stripSchemaComments(spSchema map[string]ddl.CreateTable) map[string]ddl.CreateTable {
+	for t, ct := range spSchema {
+		for c, cd := range ct.ColDefs {
+			cd.Comment = ""
+			ct.ColDefs[c] = cd
+		}
+		ct.Comment = ""
+		spSchema[t] = ct
+	}
+	return spSchema
+}
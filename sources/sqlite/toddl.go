@@ -0,0 +1,85 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sqlite handles schema and data migrations from SQLite database
+// files.
+package sqlite
+
+import (
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// ToDdlImpl sqlite specific implementation for ToDdl.
+type ToDdlImpl struct {
+}
+
+// ToSpannerType maps a scalar source schema type (defined by id and
+// mods) into a Spanner type. This is the core source-to-Spanner type
+// mapping.  toSpannerType returns the Spanner type and a list of type
+// conversion issues encountered.
+func (tdi ToDdlImpl) ToSpannerType(conv *internal.Conv, spType string, srcType schema.Type, isPk bool) (ddl.Type, []internal.SchemaIssue) {
+	return toSpannerTypeInternal(spType, srcType)
+}
+
+func (tdi ToDdlImpl) GetColumnAutoGen(conv *internal.Conv, autoGenCol ddl.AutoGenCol, colId string, tableId string) (*ddl.AutoGenCol, error) {
+	return nil, nil
+}
+
+// toSpannerTypeInternal maps the declared type of a SQLite column. SQLite
+// columns can hold values of any type, so types that SQLite itself knows
+// nothing about (such as DATE or BOOLEAN) are mapped by name, and the
+// others by the affinity SQLite gives them: see
+// https://www.sqlite.org/datatype3.html#determination_of_column_affinity.
+func toSpannerTypeInternal(spType string, srcType schema.Type) (ddl.Type, []internal.SchemaIssue) {
+	if spType == ddl.String {
+		return ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, nil
+	}
+	name := srcType.Name
+	switch name {
+	case "BOOL", "BOOLEAN":
+		return ddl.Type{Name: ddl.Bool}, nil
+	case "DATE":
+		return ddl.Type{Name: ddl.Date}, nil
+	case "DATETIME", "TIMESTAMP":
+		return ddl.Type{Name: ddl.Timestamp}, nil
+	case "JSON":
+		return ddl.Type{Name: ddl.JSON}, nil
+	case "DECIMAL", "NUMERIC":
+		return ddl.Type{Name: ddl.Numeric}, nil
+	}
+	switch {
+	case strings.Contains(name, "INT"):
+		return ddl.Type{Name: ddl.Int64}, nil
+	case strings.Contains(name, "CHAR"), strings.Contains(name, "CLOB"), strings.Contains(name, "TEXT"):
+		if len(srcType.Mods) > 0 && srcType.Mods[0] > 0 {
+			return ddl.Type{Name: ddl.String, Len: srcType.Mods[0]}, nil
+		}
+		return ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, nil
+	case strings.Contains(name, "BLOB"):
+		return ddl.Type{Name: ddl.Bytes, Len: ddl.MaxLength}, nil
+	case strings.Contains(name, "REAL"), strings.Contains(name, "FLOA"), strings.Contains(name, "DOUB"):
+		if spType == ddl.Float32 {
+			return ddl.Type{Name: ddl.Float32}, nil
+		}
+		return ddl.Type{Name: ddl.Float64}, nil
+	default:
+		// Columns declared without a type, or with a type SQLite gives
+		// numeric affinity to, can hold values of any type.
+		return ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, []internal.SchemaIssue{internal.NoGoodType}
+	}
+}
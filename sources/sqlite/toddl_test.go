@@ -0,0 +1,64 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+func TestToType(t *testing.T) {
+	assert.Equal(t, schema.Type{Name: "VARCHAR", Mods: []int64{20}}, toType("varchar(20)"))
+	assert.Equal(t, schema.Type{Name: "DECIMAL", Mods: []int64{10, 2}}, toType("DECIMAL( 10, 2 )"))
+	assert.Equal(t, schema.Type{Name: "UNSIGNED BIG INT"}, toType("unsigned  big int"))
+	assert.Equal(t, schema.Type{Name: ""}, toType(""))
+}
+
+func TestToSpannerType(t *testing.T) {
+	conv := internal.MakeConv()
+	testCases := []struct {
+		srcType  schema.Type
+		spType   string
+		expected ddl.Type
+		issues   []internal.SchemaIssue
+	}{
+		{schema.Type{Name: "INTEGER"}, "", ddl.Type{Name: ddl.Int64}, nil},
+		{schema.Type{Name: "UNSIGNED BIG INT"}, "", ddl.Type{Name: ddl.Int64}, nil},
+		{schema.Type{Name: "NVARCHAR", Mods: []int64{30}}, "", ddl.Type{Name: ddl.String, Len: 30}, nil},
+		{schema.Type{Name: "CLOB"}, "", ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, nil},
+		{schema.Type{Name: "BLOB"}, "", ddl.Type{Name: ddl.Bytes, Len: ddl.MaxLength}, nil},
+		{schema.Type{Name: "DOUBLE PRECISION"}, "", ddl.Type{Name: ddl.Float64}, nil},
+		{schema.Type{Name: "REAL"}, ddl.Float32, ddl.Type{Name: ddl.Float32}, nil},
+		{schema.Type{Name: "BOOLEAN"}, "", ddl.Type{Name: ddl.Bool}, nil},
+		{schema.Type{Name: "DATE"}, "", ddl.Type{Name: ddl.Date}, nil},
+		{schema.Type{Name: "DATETIME"}, "", ddl.Type{Name: ddl.Timestamp}, nil},
+		{schema.Type{Name: "DATETIME"}, ddl.String, ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, nil},
+		{schema.Type{Name: "NUMERIC", Mods: []int64{10, 2}}, "", ddl.Type{Name: ddl.Numeric}, nil},
+		{schema.Type{Name: "JSON"}, "", ddl.Type{Name: ddl.JSON}, nil},
+		{schema.Type{Name: ""}, "", ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, []internal.SchemaIssue{internal.NoGoodType}},
+		{schema.Type{Name: "MONEY"}, "", ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, []internal.SchemaIssue{internal.NoGoodType}},
+	}
+	toDdl := ToDdlImpl{}
+	for _, tc := range testCases {
+		ty, issues := toDdl.ToSpannerType(conv, tc.spType, tc.srcType, false)
+		assert.Equal(t, tc.expected, ty, tc.srcType.Name)
+		assert.Equal(t, tc.issues, issues, tc.srcType.Name)
+	}
+}
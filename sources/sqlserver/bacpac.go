@@ -0,0 +1,700 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// A BACPAC, written by SqlPackage or SSMS when exporting a database, is a
+// zip archive holding the schema of the database in model.xml and the
+// rows of each table in Data/<schema>.<table>/*.BCP files.
+const (
+	bacpacModelFile = "model.xml"
+	bacpacDataDir   = "Data/"
+)
+
+// Bacpac is the schema and data files of a BACPAC.
+type Bacpac struct {
+	Path   string
+	Tables []*bacpacTable
+	zip    *zip.ReadCloser
+}
+
+type bacpacTable struct {
+	Schema      string
+	Name        string
+	Columns     []bacpacColumn
+	PrimaryKeys []string
+	// Constraints maps a column to the types of the constraints on it
+	// other than its primary key, as GetConstraints returns them.
+	Constraints map[string][]string
+	ForeignKeys []bacpacForeignKey
+	Indexes     []bacpacIndex
+	dataFiles   []*zip.File
+}
+
+type bacpacColumn struct {
+	Name      string
+	TypeName  string
+	Length    int64
+	IsMax     bool
+	Precision int
+	Scale     int
+	Nullable  bool
+	Default   bool
+	Collation string // The collation of char, varchar and text columns.
+}
+
+type bacpacForeignKey struct {
+	Name        string
+	Columns     []string
+	ReferSchema string
+	ReferTable  string
+	ReferCols   []string
+}
+
+type bacpacIndex struct {
+	Name   string
+	Unique bool
+	Keys   []string
+	Desc   []bool
+	Stored []string
+}
+
+// The elements of model.xml describe objects, with their properties and
+// relationships to other objects, which are either nested elements or
+// references by name.
+type bacpacModel struct {
+	Elements []modelElement `xml:"Model>Element"`
+}
+
+type modelElement struct {
+	Type          string              `xml:"Type,attr"`
+	Name          string              `xml:"Name,attr"`
+	Properties    []modelProperty     `xml:"Property"`
+	Relationships []modelRelationship `xml:"Relationship"`
+}
+
+type modelProperty struct {
+	Name  string `xml:"Name,attr"`
+	Value string `xml:"Value,attr"`
+}
+
+type modelRelationship struct {
+	Name    string       `xml:"Name,attr"`
+	Entries []modelEntry `xml:"Entry"`
+}
+
+type modelEntry struct {
+	Elements   []modelElement   `xml:"Element"`
+	References []modelReference `xml:"References"`
+}
+
+type modelReference struct {
+	Name string `xml:"Name,attr"`
+}
+
+func (e modelElement) property(name string) string {
+	for _, p := range e.Properties {
+		if p.Name == name {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+func (e modelElement) relationship(name string) []modelEntry {
+	for _, r := range e.Relationships {
+		if r.Name == name {
+			return r.Entries
+		}
+	}
+	return nil
+}
+
+// elements returns the elements nested in relationship name.
+func (e modelElement) elements(name string) []modelElement {
+	var elements []modelElement
+	for _, entry := range e.relationship(name) {
+		elements = append(elements, entry.Elements...)
+	}
+	return elements
+}
+
+// references returns the names of the objects referenced in relationship
+// name.
+func (e modelElement) references(name string) []string {
+	var names []string
+	for _, entry := range e.relationship(name) {
+		for _, r := range entry.References {
+			names = append(names, r.Name)
+		}
+	}
+	return names
+}
+
+// OpenBacpac reads the schema of the BACPAC at path and lists its data
+// files. The archive stays open to read the data files, until Close.
+func OpenBacpac(path string) (*Bacpac, error) {
+	z, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't open BACPAC %s: %v", path, err)
+	}
+	b := &Bacpac{Path: path, zip: z}
+	if err := b.readModel(); err != nil {
+		z.Close()
+		return nil, fmt.Errorf("can't read BACPAC %s: %v", path, err)
+	}
+	b.listDataFiles()
+	return b, nil
+}
+
+// Close closes the archive.
+func (b *Bacpac) Close() error {
+	return b.zip.Close()
+}
+
+func (b *Bacpac) readModel() error {
+	f, err := b.zip.Open(bacpacModelFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var model bacpacModel
+	if err := xml.NewDecoder(f).Decode(&model); err != nil {
+		return fmt.Errorf("can't parse %s: %v", bacpacModelFile, err)
+	}
+	// Columns without a collation of their own have the collation of the
+	// database.
+	collation := ""
+	for _, e := range model.Elements {
+		if e.Type == "SqlDatabaseOptions" {
+			collation = e.property("Collation")
+		}
+	}
+	tables := make(map[string]*bacpacTable)
+	for _, e := range model.Elements {
+		if e.Type != "SqlTable" {
+			continue
+		}
+		parts := splitModelName(e.Name)
+		if len(parts) != 2 {
+			continue
+		}
+		t := &bacpacTable{Schema: parts[0], Name: parts[1], Constraints: make(map[string][]string)}
+		for _, c := range e.elements("Columns") {
+			col, ok := readModelColumn(c)
+			if !ok {
+				logger.Log.Info(fmt.Sprintf("Skipping column %s of BACPAC: only columns stored in the data files are migrated", c.Name))
+				continue
+			}
+			if col.Collation == "" && isCharType(col.TypeName) {
+				col.Collation = collation
+			}
+			t.Columns = append(t.Columns, col)
+		}
+		tables[e.Name] = t
+		b.Tables = append(b.Tables, t)
+	}
+	// Constraints and indexes are elements of their own, that reference
+	// their table.
+	for _, e := range model.Elements {
+		switch e.Type {
+		case "SqlPrimaryKeyConstraint":
+			if t := definingTable(tables, e, "DefiningTable"); t != nil {
+				for _, spec := range e.elements("ColumnSpecifications") {
+					t.PrimaryKeys = append(t.PrimaryKeys, columnNames(spec.references("Column"))...)
+				}
+			}
+		case "SqlUniqueConstraint":
+			if t := definingTable(tables, e, "DefiningTable"); t != nil {
+				for _, spec := range e.elements("ColumnSpecifications") {
+					for _, col := range columnNames(spec.references("Column")) {
+						t.Constraints[col] = append(t.Constraints[col], "UNIQUE")
+					}
+				}
+			}
+		case "SqlForeignKeyConstraint":
+			t := definingTable(tables, e, "DefiningTable")
+			refer := e.references("ForeignTable")
+			if t == nil || len(refer) != 1 {
+				continue
+			}
+			referParts := splitModelName(refer[0])
+			if len(referParts) != 2 {
+				continue
+			}
+			fk := bacpacForeignKey{
+				Name:        lastModelName(e.Name),
+				Columns:     columnNames(e.references("Columns")),
+				ReferSchema: referParts[0],
+				ReferTable:  referParts[1],
+				ReferCols:   columnNames(e.references("ForeignColumns")),
+			}
+			for _, col := range fk.Columns {
+				t.Constraints[col] = append(t.Constraints[col], "FOREIGN KEY")
+			}
+			t.ForeignKeys = append(t.ForeignKeys, fk)
+		case "SqlDefaultConstraint":
+			t := definingTable(tables, e, "DefiningTable")
+			if t == nil {
+				continue
+			}
+			for _, col := range columnNames(e.references("ForColumn")) {
+				for i := range t.Columns {
+					if t.Columns[i].Name == col {
+						t.Columns[i].Default = true
+					}
+				}
+			}
+		case "SqlIndex":
+			t := definingTable(tables, e, "IndexedObject")
+			if t == nil {
+				continue
+			}
+			index := bacpacIndex{Name: lastModelName(e.Name), Unique: e.property("IsUnique") == "True"}
+			for _, spec := range e.elements("ColumnSpecifications") {
+				for _, col := range columnNames(spec.references("Column")) {
+					index.Keys = append(index.Keys, col)
+					index.Desc = append(index.Desc, spec.property("IsAscending") == "False")
+				}
+			}
+			index.Stored = columnNames(e.references("IncludedColumns"))
+			t.Indexes = append(t.Indexes, index)
+		}
+	}
+	sort.Slice(b.Tables, func(i, j int) bool {
+		if b.Tables[i].Schema != b.Tables[j].Schema {
+			return b.Tables[i].Schema < b.Tables[j].Schema
+		}
+		return b.Tables[i].Name < b.Tables[j].Name
+	})
+	for _, t := range b.Tables {
+		sort.Slice(t.ForeignKeys, func(i, j int) bool { return t.ForeignKeys[i].Name < t.ForeignKeys[j].Name })
+		sort.Slice(t.Indexes, func(i, j int) bool { return t.Indexes[i].Name < t.Indexes[j].Name })
+	}
+	return nil
+}
+
+// readModelColumn reads a column of a table. Computed columns and
+// rowversion columns aren't stored in the data files, and are skipped.
+func readModelColumn(e modelElement) (bacpacColumn, bool) {
+	if e.Type != "SqlSimpleColumn" {
+		return bacpacColumn{}, false
+	}
+	parts := splitModelName(e.Name)
+	col := bacpacColumn{Name: parts[len(parts)-1], Nullable: e.property("IsNullable") != "False"}
+	for _, ts := range e.elements("TypeSpecifier") {
+		if types := ts.references("Type"); len(types) > 0 {
+			col.TypeName = strings.ToLower(lastModelName(types[0]))
+		}
+		col.IsMax = ts.property("IsMax") == "True"
+		col.Length, _ = strconv.ParseInt(ts.property("Length"), 10, 64)
+		col.Precision, _ = strconv.Atoi(ts.property("Precision"))
+		col.Scale, _ = strconv.Atoi(ts.property("Scale"))
+		if ts.property("Scale") == "" {
+			switch col.TypeName {
+			case timeType, dateTime2Type, dateTimeOffsetType:
+				col.Scale = 7
+			}
+		}
+	}
+	switch col.TypeName {
+	case "decimal", "numeric":
+		if col.Precision == 0 {
+			col.Precision = 18
+		}
+	case "char", "varchar", "nchar", "nvarchar", "binary", "varbinary":
+		if col.Length == 0 && !col.IsMax {
+			col.Length = 1
+		}
+	case timestampType, "rowversion", "":
+		return bacpacColumn{}, false
+	}
+	if isCharType(col.TypeName) {
+		col.Collation = e.property("Collation")
+	}
+	return col, true
+}
+
+// isCharType returns true for the types whose values are stored in the
+// code page of their collation.
+func isCharType(typeName string) bool {
+	switch typeName {
+	case "char", "varchar", "text":
+		return true
+	}
+	return false
+}
+
+func definingTable(tables map[string]*bacpacTable, e modelElement, relationship string) *bacpacTable {
+	refs := e.references(relationship)
+	if len(refs) != 1 {
+		return nil
+	}
+	return tables[refs[0]]
+}
+
+// columnNames returns the names of the columns referenced by names of the
+// form [schema].[table].[column].
+func columnNames(names []string) []string {
+	var cols []string
+	for _, n := range names {
+		cols = append(cols, lastModelName(n))
+	}
+	return cols
+}
+
+// splitModelName splits a name of model.xml such as [dbo].[Orders] in its
+// parts, "dbo" and "Orders".
+func splitModelName(name string) []string {
+	var parts []string
+	for len(name) > 0 {
+		if name[0] == '.' {
+			name = name[1:]
+			continue
+		}
+		if name[0] != '[' {
+			i := strings.IndexByte(name, '.')
+			if i < 0 {
+				i = len(name)
+			}
+			parts = append(parts, name[:i])
+			name = name[i:]
+			continue
+		}
+		var b strings.Builder
+		i := 1
+		for ; i < len(name); i++ {
+			if name[i] == ']' {
+				if i+1 < len(name) && name[i+1] == ']' {
+					b.WriteByte(']')
+					i++
+					continue
+				}
+				break
+			}
+			b.WriteByte(name[i])
+		}
+		parts = append(parts, b.String())
+		if i >= len(name) {
+			break
+		}
+		name = name[i+1:]
+	}
+	return parts
+}
+
+func lastModelName(name string) string {
+	parts := splitModelName(name)
+	if len(parts) == 0 {
+		return ""
+	}
+	return parts[len(parts)-1]
+}
+
+// listDataFiles assigns the data files of the archive to their tables.
+func (b *Bacpac) listDataFiles() {
+	tables := make(map[string]*bacpacTable)
+	for _, t := range b.Tables {
+		tables[t.Schema+"."+t.Name] = t
+	}
+	for _, f := range b.zip.File {
+		if !strings.HasPrefix(f.Name, bacpacDataDir) || !strings.EqualFold(path.Ext(f.Name), ".bcp") {
+			continue
+		}
+		dir := path.Base(path.Dir(f.Name))
+		if t, ok := tables[dir]; ok {
+			t.dataFiles = append(t.dataFiles, f)
+		} else {
+			logger.Log.Info(fmt.Sprintf("Skipping data file %s of BACPAC: no table %s in its schema", f.Name, dir))
+		}
+	}
+	for _, t := range b.Tables {
+		sort.Slice(t.dataFiles, func(i, j int) bool { return t.dataFiles[i].Name < t.dataFiles[j].Name })
+	}
+}
+
+func (b *Bacpac) table(schemaName, name string) (*bacpacTable, error) {
+	for _, t := range b.Tables {
+		if t.Schema == schemaName && t.Name == name {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("no table %s.%s in BACPAC %s", schemaName, name, b.Path)
+}
+
+// bacpacRows reads the rows of a table, from one data file after the
+// other.
+type bacpacRows struct {
+	t     *bacpacTable
+	files []*zip.File
+	rc    io.ReadCloser
+	r     *bcpReader
+}
+
+// Next returns the values of the next row, or io.EOF after the last row.
+// Rows can still be read after a *bcpValueError.
+func (rows *bacpacRows) Next() ([]string, error) {
+	for {
+		if rows.r == nil {
+			if len(rows.files) == 0 {
+				return nil, io.EOF
+			}
+			rc, err := rows.files[0].Open()
+			if err != nil {
+				return nil, err
+			}
+			rows.rc, rows.r = rc, newBcpReader(rc, rows.t.Columns)
+		}
+		vals, err := rows.r.Next()
+		if err != io.EOF {
+			var valueErr *bcpValueError
+			if err != nil && !errors.As(err, &valueErr) {
+				err = fmt.Errorf("%s: %v", rows.files[0].Name, err)
+			}
+			return vals, err
+		}
+		rows.rc.Close()
+		rows.rc, rows.r, rows.files = nil, nil, rows.files[1:]
+	}
+}
+
+// Close closes the data file being read.
+func (rows *bacpacRows) Close() error {
+	if rows.rc == nil {
+		return nil
+	}
+	return rows.rc.Close()
+}
+
+// BacpacInfoSchemaImpl reads the schema and data of a BACPAC, exported
+// from SQL Server, as InfoSchemaImpl reads them from a live database.
+// Columns have the names and types of the information schema of SQL
+// Server, and rows are converted with ProcessDataRow.
+type BacpacInfoSchemaImpl struct {
+	Bacpac *Bacpac
+}
+
+// GetToDdl function below implement the common.InfoSchema interface.
+func (isi BacpacInfoSchemaImpl) GetToDdl() common.ToDdl {
+	return ToDdlImpl{}
+}
+
+// GetTableName returns table name.
+func (isi BacpacInfoSchemaImpl) GetTableName(schema string, tableName string) string {
+	return InfoSchemaImpl{}.GetTableName(schema, tableName)
+}
+
+// GetTables return list of tables in the BACPAC.
+func (isi BacpacInfoSchemaImpl) GetTables() ([]common.SchemaAndName, error) {
+	var tables []common.SchemaAndName
+	for _, t := range isi.Bacpac.Tables {
+		tables = append(tables, common.SchemaAndName{Schema: t.Schema, Name: t.Name})
+	}
+	return tables, nil
+}
+
+// GetColumns returns a list of Column objects and names
+func (isi BacpacInfoSchemaImpl) GetColumns(conv *internal.Conv, table common.SchemaAndName, constraints map[string][]string, primaryKeys []string) (map[string]schema.Column, []string, error) {
+	t, err := isi.Bacpac.table(table.Schema, table.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	colDefs := make(map[string]schema.Column)
+	var colIds []string
+	for _, c := range t.Columns {
+		colId := internal.GenerateColumnId()
+		colDefs[colId] = schema.Column{
+			Id:      colId,
+			Name:    c.Name,
+			Type:    c.toType(),
+			NotNull: !c.Nullable,
+			Ignored: schema.Ignored{Default: c.Default},
+		}
+		colIds = append(colIds, colId)
+	}
+	return colDefs, colIds, nil
+}
+
+// toType returns the type of c, with the modifiers the information schema
+// of SQL Server gives it.
+func (c bacpacColumn) toType() schema.Type {
+	switch c.TypeName {
+	case "char", "varchar", "nchar", "nvarchar", "binary", "varbinary":
+		if c.IsMax {
+			return schema.Type{Name: c.TypeName, Mods: []int64{-1}}
+		}
+		return schema.Type{Name: c.TypeName, Mods: []int64{c.Length}}
+	case "decimal", "numeric":
+		if c.Scale != 0 {
+			return schema.Type{Name: c.TypeName, Mods: []int64{int64(c.Precision), int64(c.Scale)}}
+		}
+		return schema.Type{Name: c.TypeName, Mods: []int64{int64(c.Precision)}}
+	default:
+		return schema.Type{Name: c.TypeName}
+	}
+}
+
+// GetConstraints returns a list of primary keys and by-column map of
+// other constraints.
+func (isi BacpacInfoSchemaImpl) GetConstraints(conv *internal.Conv, table common.SchemaAndName) ([]string, []schema.CheckConstraint, map[string][]string, error) {
+	t, err := isi.Bacpac.table(table.Schema, table.Name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return t.PrimaryKeys, nil, t.Constraints, nil
+}
+
+// GetForeignKeys returns a list of all the foreign key constraints.
+func (isi BacpacInfoSchemaImpl) GetForeignKeys(conv *internal.Conv, table common.SchemaAndName) (foreignKeys []schema.ForeignKey, err error) {
+	t, err := isi.Bacpac.table(table.Schema, table.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, fk := range t.ForeignKeys {
+		foreignKeys = append(foreignKeys,
+			schema.ForeignKey{
+				Id:               internal.GenerateForeignkeyId(),
+				Name:             fk.Name,
+				ColumnNames:      fk.Columns,
+				ReferTableName:   isi.GetTableName(fk.ReferSchema, fk.ReferTable),
+				ReferColumnNames: fk.ReferCols})
+	}
+	return foreignKeys, nil
+}
+
+// GetIndexes return a list of all indexes for the specified table.
+func (isi BacpacInfoSchemaImpl) GetIndexes(conv *internal.Conv, table common.SchemaAndName, colNameIdMap map[string]string) ([]schema.Index, error) {
+	t, err := isi.Bacpac.table(table.Schema, table.Name)
+	if err != nil {
+		return nil, err
+	}
+	var indexes []schema.Index
+	for _, ix := range t.Indexes {
+		index := schema.Index{
+			Id:     internal.GenerateIndexesId(),
+			Name:   ix.Name,
+			Unique: ix.Unique}
+		for i, col := range ix.Keys {
+			index.Keys = append(index.Keys, schema.Key{ColId: colNameIdMap[col], Desc: ix.Desc[i]})
+		}
+		for _, col := range ix.Stored {
+			index.StoredColumnIds = append(index.StoredColumnIds, colNameIdMap[col])
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, nil
+}
+
+// GetRowsFromTable returns the rows of a table, which are read with
+// Next.
+func (isi BacpacInfoSchemaImpl) GetRowsFromTable(conv *internal.Conv, tableId string) (interface{}, error) {
+	tbl := conv.SrcSchema[tableId]
+	t, err := isi.Bacpac.table(tbl.Schema, strings.Replace(tbl.Name, tbl.Schema+".", "", 1))
+	if err != nil {
+		return nil, err
+	}
+	return &bacpacRows{t: t, files: t.dataFiles}, nil
+}
+
+// GetRowCount returns 0: a BACPAC doesn't record the number of rows of its
+// tables, which are instead counted by ProcessData as it reads them.
+func (isi BacpacInfoSchemaImpl) GetRowCount(table common.SchemaAndName) (int64, error) {
+	if _, err := isi.Bacpac.table(table.Schema, table.Name); err != nil {
+		return 0, err
+	}
+	return 0, nil
+}
+
+// ProcessData converts the rows of a table read from its data files and
+// writes them to Spanner, counting them in conv.Stats.Rows. ProcessData
+// may be called concurrently for several tables, so conv is only updated
+// while holding conv.ConvLock. Rows are converted and written without
+// holding it.
+func (isi BacpacInfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSchema schema.Table, commonColIds []string, spSchema ddl.CreateTable, additionalAttributes internal.AdditionalDataAttributes) error {
+	srcTableName := conv.SrcSchema[tableId].Name
+	rowsInterface, err := isi.GetRowsFromTable(conv, tableId)
+	if err != nil {
+		conv.ConvLock.Lock()
+		conv.Unexpected(fmt.Sprintf("Couldn't get data for table %s : err = %s", srcTableName, err))
+		conv.ConvLock.Unlock()
+		return err
+	}
+	rows := rowsInterface.(*bacpacRows)
+	defer rows.Close()
+	var srcCols []string
+	for _, c := range rows.t.Columns {
+		srcCols = append(srcCols, c.Name)
+	}
+	colNameIdMap := internal.GetSrcColNameIdMap(conv.SrcSchema[tableId])
+	var cvtSrcCols []string
+	for _, colId := range commonColIds {
+		cvtSrcCols = append(cvtSrcCols, srcSchema.ColDefs[colId].Name)
+	}
+	for {
+		values, err := rows.Next()
+		if err == io.EOF {
+			return nil
+		}
+		var valueErr *bcpValueError
+		if errors.As(err, &valueErr) {
+			conv.ConvLock.Lock()
+			conv.StatsAddRow(srcTableName, conv.DataMode())
+			conv.Unexpected(fmt.Sprintf("Couldn't process data row of table %s: %s", srcTableName, err))
+			conv.StatsAddBadRow(srcTableName, conv.DataMode())
+			conv.CollectBadRow(srcTableName, srcCols, values, err)
+			conv.ConvLock.Unlock()
+			continue
+		}
+		if err != nil {
+			// The rest of the data file can't be found after a value that
+			// can't be read.
+			conv.ConvLock.Lock()
+			conv.Unexpected(fmt.Sprintf("Couldn't read data of table %s : err = %s", srcTableName, err))
+			conv.ConvLock.Unlock()
+			return err
+		}
+		conv.ConvLock.Lock()
+		conv.StatsAddRow(srcTableName, conv.DataMode())
+		conv.ConvLock.Unlock()
+		newValues, err := common.PrepareValues(conv, tableId, colNameIdMap, commonColIds, srcCols, values)
+		if err != nil {
+			conv.BadRowConcurrently(srcTableName, srcCols, values, err)
+			continue
+		}
+		spTableName, cvtCols, cvtVals, err := ConvertData(conv, tableId, commonColIds, srcSchema, spSchema, newValues)
+		if err != nil {
+			conv.BadRowConcurrently(srcTableName, cvtSrcCols, newValues, err)
+			continue
+		}
+		conv.WriteRowConcurrently(srcTableName, spTableName, cvtCols, cvtVals)
+	}
+}
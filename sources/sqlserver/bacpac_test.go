@@ -0,0 +1,493 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"cloud.google.com/go/civil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/expressions_api"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/mocks"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+)
+
+const testBacpacModel = `<?xml version="1.0" encoding="utf-8"?>
+<DataSchemaModel FileFormatVersion="1.2" SchemaVersion="2.9" DspName="Microsoft.Data.Tools.Schema.Sql.Sql150DatabaseSchemaProvider" xmlns="http://schemas.microsoft.com/sqlserver/dac/Serialization/2012/02">
+  <Model>
+    <Element Type="SqlDatabaseOptions">
+      <Property Name="Collation" Value="SQL_Latin1_General_CP1_CI_AS" />
+    </Element>
+    <Element Type="SqlSchema" Name="[sales]" />
+    <Element Type="SqlTable" Name="[dbo].[customers]">
+      <Relationship Name="Columns">
+        <Entry>
+          <Element Type="SqlSimpleColumn" Name="[dbo].[customers].[id]">
+            <Property Name="IsNullable" Value="False" />
+            <Relationship Name="TypeSpecifier"><Entry><Element Type="SqlTypeSpecifier">
+              <Relationship Name="Type"><Entry><References ExternalSource="BuiltIns" Name="[int]" /></Entry></Relationship>
+            </Element></Entry></Relationship>
+          </Element>
+        </Entry>
+        <Entry>
+          <Element Type="SqlSimpleColumn" Name="[dbo].[customers].[name]">
+            <Property Name="IsNullable" Value="False" />
+            <Relationship Name="TypeSpecifier"><Entry><Element Type="SqlTypeSpecifier">
+              <Property Name="Length" Value="40" />
+              <Relationship Name="Type"><Entry><References ExternalSource="BuiltIns" Name="[nvarchar]" /></Entry></Relationship>
+            </Element></Entry></Relationship>
+          </Element>
+        </Entry>
+        <Entry>
+          <Element Type="SqlSimpleColumn" Name="[dbo].[customers].[email]">
+            <Relationship Name="TypeSpecifier"><Entry><Element Type="SqlTypeSpecifier">
+              <Property Name="IsMax" Value="True" />
+              <Relationship Name="Type"><Entry><References ExternalSource="BuiltIns" Name="[varchar]" /></Entry></Relationship>
+            </Element></Entry></Relationship>
+          </Element>
+        </Entry>
+        <Entry>
+          <Element Type="SqlSimpleColumn" Name="[dbo].[customers].[vip]">
+            <Relationship Name="TypeSpecifier"><Entry><Element Type="SqlTypeSpecifier">
+              <Relationship Name="Type"><Entry><References ExternalSource="BuiltIns" Name="[bit]" /></Entry></Relationship>
+            </Element></Entry></Relationship>
+          </Element>
+        </Entry>
+        <Entry>
+          <Element Type="SqlSimpleColumn" Name="[dbo].[customers].[balance]">
+            <Relationship Name="TypeSpecifier"><Entry><Element Type="SqlTypeSpecifier">
+              <Property Name="Precision" Value="10" />
+              <Property Name="Scale" Value="2" />
+              <Relationship Name="Type"><Entry><References ExternalSource="BuiltIns" Name="[decimal]" /></Entry></Relationship>
+            </Element></Entry></Relationship>
+          </Element>
+        </Entry>
+        <Entry>
+          <Element Type="SqlSimpleColumn" Name="[dbo].[customers].[created]">
+            <Relationship Name="TypeSpecifier"><Entry><Element Type="SqlTypeSpecifier">
+              <Property Name="Scale" Value="3" />
+              <Relationship Name="Type"><Entry><References ExternalSource="BuiltIns" Name="[datetime2]" /></Entry></Relationship>
+            </Element></Entry></Relationship>
+          </Element>
+        </Entry>
+        <Entry>
+          <Element Type="SqlComputedColumn" Name="[dbo].[customers].[upper_name]">
+            <Property Name="ExpressionScript"><Value><![CDATA[upper([name])]]></Value></Property>
+          </Element>
+        </Entry>
+      </Relationship>
+    </Element>
+    <Element Type="SqlTable" Name="[sales].[orders]">
+      <Relationship Name="Columns">
+        <Entry>
+          <Element Type="SqlSimpleColumn" Name="[sales].[orders].[customer_id]">
+            <Property Name="IsNullable" Value="False" />
+            <Relationship Name="TypeSpecifier"><Entry><Element Type="SqlTypeSpecifier">
+              <Relationship Name="Type"><Entry><References ExternalSource="BuiltIns" Name="[int]" /></Entry></Relationship>
+            </Element></Entry></Relationship>
+          </Element>
+        </Entry>
+        <Entry>
+          <Element Type="SqlSimpleColumn" Name="[sales].[orders].[order_id]">
+            <Property Name="IsNullable" Value="False" />
+            <Relationship Name="TypeSpecifier"><Entry><Element Type="SqlTypeSpecifier">
+              <Relationship Name="Type"><Entry><References ExternalSource="BuiltIns" Name="[bigint]" /></Entry></Relationship>
+            </Element></Entry></Relationship>
+          </Element>
+        </Entry>
+        <Entry>
+          <Element Type="SqlSimpleColumn" Name="[sales].[orders].[day]">
+            <Relationship Name="TypeSpecifier"><Entry><Element Type="SqlTypeSpecifier">
+              <Relationship Name="Type"><Entry><References ExternalSource="BuiltIns" Name="[date]" /></Entry></Relationship>
+            </Element></Entry></Relationship>
+          </Element>
+        </Entry>
+        <Entry>
+          <Element Type="SqlSimpleColumn" Name="[sales].[orders].[amount]">
+            <Relationship Name="TypeSpecifier"><Entry><Element Type="SqlTypeSpecifier">
+              <Relationship Name="Type"><Entry><References ExternalSource="BuiltIns" Name="[float]" /></Entry></Relationship>
+            </Element></Entry></Relationship>
+          </Element>
+        </Entry>
+        <Entry>
+          <Element Type="SqlSimpleColumn" Name="[sales].[orders].[paid]">
+            <Property Name="IsNullable" Value="False" />
+            <Relationship Name="TypeSpecifier"><Entry><Element Type="SqlTypeSpecifier">
+              <Relationship Name="Type"><Entry><References ExternalSource="BuiltIns" Name="[money]" /></Entry></Relationship>
+            </Element></Entry></Relationship>
+          </Element>
+        </Entry>
+        <Entry>
+          <Element Type="SqlSimpleColumn" Name="[sales].[orders].[ref]">
+            <Relationship Name="TypeSpecifier"><Entry><Element Type="SqlTypeSpecifier">
+              <Relationship Name="Type"><Entry><References ExternalSource="BuiltIns" Name="[uniqueidentifier]" /></Entry></Relationship>
+            </Element></Entry></Relationship>
+          </Element>
+        </Entry>
+        <Entry>
+          <Element Type="SqlSimpleColumn" Name="[sales].[orders].[version]">
+            <Property Name="IsNullable" Value="False" />
+            <Relationship Name="TypeSpecifier"><Entry><Element Type="SqlTypeSpecifier">
+              <Relationship Name="Type"><Entry><References ExternalSource="BuiltIns" Name="[rowversion]" /></Entry></Relationship>
+            </Element></Entry></Relationship>
+          </Element>
+        </Entry>
+      </Relationship>
+    </Element>
+    <Element Type="SqlPrimaryKeyConstraint" Name="[dbo].[PK_customers]">
+      <Relationship Name="ColumnSpecifications"><Entry><Element Type="SqlIndexedColumnSpecification">
+        <Relationship Name="Column"><Entry><References Name="[dbo].[customers].[id]" /></Entry></Relationship>
+      </Element></Entry></Relationship>
+      <Relationship Name="DefiningTable"><Entry><References Name="[dbo].[customers]" /></Entry></Relationship>
+    </Element>
+    <Element Type="SqlPrimaryKeyConstraint" Name="[sales].[PK_orders]">
+      <Relationship Name="ColumnSpecifications">
+        <Entry><Element Type="SqlIndexedColumnSpecification">
+          <Relationship Name="Column"><Entry><References Name="[sales].[orders].[customer_id]" /></Entry></Relationship>
+        </Element></Entry>
+        <Entry><Element Type="SqlIndexedColumnSpecification">
+          <Relationship Name="Column"><Entry><References Name="[sales].[orders].[order_id]" /></Entry></Relationship>
+        </Element></Entry>
+      </Relationship>
+      <Relationship Name="DefiningTable"><Entry><References Name="[sales].[orders]" /></Entry></Relationship>
+    </Element>
+    <Element Type="SqlUniqueConstraint" Name="[dbo].[UQ_customers_name]">
+      <Relationship Name="ColumnSpecifications"><Entry><Element Type="SqlIndexedColumnSpecification">
+        <Relationship Name="Column"><Entry><References Name="[dbo].[customers].[name]" /></Entry></Relationship>
+      </Element></Entry></Relationship>
+      <Relationship Name="DefiningTable"><Entry><References Name="[dbo].[customers]" /></Entry></Relationship>
+    </Element>
+    <Element Type="SqlForeignKeyConstraint" Name="[sales].[FK_orders_customers]">
+      <Relationship Name="Columns"><Entry><References Name="[sales].[orders].[customer_id]" /></Entry></Relationship>
+      <Relationship Name="DefiningTable"><Entry><References Name="[sales].[orders]" /></Entry></Relationship>
+      <Relationship Name="ForeignColumns"><Entry><References Name="[dbo].[customers].[id]" /></Entry></Relationship>
+      <Relationship Name="ForeignTable"><Entry><References Name="[dbo].[customers]" /></Entry></Relationship>
+    </Element>
+    <Element Type="SqlDefaultConstraint" Name="[dbo].[DF_customers_vip]">
+      <Property Name="DefaultExpressionScript"><Value><![CDATA[((0))]]></Value></Property>
+      <Relationship Name="DefiningTable"><Entry><References Name="[dbo].[customers]" /></Entry></Relationship>
+      <Relationship Name="ForColumn"><Entry><References Name="[dbo].[customers].[vip]" /></Entry></Relationship>
+    </Element>
+    <Element Type="SqlIndex" Name="[sales].[orders].[IX_orders_day]">
+      <Relationship Name="ColumnSpecifications"><Entry><Element Type="SqlIndexedColumnSpecification">
+        <Property Name="IsAscending" Value="False" />
+        <Relationship Name="Column"><Entry><References Name="[sales].[orders].[day]" /></Entry></Relationship>
+      </Element></Entry></Relationship>
+      <Relationship Name="IncludedColumns"><Entry><References Name="[sales].[orders].[amount]" /></Entry></Relationship>
+      <Relationship Name="IndexedObject"><Entry><References Name="[sales].[orders]" /></Entry></Relationship>
+    </Element>
+  </Model>
+</DataSchemaModel>
+`
+
+// bcpWriter writes values in the native format of bcp.
+type bcpWriter struct {
+	bytes.Buffer
+}
+
+func (w *bcpWriter) prefixed(prefix int, b []byte) *bcpWriter {
+	switch prefix {
+	case 1:
+		w.WriteByte(byte(len(b)))
+	case 2:
+		binary.Write(w, binary.LittleEndian, uint16(len(b)))
+	case 8:
+		binary.Write(w, binary.LittleEndian, uint64(len(b)))
+	}
+	w.Write(b)
+	return w
+}
+
+func (w *bcpWriter) null(prefix int) *bcpWriter {
+	w.Write(bytes.Repeat([]byte{0xff}, prefix))
+	return w
+}
+
+func (w *bcpWriter) int32(v int32) *bcpWriter {
+	binary.Write(w, binary.LittleEndian, v)
+	return w
+}
+
+func (w *bcpWriter) int64(v int64) *bcpWriter {
+	binary.Write(w, binary.LittleEndian, v)
+	return w
+}
+
+func (w *bcpWriter) nvarchar(s string) *bcpWriter {
+	var b bytes.Buffer
+	binary.Write(&b, binary.LittleEndian, utf16.Encode([]rune(s)))
+	return w.prefixed(2, b.Bytes())
+}
+
+func (w *bcpWriter) float64(prefix int, f float64) *bcpWriter {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], math.Float64bits(f))
+	return w.prefixed(prefix, b[:])
+}
+
+func (w *bcpWriter) money(v int64) *bcpWriter {
+	binary.Write(w, binary.LittleEndian, int32(v>>32))
+	binary.Write(w, binary.LittleEndian, uint32(v))
+	return w
+}
+
+// decimal writes a decimal of scale 2 with magnitude v.
+func decimalBytes(v uint64, positive bool) []byte {
+	b := []byte{10, 2, 0}
+	if positive {
+		b[2] = 1
+	}
+	var mag [16]byte
+	binary.LittleEndian.PutUint64(mag[:], v)
+	return append(b, mag[:]...)
+}
+
+func dateBytes(t time.Time) []byte {
+	days := int((t.Unix() - dateEpoch.Unix()) / (24 * 60 * 60))
+	return []byte{byte(days), byte(days >> 8), byte(days >> 16)}
+}
+
+// datetime2Bytes encodes t as a datetime2(3).
+func datetime2Bytes(t time.Time) []byte {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	units := uint32(t.Sub(day) / time.Millisecond)
+	b := []byte{byte(units), byte(units >> 8), byte(units >> 16), byte(units >> 24)}
+	return append(b, dateBytes(day)...)
+}
+
+func writeTestBacpac(t *testing.T, files map[string][]byte) string {
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+	for name, data := range files {
+		f, err := z.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, z.Close())
+	path := filepath.Join(t.TempDir(), "test.bacpac")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+	return path
+}
+
+func testBacpacFiles() map[string][]byte {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 678000000, time.UTC)
+	customers1 := new(bcpWriter)
+	customers1.int32(1).nvarchar("Ann").prefixed(8, []byte("ann@example.com")).prefixed(1, []byte{1}).
+		prefixed(1, decimalBytes(1250, true)).prefixed(1, datetime2Bytes(created))
+	customers2 := new(bcpWriter)
+	customers2.int32(2).nvarchar("Bob").null(8).null(1).prefixed(1, decimalBytes(700, false)).null(1)
+	orders := new(bcpWriter)
+	orders.int32(1).int64(10).prefixed(1, dateBytes(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC))).float64(1, 1.5).money(123450).
+		prefixed(1, []byte{0x33, 0x22, 0x11, 0x00, 0x55, 0x44, 0x77, 0x66, 0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff})
+	orders.int32(2).int64(20).null(1).null(1).money(-5000).null(1)
+	return map[string][]byte{
+		"model.xml":  []byte(testBacpacModel),
+		"Origin.xml": []byte("<DacOrigin />"),
+		"Data/dbo.customers/TableData-000-00000.BCP":       customers1.Bytes(),
+		"Data/dbo.customers/TableData-001-00000.BCP":       customers2.Bytes(),
+		"Data/sales.orders/TableData-000-00000.BCP":        orders.Bytes(),
+		"Data/sales.dropped_table/TableData-000-00000.BCP": {},
+	}
+}
+
+func TestOpenBacpac(t *testing.T) {
+	b, err := OpenBacpac(writeTestBacpac(t, testBacpacFiles()))
+	require.NoError(t, err)
+	defer b.Close()
+	require.Equal(t, 2, len(b.Tables))
+	customers, orders := b.Tables[0], b.Tables[1]
+	assert.Equal(t, []bacpacColumn{
+		{Name: "id", TypeName: "int"},
+		{Name: "name", TypeName: "nvarchar", Length: 40},
+		{Name: "email", TypeName: "varchar", IsMax: true, Nullable: true, Collation: "SQL_Latin1_General_CP1_CI_AS"},
+		{Name: "vip", TypeName: "bit", Nullable: true, Default: true},
+		{Name: "balance", TypeName: "decimal", Precision: 10, Scale: 2, Nullable: true},
+		{Name: "created", TypeName: "datetime2", Scale: 3, Nullable: true},
+	}, customers.Columns)
+	assert.Equal(t, []string{"id"}, customers.PrimaryKeys)
+	assert.Equal(t, map[string][]string{"name": {"UNIQUE"}}, customers.Constraints)
+	assert.Equal(t, 2, len(customers.dataFiles))
+
+	assert.Equal(t, "sales", orders.Schema)
+	assert.Equal(t, 6, len(orders.Columns))
+	assert.Equal(t, []string{"customer_id", "order_id"}, orders.PrimaryKeys)
+	assert.Equal(t, []bacpacForeignKey{{Name: "FK_orders_customers", Columns: []string{"customer_id"}, ReferSchema: "dbo", ReferTable: "customers", ReferCols: []string{"id"}}}, orders.ForeignKeys)
+	assert.Equal(t, []bacpacIndex{{Name: "IX_orders_day", Keys: []string{"day"}, Desc: []bool{true}, Stored: []string{"amount"}}}, orders.Indexes)
+
+	_, err = OpenBacpac(writeTestBacpac(t, map[string][]byte{"Origin.xml": nil}))
+	assert.Error(t, err)
+	_, err = OpenBacpac(filepath.Join(t.TempDir(), "missing.bacpac"))
+	assert.Error(t, err)
+}
+
+func TestBacpacProcessSchemaAndData(t *testing.T) {
+	b, err := OpenBacpac(writeTestBacpac(t, testBacpacFiles()))
+	require.NoError(t, err)
+	defer b.Close()
+	isi := BacpacInfoSchemaImpl{Bacpac: b}
+
+	conv := internal.MakeConv()
+	mockAccessor := new(mocks.MockExpressionVerificationAccessor)
+	mockAccessor.On("VerifyExpressions", context.Background(), mock.Anything).Return(internal.VerifyExpressionsOutput{})
+	schemaToSpanner := common.SchemaToSpannerImpl{
+		ExpressionVerificationAccessor: mockAccessor,
+		DdlV:                           &expressions_api.MockDDLVerifier{},
+	}
+	processSchema := common.ProcessSchemaImpl{}
+	err = processSchema.ProcessSchema(conv, isi, 1, internal.AdditionalSchemaAttributes{}, &schemaToSpanner, &common.UtilsOrderImpl{}, &common.InfoSchemaImpl{})
+	require.NoError(t, err)
+
+	tableId, err := internal.GetTableIdFromSrcName(conv.SrcSchema, "sales.orders")
+	require.NoError(t, err)
+	orders := conv.SrcSchema[tableId]
+	assert.Equal(t, "sales", orders.Schema)
+	assert.Equal(t, 1, len(orders.ForeignKeys))
+	customersId, err := internal.GetTableIdFromSrcName(conv.SrcSchema, "customers")
+	require.NoError(t, err)
+	assert.Equal(t, customersId, orders.ForeignKeys[0].ReferTableId)
+	assert.Equal(t, 1, len(orders.Indexes))
+	colId, err := internal.GetColIdFromSrcName(orders.ColDefs, "paid")
+	require.NoError(t, err)
+	assert.Equal(t, schema.Type{Name: "money"}, orders.ColDefs[colId].Type)
+	colId, err = internal.GetColIdFromSrcName(conv.SrcSchema[customersId].ColDefs, "email")
+	require.NoError(t, err)
+	assert.Equal(t, schema.Type{Name: "varchar", Mods: []int64{-1}}, conv.SrcSchema[customersId].ColDefs[colId].Type)
+
+	commonInfoSchema := common.InfoSchemaImpl{}
+	commonInfoSchema.SetRowStats(conv, isi)
+	assert.Equal(t, int64(0), conv.Rows())
+
+	conv.SetDataMode()
+	type row struct {
+		table string
+		cols  []string
+		vals  []interface{}
+	}
+	var rows []row
+	conv.SetDataSink(func(table string, cols []string, vals []interface{}) {
+		rows = append(rows, row{table, cols, vals})
+	})
+	commonInfoSchema.ProcessData(conv, isi, internal.AdditionalDataAttributes{})
+	assert.ElementsMatch(t, []row{
+		{"customers", []string{"id", "name", "email", "vip", "balance", "created"},
+			[]interface{}{int64(1), "Ann", "ann@example.com", true, big.NewRat(25, 2), time.Date(2024, 1, 2, 3, 4, 5, 678000000, time.UTC)}},
+		{"customers", []string{"id", "name", "balance"},
+			[]interface{}{int64(2), "Bob", big.NewRat(-7, 1)}},
+		{"sales_orders", []string{"customer_id", "order_id", "day", "amount", "paid", "ref"},
+			[]interface{}{int64(1), int64(10), civil.Date{Year: 2024, Month: 3, Day: 4}, 1.5, big.NewRat(12345, 1000), "00112233-4455-6677-8899-AABBCCDDEEFF"}},
+		{"sales_orders", []string{"customer_id", "order_id", "paid"},
+			[]interface{}{int64(2), int64(20), big.NewRat(-1, 2)}},
+	}, rows)
+	// Rows are counted as they are read.
+	assert.Equal(t, int64(2), conv.Stats.Rows["customers"])
+	assert.Equal(t, int64(2), conv.Stats.Rows["sales.orders"])
+	assert.Equal(t, int64(0), conv.BadRows())
+	assert.Equal(t, int64(0), conv.Unexpecteds(), conv.Stats.Unexpected)
+}
+
+func TestBcpReader(t *testing.T) {
+	cols := []bacpacColumn{
+		{Name: "t", TypeName: "time", Scale: 7, Nullable: true},
+		{Name: "dto", TypeName: "datetimeoffset", Scale: 0, Nullable: true},
+		{Name: "dt", TypeName: "datetime"},
+		{Name: "sdt", TypeName: "smalldatetime", Nullable: true},
+		{Name: "r", TypeName: "float", Precision: 24},
+		{Name: "big", TypeName: "nvarchar", IsMax: true, Nullable: true},
+		{Name: "g", TypeName: "geography", Nullable: true},
+		{Name: "latin1", TypeName: "varchar", Length: 10, Collation: "SQL_Latin1_General_CP1_CI_AS"},
+		{Name: "cyrillic", TypeName: "char", Length: 3, Collation: "Cyrillic_General_CI_AS"},
+		{Name: "utf8", TypeName: "varchar", Length: 10, Collation: "Latin1_General_100_CI_AS_SC_UTF8"},
+	}
+	w := new(bcpWriter)
+	// 01:02:03.1234567
+	units := uint64(37231234567)
+	w.prefixed(1, []byte{byte(units), byte(units >> 8), byte(units >> 16), byte(units >> 24), byte(units >> 32)})
+	// 2024-05-06 10:00:00 UTC, at +02:00.
+	dto := []byte{0xa0, 0x8c, 0x00}
+	dto = append(dto, dateBytes(time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC))...)
+	w.prefixed(1, append(dto, 120, 0))
+	// 1900-01-03 00:00:01.5: two days and 450 ticks of 1/300 second.
+	w.int32(2).int32(450)
+	w.prefixed(1, []byte{1, 0, 61, 0})
+	w.int32(int32(math.Float32bits(0.25)))
+	// A value of unknown length, in two chunks.
+	w.Write([]byte{0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	w.int32(2).Write([]byte{'h', 0})
+	w.int32(2).Write([]byte{'i', 0})
+	w.int32(0)
+	w.null(8)
+	// "Café" and "Мир" in Windows-1252 and Windows-1251.
+	w.prefixed(2, []byte{'C', 'a', 'f', 0xe9})
+	w.prefixed(2, []byte{0xcc, 0xe8, 0xf0})
+	w.prefixed(2, []byte("Café"))
+
+	br := newBcpReader(bytes.NewReader(w.Bytes()), cols)
+	vals, err := br.Next()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"01:02:03.1234567", "2024-05-06T12:00:00+02:00", "1900-01-03T00:00:01.5", "1900-01-02T01:01:00", "0.25", "hi", "NULL", "Café", "Мир", "Café"}, vals)
+	_, err = br.Next()
+	assert.Equal(t, io.EOF, err)
+
+	// A value that can't be decoded doesn't prevent reading the next rows.
+	w.Reset()
+	w.prefixed(8, []byte{1, 2, 3})
+	w.null(8)
+	br = newBcpReader(bytes.NewReader(w.Bytes()), []bacpacColumn{{Name: "g", TypeName: "geography", Nullable: true}})
+	_, err = br.Next()
+	assert.IsType(t, &bcpValueError{}, err)
+	vals, err = br.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"NULL"}, vals)
+
+	// Values of collations whose code page is unknown must be UTF-8.
+	w.Reset()
+	w.prefixed(2, []byte{'C', 'a', 'f', 0xe9})
+	w.prefixed(2, []byte("Café"))
+	br = newBcpReader(bytes.NewReader(w.Bytes()), []bacpacColumn{{Name: "v", TypeName: "varchar", Length: 10, Collation: "Klingon_CI_AS"}})
+	_, err = br.Next()
+	assert.IsType(t, &bcpValueError{}, err)
+	assert.ErrorContains(t, err, "isn't valid UTF-8")
+	vals, err = br.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Café"}, vals)
+
+	// Truncated rows.
+	br = newBcpReader(bytes.NewReader([]byte{1, 0, 0, 0, 2}), []bacpacColumn{{Name: "a", TypeName: "int"}, {Name: "b", TypeName: "int"}})
+	_, err = br.Next()
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+}
+
+func TestSplitModelName(t *testing.T) {
+	assert.Equal(t, []string{"dbo", "Orders"}, splitModelName("[dbo].[Orders]"))
+	assert.Equal(t, []string{"dbo", "a.b", "c]d"}, splitModelName("[dbo].[a.b].[c]]d]"))
+	assert.Equal(t, []string{"int"}, splitModelName("[int]"))
+}
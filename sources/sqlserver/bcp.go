@@ -0,0 +1,527 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+)
+
+// The data of a BACPAC is in the native format of bcp: the values of a
+// row follow each other, in the order of the columns of the table. A
+// value is preceded by its length, stored in 1, 2, 4 or 8 bytes depending
+// on its type, in which all bits set stands for NULL. Fixed-length values
+// of NOT NULL columns have no length.
+const bcpUnknownLength = math.MaxUint64 - 1
+
+var (
+	sqlServerEpoch = time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
+	dateEpoch      = time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// bcpFixedLengths holds the length of the values of fixed-length types.
+var bcpFixedLengths = map[string]int{
+	"bit":           1,
+	"tinyint":       1,
+	"smallint":      2,
+	"int":           4,
+	"bigint":        8,
+	"real":          4,
+	"float":         8,
+	"smallmoney":    4,
+	"money":         8,
+	"smalldatetime": 4,
+	dateTimeType:    8,
+	uuidType:        16,
+}
+
+// bcpFixedLength returns the length of the values of column c if its type
+// is a fixed-length type.
+func bcpFixedLength(c bacpacColumn) (int, bool) {
+	if c.TypeName == "float" && c.Precision > 0 && c.Precision <= 24 {
+		// float(1) to float(24) is stored as real.
+		return 4, true
+	}
+	l, ok := bcpFixedLengths[c.TypeName]
+	return l, ok
+}
+
+// bcpPrefixLength returns the number of bytes in which the length of the
+// values of column c is stored.
+func bcpPrefixLength(c bacpacColumn) int {
+	switch c.TypeName {
+	case "char", "varchar", "nchar", "nvarchar", "binary", "varbinary":
+		if c.IsMax {
+			return 8
+		}
+		return 2
+	case "text", "ntext", "image":
+		return 4
+	case "xml", "sql_variant", geometryType, geographyType, hierarchyIdType:
+		return 8
+	case "decimal", "numeric", dateType, timeType, dateTime2Type, dateTimeOffsetType:
+		return 1
+	default:
+		if c.Nullable {
+			return 1
+		}
+		return 0
+	}
+}
+
+// bcpReader reads the rows of a BCP data file.
+type bcpReader struct {
+	r    *bufio.Reader
+	cols []bacpacColumn
+	buf  []byte
+}
+
+func newBcpReader(r io.Reader, cols []bacpacColumn) *bcpReader {
+	return &bcpReader{r: bufio.NewReader(r), cols: cols}
+}
+
+// bcpValueError is returned for a row holding a value that was read but
+// can't be converted to a string. The next rows can still be read.
+type bcpValueError struct {
+	err error
+}
+
+func (e *bcpValueError) Error() string {
+	return e.err.Error()
+}
+
+// Next returns the values of the next row, as strings in the form the
+// queries of InfoSchemaImpl return them, with "NULL" for NULL values. It
+// returns io.EOF after the last row.
+func (br *bcpReader) Next() ([]string, error) {
+	vals := make([]string, len(br.cols))
+	var valueErr error
+	for i, c := range br.cols {
+		b, null, err := br.readValue(c)
+		if err == io.EOF && i > 0 {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+		if null {
+			vals[i] = "NULL"
+			continue
+		}
+		if vals[i], err = bcpValueToString(c, b); err != nil && valueErr == nil {
+			valueErr = &bcpValueError{fmt.Errorf("can't decode value of column %s: %v", c.Name, err)}
+		}
+	}
+	return vals, valueErr
+}
+
+// readValue reads the bytes of a value of column c.
+func (br *bcpReader) readValue(c bacpacColumn) ([]byte, bool, error) {
+	prefix := bcpPrefixLength(c)
+	var n uint64
+	if prefix == 0 {
+		l, ok := bcpFixedLength(c)
+		if !ok {
+			return nil, false, fmt.Errorf("unsupported type %s of column %s", c.TypeName, c.Name)
+		}
+		n = uint64(l)
+	} else {
+		b, err := br.read(prefix)
+		if err != nil {
+			return nil, false, err
+		}
+		var max uint64
+		switch prefix {
+		case 1:
+			n, max = uint64(b[0]), math.MaxUint8
+		case 2:
+			n, max = uint64(binary.LittleEndian.Uint16(b)), math.MaxUint16
+		case 4:
+			n, max = uint64(binary.LittleEndian.Uint32(b)), math.MaxUint32
+		case 8:
+			n, max = binary.LittleEndian.Uint64(b), math.MaxUint64
+		}
+		if n == max {
+			return nil, true, nil
+		}
+		if prefix == 8 && n == bcpUnknownLength {
+			b, err := br.readChunks()
+			return b, false, err
+		}
+	}
+	b, err := br.read(int(n))
+	if err == io.EOF && prefix > 0 {
+		// Only the end of the data before a value is the end of a row.
+		err = io.ErrUnexpectedEOF
+	}
+	return append([]byte(nil), b...), false, err
+}
+
+// readChunks reads a value of unknown length, stored as chunks each
+// preceded by its 4-byte length, up to a chunk of length 0.
+func (br *bcpReader) readChunks() ([]byte, error) {
+	var v []byte
+	for {
+		b, err := br.read(4)
+		if err != nil {
+			return nil, io.ErrUnexpectedEOF
+		}
+		n := int(binary.LittleEndian.Uint32(b))
+		if n == 0 {
+			return v, nil
+		}
+		if b, err = br.read(n); err != nil {
+			return nil, io.ErrUnexpectedEOF
+		}
+		v = append(v, b...)
+	}
+}
+
+// read reads n bytes into a buffer reused by the next call.
+func (br *bcpReader) read(n int) ([]byte, error) {
+	if cap(br.buf) < n {
+		br.buf = make([]byte, n)
+	}
+	b := br.buf[:n]
+	if _, err := io.ReadFull(br.r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func bcpValueToString(c bacpacColumn, b []byte) (string, error) {
+	le := binary.LittleEndian
+	checkLen := func(n int) error {
+		if len(b) != n {
+			return fmt.Errorf("%s value has %d bytes, expected %d", c.TypeName, len(b), n)
+		}
+		return nil
+	}
+	switch c.TypeName {
+	case "bit":
+		if err := checkLen(1); err != nil {
+			return "", err
+		}
+		return strconv.FormatBool(b[0] != 0), nil
+	case "tinyint":
+		if err := checkLen(1); err != nil {
+			return "", err
+		}
+		return strconv.Itoa(int(b[0])), nil
+	case "smallint":
+		if err := checkLen(2); err != nil {
+			return "", err
+		}
+		return strconv.Itoa(int(int16(le.Uint16(b)))), nil
+	case "int":
+		if err := checkLen(4); err != nil {
+			return "", err
+		}
+		return strconv.Itoa(int(int32(le.Uint32(b)))), nil
+	case "bigint":
+		if err := checkLen(8); err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(int64(le.Uint64(b)), 10), nil
+	case "real":
+		if err := checkLen(4); err != nil {
+			return "", err
+		}
+		return strconv.FormatFloat(float64(math.Float32frombits(le.Uint32(b))), 'g', -1, 32), nil
+	case "float":
+		switch len(b) {
+		case 4:
+			return strconv.FormatFloat(float64(math.Float32frombits(le.Uint32(b))), 'g', -1, 32), nil
+		case 8:
+			return strconv.FormatFloat(math.Float64frombits(le.Uint64(b)), 'g', -1, 64), nil
+		}
+		return "", checkLen(8)
+	case "smallmoney":
+		if err := checkLen(4); err != nil {
+			return "", err
+		}
+		return formatScaled(big.NewInt(int64(int32(le.Uint32(b)))), 4), nil
+	case "money":
+		if err := checkLen(8); err != nil {
+			return "", err
+		}
+		v := int64(int32(le.Uint32(b)))<<32 | int64(le.Uint32(b[4:]))
+		return formatScaled(big.NewInt(v), 4), nil
+	case "decimal", "numeric":
+		// Precision, scale, sign (1 for positive) and the magnitude, least
+		// significant byte first.
+		if len(b) < 4 {
+			return "", fmt.Errorf("%s value has %d bytes", c.TypeName, len(b))
+		}
+		mag := make([]byte, len(b)-3)
+		for i, x := range b[3:] {
+			mag[len(mag)-1-i] = x
+		}
+		v := new(big.Int).SetBytes(mag)
+		if b[2] == 0 {
+			v.Neg(v)
+		}
+		return formatScaled(v, int(b[1])), nil
+	case "smalldatetime":
+		if err := checkLen(4); err != nil {
+			return "", err
+		}
+		t := sqlServerEpoch.AddDate(0, 0, int(le.Uint16(b))).Add(time.Duration(le.Uint16(b[2:])) * time.Minute)
+		return t.Format("2006-01-02T15:04:05"), nil
+	case dateTimeType:
+		if err := checkLen(8); err != nil {
+			return "", err
+		}
+		// Days since 1900-01-01 and ticks of 1/300 second since midnight.
+		ticks := int64(le.Uint32(b[4:]))
+		t := sqlServerEpoch.AddDate(0, 0, int(int32(le.Uint32(b)))).Add(time.Duration(ticks*10/3) * time.Millisecond)
+		return t.Format("2006-01-02T15:04:05.999"), nil
+	case dateType:
+		if err := checkLen(3); err != nil {
+			return "", err
+		}
+		return decodeDate(b).Format("2006-01-02"), nil
+	case timeType:
+		d, err := decodeTime(b, c.Scale)
+		if err != nil {
+			return "", err
+		}
+		return formatTime(time.Time{}.Add(d), c.Scale), nil
+	case dateTime2Type:
+		if len(b) < 3 {
+			return "", checkLen(8)
+		}
+		d, err := decodeTime(b[:len(b)-3], c.Scale)
+		if err != nil {
+			return "", err
+		}
+		t := decodeDate(b[len(b)-3:]).Add(d)
+		return t.Format("2006-01-02T") + formatTime(t, c.Scale), nil
+	case dateTimeOffsetType:
+		// The time and date are in UTC, followed by the offset in minutes.
+		if len(b) < 5 {
+			return "", checkLen(10)
+		}
+		d, err := decodeTime(b[:len(b)-5], c.Scale)
+		if err != nil {
+			return "", err
+		}
+		offset := int(int16(le.Uint16(b[len(b)-2:])))
+		t := decodeDate(b[len(b)-5 : len(b)-2]).Add(d).In(time.FixedZone("", offset*60))
+		return t.Format("2006-01-02T") + formatTime(t, c.Scale) + t.Format("Z07:00"), nil
+	case uuidType:
+		if err := checkLen(16); err != nil {
+			return "", err
+		}
+		// The first three groups are stored least significant byte first.
+		return fmt.Sprintf("%08X-%04X-%04X-%X-%X", le.Uint32(b), le.Uint16(b[4:]), le.Uint16(b[6:]), b[8:10], b[10:]), nil
+	case "nchar", "nvarchar", "ntext", "xml":
+		if len(b)%2 != 0 {
+			return "", fmt.Errorf("%s value has an odd number of bytes", c.TypeName)
+		}
+		u := make([]uint16, len(b)/2)
+		for i := range u {
+			u[i] = le.Uint16(b[2*i:])
+		}
+		return string(utf16.Decode(u)), nil
+	case "char", "varchar", "text":
+		return decodeChars(c.Collation, b)
+	case "binary", "varbinary", "image":
+		return string(b), nil
+	default:
+		return "", fmt.Errorf("values of type %s are not supported", c.TypeName)
+	}
+}
+
+// codePages holds the encodings of the code pages of collations.
+var codePages = map[int]encoding.Encoding{
+	437:  charmap.CodePage437,
+	850:  charmap.CodePage850,
+	874:  charmap.Windows874,
+	932:  japanese.ShiftJIS,
+	936:  simplifiedchinese.GBK,
+	949:  korean.EUCKR,
+	950:  traditionalchinese.Big5,
+	1250: charmap.Windows1250,
+	1251: charmap.Windows1251,
+	1252: charmap.Windows1252,
+	1253: charmap.Windows1253,
+	1254: charmap.Windows1254,
+	1255: charmap.Windows1255,
+	1256: charmap.Windows1256,
+	1257: charmap.Windows1257,
+	1258: charmap.Windows1258,
+}
+
+// windowsCollationCodePages maps the languages that start the names of
+// Windows collations, e.g. Latin1_General_CI_AS, to their code pages.
+var windowsCollationCodePages = map[string]int{
+	"Albanian":            1250,
+	"Arabic":              1256,
+	"Azeri_Cyrillic":      1251,
+	"Azeri_Latin":         1254,
+	"Bosnian_Cyrillic":    1251,
+	"Bosnian_Latin":       1250,
+	"Chinese_Hong_Kong":   950,
+	"Chinese_PRC":         936,
+	"Chinese_Simplified":  936,
+	"Chinese_Taiwan":      950,
+	"Chinese_Traditional": 950,
+	"Croatian":            1250,
+	"Cyrillic_General":    1251,
+	"Czech":               1250,
+	"Danish_Greenlandic":  1252,
+	"Danish_Norwegian":    1252,
+	"Estonian":            1257,
+	"Finnish_Swedish":     1252,
+	"French":              1252,
+	"German_PhoneBook":    1252,
+	"Greek":               1253,
+	"Hebrew":              1255,
+	"Hungarian":           1250,
+	"Icelandic":           1252,
+	"Japanese":            932,
+	"Kazakh":              1251,
+	"Korean":              949,
+	"Latin1_General":      1252,
+	"Latvian":             1257,
+	"Lithuanian":          1257,
+	"Macedonian_FYROM":    1251,
+	"Modern_Spanish":      1252,
+	"Persian":             1256,
+	"Polish":              1250,
+	"Romanian":            1250,
+	"Serbian_Cyrillic":    1251,
+	"Serbian_Latin":       1250,
+	"Slovak":              1250,
+	"Slovenian":           1250,
+	"Thai":                874,
+	"Traditional_Spanish": 1252,
+	"Turkish":             1254,
+	"Ukrainian":           1251,
+	"Urdu":                1256,
+	"Uzbek_Latin":         1254,
+	"Vietnamese":          1258,
+}
+
+// sqlCollationCodePage matches the code page in the names of SQL Server
+// collations, e.g. SQL_Latin1_General_CP1_CI_AS, where CP1 stands for
+// code page 1252.
+var sqlCollationCodePage = regexp.MustCompile(`(?i)_CP(\d+)_`)
+
+// collationEncoding returns the encoding of the char, varchar and text
+// values of collation, or nil if it is a UTF-8 collation or its code page
+// is unknown.
+func collationEncoding(collation string) encoding.Encoding {
+	if strings.HasPrefix(collation, "SQL_") {
+		m := sqlCollationCodePage.FindStringSubmatch(collation)
+		if m == nil {
+			return nil
+		}
+		if m[1] == "1" {
+			return codePages[1252]
+		}
+		codePage, _ := strconv.Atoi(m[1])
+		return codePages[codePage]
+	}
+	if strings.HasSuffix(strings.ToUpper(collation), "_UTF8") {
+		return nil
+	}
+	for language, codePage := range windowsCollationCodePages {
+		if strings.HasPrefix(collation, language+"_") {
+			return codePages[codePage]
+		}
+	}
+	return nil
+}
+
+// decodeChars decodes a char, varchar or text value, stored in the code
+// page of its collation. Values of UTF-8 collations, or of collations
+// whose code page is unknown, must be valid UTF-8.
+func decodeChars(collation string, b []byte) (string, error) {
+	if enc := collationEncoding(collation); enc != nil {
+		return enc.NewDecoder().String(string(b))
+	}
+	if !utf8.Valid(b) {
+		if strings.HasSuffix(strings.ToUpper(collation), "_UTF8") {
+			return "", fmt.Errorf("value isn't valid UTF-8")
+		}
+		return "", fmt.Errorf("value isn't valid UTF-8, and the code page of collation %q is unknown", collation)
+	}
+	return string(b), nil
+}
+
+// decodeDate decodes a date stored as 3 bytes counting the days since
+// 0001-01-01.
+func decodeDate(b []byte) time.Time {
+	days := int(b[0]) | int(b[1])<<8 | int(b[2])<<16
+	return dateEpoch.AddDate(0, 0, days)
+}
+
+// decodeTime decodes a time of day stored as the number of units of
+// 10^-scale seconds since midnight.
+func decodeTime(b []byte, scale int) (time.Duration, error) {
+	if len(b) < 3 || len(b) > 5 {
+		return 0, fmt.Errorf("time value has %d bytes", len(b))
+	}
+	var units int64
+	for i := len(b) - 1; i >= 0; i-- {
+		units = units<<8 | int64(b[i])
+	}
+	for i := scale; i < 9; i++ {
+		units *= 10
+	}
+	return time.Duration(units), nil
+}
+
+// formatTime formats the time of day of t with scale fractional digits.
+func formatTime(t time.Time, scale int) string {
+	if scale == 0 {
+		return t.Format("15:04:05")
+	}
+	return t.Format("15:04:05." + strings.Repeat("0", scale))
+}
+
+// formatScaled formats v / 10^scale as a decimal number.
+func formatScaled(v *big.Int, scale int) string {
+	s := new(big.Int).Abs(v).String()
+	if scale > 0 {
+		if len(s) <= scale {
+			s = strings.Repeat("0", scale-len(s)+1) + s
+		}
+		s = s[:len(s)-scale] + "." + s[len(s)-scale:]
+	}
+	if v.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
@@ -17,6 +17,7 @@ package sqlserver
 import (
 	"database/sql"
 	"fmt"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -26,6 +27,10 @@ import (
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
 )
 
+// createViewRegex matches the CREATE VIEW statement that precedes the query
+// in the definition of a view.
+var createViewRegex = regexp.MustCompile(`(?is)^.*?\bCREATE\s+(?:OR\s+ALTER\s+)?VIEW\b.*?\bAS\b\s*`)
+
 const (
 	uuidType           string = "uniqueidentifier"
 	geographyType      string = "geography"
@@ -73,11 +78,17 @@ func (isi InfoSchemaImpl) GetTableName(schema string, tableName string) string {
 // We choose to do all type conversions explicitly ourselves so that
 // we can generate more targeted error messages: hence we pass
 // *interface{} parameters to row.Scan.
+//
+// Only the rows in additionalAttributes.KeyRange are read when it is set.
+// ProcessData may be called concurrently (see common.KeyRangeInfoSchema),
+// so conv is only updated while holding conv.ConvLock.
 func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSchema schema.Table, commonColIds []string, spSchema ddl.CreateTable, additionalAttributes internal.AdditionalDataAttributes) error {
 	srcTableName := conv.SrcSchema[tableId].Name
-	rowsInterface, err := isi.GetRowsFromTable(conv, tableId)
+	rowsInterface, err := isi.getRowsFromTable(conv, tableId, additionalAttributes.KeyRange)
 	if err != nil {
+		conv.ConvLock.Lock()
 		conv.Unexpected(fmt.Sprintf("Couldn't get data for table %s : err = %s", srcTableName, err))
+		conv.ConvLock.Unlock()
 		return err
 	}
 	rows := rowsInterface.(*sql.Rows)
@@ -85,35 +96,63 @@ func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSc
 	srcCols, _ := rows.Columns()
 	v, scanArgs := buildVals(len(srcCols))
 	colNameIdMap := internal.GetSrcColNameIdMap(conv.SrcSchema[tableId])
+	var cvtSrcCols []string
+	for _, colId := range commonColIds {
+		cvtSrcCols = append(cvtSrcCols, srcSchema.ColDefs[colId].Name)
+	}
+	// Rows are converted and written without holding conv.ConvLock, so
+	// that the key ranges of a table are processed in parallel.
 	for rows.Next() {
 		// get RawBytes from data.
 		err := rows.Scan(scanArgs...)
 		if err != nil {
+			conv.ConvLock.Lock()
 			conv.Unexpected(fmt.Sprintf("Couldn't process sql data row: %s", err))
 			// Scan failed, so we don't have any data to add to bad rows.
 			conv.StatsAddBadRow(srcTableName, conv.DataMode())
+			conv.ConvLock.Unlock()
 			continue
 		}
 		values := valsToStrings(v)
 		newValues, err := common.PrepareValues(conv, tableId, colNameIdMap, commonColIds, srcCols, values)
 		if err != nil {
-			conv.Unexpected(fmt.Sprintf("Error while converting data: %s\n", err))
-			conv.StatsAddBadRow(srcTableName, conv.DataMode())
-			conv.CollectBadRow(srcTableName, srcCols, values)
+			conv.BadRowConcurrently(srcTableName, srcCols, values, err)
 			continue
 		}
-		ProcessDataRow(conv, tableId, commonColIds, srcSchema, spSchema, newValues)
+		spTableName, cvtCols, cvtVals, err := ConvertData(conv, tableId, commonColIds, srcSchema, spSchema, newValues)
+		if err != nil {
+			conv.BadRowConcurrently(srcTableName, cvtSrcCols, newValues, err)
+			continue
+		}
+		conv.WriteRowConcurrently(srcTableName, spTableName, cvtCols, cvtVals)
 	}
 	return nil
 }
 
 // GetRowsFromTable returns a sql Rows object for a table.
 func (isi InfoSchemaImpl) GetRowsFromTable(conv *internal.Conv, tableId string) (interface{}, error) {
+	return isi.getRowsFromTable(conv, tableId, nil)
+}
+
+// getRowsFromTable returns a sql Rows object for the rows of a table in
+// keyRange, or for the whole table if keyRange is nil.
+func (isi InfoSchemaImpl) getRowsFromTable(conv *internal.Conv, tableId string, keyRange *internal.KeyRange) (interface{}, error) {
 	tbl := conv.SrcSchema[tableId]
 	//To get only the table name by removing the schema name prefix
 	tblName := strings.Replace(tbl.Name, tbl.Schema+".", "", 1)
 
 	q := getSelectQuery(isi.DbName, tbl.Schema, tblName, tbl.ColIds, tbl.ColDefs)
+	var keyCol string
+	if keyRange != nil {
+		keyCol = "[" + tbl.ColDefs[keyRange.ColId].Name + "]"
+	}
+	quoteCol := func(c string) string { return "[" + c + "]" }
+	quoteString := func(s string) string { return "N" + common.QuoteString(s) }
+	where, limit := common.ReadPredicate(conv, tableId, quoteCol, quoteString)
+	if limit > 0 {
+		q = fmt.Sprintf("SELECT TOP %d %s", limit, strings.TrimPrefix(q, "SELECT "))
+	}
+	q += common.SelectClause(where, keyRange, keyCol)
 	rows, err := isi.Db.Query(q)
 	if err != nil {
 		return nil, err
@@ -121,6 +160,20 @@ func (isi InfoSchemaImpl) GetRowsFromTable(conv *internal.Conv, tableId string)
 	return rows, err
 }
 
+// GetKeyBounds returns the smallest and largest values of column colId
+// of a table, so that the table can be read in key ranges.
+func (isi InfoSchemaImpl) GetKeyBounds(conv *internal.Conv, tableId string, colId string) (int64, int64, bool, error) {
+	tbl := conv.SrcSchema[tableId]
+	tblName := strings.Replace(tbl.Name, tbl.Schema+".", "", 1)
+	col := tbl.ColDefs[colId].Name
+	q := fmt.Sprintf("SELECT MIN([%s]), MAX([%s]) FROM [%s].[%s].[%s]", col, col, isi.DbName, tbl.Schema, tblName)
+	var min, max sql.NullInt64
+	if err := isi.Db.QueryRow(q).Scan(&min, &max); err != nil {
+		return 0, 0, false, err
+	}
+	return min.Int64, max.Int64, min.Valid && max.Valid, nil
+}
+
 func getSelectQuery(srcDb string, schemaName string, tableName string, colIds []string, colDefs map[string]schema.Column) string {
 	var selects = make([]string, len(colIds))
 
@@ -165,7 +218,13 @@ func buildVals(n int) (v []interface{}, iv []interface{}) {
 
 // GetRowCount with number of rows in each table.
 func (isi InfoSchemaImpl) GetRowCount(table common.SchemaAndName) (int64, error) {
-	q := fmt.Sprintf(`SELECT COUNT(1) FROM [%s].[%s].[%s];`, isi.DbName, table.Schema, table.Name)
+	return isi.GetFilteredRowCount(table, "")
+}
+
+// GetFilteredRowCount returns the number of rows of a table that satisfy
+// where, or of all its rows if where is "".
+func (isi InfoSchemaImpl) GetFilteredRowCount(table common.SchemaAndName, where string) (int64, error) {
+	q := fmt.Sprintf(`SELECT COUNT(1) FROM [%s].[%s].[%s]%s;`, isi.DbName, table.Schema, table.Name, common.SelectClause(where, nil, ""))
 	rows, err := isi.Db.Query(q)
 	if err != nil {
 		return 0, err
@@ -205,6 +264,47 @@ func (isi InfoSchemaImpl) GetTables() ([]common.SchemaAndName, error) {
 	return tables, nil
 }
 
+// GetViews returns the views of the database.
+func (isi InfoSchemaImpl) GetViews(conv *internal.Conv) ([]schema.View, error) {
+	q := `
+	SELECT
+		SCH.name AS view_schema,
+		VW.name AS view_name,
+		MOD.definition
+	FROM sys.views AS VW
+	INNER JOIN sys.schemas AS SCH
+	ON SCH.schema_id = VW.schema_id
+	INNER JOIN sys.sql_modules AS MOD
+	ON MOD.object_id = VW.object_id
+	WHERE VW.is_ms_shipped = 0
+	`
+	rows, err := isi.Db.Query(q)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get views: %w", err)
+	}
+	defer rows.Close()
+	var viewSchema, name string
+	var definition sql.NullString
+	var views []schema.View
+	for rows.Next() {
+		if err := rows.Scan(&viewSchema, &name, &definition); err != nil {
+			conv.Unexpected(fmt.Sprintf("Can't scan: %v", err))
+			continue
+		}
+		// The definition is null for encrypted views.
+		if !definition.Valid {
+			conv.Unexpected(fmt.Sprintf("Can't read the definition of view %s.%s", viewSchema, name))
+			continue
+		}
+		views = append(views, schema.View{
+			Name:       isi.GetTableName(viewSchema, name),
+			Schema:     viewSchema,
+			Definition: createViewRegex.ReplaceAllString(definition.String, ""),
+		})
+	}
+	return views, nil
+}
+
 // GetColumns returns a list of Column objects and names
 func (isi InfoSchemaImpl) GetColumns(conv *internal.Conv, table common.SchemaAndName, constraints map[string][]string, primaryKeys []string) (map[string]schema.Column, []string, error) {
 	q := `
@@ -25,6 +25,7 @@ import (
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/mocks"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
 	"github.com/stretchr/testify/assert"
@@ -403,3 +404,24 @@ func stripSchemaComments(spSchema map[string]ddl.CreateTable) map[string]ddl.Cre
 	}
 	return spSchema
 }
+
+func TestGetViews(t *testing.T) {
+	ms := []mockSpec{
+		{
+			query: "FROM sys.views",
+			cols:  []string{"view_schema", "view_name", "definition"},
+			rows: [][]driver.Value{
+				{"dbo", "user_ids", "-- Users.\nCREATE VIEW dbo.user_ids\nWITH SCHEMABINDING\nAS\nSELECT user_id FROM dbo.users"},
+				{"sales", "big_orders", "create or alter view [sales].[big_orders] as select id from sales.orders where total > 100"},
+			},
+		},
+	}
+	db := mkMockDB(t, ms)
+	isi := InfoSchemaImpl{"test", db}
+	views, err := isi.GetViews(internal.MakeConv())
+	assert.NoError(t, err)
+	assert.Equal(t, []schema.View{
+		{Name: "user_ids", Schema: "dbo", Definition: "SELECT user_id FROM dbo.users"},
+		{Name: "sales.big_orders", Schema: "sales", Definition: "select id from sales.orders where total > 100"},
+	}, views)
+}
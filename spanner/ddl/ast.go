@@ -55,6 +55,9 @@ const (
 	Numeric string = "NUMERIC"
 	// Json represent JSON type.
 	JSON string = "JSON"
+	// TokenList represent TOKENLIST type, the type of the columns indexed
+	// by search indexes.
+	TokenList string = "TOKENLIST"
 	// MaxLength is a sentinel for Type's Len field, representing the MAX value.
 	MaxLength = math.MaxInt64
 	// StringMaxLength represents maximum allowed STRING length.
@@ -79,6 +82,8 @@ const (
 	PGTimestamptz string = "TIMESTAMPTZ"
 	// Jsonb represents the PG.JSONB type
 	PGJSONB string = "JSONB"
+	// PGTokenList represents the spanner.tokenlist type, which is TOKENLIST type in PG.
+	PGTokenList string = "SPANNER.TOKENLIST"
 	// PGMaxLength represents sentinel for Type's Len field in PG.
 	PGMaxLength                          = 2621440
 	GeneratedColStored  GeneratedColType = "STORED"
@@ -129,6 +134,10 @@ type Type struct {
 	// IsArray represents if Type is an array_type or not
 	// When false, column has type T; when true, it is an array of type T.
 	IsArray bool
+	// VectorLength is the number of elements of the vectors stored in an
+	// ARRAY<FLOAT32> or ARRAY<FLOAT64> column. It is required for the columns
+	// of vector indexes. Zero if not set.
+	VectorLength int64
 }
 
 // PrintColumnDefType unparses the type encoded in a ColumnDef.
@@ -146,11 +155,19 @@ func (ty Type) PrintColumnDefType(isVirtual bool) string {
 	}
 	if ty.IsArray {
 		str = "ARRAY<" + str + ">"
+		if ty.VectorLength > 0 {
+			str += fmt.Sprintf("(vector_length=>%d)", ty.VectorLength)
+		}
 	}
 	return str
 }
 
 func GetPGType(spType Type) string {
+	if spType.Name == TokenList {
+		// TOKENLIST is not offered as a column type, so it is not part of
+		// STANDARD_TYPE_TO_PGSQL_TYPEMAP.
+		return PGTokenList
+	}
 	pgType, ok := STANDARD_TYPE_TO_PGSQL_TYPEMAP[spType.Name]
 	if ok {
 		return pgType
@@ -160,6 +177,10 @@ func GetPGType(spType Type) string {
 
 func (ty Type) PGPrintColumnDefType(isVirtual bool) string {
 	str := GetPGType(ty)
+	// Vectors are the only array types we generate for PG.
+	if ty.IsArray && ty.VectorLength > 0 {
+		return fmt.Sprintf("%s[] VECTOR LENGTH %d", str, ty.VectorLength)
+	}
 	// PG doesn't support array types, and we don't expect to receive a type
 	// with IsArray set to true. In the unlikely event, set to string type.
 	if ty.IsArray {
@@ -193,6 +214,7 @@ type ColumnDef struct {
 	AutoGen         AutoGenCol
 	DefaultValue    DefaultValue
 	GeneratedColumn GeneratedColumn
+	Hidden          bool // If true, the column is not returned by SELECT *.
 	Opts            map[string]string
 }
 
@@ -258,6 +280,9 @@ func (cd ColumnDef) PrintColumnDef(c Config) (string, string) {
 		s += cd.AutoGen.PrintAutoGenCol(c)
 		s += cd.GeneratedColumn.PrintGeneratedColumn(cd.T)
 	}
+	if cd.Hidden {
+		s += " HIDDEN"
+	}
 	var opts []string
 	if cd.Opts != nil {
 		if opt, ok := cd.Opts["cassandra_type"]; ok && opt != "" {
@@ -322,6 +347,41 @@ type InterleavedParent struct {
 	InterleaveType string
 }
 
+// RowDeletionPolicy encodes the following DDL definition:
+//
+//	row_deletion_policy: ROW DELETION POLICY ( OLDER_THAN ( timestamp_column, INTERVAL num_days DAY ) )
+//
+// which PG spells TTL INTERVAL 'num_days days' ON timestamp_column.
+type RowDeletionPolicy struct {
+	ColId   string // TIMESTAMP column of the table. Empty if the table has no policy.
+	NumDays int64
+}
+
+// PrintRowDeletionPolicy unparses the row deletion policy of table ct.
+func (rdp RowDeletionPolicy) PrintRowDeletionPolicy(ct CreateTable, c Config) string {
+	col := c.quote(ct.ColDefs[rdp.ColId].Name)
+	if c.SpDialect == constants.DIALECT_POSTGRESQL {
+		return fmt.Sprintf("TTL INTERVAL '%d days' ON %s", rdp.NumDays, col)
+	}
+	return fmt.Sprintf("ROW DELETION POLICY (OLDER_THAN(%s, INTERVAL %d DAY))", col, rdp.NumDays)
+}
+
+// Validate checks that the row deletion policy of table ct is on one of its
+// TIMESTAMP columns and keeps rows for a non-negative number of days.
+func (rdp RowDeletionPolicy) Validate(ct CreateTable) error {
+	col, found := ct.ColDefs[rdp.ColId]
+	if !found {
+		return fmt.Errorf("row deletion policy of table %s is on unknown column id %s", ct.Name, rdp.ColId)
+	}
+	if col.T.Name != Timestamp || col.T.IsArray {
+		return fmt.Errorf("row deletion policy of table %s must be on a TIMESTAMP column, but column %s is of type %s", ct.Name, col.Name, col.T.PrintColumnDefType(false))
+	}
+	if rdp.NumDays < 0 {
+		return fmt.Errorf("row deletion policy of table %s must have a non-negative number of days, got %d", ct.Name, rdp.NumDays)
+	}
+	return nil
+}
+
 // PrintForeignKey unparses the foreign keys.
 func (k Foreignkey) PrintForeignKey(c Config) string {
 	var cols, referCols []string
@@ -342,19 +402,22 @@ func (k Foreignkey) PrintForeignKey(c Config) string {
 
 // CreateTable encodes the following DDL definition:
 //
-//	create_table: CREATE TABLE table_name ([column_def, ...] ) primary_key [, cluster]
+//	create_table: CREATE TABLE table_name ([column_def, ...] ) primary_key [, cluster] [, row_deletion_policy]
 type CreateTable struct {
-	Name             string
-	ColIds           []string // Provides names and order of columns
-	ShardIdColumn    string
-	ColDefs          map[string]ColumnDef // Provides definition of columns (a map for simpler/faster lookup during type processing)
-	PrimaryKeys      []IndexKey
-	ForeignKeys      []Foreignkey
-	Indexes          []CreateIndex
-	ParentTable      InterleavedParent // if not empty, this table will be interleaved
-	CheckConstraints []CheckConstraint
-	Comment          string
-	Id               string
+	Name              string
+	ColIds            []string // Provides names and order of columns
+	ShardIdColumn     string
+	ColDefs           map[string]ColumnDef // Provides definition of columns (a map for simpler/faster lookup during type processing)
+	PrimaryKeys       []IndexKey
+	ForeignKeys       []Foreignkey
+	Indexes           []CreateIndex
+	SearchIndexes     []CreateSearchIndex
+	VectorIndexes     []CreateVectorIndex
+	ParentTable       InterleavedParent // if not empty, this table will be interleaved
+	CheckConstraints  []CheckConstraint
+	RowDeletionPolicy RowDeletionPolicy // if not empty, old rows of this table are deleted
+	Comment           string
+	Id                string
 }
 
 // PrintCreateTable unparses a CREATE TABLE statement.
@@ -420,6 +483,14 @@ func (ct CreateTable) PrintCreateTable(spSchema Schema, config Config) string {
 		}
 	}
 
+	if ct.RowDeletionPolicy.ColId != "" {
+		if config.SpDialect == constants.DIALECT_POSTGRESQL {
+			interleave += " " + ct.RowDeletionPolicy.PrintRowDeletionPolicy(ct, config)
+		} else {
+			interleave += ",\n" + ct.RowDeletionPolicy.PrintRowDeletionPolicy(ct, config)
+		}
+	}
+
 	var checkString string
 	if len(ct.CheckConstraints) > 0 {
 		checkString = FormatCheckConstraints(ct.CheckConstraints, config.SpDialect)
@@ -440,14 +511,14 @@ func (ct CreateTable) PrintCreateTable(spSchema Schema, config Config) string {
 //
 //	create index: CREATE [UNIQUE] [NULL_FILTERED] INDEX index_name ON table_name ( key_part [, ...] ) [ storing_clause ] [ , interleave_clause ]
 type CreateIndex struct {
-	Name            string
-	TableId         string `json:"TableId"`
-	Unique          bool
-	Keys            []IndexKey
-	Id              string
-	StoredColumnIds []string
-	// We have no requirements for null-filtered option and
-	// interleaving clauses yet, so we omit them for now.
+	Name              string
+	TableId           string `json:"TableId"`
+	Unique            bool
+	Keys              []IndexKey
+	Id                string
+	StoredColumnIds   []string
+	NullFiltered      bool   `json:",omitempty"` // if true, rows with a NULL key column are not indexed
+	InterleaveTableId string `json:",omitempty"` // if not empty, the index is interleaved in this ancestor table
 }
 
 // GeneratedColumn represents a Generated Column.
@@ -590,8 +661,10 @@ func (agc AutoGenCol) PrintIdentityCol(c Config) string {
 	return fmt.Sprintf(" GENERATED BY DEFAULT AS IDENTITY (%s)", strings.Join(options, " "))
 }
 
-// PrintCreateIndex unparses a CREATE INDEX statement.
-func (ci CreateIndex) PrintCreateIndex(ct CreateTable, c Config) string {
+// PrintCreateIndex unparses a CREATE INDEX statement. The PostgreSQL dialect
+// has no NULL_FILTERED option, so null-filtered indexes are printed with a
+// WHERE clause requiring each key column to be non-NULL instead.
+func (ci CreateIndex) PrintCreateIndex(spSchema Schema, ct CreateTable, c Config) string {
 	var keys []string
 
 	orderedKeys := []IndexKey{}
@@ -603,10 +676,27 @@ func (ci CreateIndex) PrintCreateIndex(ct CreateTable, c Config) string {
 	for _, p := range orderedKeys {
 		keys = append(keys, p.PrintPkOrIndexKey(ct, c))
 	}
-	var unique, stored, storingClause string
+	var unique, nullFiltered, stored, storingClause, interleave, where string
 	if ci.Unique {
 		unique = "UNIQUE "
 	}
+	if ci.NullFiltered && c.SpDialect != constants.DIALECT_POSTGRESQL {
+		nullFiltered = "NULL_FILTERED "
+	}
+	if parent, ok := spSchema[ci.InterleaveTableId]; ok {
+		if c.SpDialect == constants.DIALECT_POSTGRESQL {
+			interleave = " INTERLEAVE IN " + c.quote(parent.Name)
+		} else {
+			interleave = ", INTERLEAVE IN " + c.quote(parent.Name)
+		}
+	}
+	if ci.NullFiltered && c.SpDialect == constants.DIALECT_POSTGRESQL {
+		var conds []string
+		for _, k := range orderedKeys {
+			conds = append(conds, c.quote(ct.ColDefs[k.ColId].Name)+" IS NOT NULL")
+		}
+		where = " WHERE " + strings.Join(conds, " AND ")
+	}
 	if c.SpDialect == constants.DIALECT_POSTGRESQL {
 		stored = "INCLUDE"
 	} else {
@@ -621,7 +711,115 @@ func (ci CreateIndex) PrintCreateIndex(ct CreateTable, c Config) string {
 		}
 		storingClause = fmt.Sprintf(" %s (%s)", stored, strings.Join(storedColumns, ", "))
 	}
-	return fmt.Sprintf("CREATE %sINDEX %s ON %s (%s)%s", unique, c.quote(ci.Name), c.quote(ct.Name), strings.Join(keys, ", "), storingClause)
+	return fmt.Sprintf("CREATE %s%sINDEX %s ON %s (%s)%s%s%s", unique, nullFiltered, c.quote(ci.Name), c.quote(ct.Name), strings.Join(keys, ", "), storingClause, interleave, where)
+}
+
+// CanInterleaveIn returns true if the index can be interleaved in table
+// parentId, i.e. if parentId is an ancestor of the indexed table and the
+// index keys start with the primary key columns of parentId.
+func (ci CreateIndex) CanInterleaveIn(spSchema Schema, parentId string) bool {
+	ct, ok := spSchema[ci.TableId]
+	if !ok {
+		return false
+	}
+	parent, ok := spSchema[parentId]
+	if !ok {
+		return false
+	}
+	isAncestor := false
+	// Interleaving can't have cycles, but bound the walk in case the schema is invalid.
+	for id, n := ct.ParentTable.Id, 0; id != "" && n < len(spSchema); id, n = spSchema[id].ParentTable.Id, n+1 {
+		if id == parentId {
+			isAncestor = true
+			break
+		}
+	}
+	if !isAncestor {
+		return false
+	}
+	keys := append([]IndexKey{}, ci.Keys...)
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Order < keys[j].Order })
+	pks := append([]IndexKey{}, parent.PrimaryKeys...)
+	sort.Slice(pks, func(i, j int) bool { return pks[i].Order < pks[j].Order })
+	if len(keys) < len(pks) {
+		return false
+	}
+	for i, pk := range pks {
+		if ct.ColDefs[keys[i].ColId].Name != parent.ColDefs[pk.ColId].Name {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate returns an error if the index is interleaved in a table that it
+// can't be interleaved in.
+func (ci CreateIndex) Validate(spSchema Schema) error {
+	if ci.InterleaveTableId != "" && !ci.CanInterleaveIn(spSchema, ci.InterleaveTableId) {
+		return fmt.Errorf("index %s can't be interleaved in table id %s: the table must be an ancestor of the indexed table whose primary key prefixes the index keys", ci.Name, ci.InterleaveTableId)
+	}
+	return nil
+}
+
+// CreateSearchIndex encodes the following DDL definition:
+//
+//	create search index: CREATE SEARCH INDEX index_name ON table_name ( tokenlist_column [, ...] )
+type CreateSearchIndex struct {
+	Name    string
+	TableId string     `json:"TableId"`
+	Keys    []IndexKey // TOKENLIST columns of the table.
+	Id      string
+}
+
+// PrintCreateSearchIndex unparses a CREATE SEARCH INDEX statement.
+func (si CreateSearchIndex) PrintCreateSearchIndex(ct CreateTable, c Config) string {
+	var keys []string
+	for _, k := range si.Keys {
+		keys = append(keys, c.quote(ct.ColDefs[k.ColId].Name))
+	}
+	return fmt.Sprintf("CREATE SEARCH INDEX %s ON %s (%s)", c.quote(si.Name), c.quote(ct.Name), strings.Join(keys, ", "))
+}
+
+// Distance types of vector indexes.
+const (
+	DistanceCosine     string = "COSINE"
+	DistanceEuclidean  string = "EUCLIDEAN"
+	DistanceDotProduct string = "DOT_PRODUCT"
+)
+
+// CreateVectorIndex encodes the following DDL definition:
+//
+//	create vector index: CREATE VECTOR INDEX index_name ON table_name ( vector_column )
+//	                     [ WHERE vector_column IS NOT NULL ] OPTIONS ( distance_type = '...' )
+type CreateVectorIndex struct {
+	Name         string
+	TableId      string     `json:"TableId"`
+	Keys         []IndexKey // The vector column of the table.
+	DistanceType string     // One of DistanceCosine, DistanceEuclidean or DistanceDotProduct.
+	Id           string
+}
+
+// PrintCreateVectorIndex unparses a CREATE VECTOR INDEX statement. Spanner
+// only indexes non-NULL vectors, so nullable vector columns are filtered
+// with a WHERE clause. For PG, the equivalent CREATE INDEX ... USING ScaNN
+// statement is printed.
+func (vi CreateVectorIndex) PrintCreateVectorIndex(ct CreateTable, c Config) string {
+	var keys, notNull []string
+	for _, k := range vi.Keys {
+		col := ct.ColDefs[k.ColId]
+		keys = append(keys, c.quote(col.Name))
+		if !col.NotNull {
+			notNull = append(notNull, c.quote(col.Name)+" IS NOT NULL")
+		}
+	}
+	where := ""
+	if len(notNull) > 0 {
+		where = " WHERE " + strings.Join(notNull, " AND ")
+	}
+	if c.SpDialect == constants.DIALECT_POSTGRESQL {
+		return fmt.Sprintf("CREATE INDEX %s ON %s USING ScaNN (%s) WITH (distance_type = '%s')%s", c.quote(vi.Name), c.quote(ct.Name), strings.Join(keys, ", "), vi.DistanceType, where)
+	}
+	return fmt.Sprintf("CREATE VECTOR INDEX %s ON %s (%s)%s OPTIONS (distance_type = '%s')", c.quote(vi.Name), c.quote(ct.Name), strings.Join(keys, ", "), where, vi.DistanceType)
 }
 
 // Checks if the colId is part of the primary of a table
@@ -738,7 +936,7 @@ func GetSortedTableIdsBySpName(s Schema) []string {
 // Tables are printed in alphabetical order with one exception: interleaved
 // tables are potentially out of order since they must appear after the
 // definition of their parent table.
-func GetDDL(c Config, tableSchema Schema, sequenceSchema map[string]Sequence, dbOptions DatabaseOptions) []string {
+func GetDDL(c Config, tableSchema Schema, sequenceSchema map[string]Sequence, viewSchema map[string]CreateView, dbOptions DatabaseOptions) []string {
 	var ddl []string
 
 	if c.SpDialect == constants.DIALECT_POSTGRESQL {
@@ -767,7 +965,13 @@ func GetDDL(c Config, tableSchema Schema, sequenceSchema map[string]Sequence, db
 		for _, tableId := range tableIds {
 			ddl = append(ddl, tableSchema[tableId].PrintCreateTable(tableSchema, c))
 			for _, index := range tableSchema[tableId].Indexes {
-				ddl = append(ddl, index.PrintCreateIndex(tableSchema[tableId], c))
+				ddl = append(ddl, index.PrintCreateIndex(tableSchema, tableSchema[tableId], c))
+			}
+			for _, searchIndex := range tableSchema[tableId].SearchIndexes {
+				ddl = append(ddl, searchIndex.PrintCreateSearchIndex(tableSchema[tableId], c))
+			}
+			for _, vectorIndex := range tableSchema[tableId].VectorIndexes {
+				ddl = append(ddl, vectorIndex.PrintCreateVectorIndex(tableSchema[tableId], c))
 			}
 		}
 	}
@@ -784,6 +988,12 @@ func GetDDL(c Config, tableSchema Schema, sequenceSchema map[string]Sequence, db
 			}
 		}
 	}
+	// Views only depend on tables and other views, so they are printed last.
+	if c.Tables {
+		for _, viewId := range GetSortedViewIds(viewSchema) {
+			ddl = append(ddl, viewSchema[viewId].PrintCreateView(c))
+		}
+	}
 
 	return ddl
 }
@@ -808,6 +1018,58 @@ func maxStringLength(s []string) int {
 	return n
 }
 
+// CreateView encodes the following DDL definition:
+//
+//	CREATE VIEW view_name SQL SECURITY INVOKER AS query
+//
+// Query is in the dialect of the Spanner database. Spanner only supports
+// views with invoker's rights, so the security type is always printed.
+type CreateView struct {
+	Name  string
+	Id    string
+	Query string
+	// Ids of the views that Query reads from, which must be created first.
+	RefViewIds []string
+}
+
+// PrintCreateView unparses a CREATE VIEW statement.
+func (cv CreateView) PrintCreateView(c Config) string {
+	return fmt.Sprintf("CREATE VIEW %s SQL SECURITY INVOKER AS %s", c.quote(cv.Name), cv.Query)
+}
+
+// GetSortedViewIds orders views by name, except that views are placed after
+// the views they read from. References to views not in views are ignored.
+func GetSortedViewIds(views map[string]CreateView) []string {
+	var names []string
+	nameIdMap := map[string]string{}
+	for id, v := range views {
+		names = append(names, v.Name)
+		nameIdMap[v.Name] = id
+	}
+	sort.Strings(names)
+	var sortedIds []string
+	added := map[string]bool{}
+	visiting := map[string]bool{}
+	var add func(id string)
+	add = func(id string) {
+		if added[id] || visiting[id] {
+			return
+		}
+		visiting[id] = true
+		for _, refId := range views[id].RefViewIds {
+			if _, ok := views[refId]; ok {
+				add(refId)
+			}
+		}
+		added[id] = true
+		sortedIds = append(sortedIds, id)
+	}
+	for _, name := range names {
+		add(nameIdMap[name])
+	}
+	return sortedIds
+}
+
 type Sequence struct {
 	Id               string
 	Name             string
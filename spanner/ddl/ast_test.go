@@ -96,6 +96,7 @@ func TestPrintColumnDef(t *testing.T) {
 	}{
 		{in: ColumnDef{Name: "col1", T: Type{Name: Int64}}, expected: "col1 INT64"},
 		{in: ColumnDef{Name: "col1", T: Type{Name: Int64, IsArray: true}}, expected: "col1 ARRAY<INT64>"},
+		{in: ColumnDef{Name: "col1", T: Type{Name: Float32, IsArray: true, VectorLength: 3}}, expected: "col1 ARRAY<FLOAT32>(vector_length=>3)"},
 		{in: ColumnDef{Name: "col1", T: Type{Name: Int64}, NotNull: true}, expected: "col1 INT64 NOT NULL "},
 		{in: ColumnDef{Name: "col1", T: Type{Name: Int64, IsArray: true}, NotNull: true}, expected: "col1 ARRAY<INT64> NOT NULL "},
 		{in: ColumnDef{Name: "col1", T: Type{Name: Int64}}, protectIds: true, expected: "`col1` INT64"},
@@ -142,6 +143,19 @@ func TestPrintColumnDef(t *testing.T) {
 			},
 			expected: "col1 FLOAT32 AS (CAST(col2 + 1 AS FLOAT32))",
 		},
+		{
+			in: ColumnDef{
+				Name: "col1_Tokens",
+				T:    Type{Name: TokenList},
+				GeneratedColumn: GeneratedColumn{
+					IsPresent: true,
+					Value:     Expression{Statement: "TOKENIZE_FULLTEXT(col1)"},
+					Type:      GeneratedColVirtual,
+				},
+				Hidden: true,
+			},
+			expected: "col1_Tokens TOKENLIST AS (TOKENIZE_FULLTEXT(col1)) HIDDEN",
+		},
 	}
 	for _, tc := range tests {
 		s, _ := tc.in.PrintColumnDef(Config{ProtectIds: tc.protectIds})
@@ -157,6 +171,7 @@ func TestPrintColumnDefPG(t *testing.T) {
 	}{
 		{in: ColumnDef{Name: "col1", T: Type{Name: Int64}}, expected: "col1 INT8"},
 		{in: ColumnDef{Name: "col1", T: Type{Name: Int64, IsArray: true}}, expected: "col1 VARCHAR(2621440)"},
+		{in: ColumnDef{Name: "col1", T: Type{Name: Float32, IsArray: true, VectorLength: 3}}, expected: "col1 FLOAT4[] VECTOR LENGTH 3"},
 		{in: ColumnDef{Name: "col1", T: Type{Name: Int64}, NotNull: true}, expected: "col1 INT8 NOT NULL "},
 		{in: ColumnDef{Name: "col1", T: Type{Name: Int64, IsArray: true}, NotNull: true}, expected: "col1 VARCHAR(2621440) NOT NULL "},
 		{in: ColumnDef{Name: "col1", T: Type{Name: Int64}}, protectIds: true, expected: "\"col1\" INT8"},
@@ -195,6 +210,19 @@ func TestPrintColumnDefPG(t *testing.T) {
 			},
 			expected: "col1 FLOAT4 GENERATED ALWAYS AS (CAST(col2 + 1 AS FLOAT4)) VIRTUAL",
 		},
+		{
+			in: ColumnDef{
+				Name: "col1_tokens",
+				T:    Type{Name: TokenList},
+				GeneratedColumn: GeneratedColumn{
+					IsPresent: true,
+					Value:     Expression{Statement: "spanner.tokenize_fulltext(col1)"},
+					Type:      GeneratedColVirtual,
+				},
+				Hidden: true,
+			},
+			expected: "col1_tokens SPANNER.TOKENLIST GENERATED ALWAYS AS (spanner.tokenize_fulltext(col1)) VIRTUAL HIDDEN",
+		},
 	}
 	for _, tc := range tests {
 		s, _ := tc.in.PrintColumnDef(Config{ProtectIds: tc.protectIds, SpDialect: constants.DIALECT_POSTGRESQL})
@@ -575,6 +603,87 @@ func TestPrintCreateTablePG(t *testing.T) {
 	}
 }
 
+func TestPrintCreateTableWithRowDeletionPolicy(t *testing.T) {
+	s := Schema{
+		"t1": CreateTable{
+			Name:   "events",
+			ColIds: []string{"c1", "c2"},
+			ColDefs: map[string]ColumnDef{
+				"c1": {Name: "id", T: Type{Name: Int64}, NotNull: true},
+				"c2": {Name: "created_at", T: Type{Name: Timestamp}},
+			},
+			PrimaryKeys:       []IndexKey{{ColId: "c1", Order: 1}},
+			RowDeletionPolicy: RowDeletionPolicy{ColId: "c2", NumDays: 30},
+			Id:                "t1",
+		},
+		"t2": CreateTable{
+			Name:   "event_details",
+			ColIds: []string{"c3", "c4"},
+			ColDefs: map[string]ColumnDef{
+				"c3": {Name: "id", T: Type{Name: Int64}, NotNull: true},
+				"c4": {Name: "created_at", T: Type{Name: Timestamp}},
+			},
+			PrimaryKeys:       []IndexKey{{ColId: "c3", Order: 1}},
+			ParentTable:       InterleavedParent{Id: "t1", OnDelete: constants.FK_CASCADE, InterleaveType: "IN PARENT"},
+			RowDeletionPolicy: RowDeletionPolicy{ColId: "c4", NumDays: 0},
+			Id:                "t2",
+		},
+	}
+	tests := []struct {
+		name      string
+		spDialect string
+		tableId   string
+		expected  string
+	}{
+		{"row deletion policy", "", "t1",
+			"CREATE TABLE `events` (\n" +
+				"\t`id` INT64 NOT NULL ,\n" +
+				"\t`created_at` TIMESTAMP,\n" +
+				") PRIMARY KEY (`id`),\n" +
+				"ROW DELETION POLICY (OLDER_THAN(`created_at`, INTERVAL 30 DAY))"},
+		{"interleaved with row deletion policy", "", "t2",
+			"CREATE TABLE `event_details` (\n" +
+				"\t`id` INT64 NOT NULL ,\n" +
+				"\t`created_at` TIMESTAMP,\n" +
+				") PRIMARY KEY (`id`),\n" +
+				"INTERLEAVE IN PARENT `events` ON DELETE CASCADE,\n" +
+				"ROW DELETION POLICY (OLDER_THAN(`created_at`, INTERVAL 0 DAY))"},
+		{"row deletion policy PG", constants.DIALECT_POSTGRESQL, "t1",
+			"CREATE TABLE \"events\" (\n" +
+				"\t\"id\" INT8 NOT NULL ,\n" +
+				"\t\"created_at\" TIMESTAMPTZ,\n" +
+				"\tPRIMARY KEY (\"id\")\n" +
+				") TTL INTERVAL '30 days' ON \"created_at\""},
+		{"interleaved with row deletion policy PG", constants.DIALECT_POSTGRESQL, "t2",
+			"CREATE TABLE \"event_details\" (\n" +
+				"\t\"id\" INT8 NOT NULL ,\n" +
+				"\t\"created_at\" TIMESTAMPTZ,\n" +
+				"\tPRIMARY KEY (\"id\")\n" +
+				") INTERLEAVE IN PARENT \"events\" ON DELETE CASCADE TTL INTERVAL '0 days' ON \"created_at\""},
+	}
+	for _, tc := range tests {
+		c := Config{ProtectIds: true, SpDialect: tc.spDialect}
+		assert.Equal(t, tc.expected, s[tc.tableId].PrintCreateTable(s, c), tc.name)
+	}
+}
+
+func TestRowDeletionPolicyValidate(t *testing.T) {
+	ct := CreateTable{
+		Name:   "events",
+		ColIds: []string{"c1", "c2", "c3"},
+		ColDefs: map[string]ColumnDef{
+			"c1": {Name: "id", T: Type{Name: Int64}},
+			"c2": {Name: "created_at", T: Type{Name: Timestamp}},
+			"c3": {Name: "updates", T: Type{Name: Timestamp, IsArray: true}},
+		},
+	}
+	assert.NoError(t, RowDeletionPolicy{ColId: "c2", NumDays: 30}.Validate(ct))
+	assert.EqualError(t, RowDeletionPolicy{ColId: "c1", NumDays: 30}.Validate(ct), "row deletion policy of table events must be on a TIMESTAMP column, but column id is of type INT64")
+	assert.EqualError(t, RowDeletionPolicy{ColId: "c3", NumDays: 30}.Validate(ct), "row deletion policy of table events must be on a TIMESTAMP column, but column updates is of type ARRAY<TIMESTAMP>")
+	assert.EqualError(t, RowDeletionPolicy{ColId: "c4", NumDays: 30}.Validate(ct), "row deletion policy of table events is on unknown column id c4")
+	assert.EqualError(t, RowDeletionPolicy{ColId: "c2", NumDays: -1}.Validate(ct), "row deletion policy of table events must have a non-negative number of days, got -1")
+}
+
 func TestPrintCreateIndex(t *testing.T) {
 	ct := CreateTable{
 		Name:   "mytable",
@@ -584,6 +693,17 @@ func TestPrintCreateIndex(t *testing.T) {
 			"c1": {Name: "col1", Id: "c1"},
 			"c2": {Name: "col2", Id: "c2"},
 		},
+		ParentTable: InterleavedParent{Id: "t2", InterleaveType: "IN PARENT", OnDelete: constants.FK_CASCADE},
+	}
+	s := Schema{
+		"t1": ct,
+		"t2": {
+			Name:        "parent",
+			Id:          "t2",
+			ColIds:      []string{"c3"},
+			ColDefs:     map[string]ColumnDef{"c3": {Name: "col1", Id: "c3"}},
+			PrimaryKeys: []IndexKey{{ColId: "c3", Order: 1}},
+		},
 	}
 	ci := []CreateIndex{
 		{
@@ -593,6 +713,8 @@ func TestPrintCreateIndex(t *testing.T) {
 			[]IndexKey{{ColId: "c1", Desc: true}, {ColId: "c2"}},
 			"i1",
 			nil,
+			/*NullFiltered =*/ false,
+			"",
 		},
 		{
 			"myindex2",
@@ -601,6 +723,18 @@ func TestPrintCreateIndex(t *testing.T) {
 			[]IndexKey{{ColId: "c1", Desc: true}, {ColId: "c2"}},
 			"i2",
 			nil,
+			/*NullFiltered =*/ false,
+			"",
+		},
+		{
+			"myindex3",
+			"t1",
+			/*Unique =*/ true,
+			[]IndexKey{{ColId: "c1", Order: 1}, {ColId: "c2", Order: 2}},
+			"i3",
+			[]string{"c2"},
+			/*NullFiltered =*/ true,
+			"t2",
 		},
 	}
 	tests := []struct {
@@ -613,11 +747,112 @@ func TestPrintCreateIndex(t *testing.T) {
 		{"no quote non unique", false, "", ci[0], "CREATE INDEX myindex ON mytable (col1 DESC, col2)"},
 		{"quote non unique", true, "", ci[0], "CREATE INDEX `myindex` ON `mytable` (`col1` DESC, `col2`)"},
 		{"unique key", true, "", ci[1], "CREATE UNIQUE INDEX `myindex2` ON `mytable` (`col1` DESC, `col2`)"},
+		{"null filtered interleaved", false, "", ci[2], "CREATE UNIQUE NULL_FILTERED INDEX myindex3 ON mytable (col1, col2) STORING (col2), INTERLEAVE IN parent"},
 		{"quote non unique PG", true, constants.DIALECT_POSTGRESQL, ci[0], "CREATE INDEX \"myindex\" ON \"mytable\" (\"col1\" DESC, \"col2\")"},
 		{"unique key PG", true, constants.DIALECT_POSTGRESQL, ci[1], "CREATE UNIQUE INDEX \"myindex2\" ON \"mytable\" (\"col1\" DESC, \"col2\")"},
+		{"null filtered interleaved PG", false, constants.DIALECT_POSTGRESQL, ci[2], "CREATE UNIQUE INDEX myindex3 ON mytable (col1, col2) INCLUDE (col2) INTERLEAVE IN parent WHERE col1 IS NOT NULL AND col2 IS NOT NULL"},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, tc.index.PrintCreateIndex(s, ct, Config{ProtectIds: tc.protectIds, SpDialect: tc.spDialect}), tc.name)
+	}
+}
+
+func TestCanInterleaveIndex(t *testing.T) {
+	s := Schema{
+		"t1": {
+			Name:        "singers",
+			Id:          "t1",
+			ColIds:      []string{"c1"},
+			ColDefs:     map[string]ColumnDef{"c1": {Name: "singer_id", Id: "c1"}},
+			PrimaryKeys: []IndexKey{{ColId: "c1", Order: 1}},
+		},
+		"t2": {
+			Name:        "albums",
+			Id:          "t2",
+			ColIds:      []string{"c2", "c3"},
+			ColDefs:     map[string]ColumnDef{"c2": {Name: "singer_id", Id: "c2"}, "c3": {Name: "album_id", Id: "c3"}},
+			PrimaryKeys: []IndexKey{{ColId: "c2", Order: 1}, {ColId: "c3", Order: 2}},
+			ParentTable: InterleavedParent{Id: "t1", InterleaveType: "IN PARENT", OnDelete: constants.FK_CASCADE},
+		},
+		"t3": {
+			Name:        "songs",
+			Id:          "t3",
+			ColIds:      []string{"c4", "c5", "c6", "c7"},
+			ColDefs:     map[string]ColumnDef{"c4": {Name: "singer_id", Id: "c4"}, "c5": {Name: "album_id", Id: "c5"}, "c6": {Name: "song_id", Id: "c6"}, "c7": {Name: "title", Id: "c7"}},
+			PrimaryKeys: []IndexKey{{ColId: "c4", Order: 1}, {ColId: "c5", Order: 2}, {ColId: "c6", Order: 3}},
+			ParentTable: InterleavedParent{Id: "t2", InterleaveType: "IN PARENT", OnDelete: constants.FK_CASCADE},
+		},
+	}
+	byAlbum := CreateIndex{Name: "songs_by_album", TableId: "t3", Keys: []IndexKey{{ColId: "c4", Order: 1}, {ColId: "c5", Order: 2}, {ColId: "c7", Order: 3}}}
+	byTitle := CreateIndex{Name: "songs_by_title", TableId: "t3", Keys: []IndexKey{{ColId: "c7", Order: 1}}}
+
+	assert.True(t, byAlbum.CanInterleaveIn(s, "t2"))
+	assert.True(t, byAlbum.CanInterleaveIn(s, "t1"))
+	assert.False(t, byAlbum.CanInterleaveIn(s, "t3"))
+	assert.False(t, byTitle.CanInterleaveIn(s, "t1"))
+	assert.False(t, byAlbum.CanInterleaveIn(s, "t4"))
+
+	byAlbum.InterleaveTableId = "t2"
+	assert.NoError(t, byAlbum.Validate(s))
+	byTitle.InterleaveTableId = "t2"
+	assert.EqualError(t, byTitle.Validate(s), "index songs_by_title can't be interleaved in table id t2: the table must be an ancestor of the indexed table whose primary key prefixes the index keys")
+}
+
+func TestPrintCreateSearchIndex(t *testing.T) {
+	ct := CreateTable{
+		Name:   "mytable",
+		Id:     "t1",
+		ColIds: []string{"c1", "c2", "c3"},
+		ColDefs: map[string]ColumnDef{
+			"c1": {Name: "col1", Id: "c1"},
+			"c2": {Name: "col1_Tokens", Id: "c2", T: Type{Name: TokenList}, Hidden: true},
+			"c3": {Name: "col2_Tokens", Id: "c3", T: Type{Name: TokenList}, Hidden: true},
+		},
+	}
+	si := CreateSearchIndex{Name: "mysearchindex", TableId: "t1", Keys: []IndexKey{{ColId: "c2"}, {ColId: "c3"}}, Id: "i1"}
+	tests := []struct {
+		name       string
+		protectIds bool
+		spDialect  string
+		expected   string
+	}{
+		{"no quote", false, "", "CREATE SEARCH INDEX mysearchindex ON mytable (col1_Tokens, col2_Tokens)"},
+		{"quote", true, "", "CREATE SEARCH INDEX `mysearchindex` ON `mytable` (`col1_Tokens`, `col2_Tokens`)"},
+		{"quote PG", true, constants.DIALECT_POSTGRESQL, "CREATE SEARCH INDEX \"mysearchindex\" ON \"mytable\" (\"col1_Tokens\", \"col2_Tokens\")"},
 	}
 	for _, tc := range tests {
-		assert.Equal(t, tc.expected, tc.index.PrintCreateIndex(ct, Config{ProtectIds: tc.protectIds, SpDialect: tc.spDialect}))
+		assert.Equal(t, tc.expected, si.PrintCreateSearchIndex(ct, Config{ProtectIds: tc.protectIds, SpDialect: tc.spDialect}), tc.name)
+	}
+}
+
+func TestPrintCreateVectorIndex(t *testing.T) {
+	ct := CreateTable{
+		Name:   "mytable",
+		Id:     "t1",
+		ColIds: []string{"c1", "c2"},
+		ColDefs: map[string]ColumnDef{
+			"c1": {Name: "col1", Id: "c1", T: Type{Name: Float32, IsArray: true, VectorLength: 3}, NotNull: true},
+			"c2": {Name: "col2", Id: "c2", T: Type{Name: Float32, IsArray: true, VectorLength: 3}},
+		},
+	}
+	vi := []CreateVectorIndex{
+		{Name: "myindex", TableId: "t1", Keys: []IndexKey{{ColId: "c1"}}, DistanceType: DistanceCosine, Id: "i1"},
+		{Name: "myindex2", TableId: "t1", Keys: []IndexKey{{ColId: "c2"}}, DistanceType: DistanceEuclidean, Id: "i2"},
+	}
+	tests := []struct {
+		name       string
+		protectIds bool
+		spDialect  string
+		index      CreateVectorIndex
+		expected   string
+	}{
+		{"not null", false, "", vi[0], "CREATE VECTOR INDEX myindex ON mytable (col1) OPTIONS (distance_type = 'COSINE')"},
+		{"nullable", true, "", vi[1], "CREATE VECTOR INDEX `myindex2` ON `mytable` (`col2`) WHERE `col2` IS NOT NULL OPTIONS (distance_type = 'EUCLIDEAN')"},
+		{"not null PG", false, constants.DIALECT_POSTGRESQL, vi[0], "CREATE INDEX myindex ON mytable USING ScaNN (col1) WITH (distance_type = 'COSINE')"},
+		{"nullable PG", true, constants.DIALECT_POSTGRESQL, vi[1], "CREATE INDEX \"myindex2\" ON \"mytable\" USING ScaNN (\"col2\") WITH (distance_type = 'EUCLIDEAN') WHERE \"col2\" IS NOT NULL"},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, tc.index.PrintCreateVectorIndex(ct, Config{ProtectIds: tc.protectIds, SpDialect: tc.spDialect}), tc.name)
 	}
 }
 
@@ -1168,7 +1403,7 @@ func TestGetDDL(t *testing.T) {
 			ParentTable: InterleavedParent{Id: "t1", OnDelete: constants.FK_NO_ACTION, InterleaveType: "IN"},
 		},
 	}
-	tablesOnly := GetDDL(Config{Tables: true, ForeignKeys: false}, s, make(map[string]Sequence), DatabaseOptions{})
+	tablesOnly := GetDDL(Config{Tables: true, ForeignKeys: false}, s, make(map[string]Sequence), nil, DatabaseOptions{})
 	e := []string{
 		"CREATE TABLE table1 (\n" +
 			"	a INT64,\n" +
@@ -1196,14 +1431,14 @@ func TestGetDDL(t *testing.T) {
 	}
 	assert.ElementsMatch(t, e, tablesOnly)
 
-	fksOnly := GetDDL(Config{Tables: false, ForeignKeys: true}, s, make(map[string]Sequence), DatabaseOptions{})
+	fksOnly := GetDDL(Config{Tables: false, ForeignKeys: true}, s, make(map[string]Sequence), nil, DatabaseOptions{})
 	e2 := []string{
 		"ALTER TABLE table1 ADD CONSTRAINT fk1 FOREIGN KEY (b) REFERENCES table2 (b) ON DELETE CASCADE",
 		"ALTER TABLE table2 ADD CONSTRAINT fk2 FOREIGN KEY (b, c) REFERENCES table3 (b, c) ON DELETE NO ACTION",
 	}
 	assert.ElementsMatch(t, e2, fksOnly)
 
-	tablesAndFks := GetDDL(Config{Tables: true, ForeignKeys: true}, s, make(map[string]Sequence), DatabaseOptions{})
+	tablesAndFks := GetDDL(Config{Tables: true, ForeignKeys: true}, s, make(map[string]Sequence), nil, DatabaseOptions{})
 	e3 := []string{
 		"CREATE TABLE table1 (\n" +
 			"	a INT64,\n" +
@@ -1245,7 +1480,7 @@ func TestGetDDL(t *testing.T) {
 	e4 := []string{
 		"CREATE SEQUENCE sequence1 OPTIONS (sequence_kind='bit_reversed_positive', skip_range_min = 0, skip_range_max = 5, start_with_counter = 7) ",
 	}
-	sequencesOnly := GetDDL(Config{}, Schema{}, sequences, DatabaseOptions{})
+	sequencesOnly := GetDDL(Config{}, Schema{}, sequences, nil, DatabaseOptions{})
 	assert.ElementsMatch(t, e4, sequencesOnly)
 
 	databaseOptions := DatabaseOptions{
@@ -1255,10 +1490,10 @@ func TestGetDDL(t *testing.T) {
 	e5 := []string{
 		"ALTER DATABASE `test-db` SET OPTIONS (default_time_zone = 'America/New_York')",
 	}
-	dbOptionsOnly := GetDDL(Config{}, Schema{}, make(map[string]Sequence), databaseOptions)
+	dbOptionsOnly := GetDDL(Config{}, Schema{}, make(map[string]Sequence), nil, databaseOptions)
 	assert.ElementsMatch(t, e5, dbOptionsOnly)
 
-	tablesWithTableIds := GetDDL(Config{Tables: true, ForeignKeys: false, TableIds: []string{"t1", "t3"}}, s, make(map[string]Sequence), DatabaseOptions{})
+	tablesWithTableIds := GetDDL(Config{Tables: true, ForeignKeys: false, TableIds: []string{"t1", "t3"}}, s, make(map[string]Sequence), nil, DatabaseOptions{})
 	e6 := []string{
 		"CREATE TABLE table1 (\n" +
 			"	a INT64,\n" +
@@ -1273,6 +1508,18 @@ func TestGetDDL(t *testing.T) {
 			"INTERLEAVE IN PARENT table1 ON DELETE CASCADE",
 	}
 	assert.ElementsMatch(t, e6, tablesWithTableIds)
+
+	views := map[string]CreateView{
+		"v1": {Name: "view1", Id: "v1", Query: "SELECT a FROM view2", RefViewIds: []string{"v2"}},
+		"v2": {Name: "view2", Id: "v2", Query: "SELECT a FROM table1"},
+	}
+	e7 := []string{
+		"CREATE VIEW `view2` SQL SECURITY INVOKER AS SELECT a FROM table1",
+		"CREATE VIEW `view1` SQL SECURITY INVOKER AS SELECT a FROM view2",
+	}
+	viewsOnly := GetDDL(Config{Tables: true, ProtectIds: true}, Schema{}, nil, views, DatabaseOptions{})
+	assert.Equal(t, e7, viewsOnly)
+	assert.Empty(t, GetDDL(Config{ForeignKeys: true}, Schema{}, nil, views, DatabaseOptions{}))
 }
 
 func TestGetPGDDL(t *testing.T) {
@@ -1327,7 +1574,7 @@ func TestGetPGDDL(t *testing.T) {
 			ParentTable: InterleavedParent{Id: "t1", OnDelete: constants.FK_NO_ACTION, InterleaveType: "IN"},
 		},
 	}
-	tablesOnly := GetDDL(Config{Tables: true, ForeignKeys: false, SpDialect: constants.DIALECT_POSTGRESQL}, s, make(map[string]Sequence), DatabaseOptions{})
+	tablesOnly := GetDDL(Config{Tables: true, ForeignKeys: false, SpDialect: constants.DIALECT_POSTGRESQL}, s, make(map[string]Sequence), nil, DatabaseOptions{})
 	e := []string{
 		"CREATE TABLE table1 (\n" +
 			"	a INT8,\n" +
@@ -1357,14 +1604,14 @@ func TestGetPGDDL(t *testing.T) {
 	}
 	assert.ElementsMatch(t, e, tablesOnly)
 
-	fksOnly := GetDDL(Config{Tables: false, ForeignKeys: true, SpDialect: constants.DIALECT_POSTGRESQL}, s, make(map[string]Sequence), DatabaseOptions{})
+	fksOnly := GetDDL(Config{Tables: false, ForeignKeys: true, SpDialect: constants.DIALECT_POSTGRESQL}, s, make(map[string]Sequence), nil, DatabaseOptions{})
 	e2 := []string{
 		"ALTER TABLE table1 ADD CONSTRAINT fk1 FOREIGN KEY (b) REFERENCES table2 (b) ON DELETE CASCADE",
 		"ALTER TABLE table2 ADD CONSTRAINT fk2 FOREIGN KEY (b, c) REFERENCES table3 (b, c) ON DELETE NO ACTION",
 	}
 	assert.ElementsMatch(t, e2, fksOnly)
 
-	tablesAndFks := GetDDL(Config{Tables: true, ForeignKeys: true, SpDialect: constants.DIALECT_POSTGRESQL}, s, make(map[string]Sequence), DatabaseOptions{})
+	tablesAndFks := GetDDL(Config{Tables: true, ForeignKeys: true, SpDialect: constants.DIALECT_POSTGRESQL}, s, make(map[string]Sequence), nil, DatabaseOptions{})
 	e3 := []string{
 		"CREATE TABLE table1 (\n" +
 			"	a INT8,\n" +
@@ -1408,7 +1655,7 @@ func TestGetPGDDL(t *testing.T) {
 	e4 := []string{
 		"CREATE SEQUENCE sequence1 BIT_REVERSED_POSITIVE SKIP RANGE 0 5 START COUNTER WITH 7",
 	}
-	sequencesOnly := GetDDL(Config{SpDialect: constants.DIALECT_POSTGRESQL}, Schema{}, sequences, DatabaseOptions{})
+	sequencesOnly := GetDDL(Config{SpDialect: constants.DIALECT_POSTGRESQL}, Schema{}, sequences, nil, DatabaseOptions{})
 	assert.ElementsMatch(t, e4, sequencesOnly)
 
 	databaseOptions := DatabaseOptions{
@@ -1418,10 +1665,10 @@ func TestGetPGDDL(t *testing.T) {
 	e5 := []string{
 		"ALTER DATABASE \"test-db\" SET spanner.default_time_zone = 'America/New_York'",
 	}
-	dbOptionsOnly := GetDDL(Config{SpDialect: constants.DIALECT_POSTGRESQL}, Schema{}, make(map[string]Sequence), databaseOptions)
+	dbOptionsOnly := GetDDL(Config{SpDialect: constants.DIALECT_POSTGRESQL}, Schema{}, make(map[string]Sequence), nil, databaseOptions)
 	assert.ElementsMatch(t, e5, dbOptionsOnly)
 
-	tablesWithTableIds := GetDDL(Config{Tables: true, ForeignKeys: false, TableIds: []string{"t1", "t3"}, SpDialect: constants.DIALECT_POSTGRESQL}, s, make(map[string]Sequence), DatabaseOptions{})
+	tablesWithTableIds := GetDDL(Config{Tables: true, ForeignKeys: false, TableIds: []string{"t1", "t3"}, SpDialect: constants.DIALECT_POSTGRESQL}, s, make(map[string]Sequence), nil, DatabaseOptions{})
 	e6 := []string{
 		"CREATE TABLE table1 (\n" +
 			"	a INT8,\n" +
@@ -1439,6 +1686,23 @@ func TestGetPGDDL(t *testing.T) {
 	assert.ElementsMatch(t, e6, tablesWithTableIds)
 }
 
+func TestPrintCreateView(t *testing.T) {
+	view := CreateView{Name: "view1", Id: "v1", Query: "SELECT a FROM table1"}
+	assert.Equal(t, "CREATE VIEW view1 SQL SECURITY INVOKER AS SELECT a FROM table1", view.PrintCreateView(Config{}))
+	assert.Equal(t, "CREATE VIEW `view1` SQL SECURITY INVOKER AS SELECT a FROM table1", view.PrintCreateView(Config{ProtectIds: true}))
+	assert.Equal(t, `CREATE VIEW "view1" SQL SECURITY INVOKER AS SELECT a FROM table1`, view.PrintCreateView(Config{ProtectIds: true, SpDialect: constants.DIALECT_POSTGRESQL}))
+}
+
+func TestGetSortedViewIds(t *testing.T) {
+	views := map[string]CreateView{
+		"v1": {Name: "a", Id: "v1", RefViewIds: []string{"v3"}},
+		"v2": {Name: "b", Id: "v2", RefViewIds: []string{"v4"}},
+		"v3": {Name: "c", Id: "v3"},
+	}
+	assert.Equal(t, []string{"v3", "v1", "v2"}, GetSortedViewIds(views))
+	assert.Empty(t, GetSortedViewIds(nil))
+}
+
 func TestGetSortedTableIdsBySpName(t *testing.T) {
 	testCases := []struct {
 		description string
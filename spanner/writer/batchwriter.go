@@ -63,13 +63,19 @@ type BatchWriter struct {
 	bytesLimit int64                      // Limit on bytes buffered. AddRow blocks if rBytes exceeded this value.
 	retryLimit int64                      // Limit on retries.
 	verbose    bool                       // If true, print out messages about each write batch.
+	upsert     bool                       // If true, rows are written with insert-or-update semantics.
 	async      asyncState
+
+	// droppedRow is called for each dropped row, if set.
+	droppedRow func(table string, cols []string, vals []interface{}, err error)
 }
 
 type row struct {
 	table string
 	cols  []string
 	vals  []interface{}
+	// dropped, if set, counts the rows of a caller that are dropped.
+	dropped *int64
 }
 
 // Fields in this struct are modified asynchronously e.g. by go routines writing
@@ -96,6 +102,10 @@ type BatchWriterConfig struct {
 	RetryLimit int64                      // Limit on retries.
 	Write      func([]*sp.Mutation) error // Function to call to write to Spanner (typically a closure that calls client.Apply).
 	Verbose    bool                       // If true, print out messages about each write batch.
+	Upsert     bool                       // If true, rows that already exist are overwritten instead of failing.
+	// DroppedRow, if set, is called for each row that is dropped, with the
+	// error returned when writing it. It must be thread-safe.
+	DroppedRow func(table string, cols []string, vals []interface{}, err error)
 }
 
 // NewBatchWriter returns a new BatchWriter with parameters defined by config.
@@ -106,6 +116,8 @@ func NewBatchWriter(config BatchWriterConfig) *BatchWriter {
 		bytesLimit: config.BytesLimit,
 		retryLimit: config.RetryLimit,
 		verbose:    config.Verbose,
+		upsert:     config.Upsert,
+		droppedRow: config.DroppedRow,
 		async: asyncState{
 			errors:      make(map[string]int64),
 			droppedRows: make(map[string]int64),
@@ -118,7 +130,10 @@ func NewBatchWriter(config BatchWriterConfig) *BatchWriter {
 // or it may block (waiting for some of the writes already in progress to
 // complete) and then initiate writes.
 func (bw *BatchWriter) AddRow(table string, cols []string, vals []interface{}) {
-	r := &row{table, cols, vals}
+	bw.addRow(&row{table: table, cols: cols, vals: vals})
+}
+
+func (bw *BatchWriter) addRow(r *row) {
 	bw.rows = append(bw.rows, r)
 	bw.rBytes += byteSize(r)
 	bw.rCount += int64(len(r.cols))
@@ -246,6 +261,9 @@ func (bw *BatchWriter) errorStats(rows []*row, err error, retry bool) {
 	}
 	for _, x := range rows {
 		bw.async.droppedRows[x.table]++
+		if x.dropped != nil {
+			atomic.AddInt64(x.dropped, 1)
+		}
 	}
 	return
 }
@@ -255,13 +273,22 @@ func (bw *BatchWriter) errorStats(rows []*row, err error, retry bool) {
 func (bw *BatchWriter) doWriteAndHandleErrors(rows []*row) {
 	var m []*sp.Mutation
 	for _, x := range rows {
-		m = append(m, sp.Insert(x.table, x.cols, x.vals))
+		if bw.upsert {
+			m = append(m, sp.InsertOrUpdate(x.table, x.cols, x.vals))
+		} else {
+			m = append(m, sp.Insert(x.table, x.cols, x.vals))
+		}
 	}
 	if err := bw.write(m); err != nil {
 		hitRetryLimit := atomic.LoadInt64(&bw.async.retries) >= bw.retryLimit
 		retry := len(rows) > 1 && !hitRetryLimit
 		bw.errorStats(rows, err, retry)
 		if !retry {
+			if bw.droppedRow != nil {
+				for _, x := range rows {
+					bw.droppedRow(x.table, x.cols, x.vals, err)
+				}
+			}
 			if hitRetryLimit && bw.verbose {
 				logger.Log.Info(fmt.Sprintf("Have hit %d retries: will not do any more\n", atomic.LoadInt64(&bw.async.retries)))
 			}
@@ -345,6 +372,19 @@ func byteSize(r *row) int64 {
 }
 
 func GetBatchWriterWithConfig(ctx context.Context, spannerClient spannerclient.SpannerClient, conv *internal.Conv) *BatchWriter {
+	batchWriter := newBatchWriterWithConfig(ctx, spannerClient)
+	conv.SetDataMode()
+	conv.SetDataSink(
+		func(table string, cols []string, vals []interface{}) {
+			batchWriter.AddRow(table, cols, vals)
+		})
+	conv.DataFlush = func() {
+		batchWriter.Flush()
+	}
+	return batchWriter
+}
+
+func newBatchWriterWithConfig(ctx context.Context, spannerClient spannerclient.SpannerClient) *BatchWriter {
 	// TODO: review these limits
 	config := BatchWriterConfig{
 		BytesLimit: 100 * 1000 * 1000,
@@ -363,14 +403,60 @@ func GetBatchWriterWithConfig(ctx context.Context, spannerClient spannerclient.S
 		atomic.AddInt64(&rows, int64(len(m)))
 		return nil
 	}
-	batchWriter := NewBatchWriter(config)
+	return NewBatchWriter(config)
+}
+
+// SyncBatchWriter is a BatchWriter that rows can be added to from several
+// goroutines, e.g. to import several files at once. The limits of the
+// writer, on buffered bytes and in-progress writes, hold for the rows of
+// all the goroutines.
+type SyncBatchWriter struct {
+	lock sync.Mutex
+	bw   *BatchWriter
+}
+
+// GetSyncBatchWriterWithConfig returns a SyncBatchWriter with the same
+// configuration as GetBatchWriterWithConfig. Convs are attached to it with
+// SetDataSink.
+func GetSyncBatchWriterWithConfig(ctx context.Context, spannerClient spannerclient.SpannerClient) *SyncBatchWriter {
+	return NewSyncBatchWriter(newBatchWriterWithConfig(ctx, spannerClient))
+}
+
+func NewSyncBatchWriter(bw *BatchWriter) *SyncBatchWriter {
+	return &SyncBatchWriter{bw: bw}
+}
+
+// AddRow adds a row to the writer. It blocks while another goroutine adds
+// a row or flushes the writer.
+func (w *SyncBatchWriter) AddRow(table string, cols []string, vals []interface{}) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.bw.AddRow(table, cols, vals)
+}
+
+// Flush writes the rows added by all goroutines, and waits for the writes
+// to complete.
+func (w *SyncBatchWriter) Flush() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.bw.Flush()
+}
+
+// DroppedRowsByTable returns a map of tables to counts of dropped rows.
+func (w *SyncBatchWriter) DroppedRowsByTable() map[string]int64 {
+	return w.bw.DroppedRowsByTable()
+}
+
+// SetDataSink sets the rows written by conv to be added to w. Each
+// goroutine should use its own conv, since convs aren't thread-safe.
+// If dropped is not nil, the rows of conv that are dropped are counted
+// in it; the count is final once w is flushed.
+func (w *SyncBatchWriter) SetDataSink(conv *internal.Conv, dropped *int64) {
 	conv.SetDataMode()
-	conv.SetDataSink(
-		func(table string, cols []string, vals []interface{}) {
-			batchWriter.AddRow(table, cols, vals)
-		})
-	conv.DataFlush = func() {
-		batchWriter.Flush()
-	}
-	return batchWriter
+	conv.SetDataSink(func(table string, cols []string, vals []interface{}) {
+		w.lock.Lock()
+		defer w.lock.Unlock()
+		w.bw.addRow(&row{table: table, cols: cols, vals: vals, dropped: dropped})
+	})
+	conv.DataFlush = w.Flush
 }
@@ -102,6 +102,91 @@ func TestFlush(t *testing.T) {
 	}
 }
 
+func TestFlush_Upsert(t *testing.T) {
+	for _, upsert := range []bool{false, true} {
+		var written []*sp.Mutation
+		bw := NewBatchWriter(BatchWriterConfig{
+			WriteLimit: 1,
+			RetryLimit: 1,
+			Upsert:     upsert,
+			Write: func(m []*sp.Mutation) error {
+				written = append(written, m...)
+				return nil
+			},
+		})
+		bw.AddRow("t", []string{"a"}, []interface{}{int64(1)})
+		bw.Flush()
+		expected := sp.Insert("t", []string{"a"}, []interface{}{int64(1)})
+		if upsert {
+			expected = sp.InsertOrUpdate("t", []string{"a"}, []interface{}{int64(1)})
+		}
+		assert.Equal(t, []*sp.Mutation{expected}, written, fmt.Sprintf("upsert=%t", upsert))
+	}
+}
+
+func TestFlush_DroppedRow(t *testing.T) {
+	bad := sp.Insert("t", []string{"a"}, []interface{}{int64(2)})
+	var dropped [][]interface{}
+	var lock sync.Mutex
+	bw := NewBatchWriter(BatchWriterConfig{
+		WriteLimit: 1,
+		RetryLimit: 10,
+		Write: func(m []*sp.Mutation) error {
+			for _, x := range m {
+				if reflect.DeepEqual(x, bad) {
+					return fmt.Errorf("bad row")
+				}
+			}
+			return nil
+		},
+		DroppedRow: func(table string, cols []string, vals []interface{}, err error) {
+			lock.Lock()
+			defer lock.Unlock()
+			assert.Equal(t, "t", table)
+			assert.Equal(t, "bad row", err.Error())
+			dropped = append(dropped, vals)
+		},
+	})
+	for i := int64(1); i <= 3; i++ {
+		bw.AddRow("t", []string{"a"}, []interface{}{i})
+	}
+	bw.Flush()
+	assert.Equal(t, [][]interface{}{{int64(2)}}, dropped)
+	assert.Equal(t, map[string]int64{"t": 1}, bw.DroppedRowsByTable())
+}
+
+func TestSyncBatchWriter(t *testing.T) {
+	var lock sync.Mutex
+	written := int64(0)
+	w := NewSyncBatchWriter(NewBatchWriter(BatchWriterConfig{
+		BytesLimit: 100,
+		WriteLimit: 2,
+		RetryLimit: 10,
+		Write: func(m []*sp.Mutation) error {
+			lock.Lock()
+			defer lock.Unlock()
+			written += int64(len(m))
+			return nil
+		},
+	}))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		conv := internal.MakeConv()
+		w.SetDataSink(conv, nil)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := int64(0); j < 100; j++ {
+				conv.WriteRow("t", "t", []string{"a"}, []interface{}{j})
+			}
+		}()
+	}
+	wg.Wait()
+	w.Flush()
+	assert.Equal(t, int64(1000), written)
+	assert.Empty(t, w.DroppedRowsByTable())
+}
+
 func TestDroppedRowsByTable(t *testing.T) {
 	bw := NewBatchWriter(BatchWriterConfig{})
 	bw.async.lock.Lock()
@@ -118,8 +203,8 @@ func TestSampleBadRows(t *testing.T) {
 	bw := NewBatchWriter(BatchWriterConfig{})
 	bw.async.lock.Lock()
 	bw.async.sampleBadRows = []*row{
-		&row{"test", []string{"col1", "col2"}, []interface{}{"a", int64(42)}},
-		&row{"test", []string{"col1", "col2"}, []interface{}{"b", int64(6)}},
+		&row{"test", []string{"col1", "col2"}, []interface{}{"a", int64(42)}, nil},
+		&row{"test", []string{"col1", "col2"}, []interface{}{"b", int64(6)}, nil},
 	}
 	bw.async.lock.Unlock()
 	l := bw.SampleBadRows(1)
@@ -173,7 +258,7 @@ func generateRows(count int, size int) ([]*row, int) {
 	for i := 0; i < count; i++ {
 		// vals[0] serves as a unique id for each row.
 		vals := []interface{}{i, val}
-		r = append(r, &row{"table", cols, vals})
+		r = append(r, &row{"table", cols, vals, nil})
 	}
 	// Find the max number of rows in a write for the (fixed sized)
 	// rows generated in this test data.
@@ -259,3 +344,34 @@ func getSpannerClientMock() spannerclient.SpannerClientMock {
 		},
 	}
 }
+
+func TestSyncBatchWriter_DroppedRows(t *testing.T) {
+	w := NewSyncBatchWriter(NewBatchWriter(BatchWriterConfig{
+		BytesLimit: 100,
+		WriteLimit: 1,
+		RetryLimit: 10,
+		Write: func(m []*sp.Mutation) error {
+			for _, x := range m {
+				if reflect.DeepEqual(x, sp.Insert("t", []string{"a"}, []interface{}{int64(-1)})) {
+					return fmt.Errorf("bad row")
+				}
+			}
+			return nil
+		},
+	}))
+	var dropped [2]int64
+	for i := range dropped {
+		conv := internal.MakeConv()
+		w.SetDataSink(conv, &dropped[i])
+		for j := int64(0); j < 3; j++ {
+			v := j
+			if i == 1 && j == 1 {
+				v = -1
+			}
+			conv.WriteRow("t", "t", []string{"a"}, []interface{}{v})
+		}
+	}
+	w.Flush()
+	assert.Equal(t, [2]int64{0, 1}, dropped)
+	assert.Equal(t, map[string]int64{"t": 1}, w.DroppedRowsByTable())
+}
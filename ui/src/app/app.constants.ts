@@ -28,7 +28,9 @@ export enum ObjectExplorerNodeType {
   Indexes = 'indexes',
   Index = 'indexName',
   Sequences = 'sequences',
-  Sequence = 'sequenceName'
+  Sequence = 'sequenceName',
+  Views = 'views',
+  View = 'viewName'
 }
 
 export enum RulesTypes {
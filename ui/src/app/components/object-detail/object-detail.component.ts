@@ -221,7 +221,7 @@ export class ObjectDetailComponent implements OnInit, OnDestroy {
     this.indexData = changes['indexData']?.currentValue || this.indexData
     this.sequenceData = changes['sequenceData']?.currentValue || this.sequenceData
     this.currentDatabase = changes['currentDatabase']?.currentValue || this.currentDatabase
-    this.currentTabIndex = this.currentObject?.type === ObjectExplorerNodeType.Index || this.currentObject?.type === ObjectExplorerNodeType.Sequence ? -1 : 0
+    this.currentTabIndex = this.currentObject?.type === ObjectExplorerNodeType.Index || this.currentObject?.type === ObjectExplorerNodeType.Sequence || this.currentObject?.type === ObjectExplorerNodeType.View ? -1 : 0
     this.isObjectSelected = this.currentObject ? true : false
     this.pkData = this.conversion.getPkMapping(this.tableData)
 
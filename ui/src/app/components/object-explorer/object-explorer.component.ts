@@ -141,7 +141,7 @@ export class ObjectExplorerComponent implements OnInit {
 
   objectSelected(data: FlatNode) {
     this.currentSelectedObject = data
-    if (data.type === ObjectExplorerNodeType.Index || data.type === ObjectExplorerNodeType.Table || data.type === ObjectExplorerNodeType.Sequence) {
+    if (data.type === ObjectExplorerNodeType.Index || data.type === ObjectExplorerNodeType.Table || data.type === ObjectExplorerNodeType.Sequence || data.type === ObjectExplorerNodeType.View) {
       this.selectObject.emit(data)
     }
   }
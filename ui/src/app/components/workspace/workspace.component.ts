@@ -237,6 +237,8 @@ export class WorkspaceComponent implements OnInit, OnDestroy {
     } else if (object.type === ObjectExplorerNodeType.Sequence) {
       this.currentObject = object
       this.sequenceData = this.conversion.getSequenceMapping(object.id, this.conv)
+    } else if (object.type === ObjectExplorerNodeType.View) {
+      this.currentObject = object
     }
     else {
       this.currentObject = null
@@ -22,6 +22,22 @@ export default interface IConv {
   IsSharded: boolean
   SpSequences: Record<string, ICreateSequence>
   SrcSequences: Record<string, ICreateSequence>
+  SpViews: Record<string, ICreateView>
+  SrcViews: Record<string, IView>
+}
+
+export interface ICreateView {
+  Name: string
+  Id: string
+  Query: string
+  RefViewIds: string[]
+}
+
+export interface IView {
+  Name: string
+  Schema: string
+  Definition: string
+  Id: string
 }
 
 export interface IDefaultValue {
@@ -103,6 +119,9 @@ export interface IIndex {
   Unique: boolean
   Keys: ISrcIndexKey[]
   Id: string
+  FullText?: boolean
+  Vector?: boolean
+  DistanceType?: string
 }
 
 export interface IInterleavedParent{
@@ -121,17 +140,42 @@ export interface ICreateTable {
   ForeignKeys: IForeignKey[]
   CheckConstraints: ICheckConstraints[]
   Indexes: ICreateIndex[]
+  SearchIndexes?: ICreateSearchIndex[]
+  VectorIndexes?: ICreateVectorIndex[]
   ParentTable: IInterleavedParent
+  RowDeletionPolicy?: IRowDeletionPolicy
   Comment: string
   Id: string
 }
 
+export interface IRowDeletionPolicy {
+  ColId: string
+  NumDays: number
+}
+
 export interface ICreateIndex {
   Name: string
   TableId: string
   Unique: boolean
   Keys: IIndexKey[]
   Id: string
+  NullFiltered?: boolean
+  InterleaveTableId?: string
+}
+
+export interface ICreateSearchIndex {
+  Name: string
+  TableId: string
+  Keys: IIndexKey[]
+  Id: string
+}
+
+export interface ICreateVectorIndex {
+  Name: string
+  TableId: string
+  Keys: IIndexKey[]
+  DistanceType: string
+  Id: string
 }
 
 export interface IForeignKey {
@@ -179,6 +223,7 @@ export interface IType {
   Name: string
   Len: Number
   IsArray: boolean
+  VectorLength?: number
 }
 
 export interface ISyntheticPKey {
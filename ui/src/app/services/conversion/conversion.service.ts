@@ -61,6 +61,10 @@ export class ConversionService {
       conv.SpSequences[seqId].Name.toLocaleLowerCase().includes(searchText.toLocaleLowerCase())
     )
 
+    let spannerViewIds = Object.keys(conv.SpViews ?? {}).filter((viewId: string) =>
+      conv.SpViews[viewId].Name.toLocaleLowerCase().includes(searchText.toLocaleLowerCase())
+    )
+
     let deletedTableIds = Object.keys(conv.SrcSchema).filter((tableId: string) => {
       return (
         spannerTableIds.indexOf(tableId) == -1 &&
@@ -176,13 +180,40 @@ export class ConversionService {
       }),
     }
 
+    let viewNode: ISchemaObjectNode = {
+      name: `Views (${spannerViewIds.length})`,
+      type: ObjectExplorerNodeType.Views,
+      parent: '',
+      pos: -1,
+      isSpannerNode: true,
+      id: '',
+      parentId: '',
+      children: spannerViewIds.map((viewId: string) => {
+        return {
+          name: conv.SpViews[viewId].Name,
+          status: '',
+          type: ObjectExplorerNodeType.View,
+          parent: '',
+          pos: -1,
+          isSpannerNode: true,
+          id: viewId,
+          parentId: '',
+          children: [],
+        }
+      }),
+    }
+
     this.sortNodeChildren(parentNode, sortOrder)
     this.sortNodeChildren(sequenceNode, sortOrder)
+    this.sortNodeChildren(viewNode, sortOrder)
 
     let mainNodeChildren :ISchemaObjectNode[] = [parentNode]
     if (defaultAndSequenceSupportedDbs.includes(this.srcDbName)) {
       mainNodeChildren.push(sequenceNode)
     }
+    if (Object.keys(conv.SrcViews ?? {}).length > 0) {
+      mainNodeChildren.push(viewNode)
+    }
     return [
       {
         name: conv.DatabaseName,
@@ -723,7 +754,9 @@ export class ConversionService {
       let tableDeletedIndexes =
         srcTable && srcTable.Indexes
           ? srcTable.Indexes?.filter((index: IIndex) => {
-              if (!spIndexIds.includes(index.Id)) {
+              // Full-text and vector indexes are converted to search and vector
+              // indexes and cannot be restored.
+              if (!spIndexIds.includes(index.Id) && !index.FullText && !index.Vector) {
                 return true
               }
               return false
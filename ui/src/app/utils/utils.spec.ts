@@ -76,7 +76,9 @@ describe('Utils', () => {
         SpDialect: '',
         IsSharded: false,
         SpSequences: {},
-        SrcSequences: {}
+        SrcSequences: {},
+        SpViews: {},
+        SrcViews: {}
       };
     });
 
@@ -314,7 +316,9 @@ describe('Utils', () => {
         SpDialect: '',
         IsSharded: false,
         SpSequences: {},
-        SrcSequences: {}
+        SrcSequences: {},
+        SpViews: {},
+        SrcViews: {}
       };
 
       mockClick = jasmine.createSpy('click');
@@ -375,7 +379,9 @@ describe('Utils', () => {
         SpDialect: '',
         IsSharded: false,
         SpSequences: {},
-        SrcSequences: {}
+        SrcSequences: {},
+        SpViews: {},
+        SrcViews: {}
       };
 
       mockClick = jasmine.createSpy('click');
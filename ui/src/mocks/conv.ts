@@ -165,7 +165,9 @@ export function createMockIConv(): IConv {
             SequenceKind: "BIT REVERSED POSITIVE"
         },
     },
-    SrcSequences: {}
+    SrcSequences: {},
+    SpViews: {},
+    SrcViews: {}
     };
 }
 
@@ -317,6 +319,8 @@ export function createMockIConv2(): IConv {
     SpDialect: 'googlestandardsql',
     IsSharded: false,
     SpSequences: {},
-    SrcSequences: {}
+    SrcSequences: {},
+    SpViews: {},
+    SrcViews: {}
     };
 }
\ No newline at end of file
@@ -21,7 +21,8 @@ import (
 )
 
 // ApplyRule allows to add rules that changes the schema
-// currently it supports two types of operations viz. SetGlobalDataType and AddIndex
+// currently it supports the operations SetGlobalDataType, AddIndex, EditColumnMaxLength,
+// AddShardIdPrimaryKey and AddRowDeletionPolicy
 func ApplyRule(w http.ResponseWriter, r *http.Request) {
 	reqBody, err := ioutil.ReadAll(r.Body)
 	if err != nil {
@@ -101,6 +102,23 @@ func ApplyRule(w http.ResponseWriter, r *http.Request) {
 		}
 		setShardIdColumnAsPrimaryKey(shardIdPrimaryKey.AddedAtTheStart)
 		addShardIdColumnToForeignKeys(shardIdPrimaryKey.AddedAtTheStart)
+	} else if rule.Type == constants.AddRowDeletionPolicy {
+		d, err := json.Marshal(rule.Data)
+		if err != nil {
+			http.Error(w, "Invalid rule data", http.StatusInternalServerError)
+			return
+		}
+		var rowDeletionPolicy types.RowDeletionPolicy
+		err = json.Unmarshal(d, &rowDeletionPolicy)
+		if err != nil {
+			http.Error(w, "Invalid rule data", http.StatusInternalServerError)
+			return
+		}
+		err = setRowDeletionPolicy(rowDeletionPolicy)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 	} else {
 		http.Error(w, "Invalid rule type", http.StatusInternalServerError)
 		return
@@ -210,6 +228,21 @@ func DropRule(w http.ResponseWriter, r *http.Request) {
 		}
 		revertShardIdColumnAsPrimaryKey(shardIdPrimaryKey.AddedAtTheStart)
 		removeShardIdColumnFromForeignKeys(shardIdPrimaryKey.AddedAtTheStart)
+	} else if rule.Type == constants.AddRowDeletionPolicy {
+		if rule.Enabled {
+			d, err := json.Marshal(rule.Data)
+			if err != nil {
+				http.Error(w, "Invalid rule data", http.StatusInternalServerError)
+				return
+			}
+			var rowDeletionPolicy types.RowDeletionPolicy
+			err = json.Unmarshal(d, &rowDeletionPolicy)
+			if err != nil {
+				http.Error(w, "Invalid rule data", http.StatusInternalServerError)
+				return
+			}
+			removeRowDeletionPolicy(rowDeletionPolicy.TableId)
+		}
 	} else {
 		http.Error(w, "Invalid rule type", http.StatusInternalServerError)
 		return
@@ -285,6 +318,34 @@ func addIndex(newIndex ddl.CreateIndex) (ddl.CreateIndex, error) {
 	return newIndexes[0], nil
 }
 
+// setRowDeletionPolicy sets the row deletion policy of a table, after checking that
+// the policy is on a TIMESTAMP column of the table.
+func setRowDeletionPolicy(rowDeletionPolicy types.RowDeletionPolicy) error {
+	sessionState := session.GetSessionState()
+	sp, found := sessionState.Conv.SpSchema[rowDeletionPolicy.TableId]
+	if !found {
+		return fmt.Errorf("table id %s not found", rowDeletionPolicy.TableId)
+	}
+	rdp := ddl.RowDeletionPolicy{ColId: rowDeletionPolicy.ColId, NumDays: rowDeletionPolicy.NumDays}
+	if err := rdp.Validate(sp); err != nil {
+		return err
+	}
+	sp.RowDeletionPolicy = rdp
+	sessionState.Conv.SpSchema[rowDeletionPolicy.TableId] = sp
+	return nil
+}
+
+// removeRowDeletionPolicy removes the row deletion policy of a table.
+func removeRowDeletionPolicy(tableId string) {
+	sessionState := session.GetSessionState()
+	sp, found := sessionState.Conv.SpSchema[tableId]
+	if !found {
+		return
+	}
+	sp.RowDeletionPolicy = ddl.RowDeletionPolicy{}
+	sessionState.Conv.SpSchema[tableId] = sp
+}
+
 func setSpColMaxLength(spColMaxLength types.ColMaxLength, associatedObjects string) {
 	sessionState := session.GetSessionState()
 	if associatedObjects == "All table" {
@@ -551,6 +551,97 @@ func TestApplyRule(t *testing.T) {
 	}
 }
 
+func TestApplyRule_RowDeletionPolicy(t *testing.T) {
+	buildConv := func() *internal.Conv {
+		return &internal.Conv{
+			SpSchema: map[string]ddl.CreateTable{
+				"t1": {
+					Name:   "events",
+					Id:     "t1",
+					ColIds: []string{"c1", "c2"},
+					ColDefs: map[string]ddl.ColumnDef{
+						"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}, NotNull: true},
+						"c2": {Name: "created_at", Id: "c2", T: ddl.Type{Name: ddl.Timestamp}},
+					},
+					PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}},
+				}},
+			Audit: internal.Audit{
+				MigrationType: migration.MigrationData_SCHEMA_ONLY.Enum(),
+			},
+		}
+	}
+	tc := []struct {
+		name       string
+		data       map[string]interface{}
+		statusCode int64
+		expected   ddl.RowDeletionPolicy
+	}{
+		{
+			name:       "timestamp column",
+			data:       map[string]interface{}{"TableId": "t1", "ColId": "c2", "NumDays": 30},
+			statusCode: http.StatusOK,
+			expected:   ddl.RowDeletionPolicy{ColId: "c2", NumDays: 30},
+		},
+		{
+			name:       "non timestamp column",
+			data:       map[string]interface{}{"TableId": "t1", "ColId": "c1", "NumDays": 30},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown column",
+			data:       map[string]interface{}{"TableId": "t1", "ColId": "c3", "NumDays": 30},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown table",
+			data:       map[string]interface{}{"TableId": "t2", "ColId": "c2", "NumDays": 30},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "negative number of days",
+			data:       map[string]interface{}{"TableId": "t1", "ColId": "c2", "NumDays": -1},
+			statusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range tc {
+		sessionState := session.GetSessionState()
+		sessionState.Driver = constants.MYSQL
+		sessionState.Conv = buildConv()
+		rule := internal.Rule{
+			Name:              "rule-ttl",
+			ObjectType:        "Table",
+			AssociatedObjects: "t1",
+			Enabled:           true,
+			Type:              constants.AddRowDeletionPolicy,
+			Data:              tc.data,
+		}
+		inputBytes, err := json.Marshal(rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, err := http.NewRequest("POST", "/applyrule", bytes.NewBuffer(inputBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(api.ApplyRule)
+		handler.ServeHTTP(rr, req)
+		if status := rr.Code; int64(status) != tc.statusCode {
+			t.Errorf("%s : handler returned wrong status code: got %v want %v",
+				tc.name, status, tc.statusCode)
+		}
+		if tc.statusCode == http.StatusOK {
+			var res *internal.Conv
+			json.Unmarshal(rr.Body.Bytes(), &res)
+			assert.Equal(t, tc.expected, res.SpSchema["t1"].RowDeletionPolicy, tc.name)
+			assert.Equal(t, 1, len(res.Rules), tc.name)
+		} else {
+			assert.Equal(t, ddl.RowDeletionPolicy{}, sessionState.Conv.SpSchema["t1"].RowDeletionPolicy, tc.name)
+		}
+	}
+}
+
 func TestDropRule(t *testing.T) {
 	tc := []struct {
 		name         string
@@ -753,6 +844,42 @@ func TestDropRule(t *testing.T) {
 					}},
 			},
 		},
+		{
+			name:       "drop a valid add row deletion policy rule",
+			ruleId:     "r101",
+			statusCode: http.StatusOK,
+			conv: &internal.Conv{
+				SpSchema: map[string]ddl.CreateTable{
+					"t1": {
+						Name:              "table1",
+						Id:                "t1",
+						ColIds:            []string{"c1"},
+						ColDefs:           map[string]ddl.ColumnDef{"c1": {Name: "a", Id: "c1", T: ddl.Type{Name: ddl.Timestamp}}},
+						RowDeletionPolicy: ddl.RowDeletionPolicy{ColId: "c1", NumDays: 7},
+					}},
+				Audit: internal.Audit{
+					MigrationType: migration.MigrationData_SCHEMA_ONLY.Enum(),
+				},
+				Rules: []internal.Rule{{
+					Id:                "r101",
+					Name:              "ttl",
+					Type:              constants.AddRowDeletionPolicy,
+					ObjectType:        "table",
+					AssociatedObjects: "t1",
+					Enabled:           true,
+					Data:              map[string]interface{}{"TableId": "t1", "ColId": "c1", "NumDays": 7},
+				}},
+			},
+			expectedConv: &internal.Conv{
+				SpSchema: map[string]ddl.CreateTable{
+					"t1": {
+						Name:    "table1",
+						Id:      "t1",
+						ColIds:  []string{"c1"},
+						ColDefs: map[string]ddl.ColumnDef{"c1": {Name: "a", Id: "c1", T: ddl.Type{Name: ddl.Timestamp}}},
+					}},
+			},
+		},
 	}
 	for _, tc := range tc {
 		sessionState := session.GetSessionState()
@@ -230,15 +230,19 @@ func (expressionVerificationHandler *ExpressionsVerificationHandler) ConvertSche
 	json.NewEncoder(w).Encode(convm)
 }
 
-// GetDDL returns the Spanner DDL for each table in alphabetical order.
+// GetDDL returns the Spanner DDL for each table in alphabetical order, and
+// for each view.
 // Unlike internal/convert.go's GetDDL, it does not print tables in a way that
 // respects the parent/child ordering of interleaved tables.
 // Though foreign keys and secondary indexes are displayed, getDDL cannot be used to
 // build DDL to send to Spanner.
 func GetDDL(w http.ResponseWriter, r *http.Request) {
 	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.RLock()
-	defer sessionState.Conv.ConvLock.RUnlock()
+	sessionState.Conv.ConvLock.Lock()
+	defer sessionState.Conv.ConvLock.Unlock()
+	// Views are translated again, as the tables they read from may have
+	// changed since.
+	common.ViewsToSpanner(sessionState.Conv, getToDdl(sessionState.Driver))
 	c := ddl.Config{Comments: true, ProtectIds: false, SpDialect: sessionState.Conv.SpDialect, Source: sessionState.Driver}
 	var tables []string
 	for t := range sessionState.Conv.SpSchema {
@@ -253,7 +257,13 @@ func GetDDL(w http.ResponseWriter, r *http.Request) {
 			tableDdl = tableDdl + "\n"
 		}
 		for _, index := range table.Indexes {
-			tableDdl = tableDdl + "\n" + index.PrintCreateIndex(table, c) + ";"
+			tableDdl = tableDdl + "\n" + index.PrintCreateIndex(sessionState.Conv.SpSchema, table, c) + ";"
+		}
+		for _, searchIndex := range table.SearchIndexes {
+			tableDdl = tableDdl + "\n" + searchIndex.PrintCreateSearchIndex(table, c) + ";"
+		}
+		for _, vectorIndex := range table.VectorIndexes {
+			tableDdl = tableDdl + "\n" + vectorIndex.PrintCreateVectorIndex(table, c) + ";"
 		}
 		if len(table.ForeignKeys) > 0 {
 			tableDdl = tableDdl + "\n"
@@ -264,6 +274,9 @@ func GetDDL(w http.ResponseWriter, r *http.Request) {
 
 		ddl[t] = tableDdl
 	}
+	for id, view := range sessionState.Conv.SpViews {
+		ddl[id] = view.PrintCreateView(c) + ";"
+	}
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(ddl)
 }
@@ -570,6 +583,14 @@ func RestoreSecondaryIndex(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("Source index not found"), http.StatusBadRequest)
 		return
 	}
+	if srcIndex.FullText {
+		http.Error(w, fmt.Sprintf("Full-text indexes are converted to search indexes and cannot be restored as secondary indexes"), http.StatusBadRequest)
+		return
+	}
+	if srcIndex.Vector {
+		http.Error(w, fmt.Sprintf("Vector indexes are converted to vector indexes and cannot be restored as secondary indexes"), http.StatusBadRequest)
+		return
+	}
 
 	conv := sessionState.Conv
 
@@ -985,6 +1006,7 @@ func RemoveParentTable(w http.ResponseWriter, r *http.Request) {
 	spTable.ParentTable.OnDelete = ""
 	spTable.ParentTable.InterleaveType = ""
 	conv.SpSchema[tableId] = spTable
+	utilities.RemoveInvalidIndexInterleaving(conv)
 
 	sessionState.Conv = conv
 
@@ -1028,6 +1050,11 @@ func UpdateIndexes(w http.ResponseWriter, r *http.Request) {
 
 	st := sessionState.Conv.SrcSchema[table]
 
+	if err := newIndexes[0].Validate(sessionState.Conv.SpSchema); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	for i, ind := range sp.Indexes {
 		if ind.TableId == newIndexes[0].TableId && ind.Id == newIndexes[0].Id {
 
@@ -1038,6 +1065,8 @@ func UpdateIndexes(w http.ResponseWriter, r *http.Request) {
 			sp.Indexes[i].TableId = newIndexes[0].TableId
 			sp.Indexes[i].Unique = newIndexes[0].Unique
 			sp.Indexes[i].Id = newIndexes[0].Id
+			sp.Indexes[i].NullFiltered = newIndexes[0].NullFiltered
+			sp.Indexes[i].InterleaveTableId = newIndexes[0].InterleaveTableId
 
 			break
 		}
@@ -1131,6 +1160,27 @@ func GetConversionRate(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(rate)
 }
 
+// getToDdl returns the ToDdl of driver, or nil if driver isn't supported.
+func getToDdl(driver string) common.ToDdl {
+	switch driver {
+	case constants.MYSQL:
+		return mysql.InfoSchemaImpl{}.GetToDdl()
+	case constants.POSTGRES:
+		return postgres.InfoSchemaImpl{}.GetToDdl()
+	case constants.SQLSERVER:
+		return sqlserver.InfoSchemaImpl{}.GetToDdl()
+	case constants.ORACLE:
+		return oracle.InfoSchemaImpl{}.GetToDdl()
+	case constants.CASSANDRA:
+		return cassandra.InfoSchemaImpl{}.GetToDdl()
+	case constants.MYSQLDUMP:
+		return mysql.DbDumpImpl{}.GetToDdl()
+	case constants.PGDUMP:
+		return postgres.DbDumpImpl{}.GetToDdl()
+	}
+	return nil
+}
+
 func (tableHandler *TableAPIHandler) restoreTableHelper(w http.ResponseWriter, tableId string) session.ConvWithMetadata {
 	sessionState := session.GetSessionState()
 	if sessionState.Conv == nil || sessionState.Driver == "" {
@@ -1143,23 +1193,8 @@ func (tableHandler *TableAPIHandler) restoreTableHelper(w http.ResponseWriter, t
 	sessionState.Conv.ConvLock.Lock()
 	defer sessionState.Conv.ConvLock.Unlock()
 	conv := sessionState.Conv
-	var toddl common.ToDdl
-	switch sessionState.Driver {
-	case constants.MYSQL:
-		toddl = mysql.InfoSchemaImpl{}.GetToDdl()
-	case constants.POSTGRES:
-		toddl = postgres.InfoSchemaImpl{}.GetToDdl()
-	case constants.SQLSERVER:
-		toddl = sqlserver.InfoSchemaImpl{}.GetToDdl()
-	case constants.ORACLE:
-		toddl = oracle.InfoSchemaImpl{}.GetToDdl()
-	case constants.CASSANDRA:
-		toddl = cassandra.InfoSchemaImpl{}.GetToDdl()
-	case constants.MYSQLDUMP:
-		toddl = mysql.DbDumpImpl{}.GetToDdl()
-	case constants.PGDUMP:
-		toddl = postgres.DbDumpImpl{}.GetToDdl()
-	default:
+	toddl := getToDdl(sessionState.Driver)
+	if toddl == nil {
 		http.Error(w, fmt.Sprintf("Driver : '%s' is not supported", sessionState.Driver), http.StatusBadRequest)
 	}
 
@@ -1238,6 +1273,8 @@ func parentTableHelper(tableId string, parentTableId string, interleaveType stri
 		sp.ParentTable.OnDelete = onDelete
 		sp.ParentTable.InterleaveType = interleaveType
 		sessionState.Conv.SpSchema[tableId] = sp
+		index.InterleaveIndexes(tableId)
+		utilities.RemoveInvalidIndexInterleaving(sessionState.Conv)
 	}
 	tableInterleaveStatus.Possible = true
 	tableInterleaveStatus.Comment = ""
@@ -1385,6 +1422,7 @@ func dropTableHelper(w http.ResponseWriter, tableId string) session.ConvWithMeta
 			spSchema[id] = spTable
 		}
 	}
+	utilities.RemoveInvalidIndexInterleaving(sessionState.Conv)
 
 	// remove interleavable suggestion on droping the parent table
 	for tableName, tableIssues := range issues {
@@ -1455,7 +1493,7 @@ func initializeTypeMap() {
 	}
 	// Initialize postgresTypeMap.
 	toddl = postgres.InfoSchemaImpl{}.GetToDdl()
-	for _, srcTypeName := range []string{"bool", "boolean", "bigserial", "bpchar", "character", "bytea", "date", "float8", "double precision", "float4", "real", "int8", "bigint", "int4", "integer", "int2", "smallint", "numeric", "serial", "smallserial", "text", "timestamptz", "timestamp with time zone", "timestamp", "timestamp without time zone", "varchar", "character varying", "path"} {
+	for _, srcTypeName := range []string{"bool", "boolean", "bigserial", "bpchar", "character", "bytea", "date", "float8", "double precision", "float4", "real", "int8", "bigint", "int4", "integer", "int2", "smallint", "numeric", "serial", "smallserial", "text", "timestamptz", "timestamp with time zone", "timestamp", "timestamp without time zone", "varchar", "character varying", "path", "vector", "halfvec"} {
 		var l []types.TypeIssue
 		srcType := schema.MakeType()
 		srcType.Name = srcTypeName
@@ -281,6 +281,21 @@ func TestGetDDL(t *testing.T) {
 				"t2": "CREATE TABLE table2 (\n\td INT64 NOT NULL ,\n) ;"},
 			statusCode: http.StatusOK,
 		},
+		{
+			name: "Get valid ddl with view",
+			conv: &internal.Conv{
+				SpSchema: map[string]ddl.CreateTable{"t1": {
+					Name:        "table1",
+					ColIds:      []string{"c1"},
+					ColDefs:     map[string]ddl.ColumnDef{"c1": {Name: "a", T: ddl.Type{Name: ddl.Int64}, NotNull: true}},
+					PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Desc: false}},
+				}},
+				SpViews: map[string]ddl.CreateView{"v1": {Name: "view1", Id: "v1", Query: "SELECT a FROM table1"}},
+			},
+			expectedDDL: map[string]string{"t1": "CREATE TABLE table1 (\n\ta INT64 NOT NULL ,\n) PRIMARY KEY (a);",
+				"v1": "CREATE VIEW view1 SQL SECURITY INVOKER AS SELECT a FROM table1;"},
+			statusCode: http.StatusOK,
+		},
 	}
 
 	for _, tc := range tc {
@@ -708,6 +723,62 @@ func TestUpdateIndexes(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:       "Null filter an index",
+			tableId:    "t1",
+			input:      []ddl.CreateIndex{{Name: "idx", Id: "i1", TableId: "t1", Unique: false, Keys: []ddl.IndexKey{{ColId: "c2", Desc: false, Order: 1}}, NullFiltered: true}},
+			statusCode: http.StatusOK,
+			conv: &internal.Conv{
+				SpSchema: map[string]ddl.CreateTable{
+					"t1": {
+						Indexes: []ddl.CreateIndex{{Name: "idx", Id: "i1", TableId: "t1", Unique: false, Keys: []ddl.IndexKey{{ColId: "c2", Desc: false, Order: 1}}}},
+					}},
+				SrcSchema: map[string]schema.Table{
+					"t1": {
+						Indexes: []schema.Index{{Name: "idx", Id: "i1", Keys: []schema.Key{{ColId: "c2", Desc: false, Order: 1}}}},
+					},
+				},
+				Audit: internal.Audit{
+					MigrationType: migration.MigrationData_SCHEMA_ONLY.Enum(),
+				},
+				UsedNames: map[string]bool{"t1": true, "idx": true},
+			},
+			expectedConv: &internal.Conv{
+				SpSchema: map[string]ddl.CreateTable{
+					"t1": {
+						Indexes: []ddl.CreateIndex{{Name: "idx", Id: "i1", TableId: "t1", Unique: false, Keys: []ddl.IndexKey{{ColId: "c2", Desc: false, Order: 1}}, NullFiltered: true}},
+					}},
+				SrcSchema: map[string]schema.Table{
+					"t1": {
+						Indexes: []schema.Index{{Name: "idx", Id: "i1", Keys: []schema.Key{{ColId: "c2", Desc: false, Order: 1}}}},
+					},
+				},
+			},
+		},
+		{
+			name:       "Index can't be interleaved in a table that is not its parent",
+			tableId:    "t1",
+			input:      []ddl.CreateIndex{{Name: "idx", Id: "i1", TableId: "t1", Unique: false, Keys: []ddl.IndexKey{{ColId: "c2", Desc: false, Order: 1}}, InterleaveTableId: "t2"}},
+			statusCode: http.StatusBadRequest,
+			conv: &internal.Conv{
+				SpSchema: map[string]ddl.CreateTable{
+					"t1": {
+						Id:      "t1",
+						Indexes: []ddl.CreateIndex{{Name: "idx", Id: "i1", TableId: "t1", Unique: false, Keys: []ddl.IndexKey{{ColId: "c2", Desc: false, Order: 1}}}},
+					},
+					"t2": {Id: "t2"},
+				},
+				SrcSchema: map[string]schema.Table{
+					"t1": {
+						Indexes: []schema.Index{{Name: "idx", Id: "i1", Keys: []schema.Key{{ColId: "c2", Desc: false, Order: 1}}}},
+					},
+				},
+				Audit: internal.Audit{
+					MigrationType: migration.MigrationData_SCHEMA_ONLY.Enum(),
+				},
+				UsedNames: map[string]bool{"t1": true, "t2": true, "idx": true},
+			},
+		},
 		{
 			name:       "Two Index key columns can not have same order",
 			tableId:    "t1",
@@ -2242,6 +2313,57 @@ func TestSetParentTable(t *testing.T) {
 	}
 }
 
+func TestSetParentTable_InterleavesIndexes(t *testing.T) {
+	sessionState := session.GetSessionState()
+	sessionState.Driver = constants.MYSQL
+	sessionState.Conv = &internal.Conv{
+		SpSchema: map[string]ddl.CreateTable{
+			"t1": {
+				Name:   "t1",
+				Id:     "t1",
+				ColIds: []string{"c1", "c2", "c3"},
+				ColDefs: map[string]ddl.ColumnDef{
+					"c1": {Name: "col1", Id: "c1", T: ddl.Type{Name: ddl.Int64}, NotNull: true},
+					"c2": {Name: "col2", Id: "c2", T: ddl.Type{Name: ddl.Int64}, NotNull: true},
+					"c3": {Name: "col3", Id: "c3", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+				},
+				PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}, {ColId: "c2", Order: 2}},
+				Indexes: []ddl.CreateIndex{
+					{Name: "idx1", Id: "i1", TableId: "t1", Keys: []ddl.IndexKey{{ColId: "c1", Order: 1}, {ColId: "c3", Order: 2}}},
+					{Name: "idx2", Id: "i2", TableId: "t1", Keys: []ddl.IndexKey{{ColId: "c3", Order: 1}}},
+				},
+			},
+			"t2": {
+				Name:   "t2",
+				Id:     "t2",
+				ColIds: []string{"c4"},
+				ColDefs: map[string]ddl.ColumnDef{
+					"c4": {Name: "col1", Id: "c4", T: ddl.Type{Name: ddl.Int64}, NotNull: true},
+				},
+				PrimaryKeys: []ddl.IndexKey{{ColId: "c4", Order: 1}},
+			},
+		},
+		SchemaIssues: map[string]internal.TableIssues{
+			"t1": {ColumnLevelIssues: map[string][]internal.SchemaIssue{}},
+			"t2": {ColumnLevelIssues: map[string][]internal.SchemaIssue{}},
+		},
+		Audit: internal.Audit{
+			MigrationType: migration.MigrationData_SCHEMA_ONLY.Enum(),
+		},
+	}
+	req, err := http.NewRequest("GET", "/setparent?table=t1&parentTable=t2&interleaveType=IN%20PARENT&onDelete=CASCADE&update=true", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(api.SetParentTable)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	indexes := sessionState.Conv.SpSchema["t1"].Indexes
+	assert.Equal(t, "t2", indexes[0].InterleaveTableId)
+	assert.Equal(t, "", indexes[1].InterleaveTableId)
+}
+
 func TestRemoveParentTable(t *testing.T) { // TODO: convert this to table driven test
 	tc := []struct {
 		name             string
@@ -2266,6 +2388,7 @@ func TestRemoveParentTable(t *testing.T) { // TODO: convert this to table driven
 						},
 						PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Desc: false, Order: 1}, {ColId: "c2", Desc: false, Order: 2}},
 						ParentTable: ddl.InterleavedParent{Id: "t2", OnDelete: constants.FK_CASCADE, InterleaveType: "IN PARENT"},
+						Indexes:     []ddl.CreateIndex{{Name: "idx", Id: "i1", TableId: "t1", Keys: []ddl.IndexKey{{ColId: "c1", Order: 1}, {ColId: "c3", Order: 2}}, InterleaveTableId: "t2"}},
 					},
 					"t2": {
 						Name:   "table2",
@@ -2294,6 +2417,7 @@ func TestRemoveParentTable(t *testing.T) { // TODO: convert this to table driven
 					},
 					PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Desc: false, Order: 1}, {ColId: "c2", Desc: false, Order: 2}},
 					ParentTable: ddl.InterleavedParent{Id: "", OnDelete: "", InterleaveType: ""},
+					Indexes:     []ddl.CreateIndex{{Name: "idx", Id: "i1", TableId: "t1", Keys: []ddl.IndexKey{{ColId: "c1", Order: 1}, {ColId: "c3", Order: 2}}}},
 				},
 				"t2": {
 					Name:   "table2",
@@ -0,0 +1,80 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helpers
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/clients"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"google.golang.org/grpc/codes"
+)
+
+const checkpointTable = "DataMigrationCheckpoint"
+
+// MetadataCheckpointStore stores the checkpoint of a data migration in the
+// DataMigrationCheckpoint table of the internal metadata database, keyed
+// by the URI of the database being migrated to.
+type MetadataCheckpointStore struct {
+	client *spanner.Client
+	dbURI  string
+}
+
+var _ internal.CheckpointStore = (*MetadataCheckpointStore)(nil)
+
+// NewMetadataCheckpointStore returns a store for the checkpoint of the
+// migration to dbURI, in the metadata database of the given instance.
+// Close it once the migration is done.
+func NewMetadataCheckpointStore(ctx context.Context, projectId, instanceId, dbURI string) (*MetadataCheckpointStore, error) {
+	client, err := spanner.NewClient(ctx, GetSpannerUri(projectId, instanceId), clients.FetchSpannerClientOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	return &MetadataCheckpointStore{client: client, dbURI: dbURI}, nil
+}
+
+func (st *MetadataCheckpointStore) Load() ([]byte, error) {
+	row, err := st.client.Single().ReadRow(context.Background(), checkpointTable, spanner.Key{st.dbURI}, []string{"Checkpoint"})
+	if spanner.ErrCode(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var data string
+	if err := row.Columns(&data); err != nil {
+		return nil, err
+	}
+	return []byte(data), nil
+}
+
+func (st *MetadataCheckpointStore) Save(data []byte) error {
+	m := spanner.InsertOrUpdate(checkpointTable, []string{"DatabaseUri", "Checkpoint", "UpdateTimestamp"},
+		[]interface{}{st.dbURI, string(data), spanner.CommitTimestamp})
+	_, err := st.client.Apply(context.Background(), []*spanner.Mutation{m})
+	return err
+}
+
+func (st *MetadataCheckpointStore) String() string {
+	return fmt.Sprintf("table %s of database %s", checkpointTable, constants.METADATA_DB)
+}
+
+// Close closes the client of the metadata database.
+func (st *MetadataCheckpointStore) Close() {
+	st.client.Close()
+}
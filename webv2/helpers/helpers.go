@@ -54,6 +54,11 @@ var TABLE_STATEMENTS = []string{
 		SchemaConversionObject JSON NOT NULL,
 		CreateTimestamp TIMESTAMP NOT NULL,
 	  ) PRIMARY KEY(VersionId)`,
+	`CREATE TABLE IF NOT EXISTS DataMigrationCheckpoint (
+		DatabaseUri STRING(MAX) NOT NULL,
+		Checkpoint STRING(MAX) NOT NULL,
+		UpdateTimestamp TIMESTAMP NOT NULL OPTIONS (allow_commit_timestamp=true),
+	  ) PRIMARY KEY(DatabaseUri)`,
 }
 
 func GetSpannerUri(projectId string, instanceId string) string {
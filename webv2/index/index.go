@@ -110,6 +110,14 @@ func checkInterleaveIndex(index []ddl.CreateIndex, spannerTable ddl.CreateTable)
 			if len(index[i].Keys) > 0 {
 				indexFirstColumnId := index[i].Keys[0].ColId
 
+				// Suggest interleaving if the index keys start with the primary key of the parent table.
+				if index[i].InterleaveTableId == "" && index[i].CanInterleaveIn(sessionState.Conv.SpSchema, spannerTable.ParentTable.Id) {
+					schemaissue := sessionState.Conv.SchemaIssues[spannerTable.Id].ColumnLevelIssues[indexFirstColumnId]
+					if !utilities.IsSchemaIssuePresent(schemaissue, internal.InterleaveIndex) {
+						sessionState.Conv.SchemaIssues[spannerTable.Id].ColumnLevelIssues[indexFirstColumnId] = append(schemaissue, internal.InterleaveIndex)
+					}
+				}
+
 				// Ensuring it is not a redundant index.
 				if primaryKeyFirstColumnId != indexFirstColumnId {
 
@@ -152,6 +160,23 @@ func checkInterleaveIndex(index []ddl.CreateIndex, spannerTable ddl.CreateTable)
 	}
 }
 
+// InterleaveIndexes interleaves the indexes of table tableId in its parent
+// table if their keys start with the primary key of the parent table. This is
+// called when the table is interleaved.
+func InterleaveIndexes(tableId string) {
+	sessionState := session.GetSessionState()
+	sp := sessionState.Conv.SpSchema[tableId]
+	if sp.ParentTable.Id == "" {
+		return
+	}
+	for i, index := range sp.Indexes {
+		if index.InterleaveTableId == "" && index.CanInterleaveIn(sessionState.Conv.SpSchema, sp.ParentTable.Id) {
+			sp.Indexes[i].InterleaveTableId = sp.ParentTable.Id
+		}
+	}
+	sessionState.Conv.SpSchema[tableId] = sp
+}
+
 // RemoveIndexIssues removes the issues in a column which is part of the passed Index.
 // This is called when we drop an index or make changes in the primarykey of the current table.
 // Editing the primary key can affect the issues in an index (eg. Changing pk order affects Redundant index issue).
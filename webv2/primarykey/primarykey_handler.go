@@ -26,6 +26,7 @@ import (
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/index"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/session"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/table"
+	utilities "github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/utilities"
 	"github.com/google/uuid"
 )
 
@@ -149,5 +150,6 @@ func UpdatePrimaryKey(pkRequest PrimaryKeyRequest) {
 			}
 		}
 	}
+	utilities.RemoveInvalidIndexInterleaving(sessionState.Conv)
 	common.ComputeNonKeyColumnSize(sessionState.Conv, pkRequest.TableId)
 }
@@ -43,9 +43,18 @@ func RemoveColumn(tableId string, colId string, conv *internal.Conv) {
 		conv.SpSchema[id] = sp
 	}
 
+	// the hidden token columns of the column, and the search indexes left
+	// without any, are dropped with it.
+	for _, tokenColId := range getTokenColumnIds(conv, tableId, colId) {
+		removeColumnFromTableSchema(conv, tableId, tokenColId)
+	}
+
 	//remove column from the table.
 	removeColumnFromTableSchema(conv, tableId, colId)
 
+	// indexes of child tables may no longer start with the primary key of this table.
+	utilities.RemoveInvalidIndexInterleaving(conv)
+
 }
 
 // removeColumnFromCurrentTableSchema remove given column from table schema.
@@ -60,12 +69,21 @@ func removeColumnFromTableSchema(conv *internal.Conv, tableId string, colId stri
 
 	sp = removeColumnFromSpannerSecondaryIndex(sp, colId)
 
+	sp = removeColumnFromSpannerSearchIndex(sp, colId)
+
+	sp = removeColumnFromSpannerVectorIndex(sp, colId)
+
 	sp = removeColumnFromSpannerForeignkeyColumns(sp, colId)
 
 	sp = removeColumnFromSpannerForeignkeyReferColumns(sp, colId)
 
 	sp = removeColumnFromSpannerColNames(sp, colId)
 
+	// The row deletion policy is dropped with its column.
+	if sp.RowDeletionPolicy.ColId == colId {
+		sp.RowDeletionPolicy = ddl.RowDeletionPolicy{}
+	}
+
 	removeSpannerSchemaIssue(tableId, colId, conv)
 
 	conv.SpSchema[tableId] = sp
@@ -120,6 +138,38 @@ func removeColumnFromSpannerSecondaryIndex(sp ddl.CreateTable, colId string) ddl
 	return sp
 }
 
+// removeColumnFromSpannerSearchIndex remove given column from Spanner SearchIndex List.
+// A search index left without any token column is dropped.
+func removeColumnFromSpannerSearchIndex(sp ddl.CreateTable, colId string) ddl.CreateTable {
+	var searchIndexes []ddl.CreateSearchIndex
+	for _, searchIndex := range sp.SearchIndexes {
+		for j, key := range searchIndex.Keys {
+			if key.ColId == colId {
+				searchIndex.Keys = utilities.RemoveColumnFromSecondaryIndexKey(searchIndex.Keys, j)
+				break
+			}
+		}
+		if len(searchIndex.Keys) > 0 {
+			searchIndexes = append(searchIndexes, searchIndex)
+		}
+	}
+	sp.SearchIndexes = searchIndexes
+	return sp
+}
+
+// removeColumnFromSpannerVectorIndex drops the Spanner vector indexes on given column.
+func removeColumnFromSpannerVectorIndex(sp ddl.CreateTable, colId string) ddl.CreateTable {
+	var vectorIndexes []ddl.CreateVectorIndex
+	for _, vectorIndex := range sp.VectorIndexes {
+		if len(vectorIndex.Keys) > 0 && vectorIndex.Keys[0].ColId == colId {
+			continue
+		}
+		vectorIndexes = append(vectorIndexes, vectorIndex)
+	}
+	sp.VectorIndexes = vectorIndexes
+	return sp
+}
+
 // removeColumnFromSecondaryIndexKey remove given column from Spanner Secondary Schema Issue List.
 func removeSpannerSchemaIssue(tableId string, colId string, conv *internal.Conv) {
 	if conv.SchemaIssues != nil {
@@ -26,6 +26,8 @@ func renameColumn(newName, tableId, colId string, conv *internal.Conv) {
 
 	if ok {
 
+		renameTokenizedColumn(conv, tableId, colId, newName)
+
 		spColumn.Name = newName
 
 		spTable.ColDefs[colId] = spColumn
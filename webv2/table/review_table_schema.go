@@ -107,6 +107,8 @@ func ReviewTableSchema(w http.ResponseWriter, r *http.Request) {
 				conv.SpSchema[tableId].CheckConstraints[i].Expr = updatedValue
 			}
 
+			renameTokenizedColumn(conv, tableId, colId, v.Rename)
+
 			sp := conv.SpSchema[tableId]
 			column, ok := sp.ColDefs[colId]
 			if ok {
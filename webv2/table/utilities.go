@@ -110,6 +110,36 @@ func UpdateAutoGenCol(autoGen ddl.AutoGenCol, tableId, colId string, conv *inter
 	return sequences
 }
 
+// getTokenColumnIds returns the ids of the hidden TOKENLIST columns that
+// tokenize column colId for search indexes.
+func getTokenColumnIds(conv *internal.Conv, tableId, colId string) []string {
+	sp := conv.SpSchema[tableId]
+	col, ok := sp.ColDefs[colId]
+	if !ok {
+		return nil
+	}
+	expr := common.GetTokenizeExpression(conv, col.Name)
+	var ids []string
+	for _, id := range sp.ColIds {
+		c := sp.ColDefs[id]
+		if c.T.Name == ddl.TokenList && c.GeneratedColumn.IsPresent && c.GeneratedColumn.Value.Statement == expr {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// renameTokenizedColumn rewrites the TOKENIZE_FULLTEXT expressions of the
+// token columns of column colId, which is being renamed to newName.
+func renameTokenizedColumn(conv *internal.Conv, tableId, colId, newName string) {
+	sp := conv.SpSchema[tableId]
+	for _, id := range getTokenColumnIds(conv, tableId, colId) {
+		c := sp.ColDefs[id]
+		c.GeneratedColumn.Value.Statement = common.GetTokenizeExpression(conv, newName)
+		sp.ColDefs[id] = c
+	}
+}
+
 func getFkColumnPosition(colIds []string, colId string) int {
 	for i, id := range colIds {
 		if colId == id {
@@ -282,3 +282,61 @@ func TestUpdateGeneratedCol(t *testing.T) {
 	assert.Equal(t, "custom_id", conv.SpSchema["table1"].ColDefs["col2"].GeneratedColumn.Value.ExpressionId)
 	assert.Equal(t, ddl.GeneratedColVirtual, conv.SpSchema["table1"].ColDefs["col2"].GeneratedColumn.Type)
 }
+
+func searchIndexConv() *internal.Conv {
+	tokenCol := func(id, name, base string) ddl.ColumnDef {
+		return ddl.ColumnDef{
+			Id:     id,
+			Name:   name,
+			T:      ddl.Type{Name: ddl.TokenList},
+			Hidden: true,
+			GeneratedColumn: ddl.GeneratedColumn{
+				IsPresent: true,
+				Value:     ddl.Expression{Statement: "TOKENIZE_FULLTEXT(`" + base + "`)"},
+				Type:      ddl.GeneratedColVirtual,
+			},
+		}
+	}
+	conv := internal.MakeConv()
+	conv.SpDialect = constants.DIALECT_GOOGLESQL
+	conv.SpSchema = ddl.Schema{
+		"t1": {
+			Name:   "docs",
+			Id:     "t1",
+			ColIds: []string{"c1", "c2", "c3", "c4", "c5"},
+			ColDefs: map[string]ddl.ColumnDef{
+				"c1": {Id: "c1", Name: "id", T: ddl.Type{Name: ddl.Int64}},
+				"c2": {Id: "c2", Name: "title", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+				"c3": {Id: "c3", Name: "body", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+				"c4": tokenCol("c4", "title_Tokens", "title"),
+				"c5": tokenCol("c5", "body_Tokens", "body"),
+			},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}},
+			SearchIndexes: []ddl.CreateSearchIndex{
+				{Name: "title_idx", TableId: "t1", Id: "i1", Keys: []ddl.IndexKey{{ColId: "c4", Order: 1}}},
+				{Name: "title_body_idx", TableId: "t1", Id: "i2", Keys: []ddl.IndexKey{{ColId: "c4", Order: 1}, {ColId: "c5", Order: 2}}},
+			},
+		},
+	}
+	return conv
+}
+
+func TestRemoveTokenizedColumn(t *testing.T) {
+	conv := searchIndexConv()
+	RemoveColumn("t1", "c2", conv)
+	sp := conv.SpSchema["t1"]
+	assert.Equal(t, []string{"c1", "c3", "c5"}, sp.ColIds)
+	assert.NotContains(t, sp.ColDefs, "c4")
+	assert.Equal(t, []ddl.CreateSearchIndex{
+		{Name: "title_body_idx", TableId: "t1", Id: "i2", Keys: []ddl.IndexKey{{ColId: "c5", Order: 2}}},
+	}, sp.SearchIndexes)
+}
+
+func TestRenameTokenizedColumn(t *testing.T) {
+	conv := searchIndexConv()
+	renameColumn("headline", "t1", "c2", conv)
+	sp := conv.SpSchema["t1"]
+	assert.Equal(t, "headline", sp.ColDefs["c2"].Name)
+	assert.Equal(t, "TOKENIZE_FULLTEXT(`headline`)", sp.ColDefs["c4"].GeneratedColumn.Value.Statement)
+	assert.Equal(t, "TOKENIZE_FULLTEXT(`body`)", sp.ColDefs["c5"].GeneratedColumn.Value.Statement)
+}
@@ -90,6 +90,12 @@ type ShardIdPrimaryKey struct {
 	AddedAtTheStart bool `json:"AddedAtTheStart"`
 }
 
+type RowDeletionPolicy struct {
+	TableId string `json:"TableId"`
+	ColId   string `json:"ColId"`
+	NumDays int64  `json:"NumDays"`
+}
+
 // dumpConfig contains the parameters needed to run the tool using dump approach. It is
 // used to communicate via HTTP with the frontend.
 type DumpConfig struct {
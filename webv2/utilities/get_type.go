@@ -73,7 +73,8 @@ func GetType(conv *internal.Conv, newType, tableId, colId string) (ddl.CreateTab
 		conv.SchemaIssues[tableId].ColumnLevelIssues[colId] = issues
 	}
 	if conv.Source != constants.CASSANDRA {
-		ty.IsArray = len(srcCol.Type.ArrayBounds) == 1
+		// Vectors, such as pgvector columns, are arrays without array bounds.
+		ty.IsArray = len(srcCol.Type.ArrayBounds) == 1 || ty.VectorLength > 0
 	}
 	return sp, ty, nil
 }
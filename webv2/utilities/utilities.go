@@ -359,6 +359,15 @@ func UpdateDataType(conv *internal.Conv, newType, tableId, colId string) error {
 	}
 	colDef := sp.ColDefs[colId]
 	colDef.T = ty
+	// Row deletion policies can only be on TIMESTAMP columns.
+	if sp.RowDeletionPolicy.ColId == colId && ty.Name != ddl.Timestamp {
+		sp.RowDeletionPolicy = ddl.RowDeletionPolicy{}
+		conv.SpSchema[tableId] = sp
+	}
+	// Vector indexes can only be on vector columns.
+	if !(ty.IsArray && ty.VectorLength > 0) {
+		conv.SpSchema[tableId] = removeVectorIndexes(conv, sp, colId)
+	}
 	if conv.Source == constants.CASSANDRA {
 		toddl := cassandra.InfoSchemaImpl{}.GetToDdl()
 		if optionProvider, ok := toddl.(common.OptionProvider); ok {
@@ -374,6 +383,32 @@ func UpdateDataType(conv *internal.Conv, newType, tableId, colId string) error {
 	return nil
 }
 
+// removeVectorIndexes drops the vector indexes on column colId of table sp,
+// along with the issue reporting them.
+func removeVectorIndexes(conv *internal.Conv, sp ddl.CreateTable, colId string) ddl.CreateTable {
+	var vectorIndexes []ddl.CreateVectorIndex
+	for _, vectorIndex := range sp.VectorIndexes {
+		if len(vectorIndex.Keys) > 0 && vectorIndex.Keys[0].ColId == colId {
+			continue
+		}
+		vectorIndexes = append(vectorIndexes, vectorIndex)
+	}
+	if len(vectorIndexes) == len(sp.VectorIndexes) {
+		return sp
+	}
+	sp.VectorIndexes = vectorIndexes
+	if tableIssues, ok := conv.SchemaIssues[sp.Id]; ok && tableIssues.ColumnLevelIssues != nil {
+		var issues []internal.SchemaIssue
+		for _, issue := range tableIssues.ColumnLevelIssues[colId] {
+			if issue != internal.VectorIndex {
+				issues = append(issues, issue)
+			}
+		}
+		tableIssues.ColumnLevelIssues[colId] = issues
+	}
+	return sp
+}
+
 // Update the column length with the default mapping length in case its same as the length in the rule added
 func updateColLen(conv *internal.Conv, dataType, tableId, colId string, spColLen int64) error {
 	sp, ty, err := GetType(conv, dataType, tableId, colId)
@@ -430,3 +465,17 @@ func GetInterleavedFk(conv *internal.Conv, tableId string, srcColId string) (sch
 	}
 	return schema.ForeignKey{}, fmt.Errorf("interleaved Foreign key not found")
 }
+
+// RemoveInvalidIndexInterleaving removes the interleaving of indexes that can
+// no longer be interleaved in their table, e.g. because their table was
+// removed from it or its primary key changed.
+func RemoveInvalidIndexInterleaving(conv *internal.Conv) {
+	for tableId, sp := range conv.SpSchema {
+		for i, index := range sp.Indexes {
+			if index.InterleaveTableId != "" && !index.CanInterleaveIn(conv.SpSchema, index.InterleaveTableId) {
+				sp.Indexes[i].InterleaveTableId = ""
+			}
+		}
+		conv.SpSchema[tableId] = sp
+	}
+}
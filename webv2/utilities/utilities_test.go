@@ -110,6 +110,57 @@ func TestUpdateDataType(t *testing.T) {
 	}
 }
 
+func TestUpdateDataType_VectorIndex(t *testing.T) {
+	testCases := []struct {
+		name        string
+		newType     string
+		wantType    ddl.Type
+		wantIndexes int
+		wantIssues  []internal.SchemaIssue
+	}{
+		{
+			name:        "Vector column stays a vector",
+			newType:     ddl.Float32,
+			wantType:    ddl.Type{Name: ddl.Float32, IsArray: true, VectorLength: 3},
+			wantIndexes: 1,
+			wantIssues:  []internal.SchemaIssue{internal.VectorIndex},
+		},
+		{
+			name:        "Vector column changed to a string",
+			newType:     ddl.String,
+			wantType:    ddl.Type{Name: ddl.String, Len: ddl.MaxLength},
+			wantIndexes: 0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sessionState := session.GetSessionState()
+			sessionState.Driver = constants.POSTGRES
+			conv := &internal.Conv{
+				SpSchema: map[string]ddl.CreateTable{"t1": {
+					Id:      "t1",
+					Name:    "items",
+					ColDefs: map[string]ddl.ColumnDef{"c1": {Name: "embedding", T: ddl.Type{Name: ddl.Float32, IsArray: true, VectorLength: 3}}},
+					VectorIndexes: []ddl.CreateVectorIndex{
+						{Name: "items_embedding_idx", TableId: "t1", Keys: []ddl.IndexKey{{ColId: "c1", Order: 1}}, DistanceType: "COSINE", Id: "i1"},
+					},
+				}},
+				SrcSchema: map[string]schema.Table{"t1": {Id: "t1", Name: "items", ColDefs: map[string]schema.Column{"c1": {Name: "embedding", Type: schema.Type{Name: "vector", Mods: []int64{3}}}}, ColIds: []string{"c1"}}},
+				SchemaIssues: map[string]internal.TableIssues{
+					"t1": {ColumnLevelIssues: map[string][]internal.SchemaIssue{"c1": {internal.VectorIndex}}},
+				},
+				SpDialect: constants.DIALECT_GOOGLESQL,
+				Source:    constants.POSTGRES,
+			}
+
+			assert.NoError(t, UpdateDataType(conv, tc.newType, "t1", "c1"))
+			assert.Equal(t, tc.wantType, conv.SpSchema["t1"].ColDefs["c1"].T)
+			assert.Len(t, conv.SpSchema["t1"].VectorIndexes, tc.wantIndexes)
+			assert.Equal(t, tc.wantIssues, conv.SchemaIssues["t1"].ColumnLevelIssues["c1"])
+		})
+	}
+}
+
 func TestIsParent(t *testing.T) {
 	testCases := []struct {
 		name             string
@@ -159,4 +210,4 @@ func TestIsParent(t *testing.T) {
 			assert.ElementsMatch(t, tc.expectedChildIds, childIds)
 		})
 	}
-}
\ No newline at end of file
+}